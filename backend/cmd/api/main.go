@@ -4,17 +4,24 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/comunidade/backend/internal/config"
 	"github.com/comunidade/backend/internal/database"
 	"github.com/comunidade/backend/internal/delivery/http"
 	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	applogger "github.com/comunidade/backend/pkg/logger"
+	"github.com/comunidade/backend/pkg/secret"
 	"go.uber.org/zap"
 )
 
 func main() {
 	// Inicializa o logger
-	logger, err := zap.NewDevelopment()
+	logger, err := zap.NewDevelopment(zap.WrapCore(applogger.RedactCore))
 	if err != nil {
 		log.Fatalf("erro ao criar logger: %v", err)
 	}
@@ -30,6 +37,17 @@ func main() {
 		return
 	}
 
+	// Carrega a chave mestra que cifra os segredos gravados no banco de dados
+	keyring, err := secret.NewKeyring(cfg.Security.MasterKey, cfg.Security.PreviousKeys...)
+	if err != nil {
+		logger.Error("erro ao carregar a chave mestra de criptografia", zap.Error(err))
+		return
+	}
+	if !keyring.Enabled() {
+		logger.Warn("ENCRYPTION_MASTER_KEY não configurada: chaves de API e senhas serão gravadas sem criptografia")
+	}
+	secret.SetDefault(keyring)
+
 	// Conecta ao banco de dados
 	db, err := database.Connect(cfg.Database)
 	if err != nil {
@@ -49,15 +67,43 @@ func main() {
 	// Inicializa os repositórios
 	repos := repository.NewRepositories(db, logger)
 
+	// Importações interrompidas por uma parada do servidor não serão retomadas
+	interrupted, err := repos.MemberImport.FailProcessing(context.Background(), time.Now().Add(-15*time.Minute), "importação interrompida pela reinicialização do servidor")
+	if err != nil {
+		logger.Error("erro ao encerrar importações interrompidas", zap.Error(err))
+	} else if interrupted > 0 {
+		logger.Warn("importações de membros interrompidas marcadas como falhas", zap.Int64("imports", interrupted))
+	}
+
 	// Inicializa o servidor HTTP
-	server := http.NewServer(repos, logger)
+	server := http.NewServer(cfg.Server, repos, logger)
+
+	// O servidor e as tarefas em segundo plano são encerrados ao receber SIGINT ou SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Inicia as tarefas periódicas. Cada rodada é executada por uma única instância do
+	// servidor de cada vez.
+	communication := service.NewCommunicationService(repos, logger)
+
+	// Gera as despesas recorrentes e envia os lembretes de vencimento em segundo plano
+	go service.NewRecurringExpenseService(repos, logger, communication).Run(ctx, time.Hour)
+
+	// Conclui as campanhas cuja data final passou ou cuja meta foi atingida
+	go service.NewCampaignService(repos, logger).Run(ctx, time.Hour)
+
+	// Envia as felicitações do dia e os resumos semanais de datas comemorativas
+	go service.NewCelebrationService(repos, logger, communication).Run(ctx, time.Hour)
+
+	// Gera e envia os comprovantes anuais de contribuição registrados para envio
+	go service.NewGivingStatementService(repos, logger, communication, "./uploads").Run(ctx, time.Minute)
 
 	// Inicia o servidor
 	logger.Info("servidor iniciado com sucesso",
 		zap.Int("port", cfg.Server.Port),
 		zap.String("database", cfg.Database.Name),
 	)
-	if err := server.Start(context.Background(), fmt.Sprintf(":%d", cfg.Server.Port)); err != nil {
+	if err := server.Start(ctx, fmt.Sprintf(":%d", cfg.Server.Port)); err != nil {
 		logger.Error("erro ao iniciar servidor", zap.Error(err))
 	}
 }
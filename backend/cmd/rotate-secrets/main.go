@@ -0,0 +1,64 @@
+// Comando rotate-secrets cifra novamente as chaves de API e senhas gravadas no banco de
+// dados com a chave mestra atual.
+//
+// Para trocar a chave mestra, gere uma nova com -generate-key, configure-a em
+// ENCRYPTION_MASTER_KEY, mova a chave anterior para ENCRYPTION_PREVIOUS_KEYS e execute
+// o comando. Ao final, a chave anterior pode ser removida. Executado pela primeira vez,
+// o comando cifra os segredos gravados em texto puro.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/comunidade/backend/internal/config"
+	"github.com/comunidade/backend/internal/database"
+	applogger "github.com/comunidade/backend/pkg/logger"
+	"github.com/comunidade/backend/pkg/secret"
+	"go.uber.org/zap"
+)
+
+func main() {
+	generateKey := flag.Bool("generate-key", false, "gera uma nova chave mestra e encerra")
+	flag.Parse()
+
+	if *generateKey {
+		key, err := secret.GenerateMasterKey()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	logger, err := zap.NewDevelopment(zap.WrapCore(applogger.RedactCore))
+	if err != nil {
+		log.Fatalf("erro ao criar logger: %v", err)
+	}
+	defer logger.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("erro ao carregar configurações", zap.Error(err))
+	}
+
+	keyring, err := secret.NewKeyring(cfg.Security.MasterKey, cfg.Security.PreviousKeys...)
+	if err != nil {
+		logger.Fatal("erro ao carregar a chave mestra de criptografia", zap.Error(err))
+	}
+	if !keyring.Enabled() {
+		logger.Fatal("configure ENCRYPTION_MASTER_KEY antes de cifrar os segredos")
+	}
+	secret.SetDefault(keyring)
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		logger.Fatal("erro ao conectar ao banco de dados", zap.Error(err))
+	}
+
+	logger.Info("cifrando segredos", zap.String("key_id", keyring.KeyID()))
+	if err := database.RotateSecrets(db, logger); err != nil {
+		logger.Fatal("erro ao cifrar segredos", zap.Error(err))
+	}
+}
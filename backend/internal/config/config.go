@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,11 +14,18 @@ type Config struct {
 	JWT      JWTConfig
 	Storage  StorageConfig
 	Email    EmailConfig
+	Security SecurityConfig
 }
 
 type ServerConfig struct {
 	Port    int
 	Timeout int
+	// Proxies cujos cabeçalhos X-Forwarded-For são aceitos para obter o IP do cliente.
+	// Vazio usa o endereço da conexão.
+	TrustedProxies []string
+	// Cabeçalho com o IP do cliente definido pela plataforma de hospedagem
+	// (por exemplo CF-Connecting-IP)
+	ClientIPHeader string
 }
 
 type DatabaseConfig struct {
@@ -49,6 +57,13 @@ type EmailConfig struct {
 	FromEmail    string
 }
 
+// SecurityConfig guarda a chave mestra que cifra os segredos gravados no banco de dados
+// e as chaves anteriores, aceitas apenas para leitura até a rotação
+type SecurityConfig struct {
+	MasterKey    string
+	PreviousKeys []string
+}
+
 func Load() (*Config, error) {
 	// Tenta carregar o arquivo .env, mas não retorna erro se não existir
 	godotenv.Load()
@@ -57,6 +72,9 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Port:    getEnvAsInt("PORT", 8080),
 			Timeout: getEnvAsInt("SERVER_TIMEOUT", 30),
+
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
+			ClientIPHeader: getEnv("CLIENT_IP_HEADER", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DATABASE_HOST", "localhost"),
@@ -83,6 +101,10 @@ func Load() (*Config, error) {
 			FromName:     getEnv("FROM_NAME", "Comunidade"),
 			FromEmail:    getEnv("FROM_EMAIL", ""),
 		},
+		Security: SecurityConfig{
+			MasterKey:    getEnv("ENCRYPTION_MASTER_KEY", ""),
+			PreviousKeys: getEnvAsList("ENCRYPTION_PREVIOUS_KEYS"),
+		},
 	}, nil
 }
 
@@ -101,3 +123,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"github.com/comunidade/backend/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -24,16 +26,44 @@ func RunMigrations(db *gorm.DB, logger *zap.Logger) error {
 		&domain.CommunicationTemplate{},
 		&domain.CommunicationSettings{},
 		&domain.CheckIn{},
+		&domain.RecurringExpense{},
 		&domain.Expense{},
+		&domain.ExpenseApprovalRule{},
+		&domain.ExpenseApproval{},
+		&domain.ExpenseReceipt{},
 		&domain.Revenue{},
 		&domain.Supplier{},
 		&domain.FinancialCategory{},
 		&domain.FinancialReport{},
+		&domain.FinancialReportItem{},
+		&domain.DonationPostingRule{},
+		&domain.Budget{},
+		&domain.BudgetAlert{},
+		&domain.BankStatementImport{},
+		&domain.BankTransaction{},
+		&domain.MemberImport{},
+		&domain.MemberMerge{},
+		&domain.MemberSegment{},
+		&domain.PipelineStage{},
+		&domain.MemberStatusHistory{},
+		&domain.FollowUpTask{},
+		&domain.MemberProfileSettings{},
+		&domain.MemberProfileChange{},
+		&domain.MembershipEvent{},
+		&domain.LetterTemplate{},
+		&domain.MemberLetter{},
+		&domain.CelebrationSettings{},
+		&domain.CelebrationGreeting{},
+		&domain.ChildCheckIn{},
+		&domain.ContributionBatch{},
+		&domain.Contribution{},
+		&domain.GivingStatementBatch{},
 		&domain.Donation{},
 		&domain.Campaign{},
 		&domain.RecurringDonation{},
 		&domain.AsaasConfig{},
 		&domain.AsaasAccount{},
+		&domain.PaymentGatewaySettings{},
 		&domain.CommunityPost{},
 		&domain.PostComment{},
 		&domain.PostReaction{},
@@ -42,6 +72,74 @@ func RunMigrations(db *gorm.DB, logger *zap.Logger) error {
 		&domain.PrayerRequest{},
 	}
 
+	// A restrição de status das despesas foi substituída por chk_expenses_approval_status,
+	// que inclui as situações do fluxo de aprovação
+	if err := db.Exec("ALTER TABLE IF EXISTS expenses DROP CONSTRAINT IF EXISTS chk_expenses_status").Error; err != nil {
+		logger.Error("erro ao remover restrição de status das despesas", zap.Error(err))
+		return err
+	}
+
+	// A restrição de status das doações recorrentes foi substituída por
+	// chk_recurring_donations_lifecycle_status, que inclui as assinaturas pausadas
+	if err := db.Exec("ALTER TABLE IF EXISTS recurring_donations DROP CONSTRAINT IF EXISTS chk_recurring_donations_status").Error; err != nil {
+		logger.Error("erro ao remover restrição de status das doações recorrentes", zap.Error(err))
+		return err
+	}
+
+	// A restrição de status das doações foi substituída por chk_donations_refund_status,
+	// que inclui as doações reembolsadas e contestadas
+	if err := db.Exec("ALTER TABLE IF EXISTS donations DROP CONSTRAINT IF EXISTS chk_donations_status").Error; err != nil {
+		logger.Error("erro ao remover restrição de status das doações", zap.Error(err))
+		return err
+	}
+
+	// As tarefas de acompanhamento canceladas passaram a usar a grafia "cancelled", como
+	// as demais situações; a restrição de status foi substituída por
+	// chk_follow_up_tasks_cancelled_status
+	if err := db.Exec("ALTER TABLE IF EXISTS follow_up_tasks DROP CONSTRAINT IF EXISTS chk_follow_up_tasks_status").Error; err != nil {
+		logger.Error("erro ao remover restrição de status das tarefas de acompanhamento", zap.Error(err))
+		return err
+	}
+	if db.Migrator().HasTable(&domain.FollowUpTask{}) {
+		if err := db.Exec("UPDATE follow_up_tasks SET status = ? WHERE status = 'canceled'", domain.FollowUpTaskCancelled).Error; err != nil {
+			logger.Error("erro ao atualizar status das tarefas de acompanhamento", zap.Error(err))
+			return err
+		}
+	}
+
+	// As solicitações de alteração do cadastro canceladas também passaram a usar a grafia
+	// "cancelled"; a restrição de status foi substituída por
+	// chk_member_profile_changes_cancelled_status
+	if err := db.Exec("ALTER TABLE IF EXISTS member_profile_changes DROP CONSTRAINT IF EXISTS chk_member_profile_changes_status").Error; err != nil {
+		logger.Error("erro ao remover restrição de status das alterações de cadastro", zap.Error(err))
+		return err
+	}
+	if db.Migrator().HasTable(&domain.MemberProfileChange{}) {
+		if err := db.Exec("UPDATE member_profile_changes SET status = ? WHERE status = 'canceled'", domain.ProfileChangeCancelled).Error; err != nil {
+			logger.Error("erro ao atualizar status das alterações de cadastro", zap.Error(err))
+			return err
+		}
+	}
+
+	// O lote de ofertas conferido passou a lançar uma receita por forma de pagamento: o
+	// índice único por lote foi substituído por idx_revenues_contribution_batch_payment e
+	// as receitas do lote são encontradas por contribution_batch_id
+	if err := db.Exec("DROP INDEX IF EXISTS idx_revenues_contribution_batch_id").Error; err != nil {
+		logger.Error("erro ao remover índice das receitas dos lotes de ofertas", zap.Error(err))
+		return err
+	}
+	if err := db.Exec("ALTER TABLE IF EXISTS contribution_batches DROP COLUMN IF EXISTS revenue_id").Error; err != nil {
+		logger.Error("erro ao remover receita dos lotes de ofertas", zap.Error(err))
+		return err
+	}
+
+	// O token do webhook do provedor de pagamentos deixou de ser buscado pelo valor; o
+	// índice foi substituído pelo de webhook_token_digest
+	if err := db.Exec("DROP INDEX IF EXISTS idx_payment_gateway_settings_webhook_token").Error; err != nil {
+		logger.Error("erro ao remover índice do token do webhook", zap.Error(err))
+		return err
+	}
+
 	// Executa as migrações
 	for _, model := range models {
 		if err := db.AutoMigrate(model); err != nil {
@@ -53,6 +151,46 @@ func RunMigrations(db *gorm.DB, logger *zap.Logger) error {
 		}
 	}
 
+	// A data da última presença passou a ser gravada com os check-ins e as presenças;
+	// os membros sem a data, ou com uma data anterior, são preenchidos pelo histórico
+	if err := db.Exec(backfillLastAttendance).Error; err != nil {
+		logger.Error("erro ao preencher a última presença dos membros", zap.Error(err))
+		return err
+	}
+
+	// Os webhooks passaram a ser identificados pelo resumo do token, que é gravado cifrado;
+	// os tokens em texto puro recebem o resumo e são cifrados pela rotação dos segredos
+	for _, table := range []string{"asaas_configs", "payment_gateway_settings"} {
+		if err := db.Exec(fmt.Sprintf(backfillWebhookTokenDigest, table)).Error; err != nil {
+			logger.Error("erro ao preencher o resumo dos tokens de webhook", zap.String("table", table), zap.Error(err))
+			return err
+		}
+	}
+
 	logger.Info("migrações concluídas com sucesso")
 	return nil
 }
+
+const backfillLastAttendance = `
+UPDATE members SET last_attendance_at = latest.attended_at
+FROM (
+	SELECT community_id, member_id, MAX(attended_at) AS attended_at
+	FROM (
+		SELECT events.community_id, check_ins.member_id::text AS member_id, check_ins.check_in_at AS attended_at
+		FROM check_ins JOIN events ON events.id::text = check_ins.event_id::text
+		WHERE check_ins.member_id IS NOT NULL AND check_ins.member_id::text <> ''
+		UNION ALL
+		SELECT events.community_id, attendances.member_id::text, attendances.created_at
+		FROM attendances JOIN events ON events.id = attendances.event_id
+		WHERE attendances.status IN ('present', 'late')
+	) AS attended
+	GROUP BY community_id, member_id
+) AS latest
+WHERE members.community_id = latest.community_id
+	AND members.id::text = latest.member_id
+	AND (members.last_attendance_at IS NULL OR members.last_attendance_at < latest.attended_at)`
+
+const backfillWebhookTokenDigest = `
+UPDATE %s SET webhook_token_digest = encode(sha256(convert_to(webhook_token, 'UTF8')), 'hex')
+WHERE webhook_token <> '' AND webhook_token NOT LIKE 'enc:%%'
+	AND (webhook_token_digest IS NULL OR webhook_token_digest = '')`
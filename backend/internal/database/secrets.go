@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RotateSecrets grava novamente todos os segredos com a chave mestra atual. Os valores
+// são lidos com qualquer uma das chaves configuradas, inclusive os ainda em texto puro,
+// e cifrados pelos serializadores com a chave atual. Depois da rotação, as chaves
+// anteriores podem ser removidas da configuração.
+func RotateSecrets(db *gorm.DB, logger *zap.Logger) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		configs, err := rotateTable[domain.AsaasConfig](tx, "api_key", "webhook_token")
+		if err != nil {
+			return fmt.Errorf("erro ao cifrar configurações do ASAAS: %v", err)
+		}
+		accounts, err := rotateTable[domain.AsaasAccount](tx, "api_key", "webhooks")
+		if err != nil {
+			return fmt.Errorf("erro ao cifrar subcontas do ASAAS: %v", err)
+		}
+		gateways, err := rotateTable[domain.PaymentGatewaySettings](tx, "webhook_token")
+		if err != nil {
+			return fmt.Errorf("erro ao cifrar provedores de pagamento: %v", err)
+		}
+		settings, err := rotateTable[domain.CommunicationSettings](tx, "email_password", "sms_api_key", "whats_app_api_key")
+		if err != nil {
+			return fmt.Errorf("erro ao cifrar configurações de comunicação: %v", err)
+		}
+
+		logger.Info("segredos cifrados com a chave mestra atual",
+			zap.Int("asaas_configs", configs),
+			zap.Int("asaas_accounts", accounts),
+			zap.Int("payment_gateway_settings", gateways),
+			zap.Int("communication_settings", settings))
+		return nil
+	})
+}
+
+// rotateTable lê os registros em lotes e grava apenas as colunas dos segredos, sem
+// alterar a data de atualização
+func rotateTable[T any](tx *gorm.DB, columns ...string) (int, error) {
+	var rows []*T
+	count := 0
+	err := tx.FindInBatches(&rows, 100, func(batch *gorm.DB, _ int) error {
+		for _, row := range rows {
+			if err := tx.Model(row).Select(columns).UpdateColumns(row).Error; err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	}).Error
+	return count, err
+}
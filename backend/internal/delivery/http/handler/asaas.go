@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/pkg/secret"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -142,9 +143,9 @@ func (h *Handler) UpdateAsaasConfig(c *gin.Context) {
 		return
 	}
 
-	// Atualiza os campos
-	config.ApiKey = req.ApiKey
-	config.WebhookToken = req.WebhookToken
+	// Atualiza os campos. Os segredos mascarados devolvidos pela consulta mantêm os atuais
+	config.ApiKey = secret.Keep(config.ApiKey, req.ApiKey)
+	config.WebhookToken = secret.Keep(config.WebhookToken, req.WebhookToken)
 	config.UpdatedAt = time.Now()
 
 	if err := h.repos.AsaasConfig.Update(c.Request.Context(), config); err != nil {
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type BudgetRequest struct {
+	CategoryID     string  `json:"category_id" binding:"required,uuid"`
+	GroupID        *string `json:"group_id" binding:"omitempty,uuid"`
+	Period         string  `json:"period" binding:"required,oneof=monthly annual"`
+	Year           int     `json:"year" binding:"required,min=2000,max=2100"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	AlertThreshold float64 `json:"alert_threshold" binding:"omitempty,gt=0,lte=100"`
+	NotifyEmails   string  `json:"notify_emails"`
+	Notes          string  `json:"notes"`
+}
+
+// authorizeBudgets verifica se o usuário pode gerenciar os orçamentos da comunidade
+func (h *Handler) authorizeBudgets(c *gin.Context) (*domain.User, *domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar orçamentos"})
+		return nil, nil, false
+	}
+
+	return user.(*domain.User), community, true
+}
+
+// respondBudgetError converte os erros do serviço de orçamentos em respostas HTTP
+func (h *Handler) respondBudgetError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrBudgetNotFound, service.ErrBudgetCategory, service.ErrBudgetGroup:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidBudgetMonth, service.ErrInvalidNotifyEmails, service.ErrInvalidBudgetPercent:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrBudgetExists:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar orçamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// checkBudgetAlerts verifica em segundo plano os orçamentos afetados pela despesa
+func (h *Handler) checkBudgetAlerts(expense *domain.Expense) {
+	go func() {
+		if err := h.services.Budget.CheckExpense(context.Background(), expense); err != nil {
+			h.logger.Error("erro ao verificar alertas de orçamento",
+				zap.Error(err),
+				zap.String("expense_id", expense.ID))
+		}
+	}()
+}
+
+// AddBudget cria um orçamento para uma categoria financeira
+func (h *Handler) AddBudget(c *gin.Context) {
+	user, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	budget := &domain.Budget{
+		CommunityID:    community.ID,
+		UserID:         user.ID,
+		CategoryID:     req.CategoryID,
+		GroupID:        req.GroupID,
+		Period:         req.Period,
+		Year:           req.Year,
+		Amount:         req.Amount,
+		AlertThreshold: req.AlertThreshold,
+		NotifyEmails:   req.NotifyEmails,
+		Notes:          req.Notes,
+	}
+	if err := h.services.Budget.CreateBudget(c.Request.Context(), budget); err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Orçamento criado com sucesso",
+		"budget":  budget,
+	})
+}
+
+// ListBudgets lista os orçamentos da comunidade
+func (h *Handler) ListBudgets(c *gin.Context) {
+	_, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	if year := c.Query("year"); year != "" {
+		filter.AddCondition("year = ?", year)
+	}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		filter.AddCondition("category_id = ?", categoryID)
+	}
+	if groupID := c.Query("group_id"); groupID != "" {
+		filter.AddCondition("group_id = ?", groupID)
+	}
+
+	budgets, total, err := h.repos.Budget.List(c.Request.Context(), community.ID, filter)
+	if err != nil {
+		h.logger.Error("erro ao listar orçamentos", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar orçamentos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budgets": budgets,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetBudgetSummary retorna o orçado x realizado de todos os orçamentos do ano.
+// Com o parâmetro month, os orçamentos mensais consideram apenas o mês informado.
+func (h *Handler) GetBudgetSummary(c *gin.Context) {
+	_, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	year, month, ok := budgetPeriodParams(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.services.Budget.Summary(c.Request.Context(), community, year, month)
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetBudget retorna o orçamento com o orçado x realizado e os alertas enviados
+func (h *Handler) GetBudget(c *gin.Context) {
+	_, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	_, month, ok := budgetPeriodParams(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.services.Budget.GetBudget(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	status, err := h.services.Budget.GetStatus(c.Request.Context(), community, budget, month)
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	alerts, err := h.services.Budget.ListAlerts(c.Request.Context(), budget.ID)
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"alerts": alerts,
+	})
+}
+
+// UpdateBudget atualiza um orçamento
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	_, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.services.Budget.GetBudget(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	budget.CategoryID = req.CategoryID
+	budget.GroupID = req.GroupID
+	budget.Period = req.Period
+	budget.Year = req.Year
+	budget.Amount = req.Amount
+	budget.AlertThreshold = req.AlertThreshold
+	budget.NotifyEmails = req.NotifyEmails
+	budget.Notes = req.Notes
+
+	if err := h.services.Budget.UpdateBudget(c.Request.Context(), budget); err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Orçamento atualizado com sucesso",
+		"budget":  budget,
+	})
+}
+
+// DeleteBudget exclui um orçamento e seus alertas
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	_, community, ok := h.authorizeBudgets(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.services.Budget.GetBudget(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondBudgetError(c, err)
+		return
+	}
+
+	if err := h.repos.Budget.Delete(c.Request.Context(), community.ID, budget.ID); err != nil {
+		h.logger.Error("erro ao excluir orçamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir orçamento"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Orçamento excluído com sucesso"})
+}
+
+// budgetPeriodParams lê os parâmetros year (padrão: ano atual) e month (opcional)
+func budgetPeriodParams(c *gin.Context) (int, int, bool) {
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 2000 || parsed > 2100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Ano inválido"})
+			return 0, 0, false
+		}
+		year = parsed
+	}
+
+	month := 0
+	if value := c.Query("month"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mês inválido"})
+			return 0, 0, false
+		}
+		month = parsed
+	}
+
+	return year, month, true
+}
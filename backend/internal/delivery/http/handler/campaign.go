@@ -1,13 +1,14 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/comunidade/backend/internal/domain"
 	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -20,9 +21,70 @@ type AddCampaignRequest struct {
 	EventID     *string    `json:"event_id"`
 }
 
+type UpdateCampaignRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	Goal        float64    `json:"goal" binding:"required,gt=0"`
+	StartDate   time.Time  `json:"start_date" binding:"required"`
+	EndDate     *time.Time `json:"end_date"`
+	EventID     *string    `json:"event_id" binding:"omitempty,uuid"`
+}
+
+type CloseCampaignRequest struct {
+	Status string `json:"status" binding:"omitempty,oneof=completed cancelled"`
+}
+
+// authorizeCampaigns verifica se o usuário pode gerenciar as campanhas da comunidade
+func (h *Handler) authorizeCampaigns(c *gin.Context) (*domain.User, *domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		if err := h.checkUserPermission(context.Background(), user.(*domain.User).ID, community.ID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar campanhas"})
+			return nil, nil, false
+		}
+	}
+
+	return user.(*domain.User), community, true
+}
+
+// respondCampaignError converte os erros do serviço de campanhas em respostas HTTP
+func (h *Handler) respondCampaignError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrCampaignNotFound, service.ErrCommunityNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidCampaignGoal, service.ErrInvalidCampaignEnd, service.ErrInvalidCampaignClose:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrCampaignNotActive, service.ErrCampaignCancelled:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar campanha", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
 func (h *Handler) AddCampaign(c *gin.Context) {
-	communityID := c.Param("communityId")
-	userID := c.GetString("userId")
+	user, community, ok := h.authorizeCampaigns(c)
+	if !ok {
+		return
+	}
 
 	var req AddCampaignRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,23 +93,17 @@ func (h *Handler) AddCampaign(c *gin.Context) {
 	}
 
 	campaign := &domain.Campaign{
-		ID:          uuid.New().String(),
-		CommunityID: communityID,
-		UserID:      userID,
+		UserID:      user.ID,
 		Name:        req.Name,
 		Description: req.Description,
 		Goal:        req.Goal,
 		StartDate:   req.StartDate,
 		EndDate:     req.EndDate,
 		EventID:     req.EventID,
-		Status:      "active",
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
 	}
 
-	if err := h.repos.Campaign.Create(c.Request.Context(), campaign); err != nil {
-		h.logger.Error("erro ao criar campanha", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	if err := h.services.Campaign.CreateCampaign(c.Request.Context(), community, campaign); err != nil {
+		h.respondCampaignError(c, err)
 		return
 	}
 
@@ -57,22 +113,150 @@ func (h *Handler) AddCampaign(c *gin.Context) {
 	})
 }
 
+// ListCampaigns lista as campanhas da comunidade com o valor arrecadado e o percentual da meta
 func (h *Handler) ListCampaigns(c *gin.Context) {
-	communityID := c.Param("communityId")
+	_, community, ok := h.authorizeCampaigns(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	if status := c.Query("status"); status != "" {
+		filter.AddCondition("status = ?", status)
+	}
+
+	campaigns, total, err := h.services.Campaign.ListCampaigns(c.Request.Context(), community, filter)
+	if err != nil {
+		h.respondCampaignError(c, err)
+		return
+	}
 
-	filter := &repository.Filter{
-		Page:    1,
-		PerPage: 10,
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns": campaigns,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetCampaign retorna a campanha com o progresso da arrecadação
+func (h *Handler) GetCampaign(c *gin.Context) {
+	_, community, ok := h.authorizeCampaigns(c)
+	if !ok {
+		return
 	}
 
-	campaigns, err := h.repos.Campaign.List(c.Request.Context(), communityID, filter)
+	summary, err := h.services.Campaign.GetCampaign(c.Request.Context(), community, c.Param("campaignId"))
 	if err != nil {
-		h.logger.Error("erro ao listar campanhas", zap.Error(err))
+		h.respondCampaignError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// UpdateCampaign altera os dados de uma campanha
+func (h *Handler) UpdateCampaign(c *gin.Context) {
+	_, community, ok := h.authorizeCampaigns(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	campaign, err := h.repos.Campaign.FindByID(c.Request.Context(), community.ID, c.Param("campaignId"))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campanha não encontrada"})
+			return
+		}
+		h.logger.Error("erro ao buscar campanha", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
 		return
 	}
 
+	campaign.Name = req.Name
+	campaign.Description = req.Description
+	campaign.Goal = req.Goal
+	campaign.StartDate = req.StartDate
+	campaign.EndDate = req.EndDate
+	campaign.EventID = req.EventID
+
+	summary, err := h.services.Campaign.UpdateCampaign(c.Request.Context(), community, campaign)
+	if err != nil {
+		h.respondCampaignError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Campanha atualizada com sucesso",
+		"campaign": summary.Campaign,
+		"progress": summary.Progress,
+	})
+}
+
+// CloseCampaign encerra a campanha como concluída (padrão) ou cancelada
+func (h *Handler) CloseCampaign(c *gin.Context) {
+	_, community, ok := h.authorizeCampaigns(c)
+	if !ok {
+		return
+	}
+
+	var req CloseCampaignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+			return
+		}
+	}
+	if req.Status == "" {
+		req.Status = "completed"
+	}
+
+	summary, err := h.services.Campaign.CloseCampaign(c.Request.Context(), community, c.Param("campaignId"), req.Status)
+	if err != nil {
+		h.respondCampaignError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Campanha encerrada com sucesso",
+		"campaign": summary.Campaign,
+		"progress": summary.Progress,
+	})
+}
+
+// ListPublicCampaigns retorna as campanhas exibidas no site de doações da comunidade
+func (h *Handler) ListPublicCampaigns(c *gin.Context) {
+	community, campaigns, err := h.services.Campaign.PublicCampaigns(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		h.respondCampaignError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
+		"community": community,
 		"campaigns": campaigns,
 	})
 }
+
+// GetPublicCampaign retorna a página pública de uma campanha
+func (h *Handler) GetPublicCampaign(c *gin.Context) {
+	community, campaign, err := h.services.Campaign.PublicCampaign(c.Request.Context(), c.Param("slug"), c.Param("campaignId"))
+	if err != nil {
+		h.respondCampaignError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"community": community,
+		"campaign":  campaign,
+	})
+}
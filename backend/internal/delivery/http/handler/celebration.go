@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CelebrationSettingsRequest struct {
+	DigestEnabled        bool    `json:"digest_enabled"`
+	DigestWeekday        int     `json:"digest_weekday" binding:"min=0,max=6"`
+	DigestDays           int     `json:"digest_days" binding:"required,min=1,max=31"`
+	NotifyGroupLeaders   bool    `json:"notify_group_leaders"`
+	DigestEmails         string  `json:"digest_emails"`
+	GreetingsEnabled     bool    `json:"greetings_enabled"`
+	BirthdayTemplateID   *string `json:"birthday_template_id"`
+	WeddingTemplateID    *string `json:"wedding_template_id"`
+	BaptismTemplateID    *string `json:"baptism_template_id"`
+	MembershipTemplateID *string `json:"membership_template_id"`
+}
+
+// authorizeCelebrations verifica se o usuário pode consultar e configurar as datas
+// comemorativas da comunidade
+func (h *Handler) authorizeCelebrations(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores acompanham as datas comemorativas
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para acessar as datas comemorativas desta comunidade"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondCelebrationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCommunityNotFound),
+		errors.Is(err, service.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCelebrationSettings),
+		errors.Is(err, service.ErrInvalidCelebrationPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar datas comemorativas", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// ListCelebrations lista os aniversários, aniversários de casamento, de batismo e de
+// membresia dos próximos dias
+func (h *Handler) ListCelebrations(c *gin.Context) {
+	if _, ok := h.authorizeCelebrations(c); !ok {
+		return
+	}
+
+	var from time.Time
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida. Use o formato AAAA-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	days := 7
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantidade de dias inválida"})
+			return
+		}
+		days = parsed
+	}
+
+	celebrations, err := h.services.Celebration.Upcoming(c.Request.Context(), c.Param("communityId"), from, days, c.Query("kind"), c.Query("group_id"))
+	if err != nil {
+		h.respondCelebrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"celebrations": celebrations})
+}
+
+// GetCelebrationSettings retorna a configuração do resumo semanal e das felicitações
+func (h *Handler) GetCelebrationSettings(c *gin.Context) {
+	if _, ok := h.authorizeCelebrations(c); !ok {
+		return
+	}
+
+	settings, err := h.services.Celebration.GetSettings(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondCelebrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateCelebrationSettings altera a configuração do resumo semanal e das felicitações
+func (h *Handler) UpdateCelebrationSettings(c *gin.Context) {
+	user, ok := h.authorizeCelebrations(c)
+	if !ok {
+		return
+	}
+
+	var req CelebrationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	settings, err := h.services.Celebration.UpdateSettings(c.Request.Context(), &domain.CelebrationSettings{
+		CommunityID:          c.Param("communityId"),
+		DigestEnabled:        req.DigestEnabled,
+		DigestWeekday:        req.DigestWeekday,
+		DigestDays:           req.DigestDays,
+		NotifyGroupLeaders:   req.NotifyGroupLeaders,
+		DigestEmails:         req.DigestEmails,
+		GreetingsEnabled:     req.GreetingsEnabled,
+		BirthdayTemplateID:   req.BirthdayTemplateID,
+		WeddingTemplateID:    req.WeddingTemplateID,
+		BaptismTemplateID:    req.BaptismTemplateID,
+		MembershipTemplateID: req.MembershipTemplateID,
+	}, user.ID)
+	if err != nil {
+		h.respondCelebrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Configuração atualizada com sucesso",
+		"settings": settings,
+	})
+}
+
+// SendCelebrationDigest envia agora o resumo das datas comemorativas
+func (h *Handler) SendCelebrationDigest(c *gin.Context) {
+	if _, ok := h.authorizeCelebrations(c); !ok {
+		return
+	}
+
+	result, err := h.services.Celebration.SendDigest(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondCelebrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Resumo enviado com sucesso",
+		"result":  result,
+	})
+}
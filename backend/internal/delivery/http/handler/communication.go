@@ -18,7 +18,7 @@ type CreateCommunicationRequest struct {
 	Type          string `json:"type" binding:"required,oneof=email sms whatsapp"`
 	Subject       string `json:"subject" binding:"required"`
 	Content       string `json:"content" binding:"required"`
-	RecipientType string `json:"recipient_type" binding:"required,oneof=member group family custom"`
+	RecipientType string `json:"recipient_type" binding:"required,oneof=member group family custom segment"`
 	RecipientID   string `json:"recipient_id" binding:"required"`
 }
 
@@ -26,7 +26,7 @@ type UpdateCommunicationRequest struct {
 	Type          string `json:"type" binding:"required,oneof=email sms whatsapp"`
 	Subject       string `json:"subject" binding:"required"`
 	Content       string `json:"content" binding:"required"`
-	RecipientType string `json:"recipient_type" binding:"required,oneof=member group family custom"`
+	RecipientType string `json:"recipient_type" binding:"required,oneof=member group family custom segment"`
 	RecipientID   string `json:"recipient_id" binding:"required"`
 }
 
@@ -186,6 +186,37 @@ func (h *Handler) GetCommunication(c *gin.Context) {
 	})
 }
 
+// ListCommunicationRecipients lista os destinatários de uma comunicação com o status de cada envio
+func (h *Handler) ListCommunicationRecipients(c *gin.Context) {
+	communityID := c.Param("communityId")
+	communicationID := c.Param("communicationId")
+
+	communication, err := h.services.Communication.GetCommunication(context.Background(), communityID, communicationID)
+	if err != nil || communication == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunicação não encontrada"})
+		return
+	}
+
+	recipients, err := h.services.Communication.ListRecipients(context.Background(), communityID, communicationID)
+	if err != nil {
+		h.logger.Error("erro ao listar destinatários", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	// Resume o andamento do envio por status
+	summary := map[domain.CommunicationStatus]int{}
+	for _, recipient := range recipients {
+		summary[recipient.Status]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"communication": communication,
+		"recipients":    recipients,
+		"summary":       summary,
+	})
+}
+
 func (h *Handler) UpdateCommunication(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -1,47 +1,444 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"time"
 
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-func (h *Handler) CreateContribution(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+type ContributionBatchRequest struct {
+	CategoryID  string  `json:"category_id" binding:"required,uuid"`
+	EventID     *string `json:"event_id" binding:"omitempty,uuid"`
+	Date        string  `json:"date" binding:"required"`
+	Description string  `json:"description"`
+	Notes       string  `json:"notes"`
 }
 
-func (h *Handler) ListContributions(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+type ContributionRequest struct {
+	MemberID       *string `json:"member_id" binding:"omitempty,uuid"`
+	Type           string  `json:"type" binding:"required,oneof=tithe offering donation"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Method         string  `json:"method" binding:"required,oneof=cash check credit_card bank_transfer"`
+	EnvelopeNumber string  `json:"envelope_number"`
+	CheckNumber    string  `json:"check_number"`
+	Notes          string  `json:"notes"`
 }
 
-func (h *Handler) GetContribution(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+type ContributionBatchCountRequest struct {
+	CountedTotal float64 `json:"counted_total" binding:"required,gt=0"`
 }
 
-func (h *Handler) UpdateContribution(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+// authorizeContributions verifica se o usuário pode gerenciar as contribuições da comunidade
+func (h *Handler) authorizeContributions(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores podem contar e conferir lotes
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar contribuições"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
 }
 
-func (h *Handler) DeleteContribution(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+// respondContributionError converte os erros do serviço de contribuições em respostas HTTP
+func (h *Handler) respondContributionError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrBatchNotFound, service.ErrContributionNotFound, service.ErrMemberNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidCategory, service.ErrBatchEmpty, service.ErrBatchTotalMismatch:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrBatchLocked, service.ErrBatchNotCounted:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case service.ErrSameVerifier:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar contribuições", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
 }
 
+// CreateContributionBatch abre um novo lote de contagem
 func (h *Handler) CreateContributionBatch(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+	user, ok := h.authorizeContributions(c)
+	if !ok {
+		return
+	}
+
+	var req ContributionBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida"})
+		return
+	}
+
+	batch := &domain.ContributionBatch{
+		CommunityID: c.Param("communityId"),
+		CategoryID:  req.CategoryID,
+		EventID:     req.EventID,
+		Date:        date,
+		Description: req.Description,
+		Notes:       req.Notes,
+		OpenedBy:    user.ID,
+	}
+
+	if err := h.services.Contribution.OpenBatch(c.Request.Context(), batch); err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Lote aberto com sucesso",
+		"batch":   batch,
+	})
 }
 
+// ListContributionBatches lista os lotes de contagem da comunidade
 func (h *Handler) ListContributionBatches(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	communityID := c.Param("communityId")
+	filter := repository.NewFilterFromQuery(c)
+	if status := c.Query("status"); status != "" {
+		filter.AddCondition("status = ?", status)
+	}
+
+	batches, total, err := h.repos.Contribution.ListBatches(c.Request.Context(), communityID, filter)
+	if err != nil {
+		h.logger.Error("erro ao listar lotes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar lotes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batches": batches,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
 }
 
+// GetContributionBatch retorna o lote com seus envelopes
 func (h *Handler) GetContributionBatch(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	batch, err := h.services.Contribution.GetBatch(c.Request.Context(), c.Param("communityId"), c.Param("batchId"))
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch": batch})
 }
 
+// UpdateContributionBatch atualiza os dados de um lote aberto
 func (h *Handler) UpdateContributionBatch(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "not implemented"})
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	communityID := c.Param("communityId")
+
+	var req ContributionBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida"})
+		return
+	}
+
+	batch, err := h.repos.Contribution.FindBatchByID(c.Request.Context(), communityID, c.Param("batchId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar lote", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if batch == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lote não encontrado"})
+		return
+	}
+	if !batch.IsOpen() {
+		h.respondContributionError(c, service.ErrBatchLocked)
+		return
+	}
+
+	category, err := h.repos.FinancialCategory.FindByID(c.Request.Context(), communityID, req.CategoryID)
+	if err != nil {
+		h.logger.Error("erro ao buscar categoria", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if category == nil || category.Type != "revenue" {
+		h.respondContributionError(c, service.ErrInvalidCategory)
+		return
+	}
+
+	batch.CategoryID = req.CategoryID
+	batch.Category = category
+	batch.EventID = req.EventID
+	batch.Date = date
+	batch.Description = req.Description
+	batch.Notes = req.Notes
+	batch.UpdatedAt = time.Now()
+
+	if err := h.repos.Contribution.UpdateBatch(c.Request.Context(), batch); err != nil {
+		h.logger.Error("erro ao atualizar lote", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar lote"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lote atualizado com sucesso",
+		"batch":   batch,
+	})
 }
 
-func (h *Handler) AddContribution(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Método ainda não implementado"})
+// CloseContributionBatch encerra a contagem e envia o lote para conferência
+func (h *Handler) CloseContributionBatch(c *gin.Context) {
+	user, ok := h.authorizeContributions(c)
+	if !ok {
+		return
+	}
+
+	var req ContributionBatchCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	batch, err := h.services.Contribution.SubmitBatch(c.Request.Context(), c.Param("communityId"), c.Param("batchId"), user.ID, req.CountedTotal)
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lote fechado e aguardando conferência",
+		"batch":   batch,
+	})
+}
+
+// VerifyContributionBatch confere o lote por uma segunda pessoa e lança as receitas por forma de pagamento
+func (h *Handler) VerifyContributionBatch(c *gin.Context) {
+	user, ok := h.authorizeContributions(c)
+	if !ok {
+		return
+	}
+
+	var req ContributionBatchCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	batch, err := h.services.Contribution.VerifyBatch(c.Request.Context(), c.Param("communityId"), c.Param("batchId"), user.ID, req.CountedTotal)
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lote conferido e travado com sucesso",
+		"batch":   batch,
+	})
+}
+
+// ReopenContributionBatch devolve para contagem um lote que aguardava conferência
+func (h *Handler) ReopenContributionBatch(c *gin.Context) {
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	batch, err := h.services.Contribution.ReopenBatch(c.Request.Context(), c.Param("communityId"), c.Param("batchId"))
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lote reaberto com sucesso",
+		"batch":   batch,
+	})
+}
+
+// CreateContribution registra um envelope no lote
+func (h *Handler) CreateContribution(c *gin.Context) {
+	user, ok := h.authorizeContributions(c)
+	if !ok {
+		return
+	}
+
+	var req ContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	contribution := &domain.Contribution{
+		MemberID:       req.MemberID,
+		UserID:         user.ID,
+		Type:           req.Type,
+		Amount:         req.Amount,
+		Method:         req.Method,
+		EnvelopeNumber: req.EnvelopeNumber,
+		CheckNumber:    req.CheckNumber,
+		Notes:          req.Notes,
+	}
+
+	if err := h.services.Contribution.RecordContribution(c.Request.Context(), c.Param("communityId"), c.Param("batchId"), contribution); err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Contribuição registrada com sucesso",
+		"contribution": contribution,
+	})
+}
+
+// ListContributions lista as contribuições da comunidade
+func (h *Handler) ListContributions(c *gin.Context) {
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	communityID := c.Param("communityId")
+	filter := repository.NewFilterFromQuery(c)
+	if memberID := c.Query("member_id"); memberID != "" {
+		filter.AddCondition("member_id = ?", memberID)
+	}
+	if contributionType := c.Query("type"); contributionType != "" {
+		filter.AddCondition("type = ?", contributionType)
+	}
+
+	contributions, total, err := h.repos.Contribution.List(c.Request.Context(), communityID, filter)
+	if err != nil {
+		h.logger.Error("erro ao listar contribuições", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar contribuições"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contributions": contributions,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetContribution retorna um envelope do lote
+func (h *Handler) GetContribution(c *gin.Context) {
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	contribution, err := h.services.Contribution.FindContribution(c.Request.Context(), c.Param("communityId"), c.Param("batchId"), c.Param("contributionId"))
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contribution": contribution})
+}
+
+// UpdateContribution altera um envelope de um lote aberto
+func (h *Handler) UpdateContribution(c *gin.Context) {
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	communityID := c.Param("communityId")
+	batchID := c.Param("batchId")
+
+	var req ContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	contribution, err := h.services.Contribution.FindContribution(c.Request.Context(), communityID, batchID, c.Param("contributionId"))
+	if err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	contribution.MemberID = req.MemberID
+	contribution.Type = req.Type
+	contribution.Amount = req.Amount
+	contribution.Method = req.Method
+	contribution.EnvelopeNumber = req.EnvelopeNumber
+	contribution.CheckNumber = req.CheckNumber
+	contribution.Notes = req.Notes
+
+	if err := h.services.Contribution.UpdateContribution(c.Request.Context(), communityID, batchID, contribution); err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Contribuição atualizada com sucesso",
+		"contribution": contribution,
+	})
+}
+
+// DeleteContribution remove um envelope de um lote aberto
+func (h *Handler) DeleteContribution(c *gin.Context) {
+	if _, ok := h.authorizeContributions(c); !ok {
+		return
+	}
+
+	if err := h.services.Contribution.DeleteContribution(c.Request.Context(), c.Param("communityId"), c.Param("batchId"), c.Param("contributionId")); err != nil {
+		h.respondContributionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contribuição excluída com sucesso",
+	})
 }
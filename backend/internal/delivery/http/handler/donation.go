@@ -7,6 +7,7 @@ import (
 
 	"github.com/comunidade/backend/internal/domain"
 	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -26,7 +27,7 @@ type AddRecurringDonationRequest struct {
 	MemberID       *string `json:"member_id"`
 	Amount         float64 `json:"amount" binding:"required"`
 	PaymentMethod  string  `json:"payment_method" binding:"required"`
-	DueDay         int     `json:"due_day" binding:"required"`
+	DueDay         int     `json:"due_day" binding:"required,min=1,max=31"`
 	Description    string  `json:"description" binding:"required"`
 	CustomerName   string  `json:"customer_name" binding:"required"`
 	CustomerCPF    string  `json:"customer_cpf" binding:"required"`
@@ -217,35 +218,27 @@ func createDonation(member *domain.Member, req AddDonationRequest, communityID,
 	}
 }
 
-// Função auxiliar para processar uma doação (criar cliente e pagamento no ASAAS)
+// Função auxiliar para processar uma doação (criar cliente e cobrança no provedor de pagamentos)
 func processDonation(c *gin.Context, h *Handler, donation *domain.Donation) error {
-	// 1. Criar ou obter o cliente no ASAAS
-	customerID, err := h.services.Asaas.CreateCustomer(c.Request.Context(), donation.CommunityID, donation)
-	if err != nil {
-		h.logger.Error("erro ao criar cliente no ASAAS", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
-		return err
-	}
-
-	// 2. Criar o pagamento no ASAAS
-	paymentID, err := h.services.Asaas.CreatePayment(c.Request.Context(), donation.CommunityID, donation, customerID)
-	if err != nil {
-		h.logger.Error("erro ao criar pagamento no ASAAS", zap.Error(err))
+	// 1. Criar o cliente e a cobrança no provedor da comunidade
+	if err := h.services.Payment.CreateDonationCharge(c.Request.Context(), donation); err != nil {
+		if err == service.ErrPaymentMethodUnsupported {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return err
+		}
+		h.logger.Error("erro ao criar cobrança da doação", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar pagamento"})
 		return err
 	}
 
-	// Atualiza os IDs do ASAAS na doação
-	donation.AsaasID = paymentID
-
-	// 3. Salva a doação no banco
+	// 2. Salva a doação no banco
 	if err := h.repos.Donation.Create(c.Request.Context(), donation); err != nil {
 		h.logger.Error("erro ao criar doação", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
 		return err
 	}
 
-	// 4. Atualiza a doação com o link de pagamento
+	// 3. Atualiza a doação com o link de pagamento
 	if donation.PaymentLink != "" {
 		if err := h.repos.Donation.Update(c.Request.Context(), donation); err != nil {
 			h.logger.Error("erro ao atualizar link de pagamento", zap.Error(err))
@@ -345,25 +338,17 @@ func (h *Handler) AddRecurringDonation(c *gin.Context) {
 		UpdatedAt: time.Now(),
 	}
 
-	// 1. Criar ou obter o cliente no ASAAS
-	customerID, err := h.services.Asaas.CreateCustomerFromRecurringDonation(c.Request.Context(), communityID, recurringDonation)
-	if err != nil {
-		h.logger.Error("erro ao criar cliente no ASAAS", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
-		return
-	}
-
-	// 2. Criar a assinatura no ASAAS
-	subscriptionID, err := h.services.Asaas.CreateSubscription(c.Request.Context(), communityID, recurringDonation, customerID)
-	if err != nil {
-		h.logger.Error("erro ao criar assinatura no ASAAS", zap.Error(err))
+	// Cria o cliente e a assinatura no provedor de pagamentos da comunidade
+	if err := h.services.Payment.CreateSubscription(c.Request.Context(), recurringDonation); err != nil {
+		if err == service.ErrPaymentMethodUnsupported {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("erro ao criar assinatura", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar assinatura"})
 		return
 	}
 
-	// Atualiza os IDs do ASAAS na doação recorrente
-	recurringDonation.AsaasID = subscriptionID
-
 	if err := h.repos.RecurringDonation.Create(c.Request.Context(), recurringDonation); err != nil {
 		h.logger.Error("erro ao criar doação recorrente", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
@@ -468,17 +453,15 @@ func (h *Handler) UpdateDonation(c *gin.Context) {
 	donation.Status = req.Status
 	donation.UpdatedAt = time.Now()
 
-	// Atualiza a cobrança no ASAAS
-	if donation.AsaasPaymentID != "" {
-		if err := h.services.Asaas.UpdatePayment(context.Background(), communityID, donation.AsaasPaymentID, donation); err != nil {
-			h.logger.Error("erro ao atualizar cobrança no ASAAS", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cobrança no ASAAS"})
-			return
-		}
+	// Atualiza a cobrança no provedor de pagamentos
+	if err := h.services.Payment.UpdateDonationCharge(context.Background(), donation); err != nil {
+		h.logger.Error("erro ao atualizar cobrança no provedor de pagamentos", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cobrança no provedor de pagamentos"})
+		return
 	}
 
-	// Salva as alterações no banco
-	if err := h.repos.Donation.Update(context.Background(), donation); err != nil {
+	// Salva as alterações e lança ou estorna a receita da doação conforme o novo status
+	if err := h.services.Posting.SaveDonation(context.Background(), donation); err != nil {
 		h.logger.Error("erro ao atualizar doação", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar doação"})
 		return
@@ -519,17 +502,15 @@ func (h *Handler) DeleteDonation(c *gin.Context) {
 		return
 	}
 
-	// Exclui a cobrança no ASAAS
-	if donation.AsaasPaymentID != "" {
-		if err := h.services.Asaas.DeletePayment(context.Background(), communityID, donation.AsaasPaymentID); err != nil {
-			h.logger.Error("erro ao excluir cobrança no ASAAS", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir cobrança no ASAAS"})
-			return
-		}
+	// Exclui a cobrança no provedor de pagamentos
+	if err := h.services.Payment.CancelDonationCharge(context.Background(), donation); err != nil {
+		h.logger.Error("erro ao excluir cobrança no provedor de pagamentos", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir cobrança no provedor de pagamentos"})
+		return
 	}
 
-	// Exclui a doação do banco
-	if err := h.repos.Donation.Delete(context.Background(), communityID, donationID); err != nil {
+	// Estorna a receita lançada e exclui a doação do banco
+	if err := h.services.Posting.DeleteDonation(context.Background(), donation); err != nil {
 		h.logger.Error("erro ao excluir doação", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir doação"})
 		return
@@ -575,8 +556,12 @@ func (h *Handler) SendPaymentLink(c *gin.Context) {
 		return
 	}
 
-	// Envia o e-mail através do ASAAS
-	if err := h.services.Asaas.SendPaymentLink(context.Background(), communityID, donation.AsaasPaymentID, donation.CustomerEmail); err != nil {
+	// Envia o e-mail através do provedor de pagamentos
+	if err := h.services.Payment.SendDonationLink(context.Background(), donation); err != nil {
+		if err == service.ErrGatewayUnsupported {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("erro ao enviar link de pagamento", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao enviar link de pagamento"})
 		return
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DonationPostingRuleRequest struct {
+	CategoryID    string  `json:"category_id" binding:"required,uuid"`
+	CampaignID    *string `json:"campaign_id" binding:"omitempty,uuid"`
+	PaymentMethod string  `json:"payment_method" binding:"omitempty,oneof=credit_card boleto pix"`
+}
+
+// validateDonationPostingRule verifica se a categoria é de receita e se a campanha existe
+func (h *Handler) validateDonationPostingRule(c *gin.Context, communityID string, req *DonationPostingRuleRequest) bool {
+	category, err := h.repos.FinancialCategory.FindByID(context.Background(), communityID, req.CategoryID)
+	if err != nil {
+		h.logger.Error("erro ao buscar categoria", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return false
+	}
+	if category == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Categoria não encontrada"})
+		return false
+	}
+	if category.Type != "revenue" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A categoria deve ser do tipo receita"})
+		return false
+	}
+
+	if req.CampaignID != nil && *req.CampaignID != "" {
+		if _, err := h.repos.Campaign.FindByID(context.Background(), communityID, *req.CampaignID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campanha não encontrada"})
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddDonationPostingRule cria uma regra de lançamento automático de doações
+func (h *Handler) AddDonationPostingRule(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para configurar lançamentos"})
+		return
+	}
+
+	var req DonationPostingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !h.validateDonationPostingRule(c, communityID, &req) {
+		return
+	}
+
+	rule := &domain.DonationPostingRule{
+		CommunityID:   communityID,
+		UserID:        user.(*domain.User).ID,
+		CategoryID:    req.CategoryID,
+		CampaignID:    req.CampaignID,
+		PaymentMethod: req.PaymentMethod,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := h.repos.DonationPostingRule.Create(context.Background(), rule); err != nil {
+		h.logger.Error("erro ao criar regra de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar regra de lançamento"})
+		return
+	}
+
+	// Lança as doações pagas que aguardavam uma regra de lançamento
+	posted, err := h.services.Posting.PostUnposted(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao lançar doações pendentes", zap.Error(err))
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":          "Regra de lançamento criada com sucesso",
+		"rule":             rule,
+		"posted_donations": posted,
+	})
+}
+
+// ListDonationPostingRules lista as regras de lançamento automático de doações
+func (h *Handler) ListDonationPostingRules(c *gin.Context) {
+	communityID := c.Param("communityId")
+
+	rules, err := h.repos.DonationPostingRule.List(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao listar regras de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar regras de lançamento"})
+		return
+	}
+
+	// Doações pagas que ainda não foram lançadas por falta de regra
+	unposted, err := h.services.Posting.UnpostedCount(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao contar doações sem lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar regras de lançamento"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules":              rules,
+		"unposted_donations": unposted,
+	})
+}
+
+// UpdateDonationPostingRule atualiza uma regra de lançamento automático de doações
+func (h *Handler) UpdateDonationPostingRule(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return
+	}
+
+	communityID := c.Param("communityId")
+	ruleID := c.Param("id")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para configurar lançamentos"})
+		return
+	}
+
+	rule, err := h.repos.DonationPostingRule.FindByID(context.Background(), communityID, ruleID)
+	if err != nil {
+		h.logger.Error("erro ao buscar regra de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if rule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Regra de lançamento não encontrada"})
+		return
+	}
+
+	var req DonationPostingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !h.validateDonationPostingRule(c, communityID, &req) {
+		return
+	}
+
+	rule.CategoryID = req.CategoryID
+	rule.CampaignID = req.CampaignID
+	rule.PaymentMethod = req.PaymentMethod
+	rule.Category = nil
+	rule.UpdatedAt = time.Now()
+
+	if err := h.repos.DonationPostingRule.Update(context.Background(), rule); err != nil {
+		h.logger.Error("erro ao atualizar regra de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar regra de lançamento"})
+		return
+	}
+
+	// Lança as doações pagas que aguardavam uma regra de lançamento
+	posted, err := h.services.Posting.PostUnposted(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao lançar doações pendentes", zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Regra de lançamento atualizada com sucesso",
+		"rule":             rule,
+		"posted_donations": posted,
+	})
+}
+
+// DeleteDonationPostingRule exclui uma regra de lançamento automático de doações
+func (h *Handler) DeleteDonationPostingRule(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return
+	}
+
+	communityID := c.Param("communityId")
+	ruleID := c.Param("id")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para configurar lançamentos"})
+		return
+	}
+
+	rule, err := h.repos.DonationPostingRule.FindByID(context.Background(), communityID, ruleID)
+	if err != nil {
+		h.logger.Error("erro ao buscar regra de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if rule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Regra de lançamento não encontrada"})
+		return
+	}
+
+	if err := h.repos.DonationPostingRule.Delete(context.Background(), communityID, ruleID); err != nil {
+		h.logger.Error("erro ao excluir regra de lançamento", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir regra de lançamento"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Regra de lançamento excluída com sucesso",
+	})
+}
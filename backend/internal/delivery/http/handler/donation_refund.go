@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RefundDonationRequest struct {
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Reason string   `json:"reason" binding:"required,max=255"`
+}
+
+// RefundDonation estorna total ou parcialmente uma doação paga. Sem valor, estorna todo
+// o valor ainda não devolvido.
+func (h *Handler) RefundDonation(c *gin.Context) {
+	community, err := h.repos.Community.FindByID(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != c.GetString("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para reembolsar doações"})
+		return
+	}
+
+	var req RefundDonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	donation, err := h.services.Payment.RefundDonation(c.Request.Context(), community.ID, c.Param("donationId"), &service.DonationRefund{
+		Amount: req.Amount,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrDonationNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Doação não encontrada"})
+		case service.ErrDonationNotRefundable:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case service.ErrInvalidRefundAmount, service.ErrRefundReasonRequired, service.ErrGatewayUnsupported:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrRefundGateway:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("erro ao reembolsar doação", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Doação reembolsada com sucesso",
+		"donation": donation,
+	})
+}
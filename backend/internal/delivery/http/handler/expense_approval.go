@@ -0,0 +1,484 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxReceiptSize limita o tamanho do comprovante enviado
+const maxReceiptSize = 10 << 20
+
+// receiptExtensions são os formatos aceitos para comprovantes de despesas
+var receiptExtensions = map[string]bool{
+	".pdf":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+}
+
+type ExpenseApprovalRuleRequest struct {
+	Name           string   `json:"name" binding:"required"`
+	Description    string   `json:"description"`
+	Step           int      `json:"step" binding:"required,min=1"`
+	MinAmount      float64  `json:"min_amount" binding:"min=0"`
+	CategoryID     *string  `json:"category_id" binding:"omitempty,uuid"`
+	ApproverIDs    []string `json:"approver_ids" binding:"omitempty,dive,uuid"`
+	RequireReceipt bool     `json:"require_receipt"`
+	Active         *bool    `json:"active"`
+}
+
+type ExpenseDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// authorizeExpenseApprovalRules verifica se o usuário pode configurar as regras de aprovação
+func (h *Handler) authorizeExpenseApprovalRules(c *gin.Context) (*domain.Community, bool) {
+	user, community, ok := h.expenseApprovalContext(c)
+	if !ok {
+		return nil, false
+	}
+
+	if community.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para configurar a aprovação de despesas"})
+		return nil, false
+	}
+	return community, true
+}
+
+// authorizeExpenseReceipts verifica se o usuário pode gerenciar os comprovantes de
+// despesas: o criador da comunidade e os administradores
+func (h *Handler) authorizeExpenseReceipts(c *gin.Context) (*domain.User, *domain.Expense, bool) {
+	user, community, ok := h.expenseApprovalContext(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if community.CreatedBy != user.ID {
+		if err := h.checkUserPermission(context.Background(), user.ID, community.ID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar comprovantes de despesas"})
+			return nil, nil, false
+		}
+	}
+
+	expense, ok := h.findExpenseForApproval(c, community.ID)
+	if !ok {
+		return nil, nil, false
+	}
+	return user, expense, true
+}
+
+// expenseApprovalContext carrega o usuário autenticado e a comunidade. A permissão
+// para decidir sobre uma despesa é verificada pelo serviço, conforme as regras.
+func (h *Handler) expenseApprovalContext(c *gin.Context) (*domain.User, *domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	return user.(*domain.User), community, true
+}
+
+func (h *Handler) findExpenseForApproval(c *gin.Context, communityID string) (*domain.Expense, bool) {
+	expense, err := h.repos.Expense.FindByID(c.Request.Context(), communityID, c.Param("id"))
+	if err != nil {
+		h.logger.Error("erro ao buscar despesa", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if expense == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Despesa não encontrada"})
+		return nil, false
+	}
+	return expense, true
+}
+
+// respondExpenseApprovalError converte os erros do fluxo de aprovação em respostas HTTP
+func (h *Handler) respondExpenseApprovalError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrApprovalRuleNotFound, service.ErrInvalidApprover:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidApprovalRule, service.ErrInvalidExpenseCategory, service.ErrRejectionReasonRequired:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrNotExpenseApprover, service.ErrAlreadyApprovedStep, service.ErrSelfApproval:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case service.ErrExpenseApprovalRequired, service.ErrExpenseNotAwaitingApproval, service.ErrReceiptRequired:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar aprovação de despesa", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// notifyExpenseApprovers avisa em segundo plano os aprovadores da etapa atual da despesa
+func (h *Handler) notifyExpenseApprovers(community *domain.Community, expense *domain.Expense) {
+	go h.services.ExpenseApproval.NotifyRequested(context.Background(), community, expense)
+}
+
+// AddExpenseApprovalRule cria uma etapa de aprovação de despesas
+func (h *Handler) AddExpenseApprovalRule(c *gin.Context) {
+	community, ok := h.authorizeExpenseApprovalRules(c)
+	if !ok {
+		return
+	}
+
+	var req ExpenseApprovalRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	rule := &domain.ExpenseApprovalRule{
+		CommunityID:    community.ID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Step:           req.Step,
+		MinAmount:      req.MinAmount,
+		CategoryID:     req.CategoryID,
+		ApproverIDs:    req.ApproverIDs,
+		RequireReceipt: req.RequireReceipt,
+		Active:         req.Active == nil || *req.Active,
+	}
+	if err := h.services.ExpenseApproval.CreateRule(c.Request.Context(), rule); err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Regra de aprovação criada com sucesso",
+		"rule":    rule,
+	})
+}
+
+// ListExpenseApprovalRules lista as etapas de aprovação de despesas
+func (h *Handler) ListExpenseApprovalRules(c *gin.Context) {
+	community, ok := h.authorizeExpenseApprovalRules(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	rules, total, err := h.repos.ExpenseApproval.ListRules(c.Request.Context(), community.ID, filter)
+	if err != nil {
+		h.logger.Error("erro ao listar regras de aprovação", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar regras de aprovação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// UpdateExpenseApprovalRule atualiza uma etapa de aprovação de despesas
+func (h *Handler) UpdateExpenseApprovalRule(c *gin.Context) {
+	community, ok := h.authorizeExpenseApprovalRules(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.services.ExpenseApproval.GetRule(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	var req ExpenseApprovalRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	rule.Name = req.Name
+	rule.Description = req.Description
+	rule.Step = req.Step
+	rule.MinAmount = req.MinAmount
+	rule.CategoryID = req.CategoryID
+	rule.ApproverIDs = req.ApproverIDs
+	rule.RequireReceipt = req.RequireReceipt
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+
+	if err := h.services.ExpenseApproval.UpdateRule(c.Request.Context(), rule); err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Regra de aprovação atualizada com sucesso",
+		"rule":    rule,
+	})
+}
+
+// DeleteExpenseApprovalRule exclui uma etapa de aprovação de despesas
+func (h *Handler) DeleteExpenseApprovalRule(c *gin.Context) {
+	community, ok := h.authorizeExpenseApprovalRules(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.services.ExpenseApproval.GetRule(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	if err := h.repos.ExpenseApproval.DeleteRule(c.Request.Context(), community.ID, rule.ID); err != nil {
+		h.logger.Error("erro ao excluir regra de aprovação", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir regra de aprovação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Regra de aprovação excluída com sucesso"})
+}
+
+// ListExpensesAwaitingApproval lista as despesas que aguardam a aprovação do usuário
+func (h *Handler) ListExpensesAwaitingApproval(c *gin.Context) {
+	user, community, ok := h.expenseApprovalContext(c)
+	if !ok {
+		return
+	}
+
+	expenses, err := h.services.ExpenseApproval.AwaitingApproval(c.Request.Context(), community, user.ID)
+	if err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"expenses": expenses})
+}
+
+// GetExpenseApproval retorna a despesa com o histórico de aprovação e os comprovantes
+func (h *Handler) GetExpenseApproval(c *gin.Context) {
+	user, community, ok := h.expenseApprovalContext(c)
+	if !ok {
+		return
+	}
+
+	expense, ok := h.findExpenseForApproval(c, community.ID)
+	if !ok {
+		return
+	}
+
+	// Além do criador da comunidade e dos administradores, os aprovadores das
+	// despesas aguardando sua decisão podem consultá-las
+	if community.CreatedBy != user.ID && h.checkUserPermission(context.Background(), user.ID, community.ID) != nil {
+		awaiting, err := h.services.ExpenseApproval.AwaitingApproval(c.Request.Context(), community, user.ID)
+		if err != nil {
+			h.respondExpenseApprovalError(c, err)
+			return
+		}
+		allowed := false
+		for _, item := range awaiting {
+			if item.ID == expense.ID {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para consultar esta despesa"})
+			return
+		}
+	}
+
+	approvals, err := h.services.ExpenseApproval.ListApprovals(c.Request.Context(), expense.ID)
+	if err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+	receipts, err := h.repos.ExpenseApproval.ListReceipts(c.Request.Context(), expense.ID)
+	if err != nil {
+		h.logger.Error("erro ao listar comprovantes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expense":   expense,
+		"approvals": approvals,
+		"receipts":  receipts,
+	})
+}
+
+// ApproveExpense aprova a etapa atual da despesa
+func (h *Handler) ApproveExpense(c *gin.Context) {
+	h.decideExpense(c, true)
+}
+
+// RejectExpense rejeita a despesa; o comentário com o motivo é obrigatório
+func (h *Handler) RejectExpense(c *gin.Context) {
+	h.decideExpense(c, false)
+}
+
+func (h *Handler) decideExpense(c *gin.Context, approve bool) {
+	user, community, ok := h.expenseApprovalContext(c)
+	if !ok {
+		return
+	}
+
+	expense, ok := h.findExpenseForApproval(c, community.ID)
+	if !ok {
+		return
+	}
+
+	var req ExpenseDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	message := "Despesa aprovada com sucesso"
+	var err error
+	if approve {
+		err = h.services.ExpenseApproval.Approve(c.Request.Context(), community, expense, user.ID, req.Comment)
+	} else {
+		message = "Despesa rejeitada com sucesso"
+		err = h.services.ExpenseApproval.Reject(c.Request.Context(), community, expense, user.ID, req.Comment)
+	}
+	if err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"expense": expense,
+	})
+}
+
+// UploadExpenseReceipt anexa um comprovante (PDF ou imagem) à despesa
+func (h *Handler) UploadExpenseReceipt(c *gin.Context) {
+	user, expense, ok := h.authorizeExpenseReceipts(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não enviado"})
+		return
+	}
+	if file.Size > maxReceiptSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo deve ter no máximo 10MB"})
+		return
+	}
+	if !receiptExtensions[strings.ToLower(filepath.Ext(file.Filename))] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato não suportado. Envie um PDF ou uma imagem (JPG, PNG ou WEBP)"})
+		return
+	}
+
+	path, err := h.services.Upload.SaveFile(file, "expenses/receipts")
+	if err != nil {
+		h.logger.Error("erro ao salvar comprovante", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar comprovante"})
+		return
+	}
+
+	receipt := &domain.ExpenseReceipt{
+		ExpenseID:   expense.ID,
+		CommunityID: expense.CommunityID,
+		UserID:      user.ID,
+		Filename:    filepath.Base(file.Filename),
+		Path:        path,
+		ContentType: file.Header.Get("Content-Type"),
+		Size:        file.Size,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.repos.ExpenseApproval.CreateReceipt(c.Request.Context(), receipt); err != nil {
+		h.logger.Error("erro ao registrar comprovante", zap.Error(err))
+		if err := h.services.Upload.DeleteFile(path); err != nil {
+			h.logger.Error("erro ao excluir comprovante", zap.Error(err))
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar comprovante"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Comprovante anexado com sucesso",
+		"receipt": receipt,
+		"url":     "/uploads/" + path,
+	})
+}
+
+// ListExpenseReceipts lista os comprovantes da despesa
+func (h *Handler) ListExpenseReceipts(c *gin.Context) {
+	_, expense, ok := h.authorizeExpenseReceipts(c)
+	if !ok {
+		return
+	}
+
+	receipts, err := h.repos.ExpenseApproval.ListReceipts(c.Request.Context(), expense.ID)
+	if err != nil {
+		h.logger.Error("erro ao listar comprovantes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar comprovantes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"receipts": receipts})
+}
+
+// DeleteExpenseReceipt remove um comprovante. Os comprovantes de despesas pagas são
+// mantidos como prova do pagamento.
+func (h *Handler) DeleteExpenseReceipt(c *gin.Context) {
+	_, expense, ok := h.authorizeExpenseReceipts(c)
+	if !ok {
+		return
+	}
+
+	if expense.Status == "paid" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Os comprovantes de despesas pagas não podem ser excluídos"})
+		return
+	}
+
+	receipt, err := h.repos.ExpenseApproval.FindReceiptByID(c.Request.Context(), expense.ID, c.Param("receiptId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comprovante", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if receipt == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comprovante não encontrado"})
+		return
+	}
+
+	if err := h.repos.ExpenseApproval.DeleteReceipt(c.Request.Context(), expense.ID, receipt.ID); err != nil {
+		h.logger.Error("erro ao excluir comprovante", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir comprovante"})
+		return
+	}
+	if err := h.services.Upload.DeleteFile(receipt.Path); err != nil {
+		h.logger.Error("erro ao excluir arquivo do comprovante", zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comprovante excluído com sucesso"})
+}
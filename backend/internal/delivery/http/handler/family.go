@@ -2,9 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/comunidade/backend/pkg/ofx"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -12,13 +16,29 @@ import (
 type AddFamilyRequest struct {
 	Name         string `json:"name" binding:"required"`
 	Description  string `json:"description"`
-	HeadOfFamily string `json:"head_of_family"`
+	HeadOfFamily string `json:"head_of_family" binding:"omitempty,uuid"`
+	Address      string `json:"address"`
+	Number       string `json:"number" binding:"max=20"`
+	Neighborhood string `json:"neighborhood" binding:"max=100"`
+	City         string `json:"city" binding:"max=100"`
+	State        string `json:"state" binding:"max=100"`
+	Country      string `json:"country" binding:"max=100"`
+	ZipCode      string `json:"zip_code" binding:"max=20"`
 }
 
 type UpdateFamilyRequest struct {
 	Name         string `json:"name" binding:"required"`
 	Description  string `json:"description"`
-	HeadOfFamily string `json:"head_of_family"`
+	HeadOfFamily string `json:"head_of_family" binding:"omitempty,uuid"`
+	Address      string `json:"address"`
+	Number       string `json:"number" binding:"max=20"`
+	Neighborhood string `json:"neighborhood" binding:"max=100"`
+	City         string `json:"city" binding:"max=100"`
+	State        string `json:"state" binding:"max=100"`
+	Country      string `json:"country" binding:"max=100"`
+	ZipCode      string `json:"zip_code" binding:"max=20"`
+	// Copia o endereço da família para o cadastro dos seus membros
+	ApplyAddress bool `json:"apply_address"`
 }
 
 type AddFamilyMemberRequest struct {
@@ -26,14 +46,70 @@ type AddFamilyMemberRequest struct {
 	Role     string `json:"role" binding:"required"`
 }
 
-// ListFamilies lista todas as famílias de uma comunidade
-func (h *Handler) ListFamilies(c *gin.Context) {
+type MemberRelationsRequest struct {
+	SpouseID *string `json:"spouse_id" binding:"omitempty,uuid"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+// authorizeHouseholds verifica se o usuário pode manter as famílias da comunidade
+func (h *Handler) authorizeHouseholds(c *gin.Context) (*domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
 	communityID := c.Param("communityId")
 
-	families, err := h.repos.Family.ListByCommunity(context.Background(), communityID)
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
 	if err != nil {
-		h.logger.Error("erro ao listar famílias", zap.Error(err))
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores mantêm as famílias
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar as famílias desta comunidade"})
+			return nil, false
+		}
+	}
+
+	return community, true
+}
+
+func (h *Handler) respondHouseholdError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFamilyNotFound),
+		errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrMemberNotInFamily):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidFamilyRole),
+		errors.Is(err, service.ErrInvalidHousehold),
+		errors.Is(err, service.ErrInvalidRelationship):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar família", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// ListFamilies lista todas as famílias de uma comunidade
+func (h *Handler) ListFamilies(c *gin.Context) {
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
+
+	families, err := h.services.Household.List(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -44,25 +120,13 @@ func (h *Handler) ListFamilies(c *gin.Context) {
 
 // GetFamily retorna os detalhes de uma família
 func (h *Handler) GetFamily(c *gin.Context) {
-	communityID := c.Param("communityId")
-	familyID := c.Param("familyId")
-
-	family, err := h.repos.Family.FindByID(context.Background(), communityID, familyID)
-	if err != nil {
-		h.logger.Error("erro ao buscar família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-		return
-	}
-	if family == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Família não encontrada"})
+	if _, ok := h.authorizeHouseholds(c); !ok {
 		return
 	}
 
-	// Busca os membros da família
-	members, err := h.repos.Family.ListFamilyMembers(context.Background(), familyID)
+	family, members, err := h.services.Household.Get(c.Request.Context(), c.Param("communityId"), c.Param("familyId"))
 	if err != nil {
-		h.logger.Error("erro ao listar membros da família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -74,7 +138,9 @@ func (h *Handler) GetFamily(c *gin.Context) {
 
 // AddFamily cria uma nova família
 func (h *Handler) AddFamily(c *gin.Context) {
-	communityID := c.Param("communityId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
 	var req AddFamilyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -82,16 +148,21 @@ func (h *Handler) AddFamily(c *gin.Context) {
 		return
 	}
 
-	family := &domain.Family{
-		CommunityID:  communityID,
+	family, err := h.services.Household.Create(c.Request.Context(), &domain.Family{
+		CommunityID:  c.Param("communityId"),
 		Name:         req.Name,
 		Description:  req.Description,
 		HeadOfFamily: req.HeadOfFamily,
-	}
-
-	if err := h.repos.Family.Create(context.Background(), family); err != nil {
-		h.logger.Error("erro ao criar família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		Address:      req.Address,
+		Number:       req.Number,
+		Neighborhood: req.Neighborhood,
+		City:         req.City,
+		State:        req.State,
+		Country:      req.Country,
+		ZipCode:      req.ZipCode,
+	})
+	if err != nil {
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -103,8 +174,9 @@ func (h *Handler) AddFamily(c *gin.Context) {
 
 // UpdateFamily atualiza os dados de uma família
 func (h *Handler) UpdateFamily(c *gin.Context) {
-	communityID := c.Param("communityId")
-	familyID := c.Param("familyId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
 	var req UpdateFamilyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -112,24 +184,22 @@ func (h *Handler) UpdateFamily(c *gin.Context) {
 		return
 	}
 
-	family, err := h.repos.Family.FindByID(context.Background(), communityID, familyID)
+	family, err := h.services.Household.Update(c.Request.Context(), &domain.Family{
+		ID:           c.Param("familyId"),
+		CommunityID:  c.Param("communityId"),
+		Name:         req.Name,
+		Description:  req.Description,
+		HeadOfFamily: req.HeadOfFamily,
+		Address:      req.Address,
+		Number:       req.Number,
+		Neighborhood: req.Neighborhood,
+		City:         req.City,
+		State:        req.State,
+		Country:      req.Country,
+		ZipCode:      req.ZipCode,
+	}, req.ApplyAddress)
 	if err != nil {
-		h.logger.Error("erro ao buscar família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-		return
-	}
-	if family == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Família não encontrada"})
-		return
-	}
-
-	family.Name = req.Name
-	family.Description = req.Description
-	family.HeadOfFamily = req.HeadOfFamily
-
-	if err := h.repos.Family.Update(context.Background(), family); err != nil {
-		h.logger.Error("erro ao atualizar família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -141,12 +211,12 @@ func (h *Handler) UpdateFamily(c *gin.Context) {
 
 // DeleteFamily remove uma família
 func (h *Handler) DeleteFamily(c *gin.Context) {
-	communityID := c.Param("communityId")
-	familyID := c.Param("familyId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
-	if err := h.repos.Family.Delete(context.Background(), communityID, familyID); err != nil {
-		h.logger.Error("erro ao remover família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	if err := h.services.Household.Delete(c.Request.Context(), c.Param("communityId"), c.Param("familyId")); err != nil {
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -157,8 +227,9 @@ func (h *Handler) DeleteFamily(c *gin.Context) {
 
 // AddFamilyMember adiciona um membro à família
 func (h *Handler) AddFamilyMember(c *gin.Context) {
-	communityID := c.Param("communityId")
-	familyID := c.Param("familyId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
 	var req AddFamilyMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -166,39 +237,9 @@ func (h *Handler) AddFamilyMember(c *gin.Context) {
 		return
 	}
 
-	// Verifica se a família existe
-	family, err := h.repos.Family.FindByID(context.Background(), communityID, familyID)
-	if err != nil {
-		h.logger.Error("erro ao buscar família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-		return
-	}
-	if family == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Família não encontrada"})
-		return
-	}
-
-	// Verifica se o membro existe
-	member, err := h.repos.Member.FindByID(context.Background(), communityID, req.MemberID)
+	familyMember, err := h.services.Household.AddMember(c.Request.Context(), c.Param("communityId"), c.Param("familyId"), req.MemberID, req.Role)
 	if err != nil {
-		h.logger.Error("erro ao buscar membro", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-		return
-	}
-	if member == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Membro não encontrado"})
-		return
-	}
-
-	familyMember := &domain.FamilyMember{
-		FamilyID: familyID,
-		MemberID: req.MemberID,
-		Role:     req.Role,
-	}
-
-	if err := h.repos.Family.AddMember(context.Background(), familyMember); err != nil {
-		h.logger.Error("erro ao adicionar membro à família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -210,12 +251,12 @@ func (h *Handler) AddFamilyMember(c *gin.Context) {
 
 // RemoveFamilyMember remove um membro da família
 func (h *Handler) RemoveFamilyMember(c *gin.Context) {
-	familyID := c.Param("familyId")
-	memberID := c.Param("memberId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
-	if err := h.repos.Family.RemoveMember(context.Background(), familyID, memberID); err != nil {
-		h.logger.Error("erro ao remover membro da família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	if err := h.services.Household.RemoveMember(c.Request.Context(), c.Param("communityId"), c.Param("familyId"), c.Param("memberId")); err != nil {
+		h.respondHouseholdError(c, err)
 		return
 	}
 
@@ -226,8 +267,9 @@ func (h *Handler) RemoveFamilyMember(c *gin.Context) {
 
 // UpdateFamilyMemberRole atualiza o papel de um membro na família
 func (h *Handler) UpdateFamilyMemberRole(c *gin.Context) {
-	familyID := c.Param("familyId")
-	memberID := c.Param("memberId")
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
 
 	var req struct {
 		Role string `json:"role" binding:"required"`
@@ -237,13 +279,146 @@ func (h *Handler) UpdateFamilyMemberRole(c *gin.Context) {
 		return
 	}
 
-	if err := h.repos.Family.UpdateMemberRole(context.Background(), familyID, memberID, req.Role); err != nil {
-		h.logger.Error("erro ao atualizar papel do membro na família", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	familyMember, err := h.services.Household.UpdateRole(c.Request.Context(), c.Param("communityId"), c.Param("familyId"), c.Param("memberId"), req.Role)
+	if err != nil {
+		h.respondHouseholdError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Papel do membro atualizado com sucesso",
+		"member":  familyMember,
+	})
+}
+
+// GetFamilyGraph retorna o grafo da família com os parentes de fora da casa e o
+// parentesco derivado (avós, irmãos, netos)
+func (h *Handler) GetFamilyGraph(c *gin.Context) {
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
+
+	graph, err := h.services.Household.Graph(c.Request.Context(), c.Param("communityId"), c.Param("familyId"))
+	if err != nil {
+		h.respondHouseholdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"graph": graph})
+}
+
+// GetFamilyMailingList retorna a lista de correspondência com um destinatário por
+// família, em JSON, CSV ou XLSX
+func (h *Handler) GetFamilyMailingList(c *gin.Context) {
+	community, ok := h.authorizeHouseholds(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido. Use json, csv ou xlsx"})
+		return
+	}
+
+	mailing, err := h.services.Household.MailingList(c.Request.Context(), community.ID)
+	if err != nil {
+		h.respondHouseholdError(c, err)
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"mailing": mailing})
+		return
+	}
+
+	header := []string{"Destinatário", "Família", "Endereço", "Número", "Bairro", "Cidade", "Estado", "CEP", "País", "Membros"}
+	writer, err := newExportWriter(c, community, format, "correspondencia-familias", header, nil)
+	if err != nil {
+		h.logger.Error("erro ao iniciar exportação da correspondência", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	for _, row := range mailing {
+		values := []interface{}{row.Addressee, row.FamilyName, row.Address, row.Number, row.Neighborhood, row.City, row.State, row.ZipCode, row.Country, len(row.MemberIDs)}
+		if err = writer.Write(values, ofx.Transaction{}); err != nil {
+			break
+		}
+	}
+	h.finishExport(c, writer, err, "correspondência")
+}
+
+// ListMemberRelatives lista os parentes do membro (cônjuge, pais, irmãos, filhos, avós e
+// netos)
+func (h *Handler) ListMemberRelatives(c *gin.Context) {
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
+
+	relatives, err := h.services.Household.Relatives(c.Request.Context(), c.Param("communityId"), c.Param("memberId"))
+	if err != nil {
+		h.respondHouseholdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"relatives": relatives})
+}
+
+// UpdateMemberRelations define o cônjuge e o pai ou a mãe do membro
+func (h *Handler) UpdateMemberRelations(c *gin.Context) {
+	if _, ok := h.authorizeHouseholds(c); !ok {
+		return
+	}
+
+	var req MemberRelationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	member, err := h.services.Household.SetRelations(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), req.SpouseID, req.ParentID)
+	if err != nil {
+		h.respondHouseholdError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Parentesco atualizado com sucesso",
+		"member":  member,
 	})
 }
+
+// updateMemberFamily inclui, transfere ou retira o membro da família pelo serviço de
+// famílias e atualiza os campos de família e parentesco do membro carregado
+func (h *Handler) updateMemberFamily(ctx context.Context, member *domain.Member, familyID, role string) error {
+	var err error
+	switch {
+	case familyID != "" && role != "":
+		if member.FamilyID != nil && *member.FamilyID == familyID {
+			if member.FamilyRole == role {
+				return nil
+			}
+			_, err = h.services.Household.UpdateRole(ctx, member.CommunityID, familyID, member.ID, role)
+		} else {
+			_, err = h.services.Household.AddMember(ctx, member.CommunityID, familyID, member.ID, role)
+		}
+	case familyID == "" && member.FamilyID != nil:
+		err = h.services.Household.RemoveMember(ctx, member.CommunityID, *member.FamilyID, member.ID)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.repos.Member.FindByID(ctx, member.CommunityID, member.ID)
+	if err != nil {
+		return err
+	}
+	if updated != nil {
+		member.FamilyID = updated.FamilyID
+		member.FamilyRole = updated.FamilyRole
+		member.SpouseID = updated.SpouseID
+		member.ParentID = updated.ParentID
+	}
+	return nil
+}
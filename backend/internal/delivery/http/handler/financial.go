@@ -36,6 +36,7 @@ type AddExpenseRequest struct {
 	CategoryID  string    `json:"category_id" binding:"required,uuid"`
 	SupplierID  *string   `json:"supplier_id" binding:"omitempty,uuid"`
 	EventID     *string   `json:"event_id" binding:"omitempty,uuid"`
+	GroupID     *string   `json:"group_id" binding:"omitempty,uuid"`
 	Amount      float64   `json:"amount" binding:"required,gt=0"`
 	Date        time.Time `json:"date" binding:"required"`
 	Description string    `json:"description"`
@@ -47,6 +48,7 @@ type AddExpenseRequest struct {
 type AddRevenueRequest struct {
 	CategoryID  string    `json:"category_id" binding:"required,uuid"`
 	EventID     *string   `json:"event_id" binding:"omitempty,uuid"`
+	GroupID     *string   `json:"group_id" binding:"omitempty,uuid"`
 	Amount      float64   `json:"amount" binding:"required,gt=0"`
 	Date        time.Time `json:"date" binding:"required"`
 	Description string    `json:"description"`
@@ -84,6 +86,7 @@ type UpdateExpenseRequest struct {
 	CategoryID  string    `json:"category_id" binding:"required,uuid"`
 	SupplierID  *string   `json:"supplier_id" binding:"omitempty,uuid"`
 	EventID     *string   `json:"event_id" binding:"omitempty,uuid"`
+	GroupID     *string   `json:"group_id" binding:"omitempty,uuid"`
 	Amount      float64   `json:"amount" binding:"required,gt=0"`
 	Date        time.Time `json:"date" binding:"required"`
 	Description string    `json:"description"`
@@ -95,6 +98,7 @@ type UpdateExpenseRequest struct {
 type UpdateRevenueRequest struct {
 	CategoryID  string    `json:"category_id" binding:"required,uuid"`
 	EventID     *string   `json:"event_id" binding:"omitempty,uuid"`
+	GroupID     *string   `json:"group_id" binding:"omitempty,uuid"`
 	Amount      float64   `json:"amount" binding:"required,gt=0"`
 	Date        time.Time `json:"date" binding:"required"`
 	Description string    `json:"description"`
@@ -342,28 +346,53 @@ func (h *Handler) AddExpense(c *gin.Context) {
 		}
 	}
 
+	// Verifica se o ministério existe, se fornecido
+	if req.GroupID != nil {
+		group, err := h.repos.Group.FindByID(context.Background(), communityID, *req.GroupID)
+		if err != nil {
+			h.logger.Error("erro ao buscar ministério", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+			return
+		}
+		if group == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ministério não encontrado"})
+			return
+		}
+	}
+
 	expense := &domain.Expense{
 		CommunityID: communityID,
 		UserID:      user.(*domain.User).ID,
 		CategoryID:  req.CategoryID,
 		SupplierID:  req.SupplierID,
 		EventID:     req.EventID,
+		GroupID:     req.GroupID,
 		Amount:      req.Amount,
 		Date:        req.Date,
 		Description: req.Description,
-		Status:      req.Status,
 		PaymentType: req.PaymentType,
 		DueDate:     req.DueDate,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
+	// Despesas sujeitas a aprovação são criadas aguardando aprovação
+	if err := h.services.ExpenseApproval.ApplyStatus(c.Request.Context(), community, expense, req.Status, user.(*domain.User).ID); err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
 	if err := h.repos.Expense.Create(context.Background(), expense); err != nil {
 		h.logger.Error("erro ao criar despesa", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar despesa"})
 		return
 	}
 
+	h.checkBudgetAlerts(expense)
+	if expense.Status == "requested" {
+		h.notifyExpenseApprovers(community, expense)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Despesa criada com sucesso",
 		"expense": expense,
@@ -453,11 +482,26 @@ func (h *Handler) AddRevenue(c *gin.Context) {
 		}
 	}
 
+	// Verifica se o ministério existe, se fornecido
+	if req.GroupID != nil {
+		group, err := h.repos.Group.FindByID(context.Background(), communityID, *req.GroupID)
+		if err != nil {
+			h.logger.Error("erro ao buscar ministério", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+			return
+		}
+		if group == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ministério não encontrado"})
+			return
+		}
+	}
+
 	revenue := &domain.Revenue{
 		CommunityID: communityID,
 		UserID:      user.(*domain.User).ID,
 		CategoryID:  req.CategoryID,
 		EventID:     req.EventID,
+		GroupID:     req.GroupID,
 		Amount:      req.Amount,
 		Date:        req.Date,
 		Description: req.Description,
@@ -536,7 +580,12 @@ func (h *Handler) GenerateFinancialReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.repos.FinancialReport.GenerateReport(context.Background(), communityID, user.(*domain.User).ID, req.StartDate, req.EndDate)
+	if req.EndDate.Before(req.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data final deve ser posterior à data inicial"})
+		return
+	}
+
+	report, err := h.repos.FinancialReport.GenerateReport(context.Background(), communityID, user.(*domain.User).ID, req.Type, req.StartDate, req.EndDate)
 	if err != nil {
 		h.logger.Error("erro ao gerar relatório", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório"})
@@ -572,6 +621,28 @@ func (h *Handler) ListFinancialReports(c *gin.Context) {
 	})
 }
 
+// GetFinancialReport retorna um relatório com o detalhamento por categoria, evento,
+// fornecedor e mês e a comparação com o período anterior
+func (h *Handler) GetFinancialReport(c *gin.Context) {
+	communityID := c.Param("communityId")
+	reportID := c.Param("id")
+
+	report, err := h.repos.FinancialReport.FindByID(context.Background(), communityID, reportID)
+	if err != nil {
+		h.logger.Error("erro ao buscar relatório", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relatório não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report": report,
+	})
+}
+
 // UpdateFinancialCategory atualiza uma categoria financeira
 func (h *Handler) UpdateFinancialCategory(c *gin.Context) {
 	user, exists := c.Get("user")
@@ -894,21 +965,37 @@ func (h *Handler) UpdateExpense(c *gin.Context) {
 		return
 	}
 
+	// Com o vencimento alterado, o lembrete de vencimento volta a ser enviado
+	if !expense.DueDate.Equal(req.DueDate) {
+		expense.ReminderSentAt = nil
+	}
+
 	expense.CategoryID = req.CategoryID
 	expense.SupplierID = req.SupplierID
 	expense.EventID = req.EventID
+	expense.GroupID = req.GroupID
 	expense.Amount = req.Amount
 	expense.Date = req.Date
 	expense.Description = req.Description
-	expense.Status = req.Status
 	expense.PaymentType = req.PaymentType
 	expense.DueDate = req.DueDate
 
+	// O pagamento de despesas sujeitas a aprovação exige a aprovação concluída
+	previousStatus := expense.Status
+	if err := h.services.ExpenseApproval.ApplyStatus(c.Request.Context(), community, expense, req.Status, user.(*domain.User).ID); err != nil {
+		h.respondExpenseApprovalError(c, err)
+		return
+	}
+
 	if err := h.repos.Expense.Update(context.Background(), expense); err != nil {
 		h.logger.Error("erro ao atualizar despesa", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar despesa"})
 		return
 	}
+	h.checkBudgetAlerts(expense)
+	if expense.Status == "requested" && previousStatus != "requested" {
+		h.notifyExpenseApprovers(community, expense)
+	}
 
 	c.JSON(http.StatusOK, expense)
 }
@@ -954,12 +1041,26 @@ func (h *Handler) DeleteExpense(c *gin.Context) {
 		return
 	}
 
+	receipts, err := h.repos.ExpenseApproval.ListReceipts(context.Background(), expenseID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comprovantes da despesa", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
 	if err := h.repos.Expense.Delete(context.Background(), communityID, expenseID); err != nil {
 		h.logger.Error("erro ao excluir despesa", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir despesa"})
 		return
 	}
 
+	// Remove os arquivos dos comprovantes da despesa excluída
+	for _, receipt := range receipts {
+		if err := h.services.Upload.DeleteFile(receipt.Path); err != nil {
+			h.logger.Error("erro ao excluir comprovante", zap.Error(err))
+		}
+	}
+
 	c.JSON(http.StatusNoContent, nil)
 }
 
@@ -1012,6 +1113,7 @@ func (h *Handler) UpdateRevenue(c *gin.Context) {
 
 	revenue.CategoryID = req.CategoryID
 	revenue.EventID = req.EventID
+	revenue.GroupID = req.GroupID
 	revenue.Amount = req.Amount
 	revenue.Date = req.Date
 	revenue.Description = req.Description
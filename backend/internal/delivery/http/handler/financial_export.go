@@ -0,0 +1,377 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/ofx"
+	"github.com/comunidade/backend/pkg/xlsx"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportWriter escreve as linhas de uma exportação no formato escolhido. As
+// exportações tabulares usam os valores; a exportação OFX usa a transação.
+type exportWriter interface {
+	Write(values []interface{}, transaction ofx.Transaction) error
+	Close() error
+}
+
+type csvExportWriter struct {
+	c    *gin.Context
+	w    *csv.Writer
+	rows int
+}
+
+func (w *csvExportWriter) Write(values []interface{}, _ ofx.Transaction) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatExportValue(value)
+	}
+	if err := w.w.Write(record); err != nil {
+		return err
+	}
+
+	// Envia os dados ao cliente periodicamente
+	w.rows++
+	if w.rows%200 == 0 {
+		w.w.Flush()
+		w.c.Writer.Flush()
+	}
+	return w.w.Error()
+}
+
+func (w *csvExportWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type xlsxExportWriter struct {
+	w *xlsx.Writer
+}
+
+func (w *xlsxExportWriter) Write(values []interface{}, _ ofx.Transaction) error {
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		if text, ok := value.(string); ok {
+			value = escapeFormula(text)
+		}
+		row[i] = value
+	}
+	return w.w.WriteRow(row...)
+}
+
+func (w *xlsxExportWriter) Close() error {
+	return w.w.Close()
+}
+
+type ofxExportWriter struct {
+	w *ofx.Writer
+}
+
+func (w *ofxExportWriter) Write(_ []interface{}, transaction ofx.Transaction) error {
+	return w.w.WriteTransaction(transaction)
+}
+
+func (w *ofxExportWriter) Close() error {
+	return w.w.Close()
+}
+
+// formatExportValue converte um valor para texto na exportação CSV
+func formatExportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case bool:
+		if v {
+			return "sim"
+		}
+		return "não"
+	case string:
+		return escapeFormula(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeFormula impede que textos informados por doadores e usuários (ex.:
+// "=HYPERLINK(...)") sejam interpretados como fórmula pela planilha, prefixando com
+// apóstrofo os textos que começam com =, +, -, @, tabulação ou retorno de carro
+func escapeFormula(text string) string {
+	if text == "" {
+		return text
+	}
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+	return text
+}
+
+// parseExportFilter lê o formato, o período (start_date e end_date no formato
+// AAAA-MM-DD) e o status da exportação
+func parseExportFilter(c *gin.Context, statuses ...string) (string, *repository.ExportFilter, bool) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" && format != "ofx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido. Use csv, xlsx ou ofx"})
+		return "", nil, false
+	}
+
+	filter := &repository.ExportFilter{}
+	if value := c.Query("start_date"); value != "" {
+		startDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida. Use o formato AAAA-MM-DD"})
+			return "", nil, false
+		}
+		filter.StartDate = &startDate
+	}
+	if value := c.Query("end_date"); value != "" {
+		endDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida. Use o formato AAAA-MM-DD"})
+			return "", nil, false
+		}
+		// Inclui o dia inteiro da data final
+		endDate = endDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		filter.EndDate = &endDate
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data final deve ser posterior à data inicial"})
+		return "", nil, false
+	}
+
+	if status := c.Query("status"); status != "" {
+		valid := false
+		for _, s := range statuses {
+			if s == status {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+			return "", nil, false
+		}
+		filter.Status = status
+	}
+
+	return format, filter, true
+}
+
+// authorizeFinancialExport verifica se a comunidade existe e se o usuário pode exportar os dados
+func (h *Handler) authorizeFinancialExport(c *gin.Context) (*domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para exportar dados financeiros"})
+		return nil, false
+	}
+
+	return community, true
+}
+
+// newExportWriter define os cabeçalhos da resposta e inicia a exportação no formato escolhido
+func newExportWriter(c *gin.Context, community *domain.Community, format, name string, header []string, filter *repository.ExportFilter) (exportWriter, error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch format {
+	case "xlsx":
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Status(http.StatusOK)
+		w, err := xlsx.NewWriter(c.Writer, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.WriteHeader(header...); err != nil {
+			return nil, err
+		}
+		return &xlsxExportWriter{w: w}, nil
+	case "ofx":
+		c.Header("Content-Type", "application/x-ofx")
+		c.Status(http.StatusOK)
+
+		// O período do extrato é o período filtrado ou o período de um ano até hoje
+		end := time.Now()
+		if filter.EndDate != nil {
+			end = *filter.EndDate
+		}
+		start := end.AddDate(-1, 0, 0)
+		if filter.StartDate != nil {
+			start = *filter.StartDate
+		}
+		w, err := ofx.NewWriter(c.Writer, ofx.Account{AccountID: community.Slug}, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &ofxExportWriter{w: w}, nil
+	default:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		// BOM para que as planilhas reconheçam o arquivo como UTF-8
+		if _, err := c.Writer.Write([]byte("\xef\xbb\xbf")); err != nil {
+			return nil, err
+		}
+		w := csv.NewWriter(c.Writer)
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvExportWriter{c: c, w: w}, nil
+	}
+}
+
+// finishExport finaliza a exportação. Como os cabeçalhos já foram enviados, os erros
+// durante o streaming só podem ser registrados e a conexão é interrompida.
+func (h *Handler) finishExport(c *gin.Context, writer exportWriter, err error, name string) {
+	if err != nil {
+		h.logger.Error("erro ao exportar "+name, zap.Error(err))
+		c.Abort()
+		return
+	}
+	if err := writer.Close(); err != nil {
+		h.logger.Error("erro ao finalizar exportação de "+name, zap.Error(err))
+		c.Abort()
+	}
+}
+
+// ExportExpenses exporta as despesas em CSV, XLSX ou OFX
+func (h *Handler) ExportExpenses(c *gin.Context) {
+	community, ok := h.authorizeFinancialExport(c)
+	if !ok {
+		return
+	}
+
+	format, filter, ok := parseExportFilter(c, "requested", "approved", "rejected", "pending", "paid", "cancelled")
+	if !ok {
+		return
+	}
+
+	header := []string{"ID", "Data", "Vencimento", "Pago em", "Descrição", "Categoria", "Fornecedor", "CNPJ", "Evento", "Forma de pagamento", "Status", "Valor"}
+	writer, err := newExportWriter(c, community, format, "despesas", header, filter)
+	if err != nil {
+		h.logger.Error("erro ao iniciar exportação de despesas", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	err = h.repos.Expense.StreamForExport(context.Background(), community.ID, filter, func(row *repository.ExpenseExportRow) error {
+		name := row.SupplierName
+		if name == "" {
+			name = row.CategoryName
+		}
+		date := row.Date
+		if row.PaidAt != nil {
+			date = *row.PaidAt
+		}
+		return writer.Write(
+			[]interface{}{row.ID, row.Date, row.DueDate, row.PaidAt, row.Description, row.CategoryName, row.SupplierName, row.SupplierCNPJ, row.EventTitle, row.PaymentType, row.Status, row.Amount},
+			ofx.Transaction{ID: row.ID, Date: date, Amount: -row.Amount, Name: name, Memo: row.Description},
+		)
+	})
+	h.finishExport(c, writer, err, "despesas")
+}
+
+// ExportRevenues exporta as receitas em CSV, XLSX ou OFX
+func (h *Handler) ExportRevenues(c *gin.Context) {
+	community, ok := h.authorizeFinancialExport(c)
+	if !ok {
+		return
+	}
+
+	format, filter, ok := parseExportFilter(c, "pending", "received", "cancelled")
+	if !ok {
+		return
+	}
+
+	header := []string{"ID", "Data", "Recebido em", "Descrição", "Categoria", "Evento", "Forma de pagamento", "Status", "Valor"}
+	writer, err := newExportWriter(c, community, format, "receitas", header, filter)
+	if err != nil {
+		h.logger.Error("erro ao iniciar exportação de receitas", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	err = h.repos.Revenue.StreamForExport(context.Background(), community.ID, filter, func(row *repository.RevenueExportRow) error {
+		date := row.Date
+		if row.ReceivedAt != nil {
+			date = *row.ReceivedAt
+		}
+		return writer.Write(
+			[]interface{}{row.ID, row.Date, row.ReceivedAt, row.Description, row.CategoryName, row.EventTitle, row.PaymentType, row.Status, row.Amount},
+			ofx.Transaction{ID: row.ID, Date: date, Amount: row.Amount, Name: row.CategoryName, Memo: row.Description},
+		)
+	})
+	h.finishExport(c, writer, err, "receitas")
+}
+
+// ExportDonations exporta as doações em CSV, XLSX ou OFX
+func (h *Handler) ExportDonations(c *gin.Context) {
+	community, ok := h.authorizeFinancialExport(c)
+	if !ok {
+		return
+	}
+
+	format, filter, ok := parseExportFilter(c, "pending", "paid", "cancelled", "failed", "refunded", "disputed")
+	if !ok {
+		return
+	}
+
+	header := []string{"ID", "Vencimento", "Pago em", "Doador", "CPF", "Email", "Campanha", "Descrição", "Recorrente", "Forma de pagamento", "Status", "Valor"}
+	writer, err := newExportWriter(c, community, format, "doacoes", header, filter)
+	if err != nil {
+		h.logger.Error("erro ao iniciar exportação de doações", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	err = h.repos.Donation.StreamForExport(context.Background(), community.ID, filter, func(row *repository.DonationExportRow) error {
+		date := row.DueDate
+		if row.PaidAt != nil {
+			date = *row.PaidAt
+		}
+		memo := row.Description
+		if row.CampaignName != "" {
+			memo = row.CampaignName
+		}
+		return writer.Write(
+			[]interface{}{row.ID, row.DueDate, row.PaidAt, row.CustomerName, row.CustomerCPF, row.CustomerEmail, row.CampaignName, row.Description, row.Recurring, row.PaymentMethod, row.Status, row.Amount},
+			ofx.Transaction{ID: row.ID, Date: date, Amount: row.Amount, Name: row.CustomerName, Memo: memo},
+		)
+	})
+	h.finishExport(c, writer, err, "doações")
+}
@@ -0,0 +1,35 @@
+package handler
+
+import "testing"
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"Maria Souza", "Maria Souza"},
+		{"=HYPERLINK(\"http://x\",\"clique\")", "'=HYPERLINK(\"http://x\",\"clique\")"},
+		{"+55 11 99999-0000", "'+55 11 99999-0000"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"Oferta = dízimo", "Oferta = dízimo"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeFormula(tt.text); got != tt.want {
+			t.Errorf("escapeFormula(%q) = %q; esperado %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFormatExportValueKeepsNegativeAmounts(t *testing.T) {
+	if got := formatExportValue(-12.5); got != "-12.50" {
+		t.Errorf("formatExportValue(-12.5) = %q; esperado \"-12.50\"", got)
+	}
+	if got := formatExportValue("=1+1"); got != "'=1+1" {
+		t.Errorf("formatExportValue(\"=1+1\") = %q; esperado \"'=1+1\"", got)
+	}
+}
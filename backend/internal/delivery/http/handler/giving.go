@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type PublicDonationRequest struct {
+	CampaignID    string  `json:"campaign_id" binding:"required,uuid"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	PaymentMethod string  `json:"payment_method" binding:"required,oneof=pix boleto credit_card"`
+	Name          string  `json:"name" binding:"required,max=150"`
+	CPF           string  `json:"cpf" binding:"required"`
+	Email         string  `json:"email" binding:"required,email"`
+	Phone         string  `json:"phone" binding:"omitempty,max=20"`
+}
+
+// respondGivingError converte os erros da página pública de doações em respostas HTTP
+func (h *Handler) respondGivingError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrCommunityNotFound, service.ErrCampaignNotFound, service.ErrPublicDonationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidDonorCPF, service.ErrInvalidGivingAmount, service.ErrInvalidGivingMethod:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrCampaignNotActive:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case service.ErrGivingUnavailable:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case service.ErrGivingPaymentProcessing:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar doação pública", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// GetGivingPage retorna os dados da página pública de doações da comunidade
+func (h *Handler) GetGivingPage(c *gin.Context) {
+	page, err := h.services.Giving.Page(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		h.respondGivingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// CreatePublicDonation registra a doação de um visitante e gera a cobrança. Para Pix,
+// a resposta traz o QR Code e o código copia e cola.
+func (h *Handler) CreatePublicDonation(c *gin.Context) {
+	var req PublicDonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	donation, err := h.services.Giving.Donate(c.Request.Context(), c.Param("slug"), &service.PublicDonationInput{
+		CampaignID:    req.CampaignID,
+		Amount:        req.Amount,
+		PaymentMethod: req.PaymentMethod,
+		Name:          req.Name,
+		CPF:           req.CPF,
+		Email:         req.Email,
+		Phone:         req.Phone,
+	})
+	if err != nil {
+		h.respondGivingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Doação registrada com sucesso",
+		"donation": donation,
+	})
+}
+
+// GetPublicDonation retorna a situação de uma doação feita pela página pública
+func (h *Handler) GetPublicDonation(c *gin.Context) {
+	donationID := c.Param("donationId")
+	if _, err := uuid.Parse(donationID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": service.ErrPublicDonationNotFound.Error()})
+		return
+	}
+
+	donation, err := h.services.Giving.Donation(c.Request.Context(), c.Param("slug"), donationID)
+	if err != nil {
+		h.respondGivingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"donation": donation,
+	})
+}
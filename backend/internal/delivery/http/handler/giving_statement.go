@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SendGivingStatementsRequest struct {
+	Year      int      `json:"year" binding:"required,min=2000,max=2100"`
+	MemberIDs []string `json:"member_ids" binding:"omitempty,dive,uuid"`
+}
+
+// authorizeStatements verifica se a comunidade existe e se o usuário pode emitir comprovantes
+func (h *Handler) authorizeStatements(c *gin.Context) (*domain.User, *domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para emitir comprovantes"})
+		return nil, nil, false
+	}
+
+	return user.(*domain.User), community, true
+}
+
+// statementYear lê o ano do comprovante da query string (padrão: ano anterior)
+func statementYear(c *gin.Context) (int, bool) {
+	value := c.Query("year")
+	if value == "" {
+		return time.Now().Year() - 1, true
+	}
+
+	year, err := strconv.Atoi(value)
+	if err != nil || year < 2000 || year > 2100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ano inválido"})
+		return 0, false
+	}
+	return year, true
+}
+
+// ListGivingStatements lista os comprovantes anuais dos membros que contribuíram no ano
+func (h *Handler) ListGivingStatements(c *gin.Context) {
+	_, community, ok := h.authorizeStatements(c)
+	if !ok {
+		return
+	}
+
+	year, ok := statementYear(c)
+	if !ok {
+		return
+	}
+
+	statements, err := h.services.Statement.BuildStatements(context.Background(), community, year)
+	if err != nil {
+		h.logger.Error("erro ao gerar comprovantes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	var total float64
+	for _, statement := range statements {
+		total += statement.Total
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":       year,
+		"statements": statements,
+		"total":      total,
+	})
+}
+
+// GetGivingStatement retorna o comprovante anual de um membro em JSON, HTML ou PDF
+func (h *Handler) GetGivingStatement(c *gin.Context) {
+	_, community, ok := h.authorizeStatements(c)
+	if !ok {
+		return
+	}
+
+	year, ok := statementYear(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.services.Statement.BuildStatement(context.Background(), community, c.Param("memberId"), year)
+	if err != nil {
+		if err == service.ErrMemberNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Membro não encontrado"})
+			return
+		}
+		h.logger.Error("erro ao gerar comprovante", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, gin.H{
+			"statement": statement,
+		})
+	case "html":
+		content, err := h.services.Statement.RenderHTML(community, statement)
+		if err != nil {
+			h.logger.Error("erro ao gerar comprovante em HTML", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", content)
+	case "pdf":
+		content, err := h.services.Statement.RenderPDF(community, statement)
+		if err != nil {
+			h.logger.Error("erro ao gerar comprovante em PDF", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", service.StatementFilename(statement, "pdf")))
+		c.Data(http.StatusOK, "application/pdf", content)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido. Use json, html ou pdf"})
+	}
+}
+
+// SendGivingStatements envia por email os comprovantes anuais. O envio acontece em
+// segundo plano e pode ser acompanhado pelos destinatários da comunicação retornada.
+func (h *Handler) SendGivingStatements(c *gin.Context) {
+	user, community, ok := h.authorizeStatements(c)
+	if !ok {
+		return
+	}
+
+	var req SendGivingStatementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	communication, err := h.services.Statement.SendStatements(context.Background(), community, user.ID, req.Year, req.MemberIDs)
+	if err != nil {
+		if err == service.ErrNoStatements {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("erro ao enviar comprovantes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "Envio dos comprovantes registrado com sucesso",
+		"communication": communication,
+	})
+}
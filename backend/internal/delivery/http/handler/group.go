@@ -40,6 +40,7 @@ type CreateGroupRequest struct {
 	AllowSelfJoin       *bool `json:"allow_self_join"`
 	NotifyOnJoinRequest *bool `json:"notify_on_join_request"`
 	NotifyOnNewMember   *bool `json:"notify_on_new_member"`
+	IsClassroom         *bool `json:"is_classroom"`
 }
 
 type UpdateGroupRequest struct {
@@ -71,6 +72,7 @@ type UpdateGroupRequest struct {
 	AllowSelfJoin       *bool `json:"allow_self_join"`
 	NotifyOnJoinRequest *bool `json:"notify_on_join_request"`
 	NotifyOnNewMember   *bool `json:"notify_on_new_member"`
+	IsClassroom         *bool `json:"is_classroom"`
 }
 
 func (h *Handler) CreateGroup(c *gin.Context) {
@@ -140,6 +142,7 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 		AllowSelfJoin:       getBoolOrDefault(req.AllowSelfJoin, true),
 		NotifyOnJoinRequest: getBoolOrDefault(req.NotifyOnJoinRequest, true),
 		NotifyOnNewMember:   getBoolOrDefault(req.NotifyOnNewMember, true),
+		IsClassroom:         getBoolOrDefault(req.IsClassroom, false),
 	}
 
 	if err := h.repos.Group.Create(context.Background(), group); err != nil {
@@ -302,6 +305,7 @@ func (h *Handler) UpdateGroup(c *gin.Context) {
 	group.AllowSelfJoin = getBoolOrDefault(req.AllowSelfJoin, group.AllowSelfJoin)
 	group.NotifyOnJoinRequest = getBoolOrDefault(req.NotifyOnJoinRequest, group.NotifyOnJoinRequest)
 	group.NotifyOnNewMember = getBoolOrDefault(req.NotifyOnNewMember, group.NotifyOnNewMember)
+	group.IsClassroom = getBoolOrDefault(req.IsClassroom, group.IsClassroom)
 
 	group.UpdatedAt = time.Now()
 
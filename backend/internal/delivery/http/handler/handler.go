@@ -22,20 +22,71 @@ type Handler struct {
 }
 
 type Services struct {
-	Upload        *service.UploadService
-	Communication service.CommunicationService
-	CheckIn       service.CheckInService
-	Asaas         *service.AsaasService
-	Engagement    *service.EngagementService
+	Upload            *service.UploadService
+	Communication     service.CommunicationService
+	CheckIn           service.CheckInService
+	Asaas             *service.AsaasService
+	Payment           *service.PaymentService
+	Engagement        *service.EngagementService
+	Posting           *service.DonationPostingService
+	Contribution      *service.ContributionService
+	Statement         *service.GivingStatementService
+	Reconciliation    *service.ReconciliationService
+	Budget            *service.BudgetService
+	RecurringExpense  *service.RecurringExpenseService
+	ExpenseApproval   *service.ExpenseApprovalService
+	Campaign          *service.CampaignService
+	Giving            *service.GivingService
+	RecurringDonation *service.RecurringDonationService
+	MemberImport      *service.MemberImportService
+	MemberMerge       *service.MemberMergeService
+	MemberSegment     *service.MemberSegmentService
+	MemberPipeline    *service.MemberPipelineService
+	MemberProfile     *service.MemberProfileService
+	Membership        *service.MembershipService
+	Celebration       *service.CelebrationService
+	Household         *service.HouseholdService
+	KidsCheckIn       *service.KidsCheckInService
 }
 
 func NewHandler(r *gin.Engine, repos *repository.Repositories, logger *zap.Logger) {
+	communication := service.NewCommunicationService(repos, logger)
+	asaas := service.NewAsaasService(repos, logger)
+	gateways := []service.PaymentGateway{asaas, service.NewPixGateway(repos, logger)}
+	if os.Getenv("PAYMENT_FAKE_GATEWAY") == "true" {
+		// Provedor em memória para testar os fluxos de doação sem acessar o ASAAS
+		gateways = append(gateways, service.NewFakeGateway())
+	}
+	payments := service.NewPaymentService(repos, logger, gateways...)
+	campaigns := service.NewCampaignService(repos, logger)
+	pipeline := service.NewMemberPipelineService(repos, logger)
+	household := service.NewHouseholdService(repos, logger)
 	services := &Services{
-		Upload:        service.NewUploadService("./uploads"),
-		Communication: service.NewCommunicationService(repos, logger),
-		CheckIn:       service.NewCheckInService(repos.CheckIn, repos.Member, repos.Event),
-		Asaas:         service.NewAsaasService(repos, logger),
-		Engagement:    service.NewEngagementService(repos, logger),
+		Upload:            service.NewUploadService("./uploads"),
+		Communication:     communication,
+		CheckIn:           service.NewCheckInService(repos.CheckIn, repos.Member, repos.Event),
+		Asaas:             asaas,
+		Payment:           payments,
+		Engagement:        service.NewEngagementService(repos, logger),
+		Posting:           service.NewDonationPostingService(repos, logger),
+		Contribution:      service.NewContributionService(repos, logger),
+		Statement:         service.NewGivingStatementService(repos, logger, communication, "./uploads"),
+		Reconciliation:    service.NewReconciliationService(repos, logger),
+		Budget:            service.NewBudgetService(repos, logger, communication),
+		RecurringExpense:  service.NewRecurringExpenseService(repos, logger, communication),
+		ExpenseApproval:   service.NewExpenseApprovalService(repos, logger, communication),
+		Campaign:          campaigns,
+		Giving:            service.NewGivingService(repos, logger, payments, campaigns),
+		RecurringDonation: service.NewRecurringDonationService(repos, logger, payments),
+		MemberImport:      service.NewMemberImportService(repos, logger, "./private"),
+		MemberMerge:       service.NewMemberMergeService(repos, logger),
+		MemberSegment:     service.NewMemberSegmentService(repos, logger),
+		MemberPipeline:    pipeline,
+		MemberProfile:     service.NewMemberProfileService(repos, logger, "./uploads", household),
+		Membership:        service.NewMembershipService(repos, logger, "./uploads"),
+		Celebration:       service.NewCelebrationService(repos, logger, communication),
+		Household:         household,
+		KidsCheckIn:       service.NewKidsCheckInService(repos, logger),
 	}
 
 	h := &Handler{
@@ -168,10 +219,95 @@ type RouteHandler interface {
 	DeleteCommunityMember(c *gin.Context)
 	GetPublicCommunityData(c *gin.Context)
 
+	// Member import
+	DryRunMemberImport(c *gin.Context)
+	ImportMembers(c *gin.Context)
+	ListMemberImports(c *gin.Context)
+	GetMemberImport(c *gin.Context)
+	DownloadMemberImportErrors(c *gin.Context)
+
+	// Member merge
+	ListMemberDuplicates(c *gin.Context)
+	MergeMember(c *gin.Context)
+	ListMemberMerges(c *gin.Context)
+
+	// Member search and segments
+	SearchMembers(c *gin.Context)
+	AddMemberSegment(c *gin.Context)
+	ListMemberSegments(c *gin.Context)
+	GetMemberSegment(c *gin.Context)
+	UpdateMemberSegment(c *gin.Context)
+	DeleteMemberSegment(c *gin.Context)
+	ListMemberSegmentMembers(c *gin.Context)
+
+	// Member pipeline
+	ListPipelineStages(c *gin.Context)
+	AddPipelineStage(c *gin.Context)
+	UpdatePipelineStage(c *gin.Context)
+	DeletePipelineStage(c *gin.Context)
+	GetPipelineDashboard(c *gin.Context)
+	MoveMemberStage(c *gin.Context)
+	GetMemberStageHistory(c *gin.Context)
+	ListFollowUpTasks(c *gin.Context)
+	AddFollowUpTask(c *gin.Context)
+	GetFollowUpTask(c *gin.Context)
+	UpdateFollowUpTask(c *gin.Context)
+	CompleteFollowUpTask(c *gin.Context)
+	ListVisitorCheckIns(c *gin.Context)
+	ConfirmVisitorCheckIn(c *gin.Context)
+	DismissVisitorCheckIn(c *gin.Context)
+
+	// Member profile self-service
+	GetMemberProfileSettings(c *gin.Context)
+	UpdateMemberProfileSettings(c *gin.Context)
+	ListMemberProfileChanges(c *gin.Context)
+	GetMemberProfileChange(c *gin.Context)
+	ApproveMemberProfileChange(c *gin.Context)
+	RejectMemberProfileChange(c *gin.Context)
+	UpdateMyProfile(c *gin.Context)
+	UploadMyPhoto(c *gin.Context)
+	ListMyFamily(c *gin.Context)
+	AddMyFamilyMember(c *gin.Context)
+	UpdateMyFamilyMember(c *gin.Context)
+	RemoveMyFamilyMember(c *gin.Context)
+	ListMyProfileChanges(c *gin.Context)
+	CancelMyProfileChange(c *gin.Context)
+
+	// Membership history and letters
+	ListMembershipEvents(c *gin.Context)
+	AddMembershipEvent(c *gin.Context)
+	VoidMembershipEvent(c *gin.Context)
+	ListMemberLetters(c *gin.Context)
+	IssueMemberLetter(c *gin.Context)
+	DownloadMemberLetter(c *gin.Context)
+	GetLetterTemplate(c *gin.Context)
+	UpdateLetterTemplate(c *gin.Context)
+
+	// Celebrations
+	ListCelebrations(c *gin.Context)
+	GetCelebrationSettings(c *gin.Context)
+	UpdateCelebrationSettings(c *gin.Context)
+	SendCelebrationDigest(c *gin.Context)
+
+	// Households
+	GetFamilyGraph(c *gin.Context)
+	GetFamilyMailingList(c *gin.Context)
+	ListMemberRelatives(c *gin.Context)
+	UpdateMemberRelations(c *gin.Context)
+
+	// Kids check-in
+	ListKidsClassrooms(c *gin.Context)
+	ListKidsCheckIns(c *gin.Context)
+	CreateKidsCheckIn(c *gin.Context)
+	CheckoutKids(c *gin.Context)
+	PrintKidsLabels(c *gin.Context)
+
 	// Donations
 	AddAsaasConfig(c *gin.Context)
 	GetAsaasConfig(c *gin.Context)
 	UpdateAsaasConfig(c *gin.Context)
+	GetPaymentGateway(c *gin.Context)
+	UpdatePaymentGateway(c *gin.Context)
 	AddAsaasAccount(c *gin.Context)
 	ListAsaasAccounts(c *gin.Context)
 	GetAsaasAccount(c *gin.Context)
@@ -180,13 +316,36 @@ type RouteHandler interface {
 	RefreshAccount(c *gin.Context)
 	AddCampaign(c *gin.Context)
 	ListCampaigns(c *gin.Context)
+	GetCampaign(c *gin.Context)
+	UpdateCampaign(c *gin.Context)
+	CloseCampaign(c *gin.Context)
+	ListPublicCampaigns(c *gin.Context)
+	GetPublicCampaign(c *gin.Context)
+	GetGivingPage(c *gin.Context)
+	CreatePublicDonation(c *gin.Context)
+	GetPublicDonation(c *gin.Context)
 	AddDonation(c *gin.Context)
 	ListDonations(c *gin.Context)
+	RefundDonation(c *gin.Context)
 	AddRecurringDonation(c *gin.Context)
 	ListRecurringDonations(c *gin.Context)
+	GetRecurringDonation(c *gin.Context)
+	UpdateRecurringDonation(c *gin.Context)
+	PauseRecurringDonation(c *gin.Context)
+	ResumeRecurringDonation(c *gin.Context)
+	UpdateRecurringDonationCard(c *gin.Context)
+	CancelRecurringDonation(c *gin.Context)
+	ListMyRecurringDonations(c *gin.Context)
+	GetMyRecurringDonation(c *gin.Context)
+	UpdateMyRecurringDonation(c *gin.Context)
+	PauseMyRecurringDonation(c *gin.Context)
+	ResumeMyRecurringDonation(c *gin.Context)
+	UpdateMyRecurringDonationCard(c *gin.Context)
+	CancelMyRecurringDonation(c *gin.Context)
 
 	// Webhooks
 	HandleAsaasAccountStatusWebhook(c *gin.Context)
+	HandlePaymentWebhook(c *gin.Context)
 
 	// Engagement
 	GetMemberDashboard(c *gin.Context)
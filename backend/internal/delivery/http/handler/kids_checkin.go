@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type KidsCheckInChildRequest struct {
+	MemberID     string  `json:"member_id" binding:"required,uuid"`
+	ClassroomID  *string `json:"classroom_id" binding:"omitempty,uuid"`
+	Allergies    *string `json:"allergies"`
+	MedicalNotes *string `json:"medical_notes"`
+}
+
+type KidsCheckInRequest struct {
+	GuardianID    *string                   `json:"guardian_id" binding:"omitempty,uuid"`
+	GuardianName  string                    `json:"guardian_name" binding:"max=100"`
+	GuardianPhone string                    `json:"guardian_phone" binding:"max=20"`
+	Children      []KidsCheckInChildRequest `json:"children" binding:"required,min=1,dive"`
+}
+
+type KidsCheckoutRequest struct {
+	SecurityCode string   `json:"security_code" binding:"required"`
+	CheckInIDs   []string `json:"check_in_ids" binding:"omitempty,dive,uuid"`
+	PickedUpBy   string   `json:"picked_up_by" binding:"max=100"`
+}
+
+// authorizeKidsCheckIn verifica se o usuário pode operar o check-in infantil da comunidade
+func (h *Handler) authorizeKidsCheckIn(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores operam o check-in infantil
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para operar o check-in infantil desta comunidade"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondKidsCheckInError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCommunityNotFound),
+		errors.Is(err, service.ErrEventNotFound),
+		errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrClassroomNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrChildAlreadyCheckedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidSecurityCode):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidKidsCheckIn),
+		errors.Is(err, service.ErrNoClassroomAvailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar check-in infantil", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// ListKidsClassrooms lista as salas do ministério infantil com a ocupação no evento
+func (h *Handler) ListKidsClassrooms(c *gin.Context) {
+	if _, ok := h.authorizeKidsCheckIn(c); !ok {
+		return
+	}
+
+	classrooms, err := h.services.KidsCheckIn.Classrooms(c.Request.Context(), c.Param("communityId"), c.Param("eventId"))
+	if err != nil {
+		h.respondKidsCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"classrooms": classrooms})
+}
+
+// ListKidsCheckIns lista as crianças do evento, filtrando por situação e sala
+func (h *Handler) ListKidsCheckIns(c *gin.Context) {
+	if _, ok := h.authorizeKidsCheckIn(c); !ok {
+		return
+	}
+
+	checkIns, err := h.services.KidsCheckIn.List(c.Request.Context(), c.Param("communityId"), c.Param("eventId"), c.Query("status"), c.Query("classroom_id"))
+	if err != nil {
+		h.respondKidsCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"check_ins": checkIns})
+}
+
+// CreateKidsCheckIn registra a entrada das crianças e retorna o código de segurança
+func (h *Handler) CreateKidsCheckIn(c *gin.Context) {
+	if _, ok := h.authorizeKidsCheckIn(c); !ok {
+		return
+	}
+
+	var req KidsCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	input := &service.KidsCheckInInput{
+		GuardianID:    req.GuardianID,
+		GuardianName:  req.GuardianName,
+		GuardianPhone: req.GuardianPhone,
+	}
+	for _, child := range req.Children {
+		input.Children = append(input.Children, service.KidsCheckInChild{
+			MemberID:     child.MemberID,
+			ClassroomID:  child.ClassroomID,
+			Allergies:    child.Allergies,
+			MedicalNotes: child.MedicalNotes,
+		})
+	}
+
+	result, err := h.services.KidsCheckIn.CheckIn(c.Request.Context(), c.Param("communityId"), c.Param("eventId"), input)
+	if err != nil {
+		h.respondKidsCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Check-in realizado com sucesso",
+		"security_code": result.SecurityCode,
+		"guardian_name": result.GuardianName,
+		"check_ins":     result.CheckIns,
+	})
+}
+
+// CheckoutKids registra a retirada das crianças mediante o código de segurança
+func (h *Handler) CheckoutKids(c *gin.Context) {
+	user, ok := h.authorizeKidsCheckIn(c)
+	if !ok {
+		return
+	}
+
+	var req KidsCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	checkIns, err := h.services.KidsCheckIn.Checkout(c.Request.Context(), c.Param("communityId"), c.Param("eventId"), req.SecurityCode, req.CheckInIDs, req.PickedUpBy, user.ID)
+	if err != nil {
+		h.respondKidsCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Retirada registrada com sucesso",
+		"check_ins": checkIns,
+	})
+}
+
+// PrintKidsLabels gera as etiquetas da criança e do responsável em ZPL (impressoras
+// térmicas) ou PDF
+func (h *Handler) PrintKidsLabels(c *gin.Context) {
+	if _, ok := h.authorizeKidsCheckIn(c); !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "zpl")
+	if format != "zpl" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido. Use zpl ou pdf"})
+		return
+	}
+
+	labels, err := h.services.KidsCheckIn.Labels(c.Request.Context(), c.Param("communityId"), c.Param("eventId"), c.Query("code"))
+	if err != nil {
+		h.respondKidsCheckInError(c, err)
+		return
+	}
+
+	if format == "pdf" {
+		content, err := h.services.KidsCheckIn.RenderLabelsPDF(labels)
+		if err != nil {
+			h.logger.Error("erro ao gerar etiquetas em PDF", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", service.LabelsFilename(labels, "pdf")))
+		c.Data(http.StatusOK, "application/pdf", content)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", service.LabelsFilename(labels, "zpl")))
+	c.Data(http.StatusOK, "application/zpl; charset=utf-8", h.services.KidsCheckIn.RenderLabelsZPL(labels))
+}
@@ -40,6 +40,8 @@ type AddMemberRequest struct {
 	Notes            string    `json:"notes"`
 	EmergencyContact string    `json:"emergency_contact"`
 	EmergencyPhone   string    `json:"emergency_phone"`
+	Allergies        string    `json:"allergies"`
+	MedicalNotes     string    `json:"medical_notes"`
 
 	// Campos de ministério
 	Ministry          string    `json:"ministry"`
@@ -50,7 +52,8 @@ type AddMemberRequest struct {
 	Interests         []string  `json:"interests"`
 
 	// Campos de família
-	FamilyRole string `json:"family_role"`
+	FamilyRole  string    `json:"family_role"`
+	WeddingDate time.Time `json:"wedding_date"`
 
 	// Campos de batismo e membresia
 	BaptismDate     time.Time `json:"baptism_date"`
@@ -93,6 +96,8 @@ type UpdateMemberRequest struct {
 	Notes            string    `json:"notes"`
 	EmergencyContact string    `json:"emergency_contact" binding:"omitempty"`
 	EmergencyPhone   string    `json:"emergency_phone" binding:"omitempty"`
+	Allergies        string    `json:"allergies"`
+	MedicalNotes     string    `json:"medical_notes"`
 
 	// Campos de ministério
 	Ministry          string    `json:"ministry"`
@@ -103,8 +108,9 @@ type UpdateMemberRequest struct {
 	Interests         []string  `json:"interests"`
 
 	// Campos de família
-	FamilyID   string `json:"family_id"`
-	FamilyRole string `json:"family_role"`
+	FamilyID    string    `json:"family_id"`
+	FamilyRole  string    `json:"family_role"`
+	WeddingDate time.Time `json:"wedding_date"`
 
 	// Campos de batismo e membresia
 	BaptismDate     time.Time `json:"baptism_date"`
@@ -211,6 +217,8 @@ func (h *Handler) AddMember(c *gin.Context) {
 		Notes:            req.Notes,
 		EmergencyContact: req.EmergencyContact,
 		EmergencyPhone:   req.EmergencyPhone,
+		Allergies:        req.Allergies,
+		MedicalNotes:     req.MedicalNotes,
 
 		// Campos de ministério
 		Ministry:          req.Ministry,
@@ -243,6 +251,9 @@ func (h *Handler) AddMember(c *gin.Context) {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if !req.WeddingDate.IsZero() {
+		member.WeddingDate = &req.WeddingDate
+	}
 
 	h.logger.Debug("dados do membro",
 		zap.Any("member", member))
@@ -400,6 +411,8 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 		return
 	}
 
+	previousType, previousStatus := member.Type, member.Status
+
 	// Atualiza o membro
 	member.Name = req.Name
 	member.Email = req.Email
@@ -428,6 +441,8 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 	member.Notes = req.Notes
 	member.EmergencyContact = req.EmergencyContact
 	member.EmergencyPhone = req.EmergencyPhone
+	member.Allergies = req.Allergies
+	member.MedicalNotes = req.MedicalNotes
 
 	// Campos de ministério
 	member.Ministry = req.Ministry
@@ -439,43 +454,15 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 	member.Skills = req.Skills
 	member.Interests = req.Interests
 
-	// Campos de família
-	if req.FamilyID != "" && req.FamilyRole != "" {
-		// Se o membro já está em uma família, remove ele primeiro
-		if member.FamilyID != nil && *member.FamilyID != "" {
-			if err := h.repos.Family.RemoveMember(context.Background(), *member.FamilyID, member.ID); err != nil {
-				h.logger.Error("erro ao remover membro da família anterior", zap.Error(err))
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-				return
-			}
-		}
-
-		// Adiciona o membro à nova família
-		familyMember := &domain.FamilyMember{
-			FamilyID: req.FamilyID,
-			MemberID: member.ID,
-			Role:     req.FamilyRole,
-		}
-
-		if err := h.repos.Family.AddMember(context.Background(), familyMember); err != nil {
-			h.logger.Error("erro ao adicionar membro à família", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-			return
-		}
-
-		newFamilyID := req.FamilyID
-		member.FamilyID = &newFamilyID
-		member.FamilyRole = req.FamilyRole
-	} else if req.FamilyID == "" && member.FamilyID != nil {
-		// Se o membro está sendo removido de uma família
-		if err := h.repos.Family.RemoveMember(context.Background(), *member.FamilyID, member.ID); err != nil {
-			h.logger.Error("erro ao remover membro da família", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
-			return
-		}
+	// Campos de família: a inclusão, a troca e a saída da família passam pelo serviço
+	// de famílias, que mantém o vínculo e o parentesco sincronizados
+	if err := h.updateMemberFamily(c.Request.Context(), member, req.FamilyID, req.FamilyRole); err != nil {
+		h.respondHouseholdError(c, err)
+		return
+	}
 
-		member.FamilyID = nil
-		member.FamilyRole = ""
+	if !req.WeddingDate.IsZero() {
+		member.WeddingDate = &req.WeddingDate
 	}
 
 	// Campos de batismo e membresia
@@ -509,6 +496,11 @@ func (h *Handler) UpdateMember(c *gin.Context) {
 		return
 	}
 
+	// Registra a mudança de tipo ou situação no histórico do membro
+	if err := h.services.MemberPipeline.RecordMemberChange(c.Request.Context(), member, previousType, previousStatus, user.(*domain.User).ID); err != nil {
+		h.logger.Error("erro ao registrar histórico do membro", zap.Error(err))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Membro atualizado com sucesso",
 		"member":  member,
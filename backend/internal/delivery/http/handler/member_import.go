@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxMemberImportSize limita o tamanho da planilha de membros enviada
+const maxMemberImportSize = 10 << 20
+
+// authorizeMemberImport verifica se o usuário pode importar membros na comunidade
+func (h *Handler) authorizeMemberImport(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores podem importar membros
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para importar membros"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondMemberImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMemberImportNotFound), errors.Is(err, service.ErrImportErrorFileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedImportFile), errors.Is(err, service.ErrEmptyImportFile),
+		errors.Is(err, service.ErrImportTooLarge), errors.Is(err, service.ErrInvalidImportMapping),
+		errors.Is(err, service.ErrImportNameColumnMissing), errors.Is(err, service.ErrInvalidDuplicatePolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar importação de membros", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// readMemberImportUpload lê a planilha e as opções enviadas no formulário. O campo
+// mapping é opcional e relaciona o nome de cada coluna a um campo do membro.
+func (h *Handler) readMemberImportUpload(c *gin.Context) (string, []byte, service.MemberImportOptions, bool) {
+	var opts service.MemberImportOptions
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não enviado"})
+		return "", nil, opts, false
+	}
+	if file.Size > maxMemberImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo deve ter no máximo 10MB"})
+		return "", nil, opts, false
+	}
+
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mapeamento de colunas inválido"})
+			return "", nil, opts, false
+		}
+	}
+	opts.OnDuplicate = c.PostForm("on_duplicate")
+
+	src, err := file.Open()
+	if err != nil {
+		h.logger.Error("erro ao abrir arquivo", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar arquivo"})
+		return "", nil, opts, false
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxMemberImportSize))
+	if err != nil {
+		h.logger.Error("erro ao ler arquivo", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar arquivo"})
+		return "", nil, opts, false
+	}
+
+	return file.Filename, data, opts, true
+}
+
+// DryRunMemberImport valida a planilha e mostra o que seria importado, sem gravar nada
+func (h *Handler) DryRunMemberImport(c *gin.Context) {
+	if _, ok := h.authorizeMemberImport(c); !ok {
+		return
+	}
+
+	filename, data, opts, ok := h.readMemberImportUpload(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.services.MemberImport.DryRun(c.Request.Context(), c.Param("communityId"), filename, data, opts)
+	if err != nil {
+		h.respondMemberImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ImportMembers inicia a importação da planilha em segundo plano. O andamento é
+// consultado pela importação devolvida.
+func (h *Handler) ImportMembers(c *gin.Context) {
+	user, ok := h.authorizeMemberImport(c)
+	if !ok {
+		return
+	}
+
+	filename, data, opts, ok := h.readMemberImportUpload(c)
+	if !ok {
+		return
+	}
+
+	memberImport, err := h.services.MemberImport.Start(c.Request.Context(), c.Param("communityId"), user.ID, filename, data, opts)
+	if err != nil {
+		h.respondMemberImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Importação iniciada",
+		"import":  memberImport,
+	})
+}
+
+// ListMemberImports lista as importações de membros da comunidade
+func (h *Handler) ListMemberImports(c *gin.Context) {
+	if _, ok := h.authorizeMemberImport(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	imports, total, err := h.services.MemberImport.List(c.Request.Context(), c.Param("communityId"), filter)
+	if err != nil {
+		h.respondMemberImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imports": imports,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetMemberImport retorna o andamento e o resultado de uma importação
+func (h *Handler) GetMemberImport(c *gin.Context) {
+	if _, ok := h.authorizeMemberImport(c); !ok {
+		return
+	}
+
+	memberImport, err := h.services.MemberImport.Get(c.Request.Context(), c.Param("communityId"), c.Param("importId"))
+	if err != nil {
+		h.respondMemberImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": memberImport})
+}
+
+// DownloadMemberImportErrors baixa o CSV com as linhas que não foram importadas e o
+// motivo de cada uma
+func (h *Handler) DownloadMemberImportErrors(c *gin.Context) {
+	if _, ok := h.authorizeMemberImport(c); !ok {
+		return
+	}
+
+	path, err := h.services.MemberImport.ErrorFilePath(c.Request.Context(), c.Param("communityId"), c.Param("importId"))
+	if err != nil {
+		h.respondMemberImportError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, filepath.Base(path))
+}
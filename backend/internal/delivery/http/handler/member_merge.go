@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type MergeMemberRequest struct {
+	DuplicateID string `json:"duplicate_id" binding:"required,uuid"`
+}
+
+// authorizeMemberMerge verifica se o usuário pode unificar membros da comunidade
+func (h *Handler) authorizeMemberMerge(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores podem unificar membros
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para unificar membros"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+// ListMemberDuplicates lista os pares de membros que provavelmente são a mesma pessoa.
+// O parâmetro min_score define a pontuação mínima, de 1 a 100.
+func (h *Handler) ListMemberDuplicates(c *gin.Context) {
+	if _, ok := h.authorizeMemberMerge(c); !ok {
+		return
+	}
+
+	minScore := service.DefaultDuplicateScore
+	if value := c.Query("min_score"); value != "" {
+		score, err := strconv.Atoi(value)
+		if err != nil || score < 1 || score > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_score deve ser um número entre 1 e 100"})
+			return
+		}
+		minScore = score
+	}
+
+	duplicates, err := h.services.MemberMerge.FindDuplicates(c.Request.Context(), c.Param("communityId"), minScore)
+	if err != nil {
+		h.logger.Error("erro ao buscar membros duplicados", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"duplicates": duplicates,
+		"total":      len(duplicates),
+	})
+}
+
+// MergeMember unifica o membro duplicado informado no membro da rota, que é mantido
+func (h *Handler) MergeMember(c *gin.Context) {
+	user, ok := h.authorizeMemberMerge(c)
+	if !ok {
+		return
+	}
+
+	var req MergeMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	merge, err := h.services.MemberMerge.Merge(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), req.DuplicateID, user.ID)
+	if err != nil {
+		switch err {
+		case service.ErrMemberNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case service.ErrMergeSameMember, service.ErrMergeConflict:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("erro ao unificar membros", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		}
+		return
+	}
+
+	member, err := h.repos.Member.FindByID(c.Request.Context(), c.Param("communityId"), merge.SurvivorID)
+	if err != nil {
+		h.logger.Error("erro ao buscar membro", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Membros unificados com sucesso",
+		"member":  member,
+		"merge":   merge,
+	})
+}
+
+// ListMemberMerges lista o histórico de unificações de membros da comunidade
+func (h *Handler) ListMemberMerges(c *gin.Context) {
+	if _, ok := h.authorizeMemberMerge(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	merges, total, err := h.services.MemberMerge.ListMerges(c.Request.Context(), c.Param("communityId"), filter)
+	if err != nil {
+		h.logger.Error("erro ao listar unificações de membros", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merges": merges,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
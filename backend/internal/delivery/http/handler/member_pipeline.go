@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type PipelineStageRequest struct {
+	Name               string  `json:"name" binding:"required,max=100"`
+	Description        string  `json:"description"`
+	Position           int     `json:"position"`
+	MemberType         string  `json:"member_type" binding:"omitempty,oneof=regular visitor transferred"`
+	MemberStatus       string  `json:"member_status" binding:"omitempty,oneof=pending active inactive blocked"`
+	MaxDays            int     `json:"max_days" binding:"min=0"`
+	IsVisitorEntry     bool    `json:"is_visitor_entry"`
+	FollowUpTitle      string  `json:"follow_up_title" binding:"max=255"`
+	FollowUpDays       int     `json:"follow_up_days" binding:"min=0"`
+	FollowUpAssigneeID *string `json:"follow_up_assignee_id" binding:"omitempty,uuid"`
+}
+
+type MoveMemberStageRequest struct {
+	StageID string `json:"stage_id" binding:"required,uuid"`
+	Notes   string `json:"notes"`
+}
+
+type FollowUpTaskRequest struct {
+	MemberID    string    `json:"member_id" binding:"required,uuid"`
+	AssigneeID  *string   `json:"assignee_id" binding:"omitempty,uuid"`
+	Title       string    `json:"title" binding:"required,max=255"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date" binding:"required"`
+}
+
+type UpdateFollowUpTaskRequest struct {
+	AssigneeID  *string   `json:"assignee_id" binding:"omitempty,uuid"`
+	Title       string    `json:"title" binding:"required,max=255"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date" binding:"required"`
+}
+
+type ConfirmVisitorCheckInRequest struct {
+	MemberID *string `json:"member_id" binding:"omitempty,uuid"`
+}
+
+type CompleteFollowUpTaskRequest struct {
+	Status string `json:"status" binding:"omitempty,oneof=done cancelled"`
+	Result string `json:"result"`
+}
+
+// authorizeMemberPipeline verifica se o usuário pode acompanhar o caminho de integração
+func (h *Handler) authorizeMemberPipeline(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores conduzem o caminho de integração
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para acompanhar o caminho de integração"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondMemberPipelineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrStageNotFound),
+		errors.Is(err, service.ErrFollowUpTaskNotFound),
+		errors.Is(err, service.ErrVisitorCheckInNotFound),
+		errors.Is(err, service.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPipelineStage),
+		errors.Is(err, service.ErrInvalidFollowUpTask),
+		errors.Is(err, service.ErrInvalidTaskAssignee):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrStageInUse),
+		errors.Is(err, service.ErrFollowUpTaskCompleted),
+		errors.Is(err, service.ErrVisitorCheckInReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar caminho de integração", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+func (req *PipelineStageRequest) stage(communityID string) *domain.PipelineStage {
+	return &domain.PipelineStage{
+		CommunityID:        communityID,
+		Name:               req.Name,
+		Description:        req.Description,
+		Position:           req.Position,
+		MemberType:         req.MemberType,
+		MemberStatus:       req.MemberStatus,
+		MaxDays:            req.MaxDays,
+		IsVisitorEntry:     req.IsVisitorEntry,
+		FollowUpTitle:      req.FollowUpTitle,
+		FollowUpDays:       req.FollowUpDays,
+		FollowUpAssigneeID: req.FollowUpAssigneeID,
+	}
+}
+
+// ListPipelineStages lista as etapas do caminho de integração
+func (h *Handler) ListPipelineStages(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	stages, err := h.services.MemberPipeline.ListStages(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stages": stages})
+}
+
+// AddPipelineStage cria uma etapa no caminho de integração
+func (h *Handler) AddPipelineStage(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	var req PipelineStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	stage := req.stage(c.Param("communityId"))
+	if err := h.services.MemberPipeline.CreateStage(c.Request.Context(), stage); err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Etapa criada com sucesso",
+		"stage":   stage,
+	})
+}
+
+// UpdatePipelineStage altera a configuração da etapa
+func (h *Handler) UpdatePipelineStage(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	var req PipelineStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	communityID := c.Param("communityId")
+	stage, err := h.services.MemberPipeline.UpdateStage(c.Request.Context(), communityID, c.Param("stageId"), req.stage(communityID))
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Etapa atualizada com sucesso",
+		"stage":   stage,
+	})
+}
+
+// DeletePipelineStage exclui uma etapa sem membros
+func (h *Handler) DeletePipelineStage(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	if err := h.services.MemberPipeline.DeleteStage(c.Request.Context(), c.Param("communityId"), c.Param("stageId")); err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Etapa excluída com sucesso"})
+}
+
+// GetPipelineDashboard retorna os membros por etapa, os parados além do prazo e as tarefas
+func (h *Handler) GetPipelineDashboard(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	dashboard, err := h.services.MemberPipeline.Dashboard(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dashboard": dashboard})
+}
+
+// MoveMemberStage coloca o membro em uma etapa do caminho de integração
+func (h *Handler) MoveMemberStage(c *gin.Context) {
+	user, ok := h.authorizeMemberPipeline(c)
+	if !ok {
+		return
+	}
+
+	var req MoveMemberStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	member, err := h.services.MemberPipeline.MoveMember(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), req.StageID, &user.ID, req.Notes)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Membro movido de etapa com sucesso",
+		"member":  member,
+	})
+}
+
+// GetMemberStageHistory lista as mudanças de etapa, tipo e situação do membro
+func (h *Handler) GetMemberStageHistory(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	history, err := h.services.MemberPipeline.MemberHistory(c.Request.Context(), c.Param("communityId"), c.Param("memberId"))
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// ListFollowUpTasks lista as tarefas de acompanhamento, com filtros por situação,
+// responsável, membro, etapa e atraso
+func (h *Handler) ListFollowUpTasks(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	taskFilter := &repository.FollowUpTaskFilter{
+		Status:     c.Query("status"),
+		AssigneeID: c.Query("assignee_id"),
+		MemberID:   c.Query("member_id"),
+		StageID:    c.Query("stage_id"),
+		Overdue:    c.Query("overdue") == "true",
+	}
+	filter := repository.NewFilterFromQuery(c)
+	tasks, total, err := h.services.MemberPipeline.ListTasks(c.Request.Context(), c.Param("communityId"), taskFilter, filter)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": tasks,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// AddFollowUpTask cria uma tarefa de acompanhamento avulsa
+func (h *Handler) AddFollowUpTask(c *gin.Context) {
+	user, ok := h.authorizeMemberPipeline(c)
+	if !ok {
+		return
+	}
+
+	var req FollowUpTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	task := &domain.FollowUpTask{
+		CommunityID: c.Param("communityId"),
+		MemberID:    req.MemberID,
+		AssigneeID:  req.AssigneeID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		CreatedBy:   &user.ID,
+	}
+	if err := h.services.MemberPipeline.CreateTask(c.Request.Context(), task); err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tarefa criada com sucesso",
+		"task":    task,
+	})
+}
+
+// GetFollowUpTask retorna a tarefa de acompanhamento
+func (h *Handler) GetFollowUpTask(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	task, err := h.services.MemberPipeline.GetTask(c.Request.Context(), c.Param("communityId"), c.Param("taskId"))
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": task})
+}
+
+// UpdateFollowUpTask altera o título, o prazo e o responsável de uma tarefa pendente
+func (h *Handler) UpdateFollowUpTask(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	var req UpdateFollowUpTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	task, err := h.services.MemberPipeline.UpdateTask(c.Request.Context(), c.Param("communityId"), c.Param("taskId"), &domain.FollowUpTask{
+		AssigneeID:  req.AssigneeID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+	})
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tarefa atualizada com sucesso",
+		"task":    task,
+	})
+}
+
+// CompleteFollowUpTask conclui ou cancela uma tarefa pendente
+func (h *Handler) CompleteFollowUpTask(c *gin.Context) {
+	user, ok := h.authorizeMemberPipeline(c)
+	if !ok {
+		return
+	}
+
+	var req CompleteFollowUpTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if req.Status == "" {
+		req.Status = domain.FollowUpTaskDone
+	}
+
+	task, err := h.services.MemberPipeline.CompleteTask(c.Request.Context(), c.Param("communityId"), c.Param("taskId"), req.Status, req.Result, user.ID)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tarefa concluída com sucesso",
+		"task":    task,
+	})
+}
+
+// ListVisitorCheckIns lista os check-ins de visitantes dos eventos da comunidade. Por
+// padrão, somente os pendentes de confirmação.
+func (h *Handler) ListVisitorCheckIns(c *gin.Context) {
+	if _, ok := h.authorizeMemberPipeline(c); !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", domain.CheckInVisitorPending)
+	if status == "all" {
+		status = ""
+	}
+	checkIns, err := h.services.MemberPipeline.ListVisitorCheckIns(c.Request.Context(), c.Param("communityId"), status)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"check_ins": checkIns})
+}
+
+// ConfirmVisitorCheckIn confirma o check-in de um visitante, associando-o ao membro
+// informado ou ao cadastro encontrado ou criado a partir dos dados do check-in
+func (h *Handler) ConfirmVisitorCheckIn(c *gin.Context) {
+	user, ok := h.authorizeMemberPipeline(c)
+	if !ok {
+		return
+	}
+
+	checkInID, err := strconv.ParseUint(c.Param("checkInId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do check-in inválido"})
+		return
+	}
+
+	var req ConfirmVisitorCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	checkIn, err := h.services.MemberPipeline.ConfirmVisitor(c.Request.Context(), c.Param("communityId"), uint(checkInID), req.MemberID, user.ID)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Visitante confirmado com sucesso",
+		"check_in": checkIn,
+	})
+}
+
+// DismissVisitorCheckIn descarta o check-in de um visitante sem criar cadastro
+func (h *Handler) DismissVisitorCheckIn(c *gin.Context) {
+	user, ok := h.authorizeMemberPipeline(c)
+	if !ok {
+		return
+	}
+
+	checkInID, err := strconv.ParseUint(c.Param("checkInId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do check-in inválido"})
+		return
+	}
+
+	checkIn, err := h.services.MemberPipeline.DismissVisitor(c.Request.Context(), c.Param("communityId"), uint(checkInID), user.ID)
+	if err != nil {
+		h.respondMemberPipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Check-in descartado com sucesso",
+		"check_in": checkIn,
+	})
+}
@@ -0,0 +1,429 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type MemberProfileSettingsRequest struct {
+	ApprovalFields []string `json:"approval_fields" binding:"omitempty,dive,required"`
+}
+
+type FamilyMemberProfileRequest struct {
+	Name      string     `json:"name" binding:"max=255"`
+	BirthDate *time.Time `json:"birth_date"`
+	Gender    string     `json:"gender" binding:"omitempty,oneof=male female other not_specified"`
+	Role      string     `json:"role" binding:"omitempty,oneof=spouse child sibling parent grandparent grandchild uncle_aunt nephew_niece cousin other"`
+	Email     string     `json:"email" binding:"omitempty,email"`
+	Phone     string     `json:"phone" binding:"max=20"`
+}
+
+type ReviewProfileChangeRequest struct {
+	Notes string `json:"notes"`
+}
+
+// authorizeMemberProfiles verifica se o usuário pode revisar as alterações de cadastro
+func (h *Handler) authorizeMemberProfiles(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores revisam as alterações feitas no portal
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para revisar alterações de cadastro"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondMemberProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrProfileChangeNotFound),
+		errors.Is(err, service.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmptyProfileUpdate),
+		errors.Is(err, service.ErrInvalidProfileSettings),
+		errors.Is(err, service.ErrInvalidFamilyChange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFamilyMemberNotEditable):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfileEmailInUse),
+		errors.Is(err, service.ErrProfileChangeReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar alteração de cadastro", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// respondProfileUpdate informa se a alteração foi aplicada ou aguarda aprovação
+func respondProfileUpdate(c *gin.Context, member *domain.Member, change *domain.MemberProfileChange) {
+	if change != nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Alteração enviada para aprovação",
+			"member":  member,
+			"change":  change,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cadastro atualizado com sucesso",
+		"member":  member,
+	})
+}
+
+// UpdateMyProfile altera os dados de contato, endereço, preferências de comunicação,
+// habilidades e interesses do membro autenticado no portal
+func (h *Handler) UpdateMyProfile(c *gin.Context) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	var req domain.MemberProfileUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	req.Photo = nil
+
+	change, err := h.services.MemberProfile.UpdateProfile(c.Request.Context(), member, &req)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	respondProfileUpdate(c, member, change)
+}
+
+// UploadMyPhoto troca a foto do membro autenticado no portal
+func (h *Handler) UploadMyPhoto(c *gin.Context) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não encontrado", "details": err.Error()})
+		return
+	}
+	if !strings.HasPrefix(file.Header.Get("Content-Type"), "image/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo deve ser uma imagem"})
+		return
+	}
+	if file.Size > 5<<20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A foto deve ter no máximo 5MB"})
+		return
+	}
+
+	filename := fmt.Sprintf("members/%s/%s%s", member.CommunityID, uuid.New().String(), filepath.Ext(file.Filename))
+	if err := os.MkdirAll("uploads/members/"+member.CommunityID, 0755); err != nil {
+		h.logger.Error("erro ao criar diretório", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar arquivo"})
+		return
+	}
+	if err := c.SaveUploadedFile(file, "uploads/"+filename); err != nil {
+		h.logger.Error("erro ao salvar arquivo", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar arquivo"})
+		return
+	}
+
+	change, err := h.services.MemberProfile.UpdatePhoto(c.Request.Context(), member, filename)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	respondProfileUpdate(c, member, change)
+}
+
+// ListMyFamily lista os familiares do membro autenticado no portal
+func (h *Handler) ListMyFamily(c *gin.Context) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.services.MemberProfile.FamilyMembers(c.Request.Context(), member)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"family_members": members})
+}
+
+// AddMyFamilyMember cadastra um familiar do membro autenticado no portal
+func (h *Handler) AddMyFamilyMember(c *gin.Context) {
+	var req FamilyMemberProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	h.requestFamilyChange(c, domain.FamilyChangeAdd, nil, &req)
+}
+
+// UpdateMyFamilyMember altera os dados de um familiar sem acesso próprio ao portal
+func (h *Handler) UpdateMyFamilyMember(c *gin.Context) {
+	var req FamilyMemberProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	memberID := c.Param("memberId")
+	h.requestFamilyChange(c, domain.FamilyChangeUpdate, &memberID, &req)
+}
+
+// RemoveMyFamilyMember retira um familiar da família do membro autenticado no portal
+func (h *Handler) RemoveMyFamilyMember(c *gin.Context) {
+	memberID := c.Param("memberId")
+	h.requestFamilyChange(c, domain.FamilyChangeRemove, &memberID, &FamilyMemberProfileRequest{})
+}
+
+func (h *Handler) requestFamilyChange(c *gin.Context, action string, memberID *string, req *FamilyMemberProfileRequest) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	update := &domain.MemberFamilyUpdate{
+		Action:    action,
+		MemberID:  memberID,
+		Name:      req.Name,
+		BirthDate: req.BirthDate,
+		Gender:    req.Gender,
+		Role:      req.Role,
+		Email:     req.Email,
+		Phone:     req.Phone,
+	}
+	change, err := h.services.MemberProfile.RequestFamilyChange(c.Request.Context(), member, update)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	if change != nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Alteração enviada para aprovação",
+			"change":  change,
+		})
+		return
+	}
+
+	members, err := h.services.MemberProfile.FamilyMembers(c.Request.Context(), member)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Família atualizada com sucesso",
+		"family_members": members,
+	})
+}
+
+// ListMyProfileChanges lista as solicitações de alteração do membro autenticado no portal
+func (h *Handler) ListMyProfileChanges(c *gin.Context) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	changes, total, err := h.services.MemberProfile.ListChanges(c.Request.Context(), member.CommunityID, member.ID, c.Query("status"), filter)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profileChangesPage(changes, total, filter))
+}
+
+// CancelMyProfileChange cancela uma solicitação pendente do membro autenticado no portal
+func (h *Handler) CancelMyProfileChange(c *gin.Context) {
+	_, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.services.MemberProfile.CancelChange(c.Request.Context(), member, c.Param("changeId"))
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Solicitação cancelada com sucesso",
+		"change":  change,
+	})
+}
+
+func profileChangesPage(changes []*domain.MemberProfileChange, total int64, filter *repository.Filter) gin.H {
+	return gin.H{
+		"changes": changes,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	}
+}
+
+// GetMemberProfileSettings retorna os campos do portal que exigem aprovação
+func (h *Handler) GetMemberProfileSettings(c *gin.Context) {
+	if _, ok := h.authorizeMemberProfiles(c); !ok {
+		return
+	}
+
+	settings, err := h.services.MemberProfile.GetSettings(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+		"fields":   service.ProfileFields(),
+	})
+}
+
+// UpdateMemberProfileSettings define os campos do portal que exigem aprovação
+func (h *Handler) UpdateMemberProfileSettings(c *gin.Context) {
+	user, ok := h.authorizeMemberProfiles(c)
+	if !ok {
+		return
+	}
+
+	var req MemberProfileSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	settings, err := h.services.MemberProfile.UpdateSettings(c.Request.Context(), c.Param("communityId"), req.ApprovalFields, user.ID)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Configuração atualizada com sucesso",
+		"settings": settings,
+	})
+}
+
+// ListMemberProfileChanges lista as solicitações de alteração de cadastro da comunidade
+func (h *Handler) ListMemberProfileChanges(c *gin.Context) {
+	if _, ok := h.authorizeMemberProfiles(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	changes, total, err := h.services.MemberProfile.ListChanges(c.Request.Context(), c.Param("communityId"), c.Query("member_id"), c.Query("status"), filter)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profileChangesPage(changes, total, filter))
+}
+
+// GetMemberProfileChange retorna a solicitação com os valores atuais e os pedidos
+func (h *Handler) GetMemberProfileChange(c *gin.Context) {
+	if _, ok := h.authorizeMemberProfiles(c); !ok {
+		return
+	}
+
+	change, err := h.services.MemberProfile.GetChange(c.Request.Context(), c.Param("communityId"), c.Param("changeId"))
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"change": change})
+}
+
+// ApproveMemberProfileChange aplica a solicitação ao cadastro do membro
+func (h *Handler) ApproveMemberProfileChange(c *gin.Context) {
+	user, ok := h.authorizeMemberProfiles(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewProfileChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	change, err := h.services.MemberProfile.ApproveChange(c.Request.Context(), c.Param("communityId"), c.Param("changeId"), user.ID, req.Notes)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alteração aprovada com sucesso",
+		"change":  change,
+	})
+}
+
+// RejectMemberProfileChange recusa a solicitação sem alterar o cadastro
+func (h *Handler) RejectMemberProfileChange(c *gin.Context) {
+	user, ok := h.authorizeMemberProfiles(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewProfileChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	change, err := h.services.MemberProfile.RejectChange(c.Request.Context(), c.Param("communityId"), c.Param("changeId"), user.ID, req.Notes)
+	if err != nil {
+		h.respondMemberProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alteração recusada",
+		"change":  change,
+	})
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type MemberSegmentRequest struct {
+	Name        string              `json:"name" binding:"required,max=100"`
+	Description string              `json:"description"`
+	Criteria    domain.MemberSearch `json:"criteria"`
+}
+
+// authorizeMemberSegments verifica se o usuário pode consultar os membros da comunidade
+func (h *Handler) authorizeMemberSegments(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores podem buscar e segmentar membros
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para buscar membros"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondMemberSegmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSegmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidMemberSearch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSegmentInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar segmento de membros", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// memberSearchFilter lê a paginação e a ordenação da busca avançada. Aceita per_page,
+// como a listagem de membros, além de limit.
+func memberSearchFilter(c *gin.Context) *repository.Filter {
+	filter := repository.NewFilterFromQuery(c)
+	if perPage, err := strconv.Atoi(c.Query("per_page")); err == nil {
+		filter.PerPage = perPage
+	}
+	filter.OrderBy = c.Query("order_by")
+	filter.OrderDir = c.Query("order_dir")
+	return filter
+}
+
+func membersPage(members []*domain.Member, total int64, filter *repository.Filter) gin.H {
+	return gin.H{
+		"members": members,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	}
+}
+
+// SearchMembers busca os membros pelos critérios estruturados enviados no corpo
+func (h *Handler) SearchMembers(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	var search domain.MemberSearch
+	if err := c.ShouldBindJSON(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	filter := memberSearchFilter(c)
+	members, total, err := h.services.MemberSegment.Search(c.Request.Context(), c.Param("communityId"), &search, filter)
+	if err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, membersPage(members, total, filter))
+}
+
+// AddMemberSegment salva os critérios de busca como um segmento
+func (h *Handler) AddMemberSegment(c *gin.Context) {
+	user, ok := h.authorizeMemberSegments(c)
+	if !ok {
+		return
+	}
+
+	var req MemberSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	segment := &domain.MemberSegment{
+		CommunityID: c.Param("communityId"),
+		Name:        req.Name,
+		Description: req.Description,
+		Criteria:    req.Criteria,
+		CreatedBy:   user.ID,
+	}
+	if err := h.services.MemberSegment.CreateSegment(c.Request.Context(), segment); err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Segmento criado com sucesso",
+		"segment": segment,
+	})
+}
+
+// ListMemberSegments lista os segmentos salvos da comunidade
+func (h *Handler) ListMemberSegments(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	segments, total, err := h.services.MemberSegment.ListSegments(c.Request.Context(), c.Param("communityId"), filter)
+	if err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"segments": segments,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetMemberSegment retorna o segmento com a quantidade atual de membros
+func (h *Handler) GetMemberSegment(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	segment, err := h.services.MemberSegment.GetSegment(c.Request.Context(), c.Param("communityId"), c.Param("segmentId"))
+	if err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"segment": segment})
+}
+
+// UpdateMemberSegment altera o nome, a descrição e os critérios do segmento
+func (h *Handler) UpdateMemberSegment(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	var req MemberSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	segment, err := h.services.MemberSegment.UpdateSegment(c.Request.Context(), c.Param("communityId"), c.Param("segmentId"), &domain.MemberSegment{
+		Name:        req.Name,
+		Description: req.Description,
+		Criteria:    req.Criteria,
+	})
+	if err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Segmento atualizado com sucesso",
+		"segment": segment,
+	})
+}
+
+// DeleteMemberSegment exclui o segmento
+func (h *Handler) DeleteMemberSegment(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	if err := h.services.MemberSegment.DeleteSegment(c.Request.Context(), c.Param("communityId"), c.Param("segmentId")); err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Segmento excluído com sucesso"})
+}
+
+// ListMemberSegmentMembers lista os membros que atendem hoje aos critérios do segmento
+func (h *Handler) ListMemberSegmentMembers(c *gin.Context) {
+	if _, ok := h.authorizeMemberSegments(c); !ok {
+		return
+	}
+
+	filter := memberSearchFilter(c)
+	members, total, err := h.services.MemberSegment.SegmentMembers(c.Request.Context(), c.Param("communityId"), c.Param("segmentId"), filter)
+	if err != nil {
+		h.respondMemberSegmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, membersPage(members, total, filter))
+}
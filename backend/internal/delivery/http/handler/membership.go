@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type MembershipEventRequest struct {
+	Type     string     `json:"type" binding:"required,oneof=joined baptized transferred_in transferred_out disciplined deceased reinstated"`
+	Date     *time.Time `json:"date"`
+	Church   string     `json:"church" binding:"max=255"`
+	Location string     `json:"location" binding:"max=255"`
+	Notes    string     `json:"notes"`
+}
+
+type VoidMembershipEventRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type MemberLetterRequest struct {
+	Type              string `json:"type" binding:"required,oneof=transfer recommendation"`
+	DestinationChurch string `json:"destination_church" binding:"max=255"`
+	SignerName        string `json:"signer_name" binding:"max=255"`
+	SignerTitle       string `json:"signer_title" binding:"max=255"`
+	RegisterTransfer  bool   `json:"register_transfer"`
+}
+
+type LetterTemplateRequest struct {
+	Title       string `json:"title" binding:"required,max=255"`
+	Body        string `json:"body" binding:"required"`
+	SignerName  string `json:"signer_name" binding:"max=255"`
+	SignerTitle string `json:"signer_title" binding:"max=255"`
+}
+
+// authorizeMembership verifica se o usuário pode manter o histórico de membresia e
+// emitir cartas
+func (h *Handler) authorizeMembership(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores mantêm a secretaria de membros
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar a membresia desta comunidade"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+func (h *Handler) respondMembershipError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrCommunityNotFound),
+		errors.Is(err, service.ErrMembershipEventNotFound),
+		errors.Is(err, service.ErrMemberLetterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidMembershipEvent),
+		errors.Is(err, service.ErrInvalidLetterType),
+		errors.Is(err, service.ErrInvalidLetterTemplate),
+		errors.Is(err, service.ErrInvalidLetter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMembershipEventVoided),
+		errors.Is(err, service.ErrMemberNotInCommunion):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar membresia", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// ListMembershipEvents lista o histórico de membresia do membro
+func (h *Handler) ListMembershipEvents(c *gin.Context) {
+	if _, ok := h.authorizeMembership(c); !ok {
+		return
+	}
+
+	events, err := h.services.Membership.ListEvents(c.Request.Context(), c.Param("communityId"), c.Param("memberId"))
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// AddMembershipEvent registra um evento no histórico de membresia do membro
+func (h *Handler) AddMembershipEvent(c *gin.Context) {
+	user, ok := h.authorizeMembership(c)
+	if !ok {
+		return
+	}
+
+	var req MembershipEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	event := &domain.MembershipEvent{
+		CommunityID: c.Param("communityId"),
+		MemberID:    c.Param("memberId"),
+		Type:        req.Type,
+		Church:      req.Church,
+		Location:    req.Location,
+		Notes:       req.Notes,
+	}
+	if req.Date != nil {
+		event.Date = *req.Date
+	}
+
+	member, err := h.services.Membership.RecordEvent(c.Request.Context(), event, user.ID)
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Registro de membresia incluído com sucesso",
+		"event":   event,
+		"member":  member,
+	})
+}
+
+// VoidMembershipEvent anula um registro do histórico lançado por engano
+func (h *Handler) VoidMembershipEvent(c *gin.Context) {
+	user, ok := h.authorizeMembership(c)
+	if !ok {
+		return
+	}
+
+	var req VoidMembershipEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	event, member, err := h.services.Membership.VoidEvent(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), c.Param("eventId"), user.ID, req.Reason)
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Registro de membresia anulado com sucesso",
+		"event":   event,
+		"member":  member,
+	})
+}
+
+// ListMemberLetters lista as cartas emitidas para o membro
+func (h *Handler) ListMemberLetters(c *gin.Context) {
+	if _, ok := h.authorizeMembership(c); !ok {
+		return
+	}
+
+	letters, err := h.services.Membership.ListLetters(c.Request.Context(), c.Param("communityId"), c.Param("memberId"))
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"letters": letters})
+}
+
+// IssueMemberLetter emite uma carta de transferência ou de recomendação para o membro
+func (h *Handler) IssueMemberLetter(c *gin.Context) {
+	user, ok := h.authorizeMembership(c)
+	if !ok {
+		return
+	}
+
+	var req MemberLetterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	letter, member, err := h.services.Membership.IssueLetter(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), &service.LetterRequest{
+		Type:              req.Type,
+		DestinationChurch: req.DestinationChurch,
+		SignerName:        req.SignerName,
+		SignerTitle:       req.SignerTitle,
+		RegisterTransfer:  req.RegisterTransfer,
+	}, user.ID)
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Carta emitida com sucesso",
+		"letter":  letter,
+		"member":  member,
+	})
+}
+
+// DownloadMemberLetter retorna a carta em PDF com o timbre da comunidade
+func (h *Handler) DownloadMemberLetter(c *gin.Context) {
+	if _, ok := h.authorizeMembership(c); !ok {
+		return
+	}
+
+	letter, content, err := h.services.Membership.LetterPDF(c.Request.Context(), c.Param("communityId"), c.Param("memberId"), c.Param("letterId"))
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", service.LetterFilename(letter)))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// GetLetterTemplate retorna o modelo de carta da comunidade (ou o modelo padrão)
+func (h *Handler) GetLetterTemplate(c *gin.Context) {
+	if _, ok := h.authorizeMembership(c); !ok {
+		return
+	}
+
+	template, err := h.services.Membership.GetTemplate(c.Request.Context(), c.Param("communityId"), c.Param("type"))
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// UpdateLetterTemplate personaliza o modelo de carta da comunidade
+func (h *Handler) UpdateLetterTemplate(c *gin.Context) {
+	user, ok := h.authorizeMembership(c)
+	if !ok {
+		return
+	}
+
+	var req LetterTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	template, err := h.services.Membership.UpdateTemplate(c.Request.Context(), &domain.LetterTemplate{
+		CommunityID: c.Param("communityId"),
+		Type:        c.Param("type"),
+		Title:       req.Title,
+		Body:        req.Body,
+		SignerName:  req.SignerName,
+		SignerTitle: req.SignerTitle,
+	}, user.ID)
+	if err != nil {
+		h.respondMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Modelo de carta atualizado com sucesso",
+		"template": template,
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UpdatePaymentGatewayRequest struct {
+	Provider          string `json:"provider" binding:"required"`
+	PixKey            string `json:"pix_key" binding:"omitempty,max=77"`
+	MerchantName      string `json:"merchant_name" binding:"omitempty,max=25"`
+	MerchantCity      string `json:"merchant_city" binding:"omitempty,max=15"`
+	RegenerateWebhook bool   `json:"regenerate_webhook"`
+}
+
+// authorizePaymentGateway verifica se o usuário é o responsável pela comunidade
+func (h *Handler) authorizePaymentGateway(c *gin.Context) (*domain.Community, bool) {
+	community, err := h.repos.Community.FindByID(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != c.GetString("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar o provedor de pagamentos"})
+		return nil, false
+	}
+
+	return community, true
+}
+
+// GetPaymentGateway retorna o provedor de pagamentos da comunidade e os provedores disponíveis
+func (h *Handler) GetPaymentGateway(c *gin.Context) {
+	community, ok := h.authorizePaymentGateway(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.services.Payment.Settings(c.Request.Context(), community.ID)
+	if err != nil {
+		h.logger.Error("erro ao buscar provedor de pagamentos", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"gateway":   settings,
+		"providers": h.services.Payment.Providers(),
+	})
+}
+
+// UpdatePaymentGateway troca o provedor de pagamentos da comunidade. As doações já
+// criadas continuam no provedor em que foram geradas.
+func (h *Handler) UpdatePaymentGateway(c *gin.Context) {
+	community, ok := h.authorizePaymentGateway(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePaymentGatewayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	settings, webhookToken, err := h.services.Payment.UpdateSettings(c.Request.Context(), community.ID, &service.PaymentGatewaySettingsInput{
+		Provider:          req.Provider,
+		PixKey:            req.PixKey,
+		MerchantName:      req.MerchantName,
+		MerchantCity:      req.MerchantCity,
+		RegenerateWebhook: req.RegenerateWebhook,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrUnknownPaymentGateway, service.ErrInvalidPixSettings:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("erro ao atualizar provedor de pagamentos", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		}
+		return
+	}
+
+	response := gin.H{
+		"message": "Provedor de pagamentos atualizado com sucesso",
+		"gateway": settings,
+	}
+	// O token completo só é exibido quando gerado, para ser configurado no provedor
+	if webhookToken != "" {
+		response["webhook_token"] = webhookToken
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/comunidade/backend/pkg/cnab"
+	"github.com/comunidade/backend/pkg/ofx"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxStatementSize limita o tamanho do arquivo de extrato enviado
+const maxStatementSize = 10 << 20
+
+type MatchBankTransactionRequest struct {
+	ExpenseID string `json:"expense_id" binding:"omitempty,uuid"`
+	RevenueID string `json:"revenue_id" binding:"omitempty,uuid"`
+}
+
+type BankTransactionEntryRequest struct {
+	CategoryID  string  `json:"category_id" binding:"required,uuid"`
+	SupplierID  *string `json:"supplier_id" binding:"omitempty,uuid"`
+	EventID     *string `json:"event_id" binding:"omitempty,uuid"`
+	Description string  `json:"description"`
+	PaymentType string  `json:"payment_type"`
+}
+
+type IgnoreBankTransactionRequest struct {
+	Notes string `json:"notes"`
+}
+
+// authorizeReconciliation verifica se o usuário pode conciliar os extratos da comunidade
+func (h *Handler) authorizeReconciliation(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, false
+	}
+
+	communityID := c.Param("communityId")
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), communityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, false
+	}
+
+	// O criador da comunidade e os administradores podem conciliar extratos
+	adminUser := user.(*domain.User)
+	if community.CreatedBy != adminUser.ID {
+		if err := h.checkUserPermission(context.Background(), adminUser.ID, communityID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para conciliar extratos bancários"})
+			return nil, false
+		}
+	}
+
+	return adminUser, true
+}
+
+// respondReconciliationError converte os erros do serviço de conciliação em respostas HTTP
+func (h *Handler) respondReconciliationError(c *gin.Context, err error) {
+	// Erros de leitura do arquivo indicam a linha ou o campo inválido
+	if errors.Is(err, ofx.ErrInvalidFile) || errors.Is(err, cnab.ErrInvalidFile) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch err {
+	case service.ErrBankTransactionNotFound, service.ErrStatementImportNotFound, service.ErrEntryNotFound, service.ErrSupplierNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrUnsupportedStatement, service.ErrEmptyStatement, service.ErrEntryMismatch,
+		service.ErrInvalidCategory, service.ErrInvalidExpenseCategory:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrTransactionResolved, service.ErrTransactionNotResolved, service.ErrEntryNotPending,
+		service.ErrExpenseNotApproved:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar conciliação bancária", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// ImportBankStatement recebe um extrato OFX ou um retorno CNAB 240 e concilia seus lançamentos
+func (h *Handler) ImportBankStatement(c *gin.Context) {
+	user, ok := h.authorizeReconciliation(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não enviado"})
+		return
+	}
+	if file.Size > maxStatementSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O arquivo deve ter no máximo 10MB"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		h.logger.Error("erro ao abrir arquivo", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar arquivo"})
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxStatementSize))
+	if err != nil {
+		h.logger.Error("erro ao ler arquivo", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar arquivo"})
+		return
+	}
+
+	statement, err := h.services.Reconciliation.Import(c.Request.Context(), c.Param("communityId"), user.ID, file.Filename, data)
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Extrato importado com sucesso",
+		"import":  statement,
+	})
+}
+
+// ListBankStatementImports lista os extratos importados pela comunidade
+func (h *Handler) ListBankStatementImports(c *gin.Context) {
+	if _, ok := h.authorizeReconciliation(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	imports, total, err := h.repos.BankReconciliation.ListImports(c.Request.Context(), c.Param("communityId"), filter)
+	if err != nil {
+		h.logger.Error("erro ao listar importações de extrato", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar importações"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imports": imports,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetBankStatementImport retorna o resumo de uma importação
+func (h *Handler) GetBankStatementImport(c *gin.Context) {
+	if _, ok := h.authorizeReconciliation(c); !ok {
+		return
+	}
+
+	statement, err := h.services.Reconciliation.GetImport(c.Request.Context(), c.Param("communityId"), c.Param("importId"))
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": statement})
+}
+
+// ListBankTransactions lista os lançamentos importados. Por padrão retorna a fila
+// de conciliação (lançamentos pendentes); use status=all para listar todos.
+func (h *Handler) ListBankTransactions(c *gin.Context) {
+	if _, ok := h.authorizeReconciliation(c); !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	switch status := c.DefaultQuery("status", "pending"); status {
+	case "all":
+	case "pending", "matched", "ignored":
+		filter.AddCondition("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+		return
+	}
+	if importID := c.Query("import_id"); importID != "" {
+		filter.AddCondition("import_id = ?", importID)
+	}
+	switch c.Query("type") {
+	case "debit":
+		filter.AddCondition("amount < 0")
+	case "credit":
+		filter.AddCondition("amount > 0")
+	}
+
+	transactions, total, err := h.repos.BankReconciliation.ListTransactions(c.Request.Context(), c.Param("communityId"), filter)
+	if err != nil {
+		h.logger.Error("erro ao listar lançamentos bancários", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar lançamentos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetBankTransactionCandidates sugere despesas ou receitas pendentes para o lançamento
+func (h *Handler) GetBankTransactionCandidates(c *gin.Context) {
+	if _, ok := h.authorizeReconciliation(c); !ok {
+		return
+	}
+
+	candidates, err := h.services.Reconciliation.GetCandidates(c.Request.Context(), c.Param("communityId"), c.Param("transactionId"))
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, candidates)
+}
+
+// MatchBankTransaction concilia o lançamento com uma despesa ou receita existente
+func (h *Handler) MatchBankTransaction(c *gin.Context) {
+	user, ok := h.authorizeReconciliation(c)
+	if !ok {
+		return
+	}
+
+	var req MatchBankTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if (req.ExpenseID == "") == (req.RevenueID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a despesa ou a receita correspondente"})
+		return
+	}
+
+	transaction, err := h.services.Reconciliation.Match(c.Request.Context(), c.Param("communityId"), c.Param("transactionId"), user.ID, req.ExpenseID, req.RevenueID)
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Lançamento conciliado com sucesso",
+		"transaction": transaction,
+	})
+}
+
+// CreateBankTransactionEntry cria uma despesa ou receita a partir do lançamento
+func (h *Handler) CreateBankTransactionEntry(c *gin.Context) {
+	user, ok := h.authorizeReconciliation(c)
+	if !ok {
+		return
+	}
+
+	var req BankTransactionEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	transaction, err := h.services.Reconciliation.CreateEntry(c.Request.Context(), c.Param("communityId"), c.Param("transactionId"), user.ID, service.ReconciliationEntry{
+		CategoryID:  req.CategoryID,
+		SupplierID:  req.SupplierID,
+		EventID:     req.EventID,
+		Description: req.Description,
+		PaymentType: req.PaymentType,
+	})
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Lançamento registrado com sucesso",
+		"transaction": transaction,
+	})
+}
+
+// IgnoreBankTransaction retira o lançamento da fila de conciliação
+func (h *Handler) IgnoreBankTransaction(c *gin.Context) {
+	user, ok := h.authorizeReconciliation(c)
+	if !ok {
+		return
+	}
+
+	var req IgnoreBankTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	transaction, err := h.services.Reconciliation.Ignore(c.Request.Context(), c.Param("communityId"), c.Param("transactionId"), user.ID, req.Notes)
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Lançamento ignorado com sucesso",
+		"transaction": transaction,
+	})
+}
+
+// ReopenBankTransaction desfaz a conciliação e devolve o lançamento à fila
+func (h *Handler) ReopenBankTransaction(c *gin.Context) {
+	if _, ok := h.authorizeReconciliation(c); !ok {
+		return
+	}
+
+	transaction, err := h.services.Reconciliation.Reopen(c.Request.Context(), c.Param("communityId"), c.Param("transactionId"))
+	if err != nil {
+		h.respondReconciliationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Conciliação desfeita com sucesso",
+		"transaction": transaction,
+	})
+}
@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UpdateRecurringDonationRequest struct {
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+	DueDay *int     `json:"due_day" binding:"omitempty,min=1,max=31"`
+}
+
+type UpdateRecurringDonationCardRequest struct {
+	HolderName  string `json:"holder_name" binding:"required"`
+	Number      string `json:"number" binding:"required,min=13,max=19,numeric"`
+	ExpiryMonth string `json:"expiry_month" binding:"required,len=2,numeric"`
+	ExpiryYear  string `json:"expiry_year" binding:"required,len=4,numeric"`
+	CCV         string `json:"ccv" binding:"required,min=3,max=4,numeric"`
+}
+
+// recurringDonationLoader carrega a comunidade e a doação recorrente da requisição,
+// respondendo com o erro adequado quando não for possível
+type recurringDonationLoader func(c *gin.Context) (*domain.Community, *domain.RecurringDonation, bool)
+
+// respondRecurringDonationError converte os erros do serviço de doações recorrentes em respostas HTTP
+func (h *Handler) respondRecurringDonationError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrRecurringDonationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidRecurringAmount, service.ErrInvalidDueDay, service.ErrRecurringDonationNoChanges:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case service.ErrRecurringDonationCancelled, service.ErrRecurringDonationNotActive, service.ErrRecurringDonationNotPaused:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case service.ErrSubscriptionGateway:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar doação recorrente", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// adminRecurringDonation carrega a doação recorrente para as rotas administrativas
+func (h *Handler) adminRecurringDonation(c *gin.Context) (*domain.Community, *domain.RecurringDonation, bool) {
+	community, err := h.repos.Community.FindByID(c.Request.Context(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	recurring, err := h.services.RecurringDonation.GetRecurringDonation(c.Request.Context(), community.ID, c.Param("recurringId"))
+	if err != nil {
+		h.respondRecurringDonationError(c, err)
+		return nil, nil, false
+	}
+	return community, recurring, true
+}
+
+// memberRecurringDonation carrega a doação recorrente para o portal do membro. O membro
+// só acessa as próprias assinaturas.
+func (h *Handler) memberRecurringDonation(c *gin.Context) (*domain.Community, *domain.RecurringDonation, bool) {
+	community, member, ok := h.portalMember(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	recurring, err := h.services.RecurringDonation.GetRecurringDonation(c.Request.Context(), community.ID, c.Param("recurringId"))
+	if err != nil {
+		h.respondRecurringDonationError(c, err)
+		return nil, nil, false
+	}
+	if recurring.MemberID == nil || *recurring.MemberID != member.ID {
+		h.respondRecurringDonationError(c, service.ErrRecurringDonationNotFound)
+		return nil, nil, false
+	}
+	return community, recurring, true
+}
+
+// portalMember retorna o membro autenticado no portal e a comunidade da rota, que
+// precisa ser a mesma do token
+func (h *Handler) portalMember(c *gin.Context) (*domain.Community, *domain.Member, bool) {
+	value, exists := c.Get("member")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+	member := value.(*domain.Member)
+
+	if c.Param("communityId") != c.GetString("communityId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado a esta comunidade"})
+		return nil, nil, false
+	}
+
+	community, err := h.repos.Community.FindByID(c.Request.Context(), member.CommunityID)
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+	return community, member, true
+}
+
+// GetRecurringDonation retorna uma doação recorrente
+func (h *Handler) GetRecurringDonation(c *gin.Context) {
+	h.getRecurringDonation(c, h.adminRecurringDonation)
+}
+
+// UpdateRecurringDonation altera o valor ou o dia de vencimento de uma doação recorrente
+func (h *Handler) UpdateRecurringDonation(c *gin.Context) {
+	h.updateRecurringDonation(c, h.adminRecurringDonation)
+}
+
+// PauseRecurringDonation pausa as cobranças de uma doação recorrente
+func (h *Handler) PauseRecurringDonation(c *gin.Context) {
+	h.pauseRecurringDonation(c, h.adminRecurringDonation)
+}
+
+// ResumeRecurringDonation retoma as cobranças de uma doação recorrente pausada
+func (h *Handler) ResumeRecurringDonation(c *gin.Context) {
+	h.resumeRecurringDonation(c, h.adminRecurringDonation)
+}
+
+// UpdateRecurringDonationCard substitui o cartão de crédito de uma doação recorrente
+func (h *Handler) UpdateRecurringDonationCard(c *gin.Context) {
+	h.updateRecurringDonationCard(c, h.adminRecurringDonation)
+}
+
+// CancelRecurringDonation cancela uma doação recorrente
+func (h *Handler) CancelRecurringDonation(c *gin.Context) {
+	h.cancelRecurringDonation(c, h.adminRecurringDonation)
+}
+
+// ListMyRecurringDonations lista as doações recorrentes do membro autenticado no portal
+func (h *Handler) ListMyRecurringDonations(c *gin.Context) {
+	community, member, ok := h.portalMember(c)
+	if !ok {
+		return
+	}
+
+	recurringDonations, err := h.repos.RecurringDonation.ListByMember(c.Request.Context(), community.ID, member.ID)
+	if err != nil {
+		h.logger.Error("erro ao listar doações recorrentes do membro", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recurringDonations": recurringDonations,
+	})
+}
+
+// GetMyRecurringDonation retorna uma doação recorrente do membro autenticado
+func (h *Handler) GetMyRecurringDonation(c *gin.Context) {
+	h.getRecurringDonation(c, h.memberRecurringDonation)
+}
+
+// UpdateMyRecurringDonation altera o valor ou o dia de vencimento da doação do membro
+func (h *Handler) UpdateMyRecurringDonation(c *gin.Context) {
+	h.updateRecurringDonation(c, h.memberRecurringDonation)
+}
+
+// PauseMyRecurringDonation pausa a doação recorrente do membro
+func (h *Handler) PauseMyRecurringDonation(c *gin.Context) {
+	h.pauseRecurringDonation(c, h.memberRecurringDonation)
+}
+
+// ResumeMyRecurringDonation retoma a doação recorrente do membro
+func (h *Handler) ResumeMyRecurringDonation(c *gin.Context) {
+	h.resumeRecurringDonation(c, h.memberRecurringDonation)
+}
+
+// UpdateMyRecurringDonationCard substitui o cartão da doação recorrente do membro
+func (h *Handler) UpdateMyRecurringDonationCard(c *gin.Context) {
+	h.updateRecurringDonationCard(c, h.memberRecurringDonation)
+}
+
+// CancelMyRecurringDonation cancela a doação recorrente do membro
+func (h *Handler) CancelMyRecurringDonation(c *gin.Context) {
+	h.cancelRecurringDonation(c, h.memberRecurringDonation)
+}
+
+func (h *Handler) getRecurringDonation(c *gin.Context, load recurringDonationLoader) {
+	_, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recurringDonation": recurring,
+	})
+}
+
+func (h *Handler) updateRecurringDonation(c *gin.Context, load recurringDonationLoader) {
+	var req UpdateRecurringDonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	community, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	changes := &service.RecurringDonationChanges{Amount: req.Amount, DueDay: req.DueDay}
+	if err := h.services.RecurringDonation.Update(c.Request.Context(), community, recurring, changes); err != nil {
+		h.respondRecurringDonationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Doação recorrente atualizada com sucesso",
+		"recurringDonation": recurring,
+	})
+}
+
+func (h *Handler) pauseRecurringDonation(c *gin.Context, load recurringDonationLoader) {
+	_, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.RecurringDonation.Pause(c.Request.Context(), recurring); err != nil {
+		h.respondRecurringDonationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Doação recorrente pausada com sucesso",
+		"recurringDonation": recurring,
+	})
+}
+
+func (h *Handler) resumeRecurringDonation(c *gin.Context, load recurringDonationLoader) {
+	community, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.RecurringDonation.Resume(c.Request.Context(), community, recurring); err != nil {
+		h.respondRecurringDonationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Doação recorrente retomada com sucesso",
+		"recurringDonation": recurring,
+	})
+}
+
+func (h *Handler) updateRecurringDonationCard(c *gin.Context, load recurringDonationLoader) {
+	var req UpdateRecurringDonationCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	_, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	card := &service.PaymentCard{
+		HolderName:  req.HolderName,
+		Number:      req.Number,
+		ExpiryMonth: req.ExpiryMonth,
+		ExpiryYear:  req.ExpiryYear,
+		CVV:         req.CCV,
+	}
+	// O titular do cartão é o doador cadastrado na assinatura
+	holder := &service.PaymentCardHolder{
+		Name:     recurring.CustomerName,
+		CPF:      recurring.CustomerCPF,
+		Email:    recurring.CustomerEmail,
+		Phone:    recurring.CustomerPhone,
+		Street:   recurring.BillingAddress.Street,
+		Number:   recurring.BillingAddress.Number,
+		District: recurring.BillingAddress.District,
+		City:     recurring.BillingAddress.City,
+		State:    recurring.BillingAddress.State,
+		ZipCode:  recurring.BillingAddress.ZipCode,
+	}
+
+	if err := h.services.RecurringDonation.UpdateCard(c.Request.Context(), recurring, card, holder, c.ClientIP()); err != nil {
+		h.respondRecurringDonationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Cartão atualizado com sucesso",
+		"recurringDonation": recurring,
+	})
+}
+
+func (h *Handler) cancelRecurringDonation(c *gin.Context, load recurringDonationLoader) {
+	_, recurring, ok := load(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.RecurringDonation.Cancel(c.Request.Context(), recurring); err != nil {
+		h.respondRecurringDonationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Doação recorrente cancelada com sucesso",
+		"recurringDonation": recurring,
+	})
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RecurringExpenseRequest struct {
+	CategoryID   string  `json:"category_id" binding:"required,uuid"`
+	SupplierID   *string `json:"supplier_id" binding:"omitempty,uuid"`
+	GroupID      *string `json:"group_id" binding:"omitempty,uuid"`
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	Description  string  `json:"description" binding:"required"`
+	PaymentType  string  `json:"payment_type"`
+	Frequency    string  `json:"frequency" binding:"required,oneof=weekly monthly yearly"`
+	Interval     int     `json:"interval" binding:"omitempty,min=1,max=12"`
+	StartDate    string  `json:"start_date" binding:"required"` // Formato: YYYY-MM-DD
+	EndDate      string  `json:"end_date"`                      // Formato: YYYY-MM-DD
+	LeadDays     *int    `json:"lead_days" binding:"omitempty,min=0,max=366"`
+	ReminderDays *int    `json:"reminder_days" binding:"omitempty,min=0,max=30"`
+	NotifyEmails string  `json:"notify_emails"`
+}
+
+// UpdateRecurringExpenseRequest não permite alterar a frequência, o intervalo e a
+// data inicial, que definem as ocorrências já geradas
+type UpdateRecurringExpenseRequest struct {
+	CategoryID   string  `json:"category_id" binding:"required,uuid"`
+	SupplierID   *string `json:"supplier_id" binding:"omitempty,uuid"`
+	GroupID      *string `json:"group_id" binding:"omitempty,uuid"`
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	Description  string  `json:"description" binding:"required"`
+	PaymentType  string  `json:"payment_type"`
+	EndDate      string  `json:"end_date"` // Formato: YYYY-MM-DD
+	LeadDays     *int    `json:"lead_days" binding:"omitempty,min=0,max=366"`
+	ReminderDays *int    `json:"reminder_days" binding:"omitempty,min=0,max=30"`
+	NotifyEmails string  `json:"notify_emails"`
+	Active       *bool   `json:"active"`
+}
+
+// authorizePayables verifica se o usuário pode gerenciar as contas a pagar da comunidade
+func (h *Handler) authorizePayables(c *gin.Context) (*domain.User, *domain.Community, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return nil, nil, false
+	}
+
+	// Verifica se a comunidade existe
+	community, err := h.repos.Community.FindByID(context.Background(), c.Param("communityId"))
+	if err != nil {
+		h.logger.Error("erro ao buscar comunidade", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+		return nil, nil, false
+	}
+	if community == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comunidade não encontrada"})
+		return nil, nil, false
+	}
+
+	// Verifica se o usuário tem permissão
+	if community.CreatedBy != user.(*domain.User).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Você não tem permissão para gerenciar contas a pagar"})
+		return nil, nil, false
+	}
+
+	return user.(*domain.User), community, true
+}
+
+// respondRecurringExpenseError converte os erros do serviço de despesas recorrentes em respostas HTTP
+func (h *Handler) respondRecurringExpenseError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrRecurringExpenseNotFound, service.ErrSupplierNotFound, service.ErrRecurringExpenseGroup:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case service.ErrInvalidFrequency, service.ErrInvalidRecurrenceInterval, service.ErrInvalidRecurrenceEnd,
+		service.ErrInvalidLeadDays, service.ErrInvalidReminderDays, service.ErrInvalidRecurringEmails,
+		service.ErrInvalidExpenseCategory, service.ErrInvalidPayablesDays:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("erro ao processar despesa recorrente", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+	}
+}
+
+// AddRecurringExpense cria um modelo de despesa recorrente e gera as primeiras despesas
+func (h *Handler) AddRecurringExpense(c *gin.Context) {
+	user, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	var req RecurringExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida. Use o formato YYYY-MM-DD"})
+		return
+	}
+	endDate, ok := parseRecurrenceEndDate(c, req.EndDate)
+	if !ok {
+		return
+	}
+
+	recurring := &domain.RecurringExpense{
+		CommunityID:  community.ID,
+		UserID:       user.ID,
+		CategoryID:   req.CategoryID,
+		SupplierID:   req.SupplierID,
+		GroupID:      req.GroupID,
+		Amount:       req.Amount,
+		Description:  req.Description,
+		PaymentType:  req.PaymentType,
+		Frequency:    req.Frequency,
+		Interval:     req.Interval,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		LeadDays:     30,
+		ReminderDays: 3,
+		NotifyEmails: req.NotifyEmails,
+	}
+	if req.LeadDays != nil {
+		recurring.LeadDays = *req.LeadDays
+	}
+	if req.ReminderDays != nil {
+		recurring.ReminderDays = *req.ReminderDays
+	}
+
+	if err := h.services.RecurringExpense.CreateRecurringExpense(c.Request.Context(), community, recurring); err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":           "Despesa recorrente criada com sucesso",
+		"recurring_expense": recurring,
+	})
+}
+
+// ListRecurringExpenses lista os modelos de despesas recorrentes da comunidade
+func (h *Handler) ListRecurringExpenses(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.NewFilterFromQuery(c)
+	if active := c.Query("active"); active != "" {
+		value, err := strconv.ParseBool(active)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro active inválido"})
+			return
+		}
+		filter.AddCondition("active = ?", value)
+	}
+	if frequency := c.Query("frequency"); frequency != "" {
+		filter.AddCondition("frequency = ?", frequency)
+	}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		filter.AddCondition("category_id = ?", categoryID)
+	}
+
+	recurring, total, err := h.repos.RecurringExpense.List(c.Request.Context(), community.ID, filter)
+	if err != nil {
+		h.logger.Error("erro ao listar despesas recorrentes", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar despesas recorrentes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recurring_expenses": recurring,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        filter.Page,
+			"per_page":    filter.PerPage,
+			"total_pages": (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
+}
+
+// GetRecurringExpense retorna um modelo de despesa recorrente
+func (h *Handler) GetRecurringExpense(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.services.RecurringExpense.GetRecurringExpense(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recurring_expense": recurring})
+}
+
+// UpdateRecurringExpense atualiza o modelo e as despesas pendentes ainda não vencidas
+func (h *Handler) UpdateRecurringExpense(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.services.RecurringExpense.GetRecurringExpense(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	var req UpdateRecurringExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	endDate, ok := parseRecurrenceEndDate(c, req.EndDate)
+	if !ok {
+		return
+	}
+
+	recurring.CategoryID = req.CategoryID
+	recurring.SupplierID = req.SupplierID
+	recurring.GroupID = req.GroupID
+	recurring.Amount = req.Amount
+	recurring.Description = req.Description
+	recurring.PaymentType = req.PaymentType
+	recurring.EndDate = endDate
+	recurring.NotifyEmails = req.NotifyEmails
+	if req.LeadDays != nil {
+		recurring.LeadDays = *req.LeadDays
+	}
+	if req.ReminderDays != nil {
+		recurring.ReminderDays = *req.ReminderDays
+	}
+	if req.Active != nil {
+		recurring.Active = *req.Active
+	}
+
+	if err := h.services.RecurringExpense.UpdateRecurringExpense(c.Request.Context(), community, recurring); err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Despesa recorrente atualizada com sucesso",
+		"recurring_expense": recurring,
+	})
+}
+
+// DeleteRecurringExpense exclui o modelo e as despesas pendentes que ainda não venceram
+func (h *Handler) DeleteRecurringExpense(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.services.RecurringExpense.GetRecurringExpense(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	if err := h.services.RecurringExpense.DeleteRecurringExpense(c.Request.Context(), community, recurring); err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Despesa recorrente excluída com sucesso"})
+}
+
+// GenerateRecurringExpense gera imediatamente as despesas do modelo dentro da antecedência configurada
+func (h *Handler) GenerateRecurringExpense(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.services.RecurringExpense.GetRecurringExpense(c.Request.Context(), community.ID, c.Param("id"))
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	created, err := h.services.RecurringExpense.Materialize(c.Request.Context(), community, recurring, time.Now())
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Despesas geradas com sucesso",
+		"created":           created,
+		"recurring_expense": recurring,
+	})
+}
+
+// GetPayables retorna as contas a pagar: despesas vencidas e as que vencem nos
+// próximos dias (parâmetro days, padrão 30)
+func (h *Handler) GetPayables(c *gin.Context) {
+	_, community, ok := h.authorizePayables(c)
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro days inválido"})
+		return
+	}
+
+	payables, err := h.services.RecurringExpense.Payables(c.Request.Context(), community, days)
+	if err != nil {
+		h.respondRecurringExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payables)
+}
+
+// parseRecurrenceEndDate lê a data final opcional da recorrência
+func parseRecurrenceEndDate(c *gin.Context, value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	endDate, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida. Use o formato YYYY-MM-DD"})
+		return nil, false
+	}
+	return &endDate, true
+}
@@ -43,3 +43,41 @@ func (h *Handler) HandleAsaasAccountStatusWebhook(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// HandleAsaasPaymentWebhook processa os webhooks de cobranças do ASAAS
+func (h *Handler) HandleAsaasPaymentWebhook(c *gin.Context) {
+	h.handlePaymentWebhook(c, service.GatewayAsaas)
+}
+
+// HandlePaymentWebhook processa os webhooks de cobranças do provedor informado na rota
+func (h *Handler) HandlePaymentWebhook(c *gin.Context) {
+	h.handlePaymentWebhook(c, c.Param("provider"))
+}
+
+func (h *Handler) handlePaymentWebhook(c *gin.Context, provider string) {
+	body, err := c.GetRawData()
+	if err != nil {
+		h.logger.Error("Erro ao ler webhook", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar webhook"})
+		return
+	}
+
+	h.logger.Info("Webhook de cobrança recebido", zap.String("provider", provider))
+
+	if err := h.services.Payment.HandleWebhook(c.Request.Context(), provider, c.Request.Header, body); err != nil {
+		switch err {
+		case service.ErrUnknownPaymentGateway:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case service.ErrInvalidWebhookToken:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case service.ErrInvalidPaymentEvent:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar webhook"})
+		default:
+			h.logger.Error("Erro ao processar webhook", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar webhook"})
+		}
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
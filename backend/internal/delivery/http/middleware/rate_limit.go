@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRateClients limita quantos clientes cada limitador acompanha ao mesmo tempo para
+// que uma rajada de IPs diferentes não faça o mapa crescer sem limite
+const maxRateClients = 10000
+
+// rateWindow conta as requisições de um cliente na janela atual
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit limita cada IP a limit requisições por janela de tempo nas rotas do grupo.
+// A contagem é mantida em memória, por instância do servidor. O IP vem de
+// c.ClientIP(), que só considera os cabeçalhos de encaminhamento dos proxies
+// confiáveis configurados no servidor.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		clients   = make(map[string]*rateWindow)
+		lastSweep = time.Now()
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		key := c.ClientIP() + " " + c.FullPath()
+
+		mu.Lock()
+		// Remove periodicamente as janelas expiradas para não acumular clientes inativos
+		if now.Sub(lastSweep) > window {
+			for k, w := range clients {
+				if now.Sub(w.start) >= window {
+					delete(clients, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, ok := clients[key]
+		if !ok && len(clients) >= maxRateClients {
+			evictOldest(clients)
+		}
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			clients[key] = w
+		}
+		w.count++
+		count := w.count
+		retryAfter := w.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Muitas requisições. Tente novamente em instantes"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// evictOldest remove o cliente com a janela mais antiga para abrir espaço no mapa
+func evictOldest(clients map[string]*rateWindow) {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for k, w := range clients {
+		if oldestKey == "" || w.start.Before(oldest) {
+			oldestKey, oldest = k, w.start
+		}
+	}
+	delete(clients, oldestKey)
+}
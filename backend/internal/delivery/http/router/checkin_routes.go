@@ -1,13 +1,19 @@
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/comunidade/backend/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
 
 func InitPublicCheckInRoutes(router *gin.RouterGroup, h RouteHandler) {
-	// Rotas públicas de check-in
-	router.GET("/events/:eventId/checkin/public", h.GetPublicEvent)            // Usa o mesmo handler do evento público
-	router.POST("/events/:eventId/checkin", h.CreateCheckIn)                   // Permite criar check-in sem autenticação
-	router.GET("/events/:eventId/members/search", h.SearchMember)              // Busca membro por email/telefone
-	router.GET("/events/:eventId/members/:memberId/family", h.GetMemberFamily) // Busca família do membro
+	// Rotas públicas de check-in. O limite por IP é o mesmo da página pública de doações,
+	// folgado o bastante para os visitantes que compartilham a rede da igreja.
+	router.GET("/events/:eventId/checkin/public", h.GetPublicEvent)                                 // Usa o mesmo handler do evento público
+	router.POST("/events/:eventId/checkin", middleware.RateLimit(60, time.Minute), h.CreateCheckIn) // Permite criar check-in sem autenticação
+	router.GET("/events/:eventId/members/search", h.SearchMember)                                   // Busca membro por email/telefone
+	router.GET("/events/:eventId/members/:memberId/family", h.GetMemberFamily)                      // Busca família do membro
 }
 
 func InitCheckInRoutes(router *gin.RouterGroup, h RouteHandler) {
@@ -18,3 +24,15 @@ func InitCheckInRoutes(router *gin.RouterGroup, h RouteHandler) {
 		checkIn.GET("/stats", h.GetEventStats)   // Estatísticas do evento (protegido)
 	}
 }
+
+// InitKidsCheckInRoutes registra as rotas do check-in do ministério infantil
+func InitKidsCheckInRoutes(router *gin.RouterGroup, h RouteHandler) {
+	kids := router.Group("/:communityId/events/:eventId/kids")
+	{
+		kids.GET("", h.ListKidsCheckIns)
+		kids.GET("/classrooms", h.ListKidsClassrooms)
+		kids.POST("/checkin", h.CreateKidsCheckIn)
+		kids.POST("/checkout", h.CheckoutKids)
+		kids.GET("/labels", h.PrintKidsLabels)
+	}
+}
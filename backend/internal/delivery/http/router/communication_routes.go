@@ -11,6 +11,7 @@ func InitCommunicationRoutes(router *gin.RouterGroup, h RouteHandler) {
 		communications.PUT("/:communicationId", h.UpdateCommunication)
 		communications.DELETE("/:communicationId", h.DeleteCommunication)
 		communications.POST("/:communicationId/send", h.SendCommunication)
+		communications.GET("/:communicationId/recipients", h.ListCommunicationRecipients)
 
 		communications.POST("/templates", h.CreateTemplate)
 		communications.GET("/templates", h.ListTemplates)
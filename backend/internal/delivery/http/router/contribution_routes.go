@@ -0,0 +1,28 @@
+package router
+
+import "github.com/gin-gonic/gin"
+
+func InitContributionRoutes(router *gin.RouterGroup, h RouteHandler) {
+	contributions := router.Group("/:communityId/contributions")
+	{
+		contributions.GET("", h.ListContributions)
+
+		// Lotes de contagem de ofertas
+		batches := contributions.Group("/batches")
+		{
+			batches.POST("", h.CreateContributionBatch)
+			batches.GET("", h.ListContributionBatches)
+			batches.GET("/:batchId", h.GetContributionBatch)
+			batches.PUT("/:batchId", h.UpdateContributionBatch)
+			batches.POST("/:batchId/close", h.CloseContributionBatch)
+			batches.POST("/:batchId/verify", h.VerifyContributionBatch)
+			batches.POST("/:batchId/reopen", h.ReopenContributionBatch)
+
+			// Envelopes do lote
+			batches.POST("/:batchId/contributions", h.CreateContribution)
+			batches.GET("/:batchId/contributions/:contributionId", h.GetContribution)
+			batches.PUT("/:batchId/contributions/:contributionId", h.UpdateContribution)
+			batches.DELETE("/:batchId/contributions/:contributionId", h.DeleteContribution)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/comunidade/backend/internal/delivery/http/middleware"
+	"github.com/gin-gonic/gin"
+)
 
 func InitDonationRoutes(router *gin.RouterGroup, h RouteHandler) {
 	donations := router.Group("/communities/:communityId/donations")
@@ -10,6 +15,10 @@ func InitDonationRoutes(router *gin.RouterGroup, h RouteHandler) {
 		donations.GET("/asaas/config", h.GetAsaasConfig)
 		donations.PUT("/asaas/config", h.UpdateAsaasConfig)
 
+		// Provedor de pagamentos da comunidade
+		donations.GET("/gateway", h.GetPaymentGateway)
+		donations.PUT("/gateway", h.UpdatePaymentGateway)
+
 		// Gerenciamento de Contas ASAAS
 		donations.POST("/asaas/accounts", h.AddAsaasAccount)
 		donations.GET("/asaas/accounts", h.ListAsaasAccounts)
@@ -22,6 +31,9 @@ func InitDonationRoutes(router *gin.RouterGroup, h RouteHandler) {
 		// Campanhas
 		donations.POST("/campaigns", h.AddCampaign)
 		donations.GET("/campaigns", h.ListCampaigns)
+		donations.GET("/campaigns/:campaignId", h.GetCampaign)
+		donations.PUT("/campaigns/:campaignId", h.UpdateCampaign)
+		donations.POST("/campaigns/:campaignId/close", h.CloseCampaign)
 
 		// Doações únicas
 		donations.POST("/donations", h.AddDonation)
@@ -29,10 +41,51 @@ func InitDonationRoutes(router *gin.RouterGroup, h RouteHandler) {
 		donations.PUT("/donations/:donationId", h.UpdateDonation)
 		donations.DELETE("/donations/:donationId", h.DeleteDonation)
 		donations.POST("/donations/:donationId/send-payment-link", h.SendPaymentLink)
+		donations.POST("/donations/:donationId/refund", h.RefundDonation)
 
 		// Doações recorrentes
 		donations.POST("/recurring", h.AddRecurringDonation)
 		donations.GET("/recurring", h.ListRecurringDonations)
+		donations.GET("/recurring/:recurringId", h.GetRecurringDonation)
+		donations.PUT("/recurring/:recurringId", h.UpdateRecurringDonation)
+		donations.POST("/recurring/:recurringId/pause", h.PauseRecurringDonation)
+		donations.POST("/recurring/:recurringId/resume", h.ResumeRecurringDonation)
+		donations.PUT("/recurring/:recurringId/card", h.UpdateRecurringDonationCard)
+		donations.POST("/recurring/:recurringId/cancel", h.CancelRecurringDonation)
+
+		// Comprovantes anuais de contribuição
+		donations.GET("/statements", h.ListGivingStatements)
+		donations.POST("/statements/send", h.SendGivingStatements)
+		donations.GET("/statements/:memberId", h.GetGivingStatement)
+	}
+
+}
+
+// InitMemberDonationRoutes registra as rotas do portal do membro para as próprias doações recorrentes
+func InitMemberDonationRoutes(router *gin.RouterGroup, h RouteHandler) {
+	recurring := router.Group("/communities/:communityId/members/me/recurring-donations")
+	{
+		recurring.GET("", h.ListMyRecurringDonations)
+		recurring.GET("/:recurringId", h.GetMyRecurringDonation)
+		recurring.PUT("/:recurringId", h.UpdateMyRecurringDonation)
+		recurring.POST("/:recurringId/pause", h.PauseMyRecurringDonation)
+		recurring.POST("/:recurringId/resume", h.ResumeMyRecurringDonation)
+		recurring.PUT("/:recurringId/card", h.UpdateMyRecurringDonationCard)
+		recurring.POST("/:recurringId/cancel", h.CancelMyRecurringDonation)
 	}
+}
 
+// InitPublicGivingRoutes registra as páginas públicas do site de doações, identificadas pelo slug da comunidade
+func InitPublicGivingRoutes(router *gin.RouterGroup, h RouteHandler) {
+	giving := router.Group("/giving/:slug")
+	giving.Use(middleware.RateLimit(60, time.Minute))
+	{
+		giving.GET("", h.GetGivingPage)
+		giving.GET("/campaigns", h.ListPublicCampaigns)
+		giving.GET("/campaigns/:campaignId", h.GetPublicCampaign)
+		giving.GET("/donations/:donationId", h.GetPublicDonation)
+
+		// Cada cobrança criada gera um cliente e um pagamento no provedor da comunidade
+		giving.POST("/donations", middleware.RateLimit(5, 10*time.Minute), h.CreatePublicDonation)
+	}
 }
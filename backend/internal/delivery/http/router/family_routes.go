@@ -6,6 +6,7 @@ func InitFamilyRoutes(router *gin.RouterGroup, h RouteHandler) {
 	families := router.Group("/:communityId/families")
 	{
 		families.GET("", h.ListFamilies)
+		families.GET("/mailing-list", h.GetFamilyMailingList)
 		families.GET("/:familyId", h.GetFamily)
 		families.POST("", h.AddFamily)
 		families.PUT("/:familyId", h.UpdateFamily)
@@ -13,5 +14,6 @@ func InitFamilyRoutes(router *gin.RouterGroup, h RouteHandler) {
 		families.POST("/:familyId/members", h.AddFamilyMember)
 		families.DELETE("/:familyId/members/:memberId", h.RemoveFamilyMember)
 		families.PUT("/:familyId/members/:memberId/role", h.UpdateFamilyMemberRole)
+		families.GET("/:familyId/graph", h.GetFamilyGraph)
 	}
 }
@@ -28,8 +28,27 @@ func InitFinancialRoutes(router *gin.RouterGroup, h RouteHandler) {
 		{
 			expenses.POST("", h.AddExpense)
 			expenses.GET("", h.ListExpenses)
+			expenses.GET("/export", h.ExportExpenses)
 			expenses.PUT("/:id", h.UpdateExpense)
 			expenses.DELETE("/:id", h.DeleteExpense)
+
+			// Aprovação e comprovantes
+			expenses.GET("/awaiting-approval", h.ListExpensesAwaitingApproval)
+			expenses.GET("/:id/approval", h.GetExpenseApproval)
+			expenses.POST("/:id/approve", h.ApproveExpense)
+			expenses.POST("/:id/reject", h.RejectExpense)
+			expenses.POST("/:id/receipts", h.UploadExpenseReceipt)
+			expenses.GET("/:id/receipts", h.ListExpenseReceipts)
+			expenses.DELETE("/:id/receipts/:receiptId", h.DeleteExpenseReceipt)
+		}
+
+		// Rotas para Regras de Aprovação de Despesas
+		approvalRules := financial.Group("/approval-rules")
+		{
+			approvalRules.POST("", h.AddExpenseApprovalRule)
+			approvalRules.GET("", h.ListExpenseApprovalRules)
+			approvalRules.PUT("/:id", h.UpdateExpenseApprovalRule)
+			approvalRules.DELETE("/:id", h.DeleteExpenseApprovalRule)
 		}
 
 		// Rotas para Receitas
@@ -37,15 +56,66 @@ func InitFinancialRoutes(router *gin.RouterGroup, h RouteHandler) {
 		{
 			revenues.POST("", h.AddRevenue)
 			revenues.GET("", h.ListRevenues)
+			revenues.GET("/export", h.ExportRevenues)
 			revenues.PUT("/:id", h.UpdateRevenue)
 			revenues.DELETE("/:id", h.DeleteRevenue)
 		}
 
+		// Exportação das doações para a contabilidade
+		financial.GET("/donations/export", h.ExportDonations)
+
+		// Rotas para Regras de Lançamento de Doações
+		postingRules := financial.Group("/posting-rules")
+		{
+			postingRules.POST("", h.AddDonationPostingRule)
+			postingRules.GET("", h.ListDonationPostingRules)
+			postingRules.PUT("/:id", h.UpdateDonationPostingRule)
+			postingRules.DELETE("/:id", h.DeleteDonationPostingRule)
+		}
+
+		// Rotas para Despesas Recorrentes e Contas a Pagar
+		recurringExpenses := financial.Group("/recurring-expenses")
+		{
+			recurringExpenses.POST("", h.AddRecurringExpense)
+			recurringExpenses.GET("", h.ListRecurringExpenses)
+			recurringExpenses.GET("/:id", h.GetRecurringExpense)
+			recurringExpenses.PUT("/:id", h.UpdateRecurringExpense)
+			recurringExpenses.DELETE("/:id", h.DeleteRecurringExpense)
+			recurringExpenses.POST("/:id/generate", h.GenerateRecurringExpense)
+		}
+		financial.GET("/payables", h.GetPayables)
+
+		// Rotas para Orçamentos
+		budgets := financial.Group("/budgets")
+		{
+			budgets.POST("", h.AddBudget)
+			budgets.GET("", h.ListBudgets)
+			budgets.GET("/summary", h.GetBudgetSummary)
+			budgets.GET("/:id", h.GetBudget)
+			budgets.PUT("/:id", h.UpdateBudget)
+			budgets.DELETE("/:id", h.DeleteBudget)
+		}
+
+		// Rotas para Conciliação Bancária (extratos OFX e retornos CNAB 240)
+		reconciliation := financial.Group("/reconciliation")
+		{
+			reconciliation.POST("/imports", h.ImportBankStatement)
+			reconciliation.GET("/imports", h.ListBankStatementImports)
+			reconciliation.GET("/imports/:importId", h.GetBankStatementImport)
+			reconciliation.GET("/transactions", h.ListBankTransactions)
+			reconciliation.GET("/transactions/:transactionId/candidates", h.GetBankTransactionCandidates)
+			reconciliation.POST("/transactions/:transactionId/match", h.MatchBankTransaction)
+			reconciliation.POST("/transactions/:transactionId/entry", h.CreateBankTransactionEntry)
+			reconciliation.POST("/transactions/:transactionId/ignore", h.IgnoreBankTransaction)
+			reconciliation.POST("/transactions/:transactionId/reopen", h.ReopenBankTransaction)
+		}
+
 		// Rotas para Relatórios
 		reports := financial.Group("/reports")
 		{
 			reports.POST("", h.GenerateFinancialReport)
 			reports.GET("", h.ListFinancialReports)
+			reports.GET("/:id", h.GetFinancialReport)
 		}
 	}
 }
@@ -55,6 +55,62 @@ type RouteHandler interface {
 	RemoveMember(c *gin.Context)
 	UploadMemberPhoto(c *gin.Context)
 	GetMemberFamily(c *gin.Context)
+	DryRunMemberImport(c *gin.Context)
+	ImportMembers(c *gin.Context)
+	ListMemberImports(c *gin.Context)
+	GetMemberImport(c *gin.Context)
+	DownloadMemberImportErrors(c *gin.Context)
+	ListMemberDuplicates(c *gin.Context)
+	MergeMember(c *gin.Context)
+	ListMemberMerges(c *gin.Context)
+	SearchMembers(c *gin.Context)
+	AddMemberSegment(c *gin.Context)
+	ListMemberSegments(c *gin.Context)
+	GetMemberSegment(c *gin.Context)
+	UpdateMemberSegment(c *gin.Context)
+	DeleteMemberSegment(c *gin.Context)
+	ListMemberSegmentMembers(c *gin.Context)
+	ListPipelineStages(c *gin.Context)
+	AddPipelineStage(c *gin.Context)
+	UpdatePipelineStage(c *gin.Context)
+	DeletePipelineStage(c *gin.Context)
+	GetPipelineDashboard(c *gin.Context)
+	MoveMemberStage(c *gin.Context)
+	GetMemberStageHistory(c *gin.Context)
+	ListFollowUpTasks(c *gin.Context)
+	AddFollowUpTask(c *gin.Context)
+	GetFollowUpTask(c *gin.Context)
+	UpdateFollowUpTask(c *gin.Context)
+	CompleteFollowUpTask(c *gin.Context)
+	ListVisitorCheckIns(c *gin.Context)
+	ConfirmVisitorCheckIn(c *gin.Context)
+	DismissVisitorCheckIn(c *gin.Context)
+	GetMemberProfileSettings(c *gin.Context)
+	UpdateMemberProfileSettings(c *gin.Context)
+	ListMemberProfileChanges(c *gin.Context)
+	GetMemberProfileChange(c *gin.Context)
+	ApproveMemberProfileChange(c *gin.Context)
+	RejectMemberProfileChange(c *gin.Context)
+	UpdateMyProfile(c *gin.Context)
+	UploadMyPhoto(c *gin.Context)
+	ListMyFamily(c *gin.Context)
+	AddMyFamilyMember(c *gin.Context)
+	UpdateMyFamilyMember(c *gin.Context)
+	RemoveMyFamilyMember(c *gin.Context)
+	ListMyProfileChanges(c *gin.Context)
+	CancelMyProfileChange(c *gin.Context)
+	ListMembershipEvents(c *gin.Context)
+	AddMembershipEvent(c *gin.Context)
+	VoidMembershipEvent(c *gin.Context)
+	ListMemberLetters(c *gin.Context)
+	IssueMemberLetter(c *gin.Context)
+	DownloadMemberLetter(c *gin.Context)
+	GetLetterTemplate(c *gin.Context)
+	UpdateLetterTemplate(c *gin.Context)
+	ListCelebrations(c *gin.Context)
+	GetCelebrationSettings(c *gin.Context)
+	UpdateCelebrationSettings(c *gin.Context)
+	SendCelebrationDigest(c *gin.Context)
 
 	// Family
 	ListFamilies(c *gin.Context)
@@ -65,6 +121,10 @@ type RouteHandler interface {
 	AddFamilyMember(c *gin.Context)
 	RemoveFamilyMember(c *gin.Context)
 	UpdateFamilyMemberRole(c *gin.Context)
+	GetFamilyGraph(c *gin.Context)
+	GetFamilyMailingList(c *gin.Context)
+	ListMemberRelatives(c *gin.Context)
+	UpdateMemberRelations(c *gin.Context)
 
 	// Group
 	CreateGroup(c *gin.Context)
@@ -94,6 +154,7 @@ type RouteHandler interface {
 	UpdateCommunication(c *gin.Context)
 	DeleteCommunication(c *gin.Context)
 	SendCommunication(c *gin.Context)
+	ListCommunicationRecipients(c *gin.Context)
 
 	CreateTemplate(c *gin.Context)
 	GetTemplate(c *gin.Context)
@@ -110,6 +171,11 @@ type RouteHandler interface {
 	CreateCheckIn(c *gin.Context)
 	GetEventCheckIns(c *gin.Context)
 	GetEventStats(c *gin.Context)
+	ListKidsClassrooms(c *gin.Context)
+	ListKidsCheckIns(c *gin.Context)
+	CreateKidsCheckIn(c *gin.Context)
+	CheckoutKids(c *gin.Context)
+	PrintKidsLabels(c *gin.Context)
 
 	// Financeiro
 	AddFinancialCategory(c *gin.Context)
@@ -130,11 +196,68 @@ type RouteHandler interface {
 	DeleteRevenue(c *gin.Context)
 	GenerateFinancialReport(c *gin.Context)
 	ListFinancialReports(c *gin.Context)
+	GetFinancialReport(c *gin.Context)
+	ExportExpenses(c *gin.Context)
+	ExportRevenues(c *gin.Context)
+	ExportDonations(c *gin.Context)
+	ImportBankStatement(c *gin.Context)
+	ListBankStatementImports(c *gin.Context)
+	GetBankStatementImport(c *gin.Context)
+	ListBankTransactions(c *gin.Context)
+	GetBankTransactionCandidates(c *gin.Context)
+	MatchBankTransaction(c *gin.Context)
+	CreateBankTransactionEntry(c *gin.Context)
+	IgnoreBankTransaction(c *gin.Context)
+	ReopenBankTransaction(c *gin.Context)
+	ListExpensesAwaitingApproval(c *gin.Context)
+	GetExpenseApproval(c *gin.Context)
+	ApproveExpense(c *gin.Context)
+	RejectExpense(c *gin.Context)
+	UploadExpenseReceipt(c *gin.Context)
+	ListExpenseReceipts(c *gin.Context)
+	DeleteExpenseReceipt(c *gin.Context)
+	AddExpenseApprovalRule(c *gin.Context)
+	ListExpenseApprovalRules(c *gin.Context)
+	UpdateExpenseApprovalRule(c *gin.Context)
+	DeleteExpenseApprovalRule(c *gin.Context)
+	AddRecurringExpense(c *gin.Context)
+	ListRecurringExpenses(c *gin.Context)
+	GetRecurringExpense(c *gin.Context)
+	UpdateRecurringExpense(c *gin.Context)
+	DeleteRecurringExpense(c *gin.Context)
+	GenerateRecurringExpense(c *gin.Context)
+	GetPayables(c *gin.Context)
+	AddBudget(c *gin.Context)
+	ListBudgets(c *gin.Context)
+	GetBudgetSummary(c *gin.Context)
+	GetBudget(c *gin.Context)
+	UpdateBudget(c *gin.Context)
+	DeleteBudget(c *gin.Context)
+	AddDonationPostingRule(c *gin.Context)
+	ListDonationPostingRules(c *gin.Context)
+	UpdateDonationPostingRule(c *gin.Context)
+	DeleteDonationPostingRule(c *gin.Context)
+
+	// Contribuições
+	ListContributions(c *gin.Context)
+	CreateContributionBatch(c *gin.Context)
+	ListContributionBatches(c *gin.Context)
+	GetContributionBatch(c *gin.Context)
+	UpdateContributionBatch(c *gin.Context)
+	CloseContributionBatch(c *gin.Context)
+	VerifyContributionBatch(c *gin.Context)
+	ReopenContributionBatch(c *gin.Context)
+	CreateContribution(c *gin.Context)
+	GetContribution(c *gin.Context)
+	UpdateContribution(c *gin.Context)
+	DeleteContribution(c *gin.Context)
 
 	// ASAAS Integration
 	AddAsaasConfig(c *gin.Context)
 	GetAsaasConfig(c *gin.Context)
 	UpdateAsaasConfig(c *gin.Context)
+	GetPaymentGateway(c *gin.Context)
+	UpdatePaymentGateway(c *gin.Context)
 
 	// ASAAS Accounts
 	AddAsaasAccount(c *gin.Context)
@@ -148,6 +271,14 @@ type RouteHandler interface {
 	// Campaigns
 	AddCampaign(c *gin.Context)
 	ListCampaigns(c *gin.Context)
+	GetCampaign(c *gin.Context)
+	UpdateCampaign(c *gin.Context)
+	CloseCampaign(c *gin.Context)
+	ListPublicCampaigns(c *gin.Context)
+	GetPublicCampaign(c *gin.Context)
+	GetGivingPage(c *gin.Context)
+	CreatePublicDonation(c *gin.Context)
+	GetPublicDonation(c *gin.Context)
 
 	// Donations
 	AddDonation(c *gin.Context)
@@ -155,11 +286,32 @@ type RouteHandler interface {
 	UpdateDonation(c *gin.Context)
 	DeleteDonation(c *gin.Context)
 	SendPaymentLink(c *gin.Context)
+	RefundDonation(c *gin.Context)
 	AddRecurringDonation(c *gin.Context)
 	ListRecurringDonations(c *gin.Context)
+	GetRecurringDonation(c *gin.Context)
+	UpdateRecurringDonation(c *gin.Context)
+	PauseRecurringDonation(c *gin.Context)
+	ResumeRecurringDonation(c *gin.Context)
+	UpdateRecurringDonationCard(c *gin.Context)
+	CancelRecurringDonation(c *gin.Context)
+	ListMyRecurringDonations(c *gin.Context)
+	GetMyRecurringDonation(c *gin.Context)
+	UpdateMyRecurringDonation(c *gin.Context)
+	PauseMyRecurringDonation(c *gin.Context)
+	ResumeMyRecurringDonation(c *gin.Context)
+	UpdateMyRecurringDonationCard(c *gin.Context)
+	CancelMyRecurringDonation(c *gin.Context)
+
+	// Giving Statements
+	ListGivingStatements(c *gin.Context)
+	GetGivingStatement(c *gin.Context)
+	SendGivingStatements(c *gin.Context)
 
 	// Webhooks
 	HandleAsaasAccountStatusWebhook(c *gin.Context)
+	HandlePaymentWebhook(c *gin.Context)
+	HandleAsaasPaymentWebhook(c *gin.Context)
 
 	// Engagement
 	GetMemberDashboard(c *gin.Context)
@@ -8,10 +8,106 @@ func InitMemberRoutes(router *gin.RouterGroup, h RouteHandler) {
 		members.POST("", h.AddMember)
 		members.GET("", h.ListMembers)
 		members.GET("/search", h.SearchMember)
+		members.POST("/search", h.SearchMembers)
+		members.POST("/import/dry-run", h.DryRunMemberImport)
+		members.POST("/import", h.ImportMembers)
+		members.GET("/imports", h.ListMemberImports)
+		members.GET("/imports/:importId", h.GetMemberImport)
+		members.GET("/imports/:importId/errors", h.DownloadMemberImportErrors)
+		members.GET("/duplicates", h.ListMemberDuplicates)
+		members.GET("/merges", h.ListMemberMerges)
 		members.GET("/:memberId", h.GetMember)
 		members.PUT("/:memberId", h.UpdateMember)
 		members.DELETE("/:memberId", h.RemoveMember)
 		members.POST("/:memberId/photo", h.UploadMemberPhoto)
 		members.GET("/:memberId/family", h.GetMemberFamily)
+		members.GET("/:memberId/relatives", h.ListMemberRelatives)
+		members.PUT("/:memberId/relations", h.UpdateMemberRelations)
+		members.POST("/:memberId/merge", h.MergeMember)
+		members.POST("/:memberId/stage", h.MoveMemberStage)
+		members.GET("/:memberId/stage-history", h.GetMemberStageHistory)
+		members.GET("/:memberId/membership-events", h.ListMembershipEvents)
+		members.POST("/:memberId/membership-events", h.AddMembershipEvent)
+		members.POST("/:memberId/membership-events/:eventId/void", h.VoidMembershipEvent)
+		members.GET("/:memberId/letters", h.ListMemberLetters)
+		members.POST("/:memberId/letters", h.IssueMemberLetter)
+		members.GET("/:memberId/letters/:letterId/pdf", h.DownloadMemberLetter)
+	}
+}
+
+func InitMemberSegmentRoutes(router *gin.RouterGroup, h RouteHandler) {
+	segments := router.Group("/:communityId/segments")
+	{
+		segments.POST("", h.AddMemberSegment)
+		segments.GET("", h.ListMemberSegments)
+		segments.GET("/:segmentId", h.GetMemberSegment)
+		segments.PUT("/:segmentId", h.UpdateMemberSegment)
+		segments.DELETE("/:segmentId", h.DeleteMemberSegment)
+		segments.GET("/:segmentId/members", h.ListMemberSegmentMembers)
+	}
+}
+
+func InitMemberPipelineRoutes(router *gin.RouterGroup, h RouteHandler) {
+	pipeline := router.Group("/:communityId/pipeline")
+	{
+		pipeline.GET("/stages", h.ListPipelineStages)
+		pipeline.POST("/stages", h.AddPipelineStage)
+		pipeline.PUT("/stages/:stageId", h.UpdatePipelineStage)
+		pipeline.DELETE("/stages/:stageId", h.DeletePipelineStage)
+		pipeline.GET("/dashboard", h.GetPipelineDashboard)
+		pipeline.GET("/tasks", h.ListFollowUpTasks)
+		pipeline.POST("/tasks", h.AddFollowUpTask)
+		pipeline.GET("/tasks/:taskId", h.GetFollowUpTask)
+		pipeline.PUT("/tasks/:taskId", h.UpdateFollowUpTask)
+		pipeline.POST("/tasks/:taskId/complete", h.CompleteFollowUpTask)
+		pipeline.GET("/visitors", h.ListVisitorCheckIns)
+		pipeline.POST("/visitors/:checkInId/confirm", h.ConfirmVisitorCheckIn)
+		pipeline.POST("/visitors/:checkInId/dismiss", h.DismissVisitorCheckIn)
+	}
+}
+
+func InitMemberProfileChangeRoutes(router *gin.RouterGroup, h RouteHandler) {
+	router.GET("/:communityId/profile-settings", h.GetMemberProfileSettings)
+	router.PUT("/:communityId/profile-settings", h.UpdateMemberProfileSettings)
+
+	changes := router.Group("/:communityId/profile-changes")
+	{
+		changes.GET("", h.ListMemberProfileChanges)
+		changes.GET("/:changeId", h.GetMemberProfileChange)
+		changes.POST("/:changeId/approve", h.ApproveMemberProfileChange)
+		changes.POST("/:changeId/reject", h.RejectMemberProfileChange)
+	}
+}
+
+func InitLetterTemplateRoutes(router *gin.RouterGroup, h RouteHandler) {
+	templates := router.Group("/:communityId/letter-templates")
+	{
+		templates.GET("/:type", h.GetLetterTemplate)
+		templates.PUT("/:type", h.UpdateLetterTemplate)
+	}
+}
+
+func InitCelebrationRoutes(router *gin.RouterGroup, h RouteHandler) {
+	celebrations := router.Group("/:communityId/celebrations")
+	{
+		celebrations.GET("", h.ListCelebrations)
+		celebrations.GET("/settings", h.GetCelebrationSettings)
+		celebrations.PUT("/settings", h.UpdateCelebrationSettings)
+		celebrations.POST("/digest", h.SendCelebrationDigest)
+	}
+}
+
+// InitMemberProfileRoutes registra as rotas do portal do membro para o próprio cadastro
+func InitMemberProfileRoutes(router *gin.RouterGroup, h RouteHandler) {
+	me := router.Group("/communities/:communityId/members/me")
+	{
+		me.PUT("/profile", h.UpdateMyProfile)
+		me.POST("/photo", h.UploadMyPhoto)
+		me.GET("/family", h.ListMyFamily)
+		me.POST("/family", h.AddMyFamilyMember)
+		me.PUT("/family/:memberId", h.UpdateMyFamilyMember)
+		me.DELETE("/family/:memberId", h.RemoveMyFamilyMember)
+		me.GET("/profile-changes", h.ListMyProfileChanges)
+		me.DELETE("/profile-changes/:changeId", h.CancelMyProfileChange)
 	}
 }
@@ -29,6 +29,7 @@ func InitRoutes(r *gin.Engine, h RouteHandler, authMiddleware gin.HandlerFunc) {
 		InitPublicEventRoutes(public, h)
 		InitPublicCheckInRoutes(public, h)
 		InitPublicCommunityRoutes(public, h)
+		InitPublicGivingRoutes(public, h)
 		public.POST("/contact", h.HandleContactForm)
 	}
 
@@ -39,12 +40,19 @@ func InitRoutes(r *gin.Engine, h RouteHandler, authMiddleware gin.HandlerFunc) {
 		InitUserRoutes(adminProtected, h)
 		InitCommunityRoutes(adminProtected, h)
 		InitMemberRoutes(adminProtected, h)
+		InitMemberSegmentRoutes(adminProtected, h)
+		InitMemberPipelineRoutes(adminProtected, h)
+		InitMemberProfileChangeRoutes(adminProtected, h)
+		InitLetterTemplateRoutes(adminProtected, h)
+		InitCelebrationRoutes(adminProtected, h)
 		InitFamilyRoutes(adminProtected, h)
 		InitGroupRoutes(adminProtected, h)
 		InitEventRoutes(adminProtected, h)
 		InitCheckInRoutes(adminProtected, h)
+		InitKidsCheckInRoutes(adminProtected, h)
 		InitCommunicationRoutes(adminProtected, h)
 		InitFinancialRoutes(adminProtected, h)
+		InitContributionRoutes(adminProtected, h)
 		InitDonationRoutes(adminProtected, h)
 	}
 
@@ -53,6 +61,8 @@ func InitRoutes(r *gin.Engine, h RouteHandler, authMiddleware gin.HandlerFunc) {
 	memberProtected.Use(middleware.MemberAuth(h.GetRepos(), h.GetLogger()))
 	{
 		InitEngagementRoutes(memberProtected, h)
+		InitMemberDonationRoutes(memberProtected, h)
+		InitMemberProfileRoutes(memberProtected, h)
 		// Outras rotas específicas do portal do membro
 	}
 
@@ -7,5 +7,9 @@ func InitWebhookRoutes(router *gin.RouterGroup, h RouteHandler) {
 	{
 		// Webhooks do ASAAS
 		webhooks.POST("/asaas/account-status", h.HandleAsaasAccountStatusWebhook)
+		webhooks.POST("/asaas/payments", h.HandleAsaasPaymentWebhook)
+
+		// Webhooks de cobrança dos demais provedores de pagamento
+		webhooks.POST("/payments/:provider", h.HandlePaymentWebhook)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/comunidade/backend/internal/config"
 	"github.com/comunidade/backend/internal/delivery/http/handler"
 	"github.com/comunidade/backend/internal/delivery/http/middleware"
 	"github.com/comunidade/backend/internal/repository"
@@ -18,9 +19,17 @@ type Server struct {
 	logger *zap.Logger
 }
 
-func NewServer(repos *repository.Repositories, logger *zap.Logger) *Server {
+func NewServer(cfg config.ServerConfig, repos *repository.Repositories, logger *zap.Logger) *Server {
 	router := gin.Default()
 
+	// O IP do cliente identifica os visitantes nos limites de requisições. Sem proxies
+	// configurados, X-Forwarded-For é ignorado e vale o endereço da conexão.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Error("erro ao configurar proxies confiáveis", zap.Error(err))
+		router.SetTrustedProxies(nil)
+	}
+	router.TrustedPlatform = cfg.ClientIPHeader
+
 	// Adiciona middleware CORS
 	router.Use(middleware.CORS())
 
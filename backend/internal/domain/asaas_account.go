@@ -31,7 +31,7 @@ type AsaasAccount struct {
 	Province      string    `json:"province" gorm:"not null"`
 	PostalCode    string    `json:"postal_code" gorm:"not null"`
 	BirthDate     string    `json:"birth_date" gorm:"not null"`
-	ApiKey        string    `json:"api_key"`
+	ApiKey        string    `json:"api_key" gorm:"serializer:secret"`
 	WalletId      string    `json:"wallet_id"`
 	Status        string    `json:"status" gorm:"not null;default:'pending'"`
 	AsaasID       string    `json:"asaas_id"`
@@ -53,7 +53,7 @@ type AsaasAccount struct {
 	PersonType      string `json:"person_type" gorm:"not null;default:'JURIDICA'"`      // JURIDICA, FISICA
 
 	// Webhooks
-	Webhooks []WebhookConfig `json:"webhooks" gorm:"type:jsonb"`
+	Webhooks []WebhookConfig `json:"webhooks" gorm:"type:jsonb;serializer:webhooks"`
 
 	Community *Community `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
 }
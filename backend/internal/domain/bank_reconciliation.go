@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BankStatementImport representa a importação de um extrato bancário (OFX) ou de
+// um arquivo de retorno CNAB 240 para conciliação com as despesas e receitas
+type BankStatementImport struct {
+	ID               string     `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID      string     `json:"community_id" gorm:"type:uuid;not null;index"`
+	UserID           string     `json:"user_id" gorm:"type:uuid;not null"`
+	Filename         string     `json:"filename" gorm:"not null"`
+	Format           string     `json:"format" gorm:"type:varchar(10);not null;check:format IN ('ofx', 'cnab240')"`
+	BankCode         string     `json:"bank_code" gorm:"type:varchar(10)"`
+	AccountNumber    string     `json:"account_number" gorm:"type:varchar(30)"`
+	StartDate        *time.Time `json:"start_date"`
+	EndDate          *time.Time `json:"end_date"`
+	TransactionCount int        `json:"transaction_count" gorm:"not null;default:0"`
+	DuplicateCount   int        `json:"duplicate_count" gorm:"not null;default:0"`
+	MatchedCount     int        `json:"matched_count" gorm:"not null;default:0"`
+	PendingCount     int        `json:"pending_count" gorm:"not null;default:0"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"not null"`
+
+	Community    *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+	User         *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Transactions []*BankTransaction `json:"transactions,omitempty" gorm:"foreignKey:ImportID;constraint:OnDelete:CASCADE"`
+}
+
+// BankTransaction representa um lançamento do extrato importado. Valores negativos
+// são débitos (conciliados com despesas) e positivos são créditos (conciliados com
+// receitas). Lançamentos sem correspondência ficam pendentes na fila de conciliação.
+type BankTransaction struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string     `json:"community_id" gorm:"type:uuid;not null;uniqueIndex:idx_bank_transactions_external"`
+	ImportID    string     `json:"import_id" gorm:"type:uuid;not null;index"`
+	ExternalID  string     `json:"external_id" gorm:"not null;uniqueIndex:idx_bank_transactions_external"`
+	Date        time.Time  `json:"date" gorm:"not null"`
+	Amount      float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Document    string     `json:"document" gorm:"type:varchar(14)"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';check:status IN ('pending', 'matched', 'ignored')"`
+	ExpenseID   *string    `json:"expense_id" gorm:"type:uuid"`
+	RevenueID   *string    `json:"revenue_id" gorm:"type:uuid"`
+	AutoMatched bool       `json:"auto_matched" gorm:"not null;default:false"`
+	ResolvedBy  *string    `json:"resolved_by" gorm:"type:uuid"`
+	ResolvedAt  *time.Time `json:"resolved_at"`
+	Notes       string     `json:"notes"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+
+	Import  *BankStatementImport `json:"import,omitempty" gorm:"foreignKey:ImportID"`
+	Expense *Expense             `json:"expense,omitempty" gorm:"foreignKey:ExpenseID"`
+	Revenue *Revenue             `json:"revenue,omitempty" gorm:"foreignKey:RevenueID"`
+}
+
+// IsDebit indica se o lançamento é uma saída da conta
+func (t *BankTransaction) IsDebit() bool {
+	return t.Amount < 0
+}
+
+func (i *BankStatementImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (t *BankTransaction) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
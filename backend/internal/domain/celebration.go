@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Datas comemorativas dos membros
+const (
+	CelebrationBirthday   = "birthday"   // Aniversário
+	CelebrationWedding    = "wedding"    // Aniversário de casamento
+	CelebrationBaptism    = "baptism"    // Aniversário de batismo
+	CelebrationMembership = "membership" // Aniversário de membresia
+)
+
+// CelebrationSettings configura o resumo semanal de aniversários enviado aos pastores e
+// líderes e as mensagens automáticas de felicitação enviadas aos membros. Os modelos são
+// CommunicationTemplate de e-mail; sem modelo, a data não recebe felicitação.
+type CelebrationSettings struct {
+	ID          string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string `json:"community_id" gorm:"type:uuid;not null;uniqueIndex"`
+
+	DigestEnabled bool `json:"digest_enabled" gorm:"default:false"`
+	// Dia da semana do envio (0 = domingo)
+	DigestWeekday int `json:"digest_weekday" gorm:"default:1;check:digest_weekday BETWEEN 0 AND 6"`
+	// Quantidade de dias à frente incluídos no resumo
+	DigestDays int `json:"digest_days" gorm:"default:7;check:digest_days BETWEEN 1 AND 31"`
+	// Envia o resumo também aos líderes, apenas com os membros dos seus grupos
+	NotifyGroupLeaders bool `json:"notify_group_leaders" gorm:"default:true"`
+	// Outros e-mails que recebem o resumo completo, separados por vírgula
+	DigestEmails string     `json:"digest_emails" gorm:"type:text"`
+	LastDigestAt *time.Time `json:"last_digest_at"`
+
+	GreetingsEnabled     bool    `json:"greetings_enabled" gorm:"default:false"`
+	BirthdayTemplateID   *string `json:"birthday_template_id"`
+	WeddingTemplateID    *string `json:"wedding_template_id"`
+	BaptismTemplateID    *string `json:"baptism_template_id"`
+	MembershipTemplateID *string `json:"membership_template_id"`
+
+	UpdatedBy *string   `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// TemplateID retorna o modelo de felicitação configurado para o tipo de data
+func (s *CelebrationSettings) TemplateID(kind string) *string {
+	switch kind {
+	case CelebrationBirthday:
+		return s.BirthdayTemplateID
+	case CelebrationWedding:
+		return s.WeddingTemplateID
+	case CelebrationBaptism:
+		return s.BaptismTemplateID
+	case CelebrationMembership:
+		return s.MembershipTemplateID
+	}
+	return nil
+}
+
+// CelebrationGreeting registra a felicitação enviada, para que cada membro receba no
+// máximo uma mensagem por data e por ano
+type CelebrationGreeting struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string    `json:"community_id" gorm:"type:uuid;not null;index"`
+	MemberID    string    `json:"member_id" gorm:"type:uuid;not null;uniqueIndex:idx_celebration_greeting"`
+	Kind        string    `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_celebration_greeting"`
+	Year        int       `json:"year" gorm:"not null;uniqueIndex:idx_celebration_greeting"`
+	SentAt      time.Time `json:"sent_at" gorm:"not null"`
+}
+
+func (s *CelebrationSettings) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (g *CelebrationGreeting) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	return nil
+}
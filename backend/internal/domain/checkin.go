@@ -19,8 +19,21 @@ type CheckIn struct {
 	CheckInAt time.Time `json:"check_in_at"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// O check-in de visitante é feito na rota pública e fica pendente até que um
+	// administrador o confirme, associando-o a um cadastro de membro, ou o descarte
+	VisitorStatus string     `json:"visitor_status,omitempty" gorm:"type:varchar(20);index"`
+	ReviewedBy    *string    `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
 }
 
+// Situações do check-in de visitante
+const (
+	CheckInVisitorPending   = "pending"
+	CheckInVisitorConfirmed = "confirmed"
+	CheckInVisitorDismissed = "dismissed"
+)
+
 type CheckInStats struct {
 	TotalCheckIns    int64 `json:"total_check_ins"`
 	MembersCheckIns  int64 `json:"members_check_ins"`
@@ -26,6 +26,8 @@ const (
 	RecipientTypeGroup  RecipientType = "group"
 	RecipientTypeFamily RecipientType = "family"
 	RecipientTypeCustom RecipientType = "custom"
+	// Membros de um segmento salvo (MemberSegment)
+	RecipientTypeSegment RecipientType = "segment"
 )
 
 // Comunicação
@@ -83,15 +85,15 @@ type CommunicationSettings struct {
 	EmailSMTPHost    string    `json:"email_smtp_host"`
 	EmailSMTPPort    int       `json:"email_smtp_port"`
 	EmailUsername    string    `json:"email_username"`
-	EmailPassword    string    `json:"email_password"`
+	EmailPassword    string    `json:"email_password" gorm:"serializer:secret"`
 	EmailFromName    string    `json:"email_from_name"`
 	EmailFromAddress string    `json:"email_from_address"`
 	SMSEnabled       bool      `json:"sms_enabled" gorm:"default:false"`
 	SMSProvider      string    `json:"sms_provider"`
-	SMSApiKey        string    `json:"sms_api_key"`
+	SMSApiKey        string    `json:"sms_api_key" gorm:"serializer:secret"`
 	WhatsAppEnabled  bool      `json:"whatsapp_enabled" gorm:"default:false"`
 	WhatsAppProvider string    `json:"whatsapp_provider"`
-	WhatsAppApiKey   string    `json:"whatsapp_api_key"`
+	WhatsAppApiKey   string    `json:"whatsapp_api_key" gorm:"serializer:secret"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
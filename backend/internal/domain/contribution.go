@@ -3,62 +3,91 @@ package domain
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Contribution struct {
-	ID          string         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	CommunityID string         `gorm:"type:uuid;not null" json:"community_id"`
-	MemberID    string         `gorm:"type:uuid;not null" json:"member_id"`
-	BatchID     string         `gorm:"type:uuid" json:"batch_id"`
-	Type        string         `gorm:"type:varchar(50);not null;check:type IN ('tithe', 'offering', 'donation')" json:"type"`
-	Amount      float64        `gorm:"type:decimal(10,2);not null" json:"amount"`
-	Currency    string         `gorm:"type:varchar(3);not null;default:'BRL'" json:"currency"`
-	Date        time.Time      `gorm:"type:date;not null" json:"date"`
-	Method      string         `gorm:"type:varchar(50);not null;check:method IN ('cash', 'check', 'credit_card', 'bank_transfer')" json:"method"`
-	Status      string         `gorm:"type:varchar(50);not null;default:'pending';check:status IN ('pending', 'completed', 'failed', 'refunded')" json:"status"`
-	Notes       string         `gorm:"type:text" json:"notes"`
-	CreatedAt   time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID             string         `gorm:"type:uuid;primaryKey" json:"id"`
+	CommunityID    string         `gorm:"type:uuid;not null" json:"community_id"`
+	MemberID       *string        `gorm:"type:uuid" json:"member_id"`
+	BatchID        string         `gorm:"type:uuid;index" json:"batch_id"`
+	UserID         string         `gorm:"type:uuid" json:"user_id"`
+	Type           string         `gorm:"type:varchar(50);not null;check:type IN ('tithe', 'offering', 'donation')" json:"type"`
+	Amount         float64        `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency       string         `gorm:"type:varchar(3);not null;default:'BRL'" json:"currency"`
+	Date           time.Time      `gorm:"type:date;not null" json:"date"`
+	Method         string         `gorm:"type:varchar(50);not null;check:method IN ('cash', 'check', 'credit_card', 'bank_transfer')" json:"method"`
+	Status         string         `gorm:"type:varchar(50);not null;default:'pending';check:status IN ('pending', 'completed', 'failed', 'refunded')" json:"status"`
+	EnvelopeNumber string         `gorm:"type:varchar(50)" json:"envelope_number"`
+	CheckNumber    string         `gorm:"type:varchar(50)" json:"check_number"`
+	Notes          string         `gorm:"type:text" json:"notes"`
+	CreatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relacionamentos
-	Community Community         `gorm:"foreignKey:CommunityID" json:"-"`
-	Member    Member            `gorm:"foreignKey:MemberID" json:"-"`
-	Batch     ContributionBatch `gorm:"foreignKey:BatchID" json:"-"`
+	Community Community          `gorm:"foreignKey:CommunityID" json:"-"`
+	Member    *Member            `gorm:"foreignKey:MemberID" json:"member,omitempty"`
+	Batch     *ContributionBatch `gorm:"foreignKey:BatchID" json:"-"`
 }
 
+// ContributionBatch representa um lote de contagem de ofertas (ex.: culto de domingo).
+// O lote é aberto, recebe os envelopes, é fechado por quem contou (counted) e só é
+// travado (closed) após a conferência de uma segunda pessoa.
 type ContributionBatch struct {
-	ID          string         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ID          string         `gorm:"type:uuid;primaryKey" json:"id"`
 	CommunityID string         `gorm:"type:uuid;not null" json:"community_id"`
+	CategoryID  string         `gorm:"type:uuid;not null" json:"category_id"`
+	EventID     *string        `gorm:"type:uuid" json:"event_id"`
 	Date        time.Time      `gorm:"type:date;not null" json:"date"`
+	Description string         `gorm:"type:varchar(255)" json:"description"`
 	Total       float64        `gorm:"type:decimal(10,2);not null;default:0" json:"total"`
 	Count       int            `gorm:"not null;default:0" json:"count"`
-	Status      string         `gorm:"type:varchar(50);not null;default:'open';check:status IN ('open', 'closed')" json:"status"`
+	Status      string         `gorm:"type:varchar(50);not null;default:'open';check:status IN ('open', 'counted', 'closed')" json:"status"`
 	Notes       string         `gorm:"type:text" json:"notes"`
+	OpenedBy    string         `gorm:"type:uuid" json:"opened_by"`
+	CountedBy   *string        `gorm:"type:uuid" json:"counted_by"`
+	CountedAt   *time.Time     `json:"counted_at"`
+	VerifiedBy  *string        `gorm:"type:uuid" json:"verified_by"`
+	ClosedAt    *time.Time     `json:"closed_at"`
 	CreatedAt   time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relacionamentos
-	Community     Community      `gorm:"foreignKey:CommunityID" json:"-"`
-	Contributions []Contribution `gorm:"foreignKey:BatchID" json:"-"`
+	Community     Community          `gorm:"foreignKey:CommunityID" json:"-"`
+	Category      *FinancialCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Contributions []Contribution     `gorm:"foreignKey:BatchID" json:"contributions,omitempty"`
+	Revenues      []Revenue          `gorm:"foreignKey:ContributionBatchID" json:"revenues,omitempty"`
 }
 
 func (c *Contribution) BeforeCreate(tx *gorm.DB) error {
 	if c.ID == "" {
-		tx.Statement.SetColumn("ID", tx.Statement.Context.Value("uuid").(string))
+		c.ID = uuid.New().String()
 	}
 	return nil
 }
 
 func (c *ContributionBatch) BeforeCreate(tx *gorm.DB) error {
 	if c.ID == "" {
-		tx.Statement.SetColumn("ID", tx.Statement.Context.Value("uuid").(string))
+		c.ID = uuid.New().String()
 	}
 	return nil
 }
 
+func (c *ContributionBatch) IsOpen() bool {
+	return c.Status == "open"
+}
+
+func (c *ContributionBatch) IsCounted() bool {
+	return c.Status == "counted"
+}
+
+func (c *ContributionBatch) IsClosed() bool {
+	return c.Status == "closed"
+}
+
 func (c *Contribution) IsPending() bool {
 	return c.Status == "pending"
 }
@@ -3,19 +3,40 @@ package domain
 import (
 	"time"
 
+	"github.com/comunidade/backend/pkg/secret"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // AsaasConfig armazena as configurações da integração Asaas para cada comunidade
 type AsaasConfig struct {
-	ID           string    `json:"id" gorm:"primaryKey;type:uuid"`
-	CommunityID  string    `json:"community_id" gorm:"type:uuid;not null"`
-	ApiKey       string    `json:"api_key"`
-	ApiEndpoint  string    `json:"api_endpoint"`
-	WebhookToken string    `json:"webhook_token"`
-	CreatedAt    time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"not null"`
+	ID           string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID  string `json:"community_id" gorm:"type:uuid;not null"`
+	ApiKey       string `json:"api_key" gorm:"serializer:secret"`
+	ApiEndpoint  string `json:"api_endpoint"`
+	WebhookToken string `json:"webhook_token" gorm:"serializer:secret"`
+	// WebhookTokenDigest identifica a configuração pelo token recebido no webhook
+	WebhookTokenDigest string    `json:"-" gorm:"type:varchar(64);index"`
+	CreatedAt          time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"not null"`
+
+	Community *Community `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+}
+
+// PaymentGatewaySettings define o provedor de pagamentos usado pela comunidade. Sem
+// configuração, as cobranças são feitas pelo ASAAS.
+type PaymentGatewaySettings struct {
+	ID           string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID  string `json:"community_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Provider     string `json:"provider" gorm:"type:varchar(20);not null;default:'asaas'"`
+	PixKey       string `json:"pix_key" gorm:"type:varchar(77)"`
+	MerchantName string `json:"merchant_name" gorm:"type:varchar(25)"`
+	MerchantCity string `json:"merchant_city" gorm:"type:varchar(15)"`
+	WebhookToken string `json:"webhook_token" gorm:"type:text;serializer:secret"`
+	// WebhookTokenDigest identifica a comunidade pelo token recebido no webhook
+	WebhookTokenDigest string    `json:"-" gorm:"type:varchar(64);index"`
+	CreatedAt          time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"not null"`
 
 	Community *Community `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
 }
@@ -32,6 +53,7 @@ type Campaign struct {
 	EndDate     *time.Time `json:"end_date,omitempty"`
 	EventID     *string    `json:"event_id,omitempty" gorm:"type:uuid"`
 	Status      string     `json:"status" gorm:"not null;default:'active';check:status IN ('active', 'completed', 'cancelled')"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
 	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
 
@@ -43,7 +65,7 @@ type Campaign struct {
 // Donation representa uma doação única ou recorrente
 type Donation struct {
 	ID             string     `json:"id" gorm:"primaryKey;type:uuid"`
-	CommunityID    string     `json:"community_id" gorm:"type:uuid;not null"`
+	CommunityID    string     `json:"community_id" gorm:"type:uuid;not null;uniqueIndex:idx_donations_charge,where:asaas_id <> ''"`
 	UserID         string     `json:"user_id" gorm:"type:uuid;not null"`
 	MemberID       *string    `json:"member_id" gorm:"type:uuid"`
 	CampaignID     string     `json:"campaign_id" gorm:"type:uuid"`
@@ -51,14 +73,25 @@ type Donation struct {
 	PaymentMethod  string     `json:"payment_method" gorm:"not null;check:payment_method IN ('credit_card', 'boleto', 'pix')"`
 	DueDate        time.Time  `json:"due_date" gorm:"not null"`
 	Description    string     `json:"description"`
-	Status         string     `json:"status" gorm:"not null;default:'pending';check:status IN ('pending', 'paid', 'cancelled', 'failed')"`
-	AsaasID        string     `json:"asaas_id"`
+	Status         string     `json:"status" gorm:"not null;default:'pending';check:chk_donations_refund_status,status IN ('pending', 'paid', 'cancelled', 'failed', 'refunded', 'disputed')"`
+	AsaasID        string     `json:"asaas_id" gorm:"uniqueIndex:idx_donations_charge"`
+	Gateway        string     `json:"gateway" gorm:"type:varchar(20);not null;default:'asaas'"`
 	PaidAt         *time.Time `json:"paid_at"`
 	CreatedAt      time.Time  `json:"created_at" gorm:"not null"`
 	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
 	AsaasPaymentID string     `json:"asaas_payment_id" gorm:"type:varchar(100)"`
 	PaymentLink    string     `json:"payment_link" gorm:"type:varchar(255)"`
 
+	// Estornos: RefundedAmount acumula os reembolsos parciais; no reembolso total a
+	// doação passa para refunded. DisputedAt marca a contestação do pagamento (chargeback).
+	RefundedAmount float64    `json:"refunded_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	RefundedAt     *time.Time `json:"refunded_at,omitempty"`
+	RefundReason   string     `json:"refund_reason,omitempty" gorm:"type:varchar(255)"`
+	DisputedAt     *time.Time `json:"disputed_at,omitempty"`
+
+	// RecurringDonationID vincula as cobranças geradas por uma assinatura à doação recorrente
+	RecurringDonationID *string `json:"recurring_donation_id,omitempty" gorm:"type:uuid;index"`
+
 	CustomerName  string `json:"customer_name" gorm:"not null"`
 	CustomerCPF   string `json:"customer_cpf" gorm:"not null"`
 	CustomerEmail string `json:"customer_email" gorm:"not null"`
@@ -78,6 +111,8 @@ type Donation struct {
 	User      *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Member    *Member    `json:"member,omitempty" gorm:"foreignKey:MemberID"`
 	Campaign  *Campaign  `json:"campaign,omitempty" gorm:"foreignKey:CampaignID"`
+
+	RecurringDonation *RecurringDonation `json:"recurring_donation,omitempty" gorm:"foreignKey:RecurringDonationID"`
 }
 
 // RecurringDonation representa uma doação recorrente (assinatura)
@@ -91,12 +126,19 @@ type RecurringDonation struct {
 	PaymentMethod string     `json:"payment_method" gorm:"not null;check:payment_method IN ('credit_card')"`
 	DueDay        int        `json:"due_day" gorm:"not null"`
 	Description   string     `json:"description"`
-	Status        string     `json:"status" gorm:"not null;default:'active';check:status IN ('active', 'cancelled', 'failed')"`
+	Status        string     `json:"status" gorm:"not null;default:'active';check:chk_recurring_donations_lifecycle_status,status IN ('active', 'paused', 'cancelled', 'failed')"`
 	AsaasID       string     `json:"asaas_id"`
+	Gateway       string     `json:"gateway" gorm:"type:varchar(20);not null;default:'asaas'"`
 	NextDueDate   *time.Time `json:"next_due_date"`
+	PausedAt      *time.Time `json:"paused_at,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
 	CreatedAt     time.Time  `json:"created_at" gorm:"not null"`
 	UpdatedAt     time.Time  `json:"updated_at" gorm:"not null"`
 
+	// Dados do cartão informados na última atualização, para exibição
+	CardBrand      string `json:"card_brand,omitempty" gorm:"type:varchar(30)"`
+	CardLastDigits string `json:"card_last_digits,omitempty" gorm:"type:varchar(4)"`
+
 	CustomerName  string `json:"customer_name" gorm:"not null"`
 	CustomerCPF   string `json:"customer_cpf" gorm:"not null"`
 	CustomerEmail string `json:"customer_email" gorm:"not null"`
@@ -133,6 +175,11 @@ func (d *Donation) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// NetAmount é o valor da doação descontados os reembolsos
+func (d *Donation) NetAmount() float64 {
+	return d.Amount - d.RefundedAmount
+}
+
 func (r *RecurringDonation) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == "" {
 		r.ID = uuid.New().String()
@@ -146,3 +193,23 @@ func (a *AsaasConfig) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (p *PaymentGatewaySettings) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// O token dos webhooks é gravado cifrado; o resumo gravado junto permite encontrar a
+// configuração pelo token recebido
+
+func (a *AsaasConfig) BeforeSave(tx *gorm.DB) error {
+	a.WebhookTokenDigest = secret.Digest(a.WebhookToken)
+	return nil
+}
+
+func (p *PaymentGatewaySettings) BeforeSave(tx *gorm.DB) error {
+	p.WebhookTokenDigest = secret.Digest(p.WebhookToken)
+	return nil
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ExpenseApprovalRule define uma etapa de aprovação para as despesas a partir de um
+// valor mínimo, opcionalmente restrita a uma categoria. As regras que se aplicam a
+// uma despesa são aprovadas em sequência, na ordem de Step.
+type ExpenseApprovalRule struct {
+	ID             string         `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID    string         `json:"community_id" gorm:"type:uuid;not null;index"`
+	Name           string         `json:"name" gorm:"not null"`
+	Description    string         `json:"description"`
+	Step           int            `json:"step" gorm:"not null;default:1"`
+	MinAmount      float64        `json:"min_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	CategoryID     *string        `json:"category_id" gorm:"type:uuid"`
+	ApproverIDs    pq.StringArray `json:"approver_ids" gorm:"type:text[]"`
+	RequireReceipt bool           `json:"require_receipt" gorm:"not null;default:false"`
+	Active         bool           `json:"active" gorm:"not null;default:true"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"not null"`
+
+	Category *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+// ExpenseApproval registra a decisão de um aprovador em uma etapa da despesa
+type ExpenseApproval struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid"`
+	ExpenseID string    `json:"expense_id" gorm:"type:uuid;not null;index"`
+	RuleID    *string   `json:"rule_id" gorm:"type:uuid"`
+	Step      int       `json:"step" gorm:"not null"`
+	UserID    string    `json:"user_id" gorm:"type:uuid;not null"`
+	Decision  string    `json:"decision" gorm:"type:varchar(10);not null;check:decision IN ('approved', 'rejected')"`
+	Amount    float64   `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+
+	User *User                `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Rule *ExpenseApprovalRule `json:"rule,omitempty" gorm:"foreignKey:RuleID"`
+}
+
+// ExpenseReceipt é um comprovante (nota fiscal, recibo) anexado à despesa
+type ExpenseReceipt struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	ExpenseID   string    `json:"expense_id" gorm:"type:uuid;not null;index"`
+	CommunityID string    `json:"community_id" gorm:"type:uuid;not null"`
+	UserID      string    `json:"user_id" gorm:"type:uuid;not null"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	Path        string    `json:"path" gorm:"not null"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
+}
+
+func (r *ExpenseApprovalRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (a *ExpenseApproval) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (r *ExpenseReceipt) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
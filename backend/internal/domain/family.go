@@ -5,13 +5,23 @@ import (
 )
 
 type Family struct {
-	ID           string    `json:"id" gorm:"primaryKey;type:uuid" db:"id"`
-	CommunityID  string    `json:"community_id" gorm:"type:uuid;not null" db:"community_id"`
-	Name         string    `json:"name" gorm:"not null" db:"name"`                      // Nome da família (ex: "Família Silva")
-	Description  string    `json:"description" gorm:"type:text" db:"description"`       // Descrição ou notas sobre a família
-	HeadOfFamily string    `json:"head_of_family" gorm:"type:uuid" db:"head_of_family"` // ID do membro que é o chefe da família
-	CreatedAt    time.Time `json:"created_at" gorm:"not null" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"not null" db:"updated_at"`
+	ID           string `json:"id" gorm:"primaryKey;type:uuid" db:"id"`
+	CommunityID  string `json:"community_id" gorm:"type:uuid;not null" db:"community_id"`
+	Name         string `json:"name" gorm:"not null" db:"name"`                      // Nome da família (ex: "Família Silva")
+	Description  string `json:"description" gorm:"type:text" db:"description"`       // Descrição ou notas sobre a família
+	HeadOfFamily string `json:"head_of_family" gorm:"type:uuid" db:"head_of_family"` // ID do membro que é o chefe da família
+
+	// Endereço da casa, usado na correspondência enviada à família
+	Address      string `json:"address" gorm:"type:text" db:"address"`
+	Number       string `json:"number" gorm:"type:varchar(20)" db:"number"`
+	Neighborhood string `json:"neighborhood" gorm:"type:varchar(100)" db:"neighborhood"`
+	City         string `json:"city" gorm:"type:varchar(100)" db:"city"`
+	State        string `json:"state" gorm:"type:varchar(100)" db:"state"`
+	Country      string `json:"country" gorm:"type:varchar(100)" db:"country"`
+	ZipCode      string `json:"zip_code" gorm:"type:varchar(20)" db:"zip_code"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"not null" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null" db:"updated_at"`
 }
 
 type FamilyMember struct {
@@ -23,8 +33,9 @@ type FamilyMember struct {
 	UpdatedAt time.Time `json:"updated_at" gorm:"not null" db:"updated_at"`
 }
 
-// Roles possíveis para membros da família
+// Roles possíveis para membros da família. Os papéis são relativos ao chefe da família.
 const (
+	FamilyRoleHead        = "head"         // Chefe da família
 	FamilyRoleSpouse      = "spouse"       // Cônjuge
 	FamilyRoleChild       = "child"        // Filho(a)
 	FamilyRoleSibling     = "sibling"      // Irmão(ã)
@@ -36,3 +47,8 @@ const (
 	FamilyRoleCousin      = "cousin"       // Primo(a)
 	FamilyRoleOther       = "other"        // Outro
 )
+
+// HasAddress indica se a família tem endereço próprio cadastrado
+func (f *Family) HasAddress() bool {
+	return f.Address != "" || f.ZipCode != ""
+}
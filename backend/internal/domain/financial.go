@@ -54,43 +54,159 @@ type Expense struct {
 	CategoryID  string     `json:"category_id" gorm:"type:uuid;not null"`
 	SupplierID  *string    `json:"supplier_id" gorm:"type:uuid"`
 	EventID     *string    `json:"event_id" gorm:"type:uuid"`
+	GroupID     *string    `json:"group_id" gorm:"type:uuid;index"`
 	Amount      float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
 	Date        time.Time  `json:"date" gorm:"not null"`
 	Description string     `json:"description"`
-	Status      string     `json:"status" gorm:"not null;default:'pending';check:status IN ('pending', 'paid', 'cancelled')"`
+	Status      string     `json:"status" gorm:"not null;default:'pending';check:chk_expenses_approval_status,status IN ('requested', 'approved', 'rejected', 'pending', 'paid', 'cancelled')"`
 	PaymentType string     `json:"payment_type" gorm:"type:varchar(50)"`
-	DueDate     time.Time  `json:"due_date"`
+	DueDate     time.Time  `json:"due_date" gorm:"uniqueIndex:idx_expenses_recurrence,priority:2"`
 	PaidAt      *time.Time `json:"paid_at"`
 	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
 	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
 
+	// Despesa gerada por um modelo de despesa recorrente
+	RecurringExpenseID *string    `json:"recurring_expense_id" gorm:"type:uuid;uniqueIndex:idx_expenses_recurrence,priority:1"`
+	ReminderSentAt     *time.Time `json:"reminder_sent_at"`
+
+	// Fluxo de aprovação: despesas que atingem uma regra de aprovação passam por
+	// requested → approved → paid; as demais seguem direto de pending para paid
+	ApprovalSteps  int        `json:"approval_steps" gorm:"not null;default:0"`
+	ApprovalStep   int        `json:"approval_step" gorm:"not null;default:0"`
+	RequestedAt    *time.Time `json:"requested_at"`
+	ApprovedBy     *string    `json:"approved_by" gorm:"type:uuid"`
+	ApprovedAt     *time.Time `json:"approved_at"`
+	ApprovedAmount float64    `json:"approved_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PaidBy         *string    `json:"paid_by" gorm:"type:uuid"`
+
+	Community        *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+	User             *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Category         *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Supplier         *Supplier          `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	Event            *Event             `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	Group            *Group             `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+	RecurringExpense *RecurringExpense  `json:"recurring_expense,omitempty" gorm:"foreignKey:RecurringExpenseID"`
+	Receipts         []*ExpenseReceipt  `json:"receipts,omitempty" gorm:"foreignKey:ExpenseID"`
+	Approvals        []*ExpenseApproval `json:"approvals,omitempty" gorm:"foreignKey:ExpenseID"`
+}
+
+// RecurringExpense é o modelo de uma despesa que se repete (aluguel, contas de
+// consumo, salários). As ocorrências são geradas como despesas pendentes com
+// antecedência de LeadDays dias, a partir de StartDate e até EndDate, se informada.
+type RecurringExpense struct {
+	ID             string     `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID    string     `json:"community_id" gorm:"type:uuid;not null;index"`
+	UserID         string     `json:"user_id" gorm:"type:uuid;not null"`
+	CategoryID     string     `json:"category_id" gorm:"type:uuid;not null"`
+	SupplierID     *string    `json:"supplier_id" gorm:"type:uuid"`
+	GroupID        *string    `json:"group_id" gorm:"type:uuid"`
+	Amount         float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Description    string     `json:"description" gorm:"not null"`
+	PaymentType    string     `json:"payment_type" gorm:"type:varchar(50)"`
+	Frequency      string     `json:"frequency" gorm:"type:varchar(10);not null;check:frequency IN ('weekly', 'monthly', 'yearly')"`
+	Interval       int        `json:"interval" gorm:"not null;default:1"`
+	StartDate      time.Time  `json:"start_date" gorm:"not null"`
+	EndDate        *time.Time `json:"end_date"`
+	LeadDays       int        `json:"lead_days" gorm:"not null;default:30"`
+	ReminderDays   int        `json:"reminder_days" gorm:"not null;default:3"`
+	NotifyEmails   string     `json:"notify_emails"`
+	Active         bool       `json:"active" gorm:"not null;default:true"`
+	GeneratedCount int        `json:"generated_count" gorm:"not null;default:0"`
+	LastDueDate    *time.Time `json:"last_due_date"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
+
 	Community *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
 	User      *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Category  *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
 	Supplier  *Supplier          `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
-	Event     *Event             `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	Group     *Group             `json:"group,omitempty" gorm:"foreignKey:GroupID"`
 }
 
 // Revenue representa uma receita
 type Revenue struct {
-	ID          string     `json:"id" gorm:"primaryKey;type:uuid"`
-	CommunityID string     `json:"community_id" gorm:"type:uuid;not null"`
-	UserID      string     `json:"user_id" gorm:"type:uuid;not null"`
-	CategoryID  string     `json:"category_id" gorm:"type:uuid;not null"`
-	EventID     *string    `json:"event_id" gorm:"type:uuid"`
-	Amount      float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
-	Date        time.Time  `json:"date" gorm:"not null"`
-	Description string     `json:"description"`
-	Status      string     `json:"status" gorm:"not null;default:'pending';check:status IN ('pending', 'received', 'cancelled')"`
-	PaymentType string     `json:"payment_type" gorm:"type:varchar(50)"`
-	ReceivedAt  *time.Time `json:"received_at"`
-	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
-	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+	ID                  string     `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID         string     `json:"community_id" gorm:"type:uuid;not null"`
+	UserID              string     `json:"user_id" gorm:"type:uuid;not null"`
+	CategoryID          string     `json:"category_id" gorm:"type:uuid;not null"`
+	EventID             *string    `json:"event_id" gorm:"type:uuid"`
+	GroupID             *string    `json:"group_id" gorm:"type:uuid;index"`
+	Amount              float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Date                time.Time  `json:"date" gorm:"not null"`
+	Description         string     `json:"description"`
+	Status              string     `json:"status" gorm:"not null;default:'pending';check:status IN ('pending', 'received', 'cancelled')"`
+	PaymentType         string     `json:"payment_type" gorm:"type:varchar(50);uniqueIndex:idx_revenues_contribution_batch_payment"`
+	ReceivedAt          *time.Time `json:"received_at"`
+	DonationID          *string    `json:"donation_id,omitempty" gorm:"type:uuid;uniqueIndex"`
+	ContributionBatchID *string    `json:"contribution_batch_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_revenues_contribution_batch_payment"`
+	CreatedAt           time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"not null"`
 
 	Community *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
 	User      *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Category  *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
 	Event     *Event             `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	Group     *Group             `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
+
+// Budget representa o orçamento de uma categoria financeira em um ano, opcionalmente
+// restrito a um ministério (grupo). Orçamentos mensais valem para cada mês do ano;
+// orçamentos anuais para o ano inteiro. Em categorias de despesa, um alerta é enviado
+// quando o realizado atinge o percentual configurado e quando ultrapassa o orçamento.
+type Budget struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID    string    `json:"community_id" gorm:"type:uuid;not null;index"`
+	UserID         string    `json:"user_id" gorm:"type:uuid;not null"`
+	CategoryID     string    `json:"category_id" gorm:"type:uuid;not null"`
+	GroupID        *string   `json:"group_id" gorm:"type:uuid"`
+	Period         string    `json:"period" gorm:"type:varchar(10);not null;check:period IN ('monthly', 'annual')"`
+	Year           int       `json:"year" gorm:"not null"`
+	Amount         float64   `json:"amount" gorm:"type:decimal(10,2);not null"`
+	AlertThreshold float64   `json:"alert_threshold" gorm:"type:decimal(5,2);not null;default:80"`
+	NotifyEmails   string    `json:"notify_emails"`
+	Notes          string    `json:"notes"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+
+	Community *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+	User      *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Category  *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Group     *Group             `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
+
+// BudgetAlert registra um alerta de orçamento enviado. Cada nível (percentual
+// configurado ou 100%) é enviado uma única vez por período do orçamento.
+type BudgetAlert struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:uuid"`
+	BudgetID        string    `json:"budget_id" gorm:"type:uuid;not null;uniqueIndex:idx_budget_alerts_level"`
+	PeriodStart     time.Time `json:"period_start" gorm:"not null;uniqueIndex:idx_budget_alerts_level"`
+	Level           float64   `json:"level" gorm:"type:decimal(5,2);not null;uniqueIndex:idx_budget_alerts_level"`
+	Budgeted        float64   `json:"budgeted" gorm:"type:decimal(10,2);not null"`
+	Actual          float64   `json:"actual" gorm:"type:decimal(10,2);not null"`
+	Percent         float64   `json:"percent" gorm:"type:decimal(7,2);not null"`
+	CommunicationID *string   `json:"communication_id"`
+	CreatedAt       time.Time `json:"created_at" gorm:"not null"`
+
+	Budget *Budget `json:"budget,omitempty" gorm:"foreignKey:BudgetID;constraint:OnDelete:CASCADE"`
+}
+
+// DonationPostingRule define a categoria financeira usada no lançamento automático
+// de doações pagas. Regras por campanha têm prioridade sobre regras por método de
+// pagamento; uma regra sem campanha e sem método é usada como padrão.
+type DonationPostingRule struct {
+	ID            string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID   string    `json:"community_id" gorm:"type:uuid;not null"`
+	UserID        string    `json:"user_id" gorm:"type:uuid;not null"`
+	CategoryID    string    `json:"category_id" gorm:"type:uuid;not null"`
+	CampaignID    *string   `json:"campaign_id" gorm:"type:uuid"`
+	PaymentMethod string    `json:"payment_method" gorm:"type:varchar(20)"`
+	CreatedAt     time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"not null"`
+
+	Community *Community         `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+	User      *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Category  *FinancialCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Campaign  *Campaign          `json:"campaign,omitempty" gorm:"foreignKey:CampaignID"`
 }
 
 // FinancialReport representa um relatório financeiro
@@ -107,8 +223,36 @@ type FinancialReport struct {
 	CreatedAt    time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"not null"`
 
-	Community *Community `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
-	User      *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	// Comparação com o período anterior de mesma duração
+	PreviousStartDate *time.Time `json:"previous_start_date"`
+	PreviousEndDate   *time.Time `json:"previous_end_date"`
+	PreviousRevenue   float64    `json:"previous_revenue" gorm:"type:decimal(10,2);default:0"`
+	PreviousExpense   float64    `json:"previous_expense" gorm:"type:decimal(10,2);default:0"`
+	PreviousBalance   float64    `json:"previous_balance" gorm:"type:decimal(10,2);default:0"`
+	RevenueVariation  *float64   `json:"revenue_variation"`
+	ExpenseVariation  *float64   `json:"expense_variation"`
+
+	Community *Community             `json:"community,omitempty" gorm:"foreignKey:CommunityID"`
+	User      *User                  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Items     []*FinancialReportItem `json:"items,omitempty" gorm:"foreignKey:ReportID;constraint:OnDelete:CASCADE"`
+}
+
+// FinancialReportItem representa uma linha do detalhamento do relatório financeiro,
+// agrupada por categoria, evento, fornecedor ou mês, com o valor do período anterior
+type FinancialReportItem struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:uuid"`
+	ReportID       string    `json:"report_id" gorm:"type:uuid;not null;index"`
+	Dimension      string    `json:"dimension" gorm:"not null;check:dimension IN ('category', 'event', 'supplier', 'month')"`
+	Type           string    `json:"type" gorm:"not null;check:type IN ('revenue', 'expense')"`
+	ReferenceID    *string   `json:"reference_id" gorm:"type:uuid"`
+	Month          string    `json:"month,omitempty" gorm:"type:varchar(7)"`
+	Label          string    `json:"label"`
+	Amount         float64   `json:"amount" gorm:"type:decimal(10,2);not null;default:0"`
+	Count          int       `json:"count" gorm:"not null;default:0"`
+	PreviousAmount float64   `json:"previous_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PreviousCount  int       `json:"previous_count" gorm:"not null;default:0"`
+	Variation      *float64  `json:"variation"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
 }
 
 // BeforeCreate - Hooks para geração de IDs
@@ -133,6 +277,13 @@ func (e *Expense) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (r *RecurringExpense) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
 func (r *Revenue) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == "" {
 		r.ID = uuid.New().String()
@@ -140,9 +291,37 @@ func (r *Revenue) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (b *Budget) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (b *BudgetAlert) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (d *DonationPostingRule) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
 func (fr *FinancialReport) BeforeCreate(tx *gorm.DB) error {
 	if fr.ID == "" {
 		fr.ID = uuid.New().String()
 	}
 	return nil
 }
+
+func (fi *FinancialReportItem) BeforeCreate(tx *gorm.DB) error {
+	if fi.ID == "" {
+		fi.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GivingStatementBatch registra o envio em massa dos comprovantes anuais de
+// contribuição. Os comprovantes são gerados e enviados em segundo plano; o andamento
+// de cada membro fica nos destinatários da comunicação do envio.
+type GivingStatementBatch struct {
+	ID              string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID     string `json:"community_id" gorm:"type:uuid;not null;index"`
+	CommunicationID string `json:"communication_id" gorm:"not null"`
+	Year            int    `json:"year" gorm:"not null"`
+	Status          string `json:"status" gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending', 'completed')"`
+	// Rodadas de envio já feitas; os envios que falharam são repetidos nas rodadas seguintes
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	CreatedBy   string     `json:"created_by" gorm:"type:uuid;not null"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// Situações do envio dos comprovantes
+const (
+	GivingStatementBatchPending   = "pending"
+	GivingStatementBatchCompleted = "completed"
+)
+
+func (b *GivingStatementBatch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
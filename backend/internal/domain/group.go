@@ -34,6 +34,8 @@ type Group struct {
 	AllowSelfJoin       bool `json:"allow_self_join" gorm:"default:true"`
 	NotifyOnJoinRequest bool `json:"notify_on_join_request" gorm:"default:true"`
 	NotifyOnNewMember   bool `json:"notify_on_new_member" gorm:"default:true"`
+	// Sala do ministério infantil, usada na distribuição das crianças no check-in
+	IsClassroom bool `json:"is_classroom" gorm:"default:false"`
 
 	// Estatísticas
 	MemberCount       int     `json:"member_count" gorm:"default:0"`
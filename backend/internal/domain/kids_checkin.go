@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChildCheckIn registra a entrada de uma criança no ministério infantil durante um evento.
+// A criança e o responsável recebem etiquetas com o mesmo código de segurança, exigido
+// na retirada. Alergias e observações médicas são copiadas do cadastro no momento do
+// check-in para que a etiqueta e a lista da sala mostrem o que foi informado na entrada.
+type ChildCheckIn struct {
+	ID          string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string `json:"community_id" gorm:"type:uuid;not null;index"`
+	EventID     string `json:"event_id" gorm:"type:uuid;not null;index:idx_child_check_in_code"`
+	// Presença registrada na lista de check-ins do evento
+	CheckInID *uint  `json:"check_in_id"`
+	ChildID   string `json:"child_id" gorm:"type:uuid;not null;index"`
+
+	// Responsável que trouxe a criança
+	GuardianID    *string `json:"guardian_id" gorm:"type:uuid"`
+	GuardianName  string  `json:"guardian_name" gorm:"type:varchar(100);not null"`
+	GuardianPhone string  `json:"guardian_phone" gorm:"type:varchar(20)"`
+
+	ClassroomID  *string `json:"classroom_id" gorm:"type:uuid"`
+	SecurityCode string  `json:"security_code" gorm:"type:varchar(8);not null;index:idx_child_check_in_code"`
+	Allergies    string  `json:"allergies" gorm:"type:text"`
+	MedicalNotes string  `json:"medical_notes" gorm:"type:text"`
+
+	CheckedInAt  time.Time  `json:"checked_in_at" gorm:"not null"`
+	CheckedOutAt *time.Time `json:"checked_out_at"`
+	// Usuário que registrou a retirada e nome de quem retirou a criança
+	CheckedOutBy *string `json:"checked_out_by" gorm:"type:uuid"`
+	PickedUpBy   string  `json:"picked_up_by" gorm:"type:varchar(100)"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+
+	Child     *Member `json:"child,omitempty" gorm:"foreignKey:ChildID"`
+	Classroom *Group  `json:"classroom,omitempty" gorm:"foreignKey:ClassroomID"`
+}
+
+// IsCheckedOut indica se a criança já foi retirada
+func (c *ChildCheckIn) IsCheckedOut() bool {
+	return c.CheckedOutAt != nil
+}
+
+func (c *ChildCheckIn) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
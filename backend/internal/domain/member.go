@@ -37,6 +37,8 @@ type Member struct {
 	Notes              string     `json:"notes" gorm:"type:text"`
 	EmergencyContact   string     `json:"emergency_contact" gorm:"type:varchar(100)"`
 	EmergencyPhone     string     `json:"emergency_phone" gorm:"type:varchar(20)"`
+	Allergies          string     `json:"allergies" gorm:"type:text"`
+	MedicalNotes       string     `json:"medical_notes" gorm:"type:text"`
 	AsaasCustomerID    string     `json:"asaas_customer_id" gorm:"type:varchar(100)"`
 	CreatedAt          time.Time  `json:"created_at" gorm:"not null"`
 	UpdatedAt          time.Time  `json:"updated_at" gorm:"not null"`
@@ -65,6 +67,8 @@ type Member struct {
 	FamilyID *string `json:"family_id" gorm:"type:uuid"`
 	SpouseID *string `json:"spouse_id" gorm:"type:uuid"`
 	ParentID *string `json:"parent_id" gorm:"type:uuid"`
+	// Data de casamento, usada nos aniversários de casamento
+	WeddingDate *time.Time `json:"wedding_date" gorm:"type:date"`
 
 	// Campos de batismo e membresia
 	BaptismDate     *time.Time `json:"baptism_date"`
@@ -76,6 +80,10 @@ type Member struct {
 	TransferredTo   string     `json:"transferred_to" gorm:"type:varchar(255)"`
 	TransferDate    *time.Time `json:"transfer_date"`
 
+	// Etapa atual no caminho de integração (PipelineStage)
+	PipelineStageID *string    `json:"pipeline_stage_id" gorm:"type:uuid;index"`
+	StageEnteredAt  *time.Time `json:"stage_entered_at"`
+
 	// Campos de comunicação
 	NotifyByEmail            bool `json:"notify_by_email" gorm:"default:true"`
 	NotifyByPhone            bool `json:"notify_by_phone" gorm:"default:false"`
@@ -172,15 +180,20 @@ func (m *Member) HasContributed() bool {
 }
 
 func (m *Member) Age() int {
+	return m.AgeAt(time.Now())
+}
+
+// AgeAt retorna a idade do membro na data informada
+func (m *Member) AgeAt(date time.Time) int {
 	if m.BirthDate.IsZero() {
 		return 0
 	}
 
-	now := time.Now()
-	age := now.Year() - m.BirthDate.Year()
+	age := date.Year() - m.BirthDate.Year()
 
 	// Ajusta a idade se ainda não fez aniversário este ano
-	if now.YearDay() < m.BirthDate.YearDay() {
+	if date.Month() < m.BirthDate.Month() ||
+		(date.Month() == m.BirthDate.Month() && date.Day() < m.BirthDate.Day()) {
 		age--
 	}
 
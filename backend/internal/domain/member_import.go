@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemberImport registra a importação de membros a partir de uma planilha CSV ou XLSX.
+// As linhas são processadas em segundo plano; as que não puderam ser importadas ficam
+// no arquivo de erros, com o motivo de cada uma.
+type MemberImport struct {
+	ID            string            `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID   string            `json:"community_id" gorm:"type:uuid;not null;index"`
+	UserID        string            `json:"user_id" gorm:"type:uuid;not null"`
+	Filename      string            `json:"filename" gorm:"not null"`
+	Format        string            `json:"format" gorm:"type:varchar(10);not null;check:format IN ('csv', 'xlsx')"`
+	Status        string            `json:"status" gorm:"type:varchar(20);not null;default:'processing';check:status IN ('processing', 'completed', 'failed')"`
+	Mapping       map[string]string `json:"mapping" gorm:"type:jsonb;serializer:json"`
+	OnDuplicate   string            `json:"on_duplicate" gorm:"type:varchar(10);not null;default:'skip';check:on_duplicate IN ('skip', 'update')"`
+	TotalRows     int               `json:"total_rows" gorm:"not null;default:0"`
+	ProcessedRows int               `json:"processed_rows" gorm:"not null;default:0"`
+	CreatedCount  int               `json:"created_count" gorm:"not null;default:0"`
+	UpdatedCount  int               `json:"updated_count" gorm:"not null;default:0"`
+	SkippedCount  int               `json:"skipped_count" gorm:"not null;default:0"`
+	ErrorCount    int               `json:"error_count" gorm:"not null;default:0"`
+	FamilyCount   int               `json:"family_count" gorm:"not null;default:0"`
+	ErrorFile     string            `json:"-" gorm:"type:varchar(255)"`
+	Error         string            `json:"error,omitempty" gorm:"type:text"`
+	FinishedAt    *time.Time        `json:"finished_at"`
+	CreatedAt     time.Time         `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time         `json:"updated_at" gorm:"not null"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (i *MemberImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// HasErrorFile indica se há linhas rejeitadas para download
+func (i *MemberImport) HasErrorFile() bool {
+	return i.ErrorFile != ""
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemberMerge registra a unificação de dois cadastros da mesma pessoa. O cadastro
+// removido é guardado como estava antes da unificação, junto com a quantidade de
+// registros transferidos de cada tabela para o cadastro mantido.
+type MemberMerge struct {
+	ID             string                 `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID    string                 `json:"community_id" gorm:"type:uuid;not null;index"`
+	SurvivorID     string                 `json:"survivor_id" gorm:"type:uuid;not null;index"`
+	MergedMemberID string                 `json:"merged_member_id" gorm:"type:uuid;not null"`
+	MergedName     string                 `json:"merged_name" gorm:"not null"`
+	MergedBy       string                 `json:"merged_by" gorm:"type:uuid;not null"`
+	Score          int                    `json:"score" gorm:"not null;default:0"`
+	Reasons        []string               `json:"reasons" gorm:"type:jsonb;serializer:json"`
+	CopiedFields   []string               `json:"copied_fields" gorm:"type:jsonb;serializer:json"`
+	MovedRecords   map[string]int64       `json:"moved_records" gorm:"type:jsonb;serializer:json"`
+	Snapshot       map[string]interface{} `json:"snapshot" gorm:"type:jsonb;serializer:json"`
+	CreatedAt      time.Time              `json:"created_at" gorm:"not null"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:MergedBy"`
+}
+
+func (m *MemberMerge) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PipelineStage é uma etapa do caminho de integração (ex.: visitante, consolidação,
+// classe de novos membros, membro). Ao entrar na etapa, o membro pode ter o tipo e a
+// situação alterados e receber uma tarefa de acompanhamento para um líder.
+type PipelineStage struct {
+	ID          string `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string `json:"community_id" gorm:"type:uuid;not null;index"`
+	Name        string `json:"name" gorm:"type:varchar(100);not null"`
+	Description string `json:"description" gorm:"type:text"`
+	Position    int    `json:"position" gorm:"not null;default:0"`
+	// Tipo e situação atribuídos ao membro que entra na etapa; vazios mantêm os atuais
+	MemberType   string `json:"member_type" gorm:"type:varchar(20)"`
+	MemberStatus string `json:"member_status" gorm:"type:varchar(20)"`
+	// Dias na etapa a partir dos quais o membro é considerado parado; 0 não limita
+	MaxDays int `json:"max_days" gorm:"not null;default:0"`
+	// Etapa em que entram os visitantes registrados no check-in dos eventos
+	IsVisitorEntry bool `json:"is_visitor_entry" gorm:"not null;default:false"`
+	// Tarefa criada quando o membro entra na etapa
+	FollowUpTitle      string    `json:"follow_up_title" gorm:"type:varchar(255)"`
+	FollowUpDays       int       `json:"follow_up_days" gorm:"not null;default:0"`
+	FollowUpAssigneeID *string   `json:"follow_up_assignee_id" gorm:"type:uuid"`
+	CreatedAt          time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// HasFollowUp indica se a etapa gera tarefa de acompanhamento
+func (s *PipelineStage) HasFollowUp() bool {
+	return s.FollowUpTitle != ""
+}
+
+// Origens das mudanças registradas no histórico do membro
+const (
+	StatusChangeSourceManual   = "manual"
+	StatusChangeSourceCheckIn  = "checkin"
+	StatusChangeSourcePipeline = "pipeline"
+	// Evento do histórico de membresia (MembershipEvent)
+	StatusChangeSourceMembership = "membership"
+)
+
+// MemberStatusHistory registra cada mudança de etapa, tipo ou situação do membro
+type MemberStatusHistory struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string    `json:"community_id" gorm:"type:uuid;not null"`
+	MemberID    string    `json:"member_id" gorm:"type:uuid;not null;index"`
+	FromStageID *string   `json:"from_stage_id" gorm:"type:uuid"`
+	ToStageID   *string   `json:"to_stage_id" gorm:"type:uuid"`
+	FromType    string    `json:"from_type" gorm:"type:varchar(20)"`
+	ToType      string    `json:"to_type" gorm:"type:varchar(20)"`
+	FromStatus  string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus    string    `json:"to_status" gorm:"type:varchar(20)"`
+	Source      string    `json:"source" gorm:"type:varchar(20);not null;check:source IN ('manual', 'checkin', 'pipeline', 'membership')"`
+	ChangedBy   *string   `json:"changed_by" gorm:"type:uuid"` // usuário que fez a mudança
+	Notes       string    `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
+
+	FromStage *PipelineStage `json:"from_stage,omitempty" gorm:"foreignKey:FromStageID"`
+	ToStage   *PipelineStage `json:"to_stage,omitempty" gorm:"foreignKey:ToStageID"`
+}
+
+// Situações das tarefas de acompanhamento
+const (
+	FollowUpTaskPending   = "pending"
+	FollowUpTaskDone      = "done"
+	FollowUpTaskCancelled = "cancelled"
+)
+
+// FollowUpTask é uma tarefa de acompanhamento de um membro atribuída a um líder
+type FollowUpTask struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string     `json:"community_id" gorm:"type:uuid;not null;index"`
+	MemberID    string     `json:"member_id" gorm:"type:uuid;not null;index"`
+	StageID     *string    `json:"stage_id" gorm:"type:uuid"`
+	AssigneeID  *string    `json:"assignee_id" gorm:"type:uuid;index"`
+	Title       string     `json:"title" gorm:"type:varchar(255);not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	DueDate     time.Time  `json:"due_date" gorm:"type:date;not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';check:chk_follow_up_tasks_cancelled_status,status IN ('pending', 'done', 'cancelled')"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CompletedBy *string    `json:"completed_by" gorm:"type:uuid"`
+	Result      string     `json:"result" gorm:"type:text"`
+	CreatedBy   *string    `json:"created_by" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+
+	Member   *Member        `json:"member,omitempty" gorm:"foreignKey:MemberID"`
+	Assignee *Member        `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	Stage    *PipelineStage `json:"stage,omitempty" gorm:"foreignKey:StageID"`
+}
+
+// IsOverdue indica se a tarefa pendente passou do prazo
+func (t *FollowUpTask) IsOverdue(now time.Time) bool {
+	return t.Status == FollowUpTaskPending && t.DueDate.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, t.DueDate.Location()))
+}
+
+func (s *PipelineStage) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (h *MemberStatusHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == "" {
+		h.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (t *FollowUpTask) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemberProfileUpdate são os dados do cadastro que o próprio membro altera no portal.
+// Os campos nulos não são alterados; os nomes JSON são os mesmos de Member.
+type MemberProfileUpdate struct {
+	Email                    *string   `json:"email,omitempty" binding:"omitempty,email,max=255"`
+	Phone                    *string   `json:"phone,omitempty" binding:"omitempty,max=20"`
+	EmergencyContact         *string   `json:"emergency_contact,omitempty" binding:"omitempty,max=100"`
+	EmergencyPhone           *string   `json:"emergency_phone,omitempty" binding:"omitempty,max=20"`
+	Address                  *string   `json:"address,omitempty"`
+	Number                   *string   `json:"number,omitempty" binding:"omitempty,max=20"`
+	Neighborhood             *string   `json:"neighborhood,omitempty" binding:"omitempty,max=100"`
+	City                     *string   `json:"city,omitempty" binding:"omitempty,max=100"`
+	State                    *string   `json:"state,omitempty" binding:"omitempty,max=100"`
+	Country                  *string   `json:"country,omitempty" binding:"omitempty,max=100"`
+	ZipCode                  *string   `json:"zip_code,omitempty" binding:"omitempty,max=20"`
+	NotifyByEmail            *bool     `json:"notify_by_email,omitempty"`
+	NotifyByWhatsApp         *bool     `json:"notify_by_whatsapp,omitempty"`
+	IsSubscribedToNewsletter *bool     `json:"is_subscribed_to_newsletter,omitempty"`
+	Skills                   *[]string `json:"skills,omitempty" binding:"omitempty,max=30"`
+	Interests                *[]string `json:"interests,omitempty" binding:"omitempty,max=30"`
+	// Caminho da foto enviada, preenchido pelo envio de foto
+	Photo *string `json:"photo,omitempty" binding:"-"`
+}
+
+// Ações sobre os familiares pedidas pelo membro no portal
+const (
+	FamilyChangeAdd    = "add"
+	FamilyChangeUpdate = "update"
+	FamilyChangeRemove = "remove"
+)
+
+// MemberFamilyUpdate é a inclusão, alteração ou remoção de um familiar pelo membro
+type MemberFamilyUpdate struct {
+	Action    string     `json:"action"`
+	MemberID  *string    `json:"member_id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	BirthDate *time.Time `json:"birth_date,omitempty"`
+	Gender    string     `json:"gender,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Phone     string     `json:"phone,omitempty"`
+}
+
+// Campo usado nas configurações para exigir aprovação das mudanças de familiares
+const ProfileFieldFamily = "family"
+
+// MemberProfileSettings define quais campos alterados pelo membro no portal só são
+// aplicados após a aprovação de um administrador
+type MemberProfileSettings struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID    string    `json:"community_id" gorm:"type:uuid;not null;uniqueIndex"`
+	ApprovalFields []string  `json:"approval_fields" gorm:"type:jsonb;serializer:json"`
+	UpdatedBy      *string   `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// Tipos e situações das solicitações de alteração do cadastro
+const (
+	ProfileChangeKindProfile = "profile"
+	ProfileChangeKindFamily  = "family"
+
+	ProfileChangePending   = "pending"
+	ProfileChangeApproved  = "approved"
+	ProfileChangeRejected  = "rejected"
+	ProfileChangeCancelled = "cancelled"
+)
+
+// MemberProfileChange é uma alteração do cadastro feita pelo membro no portal que
+// aguarda a aprovação de um administrador. Previous guarda os valores do cadastro no
+// momento da solicitação, para comparação na revisão.
+type MemberProfileChange struct {
+	ID          string               `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string               `json:"community_id" gorm:"type:uuid;not null;index"`
+	MemberID    string               `json:"member_id" gorm:"type:uuid;not null;index"`
+	Kind        string               `json:"kind" gorm:"type:varchar(20);not null;check:kind IN ('profile', 'family')"`
+	Profile     *MemberProfileUpdate `json:"profile,omitempty" gorm:"type:jsonb;serializer:json"`
+	Previous    *MemberProfileUpdate `json:"previous,omitempty" gorm:"type:jsonb;serializer:json"`
+	Family      *MemberFamilyUpdate  `json:"family,omitempty" gorm:"type:jsonb;serializer:json"`
+	Status      string               `json:"status" gorm:"type:varchar(20);not null;default:'pending';check:chk_member_profile_changes_cancelled_status,status IN ('pending', 'approved', 'rejected', 'cancelled')"`
+	ReviewedBy  *string              `json:"reviewed_by" gorm:"type:uuid"`
+	ReviewedAt  *time.Time           `json:"reviewed_at"`
+	ReviewNotes string               `json:"review_notes" gorm:"type:text"`
+	CreatedAt   time.Time            `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time            `json:"updated_at" gorm:"not null"`
+
+	Member *Member `json:"member,omitempty" gorm:"foreignKey:MemberID"`
+}
+
+func (s *MemberProfileSettings) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (c *MemberProfileChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
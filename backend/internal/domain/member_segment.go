@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemberSearch são os critérios da busca avançada de membros. Critérios vazios não
+// filtram; listas aceitam qualquer um dos valores informados. Os períodos em dias são
+// relativos à data da busca, para que o segmento salvo continue atual.
+type MemberSearch struct {
+	Search                string     `json:"search,omitempty"`
+	Statuses              []string   `json:"statuses,omitempty"`
+	Types                 []string   `json:"types,omitempty"`
+	Roles                 []string   `json:"roles,omitempty"`
+	Genders               []string   `json:"genders,omitempty"`
+	MinAge                *int       `json:"min_age,omitempty"`
+	MaxAge                *int       `json:"max_age,omitempty"`
+	Baptized              *bool      `json:"baptized,omitempty"`
+	Ministries            []string   `json:"ministries,omitempty"`
+	IsVolunteer           *bool      `json:"is_volunteer,omitempty"`
+	Skills                []string   `json:"skills,omitempty"`
+	Interests             []string   `json:"interests,omitempty"`
+	Cities                []string   `json:"cities,omitempty"`
+	Neighborhoods         []string   `json:"neighborhoods,omitempty"`
+	LastAttendanceFrom    *time.Time `json:"last_attendance_from,omitempty"`
+	LastAttendanceTo      *time.Time `json:"last_attendance_to,omitempty"`
+	AttendedWithinDays    *int       `json:"attended_within_days,omitempty"`
+	AbsentForDays         *int       `json:"absent_for_days,omitempty"`
+	LastContributionFrom  *time.Time `json:"last_contribution_from,omitempty"`
+	LastContributionTo    *time.Time `json:"last_contribution_to,omitempty"`
+	ContributedWithinDays *int       `json:"contributed_within_days,omitempty"`
+	GroupIDs              []string   `json:"group_ids,omitempty"`
+	WithoutGroup          bool       `json:"without_group,omitempty"`
+}
+
+// MemberSegment é uma busca de membros salva com um nome, reutilizada como lista de
+// destinatários das comunicações. Os membros são buscados novamente a cada uso.
+type MemberSegment struct {
+	ID          string       `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string       `json:"community_id" gorm:"type:uuid;not null;index"`
+	Name        string       `json:"name" gorm:"type:varchar(100);not null"`
+	Description string       `json:"description" gorm:"type:text"`
+	Criteria    MemberSearch `json:"criteria" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedBy   string       `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time    `json:"updated_at" gorm:"not null"`
+
+	// Quantidade de membros no momento da consulta; não é gravada
+	MemberCount *int64 `json:"member_count,omitempty" gorm:"-"`
+}
+
+func (s *MemberSegment) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
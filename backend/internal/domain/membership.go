@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tipos de evento do histórico de membresia
+const (
+	MembershipEventJoined         = "joined"          // Recebido como membro (profissão de fé, aclamação)
+	MembershipEventBaptized       = "baptized"        // Batismo
+	MembershipEventTransferredIn  = "transferred_in"  // Recebido por transferência de outra igreja
+	MembershipEventTransferredOut = "transferred_out" // Transferido para outra igreja
+	MembershipEventDisciplined    = "disciplined"     // Disciplina
+	MembershipEventDeceased       = "deceased"        // Falecimento
+	MembershipEventReinstated     = "reinstated"      // Restaurado à comunhão
+)
+
+// MembershipEvent é um registro do histórico de membresia. O histórico só recebe novos
+// registros: um lançamento errado é anulado (VoidedAt), nunca alterado ou excluído. Os
+// dados de batismo, membresia e transferência do membro são calculados a partir dele.
+type MembershipEvent struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string    `json:"community_id" gorm:"type:uuid;not null;index"`
+	MemberID    string    `json:"member_id" gorm:"type:uuid;not null;index"`
+	Type        string    `json:"type" gorm:"type:varchar(20);not null;check:type IN ('joined', 'baptized', 'transferred_in', 'transferred_out', 'disciplined', 'deceased', 'reinstated')"`
+	Date        time.Time `json:"date" gorm:"type:date;not null"`
+	// Igreja de origem ou de destino nas transferências
+	Church string `json:"church" gorm:"type:varchar(255)"`
+	// Local do batismo
+	Location   string     `json:"location" gorm:"type:varchar(255)"`
+	Notes      string     `json:"notes" gorm:"type:text"`
+	RecordedBy *string    `json:"recorded_by" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null"`
+	VoidedAt   *time.Time `json:"voided_at"`
+	VoidedBy   *string    `json:"voided_by" gorm:"type:uuid"`
+	VoidReason string     `json:"void_reason" gorm:"type:text"`
+}
+
+// Tipos de carta emitidas para os membros
+const (
+	MemberLetterTransfer       = "transfer"
+	MemberLetterRecommendation = "recommendation"
+)
+
+// LetterTemplate é o modelo de carta da comunidade. Body usa a sintaxe de text/template.
+type LetterTemplate struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID string    `json:"community_id" gorm:"type:uuid;not null;uniqueIndex:idx_letter_template_type"`
+	Type        string    `json:"type" gorm:"type:varchar(20);not null;uniqueIndex:idx_letter_template_type;check:type IN ('transfer', 'recommendation')"`
+	Title       string    `json:"title" gorm:"type:varchar(255);not null"`
+	Body        string    `json:"body" gorm:"type:text;not null"`
+	SignerName  string    `json:"signer_name" gorm:"type:varchar(255)"`
+	SignerTitle string    `json:"signer_title" gorm:"type:varchar(255)"`
+	UpdatedBy   *string   `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"not null"`
+}
+
+// MemberLetter é uma carta emitida para o membro. O texto é guardado como foi emitido,
+// para que a segunda via seja idêntica mesmo após mudanças no modelo.
+type MemberLetter struct {
+	ID                string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CommunityID       string    `json:"community_id" gorm:"type:uuid;not null;index:idx_member_letter_number"`
+	MemberID          string    `json:"member_id" gorm:"type:uuid;not null;index"`
+	Type              string    `json:"type" gorm:"type:varchar(20);not null;check:type IN ('transfer', 'recommendation')"`
+	Year              int       `json:"year" gorm:"not null;index:idx_member_letter_number"`
+	Number            int       `json:"number" gorm:"not null;index:idx_member_letter_number"`
+	DestinationChurch string    `json:"destination_church" gorm:"type:varchar(255)"`
+	Title             string    `json:"title" gorm:"type:varchar(255);not null"`
+	Body              string    `json:"body" gorm:"type:text;not null"`
+	SignerName        string    `json:"signer_name" gorm:"type:varchar(255)"`
+	SignerTitle       string    `json:"signer_title" gorm:"type:varchar(255)"`
+	EventID           *string   `json:"event_id" gorm:"type:uuid"`
+	IssuedBy          string    `json:"issued_by" gorm:"type:uuid;not null"`
+	IssuedAt          time.Time `json:"issued_at" gorm:"not null"`
+}
+
+func (e *MembershipEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (t *LetterTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (l *MemberLetter) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
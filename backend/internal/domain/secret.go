@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/comunidade/backend/pkg/secret"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("secret", SecretSerializer{})
+	schema.RegisterSerializer("webhooks", WebhooksSerializer{})
+}
+
+// SecretSerializer grava o campo texto cifrado com a chave mestra e o decifra na
+// leitura. Valores gravados antes da criptografia são lidos como texto puro até a
+// rotação das chaves.
+type SecretSerializer struct{}
+
+func (SecretSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value, err := scanString(dbValue)
+	if err != nil {
+		return err
+	}
+	plaintext, err := secret.Default().Decrypt(value)
+	if err != nil {
+		return fmt.Errorf("erro ao decifrar %s: %v", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (SecretSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, _ := fieldValue.(string)
+	encrypted, err := secret.Default().Encrypt(value)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao cifrar %s: %v", field.Name, err)
+	}
+	return encrypted, nil
+}
+
+// webhookConfigRecord é o webhook como gravado no banco, sem o mascaramento do token
+// aplicado nas respostas da API
+type webhookConfigRecord WebhookConfig
+
+// WebhooksSerializer grava os webhooks da subconta em JSON com o token de autenticação
+// de cada um cifrado
+type WebhooksSerializer struct{}
+
+func (WebhooksSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value, err := scanString(dbValue)
+	if err != nil {
+		return err
+	}
+
+	var records []webhookConfigRecord
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &records); err != nil {
+			return fmt.Errorf("erro ao ler %s: %v", field.Name, err)
+		}
+	}
+
+	webhooks := make([]WebhookConfig, len(records))
+	for i, record := range records {
+		webhooks[i] = WebhookConfig(record)
+		webhooks[i].AuthToken, err = secret.Default().Decrypt(record.AuthToken)
+		if err != nil {
+			return fmt.Errorf("erro ao decifrar %s: %v", field.Name, err)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(webhooks))
+	return nil
+}
+
+func (WebhooksSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	webhooks, _ := fieldValue.([]WebhookConfig)
+
+	records := make([]webhookConfigRecord, len(webhooks))
+	for i, webhook := range webhooks {
+		records[i] = webhookConfigRecord(webhook)
+		token, err := secret.Default().Encrypt(webhook.AuthToken)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao cifrar %s: %v", field.Name, err)
+		}
+		records[i].AuthToken = token
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func scanString(dbValue interface{}) (string, error) {
+	switch v := dbValue.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("tipo inesperado para segredo: %T", dbValue)
+	}
+}
+
+// As respostas da API e os logs recebem os segredos mascarados. O valor completo só é
+// lido pelos serviços que o enviam aos provedores.
+
+func (c AsaasConfig) MarshalJSON() ([]byte, error) {
+	type asaasConfigJSON AsaasConfig
+	masked := asaasConfigJSON(c)
+	masked.ApiKey = secret.Mask(c.ApiKey)
+	masked.WebhookToken = secret.Mask(c.WebhookToken)
+	return json.Marshal(masked)
+}
+
+func (p PaymentGatewaySettings) MarshalJSON() ([]byte, error) {
+	type paymentGatewaySettingsJSON PaymentGatewaySettings
+	masked := paymentGatewaySettingsJSON(p)
+	masked.WebhookToken = secret.Mask(p.WebhookToken)
+	return json.Marshal(masked)
+}
+
+func (a AsaasAccount) MarshalJSON() ([]byte, error) {
+	type asaasAccountJSON AsaasAccount
+	masked := asaasAccountJSON(a)
+	masked.ApiKey = secret.Mask(a.ApiKey)
+	return json.Marshal(masked)
+}
+
+func (w WebhookConfig) MarshalJSON() ([]byte, error) {
+	masked := webhookConfigRecord(w)
+	masked.AuthToken = secret.Mask(w.AuthToken)
+	return json.Marshal(masked)
+}
+
+func (s CommunicationSettings) MarshalJSON() ([]byte, error) {
+	type communicationSettingsJSON CommunicationSettings
+	masked := communicationSettingsJSON(s)
+	masked.EmailPassword = secret.Mask(s.EmailPassword)
+	masked.SMSApiKey = secret.Mask(s.SMSApiKey)
+	masked.WhatsAppApiKey = secret.Mask(s.WhatsAppApiKey)
+	return json.Marshal(masked)
+}
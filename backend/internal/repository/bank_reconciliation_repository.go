@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BankReconciliationRepository define as operações de importação e conciliação de extratos bancários
+type BankReconciliationRepository interface {
+	Repository
+	CreateImport(ctx context.Context, statement *domain.BankStatementImport, transactions []*domain.BankTransaction) ([]*domain.BankTransaction, error)
+	FindImportByID(ctx context.Context, communityID, id string) (*domain.BankStatementImport, error)
+	ListImports(ctx context.Context, communityID string, filter *Filter) ([]*domain.BankStatementImport, int64, error)
+	FindTransactionByID(ctx context.Context, communityID, id string) (*domain.BankTransaction, error)
+	ListTransactions(ctx context.Context, communityID string, filter *Filter) ([]*domain.BankTransaction, int64, error)
+	MatchExpense(ctx context.Context, transaction *domain.BankTransaction, expense *domain.Expense) error
+	MatchRevenue(ctx context.Context, transaction *domain.BankTransaction, revenue *domain.Revenue) error
+	Ignore(ctx context.Context, transaction *domain.BankTransaction) error
+	Reopen(ctx context.Context, transaction *domain.BankTransaction) error
+}
+
+type bankReconciliationRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewBankReconciliationRepository(db *gorm.DB, logger *zap.Logger) BankReconciliationRepository {
+	return &bankReconciliationRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+// CreateImport registra a importação e seus lançamentos. Lançamentos já importados
+// anteriormente (mesmo identificador externo) são ignorados e contados como duplicados.
+// Retorna apenas os lançamentos criados.
+func (r *bankReconciliationRepository) CreateImport(ctx context.Context, statement *domain.BankStatementImport, transactions []*domain.BankTransaction) ([]*domain.BankTransaction, error) {
+	created := make([]*domain.BankTransaction, 0, len(transactions))
+
+	err := r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(statement).Error; err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			transaction.ImportID = statement.ID
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(transaction)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				statement.DuplicateCount++
+				continue
+			}
+			created = append(created, transaction)
+		}
+
+		statement.TransactionCount = len(created)
+		statement.PendingCount = len(created)
+		return tx.Model(statement).Updates(map[string]interface{}{
+			"transaction_count": statement.TransactionCount,
+			"duplicate_count":   statement.DuplicateCount,
+			"pending_count":     statement.PendingCount,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *bankReconciliationRepository) FindImportByID(ctx context.Context, communityID, id string) (*domain.BankStatementImport, error) {
+	var statement domain.BankStatementImport
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&statement).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *bankReconciliationRepository) ListImports(ctx context.Context, communityID string, filter *Filter) ([]*domain.BankStatementImport, int64, error) {
+	var statements []*domain.BankStatementImport
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.BankStatementImport{}).
+		Where("community_id = ?", communityID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil {
+		// A busca textual do filtro padrão não se aplica às importações
+		filter.Search = ""
+	}
+	if err := ApplyFilter(query, filter).Find(&statements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return statements, total, nil
+}
+
+func (r *bankReconciliationRepository) FindTransactionByID(ctx context.Context, communityID, id string) (*domain.BankTransaction, error) {
+	var transaction domain.BankTransaction
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Expense").
+		Preload("Expense.Supplier").
+		Preload("Revenue").
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&transaction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// ListTransactions lista os lançamentos importados. As condições de importação e
+// status são informadas no filtro; a busca considera o nome e a descrição.
+func (r *bankReconciliationRepository) ListTransactions(ctx context.Context, communityID string, filter *Filter) ([]*domain.BankTransaction, int64, error) {
+	var transactions []*domain.BankTransaction
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.BankTransaction{}).
+		Where("community_id = ?", communityID).
+		Session(&gorm.Session{})
+
+	countQuery := query
+	if filter != nil {
+		for _, cond := range filter.conditions {
+			countQuery = countQuery.Where(cond.query, cond.args...)
+		}
+		if filter.Search != "" {
+			countQuery = countQuery.Where("name ILIKE ? OR description ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+		}
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil && filter.OrderBy == "" {
+		filter.OrderBy = "date"
+		filter.OrderDir = "desc"
+	}
+	if err := ApplyFilter(query.Preload("Expense").Preload("Revenue"), filter).Find(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+// MatchExpense vincula o lançamento à despesa e a marca como paga na data do
+// lançamento. Uma despesa sem ID é criada já paga a partir do lançamento.
+func (r *bankReconciliationRepository) MatchExpense(ctx context.Context, transaction *domain.BankTransaction, expense *domain.Expense) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingTransaction(tx, transaction.ID); err != nil {
+			return err
+		}
+
+		paidAt := transaction.Date
+		if expense.ID == "" {
+			expense.Status = "paid"
+			expense.PaidAt = &paidAt
+			expense.PaidBy = transaction.ResolvedBy
+			if err := tx.Omit(clause.Associations).Create(expense).Error; err != nil {
+				return err
+			}
+		} else {
+			var current domain.Expense
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("community_id = ? AND id = ?", transaction.CommunityID, expense.ID).
+				First(&current).Error; err != nil {
+				return err
+			}
+			// Despesas aguardando aprovação ou rejeitadas não podem ser pagas
+			if current.Status != "pending" && current.Status != "approved" {
+				return ErrEntryNotPending
+			}
+			if err := tx.Model(&domain.Expense{}).
+				Where("id = ?", expense.ID).
+				Updates(map[string]interface{}{
+					"status":     "paid",
+					"paid_at":    paidAt,
+					"paid_by":    transaction.ResolvedBy,
+					"updated_at": time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+			expense.Status = "paid"
+			expense.PaidAt = &paidAt
+			expense.PaidBy = transaction.ResolvedBy
+		}
+
+		transaction.Status = "matched"
+		transaction.ExpenseID = &expense.ID
+		transaction.RevenueID = nil
+		return saveTransaction(tx, transaction)
+	})
+}
+
+// MatchRevenue vincula o lançamento à receita e a marca como recebida na data do
+// lançamento. Uma receita sem ID é criada já recebida a partir do lançamento.
+func (r *bankReconciliationRepository) MatchRevenue(ctx context.Context, transaction *domain.BankTransaction, revenue *domain.Revenue) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingTransaction(tx, transaction.ID); err != nil {
+			return err
+		}
+
+		receivedAt := transaction.Date
+		if revenue.ID == "" {
+			revenue.Status = "received"
+			revenue.ReceivedAt = &receivedAt
+			if err := tx.Omit(clause.Associations).Create(revenue).Error; err != nil {
+				return err
+			}
+		} else {
+			var current domain.Revenue
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("community_id = ? AND id = ?", transaction.CommunityID, revenue.ID).
+				First(&current).Error; err != nil {
+				return err
+			}
+			if current.Status != "pending" {
+				return ErrEntryNotPending
+			}
+			if err := tx.Model(&domain.Revenue{}).
+				Where("id = ?", revenue.ID).
+				Updates(map[string]interface{}{
+					"status":      "received",
+					"received_at": receivedAt,
+					"updated_at":  time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+			revenue.Status = "received"
+			revenue.ReceivedAt = &receivedAt
+		}
+
+		transaction.Status = "matched"
+		transaction.RevenueID = &revenue.ID
+		transaction.ExpenseID = nil
+		return saveTransaction(tx, transaction)
+	})
+}
+
+// Ignore retira da fila um lançamento que não corresponde a nenhuma despesa ou receita
+// (ex.: transferência entre contas próprias)
+func (r *bankReconciliationRepository) Ignore(ctx context.Context, transaction *domain.BankTransaction) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingTransaction(tx, transaction.ID); err != nil {
+			return err
+		}
+		transaction.Status = "ignored"
+		return saveTransaction(tx, transaction)
+	})
+}
+
+// Reopen devolve o lançamento à fila de conciliação. A despesa ou receita vinculada
+// volta a ficar pendente.
+func (r *bankReconciliationRepository) Reopen(ctx context.Context, transaction *domain.BankTransaction) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current domain.BankTransaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", transaction.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status == "pending" {
+			return ErrTransactionNotResolved
+		}
+
+		if current.ExpenseID != nil {
+			if err := tx.Model(&domain.Expense{}).
+				Where("id = ? AND status = ?", *current.ExpenseID, "paid").
+				Updates(map[string]interface{}{
+					// A despesa aprovada volta a aguardar pagamento já aprovada
+					"status":     gorm.Expr("CASE WHEN approved_at IS NULL THEN 'pending' ELSE 'approved' END"),
+					"paid_at":    nil,
+					"paid_by":    nil,
+					"updated_at": time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		if current.RevenueID != nil {
+			if err := tx.Model(&domain.Revenue{}).
+				Where("id = ? AND status = ?", *current.RevenueID, "received").
+				Updates(map[string]interface{}{
+					"status":      "pending",
+					"received_at": nil,
+					"updated_at":  time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		transaction.Status = "pending"
+		transaction.ExpenseID = nil
+		transaction.RevenueID = nil
+		transaction.AutoMatched = false
+		transaction.ResolvedBy = nil
+		transaction.ResolvedAt = nil
+		return saveTransaction(tx, transaction)
+	})
+}
+
+// lockPendingTransaction bloqueia o lançamento e garante que ele ainda esteja na fila
+func lockPendingTransaction(tx *gorm.DB, transactionID string) error {
+	var transaction domain.BankTransaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", transactionID).
+		First(&transaction).Error; err != nil {
+		return err
+	}
+	if transaction.Status != "pending" {
+		return ErrTransactionResolved
+	}
+	return nil
+}
+
+// saveTransaction grava o lançamento e recalcula os contadores da importação
+func saveTransaction(tx *gorm.DB, transaction *domain.BankTransaction) error {
+	transaction.UpdatedAt = time.Now()
+	if err := tx.Omit(clause.Associations).Save(transaction).Error; err != nil {
+		return err
+	}
+
+	var summary struct {
+		Matched int
+		Pending int
+	}
+	if err := tx.Model(&domain.BankTransaction{}).
+		Select("COUNT(*) FILTER (WHERE status = 'matched') AS matched, COUNT(*) FILTER (WHERE status = 'pending') AS pending").
+		Where("import_id = ?", transaction.ImportID).
+		Scan(&summary).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&domain.BankStatementImport{}).
+		Where("id = ?", transaction.ImportID).
+		Updates(map[string]interface{}{
+			"matched_count": summary.Matched,
+			"pending_count": summary.Pending,
+			"updated_at":    time.Now(),
+		}).Error
+}
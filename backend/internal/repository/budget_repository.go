@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BudgetRepository define as operações do repositório de orçamentos
+type BudgetRepository interface {
+	Repository
+	Create(ctx context.Context, budget *domain.Budget) error
+	Update(ctx context.Context, budget *domain.Budget) error
+	Delete(ctx context.Context, communityID, budgetID string) error
+	FindByID(ctx context.Context, communityID, budgetID string) (*domain.Budget, error)
+	FindByScope(ctx context.Context, communityID, categoryID string, groupID *string, period string, year int) (*domain.Budget, error)
+	FindByCategory(ctx context.Context, communityID, categoryID string, year int) ([]*domain.Budget, error)
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Budget, int64, error)
+	CreateAlert(ctx context.Context, alert *domain.BudgetAlert) (bool, error)
+	UpdateAlert(ctx context.Context, alert *domain.BudgetAlert) error
+	ListAlerts(ctx context.Context, budgetID string) ([]*domain.BudgetAlert, error)
+}
+
+type budgetRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewBudgetRepository(db *gorm.DB, logger *zap.Logger) BudgetRepository {
+	return &budgetRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *budgetRepository) Create(ctx context.Context, budget *domain.Budget) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Create(budget).Error
+}
+
+func (r *budgetRepository) Update(ctx context.Context, budget *domain.Budget) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Save(budget).Error
+}
+
+func (r *budgetRepository) Delete(ctx context.Context, communityID, budgetID string) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("budget_id = ?", budgetID).Delete(&domain.BudgetAlert{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("community_id = ? AND id = ?", communityID, budgetID).
+			Delete(&domain.Budget{}).Error
+	})
+}
+
+func (r *budgetRepository) FindByID(ctx context.Context, communityID, budgetID string) (*domain.Budget, error) {
+	var budget domain.Budget
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Group").
+		Where("community_id = ? AND id = ?", communityID, budgetID).
+		First(&budget).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// FindByScope busca o orçamento da categoria (e do ministério, se informado) no ano e período
+func (r *budgetRepository) FindByScope(ctx context.Context, communityID, categoryID string, groupID *string, period string, year int) (*domain.Budget, error) {
+	query := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND category_id = ? AND period = ? AND year = ?", communityID, categoryID, period, year)
+	if groupID != nil {
+		query = query.Where("group_id = ?", *groupID)
+	} else {
+		query = query.Where("group_id IS NULL")
+	}
+
+	var budget domain.Budget
+	if err := query.First(&budget).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// FindByCategory busca os orçamentos da categoria no ano, com e sem ministério
+func (r *budgetRepository) FindByCategory(ctx context.Context, communityID, categoryID string, year int) ([]*domain.Budget, error) {
+	var budgets []*domain.Budget
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Group").
+		Where("community_id = ? AND category_id = ? AND year = ?", communityID, categoryID, year).
+		Find(&budgets).Error; err != nil {
+		r.logger.Error("erro ao buscar orçamentos da categoria",
+			zap.Error(err),
+			zap.String("community_id", communityID),
+			zap.String("category_id", categoryID))
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *budgetRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Budget, int64, error) {
+	var budgets []*domain.Budget
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.Budget{}).
+		Where("community_id = ?", communityID).
+		Session(&gorm.Session{})
+
+	countQuery := query
+	if filter != nil {
+		for _, cond := range filter.conditions {
+			countQuery = countQuery.Where(cond.query, cond.args...)
+		}
+		// A busca textual do filtro padrão não se aplica aos orçamentos
+		filter.Search = ""
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("Category").Preload("Group"), filter).Find(&budgets).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return budgets, total, nil
+}
+
+// CreateAlert registra o alerta caso ele ainda não tenha sido enviado no período.
+// Retorna false quando o alerta já existia.
+func (r *budgetRepository) CreateAlert(ctx context.Context, alert *domain.BudgetAlert) (bool, error) {
+	result := r.GetDB().WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *budgetRepository) UpdateAlert(ctx context.Context, alert *domain.BudgetAlert) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Save(alert).Error
+}
+
+func (r *budgetRepository) ListAlerts(ctx context.Context, budgetID string) ([]*domain.BudgetAlert, error) {
+	var alerts []*domain.BudgetAlert
+	if err := r.GetDB().WithContext(ctx).
+		Where("budget_id = ?", budgetID).
+		Order("period_start DESC, level DESC").
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
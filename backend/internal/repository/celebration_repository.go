@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CelebrationRepository define as operações das datas comemorativas dos membros
+type CelebrationRepository interface {
+	Repository
+	FindSettings(ctx context.Context, communityID string) (*domain.CelebrationSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.CelebrationSettings) error
+	ListEnabledSettings(ctx context.Context) ([]*domain.CelebrationSettings, error)
+	MarkDigestSent(ctx context.Context, settingsID string, sentAt time.Time) error
+	ClaimDigest(ctx context.Context, settingsID string, since, sentAt time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, settingsID string, previous *time.Time) error
+	FindCelebrants(ctx context.Context, communityID string, days []string) ([]*domain.Member, error)
+	ListPastors(ctx context.Context, communityID string) ([]*domain.Member, error)
+	ListLeaderGroups(ctx context.Context, communityID string) ([]*domain.Group, error)
+	ListGroupMemberIDs(ctx context.Context, groupIDs []string) (map[string][]string, error)
+	CreateGreeting(ctx context.Context, greeting *domain.CelebrationGreeting) (bool, error)
+	DeleteGreeting(ctx context.Context, id string) error
+}
+
+type celebrationRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewCelebrationRepository(db *gorm.DB, logger *zap.Logger) CelebrationRepository {
+	return &celebrationRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *celebrationRepository) FindSettings(ctx context.Context, communityID string) (*domain.CelebrationSettings, error) {
+	var settings domain.CelebrationSettings
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *celebrationRepository) SaveSettings(ctx context.Context, settings *domain.CelebrationSettings) error {
+	return r.GetDB().WithContext(ctx).Save(settings).Error
+}
+
+// ListEnabledSettings lista as comunidades com resumo semanal ou felicitações ativos
+func (r *celebrationRepository) ListEnabledSettings(ctx context.Context) ([]*domain.CelebrationSettings, error) {
+	var settings []*domain.CelebrationSettings
+	if err := r.GetDB().WithContext(ctx).
+		Where("digest_enabled = ? OR greetings_enabled = ?", true, true).
+		Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *celebrationRepository) MarkDigestSent(ctx context.Context, settingsID string, sentAt time.Time) error {
+	return r.GetDB().WithContext(ctx).Model(&domain.CelebrationSettings{}).
+		Where("id = ?", settingsID).
+		Update("last_digest_at", sentAt).Error
+}
+
+// ClaimDigest registra o envio do resumo se nenhum foi enviado desde since. O registro
+// é feito antes do envio para que duas execuções simultâneas não enviem o mesmo resumo.
+func (r *celebrationRepository) ClaimDigest(ctx context.Context, settingsID string, since, sentAt time.Time) (bool, error) {
+	result := r.GetDB().WithContext(ctx).Model(&domain.CelebrationSettings{}).
+		Where("id = ? AND (last_digest_at IS NULL OR last_digest_at < ?)", settingsID, since).
+		Update("last_digest_at", sentAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseDigest restaura a data do envio anterior quando o resumo registrado não pôde
+// ser enviado, para que seja tentado novamente na próxima execução
+func (r *celebrationRepository) ReleaseDigest(ctx context.Context, settingsID string, previous *time.Time) error {
+	return r.GetDB().WithContext(ctx).Model(&domain.CelebrationSettings{}).
+		Where("id = ?", settingsID).
+		Update("last_digest_at", previous).Error
+}
+
+// FindCelebrants busca os membros ativos com aniversário, casamento, batismo ou
+// membresia em um dos dias informados (no formato MM-DD). As datas de batismo e de
+// membresia são gravadas com horário e por isso são comparadas em UTC.
+func (r *celebrationRepository) FindCelebrants(ctx context.Context, communityID string, days []string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if len(days) == 0 {
+		return members, nil
+	}
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Spouse").
+		Where("community_id = ? AND status = ?", communityID, "active").
+		Where("TO_CHAR(birth_date, 'MM-DD') IN ? OR TO_CHAR(wedding_date, 'MM-DD') IN ? OR "+
+			"TO_CHAR(baptism_date AT TIME ZONE 'UTC', 'MM-DD') IN ? OR TO_CHAR(membership_date AT TIME ZONE 'UTC', 'MM-DD') IN ?",
+			days, days, days, days).
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ListPastors lista os administradores e os membros com função pastoral da comunidade
+func (r *celebrationRepository) ListPastors(ctx context.Context, communityID string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND status = ? AND email <> ''", communityID, "active").
+		Where("role = ? OR ministry_role ILIKE ?", "admin", "%pastor%").
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ListLeaderGroups lista os grupos ativos que têm líder ou colíder
+func (r *celebrationRepository) ListLeaderGroups(ctx context.Context, communityID string) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Leader").
+		Preload("CoLeader").
+		Where("community_id = ? AND status = ?", communityID, "active").
+		Where("leader_id IS NOT NULL OR co_leader_id IS NOT NULL").
+		Order("name").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListGroupMemberIDs retorna os membros de cada grupo
+func (r *celebrationRepository) ListGroupMemberIDs(ctx context.Context, groupIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(groupIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		GroupID  string
+		MemberID string
+	}
+	if err := r.GetDB().WithContext(ctx).
+		Table("group_members").
+		Select("group_id, member_id").
+		Where("group_id IN ?", groupIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.GroupID] = append(result[row.GroupID], row.MemberID)
+	}
+	return result, nil
+}
+
+// CreateGreeting registra a felicitação e retorna false quando o membro já a recebeu
+func (r *celebrationRepository) CreateGreeting(ctx context.Context, greeting *domain.CelebrationGreeting) (bool, error) {
+	result := r.GetDB().WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(greeting)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *celebrationRepository) DeleteGreeting(ctx context.Context, id string) error {
+	return r.GetDB().WithContext(ctx).Delete(&domain.CelebrationGreeting{}, "id = ?", id).Error
+}
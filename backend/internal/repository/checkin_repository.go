@@ -5,6 +5,7 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/comunidade/backend/internal/domain"
 )
@@ -13,6 +14,9 @@ type CheckInRepository interface {
 	Create(ctx context.Context, checkIn *domain.CheckIn) error
 	GetByEventID(ctx context.Context, eventID string) ([]domain.CheckIn, error)
 	GetStats(ctx context.Context, eventID string) (*domain.CheckInStats, error)
+	Update(ctx context.Context, checkIn *domain.CheckIn) error
+	ListVisitors(ctx context.Context, communityID, status string) ([]domain.CheckIn, error)
+	LockVisitor(ctx context.Context, communityID string, id uint) (*domain.CheckIn, error)
 }
 
 type checkInRepository struct {
@@ -27,8 +31,17 @@ func NewCheckInRepository(db *gorm.DB, logger *zap.Logger) CheckInRepository {
 	}
 }
 
+// Create grava o check-in e, quando ele é de um membro, a data da última presença do membro
 func (r *checkInRepository) Create(ctx context.Context, checkIn *domain.CheckIn) error {
-	return r.db.WithContext(ctx).Create(checkIn).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(checkIn).Error; err != nil {
+			return err
+		}
+		if checkIn.MemberID == nil {
+			return nil
+		}
+		return touchLastAttendance(tx, checkIn.EventID, *checkIn.MemberID, checkIn.CheckInAt)
+	})
 }
 
 func (r *checkInRepository) GetByEventID(ctx context.Context, eventID string) ([]domain.CheckIn, error) {
@@ -63,3 +76,59 @@ func (r *checkInRepository) GetStats(ctx context.Context, eventID string) (*doma
 
 	return &stats, nil
 }
+
+// Update grava o check-in e, quando ele foi associado a um membro, a data da última
+// presença do membro
+func (r *checkInRepository) Update(ctx context.Context, checkIn *domain.CheckIn) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(checkIn).Error; err != nil {
+			return err
+		}
+		if checkIn.MemberID == nil {
+			return nil
+		}
+		return touchLastAttendance(tx, checkIn.EventID, *checkIn.MemberID, checkIn.CheckInAt)
+	})
+}
+
+// ListVisitors lista os check-ins de visitantes nos eventos da comunidade, filtrando
+// pela situação da revisão quando informada
+func (r *checkInRepository) ListVisitors(ctx context.Context, communityID, status string) ([]domain.CheckIn, error) {
+	query := r.db.WithContext(ctx).
+		Joins("JOIN events ON events.id = check_ins.event_id").
+		Where("events.community_id = ? AND check_ins.is_visitor = ?", communityID, true)
+	if status != "" {
+		query = query.Where("check_ins.visitor_status = ?", status)
+	}
+
+	var checkIns []domain.CheckIn
+	err := query.Order("check_ins.check_in_at DESC").Find(&checkIns).Error
+	return checkIns, err
+}
+
+// LockVisitor busca o check-in de visitante de um evento da comunidade, bloqueando-o
+// até o fim da transação. Retorna ErrNotFound quando não existe.
+func (r *checkInRepository) LockVisitor(ctx context.Context, communityID string, id uint) (*domain.CheckIn, error) {
+	var checkIn domain.CheckIn
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "check_ins"}}).
+		Joins("JOIN events ON events.id = check_ins.event_id").
+		Where("events.community_id = ? AND check_ins.id = ? AND check_ins.is_visitor = ?", communityID, id, true).
+		First(&checkIn).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &checkIn, nil
+}
+
+// touchLastAttendance avança a data da última presença do membro da comunidade do
+// evento, usada na busca de membros. Presenças registradas fora de ordem não fazem a
+// data voltar. at pode ser uma data ou uma expressão SQL.
+func touchLastAttendance(tx *gorm.DB, eventID, memberID string, at interface{}) error {
+	return tx.Model(&domain.Member{}).
+		Where("id = ? AND community_id = (SELECT community_id FROM events WHERE id = ?)", memberID, eventID).
+		UpdateColumn("last_attendance_at", gorm.Expr("GREATEST(COALESCE(last_attendance_at, ?), ?)", at, at)).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestCheckInsTouchLastAttendance(t *testing.T) {
+	memberID := "11111111-1111-1111-1111-111111111111"
+	eventID := "22222222-2222-2222-2222-222222222222"
+	now := time.Date(2026, 3, 15, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		save  func(ctx context.Context, repos *Repositories) error
+		touch bool
+	}{
+		{
+			name: "check-in de membro",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.CheckIn.Create(ctx, &domain.CheckIn{EventID: eventID, MemberID: &memberID, CheckInAt: now})
+			},
+			touch: true,
+		},
+		{
+			name: "check-in de visitante pendente",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.CheckIn.Create(ctx, &domain.CheckIn{EventID: eventID, IsVisitor: true, CheckInAt: now,
+					VisitorStatus: domain.CheckInVisitorPending})
+			},
+		},
+		{
+			name: "visitante confirmado",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.CheckIn.Update(ctx, &domain.CheckIn{ID: 7, EventID: eventID, MemberID: &memberID, IsVisitor: true,
+					CheckInAt: now, VisitorStatus: domain.CheckInVisitorConfirmed})
+			},
+			touch: true,
+		},
+		{
+			name: "presença",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.Event.RegisterAttendance(ctx, &domain.Attendance{ID: "a1", EventID: eventID, MemberID: memberID,
+					Status: "present", CreatedAt: now, UpdatedAt: now})
+			},
+			touch: true,
+		},
+		{
+			name: "atraso",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.Event.UpdateAttendance(ctx, &domain.Attendance{EventID: eventID, MemberID: memberID,
+					Status: "late", UpdatedAt: now})
+			},
+			touch: true,
+		},
+		{
+			name: "falta",
+			save: func(ctx context.Context, repos *Repositories) error {
+				return repos.Event.RegisterAttendance(ctx, &domain.Attendance{ID: "a1", EventID: eventID, MemberID: memberID,
+					Status: "absent", CreatedAt: now, UpdatedAt: now})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, recorder := newTestRepositories(t)
+			if err := tt.save(context.Background(), repos); err != nil {
+				t.Fatalf("erro ao gravar: %v", err)
+			}
+
+			updates := recorder.Statements(`UPDATE "members" SET "last_attendance_at"=GREATEST(`)
+			if tt.touch && len(updates) != 1 {
+				t.Fatalf("%d atualizações da última presença; esperado 1: %q", len(updates), recorder.statements)
+			}
+			if !tt.touch && len(updates) != 0 {
+				t.Fatalf("última presença atualizada sem presença no evento: %q", updates)
+			}
+		})
+	}
+}
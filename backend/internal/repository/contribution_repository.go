@@ -2,18 +2,28 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/comunidade/backend/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ContributionRepository interface {
 	Repository
+	Create(ctx context.Context, contribution *domain.Contribution) error
+	Update(ctx context.Context, contribution *domain.Contribution) error
+	Delete(ctx context.Context, communityID, id string) error
+	FindByID(ctx context.Context, communityID, id string) (*domain.Contribution, error)
 	FindByBatch(ctx context.Context, batchID string) ([]*domain.Contribution, error)
-	List(ctx context.Context, filter *Filter) ([]*domain.Contribution, int64, error)
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Contribution, int64, error)
+	FindCompletedByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Contribution, error)
 	CreateBatch(ctx context.Context, batch *domain.ContributionBatch) error
 	UpdateBatch(ctx context.Context, batch *domain.ContributionBatch) error
+	FindBatchByID(ctx context.Context, communityID, id string) (*domain.ContributionBatch, error)
+	ListBatches(ctx context.Context, communityID string, filter *Filter) ([]*domain.ContributionBatch, int64, error)
+	CloseBatch(ctx context.Context, batch *domain.ContributionBatch, revenue *domain.Revenue) error
 }
 
 type contributionRepository struct {
@@ -26,19 +36,80 @@ func NewContributionRepository(db *gorm.DB, logger *zap.Logger) ContributionRepo
 	}
 }
 
+// Create registra um envelope no lote e atualiza o total e a quantidade do lote
+func (r *contributionRepository) Create(ctx context.Context, contribution *domain.Contribution) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenBatch(tx, contribution.BatchID); err != nil {
+			return err
+		}
+		if err := tx.Create(contribution).Error; err != nil {
+			return err
+		}
+		return recalculateBatch(tx, contribution.BatchID)
+	})
+}
+
+// Update altera um envelope de um lote aberto e atualiza os totais do lote
+func (r *contributionRepository) Update(ctx context.Context, contribution *domain.Contribution) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenBatch(tx, contribution.BatchID); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(contribution).Error; err != nil {
+			return err
+		}
+		return recalculateBatch(tx, contribution.BatchID)
+	})
+}
+
+// Delete remove um envelope de um lote aberto e atualiza os totais do lote
+func (r *contributionRepository) Delete(ctx context.Context, communityID, id string) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var contribution domain.Contribution
+		if err := tx.Where("community_id = ? AND id = ?", communityID, id).First(&contribution).Error; err != nil {
+			return err
+		}
+		if err := lockOpenBatch(tx, contribution.BatchID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&contribution).Error; err != nil {
+			return err
+		}
+		return recalculateBatch(tx, contribution.BatchID)
+	})
+}
+
+func (r *contributionRepository) FindByID(ctx context.Context, communityID, id string) (*domain.Contribution, error) {
+	var contribution domain.Contribution
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&contribution).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contribution, nil
+}
+
 func (r *contributionRepository) FindByBatch(ctx context.Context, batchID string) ([]*domain.Contribution, error) {
 	var contributions []*domain.Contribution
-	if err := r.GetDB().WithContext(ctx).Where("batch_id = ?", batchID).Find(&contributions).Error; err != nil {
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Member").
+		Where("batch_id = ?", batchID).
+		Order("created_at asc").
+		Find(&contributions).Error; err != nil {
 		return nil, err
 	}
 	return contributions, nil
 }
 
-func (r *contributionRepository) List(ctx context.Context, filter *Filter) ([]*domain.Contribution, int64, error) {
+func (r *contributionRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Contribution, int64, error) {
 	var contributions []*domain.Contribution
 	var total int64
 
-	query := r.GetDB().WithContext(ctx).Model(&domain.Contribution{})
+	query := r.GetDB().WithContext(ctx).Model(&domain.Contribution{}).
+		Where("community_id = ?", communityID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -51,10 +122,190 @@ func (r *contributionRepository) List(ctx context.Context, filter *Filter) ([]*d
 	return contributions, total, nil
 }
 
+// FindCompletedByPeriod retorna as contribuições concluídas de membros identificados no
+// período [start, end). Se memberID for vazio, retorna as contribuições de todos os membros.
+func (r *contributionRepository) FindCompletedByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Contribution, error) {
+	var contributions []*domain.Contribution
+	query := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND status = ? AND member_id IS NOT NULL", communityID, "completed").
+		Where("date >= ? AND date < ?", start, end)
+
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+
+	if err := query.Order("date asc").Find(&contributions).Error; err != nil {
+		return nil, err
+	}
+	return contributions, nil
+}
+
 func (r *contributionRepository) CreateBatch(ctx context.Context, batch *domain.ContributionBatch) error {
 	return r.GetDB().WithContext(ctx).Create(batch).Error
 }
 
 func (r *contributionRepository) UpdateBatch(ctx context.Context, batch *domain.ContributionBatch) error {
-	return r.GetDB().WithContext(ctx).Save(batch).Error
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Save(batch).Error
+}
+
+func (r *contributionRepository) FindBatchByID(ctx context.Context, communityID, id string) (*domain.ContributionBatch, error) {
+	var batch domain.ContributionBatch
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Revenues").
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *contributionRepository) ListBatches(ctx context.Context, communityID string, filter *Filter) ([]*domain.ContributionBatch, int64, error) {
+	var batches []*domain.ContributionBatch
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.ContributionBatch{}).
+		Where("community_id = ?", communityID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("Category"), filter).Find(&batches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return batches, total, nil
+}
+
+// CloseBatch trava o lote, conclui seus envelopes, lança uma receita por forma de
+// pagamento a partir de revenue e atualiza as estatísticas de contribuição dos membros
+// em uma única transação
+func (r *contributionRepository) CloseBatch(ctx context.Context, batch *domain.ContributionBatch, revenue *domain.Revenue) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current domain.ContributionBatch
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", batch.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status != "counted" {
+			return ErrBatchNotCounted
+		}
+
+		if revenue != nil {
+			var totals []methodTotal
+			if err := tx.Model(&domain.Contribution{}).
+				Select("method, COALESCE(SUM(amount), 0) AS total").
+				Where("batch_id = ?", batch.ID).
+				Group("method").
+				Order("method").
+				Scan(&totals).Error; err != nil {
+				return err
+			}
+			batch.Revenues = nil
+			for _, revenue := range revenuesByMethod(revenue, totals) {
+				if err := tx.Create(revenue).Error; err != nil {
+					return err
+				}
+				batch.Revenues = append(batch.Revenues, *revenue)
+			}
+		}
+
+		if err := tx.Model(&domain.Contribution{}).
+			Where("batch_id = ? AND status = ?", batch.ID, "pending").
+			Update("status", "completed").Error; err != nil {
+			return err
+		}
+
+		// Atualiza as estatísticas de contribuição dos membros identificados
+		var totals []struct {
+			MemberID string
+			Total    float64
+			Count    int
+		}
+		if err := tx.Model(&domain.Contribution{}).
+			Select("member_id, COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").
+			Where("batch_id = ? AND member_id IS NOT NULL AND status = ?", batch.ID, "completed").
+			Group("member_id").
+			Scan(&totals).Error; err != nil {
+			return err
+		}
+		for _, t := range totals {
+			if err := tx.Model(&domain.Member{}).
+				Where("id = ?", t.MemberID).
+				Updates(map[string]interface{}{
+					"contribution_count":   gorm.Expr("contribution_count + ?", t.Count),
+					"total_contributions":  gorm.Expr("total_contributions + ?", t.Total),
+					"last_contribution_at": gorm.Expr("GREATEST(COALESCE(last_contribution_at, ?), ?)", batch.Date, batch.Date),
+					"updated_at":           time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit(clause.Associations).Save(batch).Error
+	})
+}
+
+// methodTotal é o total dos envelopes de um lote em uma forma de pagamento
+type methodTotal struct {
+	Method string
+	Total  float64
+}
+
+// revenuesByMethod separa a receita do lote em uma receita por forma de pagamento,
+// para que dinheiro, cheques e transferências sejam conciliados separadamente
+func revenuesByMethod(revenue *domain.Revenue, totals []methodTotal) []*domain.Revenue {
+	revenues := make([]*domain.Revenue, 0, len(totals))
+	for _, total := range totals {
+		if total.Total <= 0 {
+			continue
+		}
+		methodRevenue := *revenue
+		methodRevenue.ID = ""
+		methodRevenue.PaymentType = total.Method
+		methodRevenue.Amount = total.Total
+		revenues = append(revenues, &methodRevenue)
+	}
+	return revenues
+}
+
+// lockOpenBatch bloqueia a linha do lote e garante que ele ainda esteja aberto
+func lockOpenBatch(tx *gorm.DB, batchID string) error {
+	var batch domain.ContributionBatch
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", batchID).
+		First(&batch).Error; err != nil {
+		return err
+	}
+	if batch.Status != "open" {
+		return ErrBatchNotOpen
+	}
+	return nil
+}
+
+// recalculateBatch recalcula o total e a quantidade de envelopes do lote
+func recalculateBatch(tx *gorm.DB, batchID string) error {
+	var summary struct {
+		Total float64
+		Count int
+	}
+	if err := tx.Model(&domain.Contribution{}).
+		Select("COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Scan(&summary).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&domain.ContributionBatch{}).
+		Where("id = ?", batchID).
+		Updates(map[string]interface{}{
+			"total":      summary.Total,
+			"count":      summary.Count,
+			"updated_at": time.Now(),
+		}).Error
 }
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestRevenuesByMethod(t *testing.T) {
+	batchID := "b1"
+	template := &domain.Revenue{
+		ID:                  "modelo",
+		CommunityID:         "c1",
+		CategoryID:          "cat1",
+		Description:         "Ofertas do culto",
+		Status:              "received",
+		ContributionBatchID: &batchID,
+	}
+
+	revenues := revenuesByMethod(template, []methodTotal{
+		{Method: "bank_transfer", Total: 300},
+		{Method: "cash", Total: 150.5},
+		{Method: "check", Total: 0},
+	})
+
+	want := []struct {
+		method string
+		amount float64
+	}{
+		{"bank_transfer", 300},
+		{"cash", 150.5},
+	}
+	if len(revenues) != len(want) {
+		t.Fatalf("receitas = %d; esperado %d", len(revenues), len(want))
+	}
+	for i, revenue := range revenues {
+		if revenue.PaymentType != want[i].method || revenue.Amount != want[i].amount {
+			t.Errorf("receita %d = %s %v; esperado %s %v", i, revenue.PaymentType, revenue.Amount, want[i].method, want[i].amount)
+		}
+		if revenue.ID != "" {
+			t.Errorf("receita %d com ID %q; esperado novo ID ao criar", i, revenue.ID)
+		}
+		if revenue.ContributionBatchID != &batchID || revenue.CategoryID != "cat1" || revenue.Status != "received" {
+			t.Errorf("receita %d não copiou os dados do lote: %+v", i, revenue)
+		}
+	}
+	if template.PaymentType != "" || template.Amount != 0 {
+		t.Errorf("modelo alterado: %+v", template)
+	}
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/pkg/secret"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AsaasConfigRepository define as operações do repositório de configurações do Asaas
@@ -16,6 +20,15 @@ type AsaasConfigRepository interface {
 	Delete(ctx context.Context, id string) error
 	FindByID(ctx context.Context, id string) (*domain.AsaasConfig, error)
 	FindByCommunityID(ctx context.Context, communityID string) (*domain.AsaasConfig, error)
+	FindByWebhookToken(ctx context.Context, token string) (*domain.AsaasConfig, error)
+}
+
+// PaymentGatewaySettingsRepository define as operações do repositório do provedor de pagamentos das comunidades
+type PaymentGatewaySettingsRepository interface {
+	Repository
+	Save(ctx context.Context, settings *domain.PaymentGatewaySettings) error
+	FindByCommunityID(ctx context.Context, communityID string) (*domain.PaymentGatewaySettings, error)
+	FindByWebhookToken(ctx context.Context, provider, token string) (*domain.PaymentGatewaySettings, error)
 }
 
 // CampaignRepository define as operações do repositório de campanhas
@@ -26,9 +39,22 @@ type CampaignRepository interface {
 	Delete(ctx context.Context, communityID, id string) error
 	FindByID(ctx context.Context, communityID, id string) (*domain.Campaign, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Campaign, error)
+	FindByCommunity(ctx context.Context, communityID string, filter *Filter) ([]*domain.Campaign, int64, error)
+	ListActive(ctx context.Context) ([]*domain.Campaign, error)
+	Close(ctx context.Context, campaign *domain.Campaign) (bool, error)
 	CountByCommunityID(ctx context.Context, communityID string) (int64, error)
 }
 
+// CampaignTotals resume as doações pagas de uma campanha. As cobranças geradas pelas
+// doações recorrentes também são doações e entram em RecurringAmount.
+type CampaignTotals struct {
+	CampaignID      string
+	OneTimeAmount   float64
+	RecurringAmount float64
+	DonationCount   int64
+	DonorCount      int64
+}
+
 // DonationRepository define as operações do repositório de doações
 type DonationRepository interface {
 	Repository
@@ -37,10 +63,17 @@ type DonationRepository interface {
 	Delete(ctx context.Context, communityID, id string) error
 	FindByID(ctx context.Context, communityID, id string) (*domain.Donation, error)
 	FindByAsaasID(ctx context.Context, communityID, asaasID string) (*domain.Donation, error)
+	LockByID(ctx context.Context, communityID, id string) (*domain.Donation, error)
+	LockByAsaasID(ctx context.Context, communityID, asaasID string) (*domain.Donation, error)
+	CreateCharge(ctx context.Context, donation *domain.Donation) (bool, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Donation, error)
 	CountByCommunityID(ctx context.Context, communityID string) (int64, error)
 	CountByCampaign(ctx context.Context, communityID, campaignID string) (int64, error)
 	SumAmountByCampaign(ctx context.Context, communityID, campaignID string) (float64, error)
+	SumByCampaigns(ctx context.Context, communityID string, campaignIDs []string) (map[string]*CampaignTotals, error)
+	FindPaidByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Donation, error)
+	ListUnposted(ctx context.Context, communityID string) ([]*domain.Donation, error)
+	StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *DonationExportRow) error) error
 }
 
 // RecurringDonationRepository define as operações do repositório de doações recorrentes
@@ -52,6 +85,7 @@ type RecurringDonationRepository interface {
 	FindByID(ctx context.Context, communityID, id string) (*domain.RecurringDonation, error)
 	FindByAsaasID(ctx context.Context, communityID, asaasID string) (*domain.RecurringDonation, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.RecurringDonation, error)
+	ListByMember(ctx context.Context, communityID, memberID string) ([]*domain.RecurringDonation, error)
 	CountByCommunityID(ctx context.Context, communityID string) (int64, error)
 	CountByCampaign(ctx context.Context, communityID, campaignID string) (int64, error)
 	SumAmountByCampaign(ctx context.Context, communityID, campaignID string) (float64, error)
@@ -101,6 +135,53 @@ func (r *asaasConfigRepository) FindByCommunityID(ctx context.Context, community
 	return &config, nil
 }
 
+func (r *asaasConfigRepository) FindByWebhookToken(ctx context.Context, token string) (*domain.AsaasConfig, error) {
+	var config domain.AsaasConfig
+	if err := r.GetDB().WithContext(ctx).First(&config, "webhook_token_digest = ?", secret.Digest(token)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &config, nil
+}
+
+type paymentGatewaySettingsRepository struct {
+	BaseRepository
+}
+
+func NewPaymentGatewaySettingsRepository(db *gorm.DB, logger *zap.Logger) PaymentGatewaySettingsRepository {
+	return &paymentGatewaySettingsRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+	}
+}
+
+func (r *paymentGatewaySettingsRepository) Save(ctx context.Context, settings *domain.PaymentGatewaySettings) error {
+	return r.GetDB().WithContext(ctx).Save(settings).Error
+}
+
+func (r *paymentGatewaySettingsRepository) FindByCommunityID(ctx context.Context, communityID string) (*domain.PaymentGatewaySettings, error) {
+	var settings domain.PaymentGatewaySettings
+	if err := r.GetDB().WithContext(ctx).First(&settings, "community_id = ?", communityID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *paymentGatewaySettingsRepository) FindByWebhookToken(ctx context.Context, provider, token string) (*domain.PaymentGatewaySettings, error) {
+	var settings domain.PaymentGatewaySettings
+	if err := r.GetDB().WithContext(ctx).First(&settings, "provider = ? AND webhook_token_digest = ?", provider, secret.Digest(token)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
 type campaignRepository struct {
 	BaseRepository
 }
@@ -139,9 +220,19 @@ func (r *campaignRepository) FindByCommunity(ctx context.Context, communityID st
 	var total int64
 
 	query := r.GetDB().WithContext(ctx).Model(&domain.Campaign{}).
-		Where("community_id = ?", communityID)
+		Where("community_id = ?", communityID).
+		Session(&gorm.Session{})
 
-	if err := query.Count(&total).Error; err != nil {
+	countQuery := query
+	if filter != nil {
+		if filter.Search != "" {
+			countQuery = countQuery.Where("name ILIKE ? OR description ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+		}
+		for _, cond := range filter.conditions {
+			countQuery = countQuery.Where(cond.query, cond.args...)
+		}
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -152,6 +243,34 @@ func (r *campaignRepository) FindByCommunity(ctx context.Context, communityID st
 	return campaigns, total, nil
 }
 
+// ListActive lista as campanhas ativas de todas as comunidades, com a comunidade carregada
+func (r *campaignRepository) ListActive(ctx context.Context) ([]*domain.Campaign, error) {
+	var campaigns []*domain.Campaign
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Community").
+		Where("status = ?", "active").
+		Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// Close encerra a campanha com o status informado em campaign.Status, desde que ela
+// ainda esteja ativa. Retorna false quando outra requisição já a encerrou.
+func (r *campaignRepository) Close(ctx context.Context, campaign *domain.Campaign) (bool, error) {
+	result := r.GetDB().WithContext(ctx).Model(&domain.Campaign{}).
+		Where("community_id = ? AND id = ? AND status = ?", campaign.CommunityID, campaign.ID, "active").
+		Updates(map[string]interface{}{
+			"status":     campaign.Status,
+			"closed_at":  campaign.ClosedAt,
+			"updated_at": campaign.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *campaignRepository) CountByCommunityID(ctx context.Context, communityID string) (int64, error) {
 	var count int64
 	err := r.GetDB().WithContext(ctx).Model(&domain.Campaign{}).Where("community_id = ?", communityID).Count(&count).Error
@@ -251,11 +370,39 @@ func (r *donationRepository) SumAmountByCampaign(ctx context.Context, communityI
 	var sum float64
 	err := r.GetDB().WithContext(ctx).Model(&domain.Donation{}).
 		Where("community_id = ? AND campaign_id = ? AND status = ?", communityID, campaignID, "paid").
-		Select("COALESCE(SUM(amount), 0)").
+		Select("COALESCE(SUM(amount - refunded_amount), 0)").
 		Scan(&sum).Error
 	return sum, err
 }
 
+// SumByCampaigns soma as doações pagas das campanhas informadas, descontados os
+// reembolsos parciais. Os doadores são identificados pelo membro ou, para doações
+// avulsas, pelo CPF ou e-mail.
+func (r *donationRepository) SumByCampaigns(ctx context.Context, communityID string, campaignIDs []string) (map[string]*CampaignTotals, error) {
+	totals := make(map[string]*CampaignTotals, len(campaignIDs))
+	if len(campaignIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []*CampaignTotals
+	if err := r.GetDB().WithContext(ctx).Model(&domain.Donation{}).
+		Select(`campaign_id,
+			COALESCE(SUM(CASE WHEN recurring_donation_id IS NULL THEN amount - refunded_amount ELSE 0 END), 0) AS one_time_amount,
+			COALESCE(SUM(CASE WHEN recurring_donation_id IS NOT NULL THEN amount - refunded_amount ELSE 0 END), 0) AS recurring_amount,
+			COUNT(*) AS donation_count,
+			COUNT(DISTINCT COALESCE(member_id::text, NULLIF(customer_cpf, ''), LOWER(customer_email))) AS donor_count`).
+		Where("community_id = ? AND campaign_id IN ? AND status = ?", communityID, campaignIDs, "paid").
+		Group("campaign_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		totals[row.CampaignID] = row
+	}
+	return totals, nil
+}
+
 func (r *donationRepository) CountByCommunityID(ctx context.Context, communityID string) (int64, error) {
 	var count int64
 	err := r.GetDB().WithContext(ctx).Model(&domain.Donation{}).Where("community_id = ?", communityID).Count(&count).Error
@@ -273,6 +420,121 @@ func (r *donationRepository) FindByAsaasID(ctx context.Context, communityID, asa
 	return &donation, nil
 }
 
+// LockByID busca a doação bloqueando o registro até o fim da transação
+func (r *donationRepository) LockByID(ctx context.Context, communityID, id string) (*domain.Donation, error) {
+	var donation domain.Donation
+	if err := r.GetDB().WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&donation, "community_id = ? AND id = ?", communityID, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &donation, nil
+}
+
+// LockByAsaasID busca a doação da cobrança bloqueando o registro até o fim da transação
+func (r *donationRepository) LockByAsaasID(ctx context.Context, communityID, asaasID string) (*domain.Donation, error) {
+	var donation domain.Donation
+	if err := r.GetDB().WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&donation, "community_id = ? AND asaas_id = ?", communityID, asaasID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &donation, nil
+}
+
+// CreateCharge grava a doação de uma cobrança do provedor. Retorna false, sem erro,
+// quando outra entrega do webhook já gravou a doação da mesma cobrança.
+func (r *donationRepository) CreateCharge(ctx context.Context, donation *domain.Donation) (bool, error) {
+	result := r.GetDB().WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "community_id"}, {Name: "asaas_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "asaas_id <> ''"}}},
+			DoNothing:   true,
+		}).
+		Create(donation)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindPaidByPeriod retorna as doações pagas de membros identificados no período [start, end).
+// Se memberID for vazio, retorna as doações de todos os membros.
+func (r *donationRepository) FindPaidByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Donation, error) {
+	var donations []*domain.Donation
+	query := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND status = ? AND member_id IS NOT NULL", communityID, "paid").
+		Where("paid_at >= ? AND paid_at < ?", start, end)
+
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+
+	if err := query.Order("paid_at asc").Find(&donations).Error; err != nil {
+		return nil, err
+	}
+	return donations, nil
+}
+
+// ListUnposted retorna as doações pagas que ainda não têm receita lançada no financeiro
+func (r *donationRepository) ListUnposted(ctx context.Context, communityID string) ([]*domain.Donation, error) {
+	var donations []*domain.Donation
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND status = ?", communityID, "paid").
+		Where("NOT EXISTS (SELECT 1 FROM revenues WHERE revenues.donation_id = donations.id)").
+		Order("paid_at asc").
+		Find(&donations).Error; err != nil {
+		return nil, err
+	}
+	return donations, nil
+}
+
+// DonationExportRow é uma linha da exportação de doações
+type DonationExportRow struct {
+	ID            string
+	DueDate       time.Time
+	PaidAt        *time.Time
+	Amount        float64
+	PaymentMethod string
+	Status        string
+	Description   string
+	CustomerName  string
+	CustomerCPF   string
+	CustomerEmail string
+	CampaignName  string
+	Recurring     bool
+}
+
+// StreamForExport percorre as doações do filtro, uma linha por vez, sem carregar toda a
+// tabela em memória. O período considera a data de pagamento ou, se ausente, o vencimento.
+func (r *donationRepository) StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *DonationExportRow) error) error {
+	query := r.GetDB().WithContext(ctx).
+		Table("donations").
+		Select(`donations.id, donations.due_date, donations.paid_at, donations.amount,
+			donations.payment_method, donations.status, donations.description,
+			donations.customer_name, donations.customer_cpf, donations.customer_email,
+			COALESCE(c.name, '') AS campaign_name,
+			donations.recurring_donation_id IS NOT NULL AS recurring`).
+		Joins("LEFT JOIN campaigns c ON c.id::text = donations.campaign_id::text").
+		Where("donations.community_id = ?", communityID)
+	query = applyExportFilter(query, filter, "COALESCE(donations.paid_at, donations.due_date)", "donations.status").
+		Order("COALESCE(donations.paid_at, donations.due_date) ASC")
+
+	return streamRows(query, func(db *gorm.DB, rows *sql.Rows) error {
+		var row DonationExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(&row)
+	})
+}
+
 func (r *donationRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Donation, error) {
 	var donations []*domain.Donation
 	query := r.GetDB().WithContext(ctx).Where("community_id = ?", communityID)
@@ -366,7 +628,7 @@ func (r *recurringDonationRepository) SumAmountByCampaign(ctx context.Context, c
 	var sum float64
 	err := r.GetDB().WithContext(ctx).Model(&domain.RecurringDonation{}).
 		Where("community_id = ? AND campaign_id = ? AND status = ?", communityID, campaignID, "active").
-		Select("COALESCE(SUM(amount), 0)").
+		Select("COALESCE(SUM(amount - refunded_amount), 0)").
 		Scan(&sum).Error
 	return sum, err
 }
@@ -401,3 +663,16 @@ func (r *recurringDonationRepository) List(ctx context.Context, communityID stri
 	}
 	return donations, nil
 }
+
+// ListByMember lista as doações recorrentes do membro, das mais recentes para as mais antigas
+func (r *recurringDonationRepository) ListByMember(ctx context.Context, communityID, memberID string) ([]*domain.RecurringDonation, error) {
+	var donations []*domain.RecurringDonation
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Campaign").
+		Where("community_id = ? AND member_id = ?", communityID, memberID).
+		Order("created_at DESC").
+		Find(&donations).Error; err != nil {
+		return nil, err
+	}
+	return donations, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/pkg/secret"
+)
+
+func TestWebhookTokenStoredEncryptedWithDigest(t *testing.T) {
+	masterKey, err := secret.GenerateMasterKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := secret.NewKeyring(masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := secret.Default()
+	secret.SetDefault(keyring)
+	t.Cleanup(func() { secret.SetDefault(previous) })
+
+	repos, recorder := newTestRepositories(t)
+	ctx := context.Background()
+	token := "token-do-webhook-da-comunidade"
+
+	if err := repos.AsaasConfig.Create(ctx, &domain.AsaasConfig{CommunityID: "c1", ApiKey: "chave", WebhookToken: token}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repos.PaymentGateway.Save(ctx, &domain.PaymentGatewaySettings{ID: "g1", CommunityID: "c1", Provider: "pix", WebhookToken: token}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// O token é gravado cifrado, acompanhado do resumo usado na busca
+	for _, prefix := range []string{`INSERT INTO "asaas_configs"`, `UPDATE "payment_gateway_settings"`} {
+		args := recorder.Args(prefix)
+		if len(args) != 1 {
+			t.Fatalf("%s: comandos = %d; esperado 1", prefix, len(args))
+		}
+		if containsValue(args[0], token) {
+			t.Errorf("%s: token gravado em texto puro: %v", prefix, args[0])
+		}
+		if !containsValue(args[0], secret.Digest(token)) {
+			t.Errorf("%s: resumo do token não gravado: %v", prefix, args[0])
+		}
+	}
+
+	if _, err := repos.AsaasConfig.FindByWebhookToken(ctx, token); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("AsaasConfig.FindByWebhookToken: erro %v; esperado %v", err, ErrNotFound)
+	}
+	if _, err := repos.PaymentGateway.FindByWebhookToken(ctx, "pix", token); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("PaymentGateway.FindByWebhookToken: erro %v; esperado %v", err, ErrNotFound)
+	}
+	for _, table := range []string{`"asaas_configs"`, `"payment_gateway_settings"`} {
+		prefix := `SELECT * FROM ` + table
+		statements, args := recorder.Statements(prefix), recorder.Args(prefix)
+		if len(statements) != 1 || !strings.Contains(statements[0], "webhook_token_digest = $") {
+			t.Errorf("consulta = %q; esperado busca por webhook_token_digest", statements)
+			continue
+		}
+		if containsValue(args[0], token) || !containsValue(args[0], secret.Digest(token)) {
+			t.Errorf("%s: parâmetros = %v; esperado apenas o resumo do token", table, args[0])
+		}
+	}
+}
+
+func containsValue(values []driver.Value, want string) bool {
+	for _, value := range values {
+		if s, ok := value.(string); ok && s == want {
+			return true
+		}
+	}
+	return false
+}
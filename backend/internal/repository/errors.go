@@ -5,4 +5,25 @@ import "errors"
 var (
 	// ErrNotFound é retornado quando um registro não é encontrado
 	ErrNotFound = errors.New("record not found")
+
+	// ErrBatchNotOpen é retornado ao alterar envelopes de um lote que não está aberto
+	ErrBatchNotOpen = errors.New("lote de contribuições não está aberto")
+
+	// ErrBatchNotCounted é retornado ao conferir um lote que não foi fechado para conferência
+	ErrBatchNotCounted = errors.New("lote de contribuições não está aguardando conferência")
+
+	// ErrTransactionResolved é retornado ao conciliar um lançamento bancário que já saiu da fila
+	ErrTransactionResolved = errors.New("lançamento bancário já conciliado")
+
+	// ErrTransactionNotResolved é retornado ao reabrir um lançamento bancário que ainda está na fila
+	ErrTransactionNotResolved = errors.New("lançamento bancário ainda não foi conciliado")
+
+	// ErrEntryNotPending é retornado ao conciliar uma despesa ou receita que não está pendente
+	ErrEntryNotPending = errors.New("lançamento financeiro não está pendente")
+
+	// ErrChildCheckedIn é retornado ao fazer o check-in de uma criança que ainda não foi retirada do evento
+	ErrChildCheckedIn = errors.New("criança já está com check-in ativo neste evento")
+
+	// ErrChildCheckedOut é retornado ao registrar a retirada de uma criança que já foi retirada
+	ErrChildCheckedOut = errors.New("criança já foi retirada")
 )
@@ -88,12 +88,33 @@ func (r *eventRepository) List(ctx context.Context, communityID string, filter *
 	return events, total, nil
 }
 
+// RegisterAttendance grava a presença e, quando o membro esteve no evento, a data da
+// última presença do membro
 func (r *eventRepository) RegisterAttendance(ctx context.Context, attendance *domain.Attendance) error {
-	return r.GetDB().WithContext(ctx).Create(attendance).Error
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attendance).Error; err != nil {
+			return err
+		}
+		if attendance.IsAbsent() {
+			return nil
+		}
+		return touchLastAttendance(tx, attendance.EventID, attendance.MemberID, attendance.CreatedAt)
+	})
 }
 
+// UpdateAttendance altera a presença e, quando o membro passou a constar como presente,
+// leva a data em que a presença foi registrada para a última presença do membro
 func (r *eventRepository) UpdateAttendance(ctx context.Context, attendance *domain.Attendance) error {
-	return r.GetDB().WithContext(ctx).
-		Where("event_id = ? AND member_id = ?", attendance.EventID, attendance.MemberID).
-		Updates(attendance).Error
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ? AND member_id = ?", attendance.EventID, attendance.MemberID).
+			Updates(attendance).Error; err != nil {
+			return err
+		}
+		if attendance.IsAbsent() {
+			return nil
+		}
+		registeredAt := gorm.Expr("(SELECT MAX(created_at) FROM attendances WHERE event_id = ? AND member_id = ?)",
+			attendance.EventID, attendance.MemberID)
+		return touchLastAttendance(tx, attendance.EventID, attendance.MemberID, registeredAt)
+	})
 }
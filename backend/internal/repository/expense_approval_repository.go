@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExpenseApprovalRepository define as operações do fluxo de aprovação de despesas:
+// regras de aprovação, decisões dos aprovadores e comprovantes anexados
+type ExpenseApprovalRepository interface {
+	Repository
+	CreateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error
+	UpdateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error
+	DeleteRule(ctx context.Context, communityID, ruleID string) error
+	FindRuleByID(ctx context.Context, communityID, ruleID string) (*domain.ExpenseApprovalRule, error)
+	ListRules(ctx context.Context, communityID string, filter *Filter) ([]*domain.ExpenseApprovalRule, int64, error)
+	FindActiveRules(ctx context.Context, communityID string) ([]*domain.ExpenseApprovalRule, error)
+	RecordDecision(ctx context.Context, expense *domain.Expense, approval *domain.ExpenseApproval, expectedStep int) error
+	ListApprovals(ctx context.Context, expenseID string) ([]*domain.ExpenseApproval, error)
+	ListAwaitingApproval(ctx context.Context, communityID string) ([]*domain.Expense, error)
+	CreateReceipt(ctx context.Context, receipt *domain.ExpenseReceipt) error
+	DeleteReceipt(ctx context.Context, expenseID, receiptID string) error
+	FindReceiptByID(ctx context.Context, expenseID, receiptID string) (*domain.ExpenseReceipt, error)
+	ListReceipts(ctx context.Context, expenseID string) ([]*domain.ExpenseReceipt, error)
+	CountReceipts(ctx context.Context, expenseID string) (int64, error)
+}
+
+type expenseApprovalRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewExpenseApprovalRepository(db *gorm.DB, logger *zap.Logger) ExpenseApprovalRepository {
+	return &expenseApprovalRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *expenseApprovalRepository) CreateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Create(rule).Error
+}
+
+func (r *expenseApprovalRepository) UpdateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Save(rule).Error
+}
+
+func (r *expenseApprovalRepository) DeleteRule(ctx context.Context, communityID, ruleID string) error {
+	return r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, ruleID).
+		Delete(&domain.ExpenseApprovalRule{}).Error
+}
+
+func (r *expenseApprovalRepository) FindRuleByID(ctx context.Context, communityID, ruleID string) (*domain.ExpenseApprovalRule, error) {
+	var rule domain.ExpenseApprovalRule
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Where("community_id = ? AND id = ?", communityID, ruleID).
+		First(&rule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *expenseApprovalRepository) ListRules(ctx context.Context, communityID string, filter *Filter) ([]*domain.ExpenseApprovalRule, int64, error) {
+	var rules []*domain.ExpenseApprovalRule
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.ExpenseApprovalRule{}).
+		Where("community_id = ?", communityID).
+		Session(&gorm.Session{})
+
+	countQuery := query
+	if filter != nil {
+		if filter.Search != "" {
+			countQuery = countQuery.Where("name ILIKE ? OR description ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+		}
+		for _, cond := range filter.conditions {
+			countQuery = countQuery.Where(cond.query, cond.args...)
+		}
+		if filter.OrderBy == "" {
+			filter.OrderBy = "step"
+		}
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("Category"), filter).Find(&rules).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rules, total, nil
+}
+
+// FindActiveRules lista as regras ativas da comunidade na ordem das etapas
+func (r *expenseApprovalRepository) FindActiveRules(ctx context.Context, communityID string) ([]*domain.ExpenseApprovalRule, error) {
+	var rules []*domain.ExpenseApprovalRule
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND active = ?", communityID, true).
+		Order("step ASC, min_amount ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		r.logger.Error("erro ao buscar regras de aprovação",
+			zap.Error(err),
+			zap.String("community_id", communityID))
+		return nil, err
+	}
+	return rules, nil
+}
+
+// RecordDecision registra a decisão do aprovador e atualiza a situação da despesa na
+// mesma transação. A despesa precisa continuar aguardando aprovação na etapa esperada;
+// caso contrário, outra decisão foi registrada antes e ErrEntryNotPending é retornado.
+func (r *expenseApprovalRepository) RecordDecision(ctx context.Context, expense *domain.Expense, approval *domain.ExpenseApproval, expectedStep int) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current domain.Expense
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("community_id = ? AND id = ?", expense.CommunityID, expense.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status != "requested" || current.ApprovalStep != expectedStep {
+			return ErrEntryNotPending
+		}
+
+		if err := tx.Omit(clause.Associations).Create(approval).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Expense{}).
+			Where("id = ?", expense.ID).
+			Updates(map[string]interface{}{
+				"status":          expense.Status,
+				"approval_steps":  expense.ApprovalSteps,
+				"approval_step":   expense.ApprovalStep,
+				"approved_by":     expense.ApprovedBy,
+				"approved_at":     expense.ApprovedAt,
+				"approved_amount": expense.ApprovedAmount,
+				"updated_at":      time.Now(),
+			}).Error
+	})
+}
+
+func (r *expenseApprovalRepository) ListApprovals(ctx context.Context, expenseID string) ([]*domain.ExpenseApproval, error) {
+	var approvals []*domain.ExpenseApproval
+	if err := r.GetDB().WithContext(ctx).
+		Preload("User").
+		Preload("Rule").
+		Where("expense_id = ?", expenseID).
+		Order("created_at ASC").
+		Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// ListAwaitingApproval lista as despesas da comunidade que aguardam aprovação
+func (r *expenseApprovalRepository) ListAwaitingApproval(ctx context.Context, communityID string) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Preload("Receipts").
+		Where("community_id = ? AND status = ?", communityID, "requested").
+		Order("due_date ASC, created_at ASC").
+		Find(&expenses).Error; err != nil {
+		r.logger.Error("erro ao buscar despesas aguardando aprovação",
+			zap.Error(err),
+			zap.String("community_id", communityID))
+		return nil, err
+	}
+	return expenses, nil
+}
+
+func (r *expenseApprovalRepository) CreateReceipt(ctx context.Context, receipt *domain.ExpenseReceipt) error {
+	return r.GetDB().WithContext(ctx).Create(receipt).Error
+}
+
+func (r *expenseApprovalRepository) DeleteReceipt(ctx context.Context, expenseID, receiptID string) error {
+	return r.GetDB().WithContext(ctx).
+		Where("expense_id = ? AND id = ?", expenseID, receiptID).
+		Delete(&domain.ExpenseReceipt{}).Error
+}
+
+func (r *expenseApprovalRepository) FindReceiptByID(ctx context.Context, expenseID, receiptID string) (*domain.ExpenseReceipt, error) {
+	var receipt domain.ExpenseReceipt
+	if err := r.GetDB().WithContext(ctx).
+		Where("expense_id = ? AND id = ?", expenseID, receiptID).
+		First(&receipt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (r *expenseApprovalRepository) ListReceipts(ctx context.Context, expenseID string) ([]*domain.ExpenseReceipt, error) {
+	var receipts []*domain.ExpenseReceipt
+	if err := r.GetDB().WithContext(ctx).
+		Where("expense_id = ?", expenseID).
+		Order("created_at ASC").
+		Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (r *expenseApprovalRepository) CountReceipts(ctx context.Context, expenseID string) (int64, error) {
+	var count int64
+	err := r.GetDB().WithContext(ctx).Model(&domain.ExpenseReceipt{}).
+		Where("expense_id = ?", expenseID).
+		Count(&count).Error
+	return count, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -92,3 +93,10 @@ func NewFilterFromQuery(c *gin.Context) *Filter {
 		Search:  search,
 	}
 }
+
+// ExportFilter define o período e o status dos registros exportados
+type ExportFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Status    string
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"math"
 	"time"
 
 	"github.com/comunidade/backend/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // FinancialCategoryRepository interface
@@ -26,6 +29,7 @@ type SupplierRepository interface {
 	Update(ctx context.Context, supplier *domain.Supplier) error
 	Delete(ctx context.Context, communityID, supplierID string) error
 	FindByID(ctx context.Context, communityID, supplierID string) (*domain.Supplier, error)
+	FindByCNPJ(ctx context.Context, communityID, cnpj string) (*domain.Supplier, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Supplier, int64, error)
 }
 
@@ -39,8 +43,15 @@ type ExpenseRepository interface {
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Expense, int64, error)
 	GetTotalByPeriod(ctx context.Context, communityID string, startDate, endDate time.Time) (float64, error)
 	GetTotalByCategory(ctx context.Context, communityID string, categoryID string, startDate, endDate time.Time) (float64, error)
+	GetTotalByCategoryAndGroup(ctx context.Context, communityID, categoryID, groupID string, startDate, endDate time.Time) (float64, error)
 	CountByCategory(ctx context.Context, communityID, categoryID string) (int64, error)
 	CountBySupplier(ctx context.Context, communityID, supplierID string) (int64, error)
+	StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *ExpenseExportRow) error) error
+	FindReconciliationCandidates(ctx context.Context, communityID string, amount float64, startDate, endDate time.Time) ([]*domain.Expense, error)
+	FindPayables(ctx context.Context, communityID string, until time.Time) ([]*domain.Expense, error)
+	FindDueForReminder(ctx context.Context, from, until time.Time) ([]*domain.Expense, error)
+	ClaimReminders(ctx context.Context, expenseIDs []string, sentAt time.Time) ([]string, error)
+	ReleaseReminders(ctx context.Context, expenseIDs []string) error
 }
 
 // RevenueRepository interface
@@ -53,7 +64,22 @@ type RevenueRepository interface {
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Revenue, int64, error)
 	GetTotalByPeriod(ctx context.Context, communityID string, startDate, endDate time.Time) (float64, error)
 	GetTotalByCategory(ctx context.Context, communityID string, categoryID string, startDate, endDate time.Time) (float64, error)
+	GetTotalByCategoryAndGroup(ctx context.Context, communityID, categoryID, groupID string, startDate, endDate time.Time) (float64, error)
 	CountByCategory(ctx context.Context, communityID, categoryID string) (int64, error)
+	FindByDonationID(ctx context.Context, communityID, donationID string) (*domain.Revenue, error)
+	CreateForDonation(ctx context.Context, revenue *domain.Revenue) (bool, error)
+	StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *RevenueExportRow) error) error
+	FindReconciliationCandidates(ctx context.Context, communityID string, amount float64, startDate, endDate time.Time) ([]*domain.Revenue, error)
+}
+
+// DonationPostingRuleRepository interface
+type DonationPostingRuleRepository interface {
+	Repository
+	Create(ctx context.Context, rule *domain.DonationPostingRule) error
+	Update(ctx context.Context, rule *domain.DonationPostingRule) error
+	Delete(ctx context.Context, communityID, ruleID string) error
+	FindByID(ctx context.Context, communityID, ruleID string) (*domain.DonationPostingRule, error)
+	List(ctx context.Context, communityID string) ([]*domain.DonationPostingRule, error)
 }
 
 // FinancialReportRepository interface
@@ -62,7 +88,7 @@ type FinancialReportRepository interface {
 	Create(ctx context.Context, report *domain.FinancialReport) error
 	FindByID(ctx context.Context, communityID, reportID string) (*domain.FinancialReport, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.FinancialReport, int64, error)
-	GenerateReport(ctx context.Context, communityID string, userID string, startDate, endDate time.Time) (*domain.FinancialReport, error)
+	GenerateReport(ctx context.Context, communityID string, userID string, reportType string, startDate, endDate time.Time) (*domain.FinancialReport, error)
 }
 
 // Implementações concretas
@@ -91,6 +117,11 @@ type financialReportRepository struct {
 	logger *zap.Logger
 }
 
+type donationPostingRuleRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
 // Construtores
 func NewFinancialCategoryRepository(db *gorm.DB, logger *zap.Logger) FinancialCategoryRepository {
 	return &financialCategoryRepository{
@@ -127,6 +158,13 @@ func NewFinancialReportRepository(db *gorm.DB, logger *zap.Logger) FinancialRepo
 	}
 }
 
+func NewDonationPostingRuleRepository(db *gorm.DB, logger *zap.Logger) DonationPostingRuleRepository {
+	return &donationPostingRuleRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
 // Implementações dos métodos do FinancialCategoryRepository
 func (r *financialCategoryRepository) Create(ctx context.Context, category *domain.FinancialCategory) error {
 	return r.GetDB().WithContext(ctx).Create(category).Error
@@ -212,6 +250,19 @@ func (r *supplierRepository) FindByID(ctx context.Context, communityID, supplier
 	return &supplier, nil
 }
 
+func (r *supplierRepository) FindByCNPJ(ctx context.Context, communityID, cnpj string) (*domain.Supplier, error) {
+	var supplier domain.Supplier
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND cnpj = ?", communityID, cnpj).
+		First(&supplier).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
 func (r *supplierRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Supplier, int64, error) {
 	var suppliers []*domain.Supplier
 	var total int64
@@ -250,10 +301,18 @@ func (r *expenseRepository) Update(ctx context.Context, expense *domain.Expense)
 	return r.GetDB().WithContext(ctx).Save(expense).Error
 }
 
+// Delete exclui a despesa junto com seus comprovantes e o histórico de aprovação
 func (r *expenseRepository) Delete(ctx context.Context, communityID, expenseID string) error {
-	return r.GetDB().WithContext(ctx).
-		Where("community_id = ? AND id = ?", communityID, expenseID).
-		Delete(&domain.Expense{}).Error
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expense_id = ?", expenseID).Delete(&domain.ExpenseApproval{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("expense_id = ?", expenseID).Delete(&domain.ExpenseReceipt{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("community_id = ? AND id = ?", communityID, expenseID).
+			Delete(&domain.Expense{}).Error
+	})
 }
 
 func (r *expenseRepository) FindByID(ctx context.Context, communityID, expenseID string) (*domain.Expense, error) {
@@ -313,10 +372,22 @@ func (r *expenseRepository) GetTotalByPeriod(ctx context.Context, communityID st
 	return total, err
 }
 
+// GetTotalByCategory soma os lançamentos não cancelados da categoria no período
 func (r *expenseRepository) GetTotalByCategory(ctx context.Context, communityID string, categoryID string, startDate, endDate time.Time) (float64, error) {
 	var total float64
 	err := r.GetDB().WithContext(ctx).Model(&domain.Expense{}).
-		Where("community_id = ? AND category_id = ? AND date BETWEEN ? AND ?", communityID, categoryID, startDate, endDate).
+		Where("community_id = ? AND category_id = ? AND status NOT IN ? AND date BETWEEN ? AND ?", communityID, categoryID, []string{"cancelled", "rejected"}, startDate, endDate).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetTotalByCategoryAndGroup soma os lançamentos não cancelados da categoria
+// atribuídos ao ministério (grupo) no período
+func (r *expenseRepository) GetTotalByCategoryAndGroup(ctx context.Context, communityID, categoryID, groupID string, startDate, endDate time.Time) (float64, error) {
+	var total float64
+	err := r.GetDB().WithContext(ctx).Model(&domain.Expense{}).
+		Where("community_id = ? AND category_id = ? AND group_id = ? AND status NOT IN ? AND date BETWEEN ? AND ?", communityID, categoryID, groupID, []string{"cancelled", "rejected"}, startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	return total, err
@@ -357,6 +428,19 @@ func (r *revenueRepository) Create(ctx context.Context, revenue *domain.Revenue)
 	return r.GetDB().WithContext(ctx).Create(revenue).Error
 }
 
+// CreateForDonation cria a receita da doação, a menos que a doação já tenha uma receita.
+// Retorna false quando a receita já existia, como no lançamento concorrente da mesma
+// doação; o conflito não aborta a transação em andamento.
+func (r *revenueRepository) CreateForDonation(ctx context.Context, revenue *domain.Revenue) (bool, error) {
+	result := r.GetDB().WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "donation_id"}}, DoNothing: true}).
+		Create(revenue)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *revenueRepository) Update(ctx context.Context, revenue *domain.Revenue) error {
 	return r.GetDB().WithContext(ctx).Save(revenue).Error
 }
@@ -422,10 +506,22 @@ func (r *revenueRepository) GetTotalByPeriod(ctx context.Context, communityID st
 	return total, err
 }
 
+// GetTotalByCategory soma os lançamentos não cancelados da categoria no período
 func (r *revenueRepository) GetTotalByCategory(ctx context.Context, communityID string, categoryID string, startDate, endDate time.Time) (float64, error) {
 	var total float64
 	err := r.GetDB().WithContext(ctx).Model(&domain.Revenue{}).
-		Where("community_id = ? AND category_id = ? AND date BETWEEN ? AND ?", communityID, categoryID, startDate, endDate).
+		Where("community_id = ? AND category_id = ? AND status <> ? AND date BETWEEN ? AND ?", communityID, categoryID, "cancelled", startDate, endDate).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetTotalByCategoryAndGroup soma os lançamentos não cancelados da categoria
+// atribuídos ao ministério (grupo) no período
+func (r *revenueRepository) GetTotalByCategoryAndGroup(ctx context.Context, communityID, categoryID, groupID string, startDate, endDate time.Time) (float64, error) {
+	var total float64
+	err := r.GetDB().WithContext(ctx).Model(&domain.Revenue{}).
+		Where("community_id = ? AND category_id = ? AND group_id = ? AND status <> ? AND date BETWEEN ? AND ?", communityID, categoryID, groupID, "cancelled", startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	return total, err
@@ -446,6 +542,281 @@ func (r *revenueRepository) CountByCategory(ctx context.Context, communityID, ca
 	return count, nil
 }
 
+func (r *revenueRepository) FindByDonationID(ctx context.Context, communityID, donationID string) (*domain.Revenue, error) {
+	var revenue domain.Revenue
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND donation_id = ?", communityID, donationID).
+		First(&revenue).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revenue, nil
+}
+
+// FindReconciliationCandidates busca as despesas a pagar (pendentes ou aprovadas) com
+// o valor informado cuja data ou vencimento esteja no período, para conciliação com
+// o extrato bancário
+func (r *expenseRepository) FindReconciliationCandidates(ctx context.Context, communityID string, amount float64, startDate, endDate time.Time) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Where("community_id = ? AND status IN ?", communityID, []string{"pending", "approved"}).
+		Where("amount = ROUND(CAST(? AS numeric), 2)", amount).
+		Where("(date BETWEEN ? AND ?) OR (due_date BETWEEN ? AND ?)", startDate, endDate, startDate, endDate).
+		Order("due_date ASC, date ASC").
+		Find(&expenses).Error; err != nil {
+		r.logger.Error("erro ao buscar despesas para conciliação",
+			zap.Error(err),
+			zap.String("community_id", communityID))
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// FindPayables busca as despesas em aberto (aguardando aprovação, aprovadas ou
+// pendentes) com vencimento até a data informada, incluindo as vencidas, ordenadas
+// pelo vencimento
+func (r *expenseRepository) FindPayables(ctx context.Context, communityID string, until time.Time) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Preload("Group").
+		Where("community_id = ? AND status IN ? AND due_date <= ?", communityID, []string{"requested", "approved", "pending"}, until).
+		Order("due_date ASC, amount DESC").
+		Find(&expenses).Error; err != nil {
+		r.logger.Error("erro ao buscar contas a pagar",
+			zap.Error(err),
+			zap.String("community_id", communityID))
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// FindDueForReminder busca, em todas as comunidades, as despesas em aberto com
+// vencimento no período cujo lembrete ainda não foi enviado
+func (r *expenseRepository) FindDueForReminder(ctx context.Context, from, until time.Time) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Preload("RecurringExpense").
+		Where("status IN ? AND reminder_sent_at IS NULL", []string{"requested", "approved", "pending"}).
+		Where("due_date BETWEEN ? AND ?", from, until).
+		Order("community_id, due_date ASC").
+		Find(&expenses).Error; err != nil {
+		r.logger.Error("erro ao buscar despesas para lembrete", zap.Error(err))
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// ClaimReminders registra o envio do lembrete de vencimento das despesas que ainda não
+// foram lembradas e retorna os IDs registrados. O registro é feito antes do envio para
+// que duas execuções simultâneas não enviem o mesmo lembrete.
+func (r *expenseRepository) ClaimReminders(ctx context.Context, expenseIDs []string, sentAt time.Time) ([]string, error) {
+	if len(expenseIDs) == 0 {
+		return nil, nil
+	}
+	var claimed []*domain.Expense
+	if err := r.GetDB().WithContext(ctx).Model(&claimed).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN ? AND reminder_sent_at IS NULL", expenseIDs).
+		Update("reminder_sent_at", sentAt).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(claimed))
+	for _, expense := range claimed {
+		ids = append(ids, expense.ID)
+	}
+	return ids, nil
+}
+
+// ReleaseReminders desfaz o registro do lembrete quando o envio falha, para que seja
+// tentado novamente na próxima execução
+func (r *expenseRepository) ReleaseReminders(ctx context.Context, expenseIDs []string) error {
+	if len(expenseIDs) == 0 {
+		return nil
+	}
+	return r.GetDB().WithContext(ctx).Model(&domain.Expense{}).
+		Where("id IN ?", expenseIDs).
+		Update("reminder_sent_at", nil).Error
+}
+
+// FindReconciliationCandidates busca as receitas pendentes com o valor informado cuja
+// data esteja no período, para conciliação com o extrato bancário
+func (r *revenueRepository) FindReconciliationCandidates(ctx context.Context, communityID string, amount float64, startDate, endDate time.Time) ([]*domain.Revenue, error) {
+	var revenues []*domain.Revenue
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Where("community_id = ? AND status = ?", communityID, "pending").
+		Where("amount = ROUND(CAST(? AS numeric), 2)", amount).
+		Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("date ASC").
+		Find(&revenues).Error; err != nil {
+		r.logger.Error("erro ao buscar receitas para conciliação",
+			zap.Error(err),
+			zap.String("community_id", communityID))
+		return nil, err
+	}
+	return revenues, nil
+}
+
+// Implementações dos métodos do DonationPostingRuleRepository
+func (r *donationPostingRuleRepository) Create(ctx context.Context, rule *domain.DonationPostingRule) error {
+	return r.GetDB().WithContext(ctx).Create(rule).Error
+}
+
+func (r *donationPostingRuleRepository) Update(ctx context.Context, rule *domain.DonationPostingRule) error {
+	return r.GetDB().WithContext(ctx).Save(rule).Error
+}
+
+func (r *donationPostingRuleRepository) Delete(ctx context.Context, communityID, ruleID string) error {
+	return r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, ruleID).
+		Delete(&domain.DonationPostingRule{}).Error
+}
+
+func (r *donationPostingRuleRepository) FindByID(ctx context.Context, communityID, ruleID string) (*domain.DonationPostingRule, error) {
+	var rule domain.DonationPostingRule
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Where("community_id = ? AND id = ?", communityID, ruleID).
+		First(&rule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *donationPostingRuleRepository) List(ctx context.Context, communityID string) ([]*domain.DonationPostingRule, error) {
+	var rules []*domain.DonationPostingRule
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Campaign").
+		Where("community_id = ?", communityID).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ExpenseExportRow é uma linha da exportação de despesas
+type ExpenseExportRow struct {
+	ID           string
+	Date         time.Time
+	DueDate      time.Time
+	PaidAt       *time.Time
+	Description  string
+	Amount       float64
+	Status       string
+	PaymentType  string
+	CategoryName string
+	SupplierName string
+	SupplierCNPJ string
+	EventTitle   string
+}
+
+// StreamForExport percorre as despesas do filtro em ordem de data, uma linha por vez,
+// sem carregar toda a tabela em memória
+func (r *expenseRepository) StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *ExpenseExportRow) error) error {
+	query := r.GetDB().WithContext(ctx).
+		Table("expenses").
+		Select(`expenses.id, expenses.date, expenses.due_date, expenses.paid_at, expenses.description,
+			expenses.amount, expenses.status, expenses.payment_type,
+			COALESCE(c.name, '') AS category_name, COALESCE(s.name, '') AS supplier_name,
+			COALESCE(s.cnpj, '') AS supplier_cnpj, COALESCE(e.title, '') AS event_title`).
+		Joins("LEFT JOIN financial_categories c ON c.id = expenses.category_id").
+		Joins("LEFT JOIN suppliers s ON s.id = expenses.supplier_id").
+		Joins("LEFT JOIN events e ON e.id = expenses.event_id").
+		Where("expenses.community_id = ?", communityID)
+	query = applyExportFilter(query, filter, "expenses.date", "expenses.status").
+		Order("expenses.date ASC, expenses.created_at ASC")
+
+	return streamRows(query, func(db *gorm.DB, rows *sql.Rows) error {
+		var row ExpenseExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(&row)
+	})
+}
+
+// RevenueExportRow é uma linha da exportação de receitas
+type RevenueExportRow struct {
+	ID           string
+	Date         time.Time
+	ReceivedAt   *time.Time
+	Description  string
+	Amount       float64
+	Status       string
+	PaymentType  string
+	CategoryName string
+	EventTitle   string
+}
+
+// StreamForExport percorre as receitas do filtro em ordem de data, uma linha por vez,
+// sem carregar toda a tabela em memória
+func (r *revenueRepository) StreamForExport(ctx context.Context, communityID string, filter *ExportFilter, fn func(row *RevenueExportRow) error) error {
+	query := r.GetDB().WithContext(ctx).
+		Table("revenues").
+		Select(`revenues.id, revenues.date, revenues.received_at, revenues.description,
+			revenues.amount, revenues.status, revenues.payment_type,
+			COALESCE(c.name, '') AS category_name, COALESCE(e.title, '') AS event_title`).
+		Joins("LEFT JOIN financial_categories c ON c.id = revenues.category_id").
+		Joins("LEFT JOIN events e ON e.id = revenues.event_id").
+		Where("revenues.community_id = ?", communityID)
+	query = applyExportFilter(query, filter, "revenues.date", "revenues.status").
+		Order("revenues.date ASC, revenues.created_at ASC")
+
+	return streamRows(query, func(db *gorm.DB, rows *sql.Rows) error {
+		var row RevenueExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(&row)
+	})
+}
+
+// applyExportFilter aplica o período e o status da exportação nas colunas informadas
+func applyExportFilter(query *gorm.DB, filter *ExportFilter, dateColumn, statusColumn string) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.StartDate != nil {
+		query = query.Where(dateColumn+" >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where(dateColumn+" <= ?", *filter.EndDate)
+	}
+	if filter.Status != "" {
+		query = query.Where(statusColumn+" = ?", filter.Status)
+	}
+	return query
+}
+
+// streamRows executa a consulta e chama fn para cada linha do resultado
+func streamRows(query *gorm.DB, fn func(db *gorm.DB, rows *sql.Rows) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(query, rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // Implementações dos métodos do FinancialReportRepository
 func (r *financialReportRepository) Create(ctx context.Context, report *domain.FinancialReport) error {
 	return r.GetDB().WithContext(ctx).Create(report).Error
@@ -454,6 +825,9 @@ func (r *financialReportRepository) Create(ctx context.Context, report *domain.F
 func (r *financialReportRepository) FindByID(ctx context.Context, communityID, reportID string) (*domain.FinancialReport, error) {
 	var report domain.FinancialReport
 	if err := r.GetDB().WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type ASC, dimension ASC, month ASC, amount DESC")
+		}).
 		Where("community_id = ? AND id = ?", communityID, reportID).
 		First(&report).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -489,44 +863,255 @@ func (r *financialReportRepository) List(ctx context.Context, communityID string
 	return reports, total, nil
 }
 
-func (r *financialReportRepository) GenerateReport(ctx context.Context, communityID string, userID string, startDate, endDate time.Time) (*domain.FinancialReport, error) {
-	var totalRevenue float64
-	var totalExpense float64
+// GenerateReport calcula os totais do período, o detalhamento por categoria, evento,
+// fornecedor e mês e a comparação com o período anterior, salvando tudo em uma transação
+func (r *financialReportRepository) GenerateReport(ctx context.Context, communityID string, userID string, reportType string, startDate, endDate time.Time) (*domain.FinancialReport, error) {
+	previousStart, previousEnd := previousReportPeriod(reportType, startDate, endDate)
 
-	// Calcula o total de receitas
-	if err := r.GetDB().WithContext(ctx).Model(&domain.Revenue{}).
-		Where("community_id = ? AND date BETWEEN ? AND ?", communityID, startDate, endDate).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalRevenue).Error; err != nil {
+	report := &domain.FinancialReport{
+		CommunityID:       communityID,
+		UserID:            userID,
+		Type:              reportType,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		PreviousStartDate: &previousStart,
+		PreviousEndDate:   &previousEnd,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	err := r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		// Calcula os totais de receitas e despesas dos dois períodos
+		if report.TotalRevenue, err = sumReportTotal(tx, "revenues", communityID, startDate, endDate); err != nil {
+			return err
+		}
+		if report.TotalExpense, err = sumReportTotal(tx, "expenses", communityID, startDate, endDate); err != nil {
+			return err
+		}
+		if report.PreviousRevenue, err = sumReportTotal(tx, "revenues", communityID, previousStart, previousEnd); err != nil {
+			return err
+		}
+		if report.PreviousExpense, err = sumReportTotal(tx, "expenses", communityID, previousStart, previousEnd); err != nil {
+			return err
+		}
+		report.Balance = report.TotalRevenue - report.TotalExpense
+		report.PreviousBalance = report.PreviousRevenue - report.PreviousExpense
+		report.RevenueVariation = reportVariation(report.TotalRevenue, report.PreviousRevenue)
+		report.ExpenseVariation = reportVariation(report.TotalExpense, report.PreviousExpense)
+
+		// Calcula o detalhamento de cada dimensão
+		monthShift := monthsBetween(previousStart, startDate)
+		breakdowns := []struct {
+			itemType  string
+			table     string
+			dimension string
+		}{
+			{"revenue", "revenues", "category"},
+			{"revenue", "revenues", "event"},
+			{"revenue", "revenues", "month"},
+			{"expense", "expenses", "category"},
+			{"expense", "expenses", "event"},
+			{"expense", "expenses", "supplier"},
+			{"expense", "expenses", "month"},
+		}
+		for _, b := range breakdowns {
+			current, err := aggregateReport(tx, b.table, b.dimension, communityID, startDate, endDate)
+			if err != nil {
+				return err
+			}
+			previous, err := aggregateReport(tx, b.table, b.dimension, communityID, previousStart, previousEnd)
+			if err != nil {
+				return err
+			}
+			report.Items = append(report.Items, mergeReportItems(b.itemType, b.dimension, current, previous, monthShift)...)
+		}
+
+		// Salva o relatório com os itens
+		return tx.Create(report).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Calcula o total de despesas
-	if err := r.GetDB().WithContext(ctx).Model(&domain.Expense{}).
-		Where("community_id = ? AND date BETWEEN ? AND ?", communityID, startDate, endDate).
+	return report, nil
+}
+
+// reportAggregate é o resultado do agrupamento de receitas ou despesas
+type reportAggregate struct {
+	ReferenceID *string
+	Label       string
+	Total       float64
+	Count       int
+}
+
+func sumReportTotal(tx *gorm.DB, table, communityID string, startDate, endDate time.Time) (float64, error) {
+	var total float64
+	err := tx.Table(table).
+		Where("community_id = ? AND status NOT IN ? AND date BETWEEN ? AND ?", communityID, []string{"cancelled", "rejected"}, startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalExpense).Error; err != nil {
-		return nil, err
+		Scan(&total).Error
+	return total, err
+}
+
+// aggregateReport agrupa os lançamentos não cancelados nem rejeitados do período pela dimensão informada
+func aggregateReport(tx *gorm.DB, table, dimension, communityID string, startDate, endDate time.Time) ([]reportAggregate, error) {
+	query := tx.Table(table+" AS t").
+		Where("t.community_id = ? AND t.status NOT IN ? AND t.date BETWEEN ? AND ?", communityID, []string{"cancelled", "rejected"}, startDate, endDate)
+
+	switch dimension {
+	case "category":
+		query = query.
+			Select("t.category_id AS reference_id, COALESCE(c.name, '') AS label, COALESCE(SUM(t.amount), 0) AS total, COUNT(*) AS count").
+			Joins("LEFT JOIN financial_categories c ON c.id = t.category_id").
+			Group("t.category_id, c.name")
+	case "event":
+		query = query.
+			Select("t.event_id AS reference_id, COALESCE(e.title, 'Sem evento') AS label, COALESCE(SUM(t.amount), 0) AS total, COUNT(*) AS count").
+			Joins("LEFT JOIN events e ON e.id = t.event_id").
+			Group("t.event_id, e.title")
+	case "supplier":
+		query = query.
+			Select("t.supplier_id AS reference_id, COALESCE(s.name, 'Sem fornecedor') AS label, COALESCE(SUM(t.amount), 0) AS total, COUNT(*) AS count").
+			Joins("LEFT JOIN suppliers s ON s.id = t.supplier_id").
+			Group("t.supplier_id, s.name")
+	case "month":
+		query = query.
+			Select("TO_CHAR(t.date, 'YYYY-MM') AS label, COALESCE(SUM(t.amount), 0) AS total, COUNT(*) AS count").
+			Group("TO_CHAR(t.date, 'YYYY-MM')")
 	}
 
-	// Cria o relatório
-	report := &domain.FinancialReport{
-		CommunityID:  communityID,
-		UserID:       userID,
-		Type:         "custom",
-		StartDate:    startDate,
-		EndDate:      endDate,
-		TotalRevenue: totalRevenue,
-		TotalExpense: totalExpense,
-		Balance:      totalRevenue - totalExpense,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-	}
-
-	// Salva o relatório
-	if err := r.GetDB().WithContext(ctx).Create(report).Error; err != nil {
+	var rows []reportAggregate
+	if err := query.Order("total DESC").Scan(&rows).Error; err != nil {
 		return nil, err
 	}
+	return rows, nil
+}
 
-	return report, nil
+// mergeReportItems une os agrupamentos do período atual e do anterior. Os meses do
+// período anterior são comparados com os meses correspondentes do período atual.
+func mergeReportItems(itemType, dimension string, current, previous []reportAggregate, monthShift int) []*domain.FinancialReportItem {
+	items := make([]*domain.FinancialReportItem, 0, len(current))
+	index := make(map[string]*domain.FinancialReportItem)
+
+	key := func(row reportAggregate) string {
+		if dimension == "month" {
+			return row.Label
+		}
+		if row.ReferenceID == nil {
+			return ""
+		}
+		return *row.ReferenceID
+	}
+
+	for _, row := range current {
+		item := &domain.FinancialReportItem{
+			Dimension:   dimension,
+			Type:        itemType,
+			ReferenceID: row.ReferenceID,
+			Label:       row.Label,
+			Amount:      row.Total,
+			Count:       row.Count,
+			CreatedAt:   time.Now(),
+		}
+		if dimension == "month" {
+			item.Month = row.Label
+			item.Label = monthLabel(row.Label)
+			item.ReferenceID = nil
+		}
+		index[key(row)] = item
+		items = append(items, item)
+	}
+
+	for _, row := range previous {
+		k := key(row)
+		if dimension == "month" {
+			k = shiftMonth(row.Label, monthShift)
+		}
+
+		item, ok := index[k]
+		if !ok {
+			item = &domain.FinancialReportItem{
+				Dimension:   dimension,
+				Type:        itemType,
+				ReferenceID: row.ReferenceID,
+				Label:       row.Label,
+				CreatedAt:   time.Now(),
+			}
+			if dimension == "month" {
+				item.Month = k
+				item.Label = monthLabel(k)
+				item.ReferenceID = nil
+			}
+			index[k] = item
+			items = append(items, item)
+		}
+		item.PreviousAmount = row.Total
+		item.PreviousCount = row.Count
+	}
+
+	for _, item := range items {
+		item.Variation = reportVariation(item.Amount, item.PreviousAmount)
+	}
+
+	return items
+}
+
+// previousReportPeriod retorna o período anterior de mesma duração do relatório. Nos
+// relatórios mensais e anuais, o período anterior vai do primeiro ao último dia do mês
+// ou do ano anterior ao da data inicial, mantendo o horário das datas informadas.
+func previousReportPeriod(reportType string, startDate, endDate time.Time) (time.Time, time.Time) {
+	switch reportType {
+	case "daily":
+		return startDate.AddDate(0, 0, -1), endDate.AddDate(0, 0, -1)
+	case "weekly":
+		return startDate.AddDate(0, 0, -7), endDate.AddDate(0, 0, -7)
+	case "monthly":
+		year, month, _ := startDate.Date()
+		// O dia 0 do mês corrente é o último dia do mês anterior
+		return atClock(year, month-1, 1, startDate), atClock(year, month, 0, endDate)
+	case "yearly":
+		year := startDate.Year() - 1
+		return atClock(year, time.January, 1, startDate), atClock(year, time.December, 31, endDate)
+	default:
+		previousEnd := startDate.AddDate(0, 0, -1)
+		return previousEnd.Add(-endDate.Sub(startDate)), previousEnd
+	}
+}
+
+// atClock retorna a data informada com o horário e o fuso de clock
+func atClock(year int, month time.Month, day int, clock time.Time) time.Time {
+	return time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), clock.Location())
+}
+
+// reportVariation calcula a variação percentual em relação ao período anterior
+func reportVariation(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	variation := math.Round((current-previous)/math.Abs(previous)*10000) / 100
+	return &variation
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// shiftMonth desloca um mês no formato YYYY-MM
+func shiftMonth(month string, months int) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return month
+	}
+	return t.AddDate(0, months, 0).Format("2006-01")
+}
+
+// monthLabel formata um mês YYYY-MM como MM/YYYY
+func monthLabel(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return month
+	}
+	return t.Format("01/2006")
 }
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestPreviousReportPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	endOfDay := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		reportType string
+		start, end time.Time
+		wantStart  time.Time
+		wantEnd    time.Time
+	}{
+		{"diário", "daily", date(2026, 3, 1), endOfDay(2026, 3, 1), date(2026, 2, 28), endOfDay(2026, 2, 28)},
+		{"semanal", "weekly", date(2026, 3, 2), endOfDay(2026, 3, 8), date(2026, 2, 23), endOfDay(2026, 3, 1)},
+		{"mensal após fevereiro", "monthly", date(2026, 3, 1), endOfDay(2026, 3, 31), date(2026, 2, 1), endOfDay(2026, 2, 28)},
+		{"mensal após mês de 31 dias", "monthly", date(2026, 4, 1), endOfDay(2026, 4, 30), date(2026, 3, 1), endOfDay(2026, 3, 31)},
+		{"mensal em janeiro", "monthly", date(2026, 1, 1), endOfDay(2026, 1, 31), date(2025, 12, 1), endOfDay(2025, 12, 31)},
+		{"mensal em ano bissexto", "monthly", date(2024, 3, 1), endOfDay(2024, 3, 31), date(2024, 2, 1), endOfDay(2024, 2, 29)},
+		{"anual", "yearly", date(2026, 1, 1), endOfDay(2026, 12, 31), date(2025, 1, 1), endOfDay(2025, 12, 31)},
+		{"anual após ano bissexto", "yearly", date(2025, 1, 1), endOfDay(2025, 12, 31), date(2024, 1, 1), endOfDay(2024, 12, 31)},
+		{"personalizado", "custom", date(2026, 3, 10), date(2026, 3, 19), date(2026, 2, 28), date(2026, 3, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd := previousReportPeriod(tt.reportType, tt.start, tt.end)
+			if !gotStart.Equal(tt.wantStart) || !gotEnd.Equal(tt.wantEnd) {
+				t.Errorf("previousReportPeriod(%s, %s, %s) = %s, %s; esperado %s, %s",
+					tt.reportType, tt.start, tt.end, gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+			if !gotEnd.Before(tt.start) {
+				t.Errorf("o período anterior termina em %s, depois do início do período atual %s", gotEnd, tt.start)
+			}
+		})
+	}
+}
+
+func TestCreateForDonationIgnoresConflict(t *testing.T) {
+	repos, recorder := newTestRepositories(t)
+	donationID := "33333333-3333-3333-3333-333333333333"
+	created, err := repos.Revenue.CreateForDonation(context.Background(), &domain.Revenue{
+		CommunityID: "c1",
+		Amount:      100,
+		Status:      "received",
+		DonationID:  &donationID,
+	})
+	if err != nil || !created {
+		t.Fatalf("CreateForDonation = %v, %v; esperado true, nil", created, err)
+	}
+
+	// Um INSERT que falha pela restrição única aborta a transação do webhook no PostgreSQL
+	inserts := recorder.Statements(`INSERT INTO "revenues"`)
+	if len(inserts) != 1 || !strings.Contains(inserts[0], `ON CONFLICT ("donation_id") DO NOTHING`) {
+		t.Errorf("comando = %q; esperado INSERT com ON CONFLICT (donation_id) DO NOTHING", inserts)
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GivingStatementRepository define as operações dos envios de comprovantes anuais
+type GivingStatementRepository interface {
+	Repository
+	CreateBatch(ctx context.Context, batch *domain.GivingStatementBatch) error
+	UpdateBatch(ctx context.Context, batch *domain.GivingStatementBatch) error
+	ListPendingBatches(ctx context.Context) ([]*domain.GivingStatementBatch, error)
+}
+
+type givingStatementRepository struct {
+	BaseRepository
+}
+
+func NewGivingStatementRepository(db *gorm.DB, logger *zap.Logger) GivingStatementRepository {
+	return &givingStatementRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+	}
+}
+
+func (r *givingStatementRepository) CreateBatch(ctx context.Context, batch *domain.GivingStatementBatch) error {
+	return r.GetDB().WithContext(ctx).Create(batch).Error
+}
+
+func (r *givingStatementRepository) UpdateBatch(ctx context.Context, batch *domain.GivingStatementBatch) error {
+	return r.GetDB().WithContext(ctx).Save(batch).Error
+}
+
+// ListPendingBatches lista os envios ainda não concluídos, dos mais antigos para os mais novos
+func (r *givingStatementRepository) ListPendingBatches(ctx context.Context) ([]*domain.GivingStatementBatch, error) {
+	var batches []*domain.GivingStatementBatch
+	if err := r.GetDB().WithContext(ctx).
+		Where("status = ?", domain.GivingStatementBatchPending).
+		Order("created_at").
+		Find(&batches).Error; err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// HouseholdChange reúne as alterações de uma família gravadas em uma única transação:
+// os vínculos em family_members e os campos de família e parentesco dos membros
+type HouseholdChange struct {
+	// Famílias criadas ou alteradas
+	Families []*domain.Family
+	// Copia o endereço das famílias alteradas para o cadastro dos seus membros
+	ApplyAddress bool
+	// Membros desvinculados da família em que estão
+	Unlink []string
+	// Vínculos novos ou alterados. Um vínculo novo tira o membro da família anterior.
+	Links []*domain.FamilyMember
+	// Membros com família, papel, cônjuge ou pai/mãe alterados
+	Members []*domain.Member
+	// Família removida junto com os vínculos restantes
+	DeleteFamilyID string
+}
+
+// HouseholdRepository define as operações que mantêm as famílias (Family e FamilyMember)
+// e os campos de família e parentesco dos membros sincronizados
+type HouseholdRepository interface {
+	Repository
+	Save(ctx context.Context, change *HouseholdChange) error
+	FindRelatives(ctx context.Context, communityID string, ids []string) ([]*domain.Member, error)
+	ListLinks(ctx context.Context, communityID string) ([]*domain.FamilyMember, error)
+	ListActiveMembers(ctx context.Context, communityID string) ([]*domain.Member, error)
+}
+
+type householdRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewHouseholdRepository(db *gorm.DB, logger *zap.Logger) HouseholdRepository {
+	return &householdRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *householdRepository) Save(ctx context.Context, change *HouseholdChange) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		for _, family := range change.Families {
+			if family.ID == "" {
+				family.ID = uuid.New().String()
+			}
+			if family.CreatedAt.IsZero() {
+				family.CreatedAt = now
+			}
+			family.UpdatedAt = now
+			if err := tx.Save(family).Error; err != nil {
+				return err
+			}
+			if !change.ApplyAddress {
+				continue
+			}
+			if err := tx.Model(&domain.Member{}).
+				Where("community_id = ? AND family_id = ?", family.CommunityID, family.ID).
+				Updates(map[string]interface{}{
+					"address":      family.Address,
+					"number":       family.Number,
+					"neighborhood": family.Neighborhood,
+					"city":         family.City,
+					"state":        family.State,
+					"country":      family.Country,
+					"zip_code":     family.ZipCode,
+					"updated_at":   now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, memberID := range change.Unlink {
+			if err := tx.Where("member_id = ?", memberID).Delete(&domain.FamilyMember{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, link := range change.Links {
+			if link.ID == "" {
+				if err := tx.Where("member_id = ?", link.MemberID).Delete(&domain.FamilyMember{}).Error; err != nil {
+					return err
+				}
+				link.ID = uuid.New().String()
+				link.CreatedAt = now
+			}
+			link.UpdatedAt = now
+			if err := tx.Save(link).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, member := range change.Members {
+			member.UpdatedAt = now
+			if err := tx.Model(member).
+				Where("community_id = ?", member.CommunityID).
+				Select("family_id", "family_role", "spouse_id", "parent_id", "updated_at").
+				Updates(member).Error; err != nil {
+				return err
+			}
+		}
+
+		if change.DeleteFamilyID != "" {
+			if err := tx.Where("family_id = ?", change.DeleteFamilyID).Delete(&domain.FamilyMember{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id = ?", change.DeleteFamilyID).Delete(&domain.Family{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindRelatives busca os membros informados, os seus cônjuges e os seus filhos
+func (r *householdRepository) FindRelatives(ctx context.Context, communityID string, ids []string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if len(ids) == 0 {
+		return members, nil
+	}
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		Where("id IN ? OR spouse_id IN ? OR parent_id IN ?", ids, ids, ids).
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ListLinks lista os vínculos de todas as famílias da comunidade
+func (r *householdRepository) ListLinks(ctx context.Context, communityID string) ([]*domain.FamilyMember, error) {
+	var links []*domain.FamilyMember
+	if err := r.GetDB().WithContext(ctx).
+		Joins("JOIN families ON families.id = family_members.family_id").
+		Where("families.community_id = ?", communityID).
+		Order("family_members.created_at").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *householdRepository) ListActiveMembers(ctx context.Context, communityID string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND status = ?", communityID, "active").
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
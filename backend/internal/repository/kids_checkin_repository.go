@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSecurityCodeAttempts limita as tentativas de gerar um código de segurança livre no evento
+const maxSecurityCodeAttempts = 20
+
+// KidsCheckInSession reúne o check-in das crianças trazidas por um responsável
+type KidsCheckInSession struct {
+	CommunityID string
+	EventID     string
+	// Check-ins das crianças; todos recebem o mesmo código de segurança
+	CheckIns []*domain.ChildCheckIn
+	// Presenças na lista de check-ins do evento, na mesma ordem de CheckIns
+	Attendances []*domain.CheckIn
+	// Crianças com alergias ou observações médicas atualizadas no cadastro
+	Members []*domain.Member
+}
+
+// KidsCheckInFilter filtra a lista de crianças do evento
+type KidsCheckInFilter struct {
+	// active (ainda na sala) ou checked_out (já retiradas)
+	Status      string
+	ClassroomID string
+}
+
+type KidsCheckInRepository interface {
+	Repository
+	Create(ctx context.Context, session *KidsCheckInSession, newCode func() (string, error)) error
+	List(ctx context.Context, communityID, eventID string, filter *KidsCheckInFilter) ([]*domain.ChildCheckIn, error)
+	FindActiveByCode(ctx context.Context, communityID, eventID, code string) ([]*domain.ChildCheckIn, error)
+	Checkout(ctx context.Context, ids []string, checkedOutAt time.Time, checkedOutBy *string, pickedUpBy string) error
+	ListClassrooms(ctx context.Context, communityID string) ([]*domain.Group, error)
+	CountActiveByClassroom(ctx context.Context, eventID string) (map[string]int64, error)
+}
+
+type kidsCheckInRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewKidsCheckInRepository(db *gorm.DB, logger *zap.Logger) KidsCheckInRepository {
+	return &kidsCheckInRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+// Create grava o check-in das crianças com um código de segurança que não está em uso
+// por outra criança presente no evento. O registro do evento fica bloqueado durante a
+// transação para que dois check-ins simultâneos não recebam o mesmo código nem
+// registrem a mesma criança duas vezes.
+func (r *kidsCheckInRepository) Create(ctx context.Context, session *KidsCheckInSession, newCode func() (string, error)) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("community_id = ? AND id = ?", session.CommunityID, session.EventID).
+			First(&domain.Event{}).Error; err != nil {
+			return err
+		}
+
+		childIDs := make([]string, 0, len(session.CheckIns))
+		for _, checkIn := range session.CheckIns {
+			childIDs = append(childIDs, checkIn.ChildID)
+		}
+		var active int64
+		if err := tx.Model(&domain.ChildCheckIn{}).
+			Where("event_id = ? AND child_id IN ? AND checked_out_at IS NULL", session.EventID, childIDs).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrChildCheckedIn
+		}
+
+		code := ""
+		for attempt := 0; attempt < maxSecurityCodeAttempts && code == ""; attempt++ {
+			candidate, err := newCode()
+			if err != nil {
+				return err
+			}
+			var used int64
+			if err := tx.Model(&domain.ChildCheckIn{}).
+				Where("event_id = ? AND security_code = ? AND checked_out_at IS NULL", session.EventID, candidate).
+				Count(&used).Error; err != nil {
+				return err
+			}
+			if used == 0 {
+				code = candidate
+			}
+		}
+		if code == "" {
+			return fmt.Errorf("nenhum código de segurança livre após %d tentativas", maxSecurityCodeAttempts)
+		}
+
+		for i, checkIn := range session.CheckIns {
+			if i < len(session.Attendances) {
+				attendance := session.Attendances[i]
+				if err := tx.Create(attendance).Error; err != nil {
+					return err
+				}
+				if err := touchLastAttendance(tx, attendance.EventID, checkIn.ChildID, attendance.CheckInAt); err != nil {
+					return err
+				}
+				checkIn.CheckInID = &attendance.ID
+			}
+			checkIn.SecurityCode = code
+			if err := tx.Omit(clause.Associations).Create(checkIn).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, member := range session.Members {
+			if err := tx.Model(member).
+				Where("community_id = ?", member.CommunityID).
+				Select("allergies", "medical_notes", "updated_at").
+				Updates(member).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *kidsCheckInRepository) List(ctx context.Context, communityID, eventID string, filter *KidsCheckInFilter) ([]*domain.ChildCheckIn, error) {
+	var checkIns []*domain.ChildCheckIn
+	query := r.GetDB().WithContext(ctx).
+		Preload("Child").
+		Preload("Classroom").
+		Where("community_id = ? AND event_id = ?", communityID, eventID)
+
+	if filter != nil {
+		switch filter.Status {
+		case "active":
+			query = query.Where("checked_out_at IS NULL")
+		case "checked_out":
+			query = query.Where("checked_out_at IS NOT NULL")
+		}
+		if filter.ClassroomID != "" {
+			query = query.Where("classroom_id = ?", filter.ClassroomID)
+		}
+	}
+
+	if err := query.Order("checked_in_at").Find(&checkIns).Error; err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// FindActiveByCode busca as crianças presentes no evento com o código de segurança informado
+func (r *kidsCheckInRepository) FindActiveByCode(ctx context.Context, communityID, eventID, code string) ([]*domain.ChildCheckIn, error) {
+	var checkIns []*domain.ChildCheckIn
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Child").
+		Preload("Classroom").
+		Where("community_id = ? AND event_id = ? AND security_code = ? AND checked_out_at IS NULL", communityID, eventID, code).
+		Order("checked_in_at").
+		Find(&checkIns).Error; err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// Checkout registra a retirada das crianças. Se alguma delas já tiver sido retirada,
+// nenhuma retirada é gravada.
+func (r *kidsCheckInRepository) Checkout(ctx context.Context, ids []string, checkedOutAt time.Time, checkedOutBy *string, pickedUpBy string) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.ChildCheckIn{}).
+			Where("id IN ? AND checked_out_at IS NULL", ids).
+			Updates(map[string]interface{}{
+				"checked_out_at": checkedOutAt,
+				"checked_out_by": checkedOutBy,
+				"picked_up_by":   pickedUpBy,
+				"updated_at":     checkedOutAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return ErrChildCheckedOut
+		}
+		return nil
+	})
+}
+
+// ListClassrooms lista as salas ativas do ministério infantil
+func (r *kidsCheckInRepository) ListClassrooms(ctx context.Context, communityID string) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND is_classroom = ? AND status = ?", communityID, true, "active").
+		Order("name").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CountActiveByClassroom conta as crianças presentes em cada sala do evento
+func (r *kidsCheckInRepository) CountActiveByClassroom(ctx context.Context, eventID string) (map[string]int64, error) {
+	var rows []struct {
+		ClassroomID string
+		Total       int64
+	}
+	if err := r.GetDB().WithContext(ctx).
+		Model(&domain.ChildCheckIn{}).
+		Select("classroom_id, COUNT(*) AS total").
+		Where("event_id = ? AND checked_out_at IS NULL AND classroom_id IS NOT NULL", eventID).
+		Group("classroom_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ClassroomID] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MemberImportRepository define as operações das importações de membros
+type MemberImportRepository interface {
+	Repository
+	Create(ctx context.Context, memberImport *domain.MemberImport) error
+	Update(ctx context.Context, memberImport *domain.MemberImport) error
+	FindByID(ctx context.Context, communityID, id string) (*domain.MemberImport, error)
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberImport, int64, error)
+	FailProcessing(ctx context.Context, staleBefore time.Time, message string) (int64, error)
+}
+
+type memberImportRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMemberImportRepository(db *gorm.DB, logger *zap.Logger) MemberImportRepository {
+	return &memberImportRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *memberImportRepository) Create(ctx context.Context, memberImport *domain.MemberImport) error {
+	return r.GetDB().WithContext(ctx).Create(memberImport).Error
+}
+
+func (r *memberImportRepository) Update(ctx context.Context, memberImport *domain.MemberImport) error {
+	return r.GetDB().WithContext(ctx).Omit("User").Save(memberImport).Error
+}
+
+func (r *memberImportRepository) FindByID(ctx context.Context, communityID, id string) (*domain.MemberImport, error) {
+	var memberImport domain.MemberImport
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&memberImport).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &memberImport, nil
+}
+
+func (r *memberImportRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberImport, int64, error) {
+	var imports []*domain.MemberImport
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.MemberImport{}).
+		Where("community_id = ?", communityID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil {
+		// A busca textual do filtro padrão não se aplica às importações
+		filter.Search = ""
+	}
+	if err := ApplyFilter(query, filter).Find(&imports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return imports, total, nil
+}
+
+// FailProcessing marca como falhas as importações em andamento sem progresso gravado
+// desde staleBefore. O progresso é gravado a cada lote de linhas, então as importações
+// paradas há mais tempo foram interrompidas, e as de outras instâncias do servidor
+// continuam em andamento.
+func (r *memberImportRepository) FailProcessing(ctx context.Context, staleBefore time.Time, message string) (int64, error) {
+	now := time.Now()
+	result := r.GetDB().WithContext(ctx).
+		Model(&domain.MemberImport{}).
+		Where("status = ? AND updated_at < ?", "processing", staleBefore).
+		Updates(map[string]interface{}{
+			"status":      "failed",
+			"error":       message,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MemberMergeRepository define as operações de unificação de membros duplicados
+type MemberMergeRepository interface {
+	Repository
+	Merge(ctx context.Context, merge *domain.MemberMerge, survivor *domain.Member) error
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberMerge, int64, error)
+}
+
+// memberReference é uma coluna que aponta para um membro. Quando dedupe é informado,
+// os registros do membro removido que repetem os valores dessas colunas em um registro
+// do membro mantido são excluídos em vez de transferidos (ex.: a presença no mesmo evento).
+type memberReference struct {
+	table     string
+	column    string
+	condition string
+	dedupe    []string
+}
+
+// memberReferences são as tabelas transferidas para o membro mantido na unificação
+var memberReferences = []memberReference{
+	{table: "attendances", column: "member_id", dedupe: []string{"event_id"}},
+	{table: "event_attendances", column: "member_id", dedupe: []string{"event_id"}},
+	{table: "check_ins", column: "member_id"},
+	{table: "child_check_ins", column: "child_id"},
+	{table: "child_check_ins", column: "guardian_id"},
+	{table: "group_members", column: "member_id", dedupe: []string{"group_id"}},
+	{table: "groups", column: "leader_id"},
+	{table: "groups", column: "co_leader_id"},
+	{table: "events", column: "responsible_id"},
+	{table: "families", column: "head_of_family"},
+	{table: "members", column: "spouse_id"},
+	{table: "members", column: "parent_id"},
+	{table: "donations", column: "member_id"},
+	{table: "recurring_donations", column: "member_id"},
+	{table: "contributions", column: "member_id"},
+	{table: "community_posts", column: "author_id"},
+	{table: "post_comments", column: "author_id"},
+	{table: "post_reactions", column: "member_id", dedupe: []string{"post_id"}},
+	{table: "prayer_requests", column: "member_id"},
+	{table: "member_achievements", column: "member_id", dedupe: []string{"badge_name"}},
+	{table: "communications", column: "recipient_id", condition: "recipient_type = 'member'"},
+	{table: "communication_recipients", column: "recipient_id", condition: "recipient_type <> 'custom'"},
+	{table: "member_merges", column: "survivor_id"},
+	{table: "member_status_histories", column: "member_id"},
+	{table: "follow_up_tasks", column: "member_id"},
+	{table: "follow_up_tasks", column: "assignee_id"},
+	{table: "pipeline_stages", column: "follow_up_assignee_id"},
+	{table: "member_profile_changes", column: "member_id"},
+	{table: "membership_events", column: "member_id"},
+	{table: "member_letters", column: "member_id"},
+	{table: "celebration_greetings", column: "member_id", dedupe: []string{"kind", "year"}},
+}
+
+type memberMergeRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMemberMergeRepository(db *gorm.DB, logger *zap.Logger) MemberMergeRepository {
+	return &memberMergeRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+// Merge transfere os registros do membro removido para o membro mantido, grava os
+// dados combinados do membro mantido, exclui o membro removido e registra a unificação,
+// tudo na mesma transação. A quantidade transferida de cada tabela fica em MovedRecords.
+func (r *memberMergeRepository) Merge(ctx context.Context, merge *domain.MemberMerge, survivor *domain.Member) error {
+	from, to := merge.MergedMemberID, merge.SurvivorID
+	merge.MovedRecords = make(map[string]int64)
+
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A pessoa pertence a uma só família: o vínculo do membro mantido prevalece
+		var survivorFamilies int64
+		if err := tx.Table("family_members").Where("member_id = ?", to).Count(&survivorFamilies).Error; err != nil {
+			return err
+		}
+		if survivorFamilies > 0 {
+			if err := tx.Exec("DELETE FROM family_members WHERE member_id = ?", from).Error; err != nil {
+				return fmt.Errorf("family_members: %w", err)
+			}
+		} else {
+			result := tx.Exec("UPDATE family_members SET member_id = ? WHERE member_id = ?", to, from)
+			if result.Error != nil {
+				return fmt.Errorf("family_members: %w", result.Error)
+			}
+			if result.RowsAffected > 0 {
+				merge.MovedRecords["family_members.member_id"] = result.RowsAffected
+			}
+		}
+
+		for _, ref := range memberReferences {
+			moved, err := moveMemberReference(tx, ref, from, to)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", ref.table, ref.column, err)
+			}
+			if moved > 0 {
+				merge.MovedRecords[ref.table+"."+ref.column] = moved
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(survivor).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("community_id = ? AND id = ?", merge.CommunityID, from).
+			Delete(&domain.Member{}).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(merge).Error
+	})
+}
+
+func moveMemberReference(tx *gorm.DB, ref memberReference, from, to string) (int64, error) {
+	condition := ""
+	if ref.condition != "" {
+		condition = " AND " + ref.condition
+	}
+
+	if len(ref.dedupe) > 0 {
+		dedupe := strings.Join(ref.dedupe, ", ")
+		if err := tx.Exec(fmt.Sprintf(
+			"DELETE FROM %[1]s WHERE %[2]s = ? AND (%[3]s) IN (SELECT %[3]s FROM %[1]s WHERE %[2]s = ?)",
+			ref.table, ref.column, dedupe), from, to).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	result := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?%s", ref.table, ref.column, ref.column, condition), to, from)
+	return result.RowsAffected, result.Error
+}
+
+func (r *memberMergeRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberMerge, int64, error) {
+	var merges []*domain.MemberMerge
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.MemberMerge{}).
+		Where("community_id = ?", communityID)
+
+	if filter != nil && filter.Search != "" {
+		query = query.Where("merged_name ILIKE ?", "%"+filter.Search+"%")
+		filter.Search = ""
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("User"), filter).Find(&merges).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return merges, total, nil
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FollowUpTaskFilter restringe a listagem das tarefas de acompanhamento
+type FollowUpTaskFilter struct {
+	Status     string
+	AssigneeID string
+	MemberID   string
+	StageID    string
+	Overdue    bool
+}
+
+// StageMemberCount é a quantidade de membros em uma etapa e quantos estão parados nela
+type StageMemberCount struct {
+	StageID string `json:"stage_id"`
+	Total   int64  `json:"total"`
+	Stuck   int64  `json:"stuck"`
+}
+
+// MemberPipelineRepository define as operações do caminho de integração de membros
+type MemberPipelineRepository interface {
+	Repository
+	CreateStage(ctx context.Context, stage *domain.PipelineStage) error
+	UpdateStage(ctx context.Context, stage *domain.PipelineStage) error
+	DeleteStage(ctx context.Context, communityID, id string) error
+	FindStageByID(ctx context.Context, communityID, id string) (*domain.PipelineStage, error)
+	ListStages(ctx context.Context, communityID string) ([]*domain.PipelineStage, error)
+	FindVisitorStage(ctx context.Context, communityID string) (*domain.PipelineStage, error)
+	ClearVisitorEntry(ctx context.Context, communityID, exceptID string) error
+	CountMembersInStage(ctx context.Context, communityID, stageID string) (int64, error)
+
+	MoveMember(ctx context.Context, member *domain.Member, history *domain.MemberStatusHistory, task *domain.FollowUpTask) error
+	CreateHistory(ctx context.Context, history *domain.MemberStatusHistory) error
+	ListHistory(ctx context.Context, communityID, memberID string) ([]*domain.MemberStatusHistory, error)
+
+	CreateTask(ctx context.Context, task *domain.FollowUpTask) error
+	UpdateTask(ctx context.Context, task *domain.FollowUpTask) error
+	FindTaskByID(ctx context.Context, communityID, id string) (*domain.FollowUpTask, error)
+	ListTasks(ctx context.Context, communityID string, taskFilter *FollowUpTaskFilter, filter *Filter) ([]*domain.FollowUpTask, int64, error)
+	CancelPendingTasks(ctx context.Context, communityID, memberID, stageID string) error
+
+	CountStageMembers(ctx context.Context, communityID string, stages []*domain.PipelineStage, now time.Time) ([]StageMemberCount, error)
+	ListStuckMembers(ctx context.Context, communityID string, stage *domain.PipelineStage, now time.Time, limit int) ([]*domain.Member, error)
+	CountTasks(ctx context.Context, communityID string, now time.Time) (pending, overdue int64, err error)
+}
+
+type memberPipelineRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMemberPipelineRepository(db *gorm.DB, logger *zap.Logger) MemberPipelineRepository {
+	return &memberPipelineRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *memberPipelineRepository) CreateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	return r.GetDB().WithContext(ctx).Create(stage).Error
+}
+
+func (r *memberPipelineRepository) UpdateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	return r.GetDB().WithContext(ctx).Save(stage).Error
+}
+
+func (r *memberPipelineRepository) DeleteStage(ctx context.Context, communityID, id string) error {
+	return r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		Delete(&domain.PipelineStage{}).Error
+}
+
+func (r *memberPipelineRepository) FindStageByID(ctx context.Context, communityID, id string) (*domain.PipelineStage, error) {
+	var stage domain.PipelineStage
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&stage).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stage, nil
+}
+
+func (r *memberPipelineRepository) ListStages(ctx context.Context, communityID string) ([]*domain.PipelineStage, error) {
+	var stages []*domain.PipelineStage
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		Order("position, created_at").
+		Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// FindVisitorStage busca a etapa de entrada dos visitantes. Sem etapa marcada, usa a
+// primeira etapa do caminho.
+func (r *memberPipelineRepository) FindVisitorStage(ctx context.Context, communityID string) (*domain.PipelineStage, error) {
+	var stage domain.PipelineStage
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		Order("is_visitor_entry DESC, position, created_at").
+		First(&stage).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// ClearVisitorEntry desmarca a entrada de visitantes das demais etapas da comunidade
+func (r *memberPipelineRepository) ClearVisitorEntry(ctx context.Context, communityID, exceptID string) error {
+	return r.GetDB().WithContext(ctx).Model(&domain.PipelineStage{}).
+		Where("community_id = ? AND id <> ? AND is_visitor_entry", communityID, exceptID).
+		Update("is_visitor_entry", false).Error
+}
+
+func (r *memberPipelineRepository) CountMembersInStage(ctx context.Context, communityID, stageID string) (int64, error) {
+	var count int64
+	err := r.GetDB().WithContext(ctx).Model(&domain.Member{}).
+		Where("community_id = ? AND pipeline_stage_id = ?", communityID, stageID).
+		Count(&count).Error
+	return count, err
+}
+
+// MoveMember grava a nova etapa, o tipo e a situação do membro, o histórico da mudança
+// e a tarefa de acompanhamento da etapa, quando houver, na mesma transação
+func (r *memberPipelineRepository) MoveMember(ctx context.Context, member *domain.Member, history *domain.MemberStatusHistory, task *domain.FollowUpTask) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Member{}).
+			Where("community_id = ? AND id = ?", member.CommunityID, member.ID).
+			Updates(map[string]interface{}{
+				"pipeline_stage_id": member.PipelineStageID,
+				"stage_entered_at":  member.StageEnteredAt,
+				"type":              member.Type,
+				"status":            member.Status,
+				"updated_at":        member.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+		if task != nil {
+			return tx.Create(task).Error
+		}
+		return nil
+	})
+}
+
+func (r *memberPipelineRepository) CreateHistory(ctx context.Context, history *domain.MemberStatusHistory) error {
+	return r.GetDB().WithContext(ctx).Create(history).Error
+}
+
+func (r *memberPipelineRepository) ListHistory(ctx context.Context, communityID, memberID string) ([]*domain.MemberStatusHistory, error) {
+	var history []*domain.MemberStatusHistory
+	if err := r.GetDB().WithContext(ctx).
+		Preload("FromStage").
+		Preload("ToStage").
+		Where("community_id = ? AND member_id = ?", communityID, memberID).
+		Order("created_at DESC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (r *memberPipelineRepository) CreateTask(ctx context.Context, task *domain.FollowUpTask) error {
+	return r.GetDB().WithContext(ctx).Create(task).Error
+}
+
+func (r *memberPipelineRepository) UpdateTask(ctx context.Context, task *domain.FollowUpTask) error {
+	return r.GetDB().WithContext(ctx).Omit("Member", "Assignee", "Stage").Save(task).Error
+}
+
+func (r *memberPipelineRepository) FindTaskByID(ctx context.Context, communityID, id string) (*domain.FollowUpTask, error) {
+	var task domain.FollowUpTask
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Member").
+		Preload("Assignee").
+		Preload("Stage").
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&task).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *memberPipelineRepository) ListTasks(ctx context.Context, communityID string, taskFilter *FollowUpTaskFilter, filter *Filter) ([]*domain.FollowUpTask, int64, error) {
+	var tasks []*domain.FollowUpTask
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.FollowUpTask{}).
+		Where("community_id = ?", communityID)
+
+	if taskFilter != nil {
+		if taskFilter.Status != "" {
+			query = query.Where("status = ?", taskFilter.Status)
+		}
+		if taskFilter.AssigneeID != "" {
+			query = query.Where("assignee_id = ?", taskFilter.AssigneeID)
+		}
+		if taskFilter.MemberID != "" {
+			query = query.Where("member_id = ?", taskFilter.MemberID)
+		}
+		if taskFilter.StageID != "" {
+			query = query.Where("stage_id = ?", taskFilter.StageID)
+		}
+		if taskFilter.Overdue {
+			query = query.Where("status = ? AND due_date < CURRENT_DATE", domain.FollowUpTaskPending)
+		}
+	}
+
+	if filter != nil && filter.Search != "" {
+		query = query.Where("title ILIKE ?", "%"+filter.Search+"%")
+		filter.Search = ""
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil && filter.OrderBy == "" {
+		filter.OrderBy = "due_date"
+	}
+	if err := ApplyFilter(query.Preload("Member").Preload("Assignee").Preload("Stage"), filter).
+		Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// CancelPendingTasks cancela as tarefas pendentes do membro criadas em outra etapa
+func (r *memberPipelineRepository) CancelPendingTasks(ctx context.Context, communityID, memberID, stageID string) error {
+	return r.GetDB().WithContext(ctx).Model(&domain.FollowUpTask{}).
+		Where("community_id = ? AND member_id = ? AND status = ? AND stage_id IS NOT NULL AND stage_id <> ?",
+			communityID, memberID, domain.FollowUpTaskPending, stageID).
+		Updates(map[string]interface{}{
+			"status":     domain.FollowUpTaskCancelled,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// CountStageMembers conta os membros de cada etapa e os que passaram do prazo da etapa
+func (r *memberPipelineRepository) CountStageMembers(ctx context.Context, communityID string, stages []*domain.PipelineStage, now time.Time) ([]StageMemberCount, error) {
+	var rows []struct {
+		StageID string
+		Total   int64
+	}
+	if err := r.GetDB().WithContext(ctx).Model(&domain.Member{}).
+		Select("pipeline_stage_id AS stage_id, COUNT(*) AS total").
+		Where("community_id = ? AND pipeline_stage_id IS NOT NULL", communityID).
+		Group("pipeline_stage_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		totals[row.StageID] = row.Total
+	}
+
+	counts := make([]StageMemberCount, 0, len(stages))
+	for _, stage := range stages {
+		count := StageMemberCount{StageID: stage.ID, Total: totals[stage.ID]}
+		if stage.MaxDays > 0 && count.Total > 0 {
+			if err := r.stuckQuery(ctx, communityID, stage, now).Count(&count.Stuck).Error; err != nil {
+				return nil, err
+			}
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// ListStuckMembers lista os membros há mais tempo na etapa além do prazo dela
+func (r *memberPipelineRepository) ListStuckMembers(ctx context.Context, communityID string, stage *domain.PipelineStage, now time.Time, limit int) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if stage.MaxDays <= 0 {
+		return members, nil
+	}
+	if err := r.stuckQuery(ctx, communityID, stage, now).
+		Order("stage_entered_at").
+		Limit(limit).
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *memberPipelineRepository) stuckQuery(ctx context.Context, communityID string, stage *domain.PipelineStage, now time.Time) *gorm.DB {
+	return r.GetDB().WithContext(ctx).Model(&domain.Member{}).
+		Where("community_id = ? AND pipeline_stage_id = ? AND stage_entered_at < ?",
+			communityID, stage.ID, now.AddDate(0, 0, -stage.MaxDays))
+}
+
+// CountTasks conta as tarefas pendentes da comunidade e quantas estão atrasadas
+func (r *memberPipelineRepository) CountTasks(ctx context.Context, communityID string, now time.Time) (pending, overdue int64, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var result struct {
+		Pending int64
+		Overdue int64
+	}
+	err = r.GetDB().WithContext(ctx).Model(&domain.FollowUpTask{}).
+		Select("COUNT(*) AS pending, COUNT(*) FILTER (WHERE due_date < ?) AS overdue", today).
+		Where("community_id = ? AND status = ?", communityID, domain.FollowUpTaskPending).
+		Scan(&result).Error
+	return result.Pending, result.Overdue, err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MemberProfileRepository define as operações das alterações de cadastro feitas pelos
+// membros no portal
+type MemberProfileRepository interface {
+	Repository
+	FindSettings(ctx context.Context, communityID string) (*domain.MemberProfileSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.MemberProfileSettings) error
+	CreateChange(ctx context.Context, change *domain.MemberProfileChange) error
+	UpdateChange(ctx context.Context, change *domain.MemberProfileChange) error
+	FindChangeByID(ctx context.Context, communityID, id string) (*domain.MemberProfileChange, error)
+	FindPendingProfileChange(ctx context.Context, communityID, memberID string) (*domain.MemberProfileChange, error)
+	ListChanges(ctx context.Context, communityID, memberID, status string, filter *Filter) ([]*domain.MemberProfileChange, int64, error)
+}
+
+type memberProfileRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMemberProfileRepository(db *gorm.DB, logger *zap.Logger) MemberProfileRepository {
+	return &memberProfileRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *memberProfileRepository) FindSettings(ctx context.Context, communityID string) (*domain.MemberProfileSettings, error) {
+	var settings domain.MemberProfileSettings
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *memberProfileRepository) SaveSettings(ctx context.Context, settings *domain.MemberProfileSettings) error {
+	return r.GetDB().WithContext(ctx).Save(settings).Error
+}
+
+func (r *memberProfileRepository) CreateChange(ctx context.Context, change *domain.MemberProfileChange) error {
+	return r.GetDB().WithContext(ctx).Omit("Member").Create(change).Error
+}
+
+func (r *memberProfileRepository) UpdateChange(ctx context.Context, change *domain.MemberProfileChange) error {
+	return r.GetDB().WithContext(ctx).Omit("Member").Save(change).Error
+}
+
+func (r *memberProfileRepository) FindChangeByID(ctx context.Context, communityID, id string) (*domain.MemberProfileChange, error) {
+	var change domain.MemberProfileChange
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Member").
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&change).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+// FindPendingProfileChange busca a alteração de dados pessoais pendente do membro
+func (r *memberProfileRepository) FindPendingProfileChange(ctx context.Context, communityID, memberID string) (*domain.MemberProfileChange, error) {
+	var change domain.MemberProfileChange
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND member_id = ? AND kind = ? AND status = ?",
+			communityID, memberID, domain.ProfileChangeKindProfile, domain.ProfileChangePending).
+		First(&change).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListChanges lista as alterações da comunidade, opcionalmente de um membro e de uma situação
+func (r *memberProfileRepository) ListChanges(ctx context.Context, communityID, memberID, status string, filter *Filter) ([]*domain.MemberProfileChange, int64, error) {
+	var changes []*domain.MemberProfileChange
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.MemberProfileChange{}).
+		Where("community_id = ?", communityID)
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if filter != nil && filter.Search != "" {
+		query = query.Where("member_id IN (SELECT id FROM members WHERE community_id = ? AND name ILIKE ?)",
+			communityID, "%"+filter.Search+"%")
+		filter.Search = ""
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("Member"), filter).Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return changes, total, nil
+}
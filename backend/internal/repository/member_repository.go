@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/comunidade/backend/internal/domain"
 	"go.uber.org/zap"
@@ -15,6 +16,7 @@ type MemberRepository interface {
 	Update(ctx context.Context, member *domain.Member) error
 	Delete(ctx context.Context, communityID, memberID string) error
 	FindByID(ctx context.Context, communityID, memberID string) (*domain.Member, error)
+	FindByIDs(ctx context.Context, communityID string, memberIDs []string) ([]*domain.Member, error)
 	FindByEmail(ctx context.Context, communityID, email string) (*domain.Member, error)
 	FindByEmailOrPhone(ctx context.Context, communityID, search string) (*domain.Member, error)
 	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.Member, int64, error)
@@ -22,6 +24,9 @@ type MemberRepository interface {
 	FindByGroupID(ctx context.Context, communityID, groupID string) ([]*domain.Member, error)
 	FindByCPF(ctx context.Context, communityID string, cpf string) (*domain.Member, error)
 	FindByEmailOrCPF(ctx context.Context, communityID, email, cpf string) (*domain.Member, error)
+	ListByCommunity(ctx context.Context, communityID string) ([]*domain.Member, error)
+	Search(ctx context.Context, communityID string, search *domain.MemberSearch, filter *Filter) ([]*domain.Member, int64, error)
+	SearchAll(ctx context.Context, communityID string, search *domain.MemberSearch) ([]*domain.Member, error)
 }
 
 type memberRepository struct {
@@ -72,6 +77,20 @@ func (r *memberRepository) FindByID(ctx context.Context, communityID, memberID s
 	return &member, nil
 }
 
+// FindByIDs busca os membros da comunidade com os IDs informados
+func (r *memberRepository) FindByIDs(ctx context.Context, communityID string, memberIDs []string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if len(memberIDs) == 0 {
+		return members, nil
+	}
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id IN ?", communityID, memberIDs).
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
 func (r *memberRepository) FindByEmail(ctx context.Context, communityID, email string) (*domain.Member, error) {
 	var member domain.Member
 	if err := r.GetDB().WithContext(ctx).
@@ -176,11 +195,27 @@ func (r *memberRepository) FindByCPF(ctx context.Context, communityID string, cp
 	return &member, nil
 }
 
-// FindByEmailOrCPF busca um membro pelo email ou CPF
+// FindByEmailOrCPF busca um membro pelo email ou CPF. Critérios vazios são ignorados,
+// para não coincidir com membros sem email ou sem CPF cadastrado.
 func (r *memberRepository) FindByEmailOrCPF(ctx context.Context, communityID, email, cpf string) (*domain.Member, error) {
+	var conditions []string
+	var args []interface{}
+	if email != "" {
+		conditions = append(conditions, "email = ?")
+		args = append(args, email)
+	}
+	if cpf != "" {
+		conditions = append(conditions, "cpf = ?")
+		args = append(args, cpf)
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
 	var member domain.Member
 	if err := r.GetDB().WithContext(ctx).
-		Where("community_id = ? AND (email = ? OR cpf = ?)", communityID, email, cpf).
+		Where("community_id = ?", communityID).
+		Where(strings.Join(conditions, " OR "), args...).
 		First(&member).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -189,3 +224,15 @@ func (r *memberRepository) FindByEmailOrCPF(ctx context.Context, communityID, em
 	}
 	return &member, nil
 }
+
+// ListByCommunity lista todos os membros da comunidade, sem paginação
+func (r *memberRepository) ListByCommunity(ctx context.Context, communityID string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID).
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// memberSearchOrder são as colunas aceitas na ordenação da busca avançada
+var memberSearchOrder = map[string]bool{
+	"name":                 true,
+	"created_at":           true,
+	"join_date":            true,
+	"birth_date":           true,
+	"last_attendance_at":   true,
+	"last_contribution_at": true,
+}
+
+// minBirthDate desconsidera as datas vazias gravadas como 0001-01-01
+const minBirthDate = "1900-01-01"
+
+// Search busca os membros da comunidade pelos critérios da busca avançada
+func (r *memberRepository) Search(ctx context.Context, communityID string, search *domain.MemberSearch, filter *Filter) ([]*domain.Member, int64, error) {
+	var members []*domain.Member
+	var total int64
+
+	query := applyMemberSearch(r.GetDB().WithContext(ctx).Model(&domain.Member{}).
+		Where("community_id = ?", communityID), search, time.Now())
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil {
+		filter.Validate()
+		if memberSearchOrder[filter.OrderBy] {
+			direction := "ASC"
+			if strings.EqualFold(filter.OrderDir, "desc") {
+				direction = "DESC"
+			}
+			query = query.Order(filter.OrderBy + " " + direction + " NULLS LAST")
+		} else {
+			query = query.Order("name")
+		}
+		query = query.Offset((filter.Page - 1) * filter.PerPage).Limit(filter.PerPage)
+	}
+
+	if err := query.Find(&members).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return members, total, nil
+}
+
+// SearchAll busca todos os membros que atendem aos critérios, sem paginação
+func (r *memberRepository) SearchAll(ctx context.Context, communityID string, search *domain.MemberSearch) ([]*domain.Member, error) {
+	var members []*domain.Member
+	if err := applyMemberSearch(r.GetDB().WithContext(ctx).
+		Where("community_id = ?", communityID), search, time.Now()).
+		Order("name").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// applyMemberSearch aplica os critérios da busca avançada à consulta de membros
+func applyMemberSearch(query *gorm.DB, search *domain.MemberSearch, now time.Time) *gorm.DB {
+	if search == nil {
+		return query
+	}
+
+	if search.Search != "" {
+		term := "%" + search.Search + "%"
+		query = query.Where("(name ILIKE ? OR email ILIKE ? OR phone ILIKE ? OR cpf ILIKE ?)", term, term, term, term)
+	}
+
+	if len(search.Statuses) > 0 {
+		query = query.Where("status IN ?", search.Statuses)
+	}
+	if len(search.Types) > 0 {
+		query = query.Where("type IN ?", search.Types)
+	}
+	if len(search.Roles) > 0 {
+		query = query.Where("role IN ?", search.Roles)
+	}
+	if len(search.Genders) > 0 {
+		query = query.Where("gender IN ?", search.Genders)
+	}
+
+	// Idade completa na data da busca: quem tem pelo menos N anos nasceu até hoje há N anos
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if search.MinAge != nil || search.MaxAge != nil {
+		query = query.Where("birth_date > ?", minBirthDate)
+	}
+	if search.MinAge != nil {
+		query = query.Where("birth_date <= ?", today.AddDate(-*search.MinAge, 0, 0))
+	}
+	if search.MaxAge != nil {
+		query = query.Where("birth_date > ?", today.AddDate(-*search.MaxAge-1, 0, 0))
+	}
+
+	if search.Baptized != nil {
+		if *search.Baptized {
+			query = query.Where("baptism_date IS NOT NULL AND baptism_date > ?", minBirthDate)
+		} else {
+			query = query.Where("(baptism_date IS NULL OR baptism_date <= ?)", minBirthDate)
+		}
+	}
+
+	if len(search.Ministries) > 0 {
+		query = query.Where("LOWER(ministry) IN ?", lowerAll(search.Ministries))
+	}
+	if search.IsVolunteer != nil {
+		query = query.Where("is_volunteer = ?", *search.IsVolunteer)
+	}
+	if len(search.Skills) > 0 {
+		query = query.Where("skills && ?", pq.Array(search.Skills))
+	}
+	if len(search.Interests) > 0 {
+		query = query.Where("interests && ?", pq.Array(search.Interests))
+	}
+	if len(search.Cities) > 0 {
+		query = query.Where("LOWER(city) IN ?", lowerAll(search.Cities))
+	}
+	if len(search.Neighborhoods) > 0 {
+		query = query.Where("LOWER(neighborhood) IN ?", lowerAll(search.Neighborhoods))
+	}
+
+	if search.LastAttendanceFrom != nil {
+		query = query.Where("last_attendance_at >= ?", *search.LastAttendanceFrom)
+	}
+	if search.LastAttendanceTo != nil {
+		query = query.Where("last_attendance_at < ?", search.LastAttendanceTo.AddDate(0, 0, 1))
+	}
+	if search.AttendedWithinDays != nil {
+		query = query.Where("last_attendance_at >= ?", today.AddDate(0, 0, -*search.AttendedWithinDays))
+	}
+	if search.AbsentForDays != nil {
+		query = query.Where("(last_attendance_at IS NULL OR last_attendance_at < ?)", today.AddDate(0, 0, -*search.AbsentForDays))
+	}
+
+	if search.LastContributionFrom != nil {
+		query = query.Where("last_contribution_at >= ?", *search.LastContributionFrom)
+	}
+	if search.LastContributionTo != nil {
+		query = query.Where("last_contribution_at < ?", search.LastContributionTo.AddDate(0, 0, 1))
+	}
+	if search.ContributedWithinDays != nil {
+		query = query.Where("last_contribution_at >= ?", today.AddDate(0, 0, -*search.ContributedWithinDays))
+	}
+
+	if len(search.GroupIDs) > 0 {
+		query = query.Where("id IN (SELECT member_id FROM group_members WHERE group_id IN ?)", search.GroupIDs)
+	}
+	if search.WithoutGroup {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM group_members WHERE group_members.member_id = members.id)")
+	}
+
+	return query
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(strings.TrimSpace(value))
+	}
+	return lowered
+}
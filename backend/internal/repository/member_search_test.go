@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+func TestApplyMemberSearch(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	timePtr := func(v time.Time) *time.Time { return &v }
+
+	tests := []struct {
+		name     string
+		search   *domain.MemberSearch
+		wantSQL  []string
+		wantVars []interface{}
+	}{
+		{
+			name:   "sem critérios",
+			search: nil,
+		},
+		{
+			name:     "texto busca nome, email, telefone e CPF",
+			search:   &domain.MemberSearch{Search: "ana"},
+			wantSQL:  []string{"(name ILIKE $1 OR email ILIKE $2 OR phone ILIKE $3 OR cpf ILIKE $4)"},
+			wantVars: []interface{}{"%ana%", "%ana%", "%ana%", "%ana%"},
+		},
+		{
+			name:     "listas de situação e tipo",
+			search:   &domain.MemberSearch{Statuses: []string{"active"}, Types: []string{"regular", "visitor"}},
+			wantSQL:  []string{"status IN ($1)", "type IN ($2,$3)"},
+			wantVars: []interface{}{"active", "regular", "visitor"},
+		},
+		{
+			name:    "faixa de idade completa na data da busca",
+			search:  &domain.MemberSearch{MinAge: intPtr(18), MaxAge: intPtr(30)},
+			wantSQL: []string{"birth_date > $1", "birth_date <= $2", "birth_date > $3"},
+			// Tem ao menos 18 anos quem nasceu até 15/03/2008 e no máximo 30 quem nasceu depois de 15/03/1995
+			wantVars: []interface{}{minBirthDate, date(2008, 3, 15), date(1995, 3, 15)},
+		},
+		{
+			name:     "não batizados incluem as datas vazias",
+			search:   &domain.MemberSearch{Baptized: boolPtr(false)},
+			wantSQL:  []string{"(baptism_date IS NULL OR baptism_date <= $1)"},
+			wantVars: []interface{}{minBirthDate},
+		},
+		{
+			name:     "ministérios e cidades sem diferenciar maiúsculas",
+			search:   &domain.MemberSearch{Ministries: []string{" Louvor "}, Cities: []string{"São Paulo"}},
+			wantSQL:  []string{"LOWER(ministry) IN ($1)", "LOWER(city) IN ($2)"},
+			wantVars: []interface{}{"louvor", "são paulo"},
+		},
+		{
+			name:     "habilidades em comum",
+			search:   &domain.MemberSearch{Skills: []string{"música"}},
+			wantSQL:  []string{"skills && $1"},
+			wantVars: []interface{}{pq.Array([]string{"música"})},
+		},
+		{
+			name: "período da última presença inclui o dia final",
+			search: &domain.MemberSearch{
+				LastAttendanceFrom: timePtr(date(2026, 1, 1)),
+				LastAttendanceTo:   timePtr(date(2026, 1, 31)),
+			},
+			wantSQL:  []string{"last_attendance_at >= $1", "last_attendance_at < $2"},
+			wantVars: []interface{}{date(2026, 1, 1), date(2026, 2, 1)},
+		},
+		{
+			name:     "ausentes incluem quem nunca compareceu",
+			search:   &domain.MemberSearch{AbsentForDays: intPtr(30)},
+			wantSQL:  []string{"(last_attendance_at IS NULL OR last_attendance_at < $1)"},
+			wantVars: []interface{}{date(2026, 2, 13)},
+		},
+		{
+			name:     "contribuíram nos últimos dias",
+			search:   &domain.MemberSearch{ContributedWithinDays: intPtr(90)},
+			wantSQL:  []string{"last_contribution_at >= $1"},
+			wantVars: []interface{}{date(2025, 12, 15)},
+		},
+		{
+			name:     "grupos e sem grupo",
+			search:   &domain.MemberSearch{GroupIDs: []string{"g1"}, WithoutGroup: true},
+			wantSQL:  []string{"id IN (SELECT member_id FROM group_members WHERE group_id IN ($1))", "NOT EXISTS (SELECT 1 FROM group_members WHERE group_members.member_id = members.id)"},
+			wantVars: []interface{}{"g1"},
+		},
+	}
+
+	repos, _ := newTestRepositories(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var members []*domain.Member
+			statement := applyMemberSearch(repos.db.Session(&gorm.Session{DryRun: true}).Model(&domain.Member{}), tt.search, now).
+				Find(&members).Statement
+
+			sql := statement.SQL.String()
+			if len(tt.wantSQL) == 0 && strings.Contains(sql, "WHERE") {
+				t.Errorf("SQL = %q; esperado sem filtros", sql)
+			}
+			for _, want := range tt.wantSQL {
+				if !strings.Contains(sql, want) {
+					t.Errorf("SQL = %q; esperado conter %q", sql, want)
+				}
+			}
+			vars := statement.Vars
+			if len(tt.wantVars) == 0 {
+				vars = nil
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("parâmetros = %#v; esperado %#v", statement.Vars, tt.wantVars)
+			}
+		})
+	}
+}
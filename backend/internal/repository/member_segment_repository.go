@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MemberSegmentRepository define as operações dos segmentos de membros salvos
+type MemberSegmentRepository interface {
+	Repository
+	Create(ctx context.Context, segment *domain.MemberSegment) error
+	Update(ctx context.Context, segment *domain.MemberSegment) error
+	Delete(ctx context.Context, communityID, id string) error
+	FindByID(ctx context.Context, communityID, id string) (*domain.MemberSegment, error)
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberSegment, int64, error)
+	HasPendingCommunications(ctx context.Context, communityID, id string) (bool, error)
+}
+
+type memberSegmentRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMemberSegmentRepository(db *gorm.DB, logger *zap.Logger) MemberSegmentRepository {
+	return &memberSegmentRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *memberSegmentRepository) Create(ctx context.Context, segment *domain.MemberSegment) error {
+	return r.GetDB().WithContext(ctx).Create(segment).Error
+}
+
+func (r *memberSegmentRepository) Update(ctx context.Context, segment *domain.MemberSegment) error {
+	return r.GetDB().WithContext(ctx).Save(segment).Error
+}
+
+func (r *memberSegmentRepository) Delete(ctx context.Context, communityID, id string) error {
+	return r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		Delete(&domain.MemberSegment{}).Error
+}
+
+func (r *memberSegmentRepository) FindByID(ctx context.Context, communityID, id string) (*domain.MemberSegment, error) {
+	var segment domain.MemberSegment
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND id = ?", communityID, id).
+		First(&segment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &segment, nil
+}
+
+func (r *memberSegmentRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.MemberSegment, int64, error) {
+	var segments []*domain.MemberSegment
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.MemberSegment{}).
+		Where("community_id = ?", communityID)
+
+	if filter != nil && filter.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Search+"%")
+		filter.Search = ""
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter != nil && filter.OrderBy == "" {
+		filter.OrderBy = "name"
+	}
+	if err := ApplyFilter(query, filter).Find(&segments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return segments, total, nil
+}
+
+// HasPendingCommunications indica se há comunicações ainda não enviadas para o segmento
+func (r *memberSegmentRepository) HasPendingCommunications(ctx context.Context, communityID, id string) (bool, error) {
+	var count int64
+	err := r.GetDB().WithContext(ctx).Model(&domain.Communication{}).
+		Where("community_id = ? AND recipient_type = ? AND recipient_id = ? AND status = ?",
+			communityID, domain.RecipientTypeSegment, id, domain.CommunicationStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
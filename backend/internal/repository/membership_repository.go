@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MembershipRepository define as operações do histórico de membresia e das cartas
+// emitidas para os membros
+type MembershipRepository interface {
+	Repository
+	SaveEvent(ctx context.Context, event *domain.MembershipEvent, member *domain.Member, history *domain.MemberStatusHistory) error
+	FindEventByID(ctx context.Context, communityID, memberID, id string) (*domain.MembershipEvent, error)
+	ListEvents(ctx context.Context, communityID, memberID string, includeVoided bool) ([]*domain.MembershipEvent, error)
+	FindTemplate(ctx context.Context, communityID, letterType string) (*domain.LetterTemplate, error)
+	SaveTemplate(ctx context.Context, template *domain.LetterTemplate) error
+	CreateLetter(ctx context.Context, letter *domain.MemberLetter, event *domain.MembershipEvent, member *domain.Member, history *domain.MemberStatusHistory) error
+	FindLetterByID(ctx context.Context, communityID, memberID, id string) (*domain.MemberLetter, error)
+	ListLetters(ctx context.Context, communityID, memberID string) ([]*domain.MemberLetter, error)
+}
+
+type membershipRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewMembershipRepository(db *gorm.DB, logger *zap.Logger) MembershipRepository {
+	return &membershipRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+// SaveEvent grava o evento (novo ou anulado) junto com os dados de membresia do membro
+// recalculados e, quando houver mudança de tipo ou situação, o registro no histórico
+func (r *membershipRepository) SaveEvent(ctx context.Context, event *domain.MembershipEvent, member *domain.Member, history *domain.MemberStatusHistory) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveMembershipEvent(tx, event, member, history)
+	})
+}
+
+func saveMembershipEvent(tx *gorm.DB, event *domain.MembershipEvent, member *domain.Member, history *domain.MemberStatusHistory) error {
+	if err := tx.Save(event).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&domain.Member{}).
+		Where("community_id = ? AND id = ?", member.CommunityID, member.ID).
+		Updates(map[string]interface{}{
+			"membership_date":  member.MembershipDate,
+			"baptism_date":     member.BaptismDate,
+			"baptism_location": member.BaptismLocation,
+			"transferred_from": member.TransferredFrom,
+			"transferred_to":   member.TransferredTo,
+			"transfer_date":    member.TransferDate,
+			"type":             member.Type,
+			"status":           member.Status,
+			"updated_at":       member.UpdatedAt,
+		}).Error; err != nil {
+		return err
+	}
+	if history != nil {
+		return tx.Create(history).Error
+	}
+	return nil
+}
+
+func (r *membershipRepository) FindEventByID(ctx context.Context, communityID, memberID, id string) (*domain.MembershipEvent, error) {
+	var event domain.MembershipEvent
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND member_id = ? AND id = ?", communityID, memberID, id).
+		First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListEvents lista o histórico do membro em ordem cronológica
+func (r *membershipRepository) ListEvents(ctx context.Context, communityID, memberID string, includeVoided bool) ([]*domain.MembershipEvent, error) {
+	var events []*domain.MembershipEvent
+	query := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND member_id = ?", communityID, memberID)
+	if !includeVoided {
+		query = query.Where("voided_at IS NULL")
+	}
+	if err := query.Order("date, created_at").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *membershipRepository) FindTemplate(ctx context.Context, communityID, letterType string) (*domain.LetterTemplate, error) {
+	var template domain.LetterTemplate
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND type = ?", communityID, letterType).
+		First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *membershipRepository) SaveTemplate(ctx context.Context, template *domain.LetterTemplate) error {
+	return r.GetDB().WithContext(ctx).Save(template).Error
+}
+
+// CreateLetter numera e grava a carta. A numeração é sequencial por comunidade e ano;
+// o registro da comunidade fica bloqueado durante a transação para que duas cartas
+// emitidas ao mesmo tempo não recebam o mesmo número. Quando a carta registra uma
+// transferência, o evento e os dados do membro são gravados na mesma transação.
+func (r *membershipRepository) CreateLetter(ctx context.Context, letter *domain.MemberLetter, event *domain.MembershipEvent, member *domain.Member, history *domain.MemberStatusHistory) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", letter.CommunityID).
+			First(&domain.Community{}).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&domain.MemberLetter{}).
+			Where("community_id = ? AND year = ?", letter.CommunityID, letter.Year).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		letter.Number = last + 1
+
+		if event != nil {
+			if err := saveMembershipEvent(tx, event, member, history); err != nil {
+				return err
+			}
+			letter.EventID = &event.ID
+		}
+		return tx.Create(letter).Error
+	})
+}
+
+func (r *membershipRepository) FindLetterByID(ctx context.Context, communityID, memberID, id string) (*domain.MemberLetter, error) {
+	var letter domain.MemberLetter
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND member_id = ? AND id = ?", communityID, memberID, id).
+		First(&letter).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &letter, nil
+}
+
+func (r *membershipRepository) ListLetters(ctx context.Context, communityID, memberID string) ([]*domain.MemberLetter, error) {
+	var letters []*domain.MemberLetter
+	if err := r.GetDB().WithContext(ctx).
+		Where("community_id = ? AND member_id = ?", communityID, memberID).
+		Order("issued_at DESC").
+		Find(&letters).Error; err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
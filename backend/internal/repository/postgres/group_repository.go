@@ -54,6 +54,7 @@ func (r *groupRepository) Update(ctx context.Context, group *domain.Group) error
 			"allow_self_join":        group.AllowSelfJoin,
 			"notify_on_join_request": group.NotifyOnJoinRequest,
 			"notify_on_new_member":   group.NotifyOnNewMember,
+			"is_classroom":           group.IsClassroom,
 			"member_count":           group.MemberCount,
 			"attendance_count":       group.AttendanceCount,
 			"average_attendance":     group.AverageAttendance,
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecurringExpenseRepository define as operações do repositório de despesas recorrentes
+type RecurringExpenseRepository interface {
+	Repository
+	Create(ctx context.Context, recurring *domain.RecurringExpense) error
+	Update(ctx context.Context, recurring *domain.RecurringExpense) error
+	Delete(ctx context.Context, communityID, recurringID string, from time.Time) error
+	FindByID(ctx context.Context, communityID, recurringID string) (*domain.RecurringExpense, error)
+	List(ctx context.Context, communityID string, filter *Filter) ([]*domain.RecurringExpense, int64, error)
+	ListActive(ctx context.Context) ([]*domain.RecurringExpense, error)
+	Materialize(ctx context.Context, recurring *domain.RecurringExpense, expenses []*domain.Expense) (int, error)
+	UpdatePendingExpenses(ctx context.Context, recurring *domain.RecurringExpense, from time.Time) (int64, error)
+	DeletePendingExpenses(ctx context.Context, recurringID string, after time.Time) (int64, error)
+}
+
+type recurringExpenseRepository struct {
+	BaseRepository
+	logger *zap.Logger
+}
+
+func NewRecurringExpenseRepository(db *gorm.DB, logger *zap.Logger) RecurringExpenseRepository {
+	return &recurringExpenseRepository{
+		BaseRepository: NewBaseRepository(db, logger),
+		logger:         logger,
+	}
+}
+
+func (r *recurringExpenseRepository) Create(ctx context.Context, recurring *domain.RecurringExpense) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Create(recurring).Error
+}
+
+func (r *recurringExpenseRepository) Update(ctx context.Context, recurring *domain.RecurringExpense) error {
+	return r.GetDB().WithContext(ctx).Omit(clause.Associations).Save(recurring).Error
+}
+
+// Delete exclui o modelo e as despesas pendentes geradas a partir da data informada.
+// As despesas já pagas ou vencidas são mantidas e apenas desvinculadas do modelo.
+func (r *recurringExpenseRepository) Delete(ctx context.Context, communityID, recurringID string, from time.Time) error {
+	return r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_expense_id = ? AND status = ? AND due_date >= ?", recurringID, "pending", from).
+			Delete(&domain.Expense{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Expense{}).
+			Where("recurring_expense_id = ?", recurringID).
+			Update("recurring_expense_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("community_id = ? AND id = ?", communityID, recurringID).
+			Delete(&domain.RecurringExpense{}).Error
+	})
+}
+
+func (r *recurringExpenseRepository) FindByID(ctx context.Context, communityID, recurringID string) (*domain.RecurringExpense, error) {
+	var recurring domain.RecurringExpense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Preload("Group").
+		Where("community_id = ? AND id = ?", communityID, recurringID).
+		First(&recurring).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *recurringExpenseRepository) List(ctx context.Context, communityID string, filter *Filter) ([]*domain.RecurringExpense, int64, error) {
+	var recurring []*domain.RecurringExpense
+	var total int64
+
+	query := r.GetDB().WithContext(ctx).Model(&domain.RecurringExpense{}).
+		Where("community_id = ?", communityID).
+		Session(&gorm.Session{})
+
+	countQuery := query
+	if filter != nil {
+		for _, cond := range filter.conditions {
+			countQuery = countQuery.Where(cond.query, cond.args...)
+		}
+		if filter.Search != "" {
+			countQuery = countQuery.Where("description ILIKE ?", "%"+filter.Search+"%")
+			query = query.Where("description ILIKE ?", "%"+filter.Search+"%")
+			// A busca do filtro padrão inclui a coluna name, inexistente neste modelo
+			filter.Search = ""
+		}
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := ApplyFilter(query.Preload("Category").Preload("Supplier").Preload("Group"), filter).
+		Find(&recurring).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return recurring, total, nil
+}
+
+// ListActive lista os modelos ativos de todas as comunidades, com a comunidade carregada
+func (r *recurringExpenseRepository) ListActive(ctx context.Context) ([]*domain.RecurringExpense, error) {
+	var recurring []*domain.RecurringExpense
+	if err := r.GetDB().WithContext(ctx).
+		Preload("Community").
+		Where("active = ?", true).
+		Order("community_id, created_at").
+		Find(&recurring).Error; err != nil {
+		r.logger.Error("erro ao listar despesas recorrentes ativas", zap.Error(err))
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// Materialize registra as despesas geradas e o progresso do modelo na mesma
+// transação. Ocorrências já existentes para o vencimento são ignoradas; o retorno
+// é a quantidade de despesas efetivamente criadas.
+func (r *recurringExpenseRepository) Materialize(ctx context.Context, recurring *domain.RecurringExpense, expenses []*domain.Expense) (int, error) {
+	created := 0
+	err := r.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, expense := range expenses {
+			result := tx.Omit(clause.Associations).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(expense)
+			if result.Error != nil {
+				return result.Error
+			}
+			created += int(result.RowsAffected)
+		}
+		return tx.Model(&domain.RecurringExpense{}).
+			Where("id = ?", recurring.ID).
+			Updates(map[string]interface{}{
+				"generated_count": recurring.GeneratedCount,
+				"last_due_date":   recurring.LastDueDate,
+				"updated_at":      time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// UpdatePendingExpenses aplica os dados do modelo às despesas pendentes geradas com
+// vencimento a partir da data informada
+func (r *recurringExpenseRepository) UpdatePendingExpenses(ctx context.Context, recurring *domain.RecurringExpense, from time.Time) (int64, error) {
+	result := r.GetDB().WithContext(ctx).Model(&domain.Expense{}).
+		Where("recurring_expense_id = ? AND status = ? AND due_date >= ?", recurring.ID, "pending", from).
+		Updates(map[string]interface{}{
+			"category_id":  recurring.CategoryID,
+			"supplier_id":  recurring.SupplierID,
+			"group_id":     recurring.GroupID,
+			"amount":       recurring.Amount,
+			"description":  recurring.Description,
+			"payment_type": recurring.PaymentType,
+			"updated_at":   time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeletePendingExpenses exclui as despesas pendentes geradas com vencimento posterior à data
+func (r *recurringExpenseRepository) DeletePendingExpenses(ctx context.Context, recurringID string, after time.Time) (int64, error) {
+	result := r.GetDB().WithContext(ctx).
+		Where("recurring_expense_id = ? AND status = ? AND due_date > ?", recurringID, "pending", after).
+		Delete(&domain.Expense{})
+	return result.RowsAffected, result.Error
+}
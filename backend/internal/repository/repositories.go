@@ -1,54 +1,134 @@
 package repository
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type Repositories struct {
-	User              UserRepository
-	Community         CommunityRepository
-	Member            MemberRepository
-	Group             GroupRepository
-	Event             EventRepository
-	Family            FamilyRepository
-	Communication     CommunicationRepository
-	CheckIn           CheckInRepository
-	FinancialCategory FinancialCategoryRepository
-	Supplier          SupplierRepository
-	Expense           ExpenseRepository
-	Revenue           RevenueRepository
-	FinancialReport   FinancialReportRepository
-	Donation          DonationRepository
-	Campaign          CampaignRepository
-	RecurringDonation RecurringDonationRepository
-	AsaasConfig       AsaasConfigRepository
-	AsaasAccount      AsaasAccountRepository
-	Engagement        EngagementRepository
-	//Contribution      ContributionRepository
+	User                UserRepository
+	Community           CommunityRepository
+	Member              MemberRepository
+	MemberImport        MemberImportRepository
+	MemberMerge         MemberMergeRepository
+	MemberSegment       MemberSegmentRepository
+	MemberPipeline      MemberPipelineRepository
+	MemberProfile       MemberProfileRepository
+	Membership          MembershipRepository
+	Celebration         CelebrationRepository
+	Group               GroupRepository
+	Event               EventRepository
+	Family              FamilyRepository
+	Household           HouseholdRepository
+	Communication       CommunicationRepository
+	CheckIn             CheckInRepository
+	KidsCheckIn         KidsCheckInRepository
+	FinancialCategory   FinancialCategoryRepository
+	Supplier            SupplierRepository
+	Expense             ExpenseRepository
+	RecurringExpense    RecurringExpenseRepository
+	ExpenseApproval     ExpenseApprovalRepository
+	Revenue             RevenueRepository
+	FinancialReport     FinancialReportRepository
+	DonationPostingRule DonationPostingRuleRepository
+	BankReconciliation  BankReconciliationRepository
+	Budget              BudgetRepository
+	Donation            DonationRepository
+	Campaign            CampaignRepository
+	RecurringDonation   RecurringDonationRepository
+	AsaasConfig         AsaasConfigRepository
+	AsaasAccount        AsaasAccountRepository
+	PaymentGateway      PaymentGatewaySettingsRepository
+	Engagement          EngagementRepository
+	Contribution        ContributionRepository
+	GivingStatement     GivingStatementRepository
+
+	db     *gorm.DB
+	logger *zap.Logger
 }
 
 func NewRepositories(db *gorm.DB, logger *zap.Logger) *Repositories {
 	return &Repositories{
-		User:              NewUserRepository(db, logger),
-		Community:         NewCommunityRepository(db, logger),
-		Member:            NewMemberRepository(db, logger),
-		Group:             NewGroupRepository(db, logger),
-		Event:             NewEventRepository(db, logger),
-		Family:            NewFamilyRepository(db, logger),
-		Communication:     NewCommunicationRepository(db, logger),
-		CheckIn:           NewCheckInRepository(db, logger),
-		FinancialCategory: NewFinancialCategoryRepository(db, logger),
-		Supplier:          NewSupplierRepository(db, logger),
-		Expense:           NewExpenseRepository(db, logger),
-		Revenue:           NewRevenueRepository(db, logger),
-		FinancialReport:   NewFinancialReportRepository(db, logger),
-		Donation:          NewDonationRepository(db, logger),
-		Campaign:          NewCampaignRepository(db, logger),
-		RecurringDonation: NewRecurringDonationRepository(db, logger),
-		AsaasConfig:       NewAsaasConfigRepository(db, logger),
-		AsaasAccount:      NewAsaasAccountRepository(db, logger),
-		Engagement:        NewEngagementRepository(db, logger),
-		//Contribution:      NewContributionRepository(db, logger),
+		User:                NewUserRepository(db, logger),
+		Community:           NewCommunityRepository(db, logger),
+		Member:              NewMemberRepository(db, logger),
+		MemberImport:        NewMemberImportRepository(db, logger),
+		MemberMerge:         NewMemberMergeRepository(db, logger),
+		MemberSegment:       NewMemberSegmentRepository(db, logger),
+		MemberPipeline:      NewMemberPipelineRepository(db, logger),
+		MemberProfile:       NewMemberProfileRepository(db, logger),
+		Membership:          NewMembershipRepository(db, logger),
+		Celebration:         NewCelebrationRepository(db, logger),
+		Group:               NewGroupRepository(db, logger),
+		Event:               NewEventRepository(db, logger),
+		Family:              NewFamilyRepository(db, logger),
+		Household:           NewHouseholdRepository(db, logger),
+		Communication:       NewCommunicationRepository(db, logger),
+		CheckIn:             NewCheckInRepository(db, logger),
+		KidsCheckIn:         NewKidsCheckInRepository(db, logger),
+		FinancialCategory:   NewFinancialCategoryRepository(db, logger),
+		Supplier:            NewSupplierRepository(db, logger),
+		Expense:             NewExpenseRepository(db, logger),
+		RecurringExpense:    NewRecurringExpenseRepository(db, logger),
+		ExpenseApproval:     NewExpenseApprovalRepository(db, logger),
+		Revenue:             NewRevenueRepository(db, logger),
+		FinancialReport:     NewFinancialReportRepository(db, logger),
+		DonationPostingRule: NewDonationPostingRuleRepository(db, logger),
+		BankReconciliation:  NewBankReconciliationRepository(db, logger),
+		Budget:              NewBudgetRepository(db, logger),
+		Donation:            NewDonationRepository(db, logger),
+		Campaign:            NewCampaignRepository(db, logger),
+		RecurringDonation:   NewRecurringDonationRepository(db, logger),
+		AsaasConfig:         NewAsaasConfigRepository(db, logger),
+		AsaasAccount:        NewAsaasAccountRepository(db, logger),
+		PaymentGateway:      NewPaymentGatewaySettingsRepository(db, logger),
+		Engagement:          NewEngagementRepository(db, logger),
+		Contribution:        NewContributionRepository(db, logger),
+		GivingStatement:     NewGivingStatementRepository(db, logger),
+
+		db:     db,
+		logger: logger,
 	}
 }
+
+// Transaction executa fn com repositórios que compartilham a mesma transação. Se fn
+// retornar erro, nenhuma alteração feita pelos repositórios é gravada.
+func (r *Repositories) Transaction(ctx context.Context, fn func(repos *Repositories) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx, r.logger))
+	})
+}
+
+// RunExclusive executa fn somente se nenhuma outra instância do servidor estiver
+// executando a tarefa name, usando um advisory lock do PostgreSQL. O lock fica em uma
+// conexão reservada e é liberado ao fim de fn ou quando a conexão cai. Retorna false
+// sem executar fn quando outra instância detém o lock.
+func (r *Repositories) RunExclusive(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// O contexto da tarefa pode ter sido cancelado; o lock é liberado mesmo assim
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+			r.logger.Error("erro ao liberar lock da tarefa", zap.String("task", name), zap.Error(err))
+		}
+	}()
+
+	return true, fn(ctx)
+}
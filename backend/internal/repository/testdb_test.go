@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statementRecorder é um driver de banco de dados que apenas registra os comandos
+// executados. As consultas não retornam linhas.
+type statementRecorder struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+}
+
+func (r *statementRecorder) Connect(context.Context) (driver.Conn, error) { return r, nil }
+func (r *statementRecorder) Driver() driver.Driver                        { return nil }
+func (r *statementRecorder) Prepare(query string) (driver.Stmt, error) {
+	return &recordedStmt{r, query}, nil
+}
+func (r *statementRecorder) Close() error              { return nil }
+func (r *statementRecorder) Begin() (driver.Tx, error) { return r, nil }
+func (r *statementRecorder) Commit() error             { return nil }
+func (r *statementRecorder) Rollback() error           { return nil }
+
+func (r *statementRecorder) record(query string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+	r.args = append(r.args, args)
+}
+
+// Statements retorna os comandos executados que começam com prefix
+func (r *statementRecorder) Statements(prefix string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var statements []string
+	for _, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// Args retorna os parâmetros dos comandos executados que começam com prefix
+func (r *statementRecorder) Args(prefix string) [][]driver.Value {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var args [][]driver.Value
+	for i, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			args = append(args, r.args[i])
+		}
+	}
+	return args
+}
+
+type recordedStmt struct {
+	recorder *statementRecorder
+	query    string
+}
+
+func (s *recordedStmt) Close() error  { return nil }
+func (s *recordedStmt) NumInput() int { return -1 }
+
+func (s *recordedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.recorder.record(s.query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.recorder.record(s.query, args)
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// newTestRepositories cria os repositórios sobre o driver que registra os comandos
+func newTestRepositories(t *testing.T) (*Repositories, *statementRecorder) {
+	t.Helper()
+	recorder := &statementRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(recorder)}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	return NewRepositories(db, zap.NewNop()), recorder
+}
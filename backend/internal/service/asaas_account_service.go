@@ -11,6 +11,7 @@ import (
 
 	"github.com/comunidade/backend/internal/domain"
 	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/secret"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -478,7 +479,7 @@ func (s *AsaasAccountService) GetOnboardingURL(ctx context.Context, communityID,
 		zap.String("communityID", communityID),
 		zap.String("accountID", accountID),
 		zap.String("asaasID", account.AsaasID),
-		zap.String("apiKey", "***"+account.ApiKey[len(account.ApiKey)-4:]),
+		zap.String("apiKey", secret.Mask(account.ApiKey)),
 	)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/myAccount/documents", nil)
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comunidade/backend/internal/repository"
+)
+
+// Eventos de cobrança enviados pelo ASAAS
+const (
+	AsaasEventPaymentReceived  = "PAYMENT_RECEIVED"
+	AsaasEventPaymentConfirmed = "PAYMENT_CONFIRMED"
+	AsaasEventPaymentOverdue   = "PAYMENT_OVERDUE"
+	AsaasEventPaymentRefunded  = "PAYMENT_REFUNDED"
+	AsaasEventPaymentDeleted   = "PAYMENT_DELETED"
+
+	AsaasEventChargebackRequested      = "PAYMENT_CHARGEBACK_REQUESTED"
+	AsaasEventChargebackDispute        = "PAYMENT_CHARGEBACK_DISPUTE"
+	AsaasEventChargebackReversal       = "PAYMENT_AWAITING_CHARGEBACK_REVERSAL"
+	AsaasEventPaymentPartiallyRefunded = "PAYMENT_PARTIALLY_REFUNDED"
+)
+
+// AsaasPaymentWebhookEvent representa o corpo do webhook de cobranças do ASAAS
+type AsaasPaymentWebhookEvent struct {
+	Event   string `json:"event"`
+	Payment struct {
+		ID                string  `json:"id"`
+		Customer          string  `json:"customer"`
+		Subscription      string  `json:"subscription"`
+		BillingType       string  `json:"billingType"`
+		Value             float64 `json:"value"`
+		NetValue          float64 `json:"netValue"`
+		Status            string  `json:"status"`
+		DueDate           string  `json:"dueDate"`
+		PaymentDate       string  `json:"paymentDate"`
+		ConfirmedDate     string  `json:"confirmedDate"`
+		ClientPaymentDate string  `json:"clientPaymentDate"`
+		Description       string  `json:"description"`
+		ExternalReference string  `json:"externalReference"`
+		InvoiceURL        string  `json:"invoiceUrl"`
+	} `json:"payment"`
+}
+
+// ParseWebhook valida o token enviado pelo ASAAS no cabeçalho asaas-access-token, que
+// identifica a comunidade, e converte o evento de cobrança
+func (s *AsaasService) ParseWebhook(ctx context.Context, header http.Header, body []byte) (*PaymentEvent, error) {
+	var event AsaasPaymentWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, ErrInvalidPaymentEvent
+	}
+
+	token := header.Get("asaas-access-token")
+	if token == "" {
+		return nil, ErrInvalidWebhookToken
+	}
+
+	// Identifica a comunidade pelo token configurado no webhook
+	config, err := s.repos.AsaasConfig.FindByWebhookToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidWebhookToken
+		}
+		return nil, fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
+	}
+
+	result := &PaymentEvent{
+		CommunityID:    config.CommunityID,
+		Event:          event.Event,
+		ChargeID:       event.Payment.ID,
+		SubscriptionID: event.Payment.Subscription,
+		Amount:         event.Payment.Value,
+		Method:         s.mapBillingType(event.Payment.BillingType),
+		PaymentLink:    event.Payment.InvoiceURL,
+	}
+	if dueDate, err := time.Parse("2006-01-02", event.Payment.DueDate); err == nil {
+		result.DueDate = dueDate
+	}
+
+	switch event.Event {
+	case AsaasEventPaymentReceived, AsaasEventPaymentConfirmed:
+		result.Type = PaymentEventPaid
+		result.PaidAt = parseAsaasDate(event.Payment.PaymentDate, event.Payment.ConfirmedDate, event.Payment.ClientPaymentDate)
+	case AsaasEventPaymentOverdue:
+		result.Type = PaymentEventOverdue
+	case AsaasEventPaymentRefunded:
+		result.Type = PaymentEventRefunded
+	case AsaasEventPaymentDeleted:
+		result.Type = PaymentEventDeleted
+	case AsaasEventChargebackRequested, AsaasEventChargebackDispute:
+		result.Type = PaymentEventChargeback
+	case AsaasEventChargebackReversal:
+		result.Type = PaymentEventChargebackReversed
+	case AsaasEventPaymentPartiallyRefunded:
+		// Reembolsos parciais são registrados pela operação de reembolso da doação; o
+		// evento não traz o valor acumulado e é apenas registrado no log
+	}
+
+	return result, nil
+}
+
+// mapBillingType converte o tipo de cobrança do ASAAS para o método de pagamento interno
+func (s *AsaasService) mapBillingType(billingType string) string {
+	switch billingType {
+	case "CREDIT_CARD":
+		return "credit_card"
+	case "BOLETO":
+		return "boleto"
+	case "PIX":
+		return "pix"
+	default:
+		return ""
+	}
+}
+
+// parseAsaasDate retorna a primeira data válida no formato do ASAAS ou o horário atual
+func parseAsaasDate(dates ...string) time.Time {
+	for _, date := range dates {
+		if date == "" {
+			continue
+		}
+		if parsed, err := time.Parse("2006-01-02", date); err == nil {
+			return parsed
+		}
+	}
+	return time.Now()
+}
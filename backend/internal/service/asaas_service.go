@@ -15,6 +15,8 @@ import (
 	"go.uber.org/zap"
 )
 
+// AsaasService é o provedor de pagamentos ASAAS. Cobranças e clientes usam a chave da
+// conta ASAAS da comunidade; as assinaturas usam a chave da configuração do ASAAS.
 type AsaasService struct {
 	repos  *repository.Repositories
 	logger *zap.Logger
@@ -27,6 +29,25 @@ func NewAsaasService(repos *repository.Repositories, logger *zap.Logger) *AsaasS
 	}
 }
 
+// Name identifica o ASAAS entre os provedores de pagamento
+func (s *AsaasService) Name() string {
+	return GatewayAsaas
+}
+
+// Methods retorna as formas de pagamento aceitas pelo ASAAS
+func (s *AsaasService) Methods() []string {
+	return []string{"pix", "boleto", "credit_card"}
+}
+
+// Configured indica se a comunidade possui conta no ASAAS para receber as doações
+func (s *AsaasService) Configured(ctx context.Context, communityID string) (bool, error) {
+	account, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao buscar conta do ASAAS: %v", err)
+	}
+	return account != nil && account.ApiKey != "", nil
+}
+
 type AsaasCustomer struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
@@ -87,7 +108,9 @@ type AsaasSubscription struct {
 	CreditCardHolderInfo *AsaasCreditCardHolder `json:"creditCardHolderInfo,omitempty"`
 }
 
-func (s *AsaasService) CreateCustomer(ctx context.Context, communityID string, donation *domain.Donation) (string, error) {
+// CreateCustomer cria o cliente no ASAAS. Membros já cadastrados no ASAAS reaproveitam o
+// cliente existente.
+func (s *AsaasService) CreateCustomer(ctx context.Context, communityID string, customer *PaymentCustomer) (string, error) {
 	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
 	if err != nil {
 		return "", fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
@@ -97,7 +120,7 @@ func (s *AsaasService) CreateCustomer(ctx context.Context, communityID string, d
 	}
 
 	// Busca o membro pelo CPF
-	member, err := s.repos.Member.FindByCPF(ctx, communityID, donation.CustomerCPF)
+	member, err := s.repos.Member.FindByCPF(ctx, communityID, customer.CPF)
 	if err != nil {
 		return "", fmt.Errorf("erro ao buscar membro: %v", err)
 	}
@@ -109,16 +132,16 @@ func (s *AsaasService) CreateCustomer(ctx context.Context, communityID string, d
 
 	// Prepara os dados do cliente
 	customerData := AsaasCustomer{
-		Name:      donation.CustomerName,
-		CPF:       donation.CustomerCPF,
-		Email:     donation.CustomerEmail,
-		Phone:     donation.CustomerPhone,
-		Address:   donation.BillingAddress.Street,
-		Number:    donation.BillingAddress.Number,
-		District:  donation.BillingAddress.District,
-		City:      donation.BillingAddress.City,
-		State:     donation.BillingAddress.State,
-		ZipCode:   donation.BillingAddress.ZipCode,
+		Name:      customer.Name,
+		CPF:       customer.CPF,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		Address:   customer.Street,
+		Number:    customer.Number,
+		District:  customer.District,
+		City:      customer.City,
+		State:     customer.State,
+		ZipCode:   customer.ZipCode,
 		NotifyVia: "NONE",
 	}
 
@@ -169,13 +192,14 @@ func (s *AsaasService) CreateCustomer(ctx context.Context, communityID string, d
 	return response.ID, nil
 }
 
-func (s *AsaasService) CreatePayment(ctx context.Context, communityID string, donation *domain.Donation, customerID string) (string, error) {
-	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
+// CreateCharge cria a cobrança da doação no ASAAS
+func (s *AsaasService) CreateCharge(ctx context.Context, donation *domain.Donation, customerID string) (*PaymentCharge, error) {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, donation.CommunityID)
 	if err != nil {
-		return "", fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
+		return nil, fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
 	}
 	if config == nil {
-		return "", fmt.Errorf("configuração do ASAAS não encontrada")
+		return nil, fmt.Errorf("configuração do ASAAS não encontrada")
 	}
 
 	// Prepara os dados do pagamento
@@ -197,14 +221,14 @@ func (s *AsaasService) CreatePayment(ctx context.Context, communityID string, do
 
 	jsonData, err := json.Marshal(paymentData)
 	if err != nil {
-		return "", fmt.Errorf("erro ao serializar dados do pagamento: %v", err)
+		return nil, fmt.Errorf("erro ao serializar dados do pagamento: %v", err)
 	}
 
 	// Cria a requisição para o ASAAS
 	baseURL := os.Getenv("ASAAS_API_URL")
 	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/payments", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("erro ao criar requisição: %v", err)
+		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -213,13 +237,13 @@ func (s *AsaasService) CreatePayment(ctx context.Context, communityID string, do
 	// Executa a requisição
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("erro ao executar requisição: %v", err)
+		return nil, fmt.Errorf("erro ao executar requisição: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("erro ao criar pagamento no ASAAS: %v - %s", resp.Status, string(body))
+		return nil, fmt.Errorf("erro ao criar pagamento no ASAAS: %v - %s", resp.Status, string(body))
 	}
 
 	var response struct {
@@ -228,18 +252,18 @@ func (s *AsaasService) CreatePayment(ctx context.Context, communityID string, do
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("erro ao decodificar resposta: %v", err)
+		return nil, fmt.Errorf("erro ao decodificar resposta: %v", err)
 	}
 
-	// Armazena o link de pagamento na doação
-	donation.PaymentLink = response.PaymentLink
-	donation.AsaasPaymentID = response.ID
-
-	return response.ID, nil
+	return &PaymentCharge{
+		ID:          response.ID,
+		PaymentLink: response.PaymentLink,
+	}, nil
 }
 
-func (s *AsaasService) CreateSubscription(ctx context.Context, communityID string, donation *domain.RecurringDonation, customerID string) (string, error) {
-	config, err := s.repos.AsaasConfig.FindByCommunityID(ctx, communityID)
+// CreateSubscription cria a assinatura mensal da doação recorrente no ASAAS
+func (s *AsaasService) CreateSubscription(ctx context.Context, donation *domain.RecurringDonation, customerID string) (string, error) {
+	config, err := s.repos.AsaasConfig.FindByCommunityID(ctx, donation.CommunityID)
 	if err != nil {
 		return "", fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
 	}
@@ -247,6 +271,12 @@ func (s *AsaasService) CreateSubscription(ctx context.Context, communityID strin
 		return "", fmt.Errorf("configuração do ASAAS não encontrada")
 	}
 
+	// A primeira cobrança vence no próximo dia de vencimento escolhido
+	if donation.NextDueDate == nil {
+		nextDueDate := NextRecurringDueDate(time.Now(), donation.DueDay)
+		donation.NextDueDate = &nextDueDate
+	}
+
 	// Prepara os dados da assinatura
 	subscriptionData := struct {
 		Customer          string  `json:"customer"`
@@ -261,7 +291,7 @@ func (s *AsaasService) CreateSubscription(ctx context.Context, communityID strin
 		Customer:          customerID,
 		BillingType:       s.mapPaymentMethod(donation.PaymentMethod),
 		Value:             donation.Amount,
-		NextDueDate:       time.Now().AddDate(0, 0, donation.DueDay).Format("2006-01-02"),
+		NextDueDate:       donation.NextDueDate.Format("2006-01-02"),
 		Description:       donation.Description,
 		Cycle:             "MONTHLY",
 		DueDay:            donation.DueDay,
@@ -306,88 +336,6 @@ func (s *AsaasService) CreateSubscription(ctx context.Context, communityID strin
 	return response.ID, nil
 }
 
-func (s *AsaasService) CreateCustomerFromRecurringDonation(ctx context.Context, communityID string, donation *domain.RecurringDonation) (string, error) {
-	config, err := s.repos.AsaasConfig.FindByCommunityID(ctx, communityID)
-	if err != nil {
-		return "", fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
-	}
-	if config == nil {
-		return "", fmt.Errorf("configuração do ASAAS não encontrada")
-	}
-
-	// Busca o membro pelo CPF
-	member, err := s.repos.Member.FindByCPF(ctx, communityID, donation.CustomerCPF)
-	if err != nil {
-		return "", fmt.Errorf("erro ao buscar membro: %v", err)
-	}
-
-	// Se encontrou o membro e ele já tem ID do ASAAS, retorna o ID existente
-	if member != nil && member.AsaasCustomerID != "" {
-		return member.AsaasCustomerID, nil
-	}
-
-	// Prepara os dados do cliente
-	customerData := AsaasCustomer{
-		Name:      donation.CustomerName,
-		CPF:       donation.CustomerCPF,
-		Email:     donation.CustomerEmail,
-		Phone:     donation.CustomerPhone,
-		Address:   donation.BillingAddress.Street,
-		Number:    donation.BillingAddress.Number,
-		District:  donation.BillingAddress.District,
-		City:      donation.BillingAddress.City,
-		State:     donation.BillingAddress.State,
-		ZipCode:   donation.BillingAddress.ZipCode,
-		NotifyVia: "NONE",
-	}
-
-	jsonData, err := json.Marshal(customerData)
-	if err != nil {
-		return "", fmt.Errorf("erro ao serializar dados do cliente: %v", err)
-	}
-
-	// Cria a requisição para o ASAAS
-	baseURL := os.Getenv("ASAAS_API_URL")
-	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/customers", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("erro ao criar requisição: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("access_token", config.ApiKey)
-
-	// Executa a requisição
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("erro ao executar requisição: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("erro ao criar cliente no ASAAS: %v - %s", resp.Status, string(body))
-	}
-
-	var response struct {
-		ID string `json:"id"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("erro ao decodificar resposta: %v", err)
-	}
-
-	// Se encontrou o membro, atualiza com o ID do ASAAS
-	if member != nil {
-		member.AsaasCustomerID = response.ID
-		if err := s.repos.Member.Update(ctx, member); err != nil {
-			s.logger.Error("erro ao atualizar membro com ID do ASAAS", zap.Error(err))
-			// Não retornamos erro aqui pois o cliente já foi criado no ASAAS
-		}
-	}
-
-	return response.ID, nil
-}
-
 func (s *AsaasService) mapPaymentMethod(method string) string {
 	switch method {
 	case "credit_card":
@@ -401,9 +349,9 @@ func (s *AsaasService) mapPaymentMethod(method string) string {
 	}
 }
 
-// UpdatePayment atualiza uma cobrança existente no ASAAS
-func (s *AsaasService) UpdatePayment(ctx context.Context, communityID string, paymentID string, payment *domain.Donation) error {
-	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
+// UpdateCharge atualiza uma cobrança existente no ASAAS
+func (s *AsaasService) UpdateCharge(ctx context.Context, payment *domain.Donation) error {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, payment.CommunityID)
 	if err != nil {
 		return fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
 	}
@@ -429,7 +377,7 @@ func (s *AsaasService) UpdatePayment(ctx context.Context, communityID string, pa
 
 	// Cria a requisição para o ASAAS
 	baseURL := os.Getenv("ASAAS_API_URL")
-	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/payments/%s", baseURL, paymentID), bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/payments/%s", baseURL, payment.AsaasID), bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("erro ao criar requisição: %v", err)
 	}
@@ -452,9 +400,9 @@ func (s *AsaasService) UpdatePayment(ctx context.Context, communityID string, pa
 	return nil
 }
 
-// DeletePayment exclui uma cobrança no ASAAS
-func (s *AsaasService) DeletePayment(ctx context.Context, communityID string, paymentID string) error {
-	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
+// CancelCharge exclui uma cobrança no ASAAS
+func (s *AsaasService) CancelCharge(ctx context.Context, donation *domain.Donation) error {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, donation.CommunityID)
 	if err != nil {
 		return fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
 	}
@@ -464,7 +412,7 @@ func (s *AsaasService) DeletePayment(ctx context.Context, communityID string, pa
 
 	// Cria a requisição para o ASAAS
 	baseURL := os.Getenv("ASAAS_API_URL")
-	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/payments/%s", baseURL, paymentID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/payments/%s", baseURL, donation.AsaasID), nil)
 	if err != nil {
 		return fmt.Errorf("erro ao criar requisição: %v", err)
 	}
@@ -486,9 +434,9 @@ func (s *AsaasService) DeletePayment(ctx context.Context, communityID string, pa
 	return nil
 }
 
-// SendPaymentLink envia o link de pagamento por e-mail
-func (s *AsaasService) SendPaymentLink(ctx context.Context, communityID, paymentID, email string) error {
-	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, communityID)
+// SendChargeLink envia o link de pagamento por e-mail ao doador
+func (s *AsaasService) SendChargeLink(ctx context.Context, donation *domain.Donation) error {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, donation.CommunityID)
 	if err != nil {
 		return fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
 	}
@@ -498,11 +446,11 @@ func (s *AsaasService) SendPaymentLink(ctx context.Context, communityID, payment
 
 	// Cria a requisição para o ASAAS
 	baseURL := os.Getenv("ASAAS_API_URL")
-	url := fmt.Sprintf("%s/payments/%s/paymentLink", baseURL, paymentID)
+	url := fmt.Sprintf("%s/payments/%s/paymentLink", baseURL, donation.AsaasID)
 
 	// Prepara o payload
 	payload := map[string]string{
-		"email": email,
+		"email": donation.CustomerEmail,
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -534,3 +482,239 @@ func (s *AsaasService) SendPaymentLink(ctx context.Context, communityID, payment
 
 	return nil
 }
+
+// PixQrCode é o QR Code de uma cobrança Pix: a imagem em base64 e o código copia e cola
+type PixQrCode struct {
+	EncodedImage   string `json:"encoded_image"`
+	Payload        string `json:"payload"`
+	ExpirationDate string `json:"expiration_date"`
+}
+
+// PixQrCode busca o QR Code de uma cobrança Pix no ASAAS
+func (s *AsaasService) PixQrCode(ctx context.Context, donation *domain.Donation) (*PixQrCode, error) {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, donation.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("configuração do ASAAS não encontrada")
+	}
+
+	// Cria a requisição para o ASAAS
+	baseURL := os.Getenv("ASAAS_API_URL")
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/payments/%s/pixQrCode", baseURL, donation.AsaasID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+
+	req.Header.Set("access_token", config.ApiKey)
+
+	// Executa a requisição
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro ao buscar QR Code Pix no ASAAS: %v - %s", resp.Status, string(body))
+	}
+
+	var response struct {
+		EncodedImage   string `json:"encodedImage"`
+		Payload        string `json:"payload"`
+		ExpirationDate string `json:"expirationDate"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta: %v", err)
+	}
+
+	return &PixQrCode{
+		EncodedImage:   response.EncodedImage,
+		Payload:        response.Payload,
+		ExpirationDate: response.ExpirationDate,
+	}, nil
+}
+
+// RefundCharge estorna o valor informado de uma cobrança paga no ASAAS
+func (s *AsaasService) RefundCharge(ctx context.Context, donation *domain.Donation, amount float64) error {
+	config, err := s.repos.AsaasAccount.FindByCommunityID(ctx, donation.CommunityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
+	}
+	if config == nil {
+		return fmt.Errorf("configuração do ASAAS não encontrada")
+	}
+
+	jsonData, err := json.Marshal(map[string]float64{"value": amount})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar dados do estorno: %v", err)
+	}
+
+	// Cria a requisição para o ASAAS
+	baseURL := os.Getenv("ASAAS_API_URL")
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/payments/%s/refund", baseURL, donation.AsaasID), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("access_token", config.ApiKey)
+
+	// Executa a requisição
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao executar requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("erro ao estornar pagamento no ASAAS: %v - %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// asaasSubscriptionUpdate é o corpo da alteração de assinatura no ASAAS
+type asaasSubscriptionUpdate struct {
+	Value                 *float64 `json:"value,omitempty"`
+	NextDueDate           string   `json:"nextDueDate,omitempty"`
+	Status                string   `json:"status,omitempty"`
+	UpdatePendingPayments bool     `json:"updatePendingPayments"`
+}
+
+// UpdateSubscription altera o valor, o vencimento ou a situação de uma assinatura no ASAAS
+func (s *AsaasService) UpdateSubscription(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error {
+	payload := &asaasSubscriptionUpdate{
+		Value:                 update.Amount,
+		UpdatePendingPayments: update.UpdatePendingPayments,
+	}
+	if update.NextDueDate != nil {
+		payload.NextDueDate = update.NextDueDate.Format("2006-01-02")
+	}
+	switch update.Status {
+	case "active":
+		payload.Status = "ACTIVE"
+	case "paused":
+		payload.Status = "INACTIVE"
+	}
+	return s.subscriptionRequest(ctx, recurring.CommunityID, "POST", "/subscriptions/"+recurring.AsaasID, payload, nil)
+}
+
+// CancelSubscription cancela uma assinatura no ASAAS, removendo as cobranças pendentes
+func (s *AsaasService) CancelSubscription(ctx context.Context, recurring *domain.RecurringDonation) error {
+	return s.subscriptionRequest(ctx, recurring.CommunityID, "DELETE", "/subscriptions/"+recurring.AsaasID, nil, nil)
+}
+
+// UpdateSubscriptionCard substitui o cartão de crédito de uma assinatura sem gerar cobrança
+func (s *AsaasService) UpdateSubscriptionCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) (*CardInfo, error) {
+	payload := struct {
+		CreditCard           *AsaasCreditCard       `json:"creditCard"`
+		CreditCardHolderInfo *AsaasCreditCardHolder `json:"creditCardHolderInfo"`
+		RemoteIP             string                 `json:"remoteIp"`
+	}{
+		CreditCard:           asaasCreditCard(card),
+		CreditCardHolderInfo: asaasCreditCardHolder(holder),
+		RemoteIP:             remoteIP,
+	}
+
+	var response struct {
+		CreditCard struct {
+			Number string `json:"creditCardNumber"`
+			Brand  string `json:"creditCardBrand"`
+		} `json:"creditCard"`
+	}
+	if err := s.subscriptionRequest(ctx, recurring.CommunityID, "PUT", "/subscriptions/"+recurring.AsaasID+"/creditCard", payload, &response); err != nil {
+		return nil, err
+	}
+
+	info := &CardInfo{
+		Brand:      response.CreditCard.Brand,
+		LastDigits: response.CreditCard.Number,
+	}
+	if info.LastDigits == "" && len(card.Number) >= 4 {
+		info.LastDigits = card.Number[len(card.Number)-4:]
+	}
+	return info, nil
+}
+
+// asaasCreditCard converte o cartão informado para o formato da API do ASAAS
+func asaasCreditCard(card *PaymentCard) *AsaasCreditCard {
+	return &AsaasCreditCard{
+		HolderName:  card.HolderName,
+		Number:      card.Number,
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+		CVV:         card.CVV,
+	}
+}
+
+// asaasCreditCardHolder converte o titular do cartão para o formato da API do ASAAS
+func asaasCreditCardHolder(holder *PaymentCardHolder) *AsaasCreditCardHolder {
+	return &AsaasCreditCardHolder{
+		Name:     holder.Name,
+		CPF:      holder.CPF,
+		Email:    holder.Email,
+		Phone:    holder.Phone,
+		Address:  holder.Street,
+		Number:   holder.Number,
+		District: holder.District,
+		City:     holder.City,
+		State:    holder.State,
+		ZipCode:  holder.ZipCode,
+	}
+}
+
+// subscriptionRequest executa uma requisição de assinatura no ASAAS com a chave da comunidade
+func (s *AsaasService) subscriptionRequest(ctx context.Context, communityID, method, path string, payload, out interface{}) error {
+	config, err := s.repos.AsaasConfig.FindByCommunityID(ctx, communityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar configuração do ASAAS: %v", err)
+	}
+	if config == nil {
+		return fmt.Errorf("configuração do ASAAS não encontrada")
+	}
+
+	var body io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar dados da assinatura: %v", err)
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	// Cria a requisição para o ASAAS
+	baseURL := os.Getenv("ASAAS_API_URL")
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %v", err)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("access_token", config.ApiKey)
+
+	// Executa a requisição
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao executar requisição: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("erro ao atualizar assinatura no ASAAS: %v - %s", resp.Status, string(body))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("erro ao decodificar resposta: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBudgetNotFound       = errors.New("orçamento não encontrado")
+	ErrBudgetExists         = errors.New("já existe um orçamento para esta categoria, ministério, período e ano")
+	ErrBudgetCategory       = errors.New("categoria financeira não encontrada")
+	ErrBudgetGroup          = errors.New("ministério não encontrado")
+	ErrInvalidBudgetMonth   = errors.New("mês inválido")
+	ErrInvalidNotifyEmails  = errors.New("e-mail inválido na lista de notificação do orçamento")
+	ErrInvalidBudgetPercent = errors.New("o percentual de alerta deve estar entre 1 e 100")
+)
+
+// defaultBudgetAlertThreshold é o percentual de alerta usado quando não informado
+const defaultBudgetAlertThreshold = 80
+
+// Situação do orçamento em relação ao realizado
+const (
+	BudgetStatusOK       = "ok"
+	BudgetStatusWarning  = "warning"
+	BudgetStatusExceeded = "exceeded"
+)
+
+// BudgetService controla os orçamentos por categoria, o comparativo orçado x
+// realizado e os alertas enviados quando uma categoria de despesa se aproxima
+// do limite ou o ultrapassa.
+type BudgetService struct {
+	repos         *repository.Repositories
+	logger        *zap.Logger
+	communication CommunicationService
+}
+
+func NewBudgetService(repos *repository.Repositories, logger *zap.Logger, communication CommunicationService) *BudgetService {
+	return &BudgetService{
+		repos:         repos,
+		logger:        logger,
+		communication: communication,
+	}
+}
+
+// BudgetStatus é o comparativo orçado x realizado de um orçamento no período
+type BudgetStatus struct {
+	Budget      *domain.Budget       `json:"budget"`
+	PeriodStart time.Time            `json:"period_start"`
+	PeriodEnd   time.Time            `json:"period_end"`
+	Budgeted    float64              `json:"budgeted"`
+	Actual      float64              `json:"actual"`
+	Remaining   float64              `json:"remaining"`
+	Percent     float64              `json:"percent"`
+	Status      string               `json:"status"`
+	Months      []*BudgetMonthStatus `json:"months,omitempty"`
+}
+
+// BudgetMonthStatus é o comparativo de um mês de um orçamento mensal
+type BudgetMonthStatus struct {
+	Month    int     `json:"month"`
+	Budgeted float64 `json:"budgeted"`
+	Actual   float64 `json:"actual"`
+	Percent  float64 `json:"percent"`
+	Status   string  `json:"status"`
+}
+
+// BudgetSummary reúne o comparativo de todos os orçamentos do período
+type BudgetSummary struct {
+	Year            int             `json:"year"`
+	Month           int             `json:"month,omitempty"`
+	Budgets         []*BudgetStatus `json:"budgets"`
+	ExpenseBudgeted float64         `json:"expense_budgeted"`
+	ExpenseActual   float64         `json:"expense_actual"`
+	RevenueBudgeted float64         `json:"revenue_budgeted"`
+	RevenueActual   float64         `json:"revenue_actual"`
+}
+
+// CreateBudget valida e registra um orçamento
+func (s *BudgetService) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	if err := s.validateBudget(ctx, budget); err != nil {
+		return err
+	}
+
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = time.Now()
+	if err := s.repos.Budget.Create(ctx, budget); err != nil {
+		return fmt.Errorf("erro ao criar orçamento: %v", err)
+	}
+	return nil
+}
+
+// UpdateBudget valida e atualiza um orçamento
+func (s *BudgetService) UpdateBudget(ctx context.Context, budget *domain.Budget) error {
+	if err := s.validateBudget(ctx, budget); err != nil {
+		return err
+	}
+
+	budget.UpdatedAt = time.Now()
+	budget.Category = nil
+	budget.Group = nil
+	if err := s.repos.Budget.Update(ctx, budget); err != nil {
+		return fmt.Errorf("erro ao atualizar orçamento: %v", err)
+	}
+	return nil
+}
+
+// GetBudget retorna o orçamento
+func (s *BudgetService) GetBudget(ctx context.Context, communityID, budgetID string) (*domain.Budget, error) {
+	budget, err := s.repos.Budget.FindByID(ctx, communityID, budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar orçamento: %v", err)
+	}
+	if budget == nil {
+		return nil, ErrBudgetNotFound
+	}
+	return budget, nil
+}
+
+// GetStatus calcula o orçado x realizado do orçamento. Para orçamentos mensais,
+// month (1-12) restringe o comparativo a um mês; com month = 0 é considerado o
+// ano inteiro, com o detalhamento mês a mês.
+func (s *BudgetService) GetStatus(ctx context.Context, community *domain.Community, budget *domain.Budget, month int) (*BudgetStatus, error) {
+	if month < 0 || month > 12 {
+		return nil, ErrInvalidBudgetMonth
+	}
+	if budget.Category == nil {
+		category, err := s.repos.FinancialCategory.FindByID(ctx, budget.CommunityID, budget.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar categoria: %v", err)
+		}
+		if category == nil {
+			return nil, ErrBudgetCategory
+		}
+		budget.Category = category
+	}
+
+	location := communityLocation(community)
+	status := &BudgetStatus{Budget: budget}
+
+	if budget.Period == "monthly" && month > 0 {
+		status.PeriodStart = time.Date(budget.Year, time.Month(month), 1, 0, 0, 0, 0, location)
+		status.PeriodEnd = status.PeriodStart.AddDate(0, 1, 0)
+		status.Budgeted = budget.Amount
+		actual, err := s.actual(ctx, budget, status.PeriodStart, status.PeriodEnd)
+		if err != nil {
+			return nil, err
+		}
+		status.Actual = actual
+	} else {
+		status.PeriodStart = time.Date(budget.Year, time.January, 1, 0, 0, 0, 0, location)
+		status.PeriodEnd = status.PeriodStart.AddDate(1, 0, 0)
+		status.Budgeted = budget.Amount
+
+		if budget.Period == "monthly" {
+			status.Budgeted = budget.Amount * 12
+			for m := 1; m <= 12; m++ {
+				start := time.Date(budget.Year, time.Month(m), 1, 0, 0, 0, 0, location)
+				actual, err := s.actual(ctx, budget, start, start.AddDate(0, 1, 0))
+				if err != nil {
+					return nil, err
+				}
+				percent := budgetPercent(actual, budget.Amount)
+				status.Months = append(status.Months, &BudgetMonthStatus{
+					Month:    m,
+					Budgeted: budget.Amount,
+					Actual:   actual,
+					Percent:  percent,
+					Status:   budgetStatus(percent, budget.AlertThreshold),
+				})
+				status.Actual += actual
+			}
+			status.Actual = math.Round(status.Actual*100) / 100
+		} else {
+			actual, err := s.actual(ctx, budget, status.PeriodStart, status.PeriodEnd)
+			if err != nil {
+				return nil, err
+			}
+			status.Actual = actual
+		}
+	}
+
+	status.Remaining = math.Round((status.Budgeted-status.Actual)*100) / 100
+	status.Percent = budgetPercent(status.Actual, status.Budgeted)
+	status.Status = budgetStatus(status.Percent, budget.AlertThreshold)
+	return status, nil
+}
+
+// Summary calcula o orçado x realizado de todos os orçamentos do ano (ou do mês)
+func (s *BudgetService) Summary(ctx context.Context, community *domain.Community, year, month int) (*BudgetSummary, error) {
+	if month < 0 || month > 12 {
+		return nil, ErrInvalidBudgetMonth
+	}
+
+	filter := &repository.Filter{Page: 1, PerPage: 100, OrderBy: "created_at", OrderDir: "asc"}
+	filter.AddCondition("year = ?", year)
+
+	summary := &BudgetSummary{Year: year, Month: month, Budgets: []*BudgetStatus{}}
+	for {
+		budgets, total, err := s.repos.Budget.List(ctx, community.ID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar orçamentos: %v", err)
+		}
+
+		for _, budget := range budgets {
+			budgetMonth := month
+			if budget.Period == "annual" {
+				budgetMonth = 0
+			}
+			status, err := s.GetStatus(ctx, community, budget, budgetMonth)
+			if err != nil {
+				return nil, err
+			}
+			summary.Budgets = append(summary.Budgets, status)
+
+			if budget.Category != nil && budget.Category.Type == "revenue" {
+				summary.RevenueBudgeted += status.Budgeted
+				summary.RevenueActual += status.Actual
+			} else {
+				summary.ExpenseBudgeted += status.Budgeted
+				summary.ExpenseActual += status.Actual
+			}
+		}
+
+		if int64(filter.Page*filter.PerPage) >= total {
+			break
+		}
+		filter.Page++
+	}
+
+	summary.ExpenseBudgeted = math.Round(summary.ExpenseBudgeted*100) / 100
+	summary.ExpenseActual = math.Round(summary.ExpenseActual*100) / 100
+	summary.RevenueBudgeted = math.Round(summary.RevenueBudgeted*100) / 100
+	summary.RevenueActual = math.Round(summary.RevenueActual*100) / 100
+	return summary, nil
+}
+
+// ListAlerts lista os alertas enviados para o orçamento
+func (s *BudgetService) ListAlerts(ctx context.Context, budgetID string) ([]*domain.BudgetAlert, error) {
+	alerts, err := s.repos.Budget.ListAlerts(ctx, budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar alertas do orçamento: %v", err)
+	}
+	return alerts, nil
+}
+
+// CheckExpense verifica os orçamentos afetados pela despesa e envia os alertas cujo
+// percentual foi atingido no período da despesa
+func (s *BudgetService) CheckExpense(ctx context.Context, expense *domain.Expense) error {
+	if expense.Status == "cancelled" {
+		return nil
+	}
+
+	community, err := s.repos.Community.FindByID(ctx, expense.CommunityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil
+	}
+
+	date := expense.Date.In(communityLocation(community))
+	budgets, err := s.repos.Budget.FindByCategory(ctx, community.ID, expense.CategoryID, date.Year())
+	if err != nil {
+		return fmt.Errorf("erro ao buscar orçamentos: %v", err)
+	}
+
+	for _, budget := range budgets {
+		if budget.GroupID != nil && (expense.GroupID == nil || *expense.GroupID != *budget.GroupID) {
+			continue
+		}
+		if err := s.CheckBudget(ctx, community, budget, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckBudget envia os alertas do orçamento para o período que contém a data
+// informada. Cada nível é enviado uma única vez por período.
+func (s *BudgetService) CheckBudget(ctx context.Context, community *domain.Community, budget *domain.Budget, date time.Time) error {
+	if budget.Category == nil || budget.Category.Type != "expense" {
+		return nil
+	}
+
+	month := 0
+	if budget.Period == "monthly" {
+		month = int(date.In(communityLocation(community)).Month())
+	}
+	status, err := s.GetStatus(ctx, community, budget, month)
+	if err != nil {
+		return err
+	}
+
+	levels := []float64{100}
+	if budget.AlertThreshold < 100 {
+		levels = []float64{budget.AlertThreshold, 100}
+	}
+
+	// Envia apenas o nível mais alto atingido; os inferiores são registrados sem envio
+	var reached []float64
+	for _, level := range levels {
+		if status.Percent >= level {
+			reached = append(reached, level)
+		}
+	}
+	for i, level := range reached {
+		alert := &domain.BudgetAlert{
+			BudgetID:    budget.ID,
+			PeriodStart: status.PeriodStart,
+			Level:       level,
+			Budgeted:    status.Budgeted,
+			Actual:      status.Actual,
+			Percent:     status.Percent,
+			CreatedAt:   time.Now(),
+		}
+		created, err := s.repos.Budget.CreateAlert(ctx, alert)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar alerta de orçamento: %v", err)
+		}
+		if !created || i < len(reached)-1 {
+			continue
+		}
+
+		communication, err := s.sendAlert(ctx, community, budget, status, level)
+		if err != nil {
+			return err
+		}
+		if communication != nil {
+			alert.CommunicationID = &communication.ID
+			if err := s.repos.Budget.UpdateAlert(ctx, alert); err != nil {
+				return fmt.Errorf("erro ao atualizar alerta de orçamento: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// sendAlert envia o alerta por e-mail ao responsável pela comunidade e aos e-mails
+// configurados no orçamento
+func (s *BudgetService) sendAlert(ctx context.Context, community *domain.Community, budget *domain.Budget, status *BudgetStatus, level float64) (*domain.Communication, error) {
+	recipients := splitEmails(budget.NotifyEmails)
+	owner, err := s.repos.User.FindByID(ctx, community.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar responsável pela comunidade: %v", err)
+	}
+	if owner != nil && owner.Email != "" {
+		recipients = append([]string{owner.Email}, recipients...)
+	}
+	recipients = uniqueEmails(recipients)
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	scope := budget.Category.Name
+	if budget.Group != nil {
+		scope += " - " + budget.Group.Name
+	}
+	subject := fmt.Sprintf("Orçamento de %s atingiu %.0f%%", scope, status.Percent)
+	if level >= 100 {
+		subject = fmt.Sprintf("Orçamento de %s ultrapassado", scope)
+	}
+
+	var body bytes.Buffer
+	if err := budgetAlertTemplate.Execute(&body, map[string]interface{}{
+		"Community": community.Name,
+		"Scope":     scope,
+		"Period":    budgetPeriodLabel(budget, status),
+		"Budgeted":  formatCurrency(status.Budgeted),
+		"Actual":    formatCurrency(status.Actual),
+		"Remaining": formatCurrency(status.Remaining),
+		"Percent":   fmt.Sprintf("%.1f%%", status.Percent),
+		"Exceeded":  level >= 100,
+	}); err != nil {
+		return nil, fmt.Errorf("erro ao gerar alerta de orçamento: %v", err)
+	}
+
+	messages := make([]PersonalizedMessage, 0, len(recipients))
+	for _, email := range recipients {
+		messages = append(messages, PersonalizedMessage{
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   "budget-" + budget.ID,
+			Email:         email,
+			Subject:       subject,
+			Body:          body.String(),
+		})
+	}
+
+	communication := &domain.Communication{
+		Type:          domain.CommunicationTypeEmail,
+		Subject:       subject,
+		Content:       body.String(),
+		RecipientType: domain.RecipientTypeCustom,
+		RecipientID:   "budget-" + budget.ID,
+		CreatedBy:     community.CreatedBy,
+	}
+	if err := s.communication.SendPersonalizedCommunication(ctx, community.ID, communication, messages); err != nil {
+		return nil, fmt.Errorf("erro ao enviar alerta de orçamento: %v", err)
+	}
+	return communication, nil
+}
+
+// actual soma o realizado da categoria (e do ministério) no período [start, end)
+func (s *BudgetService) actual(ctx context.Context, budget *domain.Budget, start, end time.Time) (float64, error) {
+	// Os totais consideram o intervalo fechado; o fim é ajustado para não incluir o próximo período
+	end = end.Add(-time.Microsecond)
+
+	var total float64
+	var err error
+	switch {
+	case budget.Category.Type == "revenue" && budget.GroupID != nil:
+		total, err = s.repos.Revenue.GetTotalByCategoryAndGroup(ctx, budget.CommunityID, budget.CategoryID, *budget.GroupID, start, end)
+	case budget.Category.Type == "revenue":
+		total, err = s.repos.Revenue.GetTotalByCategory(ctx, budget.CommunityID, budget.CategoryID, start, end)
+	case budget.GroupID != nil:
+		total, err = s.repos.Expense.GetTotalByCategoryAndGroup(ctx, budget.CommunityID, budget.CategoryID, *budget.GroupID, start, end)
+	default:
+		total, err = s.repos.Expense.GetTotalByCategory(ctx, budget.CommunityID, budget.CategoryID, start, end)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao calcular realizado do orçamento: %v", err)
+	}
+	return math.Round(total*100) / 100, nil
+}
+
+func (s *BudgetService) validateBudget(ctx context.Context, budget *domain.Budget) error {
+	if budget.AlertThreshold == 0 {
+		budget.AlertThreshold = defaultBudgetAlertThreshold
+	}
+	if budget.AlertThreshold < 1 || budget.AlertThreshold > 100 {
+		return ErrInvalidBudgetPercent
+	}
+
+	for _, email := range splitEmails(budget.NotifyEmails) {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return ErrInvalidNotifyEmails
+		}
+	}
+	budget.NotifyEmails = strings.Join(splitEmails(budget.NotifyEmails), ", ")
+
+	category, err := s.repos.FinancialCategory.FindByID(ctx, budget.CommunityID, budget.CategoryID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar categoria: %v", err)
+	}
+	if category == nil {
+		return ErrBudgetCategory
+	}
+
+	if budget.GroupID != nil {
+		group, err := s.repos.Group.FindByID(ctx, budget.CommunityID, *budget.GroupID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar ministério: %v", err)
+		}
+		if group == nil {
+			return ErrBudgetGroup
+		}
+	}
+
+	existing, err := s.repos.Budget.FindByScope(ctx, budget.CommunityID, budget.CategoryID, budget.GroupID, budget.Period, budget.Year)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar orçamento: %v", err)
+	}
+	if existing != nil && existing.ID != budget.ID {
+		return ErrBudgetExists
+	}
+	return nil
+}
+
+// budgetPercent retorna o percentual realizado com uma casa decimal
+func budgetPercent(actual, budgeted float64) float64 {
+	if budgeted <= 0 {
+		return 0
+	}
+	return math.Round(actual/budgeted*1000) / 10
+}
+
+func budgetStatus(percent, threshold float64) string {
+	switch {
+	case percent >= 100:
+		return BudgetStatusExceeded
+	case percent >= threshold:
+		return BudgetStatusWarning
+	}
+	return BudgetStatusOK
+}
+
+func budgetPeriodLabel(budget *domain.Budget, status *BudgetStatus) string {
+	if budget.Period == "monthly" && status.Months == nil {
+		return status.PeriodStart.Format("01/2006")
+	}
+	return fmt.Sprintf("%d", budget.Year)
+}
+
+// splitEmails separa uma lista de e-mails delimitada por vírgula ou ponto e vírgula
+func splitEmails(value string) []string {
+	var emails []string
+	for _, email := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+func uniqueEmails(emails []string) []string {
+	seen := make(map[string]bool, len(emails))
+	unique := emails[:0]
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, email)
+	}
+	return unique
+}
+
+var budgetAlertTemplate = template.Must(template.New("budget-alert").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="font-family: Arial, Helvetica, sans-serif; color: #222;">
+<h2>{{.Community}}</h2>
+{{if .Exceeded}}
+<p>O orçamento de <strong>{{.Scope}}</strong> foi ultrapassado no período {{.Period}}.</p>
+{{else}}
+<p>O orçamento de <strong>{{.Scope}}</strong> atingiu {{.Percent}} no período {{.Period}}.</p>
+{{end}}
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr><td>Orçado</td><td style="text-align: right;">{{.Budgeted}}</td></tr>
+<tr><td>Realizado</td><td style="text-align: right;">{{.Actual}}</td></tr>
+<tr><td>Saldo</td><td style="text-align: right;">{{.Remaining}}</td></tr>
+<tr><td>Percentual utilizado</td><td style="text-align: right;">{{.Percent}}</td></tr>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCampaignNotFound     = errors.New("campanha não encontrada")
+	ErrCampaignNotActive    = errors.New("a campanha não está ativa")
+	ErrCampaignCancelled    = errors.New("campanhas canceladas não podem ser alteradas")
+	ErrInvalidCampaignGoal  = errors.New("a meta da campanha deve ser maior que zero")
+	ErrInvalidCampaignEnd   = errors.New("a data final deve ser posterior à data inicial")
+	ErrInvalidCampaignClose = errors.New("a campanha só pode ser encerrada como concluída ou cancelada")
+	ErrCommunityNotFound    = errors.New("comunidade não encontrada")
+)
+
+// CampaignService acompanha a arrecadação das campanhas e as encerra automaticamente
+// quando a data final passa ou a meta é atingida.
+type CampaignService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewCampaignService(repos *repository.Repositories, logger *zap.Logger) *CampaignService {
+	return &CampaignService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// CampaignProgress é a arrecadação de uma campanha em relação à meta
+type CampaignProgress struct {
+	Goal            float64 `json:"goal"`
+	Raised          float64 `json:"raised"`
+	OneTimeRaised   float64 `json:"one_time_raised"`
+	RecurringRaised float64 `json:"recurring_raised"`
+	Remaining       float64 `json:"remaining"`
+	Percent         float64 `json:"percent"`
+	DonationCount   int64   `json:"donation_count"`
+	DonorCount      int64   `json:"donor_count"`
+	GoalReached     bool    `json:"goal_reached"`
+	DaysLeft        *int    `json:"days_left,omitempty"`
+}
+
+// CampaignSummary reúne a campanha e o seu progresso
+type CampaignSummary struct {
+	Campaign *domain.Campaign  `json:"campaign"`
+	Progress *CampaignProgress `json:"progress"`
+}
+
+// PublicCampaign é a visão da campanha exibida no site de doações, sem os dados internos
+type PublicCampaign struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	Status      string     `json:"status"`
+	Goal        float64    `json:"goal"`
+	Raised      float64    `json:"raised"`
+	Percent     float64    `json:"percent"`
+	DonorCount  int64      `json:"donor_count"`
+	GoalReached bool       `json:"goal_reached"`
+	DaysLeft    *int       `json:"days_left,omitempty"`
+}
+
+// PublicCommunity identifica a comunidade na página pública das campanhas
+type PublicCommunity struct {
+	Name   string `json:"name"`
+	Slug   string `json:"slug"`
+	Logo   string `json:"logo"`
+	Banner string `json:"banner"`
+}
+
+// CreateCampaign valida e cria uma campanha ativa
+func (s *CampaignService) CreateCampaign(ctx context.Context, community *domain.Community, campaign *domain.Campaign) error {
+	if err := validateCampaign(campaign); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	campaign.CommunityID = community.ID
+	campaign.Status = "active"
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	if err := s.repos.Campaign.Create(ctx, campaign); err != nil {
+		return fmt.Errorf("erro ao criar campanha: %v", err)
+	}
+	return nil
+}
+
+// GetCampaign retorna a campanha com o progresso, encerrando-a se já estiver concluída
+func (s *CampaignService) GetCampaign(ctx context.Context, community *domain.Community, id string) (*CampaignSummary, error) {
+	campaign, err := s.findCampaign(ctx, community.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := s.summarize(ctx, community, []*domain.Campaign{campaign}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return summaries[0], nil
+}
+
+// ListCampaigns lista as campanhas da comunidade com o progresso de cada uma
+func (s *CampaignService) ListCampaigns(ctx context.Context, community *domain.Community, filter *repository.Filter) ([]*CampaignSummary, int64, error) {
+	campaigns, total, err := s.repos.Campaign.FindByCommunity(ctx, community.ID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar campanhas: %v", err)
+	}
+
+	summaries, err := s.summarize(ctx, community, campaigns, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	return summaries, total, nil
+}
+
+// UpdateCampaign altera os dados da campanha. Uma campanha concluída volta a ficar
+// ativa quando a nova meta ou a nova data final ainda não foram alcançadas.
+func (s *CampaignService) UpdateCampaign(ctx context.Context, community *domain.Community, campaign *domain.Campaign) (*CampaignSummary, error) {
+	if campaign.Status == "cancelled" {
+		return nil, ErrCampaignCancelled
+	}
+	if err := validateCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	totals, err := s.repos.Donation.SumByCampaigns(ctx, community.ID, []string{campaign.ID})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao somar doações da campanha: %v", err)
+	}
+	location := communityLocation(community)
+
+	progress := campaignProgress(campaign, totals[campaign.ID], location, now)
+	if campaign.Status == "completed" && !campaignFinished(campaign, progress, location, now) {
+		campaign.Status = "active"
+		campaign.ClosedAt = nil
+		progress = campaignProgress(campaign, totals[campaign.ID], location, now)
+	}
+	campaign.UpdatedAt = now
+
+	if err := s.repos.Campaign.Update(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar campanha: %v", err)
+	}
+
+	if err := s.autoComplete(ctx, community, campaign, progress, now); err != nil {
+		return nil, err
+	}
+	return &CampaignSummary{Campaign: campaign, Progress: progress}, nil
+}
+
+// CloseCampaign encerra manualmente uma campanha ativa como concluída ou cancelada
+func (s *CampaignService) CloseCampaign(ctx context.Context, community *domain.Community, id, status string) (*CampaignSummary, error) {
+	if status != "completed" && status != "cancelled" {
+		return nil, ErrInvalidCampaignClose
+	}
+
+	campaign, err := s.findCampaign(ctx, community.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != "active" {
+		return nil, ErrCampaignNotActive
+	}
+
+	now := time.Now()
+	campaign.Status = status
+	campaign.ClosedAt = &now
+	campaign.UpdatedAt = now
+
+	closed, err := s.repos.Campaign.Close(ctx, campaign)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao encerrar campanha: %v", err)
+	}
+	if !closed {
+		return nil, ErrCampaignNotActive
+	}
+
+	totals, err := s.repos.Donation.SumByCampaigns(ctx, community.ID, []string{campaign.ID})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao somar doações da campanha: %v", err)
+	}
+	return &CampaignSummary{
+		Campaign: campaign,
+		Progress: campaignProgress(campaign, totals[campaign.ID], communityLocation(community), now),
+	}, nil
+}
+
+// CompleteDue encerra as campanhas ativas cuja data final passou ou cuja meta foi atingida
+func (s *CampaignService) CompleteDue(ctx context.Context, now time.Time) error {
+	campaigns, err := s.repos.Campaign.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar campanhas ativas: %v", err)
+	}
+
+	for _, campaign := range campaigns {
+		if campaign.Community == nil {
+			continue
+		}
+		if err := s.completeIfFinished(ctx, campaign, now); err != nil {
+			// Uma falha em uma campanha não impede o encerramento das demais
+			s.logger.Error("erro ao verificar encerramento da campanha",
+				zap.Error(err),
+				zap.String("campaign_id", campaign.ID))
+		}
+	}
+	return nil
+}
+
+// completeIfFinished conclui a campanha ativa cuja data final passou ou cuja meta foi atingida
+func (s *CampaignService) completeIfFinished(ctx context.Context, campaign *domain.Campaign, now time.Time) error {
+	totals, err := s.repos.Donation.SumByCampaigns(ctx, campaign.CommunityID, []string{campaign.ID})
+	if err != nil {
+		return fmt.Errorf("erro ao somar doações da campanha: %v", err)
+	}
+	progress := campaignProgress(campaign, totals[campaign.ID], communityLocation(campaign.Community), now)
+	return s.autoComplete(ctx, campaign.Community, campaign, progress, now)
+}
+
+// Run verifica periodicamente o encerramento das campanhas até o contexto ser cancelado
+func (s *CampaignService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Com mais de uma instância do servidor, apenas uma executa a rodada
+		if _, err := s.repos.RunExclusive(ctx, "campaigns", func(ctx context.Context) error {
+			if err := s.CompleteDue(ctx, time.Now()); err != nil {
+				s.logger.Error("erro ao encerrar campanhas", zap.Error(err))
+			}
+			return nil
+		}); err != nil {
+			s.logger.Error("erro ao obter lock das campanhas", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublicCampaigns lista as campanhas ativas e concluídas da comunidade identificada pelo slug
+func (s *CampaignService) PublicCampaigns(ctx context.Context, slug string) (*PublicCommunity, []*PublicCampaign, error) {
+	community, err := s.publicCommunity(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := &repository.Filter{Page: 1, PerPage: 100, OrderBy: "start_date", OrderDir: "desc"}
+	filter.AddCondition("status IN ?", []string{"active", "completed"})
+	campaigns, _, err := s.repos.Campaign.FindByCommunity(ctx, community.ID, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar campanhas: %v", err)
+	}
+
+	summaries, err := s.summarize(ctx, community, campaigns, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	public := make([]*PublicCampaign, 0, len(summaries))
+	for _, summary := range summaries {
+		public = append(public, publicCampaign(summary))
+	}
+	return publicCommunity(community), public, nil
+}
+
+// PublicCampaign retorna uma campanha da comunidade identificada pelo slug
+func (s *CampaignService) PublicCampaign(ctx context.Context, slug, id string) (*PublicCommunity, *PublicCampaign, error) {
+	community, err := s.publicCommunity(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := s.GetCampaign(ctx, community, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if summary.Campaign.Status == "cancelled" {
+		return nil, nil, ErrCampaignNotFound
+	}
+	return publicCommunity(community), publicCampaign(summary), nil
+}
+
+func (s *CampaignService) publicCommunity(ctx context.Context, slug string) (*domain.Community, error) {
+	community, err := s.repos.Community.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil || community.Status != "active" {
+		return nil, ErrCommunityNotFound
+	}
+	return community, nil
+}
+
+func (s *CampaignService) findCampaign(ctx context.Context, communityID, id string) (*domain.Campaign, error) {
+	campaign, err := s.repos.Campaign.FindByID(ctx, communityID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar campanha: %v", err)
+	}
+	return campaign, nil
+}
+
+// summarize calcula o progresso das campanhas. As consultas não alteram a situação das
+// campanhas; a conclusão automática fica a cargo de Run.
+func (s *CampaignService) summarize(ctx context.Context, community *domain.Community, campaigns []*domain.Campaign, now time.Time) ([]*CampaignSummary, error) {
+	ids := make([]string, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+
+	totals, err := s.repos.Donation.SumByCampaigns(ctx, community.ID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao somar doações das campanhas: %v", err)
+	}
+
+	location := communityLocation(community)
+	summaries := make([]*CampaignSummary, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		progress := campaignProgress(campaign, totals[campaign.ID], location, now)
+		summaries = append(summaries, &CampaignSummary{Campaign: campaign, Progress: progress})
+	}
+	return summaries, nil
+}
+
+// autoComplete marca a campanha ativa como concluída quando a data final passou ou a meta foi atingida
+func (s *CampaignService) autoComplete(ctx context.Context, community *domain.Community, campaign *domain.Campaign, progress *CampaignProgress, now time.Time) error {
+	if campaign.Status != "active" || !campaignFinished(campaign, progress, communityLocation(community), now) {
+		return nil
+	}
+
+	campaign.Status = "completed"
+	campaign.ClosedAt = &now
+	campaign.UpdatedAt = now
+
+	closed, err := s.repos.Campaign.Close(ctx, campaign)
+	if err != nil {
+		return fmt.Errorf("erro ao concluir campanha: %v", err)
+	}
+	if closed {
+		s.logger.Info("campanha concluída automaticamente",
+			zap.String("campaign_id", campaign.ID),
+			zap.Bool("goal_reached", progress.GoalReached))
+	}
+	return nil
+}
+
+func validateCampaign(campaign *domain.Campaign) error {
+	if campaign.Goal <= 0 {
+		return ErrInvalidCampaignGoal
+	}
+	if campaign.EndDate != nil && campaign.EndDate.Before(campaign.StartDate) {
+		return ErrInvalidCampaignEnd
+	}
+	return nil
+}
+
+// campaignFinished indica se a meta foi atingida ou se o último dia da campanha já passou
+func campaignFinished(campaign *domain.Campaign, progress *CampaignProgress, location *time.Location, now time.Time) bool {
+	if progress.GoalReached {
+		return true
+	}
+	if campaign.EndDate == nil {
+		return false
+	}
+	return startOfDay(now, location).After(calendarDate(*campaign.EndDate, location))
+}
+
+func campaignProgress(campaign *domain.Campaign, totals *repository.CampaignTotals, location *time.Location, now time.Time) *CampaignProgress {
+	progress := &CampaignProgress{Goal: campaign.Goal}
+	if totals != nil {
+		progress.OneTimeRaised = math.Round(totals.OneTimeAmount*100) / 100
+		progress.RecurringRaised = math.Round(totals.RecurringAmount*100) / 100
+		progress.DonationCount = totals.DonationCount
+		progress.DonorCount = totals.DonorCount
+	}
+	progress.Raised = math.Round((progress.OneTimeRaised+progress.RecurringRaised)*100) / 100
+	progress.Remaining = math.Max(0, math.Round((campaign.Goal-progress.Raised)*100)/100)
+	if campaign.Goal > 0 {
+		progress.Percent = math.Round(progress.Raised/campaign.Goal*1000) / 10
+		progress.GoalReached = progress.Raised >= campaign.Goal
+	}
+
+	if campaign.EndDate != nil && campaign.Status == "active" {
+		today := startOfDay(now, location)
+		end := calendarDate(*campaign.EndDate, location)
+		daysLeft := 0
+		if !today.After(end) {
+			daysLeft = int(math.Round(end.Sub(today).Hours()/24)) + 1
+		}
+		progress.DaysLeft = &daysLeft
+	}
+	return progress
+}
+
+func publicCampaign(summary *CampaignSummary) *PublicCampaign {
+	return &PublicCampaign{
+		ID:          summary.Campaign.ID,
+		Name:        summary.Campaign.Name,
+		Description: summary.Campaign.Description,
+		StartDate:   summary.Campaign.StartDate,
+		EndDate:     summary.Campaign.EndDate,
+		Status:      summary.Campaign.Status,
+		Goal:        summary.Progress.Goal,
+		Raised:      summary.Progress.Raised,
+		Percent:     summary.Progress.Percent,
+		DonorCount:  summary.Progress.DonorCount,
+		GoalReached: summary.Progress.GoalReached,
+		DaysLeft:    summary.Progress.DaysLeft,
+	}
+}
+
+func publicCommunity(community *domain.Community) *PublicCommunity {
+	return &PublicCommunity{
+		Name:   community.Name,
+		Slug:   community.Slug,
+		Logo:   community.Logo,
+		Banner: community.Banner,
+	}
+}
@@ -0,0 +1,826 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidCelebrationSettings = errors.New("configuração de datas comemorativas inválida")
+	ErrInvalidCelebrationPeriod   = errors.New("o período deve estar entre 1 e 31 dias")
+	ErrGroupNotFound              = errors.New("grupo não encontrado")
+)
+
+// maxCelebrationDays é o maior período aceito na consulta e no resumo
+const maxCelebrationDays = 31
+
+var celebrationKinds = []string{
+	domain.CelebrationBirthday,
+	domain.CelebrationWedding,
+	domain.CelebrationBaptism,
+	domain.CelebrationMembership,
+}
+
+// Celebration é uma data comemorativa de um membro no período consultado
+type Celebration struct {
+	Kind string `json:"kind"`
+	// Data da comemoração no período e data original
+	Date         time.Time `json:"date"`
+	OriginalDate time.Time `json:"original_date"`
+	// Anos completados na data (idade no aniversário)
+	Years int `json:"years"`
+	// Datas redondas (15 e 18 anos, décadas de vida, 1 ano e múltiplos de 5 das demais)
+	Milestone bool           `json:"milestone"`
+	Member    *domain.Member `json:"member"`
+	// Cônjuge, nos aniversários de casamento
+	Spouse *domain.Member `json:"spouse,omitempty"`
+}
+
+// CelebrationDigestResult resume um envio do resumo de datas comemorativas
+type CelebrationDigestResult struct {
+	Celebrations int `json:"celebrations"`
+	Recipients   int `json:"recipients"`
+}
+
+// CelebrationService lista os aniversários, aniversários de casamento, de batismo e de
+// membresia dos membros, envia o resumo semanal aos pastores e aos líderes de grupo e,
+// quando configurado, as felicitações automáticas por e-mail.
+type CelebrationService struct {
+	repos         *repository.Repositories
+	logger        *zap.Logger
+	communication CommunicationService
+}
+
+func NewCelebrationService(repos *repository.Repositories, logger *zap.Logger, communication CommunicationService) *CelebrationService {
+	return &CelebrationService{
+		repos:         repos,
+		logger:        logger,
+		communication: communication,
+	}
+}
+
+// GetSettings retorna a configuração da comunidade ou a configuração padrão
+func (s *CelebrationService) GetSettings(ctx context.Context, communityID string) (*domain.CelebrationSettings, error) {
+	settings, err := s.repos.Celebration.FindSettings(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar configuração de datas comemorativas: %v", err)
+	}
+	if settings == nil {
+		settings = &domain.CelebrationSettings{
+			CommunityID:        communityID,
+			DigestWeekday:      int(time.Monday),
+			DigestDays:         7,
+			NotifyGroupLeaders: true,
+		}
+	}
+	return settings, nil
+}
+
+// UpdateSettings altera a configuração do resumo semanal e das felicitações
+func (s *CelebrationService) UpdateSettings(ctx context.Context, input *domain.CelebrationSettings, updatedBy string) (*domain.CelebrationSettings, error) {
+	settings, err := s.GetSettings(ctx, input.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.DigestWeekday < 0 || input.DigestWeekday > 6 {
+		return nil, fmt.Errorf("%w: o dia da semana deve estar entre 0 (domingo) e 6 (sábado)", ErrInvalidCelebrationSettings)
+	}
+	if input.DigestDays < 1 || input.DigestDays > maxCelebrationDays {
+		return nil, fmt.Errorf("%w: o resumo deve incluir entre 1 e %d dias", ErrInvalidCelebrationSettings, maxCelebrationDays)
+	}
+	emails := splitEmails(input.DigestEmails)
+	for _, email := range emails {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, fmt.Errorf("%w: e-mail inválido: %s", ErrInvalidCelebrationSettings, email)
+		}
+	}
+
+	templates := map[string]*string{
+		domain.CelebrationBirthday:   input.BirthdayTemplateID,
+		domain.CelebrationWedding:    input.WeddingTemplateID,
+		domain.CelebrationBaptism:    input.BaptismTemplateID,
+		domain.CelebrationMembership: input.MembershipTemplateID,
+	}
+	for kind, templateID := range templates {
+		if templateID == nil || *templateID == "" {
+			templates[kind] = nil
+			continue
+		}
+		communicationTemplate, err := s.repos.Communication.FindTemplateByID(ctx, input.CommunityID, *templateID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar modelo de comunicação: %v", err)
+		}
+		if communicationTemplate == nil {
+			return nil, fmt.Errorf("%w: modelo de comunicação não encontrado: %s", ErrInvalidCelebrationSettings, *templateID)
+		}
+		if communicationTemplate.Type != domain.CommunicationTypeEmail {
+			return nil, fmt.Errorf("%w: o modelo %q não é de e-mail", ErrInvalidCelebrationSettings, communicationTemplate.Name)
+		}
+	}
+
+	settings.DigestEnabled = input.DigestEnabled
+	settings.DigestWeekday = input.DigestWeekday
+	settings.DigestDays = input.DigestDays
+	settings.NotifyGroupLeaders = input.NotifyGroupLeaders
+	settings.DigestEmails = strings.Join(uniqueEmails(emails), ", ")
+	settings.GreetingsEnabled = input.GreetingsEnabled
+	settings.BirthdayTemplateID = templates[domain.CelebrationBirthday]
+	settings.WeddingTemplateID = templates[domain.CelebrationWedding]
+	settings.BaptismTemplateID = templates[domain.CelebrationBaptism]
+	settings.MembershipTemplateID = templates[domain.CelebrationMembership]
+	settings.UpdatedBy = &updatedBy
+	settings.UpdatedAt = time.Now()
+	if settings.ID == "" {
+		settings.CreatedAt = time.Now()
+	}
+
+	if err := s.repos.Celebration.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("erro ao salvar configuração de datas comemorativas: %v", err)
+	}
+	return settings, nil
+}
+
+// Upcoming lista as datas comemorativas dos próximos dias a partir de from (hoje, no
+// fuso horário da comunidade, quando não informado). Com groupID, apenas os membros do
+// grupo; com kind, apenas um tipo de data.
+func (s *CelebrationService) Upcoming(ctx context.Context, communityID string, from time.Time, days int, kind, groupID string) ([]*Celebration, error) {
+	if days < 1 || days > maxCelebrationDays {
+		return nil, ErrInvalidCelebrationPeriod
+	}
+	if kind != "" && !containsString(celebrationKinds, kind) {
+		return nil, fmt.Errorf("%w: tipo aceita apenas %s", ErrInvalidCelebrationSettings, strings.Join(celebrationKinds, ", "))
+	}
+
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, ErrCommunityNotFound
+	}
+	if from.IsZero() {
+		from = dueDate(time.Now(), communityLocation(community), 0)
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+
+	celebrations, err := s.celebrations(ctx, communityID, from, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupID != "" {
+		group, err := s.repos.Group.FindByID(ctx, communityID, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar grupo: %v", err)
+		}
+		if group == nil {
+			return nil, ErrGroupNotFound
+		}
+		members, err := s.repos.Celebration.ListGroupMemberIDs(ctx, []string{groupID})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar membros do grupo: %v", err)
+		}
+		celebrations = filterCelebrations(celebrations, members[groupID])
+	}
+
+	if kind != "" {
+		filtered := celebrations[:0]
+		for _, celebration := range celebrations {
+			if celebration.Kind == kind {
+				filtered = append(filtered, celebration)
+			}
+		}
+		celebrations = filtered
+	}
+	return celebrations, nil
+}
+
+// celebrations calcula as datas comemorativas dos membros ativos no período
+func (s *CelebrationService) celebrations(ctx context.Context, communityID string, from time.Time, days int) ([]*Celebration, error) {
+	to := from.AddDate(0, 0, days-1)
+
+	var monthDays []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		monthDays = append(monthDays, day.Format("01-02"))
+		// Quem nasceu em 29 de fevereiro comemora em 28 de fevereiro nos anos não bissextos
+		if day.Month() == time.February && day.Day() == 28 && !isLeapYear(day.Year()) {
+			monthDays = append(monthDays, "02-29")
+		}
+	}
+
+	members, err := s.repos.Celebration.FindCelebrants(ctx, communityID, monthDays)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar datas comemorativas: %v", err)
+	}
+
+	var celebrations []*Celebration
+	couples := make(map[string]bool)
+	for _, member := range members {
+		dates := map[string]*time.Time{
+			domain.CelebrationBirthday:   &member.BirthDate,
+			domain.CelebrationWedding:    member.WeddingDate,
+			domain.CelebrationBaptism:    member.BaptismDate,
+			domain.CelebrationMembership: member.MembershipDate,
+		}
+		for _, kind := range celebrationKinds {
+			original := dates[kind]
+			if original == nil || original.IsZero() || original.Year() <= 1 {
+				continue
+			}
+			originalDate := time.Date(original.UTC().Year(), original.UTC().Month(), original.UTC().Day(), 0, 0, 0, 0, time.UTC)
+			date, ok := anniversaryBetween(originalDate, from, to)
+			if !ok {
+				continue
+			}
+			years := date.Year() - originalDate.Year()
+			if years < 1 {
+				continue
+			}
+
+			celebration := &Celebration{
+				Kind:         kind,
+				Date:         date,
+				OriginalDate: originalDate,
+				Years:        years,
+				Milestone:    isMilestone(kind, years),
+				Member:       member,
+			}
+			if kind == domain.CelebrationWedding && member.Spouse != nil {
+				// O casal aparece uma única vez
+				key := coupleKey(member.ID, member.Spouse.ID)
+				if couples[key] {
+					continue
+				}
+				couples[key] = true
+				celebration.Spouse = member.Spouse
+			}
+			celebrations = append(celebrations, celebration)
+		}
+	}
+
+	sort.SliceStable(celebrations, func(i, j int) bool {
+		if !celebrations[i].Date.Equal(celebrations[j].Date) {
+			return celebrations[i].Date.Before(celebrations[j].Date)
+		}
+		return celebrations[i].Member.Name < celebrations[j].Member.Name
+	})
+	return celebrations, nil
+}
+
+// SendDigest envia agora o resumo das datas comemorativas da comunidade
+func (s *CelebrationService) SendDigest(ctx context.Context, communityID string) (*CelebrationDigestResult, error) {
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, ErrCommunityNotFound
+	}
+	settings, err := s.GetSettings(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendDigest(ctx, community, settings, time.Now())
+}
+
+// SendDigests envia o resumo semanal das comunidades cujo dia de envio é hoje, no fuso
+// horário de cada comunidade
+func (s *CelebrationService) SendDigests(ctx context.Context, now time.Time) error {
+	settingsList, err := s.repos.Celebration.ListEnabledSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar configurações de datas comemorativas: %v", err)
+	}
+
+	for _, settings := range settingsList {
+		if !settings.DigestEnabled {
+			continue
+		}
+		community, err := s.repos.Community.FindByID(ctx, settings.CommunityID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar comunidade: %v", err)
+		}
+		if community == nil {
+			continue
+		}
+
+		location := communityLocation(community)
+		today := startOfDay(now, location)
+		if int(today.Weekday()) != settings.DigestWeekday {
+			continue
+		}
+		if settings.LastDigestAt != nil && !startOfDay(*settings.LastDigestAt, location).Before(today) {
+			continue
+		}
+
+		// O envio é registrado antes de enviar; outra execução que já o registrou hoje
+		// envia o resumo sozinha
+		previous := settings.LastDigestAt
+		claimed, err := s.repos.Celebration.ClaimDigest(ctx, settings.ID, today, now)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar envio do resumo: %v", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.sendDigest(ctx, community, settings, now); err != nil {
+			s.logger.Error("erro ao enviar resumo de datas comemorativas",
+				zap.Error(err),
+				zap.String("community_id", community.ID))
+			if err := s.repos.Celebration.ReleaseDigest(ctx, settings.ID, previous); err != nil {
+				s.logger.Error("erro ao liberar resumo não enviado", zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// sendDigest envia o resumo completo aos pastores, administradores e e-mails
+// configurados e, a cada líder de grupo, o resumo com os membros dos seus grupos
+func (s *CelebrationService) sendDigest(ctx context.Context, community *domain.Community, settings *domain.CelebrationSettings, now time.Time) (*CelebrationDigestResult, error) {
+	from := dueDate(now, communityLocation(community), 0)
+	celebrations, err := s.celebrations(ctx, community.ID, from, settings.DigestDays)
+	if err != nil {
+		return nil, err
+	}
+	result := &CelebrationDigestResult{Celebrations: len(celebrations)}
+
+	// Destinatários do resumo completo
+	recipients := splitEmails(settings.DigestEmails)
+	owner, err := s.repos.User.FindByID(ctx, community.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar responsável pela comunidade: %v", err)
+	}
+	if owner != nil && owner.Email != "" {
+		recipients = append(recipients, owner.Email)
+	}
+	pastors, err := s.repos.Celebration.ListPastors(ctx, community.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar pastores: %v", err)
+	}
+	for _, pastor := range pastors {
+		recipients = append(recipients, pastor.Email)
+	}
+	recipients = uniqueEmails(recipients)
+
+	var messages []PersonalizedMessage
+	recipientID := "celebrations-" + from.Format("2006-01-02")
+	if len(celebrations) > 0 {
+		subject, body, err := celebrationDigest(community, []celebrationDigestSection{{Celebrations: celebrations}}, from, settings.DigestDays)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range recipients {
+			messages = append(messages, PersonalizedMessage{
+				RecipientType: domain.RecipientTypeCustom,
+				RecipientID:   recipientID,
+				Email:         email,
+				Subject:       subject,
+				Body:          body,
+			})
+		}
+	}
+
+	// Resumo dos líderes, apenas com os membros dos seus grupos
+	if settings.NotifyGroupLeaders && len(celebrations) > 0 {
+		leaderMessages, err := s.leaderDigests(ctx, community, celebrations, recipients, from, settings.DigestDays)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, leaderMessages...)
+	}
+
+	if len(messages) > 0 {
+		communication := &domain.Communication{
+			Type:          domain.CommunicationTypeEmail,
+			Subject:       messages[0].Subject,
+			Content:       messages[0].Body,
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   recipientID,
+			CreatedBy:     community.CreatedBy,
+		}
+		if err := s.communication.SendPersonalizedCommunication(ctx, community.ID, communication, messages); err != nil {
+			return nil, fmt.Errorf("erro ao enviar resumo de datas comemorativas: %v", err)
+		}
+	}
+	result.Recipients = len(messages)
+
+	if settings.ID != "" {
+		if err := s.repos.Celebration.MarkDigestSent(ctx, settings.ID, now); err != nil {
+			return nil, fmt.Errorf("erro ao registrar envio do resumo: %v", err)
+		}
+		settings.LastDigestAt = &now
+	}
+	return result, nil
+}
+
+// leaderDigests monta um resumo por líder, com uma seção para cada grupo que lidera.
+// Quem já recebe o resumo completo não recebe o resumo do grupo.
+func (s *CelebrationService) leaderDigests(ctx context.Context, community *domain.Community, celebrations []*Celebration, skip []string, from time.Time, days int) ([]PersonalizedMessage, error) {
+	groups, err := s.repos.Celebration.ListLeaderGroups(ctx, community.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar grupos: %v", err)
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	groupIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+	groupMembers, err := s.repos.Celebration.ListGroupMemberIDs(ctx, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros dos grupos: %v", err)
+	}
+
+	skipped := make(map[string]bool, len(skip))
+	for _, email := range skip {
+		skipped[strings.ToLower(email)] = true
+	}
+
+	sections := make(map[string][]celebrationDigestSection)
+	var leaders []string
+	for _, group := range groups {
+		groupCelebrations := filterCelebrations(celebrations, groupMembers[group.ID])
+		if len(groupCelebrations) == 0 {
+			continue
+		}
+		for _, leader := range []*domain.Member{group.Leader, group.CoLeader} {
+			if leader == nil || leader.Email == "" || leader.Status != "active" {
+				continue
+			}
+			email := strings.ToLower(leader.Email)
+			if skipped[email] {
+				continue
+			}
+			if _, ok := sections[email]; !ok {
+				leaders = append(leaders, leader.Email)
+			}
+			sections[email] = append(sections[email], celebrationDigestSection{
+				Group:        group.Name,
+				Celebrations: groupCelebrations,
+			})
+		}
+	}
+
+	recipientID := "celebrations-" + from.Format("2006-01-02")
+	messages := make([]PersonalizedMessage, 0, len(leaders))
+	for _, email := range leaders {
+		subject, body, err := celebrationDigest(community, sections[strings.ToLower(email)], from, days)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, PersonalizedMessage{
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   recipientID,
+			Email:         email,
+			Subject:       subject,
+			Body:          body,
+		})
+	}
+	return messages, nil
+}
+
+// SendGreetings envia as felicitações do dia aos membros das comunidades com
+// felicitações ativas. Cada membro recebe no máximo uma mensagem por data e por ano.
+func (s *CelebrationService) SendGreetings(ctx context.Context, now time.Time) error {
+	settingsList, err := s.repos.Celebration.ListEnabledSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar configurações de datas comemorativas: %v", err)
+	}
+
+	for _, settings := range settingsList {
+		if !settings.GreetingsEnabled {
+			continue
+		}
+		community, err := s.repos.Community.FindByID(ctx, settings.CommunityID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar comunidade: %v", err)
+		}
+		if community == nil {
+			continue
+		}
+		if err := s.sendGreetings(ctx, community, settings, now); err != nil {
+			s.logger.Error("erro ao enviar felicitações",
+				zap.Error(err),
+				zap.String("community_id", community.ID))
+		}
+	}
+	return nil
+}
+
+func (s *CelebrationService) sendGreetings(ctx context.Context, community *domain.Community, settings *domain.CelebrationSettings, now time.Time) error {
+	today := dueDate(now, communityLocation(community), 0)
+	celebrations, err := s.celebrations(ctx, community.ID, today, 1)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range celebrationKinds {
+		templateID := settings.TemplateID(kind)
+		if templateID == nil {
+			continue
+		}
+
+		var greetings []*domain.CelebrationGreeting
+		var messages []PersonalizedMessage
+		var communicationTemplate *domain.CommunicationTemplate
+		for _, celebration := range celebrations {
+			if celebration.Kind != kind {
+				continue
+			}
+			if communicationTemplate == nil {
+				communicationTemplate, err = s.repos.Communication.FindTemplateByID(ctx, community.ID, *templateID)
+				if err != nil {
+					return fmt.Errorf("erro ao buscar modelo de comunicação: %v", err)
+				}
+				if communicationTemplate == nil {
+					s.logger.Warn("modelo de felicitação não encontrado",
+						zap.String("community_id", community.ID),
+						zap.String("template_id", *templateID))
+					break
+				}
+			}
+
+			// No aniversário de casamento, cada cônjuge recebe a sua mensagem
+			recipients := []*domain.Member{celebration.Member}
+			if celebration.Spouse != nil {
+				recipients = append(recipients, celebration.Spouse)
+			}
+			for i, member := range recipients {
+				if member.Email == "" || !member.NotifyByEmail || member.Status != "active" {
+					continue
+				}
+				greeting := &domain.CelebrationGreeting{
+					CommunityID: community.ID,
+					MemberID:    member.ID,
+					Kind:        kind,
+					Year:        today.Year(),
+					SentAt:      time.Now(),
+				}
+				created, err := s.repos.Celebration.CreateGreeting(ctx, greeting)
+				if err != nil {
+					return fmt.Errorf("erro ao registrar felicitação: %v", err)
+				}
+				if !created {
+					continue
+				}
+				greetings = append(greetings, greeting)
+
+				var spouse *domain.Member
+				if len(recipients) > 1 {
+					spouse = recipients[1-i]
+				}
+				values := greetingValues(community, celebration, member, spouse)
+				messages = append(messages, PersonalizedMessage{
+					RecipientType: domain.RecipientTypeMember,
+					RecipientID:   member.ID,
+					Email:         member.Email,
+					Subject:       fillGreeting(communicationTemplate.Subject, values, false),
+					Body:          fillGreeting(communicationTemplate.Content, values, true),
+				})
+			}
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		communication := &domain.Communication{
+			Type:          domain.CommunicationTypeEmail,
+			Subject:       communicationTemplate.Subject,
+			Content:       communicationTemplate.Content,
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   kind + "-" + today.Format("2006-01-02"),
+			CreatedBy:     community.CreatedBy,
+		}
+		if err := s.communication.SendPersonalizedCommunication(ctx, community.ID, communication, messages); err != nil {
+			// Libera as felicitações para uma nova tentativa
+			for _, greeting := range greetings {
+				if err := s.repos.Celebration.DeleteGreeting(ctx, greeting.ID); err != nil {
+					s.logger.Error("erro ao remover registro de felicitação", zap.Error(err))
+				}
+			}
+			return fmt.Errorf("erro ao enviar felicitações: %v", err)
+		}
+	}
+	return nil
+}
+
+// Run envia as felicitações e os resumos semanais periodicamente, até o contexto ser
+// cancelado
+func (s *CelebrationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Com mais de uma instância do servidor, apenas uma executa a rodada
+		if _, err := s.repos.RunExclusive(ctx, "celebrations", func(ctx context.Context) error {
+			now := time.Now()
+			if err := s.SendGreetings(ctx, now); err != nil {
+				s.logger.Error("erro ao enviar felicitações", zap.Error(err))
+			}
+			if err := s.SendDigests(ctx, now); err != nil {
+				s.logger.Error("erro ao enviar resumos de datas comemorativas", zap.Error(err))
+			}
+			return nil
+		}); err != nil {
+			s.logger.Error("erro ao obter lock das datas comemorativas", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CelebrationLabel retorna o nome da data comemorativa
+func CelebrationLabel(kind string) string {
+	switch kind {
+	case domain.CelebrationBirthday:
+		return "Aniversário"
+	case domain.CelebrationWedding:
+		return "Aniversário de casamento"
+	case domain.CelebrationBaptism:
+		return "Aniversário de batismo"
+	case domain.CelebrationMembership:
+		return "Aniversário de membresia"
+	}
+	return kind
+}
+
+type celebrationDigestSection struct {
+	Group        string
+	Celebrations []*Celebration
+}
+
+func celebrationDigest(community *domain.Community, sections []celebrationDigestSection, from time.Time, days int) (string, string, error) {
+	type digestItem struct {
+		Date      string
+		Name      string
+		Label     string
+		Years     string
+		Milestone bool
+		Today     bool
+	}
+	type digestSection struct {
+		Group string
+		Items []digestItem
+	}
+
+	to := from.AddDate(0, 0, days-1)
+	data := make([]digestSection, 0, len(sections))
+	for _, section := range sections {
+		items := make([]digestItem, 0, len(section.Celebrations))
+		for _, celebration := range section.Celebrations {
+			name := celebration.Member.Name
+			if celebration.Spouse != nil {
+				name += " e " + celebration.Spouse.Name
+			}
+			years := strconv.Itoa(celebration.Years) + " anos"
+			if celebration.Years == 1 {
+				years = "1 ano"
+			}
+			items = append(items, digestItem{
+				Date:      celebration.Date.Format("02/01") + " (" + weekdayNames[celebration.Date.Weekday()] + ")",
+				Name:      name,
+				Label:     CelebrationLabel(celebration.Kind),
+				Years:     years,
+				Milestone: celebration.Milestone,
+				Today:     celebration.Date.Equal(from),
+			})
+		}
+		data = append(data, digestSection{Group: section.Group, Items: items})
+	}
+
+	subject := fmt.Sprintf("Datas comemorativas de %s a %s", from.Format("02/01"), to.Format("02/01"))
+	var body bytes.Buffer
+	if err := celebrationDigestTemplate.Execute(&body, map[string]interface{}{
+		"Community": community.Name,
+		"Period":    from.Format("02/01/2006") + " a " + to.Format("02/01/2006"),
+		"Sections":  data,
+	}); err != nil {
+		return "", "", fmt.Errorf("erro ao gerar resumo de datas comemorativas: %v", err)
+	}
+	return subject, body.String(), nil
+}
+
+// greetingValues são os valores das variáveis aceitas nos modelos de felicitação
+func greetingValues(community *domain.Community, celebration *Celebration, member, spouse *domain.Member) map[string]string {
+	values := map[string]string{
+		"nome":          member.Name,
+		"primeiro_nome": strings.Fields(member.Name + " ")[0],
+		"comunidade":    community.Name,
+		"anos":          strconv.Itoa(celebration.Years),
+		"data":          celebration.Date.Format("02/01"),
+		"ocasiao":       strings.ToLower(CelebrationLabel(celebration.Kind)),
+		"conjuge":       "",
+	}
+	if spouse != nil {
+		values["conjuge"] = spouse.Name
+	}
+	return values
+}
+
+var greetingVariable = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// fillGreeting substitui as variáveis {{nome}}, {{primeiro_nome}}, {{conjuge}},
+// {{comunidade}}, {{anos}}, {{data}} e {{ocasiao}} do modelo. Variáveis desconhecidas
+// são mantidas.
+func fillGreeting(text string, values map[string]string, escape bool) string {
+	return greetingVariable.ReplaceAllStringFunc(text, func(match string) string {
+		value, ok := values[greetingVariable.FindStringSubmatch(match)[1]]
+		if !ok {
+			return match
+		}
+		if escape {
+			return html.EscapeString(value)
+		}
+		return value
+	})
+}
+
+// filterCelebrations mantém apenas as datas dos membros informados (nos aniversários de
+// casamento, basta um dos cônjuges)
+func filterCelebrations(celebrations []*Celebration, memberIDs []string) []*Celebration {
+	members := make(map[string]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+	var filtered []*Celebration
+	for _, celebration := range celebrations {
+		if members[celebration.Member.ID] || (celebration.Spouse != nil && members[celebration.Spouse.ID]) {
+			filtered = append(filtered, celebration)
+		}
+	}
+	return filtered
+}
+
+// anniversaryBetween retorna a data em que original é comemorada no período
+func anniversaryBetween(original, from, to time.Time) (time.Time, bool) {
+	for year := from.Year(); year <= to.Year(); year++ {
+		day := original.Day()
+		if original.Month() == time.February && day == 29 && !isLeapYear(year) {
+			day = 28
+		}
+		date := time.Date(year, original.Month(), day, 0, 0, 0, 0, time.UTC)
+		if !date.Before(from) && !date.After(to) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func isMilestone(kind string, years int) bool {
+	if kind == domain.CelebrationBirthday {
+		return years == 15 || years == 18 || years%10 == 0
+	}
+	return years == 1 || years%5 == 0
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func coupleKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+var weekdayNames = []string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+var celebrationDigestTemplate = template.Must(template.New("celebration-digest").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="font-family: Arial, Helvetica, sans-serif; color: #222;">
+<h2>{{.Community}}</h2>
+<p>Datas comemorativas de {{.Period}}:</p>
+{{range .Sections}}
+{{if .Group}}<h3>{{.Group}}</h3>{{end}}
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr style="background: #f0f0f0;"><th align="left">Data</th><th align="left">Nome</th><th align="left">Ocasião</th><th align="left"></th></tr>
+{{range .Items}}
+<tr><td>{{.Date}}{{if .Today}} (hoje){{end}}</td><td>{{if .Milestone}}<strong>{{.Name}}</strong>{{else}}{{.Name}}{{end}}</td><td>{{.Label}}</td><td>{{.Years}}{{if .Milestone}} &#9733;{{end}}</td></tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+)
+
+type fakeCelebrationRepository struct {
+	repository.CelebrationRepository
+	members []*domain.Member
+	days    []string
+}
+
+func (r *fakeCelebrationRepository) FindCelebrants(ctx context.Context, communityID string, days []string) ([]*domain.Member, error) {
+	r.days = days
+	return r.members, nil
+}
+
+func TestAnniversaryBetween(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		original time.Time
+		from, to time.Time
+		want     time.Time
+		wantOK   bool
+	}{
+		{"no período", date(1990, 5, 20), date(2026, 5, 18), date(2026, 5, 24), date(2026, 5, 20), true},
+		{"primeiro dia do período", date(1990, 5, 18), date(2026, 5, 18), date(2026, 5, 24), date(2026, 5, 18), true},
+		{"fora do período", date(1990, 5, 25), date(2026, 5, 18), date(2026, 5, 24), time.Time{}, false},
+		{"período na virada do ano", date(1990, 1, 2), date(2026, 12, 28), date(2027, 1, 3), date(2027, 1, 2), true},
+		{"29 de fevereiro em ano não bissexto", date(2000, 2, 29), date(2026, 2, 25), date(2026, 3, 3), date(2026, 2, 28), true},
+		{"29 de fevereiro em ano bissexto", date(2000, 2, 29), date(2028, 2, 28), date(2028, 2, 28), time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := anniversaryBetween(tt.original, tt.from, tt.to)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("anniversaryBetween = %s, %v; esperado %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsMilestone(t *testing.T) {
+	tests := []struct {
+		kind  string
+		years int
+		want  bool
+	}{
+		{domain.CelebrationBirthday, 15, true},
+		{domain.CelebrationBirthday, 18, true},
+		{domain.CelebrationBirthday, 40, true},
+		{domain.CelebrationBirthday, 25, false},
+		{domain.CelebrationBirthday, 1, false},
+		{domain.CelebrationWedding, 1, true},
+		{domain.CelebrationWedding, 25, true},
+		{domain.CelebrationBaptism, 12, false},
+		{domain.CelebrationMembership, 10, true},
+	}
+
+	for _, tt := range tests {
+		if got := isMilestone(tt.kind, tt.years); got != tt.want {
+			t.Errorf("isMilestone(%q, %d) = %v; esperado %v", tt.kind, tt.years, got, tt.want)
+		}
+	}
+}
+
+func TestCelebrations(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	wedding := time.Date(2016, 2, 27, 0, 0, 0, 0, time.UTC)
+	// Gravado com horário: 22h em São Paulo já é o dia seguinte em UTC
+	baptism := time.Date(2020, 2, 26, 22, 0, 0, 0, saoPaulo)
+	membership := time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC)
+
+	ana := &domain.Member{ID: "m1", Name: "Ana Souza", BirthDate: time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC), WeddingDate: &wedding}
+	bruno := &domain.Member{ID: "m2", Name: "Bruno Souza", BirthDate: time.Date(1990, 6, 1, 0, 0, 0, 0, time.UTC), WeddingDate: &wedding, BaptismDate: &baptism, MembershipDate: &membership}
+	ana.Spouse, bruno.Spouse = bruno, ana
+
+	repo := &fakeCelebrationRepository{members: []*domain.Member{ana, bruno}}
+	s := &CelebrationService{repos: &repository.Repositories{Celebration: repo}}
+
+	from := time.Date(2026, 2, 26, 0, 0, 0, 0, time.UTC)
+	celebrations, err := s.celebrations(context.Background(), "c1", from, 3)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	// 29 de fevereiro é buscado junto com 28 de fevereiro nos anos não bissextos
+	if want := []string{"02-26", "02-27", "02-28", "02-29"}; !reflect.DeepEqual(repo.days, want) {
+		t.Errorf("dias buscados = %q; esperado %q", repo.days, want)
+	}
+
+	type result struct {
+		kind   string
+		member string
+		date   string
+		years  int
+		spouse bool
+	}
+	var got []result
+	for _, celebration := range celebrations {
+		got = append(got, result{
+			kind:   celebration.Kind,
+			member: celebration.Member.ID,
+			date:   celebration.Date.Format("2006-01-02"),
+			years:  celebration.Years,
+			spouse: celebration.Spouse != nil,
+		})
+	}
+	// O casamento aparece uma vez por casal e a membresia deste ano ainda não completou um ano
+	want := []result{
+		{domain.CelebrationWedding, "m1", "2026-02-27", 10, true},
+		{domain.CelebrationBaptism, "m2", "2026-02-27", 6, false},
+		{domain.CelebrationBirthday, "m1", "2026-02-28", 26, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("datas = %+v; esperado %+v", got, want)
+	}
+}
+
+func TestFillGreeting(t *testing.T) {
+	values := map[string]string{"nome": "Ana & Bruno", "anos": "10"}
+
+	tests := []struct {
+		name   string
+		text   string
+		escape bool
+		want   string
+	}{
+		{"substitui variáveis com espaços", "Parabéns, {{ nome }}! {{anos}} anos", false, "Parabéns, Ana & Bruno! 10 anos"},
+		{"escapa o HTML no corpo", "<p>{{nome}}</p>", true, "<p>Ana &amp; Bruno</p>"},
+		{"mantém variáveis desconhecidas", "{{desconhecida}} {{anos}}", false, "{{desconhecida}} 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fillGreeting(tt.text, values, tt.escape); got != tt.want {
+				t.Errorf("fillGreeting = %q; esperado %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -57,26 +57,37 @@ func (s *checkInService) CreateCheckIn(ctx context.Context, request *domain.Chec
 		}
 	}
 
+	// A rota é pública: o check-in de visitante não é associado a nenhum membro e fica
+	// pendente até um administrador confirmá-lo no caminho de integração
+	memberID := request.MemberID
+	visitorStatus := ""
+	if request.IsVisitor {
+		memberID = nil
+		visitorStatus = domain.CheckInVisitorPending
+	}
+
 	checkIn := &domain.CheckIn{
-		EventID:   request.EventID,
-		MemberID:  request.MemberID,
-		IsVisitor: request.IsVisitor,
-		Name:      request.Name,
-		Email:     request.Email,
-		Phone:     request.Phone,
-		City:      request.City,
-		District:  request.District,
-		Source:    request.Source,
-		Consent:   request.Consent,
-		CheckInAt: time.Now(),
+		EventID:       request.EventID,
+		MemberID:      memberID,
+		IsVisitor:     request.IsVisitor,
+		Name:          request.Name,
+		Email:         request.Email,
+		Phone:         request.Phone,
+		City:          request.City,
+		District:      request.District,
+		Source:        request.Source,
+		Consent:       request.Consent,
+		CheckInAt:     time.Now(),
+		VisitorStatus: visitorStatus,
 	}
 
 	if err := s.checkInRepo.Create(ctx, checkIn); err != nil {
 		return err
 	}
 
-	// Se houver membros da família, criar check-in para cada um
-	if len(request.FamilyIds) > 0 {
+	// Se houver membros da família, criar check-in para cada um. O visitante ainda não
+	// tem família cadastrada, e os IDs informados por ele não são aceitos.
+	if !request.IsVisitor && len(request.FamilyIds) > 0 {
 		for _, familyMemberID := range request.FamilyIds {
 			// Busca os dados do membro da família usando o communityID do evento
 			familyMember, err := s.memberRepo.FindByID(ctx, event.CommunityID, familyMemberID)
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+)
+
+type fakeCheckInRepository struct {
+	repository.CheckInRepository
+	checkIns []domain.CheckIn
+}
+
+func (r *fakeCheckInRepository) Create(ctx context.Context, checkIn *domain.CheckIn) error {
+	r.checkIns = append(r.checkIns, *checkIn)
+	return nil
+}
+
+func (r *fakeCheckInRepository) GetByEventID(ctx context.Context, eventID string) ([]domain.CheckIn, error) {
+	var checkIns []domain.CheckIn
+	for _, checkIn := range r.checkIns {
+		if checkIn.EventID == eventID {
+			checkIns = append(checkIns, checkIn)
+		}
+	}
+	return checkIns, nil
+}
+
+type fakeEventRepository struct {
+	repository.EventRepository
+	events map[string]*domain.Event
+}
+
+func (r *fakeEventRepository) FindPublicByID(ctx context.Context, eventID string) (*domain.Event, error) {
+	return r.events[eventID], nil
+}
+
+func TestCreateCheckIn(t *testing.T) {
+	memberID := "m1"
+	existing := domain.CheckIn{EventID: "e1", Name: "Ana", Email: "ana@email.com", Phone: "11911112222"}
+
+	tests := []struct {
+		name         string
+		request      domain.CheckInRequest
+		wantErr      error
+		wantCheckIns int
+		wantMemberID *string
+		wantVisitor  string
+	}{
+		{
+			name:         "visitante fica pendente sem membro",
+			request:      domain.CheckInRequest{EventID: "e1", IsVisitor: true, MemberID: &memberID, Name: "Bruno", Email: "bruno@email.com", Phone: "11933334444", FamilyIds: []string{"m2"}},
+			wantCheckIns: 1,
+			wantVisitor:  domain.CheckInVisitorPending,
+		},
+		{
+			name:         "membro mantém o cadastro informado",
+			request:      domain.CheckInRequest{EventID: "e1", MemberID: &memberID, Name: "Carla", Email: "carla@email.com", Phone: "11955556666"},
+			wantCheckIns: 1,
+			wantMemberID: &memberID,
+		},
+		{
+			name:    "email repetido no evento",
+			request: domain.CheckInRequest{EventID: "e1", IsVisitor: true, Name: "Ana", Email: "ana@email.com", Phone: "11900000000"},
+			wantErr: ErrDuplicateCheckIn,
+		},
+		{
+			name:    "telefone repetido no evento",
+			request: domain.CheckInRequest{EventID: "e1", IsVisitor: true, Name: "Ana", Email: "outra@email.com", Phone: "11911112222"},
+			wantErr: ErrDuplicateCheckIn,
+		},
+		{
+			name:    "evento inexistente",
+			request: domain.CheckInRequest{EventID: "e2", Name: "Ana", Email: "ana@email.com", Phone: "11911112222"},
+			wantErr: ErrEventNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkIns := &fakeCheckInRepository{checkIns: []domain.CheckIn{existing}}
+			members := &fakeMemberRepository{members: []*domain.Member{{ID: "m2", CommunityID: "c1", Name: "Filho"}}}
+			events := &fakeEventRepository{events: map[string]*domain.Event{"e1": {ID: "e1", CommunityID: "c1"}}}
+			s := NewCheckInService(checkIns, members, events)
+
+			err := s.CreateCheckIn(context.Background(), &tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro %v; esperado %v", err, tt.wantErr)
+			}
+			created := checkIns.checkIns[1:]
+			if len(created) != tt.wantCheckIns {
+				t.Fatalf("%d check-ins criados; esperado %d", len(created), tt.wantCheckIns)
+			}
+			if tt.wantCheckIns == 0 {
+				return
+			}
+			got := created[0]
+			if (got.MemberID == nil) != (tt.wantMemberID == nil) || (got.MemberID != nil && *got.MemberID != *tt.wantMemberID) {
+				t.Errorf("membro %v; esperado %v", got.MemberID, tt.wantMemberID)
+			}
+			if got.VisitorStatus != tt.wantVisitor {
+				t.Errorf("situação do visitante %q; esperado %q", got.VisitorStatus, tt.wantVisitor)
+			}
+			if len(members.created) > 0 {
+				t.Errorf("a rota pública não deveria cadastrar membros")
+			}
+		})
+	}
+}
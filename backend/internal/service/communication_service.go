@@ -8,6 +8,7 @@ import (
 
 	"github.com/comunidade/backend/internal/domain"
 	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/secret"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -19,6 +20,9 @@ type CommunicationService interface {
 	UpdateCommunication(ctx context.Context, communityID, communicationID string, communication *domain.Communication) error
 	DeleteCommunication(ctx context.Context, communityID, communicationID string) error
 	SendCommunication(ctx context.Context, communityID, communicationID string) error
+	SendPersonalizedCommunication(ctx context.Context, communityID string, communication *domain.Communication, messages []PersonalizedMessage) error
+	SendRecipientMessage(ctx context.Context, recipient *domain.CommunicationRecipient, message PersonalizedMessage) error
+	ListRecipients(ctx context.Context, communityID, communicationID string) ([]*domain.CommunicationRecipient, error)
 
 	CreateTemplate(ctx context.Context, communityID string, template *domain.CommunicationTemplate) error
 	GetTemplate(ctx context.Context, communityID, templateID string) (*domain.CommunicationTemplate, error)
@@ -30,6 +34,17 @@ type CommunicationService interface {
 	UpdateCommunicationSettings(ctx context.Context, communityID string, settings *domain.CommunicationSettings) error
 }
 
+// PersonalizedMessage é uma mensagem individual de um envio em massa, com
+// assunto, conteúdo e anexos próprios de cada destinatário
+type PersonalizedMessage struct {
+	RecipientType domain.RecipientType
+	RecipientID   string
+	Email         string
+	Subject       string
+	Body          string
+	Attachments   []EmailAttachment
+}
+
 type communicationService struct {
 	repos        *repository.Repositories
 	logger       *zap.Logger
@@ -163,6 +178,99 @@ func (s *communicationService) SendCommunication(ctx context.Context, communityI
 	return nil
 }
 
+// SendPersonalizedCommunication registra a comunicação e envia uma mensagem própria
+// para cada destinatário, acompanhando o status de cada envio individualmente
+func (s *communicationService) SendPersonalizedCommunication(ctx context.Context, communityID string, communication *domain.Communication, messages []PersonalizedMessage) error {
+	if communication.ID == "" {
+		if err := s.CreateCommunication(ctx, communityID, communication); err != nil {
+			return fmt.Errorf("erro ao criar comunicação: %v", err)
+		}
+	}
+
+	// Registra todos os destinatários antes do envio para permitir o acompanhamento
+	recipients := make([]*domain.CommunicationRecipient, 0, len(messages))
+	for _, message := range messages {
+		email := message.Email
+		recipient := &domain.CommunicationRecipient{
+			ID:              uuid.New().String(),
+			CommunicationID: communication.ID,
+			RecipientType:   message.RecipientType,
+			RecipientID:     message.RecipientID,
+			Email:           &email,
+			Status:          domain.CommunicationStatusPending,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if err := s.repos.Communication.CreateRecipient(ctx, recipient); err != nil {
+			return fmt.Errorf("erro ao registrar destinatário: %v", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	failed := 0
+	for i, message := range messages {
+		if err := s.SendRecipientMessage(ctx, recipients[i], message); err != nil {
+			failed++
+		}
+	}
+
+	// A comunicação só é marcada como falha quando nenhuma mensagem foi entregue
+	now := time.Now()
+	communication.Status = domain.CommunicationStatusSent
+	communication.SentAt = &now
+	if failed > 0 && failed == len(messages) {
+		communication.Status = domain.CommunicationStatusFailed
+	}
+	communication.UpdatedAt = now
+	if err := s.repos.Communication.Update(ctx, communication); err != nil {
+		return fmt.Errorf("erro ao atualizar status da comunicação: %v", err)
+	}
+
+	return nil
+}
+
+// SendRecipientMessage envia a mensagem a um destinatário já registrado na comunicação
+// e grava no destinatário o resultado do envio. Retorna o erro do envio do email.
+func (s *communicationService) SendRecipientMessage(ctx context.Context, recipient *domain.CommunicationRecipient, message PersonalizedMessage) error {
+	var err error
+	if message.Email == "" {
+		err = errors.New("destinatário sem email")
+	} else {
+		err = s.emailService.SendEmailWithAttachments(message.Email, message.Subject, message.Body, message.Attachments...)
+	}
+
+	now := time.Now()
+	email := message.Email
+	recipient.Email = &email
+	recipient.UpdatedAt = now
+	if err != nil {
+		errorMessage := err.Error()
+		recipient.Status = domain.CommunicationStatusFailed
+		recipient.ErrorMessage = &errorMessage
+		s.logger.Error("erro ao enviar mensagem personalizada",
+			zap.Error(err),
+			zap.String("communicationId", recipient.CommunicationID),
+			zap.String("recipientId", message.RecipientID))
+	} else {
+		recipient.Status = domain.CommunicationStatusSent
+		recipient.SentAt = &now
+		recipient.ErrorMessage = nil
+	}
+
+	if updateErr := s.repos.Communication.UpdateRecipient(ctx, recipient); updateErr != nil {
+		s.logger.Error("erro ao atualizar destinatário", zap.Error(updateErr), zap.String("id", recipient.ID))
+	}
+	return err
+}
+
+// ListRecipients lista os destinatários de uma comunicação com o status de cada envio
+func (s *communicationService) ListRecipients(ctx context.Context, communityID, communicationID string) ([]*domain.CommunicationRecipient, error) {
+	if _, err := s.GetCommunication(ctx, communityID, communicationID); err != nil {
+		return nil, err
+	}
+	return s.repos.Communication.ListRecipients(ctx, communicationID)
+}
+
 func (s *communicationService) CreateTemplate(ctx context.Context, communityID string, template *domain.CommunicationTemplate) error {
 	template.ID = uuid.New().String()
 	template.CommunityID = communityID
@@ -233,6 +341,11 @@ func (s *communicationService) UpdateCommunicationSettings(ctx context.Context,
 		return s.repos.Communication.CreateSettings(ctx, settings)
 	}
 
+	// Os segredos mascarados devolvidos pela consulta mantêm os valores atuais
+	settings.EmailPassword = secret.Keep(existing.EmailPassword, settings.EmailPassword)
+	settings.SMSApiKey = secret.Keep(existing.SMSApiKey, settings.SMSApiKey)
+	settings.WhatsAppApiKey = secret.Keep(existing.WhatsAppApiKey, settings.WhatsAppApiKey)
+
 	return s.repos.Communication.UpdateSettings(ctx, settings)
 }
 
@@ -314,6 +427,33 @@ func (s *communicationService) getRecipients(ctx context.Context, communityID st
 				UpdatedAt:       time.Now(),
 			})
 		}
+
+	case domain.RecipientTypeSegment:
+		segment, err := s.repos.MemberSegment.FindByID(ctx, communityID, communication.RecipientID)
+		if err != nil {
+			return nil, err
+		}
+		if segment == nil {
+			return nil, errors.New("segment not found")
+		}
+
+		// Os membros do segmento são buscados no momento do envio
+		members, err := s.repos.Member.SearchAll(ctx, communityID, &segment.Criteria)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			recipients = append(recipients, &domain.CommunicationRecipient{
+				ID:              uuid.New().String(),
+				CommunicationID: communication.ID,
+				RecipientType:   domain.RecipientTypeSegment,
+				RecipientID:     member.ID,
+				Email:           &member.Email,
+				Status:          domain.CommunicationStatusPending,
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+			})
+		}
 	}
 
 	return recipients, nil
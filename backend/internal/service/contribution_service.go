@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBatchNotFound        = errors.New("lote de contribuições não encontrado")
+	ErrBatchLocked          = errors.New("o lote não está aberto para alterações")
+	ErrBatchNotCounted      = errors.New("o lote não está aguardando conferência")
+	ErrBatchEmpty           = errors.New("o lote não possui contribuições")
+	ErrBatchTotalMismatch   = errors.New("o valor contado não confere com o total do lote")
+	ErrSameVerifier         = errors.New("a conferência deve ser feita por uma pessoa diferente de quem contou o lote")
+	ErrContributionNotFound = errors.New("contribuição não encontrada")
+	ErrInvalidCategory      = errors.New("a categoria deve ser uma categoria de receita da comunidade")
+	ErrMemberNotFound       = errors.New("membro não encontrado")
+)
+
+// ContributionService controla a contagem de ofertas em lotes: abertura, registro
+// dos envelopes, fechamento com dupla conferência e lançamento da receita.
+type ContributionService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewContributionService(repos *repository.Repositories, logger *zap.Logger) *ContributionService {
+	return &ContributionService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// OpenBatch abre um novo lote de contagem
+func (s *ContributionService) OpenBatch(ctx context.Context, batch *domain.ContributionBatch) error {
+	if err := s.validateCategory(ctx, batch.CommunityID, batch.CategoryID); err != nil {
+		return err
+	}
+
+	batch.Status = "open"
+	batch.Total = 0
+	batch.Count = 0
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+
+	if err := s.repos.Contribution.CreateBatch(ctx, batch); err != nil {
+		return fmt.Errorf("erro ao criar lote: %v", err)
+	}
+
+	return nil
+}
+
+// GetBatch retorna o lote com seus envelopes
+func (s *ContributionService) GetBatch(ctx context.Context, communityID, batchID string) (*domain.ContributionBatch, error) {
+	batch, err := s.findBatch(ctx, communityID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions, err := s.repos.Contribution.FindByBatch(ctx, batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contribuições do lote: %v", err)
+	}
+	batch.Contributions = make([]domain.Contribution, 0, len(contributions))
+	for _, contribution := range contributions {
+		batch.Contributions = append(batch.Contributions, *contribution)
+	}
+
+	return batch, nil
+}
+
+// RecordContribution registra um envelope em um lote aberto
+func (s *ContributionService) RecordContribution(ctx context.Context, communityID, batchID string, contribution *domain.Contribution) error {
+	batch, err := s.findBatch(ctx, communityID, batchID)
+	if err != nil {
+		return err
+	}
+	if !batch.IsOpen() {
+		return ErrBatchLocked
+	}
+
+	if err := s.validateMember(ctx, communityID, contribution.MemberID); err != nil {
+		return err
+	}
+
+	contribution.CommunityID = communityID
+	contribution.BatchID = batch.ID
+	contribution.Date = batch.Date
+	contribution.Status = "pending"
+	if contribution.Currency == "" {
+		contribution.Currency = "BRL"
+	}
+	contribution.CreatedAt = time.Now()
+	contribution.UpdatedAt = time.Now()
+
+	return s.mapBatchError(s.repos.Contribution.Create(ctx, contribution), "erro ao registrar contribuição")
+}
+
+// UpdateContribution altera um envelope de um lote aberto
+func (s *ContributionService) UpdateContribution(ctx context.Context, communityID, batchID string, contribution *domain.Contribution) error {
+	if contribution.BatchID != batchID {
+		return ErrContributionNotFound
+	}
+
+	if err := s.validateMember(ctx, communityID, contribution.MemberID); err != nil {
+		return err
+	}
+
+	contribution.UpdatedAt = time.Now()
+	return s.mapBatchError(s.repos.Contribution.Update(ctx, contribution), "erro ao atualizar contribuição")
+}
+
+// FindContribution busca um envelope de um lote
+func (s *ContributionService) FindContribution(ctx context.Context, communityID, batchID, contributionID string) (*domain.Contribution, error) {
+	contribution, err := s.repos.Contribution.FindByID(ctx, communityID, contributionID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contribuição: %v", err)
+	}
+	if contribution == nil || contribution.BatchID != batchID {
+		return nil, ErrContributionNotFound
+	}
+	return contribution, nil
+}
+
+// DeleteContribution remove um envelope de um lote aberto
+func (s *ContributionService) DeleteContribution(ctx context.Context, communityID, batchID, contributionID string) error {
+	if _, err := s.FindContribution(ctx, communityID, batchID, contributionID); err != nil {
+		return err
+	}
+	return s.mapBatchError(s.repos.Contribution.Delete(ctx, communityID, contributionID), "erro ao excluir contribuição")
+}
+
+// SubmitBatch encerra a contagem: o lote deixa de aceitar envelopes e aguarda a
+// conferência de uma segunda pessoa
+func (s *ContributionService) SubmitBatch(ctx context.Context, communityID, batchID, userID string, countedTotal float64) (*domain.ContributionBatch, error) {
+	batch, err := s.findBatch(ctx, communityID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.IsOpen() {
+		return nil, ErrBatchLocked
+	}
+	if batch.Count == 0 {
+		return nil, ErrBatchEmpty
+	}
+	if !sameAmount(batch.Total, countedTotal) {
+		return nil, ErrBatchTotalMismatch
+	}
+
+	now := time.Now()
+	batch.Status = "counted"
+	batch.CountedBy = &userID
+	batch.CountedAt = &now
+	batch.UpdatedAt = now
+
+	if err := s.repos.Contribution.UpdateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("erro ao fechar lote: %v", err)
+	}
+
+	return batch, nil
+}
+
+// ReopenBatch devolve para contagem um lote que não passou na conferência
+func (s *ContributionService) ReopenBatch(ctx context.Context, communityID, batchID string) (*domain.ContributionBatch, error) {
+	batch, err := s.findBatch(ctx, communityID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.IsCounted() {
+		return nil, ErrBatchNotCounted
+	}
+
+	batch.Status = "open"
+	batch.CountedBy = nil
+	batch.CountedAt = nil
+	batch.UpdatedAt = time.Now()
+
+	if err := s.repos.Contribution.UpdateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("erro ao reabrir lote: %v", err)
+	}
+
+	return batch, nil
+}
+
+// VerifyBatch confere o lote por uma segunda pessoa, trava o lote e lança as receitas por forma de pagamento
+func (s *ContributionService) VerifyBatch(ctx context.Context, communityID, batchID, userID string, countedTotal float64) (*domain.ContributionBatch, error) {
+	batch, err := s.findBatch(ctx, communityID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.IsCounted() {
+		return nil, ErrBatchNotCounted
+	}
+	if batch.CountedBy != nil && *batch.CountedBy == userID {
+		return nil, ErrSameVerifier
+	}
+	if !sameAmount(batch.Total, countedTotal) {
+		return nil, ErrBatchTotalMismatch
+	}
+
+	// O valor e a forma de pagamento de cada receita vêm dos envelopes do lote, agrupados
+	// por forma de pagamento ao travá-lo
+	now := time.Now()
+	batchID = batch.ID
+	revenue := &domain.Revenue{
+		CommunityID:         communityID,
+		UserID:              userID,
+		CategoryID:          batch.CategoryID,
+		EventID:             batch.EventID,
+		Date:                batch.Date,
+		Description:         s.describe(batch),
+		Status:              "received",
+		ReceivedAt:          &now,
+		ContributionBatchID: &batchID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	batch.Status = "closed"
+	batch.VerifiedBy = &userID
+	batch.ClosedAt = &now
+	batch.UpdatedAt = now
+
+	if err := s.repos.Contribution.CloseBatch(ctx, batch, revenue); err != nil {
+		if err == repository.ErrBatchNotCounted {
+			return nil, ErrBatchNotCounted
+		}
+		return nil, fmt.Errorf("erro ao conferir lote: %v", err)
+	}
+
+	return batch, nil
+}
+
+func (s *ContributionService) findBatch(ctx context.Context, communityID, batchID string) (*domain.ContributionBatch, error) {
+	batch, err := s.repos.Contribution.FindBatchByID(ctx, communityID, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar lote: %v", err)
+	}
+	if batch == nil {
+		return nil, ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+func (s *ContributionService) validateCategory(ctx context.Context, communityID, categoryID string) error {
+	category, err := s.repos.FinancialCategory.FindByID(ctx, communityID, categoryID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar categoria: %v", err)
+	}
+	if category == nil || category.Type != "revenue" {
+		return ErrInvalidCategory
+	}
+	return nil
+}
+
+func (s *ContributionService) validateMember(ctx context.Context, communityID string, memberID *string) error {
+	if memberID == nil || *memberID == "" {
+		return nil
+	}
+	member, err := s.repos.Member.FindByID(ctx, communityID, *memberID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (s *ContributionService) mapBatchError(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	if err == repository.ErrBatchNotOpen {
+		return ErrBatchLocked
+	}
+	return fmt.Errorf("%s: %v", message, err)
+}
+
+func (s *ContributionService) describe(batch *domain.ContributionBatch) string {
+	description := fmt.Sprintf("Lote de contribuições %s", batch.Date.Format("02/01/2006"))
+	if batch.Description != "" {
+		description += " - " + batch.Description
+	}
+	return description
+}
+
+// sameAmount compara dois valores monetários em centavos
+func sameAmount(a, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// DonationPostingService lança no financeiro as doações pagas como receitas e
+// estorna esses lançamentos quando a doação é cancelada ou reembolsada.
+type DonationPostingService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewDonationPostingService(repos *repository.Repositories, logger *zap.Logger) *DonationPostingService {
+	return &DonationPostingService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// SyncDonation ajusta a receita vinculada de acordo com o status atual da doação.
+// Pode ser chamado várias vezes para a mesma doação sem gerar lançamentos duplicados.
+func (s *DonationPostingService) SyncDonation(ctx context.Context, donation *domain.Donation) error {
+	if donation.Status == "paid" {
+		return s.PostDonation(ctx, donation)
+	}
+	return s.ReverseDonation(ctx, donation)
+}
+
+// PostDonation cria (ou reativa) a receita correspondente a uma doação paga. A receita
+// tem o valor líquido dos reembolsos parciais.
+func (s *DonationPostingService) PostDonation(ctx context.Context, donation *domain.Donation) error {
+	revenue, err := s.repos.Revenue.FindByDonationID(ctx, donation.CommunityID, donation.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar receita da doação: %v", err)
+	}
+
+	receivedAt := time.Now()
+	if donation.PaidAt != nil {
+		receivedAt = *donation.PaidAt
+	}
+
+	// A doação já foi lançada: apenas garante que o lançamento esteja ativo
+	if revenue != nil {
+		if revenue.Status == "received" && revenue.Amount == donation.NetAmount() {
+			return nil
+		}
+		revenue.Status = "received"
+		revenue.Amount = donation.NetAmount()
+		revenue.ReceivedAt = &receivedAt
+		revenue.UpdatedAt = time.Now()
+		if err := s.repos.Revenue.Update(ctx, revenue); err != nil {
+			return fmt.Errorf("erro ao atualizar receita da doação: %v", err)
+		}
+		return nil
+	}
+
+	categoryID, err := s.resolveCategory(ctx, donation)
+	if err != nil {
+		return err
+	}
+	if categoryID == "" {
+		// A doação fica pendente de lançamento e é lançada por PostUnposted quando a
+		// comunidade cadastrar uma regra de lançamento
+		s.logger.Warn("nenhuma categoria configurada para lançamento de doações",
+			zap.String("community_id", donation.CommunityID),
+			zap.String("donation_id", donation.ID),
+		)
+		return nil
+	}
+
+	donationID := donation.ID
+	revenue = &domain.Revenue{
+		CommunityID: donation.CommunityID,
+		UserID:      donation.UserID,
+		CategoryID:  categoryID,
+		Amount:      donation.NetAmount(),
+		Date:        receivedAt,
+		Description: s.describe(donation),
+		Status:      "received",
+		PaymentType: donation.PaymentMethod,
+		ReceivedAt:  &receivedAt,
+		DonationID:  &donationID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Um lançamento concorrente da mesma doação é ignorado pela restrição única de donation_id
+	if _, err := s.repos.Revenue.CreateForDonation(ctx, revenue); err != nil {
+		return fmt.Errorf("erro ao criar receita da doação: %v", err)
+	}
+
+	return nil
+}
+
+// PostUnposted lança as doações pagas que ficaram sem receita por falta de regra de
+// lançamento. Retorna a quantidade de doações lançadas.
+func (s *DonationPostingService) PostUnposted(ctx context.Context, communityID string) (int, error) {
+	donations, err := s.repos.Donation.ListUnposted(ctx, communityID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar doações sem lançamento: %v", err)
+	}
+
+	for _, donation := range donations {
+		if err := s.PostDonation(ctx, donation); err != nil {
+			return 0, err
+		}
+	}
+
+	// Doações sem regra que se aplique continuam pendentes
+	remaining, err := s.UnpostedCount(ctx, communityID)
+	if err != nil {
+		return 0, err
+	}
+	return len(donations) - remaining, nil
+}
+
+// UnpostedCount retorna a quantidade de doações pagas ainda sem receita lançada
+func (s *DonationPostingService) UnpostedCount(ctx context.Context, communityID string) (int, error) {
+	donations, err := s.repos.Donation.ListUnposted(ctx, communityID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar doações sem lançamento: %v", err)
+	}
+	return len(donations), nil
+}
+
+// SaveDonation grava a doação e ajusta a receita vinculada na mesma transação
+func (s *DonationPostingService) SaveDonation(ctx context.Context, donation *domain.Donation) error {
+	return s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Donation.Update(ctx, donation); err != nil {
+			return fmt.Errorf("erro ao atualizar doação: %v", err)
+		}
+		return NewDonationPostingService(repos, s.logger).SyncDonation(ctx, donation)
+	})
+}
+
+// DeleteDonation estorna a receita vinculada e exclui a doação na mesma transação
+func (s *DonationPostingService) DeleteDonation(ctx context.Context, donation *domain.Donation) error {
+	return s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		if err := NewDonationPostingService(repos, s.logger).ReverseDonation(ctx, donation); err != nil {
+			return err
+		}
+		if err := repos.Donation.Delete(ctx, donation.CommunityID, donation.ID); err != nil {
+			return fmt.Errorf("erro ao excluir doação: %v", err)
+		}
+		return nil
+	})
+}
+
+// ReverseDonation cancela a receita lançada para a doação, se existir. Também é usado
+// para doações reembolsadas e contestadas.
+func (s *DonationPostingService) ReverseDonation(ctx context.Context, donation *domain.Donation) error {
+	revenue, err := s.repos.Revenue.FindByDonationID(ctx, donation.CommunityID, donation.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar receita da doação: %v", err)
+	}
+	if revenue == nil || revenue.Status == "cancelled" {
+		return nil
+	}
+
+	revenue.Status = "cancelled"
+	revenue.UpdatedAt = time.Now()
+	if err := s.repos.Revenue.Update(ctx, revenue); err != nil {
+		return fmt.Errorf("erro ao estornar receita da doação: %v", err)
+	}
+
+	return nil
+}
+
+// resolveCategory escolhe a categoria pela regra da campanha, depois pela do
+// método de pagamento e, por fim, pela regra padrão da comunidade
+func (s *DonationPostingService) resolveCategory(ctx context.Context, donation *domain.Donation) (string, error) {
+	rules, err := s.repos.DonationPostingRule.List(ctx, donation.CommunityID)
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar regras de lançamento: %v", err)
+	}
+
+	var byMethod, fallback string
+	for _, rule := range rules {
+		switch {
+		case rule.CampaignID != nil && *rule.CampaignID != "":
+			if donation.CampaignID != "" && *rule.CampaignID == donation.CampaignID {
+				return rule.CategoryID, nil
+			}
+		case rule.PaymentMethod != "":
+			if rule.PaymentMethod == donation.PaymentMethod && byMethod == "" {
+				byMethod = rule.CategoryID
+			}
+		default:
+			if fallback == "" {
+				fallback = rule.CategoryID
+			}
+		}
+	}
+
+	if byMethod != "" {
+		return byMethod, nil
+	}
+	return fallback, nil
+}
+
+func (s *DonationPostingService) describe(donation *domain.Donation) string {
+	if donation.Description != "" {
+		return fmt.Sprintf("Doação: %s - %s", donation.CustomerName, donation.Description)
+	}
+	return fmt.Sprintf("Doação: %s", donation.CustomerName)
+}
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"os"
 	"sync"
 
@@ -26,9 +31,17 @@ type smtpConfig struct {
 }
 
 type emailJob struct {
-	to      string
-	subject string
-	body    string
+	to          string
+	subject     string
+	body        string
+	attachments []EmailAttachment
+}
+
+// EmailAttachment representa um arquivo anexado ao email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
 }
 
 func NewEmailService(logger *zap.Logger) *EmailService {
@@ -104,11 +117,23 @@ func (s *EmailService) SendEmail(to, subject, body string) error {
 	return s.sendEmail(job)
 }
 
+// SendEmailWithAttachments envia um email HTML com arquivos anexados
+func (s *EmailService) SendEmailWithAttachments(to, subject, body string, attachments ...EmailAttachment) error {
+	job := emailJob{
+		to:          to,
+		subject:     subject,
+		body:        body,
+		attachments: attachments,
+	}
+	return s.sendEmail(job)
+}
+
 func (s *EmailService) sendEmail(job emailJob) error {
 	s.logger.Info("Preparando para enviar email",
 		zap.String("to", job.to),
 		zap.String("subject", job.subject),
-		zap.String("body", job.body))
+		zap.String("body", job.body),
+		zap.Int("attachments", len(job.attachments)))
 
 	// Montar o email
 	mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
@@ -117,6 +142,14 @@ func (s *EmailService) sendEmail(job emailJob) error {
 	subject := fmt.Sprintf("Subject: %s\n", job.subject)
 	msg := []byte(from + to + subject + mime + "\n" + job.body)
 
+	if len(job.attachments) > 0 {
+		body, contentType, err := buildMultipartBody(job)
+		if err != nil {
+			return fmt.Errorf("erro ao montar anexos: %v", err)
+		}
+		msg = append([]byte(from+to+subject+"MIME-version: 1.0\nContent-Type: "+contentType+"\n\n"), body...)
+	}
+
 	s.logger.Info("Configurações SMTP",
 		zap.String("host", s.smtpConfig.host),
 		zap.String("port", s.smtpConfig.port),
@@ -182,3 +215,53 @@ func (s *EmailService) sendEmail(job emailJob) error {
 
 	return nil
 }
+
+// buildMultipartBody monta o corpo multipart/mixed com o HTML e os anexos do email
+func buildMultipartBody(job emailJob) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	htmlHeader := textproto.MIMEHeader{}
+	htmlHeader.Set("Content-Type", "text/html; charset=\"UTF-8\"")
+	part, err := writer.CreatePart(htmlHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write([]byte(job.body)); err != nil {
+		return nil, "", err
+	}
+
+	for _, attachment := range job.attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Quebra o conteúdo em linhas de 76 caracteres, como exige o padrão MIME
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, "", err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), "multipart/mixed; boundary=" + writer.Boundary(), nil
+}
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrApprovalRuleNotFound       = errors.New("regra de aprovação não encontrada")
+	ErrInvalidApprovalRule        = errors.New("a etapa deve ser maior que zero e o valor mínimo não pode ser negativo")
+	ErrInvalidApprover            = errors.New("aprovador não encontrado")
+	ErrExpenseApprovalRequired    = errors.New("a despesa precisa ser aprovada antes de ser paga")
+	ErrExpenseNotAwaitingApproval = errors.New("a despesa não está aguardando aprovação")
+	ErrExpenseNotApproved         = errors.New("a despesa ainda não foi aprovada")
+	ErrNotExpenseApprover         = errors.New("você não é aprovador desta etapa da despesa")
+	ErrAlreadyApprovedStep        = errors.New("você já aprovou outra etapa desta despesa")
+	ErrSelfApproval               = errors.New("você não pode aprovar uma despesa que você mesmo solicitou")
+	ErrReceiptRequired            = errors.New("anexe o comprovante da despesa antes de aprová-la")
+	ErrRejectionReasonRequired    = errors.New("informe o motivo da rejeição")
+)
+
+// ExpenseApprovalService controla o fluxo de aprovação das despesas. As regras de
+// aprovação ativas cujo valor mínimo é atingido pela despesa (e cuja categoria, se
+// informada, coincide) formam as etapas que precisam ser aprovadas, em ordem, antes
+// do pagamento. Cada etapa é aprovada por um dos aprovadores da regra ou, quando a
+// regra não define aprovadores, pelo responsável pela comunidade.
+type ExpenseApprovalService struct {
+	repos         *repository.Repositories
+	logger        *zap.Logger
+	communication CommunicationService
+}
+
+func NewExpenseApprovalService(repos *repository.Repositories, logger *zap.Logger, communication CommunicationService) *ExpenseApprovalService {
+	return &ExpenseApprovalService{
+		repos:         repos,
+		logger:        logger,
+		communication: communication,
+	}
+}
+
+// CreateRule valida e registra uma regra de aprovação
+func (s *ExpenseApprovalService) CreateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error {
+	if err := s.validateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	if err := s.repos.ExpenseApproval.CreateRule(ctx, rule); err != nil {
+		return fmt.Errorf("erro ao criar regra de aprovação: %v", err)
+	}
+	return nil
+}
+
+// UpdateRule valida e atualiza uma regra de aprovação. As despesas que já aguardam
+// aprovação passam a seguir as regras vigentes na próxima decisão.
+func (s *ExpenseApprovalService) UpdateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error {
+	if err := s.validateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	rule.UpdatedAt = time.Now()
+	rule.Category = nil
+	if err := s.repos.ExpenseApproval.UpdateRule(ctx, rule); err != nil {
+		return fmt.Errorf("erro ao atualizar regra de aprovação: %v", err)
+	}
+	return nil
+}
+
+// GetRule retorna a regra de aprovação
+func (s *ExpenseApprovalService) GetRule(ctx context.Context, communityID, ruleID string) (*domain.ExpenseApprovalRule, error) {
+	rule, err := s.repos.ExpenseApproval.FindRuleByID(ctx, communityID, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar regra de aprovação: %v", err)
+	}
+	if rule == nil {
+		return nil, ErrApprovalRuleNotFound
+	}
+	return rule, nil
+}
+
+// ApplyStatus aplica à despesa a situação solicitada no cadastro (pending, paid ou
+// cancelled) respeitando o fluxo de aprovação. A despesa deve chegar com a situação
+// atual (vazia para uma despesa nova) e os demais dados já atualizados.
+//
+// Uma despesa em aberto que exige aprovação fica em requested até ser aprovada;
+// o pagamento só é aceito depois da aprovação. Se o valor passar do valor aprovado,
+// a aprovação é refeita.
+func (s *ExpenseApprovalService) ApplyStatus(ctx context.Context, community *domain.Community, expense *domain.Expense, status, userID string) error {
+	current := expense.Status
+
+	if status == "cancelled" {
+		expense.Status = "cancelled"
+		return nil
+	}
+
+	rules, err := s.matchingRules(ctx, expense)
+	if err != nil {
+		return err
+	}
+
+	// Um aumento de valor após a aprovação invalida a aprovação anterior
+	if expense.ApprovedAt != nil && current != "paid" && expense.Amount > expense.ApprovedAmount+0.005 {
+		clearApproval(expense)
+	}
+	approved := expense.ApprovedAt != nil
+
+	switch status {
+	case "paid":
+		if current == "paid" {
+			return nil
+		}
+		if len(rules) > 0 && !approved {
+			return ErrExpenseApprovalRequired
+		}
+		now := time.Now()
+		expense.Status = "paid"
+		if expense.PaidAt == nil {
+			expense.PaidAt = &now
+		}
+		expense.PaidBy = &userID
+		return nil
+
+	case "pending":
+		expense.PaidAt = nil
+		expense.PaidBy = nil
+		switch {
+		case approved:
+			expense.Status = "approved"
+		case len(rules) == 0:
+			expense.Status = "pending"
+			expense.ApprovalSteps = 0
+			expense.ApprovalStep = 0
+		case current == "requested":
+			// Continua na etapa em que estava; as etapas são recalculadas
+			expense.ApprovalSteps = len(rules)
+		default:
+			s.request(expense, len(rules))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("situação de despesa inválida: %s", status)
+}
+
+// NotifyRequested avisa os aprovadores da etapa atual de uma despesa que passou a
+// aguardar aprovação. Deve ser chamado depois que a despesa for gravada.
+func (s *ExpenseApprovalService) NotifyRequested(ctx context.Context, community *domain.Community, expense *domain.Expense) {
+	rules, err := s.matchingRules(ctx, expense)
+	if err != nil {
+		s.logger.Error("erro ao notificar aprovadores da despesa", zap.Error(err))
+		return
+	}
+	if expense.ApprovalStep < len(rules) {
+		s.notifyApprovers(ctx, community, expense, rules[expense.ApprovalStep])
+	}
+}
+
+// Approve registra a aprovação da etapa atual da despesa. Após a última etapa, a
+// despesa fica aprovada e pode ser paga.
+func (s *ExpenseApprovalService) Approve(ctx context.Context, community *domain.Community, expense *domain.Expense, userID, comment string) error {
+	if expense.Status != "requested" {
+		return ErrExpenseNotAwaitingApproval
+	}
+
+	// Quem solicitou a despesa não aprova o próprio gasto, nem como responsável pela comunidade
+	if expense.UserID == userID {
+		return ErrSelfApproval
+	}
+
+	rules, err := s.matchingRules(ctx, expense)
+	if err != nil {
+		return err
+	}
+
+	expectedStep := expense.ApprovalStep
+	var rule *domain.ExpenseApprovalRule
+	if expectedStep < len(rules) {
+		rule = rules[expectedStep]
+	}
+
+	// Sem regra para a etapa (as regras mudaram), a aprovação cabe ao responsável
+	if !isExpenseApprover(community, rule, userID) {
+		return ErrNotExpenseApprover
+	}
+
+	approvals, err := s.repos.ExpenseApproval.ListApprovals(ctx, expense.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar aprovações da despesa: %v", err)
+	}
+	for _, approval := range currentRound(expense, approvals) {
+		if approval.UserID == userID && approval.Decision == "approved" {
+			return ErrAlreadyApprovedStep
+		}
+	}
+
+	if rule != nil && rule.RequireReceipt {
+		count, err := s.repos.ExpenseApproval.CountReceipts(ctx, expense.ID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar comprovantes da despesa: %v", err)
+		}
+		if count == 0 {
+			return ErrReceiptRequired
+		}
+	}
+
+	approval := &domain.ExpenseApproval{
+		ExpenseID: expense.ID,
+		Step:      expectedStep + 1,
+		UserID:    userID,
+		Decision:  "approved",
+		Amount:    expense.Amount,
+		Comment:   strings.TrimSpace(comment),
+		CreatedAt: time.Now(),
+	}
+	if rule != nil {
+		approval.RuleID = &rule.ID
+	}
+
+	expense.ApprovalSteps = len(rules)
+	expense.ApprovalStep = expectedStep + 1
+	final := expense.ApprovalStep >= len(rules)
+	if final {
+		now := time.Now()
+		expense.Status = "approved"
+		expense.ApprovedBy = &userID
+		expense.ApprovedAt = &now
+		expense.ApprovedAmount = expense.Amount
+		expense.ApprovalSteps = expense.ApprovalStep
+	}
+
+	if err := s.repos.ExpenseApproval.RecordDecision(ctx, expense, approval, expectedStep); err != nil {
+		if err == repository.ErrEntryNotPending {
+			return ErrExpenseNotAwaitingApproval
+		}
+		return fmt.Errorf("erro ao registrar aprovação: %v", err)
+	}
+
+	if final {
+		s.notifyRequester(ctx, community, expense, "Despesa aprovada", "foi aprovada e pode ser paga", "")
+	} else {
+		s.notifyApprovers(ctx, community, expense, rules[expense.ApprovalStep])
+	}
+	return nil
+}
+
+// Reject rejeita a despesa na etapa atual. A despesa rejeitada pode ser corrigida e
+// reenviada para aprovação pelo cadastro, com a situação pending.
+func (s *ExpenseApprovalService) Reject(ctx context.Context, community *domain.Community, expense *domain.Expense, userID, reason string) error {
+	if expense.Status != "requested" {
+		return ErrExpenseNotAwaitingApproval
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrRejectionReasonRequired
+	}
+
+	rules, err := s.matchingRules(ctx, expense)
+	if err != nil {
+		return err
+	}
+
+	expectedStep := expense.ApprovalStep
+	var rule *domain.ExpenseApprovalRule
+	if expectedStep < len(rules) {
+		rule = rules[expectedStep]
+	}
+	if !isExpenseApprover(community, rule, userID) {
+		return ErrNotExpenseApprover
+	}
+
+	approval := &domain.ExpenseApproval{
+		ExpenseID: expense.ID,
+		Step:      expectedStep + 1,
+		UserID:    userID,
+		Decision:  "rejected",
+		Amount:    expense.Amount,
+		Comment:   reason,
+		CreatedAt: time.Now(),
+	}
+	if rule != nil {
+		approval.RuleID = &rule.ID
+	}
+
+	expense.Status = "rejected"
+	if err := s.repos.ExpenseApproval.RecordDecision(ctx, expense, approval, expectedStep); err != nil {
+		if err == repository.ErrEntryNotPending {
+			return ErrExpenseNotAwaitingApproval
+		}
+		return fmt.Errorf("erro ao registrar rejeição: %v", err)
+	}
+
+	s.notifyRequester(ctx, community, expense, "Despesa rejeitada", "foi rejeitada", reason)
+	return nil
+}
+
+// AwaitingApproval lista as despesas cuja etapa atual pode ser aprovada pelo usuário
+func (s *ExpenseApprovalService) AwaitingApproval(ctx context.Context, community *domain.Community, userID string) ([]*domain.Expense, error) {
+	expenses, err := s.repos.ExpenseApproval.ListAwaitingApproval(ctx, community.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar despesas aguardando aprovação: %v", err)
+	}
+	rules, err := s.repos.ExpenseApproval.FindActiveRules(ctx, community.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar regras de aprovação: %v", err)
+	}
+
+	awaiting := []*domain.Expense{}
+	for _, expense := range expenses {
+		matching := filterRules(rules, expense)
+		var rule *domain.ExpenseApprovalRule
+		if expense.ApprovalStep < len(matching) {
+			rule = matching[expense.ApprovalStep]
+		}
+		if isExpenseApprover(community, rule, userID) {
+			awaiting = append(awaiting, expense)
+		}
+	}
+	return awaiting, nil
+}
+
+// ListApprovals lista o histórico de decisões da despesa
+func (s *ExpenseApprovalService) ListApprovals(ctx context.Context, expenseID string) ([]*domain.ExpenseApproval, error) {
+	approvals, err := s.repos.ExpenseApproval.ListApprovals(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aprovações da despesa: %v", err)
+	}
+	return approvals, nil
+}
+
+// request inicia (ou reinicia) a aprovação da despesa na primeira etapa
+func (s *ExpenseApprovalService) request(expense *domain.Expense, steps int) {
+	now := time.Now()
+	clearApproval(expense)
+	expense.Status = "requested"
+	expense.ApprovalSteps = steps
+	expense.RequestedAt = &now
+}
+
+// matchingRules retorna as etapas de aprovação que se aplicam à despesa
+func (s *ExpenseApprovalService) matchingRules(ctx context.Context, expense *domain.Expense) ([]*domain.ExpenseApprovalRule, error) {
+	rules, err := s.repos.ExpenseApproval.FindActiveRules(ctx, expense.CommunityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar regras de aprovação: %v", err)
+	}
+	return filterRules(rules, expense), nil
+}
+
+// notifyApprovers avisa os aprovadores da etapa que a despesa aguarda aprovação.
+// Falhas no envio não impedem o andamento da aprovação.
+func (s *ExpenseApprovalService) notifyApprovers(ctx context.Context, community *domain.Community, expense *domain.Expense, rule *domain.ExpenseApprovalRule) {
+	approvers := []string(rule.ApproverIDs)
+	if len(approvers) == 0 {
+		approvers = []string{community.CreatedBy}
+	}
+
+	subject := fmt.Sprintf("Despesa aguardando aprovação: %s", expenseLabel(expense))
+	if err := s.notify(ctx, community, expense, approvers, subject, map[string]interface{}{
+		"Message": fmt.Sprintf("A despesa abaixo aguarda sua aprovação (etapa %d de %d: %s).", expense.ApprovalStep+1, expense.ApprovalSteps, rule.Name),
+	}); err != nil {
+		s.logger.Error("erro ao notificar aprovadores da despesa",
+			zap.Error(err),
+			zap.String("expense_id", expense.ID))
+	}
+}
+
+// notifyRequester avisa quem cadastrou a despesa sobre a decisão final
+func (s *ExpenseApprovalService) notifyRequester(ctx context.Context, community *domain.Community, expense *domain.Expense, subject, outcome, reason string) {
+	if err := s.notify(ctx, community, expense, []string{expense.UserID}, fmt.Sprintf("%s: %s", subject, expenseLabel(expense)), map[string]interface{}{
+		"Message": fmt.Sprintf("A despesa abaixo %s.", outcome),
+		"Reason":  reason,
+	}); err != nil {
+		s.logger.Error("erro ao notificar solicitante da despesa",
+			zap.Error(err),
+			zap.String("expense_id", expense.ID))
+	}
+}
+
+func (s *ExpenseApprovalService) notify(ctx context.Context, community *domain.Community, expense *domain.Expense, userIDs []string, subject string, data map[string]interface{}) error {
+	var recipients []string
+	for _, userID := range userIDs {
+		user, err := s.repos.User.FindByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar usuário: %v", err)
+		}
+		if user != nil && user.Email != "" {
+			recipients = append(recipients, user.Email)
+		}
+	}
+	recipients = uniqueEmails(recipients)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	data["Community"] = community.Name
+	data["Description"] = expense.Description
+	data["Amount"] = formatCurrency(expense.Amount)
+	data["DueDate"] = expense.DueDate.In(communityLocation(community)).Format("02/01/2006")
+
+	var body bytes.Buffer
+	if err := expenseApprovalTemplate.Execute(&body, data); err != nil {
+		return fmt.Errorf("erro ao gerar notificação de aprovação: %v", err)
+	}
+
+	messages := make([]PersonalizedMessage, 0, len(recipients))
+	for _, email := range recipients {
+		messages = append(messages, PersonalizedMessage{
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   "expense-" + expense.ID,
+			Email:         email,
+			Subject:       subject,
+			Body:          body.String(),
+		})
+	}
+
+	communication := &domain.Communication{
+		Type:          domain.CommunicationTypeEmail,
+		Subject:       subject,
+		Content:       body.String(),
+		RecipientType: domain.RecipientTypeCustom,
+		RecipientID:   "expense-" + expense.ID,
+		CreatedBy:     community.CreatedBy,
+	}
+	return s.communication.SendPersonalizedCommunication(ctx, community.ID, communication, messages)
+}
+
+func (s *ExpenseApprovalService) validateRule(ctx context.Context, rule *domain.ExpenseApprovalRule) error {
+	if rule.Step < 1 || rule.MinAmount < 0 {
+		return ErrInvalidApprovalRule
+	}
+
+	if rule.CategoryID != nil {
+		category, err := s.repos.FinancialCategory.FindByID(ctx, rule.CommunityID, *rule.CategoryID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar categoria: %v", err)
+		}
+		if category == nil || category.Type != "expense" {
+			return ErrInvalidExpenseCategory
+		}
+	}
+
+	seen := make(map[string]bool, len(rule.ApproverIDs))
+	approvers := rule.ApproverIDs[:0]
+	for _, userID := range rule.ApproverIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := s.repos.User.FindByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar aprovador: %v", err)
+		}
+		if user == nil {
+			return ErrInvalidApprover
+		}
+		approvers = append(approvers, userID)
+	}
+	rule.ApproverIDs = approvers
+	return nil
+}
+
+// filterRules seleciona, na ordem das etapas, as regras que se aplicam à despesa
+func filterRules(rules []*domain.ExpenseApprovalRule, expense *domain.Expense) []*domain.ExpenseApprovalRule {
+	var matching []*domain.ExpenseApprovalRule
+	for _, rule := range rules {
+		if expense.Amount+0.005 < rule.MinAmount {
+			continue
+		}
+		if rule.CategoryID != nil && *rule.CategoryID != expense.CategoryID {
+			continue
+		}
+		matching = append(matching, rule)
+	}
+	return matching
+}
+
+// isExpenseApprover indica se o usuário aprova a etapa. Sem aprovadores definidos
+// (ou sem regra para a etapa), a aprovação cabe ao responsável pela comunidade.
+func isExpenseApprover(community *domain.Community, rule *domain.ExpenseApprovalRule, userID string) bool {
+	if rule == nil || len(rule.ApproverIDs) == 0 {
+		return community.CreatedBy == userID
+	}
+	for _, approverID := range rule.ApproverIDs {
+		if approverID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// currentRound retorna as decisões registradas desde a última solicitação de aprovação
+func currentRound(expense *domain.Expense, approvals []*domain.ExpenseApproval) []*domain.ExpenseApproval {
+	if expense.RequestedAt == nil {
+		return approvals
+	}
+	var round []*domain.ExpenseApproval
+	for _, approval := range approvals {
+		if !approval.CreatedAt.Before(*expense.RequestedAt) {
+			round = append(round, approval)
+		}
+	}
+	return round
+}
+
+func clearApproval(expense *domain.Expense) {
+	expense.ApprovalStep = 0
+	expense.ApprovedBy = nil
+	expense.ApprovedAt = nil
+	expense.ApprovedAmount = 0
+}
+
+func expenseLabel(expense *domain.Expense) string {
+	if expense.Description != "" {
+		return fmt.Sprintf("%s (%s)", expense.Description, formatCurrency(expense.Amount))
+	}
+	return formatCurrency(expense.Amount)
+}
+
+var expenseApprovalTemplate = template.Must(template.New("expense-approval").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="font-family: Arial, Helvetica, sans-serif; color: #222;">
+<h2>{{.Community}}</h2>
+<p>{{.Message}}</p>
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr><td>Descrição</td><td>{{.Description}}</td></tr>
+<tr><td>Valor</td><td>{{.Amount}}</td></tr>
+<tr><td>Vencimento</td><td>{{.DueDate}}</td></tr>
+</table>
+{{if .Reason}}<p><strong>Motivo:</strong> {{.Reason}}</p>{{end}}
+</body>
+</html>
+`))
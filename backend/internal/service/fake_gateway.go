@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+// FakeGateway é um provedor de pagamentos em memória para desenvolvimento e testes.
+// Nenhuma cobrança sai do servidor: os pagamentos são simulados enviando eventos ao
+// webhook /webhooks/payments/fake. Só é registrado com PAYMENT_FAKE_GATEWAY=true.
+type FakeGateway struct {
+	mu            sync.Mutex
+	seq           int
+	customers     map[string]*PaymentCustomer
+	charges       map[string]*FakeCharge
+	subscriptions map[string]*FakeSubscription
+}
+
+// FakeCharge é uma cobrança guardada pelo provedor em memória
+type FakeCharge struct {
+	ID             string
+	CommunityID    string
+	SubscriptionID string
+	Amount         float64
+	Refunded       float64
+	Method         string
+	Status         string
+	DueDate        time.Time
+}
+
+// FakeSubscription é uma assinatura guardada pelo provedor em memória
+type FakeSubscription struct {
+	ID          string
+	CommunityID string
+	Amount      float64
+	Method      string
+	Status      string
+	NextDueDate time.Time
+	Card        *CardInfo
+}
+
+// FakeWebhookEvent é o corpo do webhook que simula um evento de cobrança. Com
+// subscription_id e sem charge_id, o evento paid gera uma nova cobrança da assinatura.
+type FakeWebhookEvent struct {
+	Event          string  `json:"event"`
+	ChargeID       string  `json:"charge_id"`
+	SubscriptionID string  `json:"subscription_id"`
+	Amount         float64 `json:"amount"`
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		customers:     make(map[string]*PaymentCustomer),
+		charges:       make(map[string]*FakeCharge),
+		subscriptions: make(map[string]*FakeSubscription),
+	}
+}
+
+// Name identifica o provedor em memória entre os provedores de pagamento
+func (g *FakeGateway) Name() string {
+	return GatewayFake
+}
+
+// Methods retorna todas as formas de pagamento
+func (g *FakeGateway) Methods() []string {
+	return []string{"pix", "boleto", "credit_card"}
+}
+
+// Configured está sempre configurado
+func (g *FakeGateway) Configured(ctx context.Context, communityID string) (bool, error) {
+	return true, nil
+}
+
+func (g *FakeGateway) CreateCustomer(ctx context.Context, communityID string, customer *PaymentCustomer) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.nextID("cus")
+	copied := *customer
+	g.customers[id] = &copied
+	return id, nil
+}
+
+func (g *FakeGateway) CreateCharge(ctx context.Context, donation *domain.Donation, customerID string) (*PaymentCharge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.nextID("pay")
+	g.charges[id] = &FakeCharge{
+		ID:          id,
+		CommunityID: donation.CommunityID,
+		Amount:      donation.Amount,
+		Method:      donation.PaymentMethod,
+		Status:      "pending",
+		DueDate:     donation.DueDate,
+	}
+	return &PaymentCharge{
+		ID:          id,
+		PaymentLink: "https://fake-gateway.local/pay/" + id,
+	}, nil
+}
+
+func (g *FakeGateway) UpdateCharge(ctx context.Context, donation *domain.Donation) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(donation.AsaasID)
+	if err != nil {
+		return err
+	}
+	charge.Amount = donation.Amount
+	charge.DueDate = donation.DueDate
+	return nil
+}
+
+func (g *FakeGateway) CancelCharge(ctx context.Context, donation *domain.Donation) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(donation.AsaasID)
+	if err != nil {
+		return err
+	}
+	charge.Status = "deleted"
+	return nil
+}
+
+func (g *FakeGateway) SendChargeLink(ctx context.Context, donation *domain.Donation) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, err := g.charge(donation.AsaasID)
+	return err
+}
+
+// PixQrCode gera um código copia e cola fictício com o valor da cobrança
+func (g *FakeGateway) PixQrCode(ctx context.Context, donation *domain.Donation) (*PixQrCode, error) {
+	return &PixQrCode{
+		Payload:        PixPayload("fake@comunidade.local", "COMUNIDADE", "SAO PAULO", donation.AsaasID, donation.Amount),
+		ExpirationDate: donation.DueDate.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+func (g *FakeGateway) RefundCharge(ctx context.Context, donation *domain.Donation, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, err := g.charge(donation.AsaasID)
+	if err != nil {
+		return err
+	}
+	if charge.Status != "paid" || amount <= 0 || charge.Refunded+amount > charge.Amount {
+		return fmt.Errorf("estorno inválido para a cobrança %s", charge.ID)
+	}
+	charge.Refunded += amount
+	if charge.Refunded >= charge.Amount {
+		charge.Status = "refunded"
+	}
+	return nil
+}
+
+func (g *FakeGateway) CreateSubscription(ctx context.Context, recurring *domain.RecurringDonation, customerID string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if recurring.NextDueDate == nil {
+		nextDueDate := NextRecurringDueDate(time.Now(), recurring.DueDay)
+		recurring.NextDueDate = &nextDueDate
+	}
+
+	id := g.nextID("sub")
+	g.subscriptions[id] = &FakeSubscription{
+		ID:          id,
+		CommunityID: recurring.CommunityID,
+		Amount:      recurring.Amount,
+		Method:      recurring.PaymentMethod,
+		Status:      "active",
+		NextDueDate: *recurring.NextDueDate,
+	}
+	return id, nil
+}
+
+func (g *FakeGateway) UpdateSubscription(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, err := g.subscription(recurring.AsaasID)
+	if err != nil {
+		return err
+	}
+	if update.Amount != nil {
+		subscription.Amount = *update.Amount
+	}
+	if update.NextDueDate != nil {
+		subscription.NextDueDate = *update.NextDueDate
+	}
+	if update.Status != "" {
+		subscription.Status = update.Status
+	}
+	return nil
+}
+
+func (g *FakeGateway) UpdateSubscriptionCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) (*CardInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, err := g.subscription(recurring.AsaasID)
+	if err != nil {
+		return nil, err
+	}
+	info := &CardInfo{Brand: "FAKE"}
+	if len(card.Number) >= 4 {
+		info.LastDigits = card.Number[len(card.Number)-4:]
+	}
+	subscription.Card = info
+	return info, nil
+}
+
+func (g *FakeGateway) CancelSubscription(ctx context.Context, recurring *domain.RecurringDonation) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, err := g.subscription(recurring.AsaasID)
+	if err != nil {
+		return err
+	}
+	subscription.Status = "cancelled"
+	return nil
+}
+
+// ParseWebhook aplica o evento simulado à cobrança em memória. A comunidade é a da
+// cobrança ou da assinatura informada.
+func (g *FakeGateway) ParseWebhook(ctx context.Context, header http.Header, body []byte) (*PaymentEvent, error) {
+	var event FakeWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, ErrInvalidPaymentEvent
+	}
+	switch event.Event {
+	case PaymentEventPaid, PaymentEventOverdue, PaymentEventRefunded, PaymentEventDeleted,
+		PaymentEventChargeback, PaymentEventChargebackReversed:
+	default:
+		return nil, ErrInvalidPaymentEvent
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Simula a cobrança mensal gerada pela assinatura
+	if event.ChargeID == "" && event.SubscriptionID != "" {
+		subscription, err := g.subscription(event.SubscriptionID)
+		if err != nil || subscription.Status != "active" {
+			return nil, ErrInvalidPaymentEvent
+		}
+		id := g.nextID("pay")
+		g.charges[id] = &FakeCharge{
+			ID:             id,
+			CommunityID:    subscription.CommunityID,
+			SubscriptionID: subscription.ID,
+			Amount:         subscription.Amount,
+			Method:         subscription.Method,
+			Status:         "pending",
+			DueDate:        subscription.NextDueDate,
+		}
+		event.ChargeID = id
+	}
+
+	charge, err := g.charge(event.ChargeID)
+	if err != nil {
+		return nil, ErrInvalidPaymentEvent
+	}
+	charge.Status = event.Event
+	if event.Event == PaymentEventChargebackReversed {
+		charge.Status = "paid"
+	}
+
+	amount := event.Amount
+	if amount == 0 {
+		amount = charge.Amount
+	}
+	result := &PaymentEvent{
+		CommunityID:    charge.CommunityID,
+		Event:          event.Event,
+		Type:           event.Event,
+		ChargeID:       charge.ID,
+		SubscriptionID: charge.SubscriptionID,
+		Amount:         amount,
+		Method:         charge.Method,
+		DueDate:        charge.DueDate,
+		PaymentLink:    "https://fake-gateway.local/pay/" + charge.ID,
+	}
+	if event.Event == PaymentEventPaid {
+		result.PaidAt = time.Now()
+	}
+	return result, nil
+}
+
+// Charge retorna uma cópia da cobrança guardada em memória
+func (g *FakeGateway) Charge(id string) (*FakeCharge, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *charge
+	return &copied, true
+}
+
+// Subscription retorna uma cópia da assinatura guardada em memória
+func (g *FakeGateway) Subscription(id string) (*FakeSubscription, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, ok := g.subscriptions[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *subscription
+	return &copied, true
+}
+
+func (g *FakeGateway) nextID(prefix string) string {
+	g.seq++
+	return fmt.Sprintf("fake_%s_%06d", prefix, g.seq)
+}
+
+func (g *FakeGateway) charge(id string) (*FakeCharge, error) {
+	charge, ok := g.charges[id]
+	if !ok {
+		return nil, fmt.Errorf("cobrança %s não encontrada no provedor em memória", id)
+	}
+	return charge, nil
+}
+
+func (g *FakeGateway) subscription(id string) (*FakeSubscription, error) {
+	subscription, ok := g.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("assinatura %s não encontrada no provedor em memória", id)
+	}
+	return subscription, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/validator"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrGivingUnavailable       = errors.New("a comunidade não está recebendo doações online")
+	ErrInvalidDonorCPF         = errors.New("CPF inválido")
+	ErrInvalidGivingAmount     = errors.New("valor da doação fora dos limites permitidos")
+	ErrInvalidGivingMethod     = errors.New("forma de pagamento inválida")
+	ErrPublicDonationNotFound  = errors.New("doação não encontrada")
+	ErrGivingPaymentProcessing = errors.New("não foi possível gerar a cobrança. Tente novamente")
+)
+
+// Limites de valor das doações feitas pela página pública
+const (
+	minPublicDonationAmount = 5.00
+	maxPublicDonationAmount = 50000.00
+)
+
+// givingMethods são as formas de pagamento oferecidas na página pública, quando aceitas
+// pelo provedor da comunidade. Cartão e boleto são pagos na fatura do provedor; para
+// Pix o QR Code é devolvido diretamente.
+var givingMethods = []string{"pix", "boleto", "credit_card"}
+
+// GivingService recebe as doações feitas pelos visitantes na página pública da
+// comunidade, sem autenticação.
+type GivingService struct {
+	repos     *repository.Repositories
+	logger    *zap.Logger
+	payments  *PaymentService
+	campaigns *CampaignService
+}
+
+func NewGivingService(repos *repository.Repositories, logger *zap.Logger, payments *PaymentService, campaigns *CampaignService) *GivingService {
+	return &GivingService{
+		repos:     repos,
+		logger:    logger,
+		payments:  payments,
+		campaigns: campaigns,
+	}
+}
+
+// GivingPage reúne os dados exibidos na página pública de doações
+type GivingPage struct {
+	Community *PublicCommunity  `json:"community"`
+	Campaigns []*PublicCampaign `json:"campaigns"`
+	Methods   []string          `json:"methods"`
+	MinAmount float64           `json:"min_amount"`
+	MaxAmount float64           `json:"max_amount"`
+	Enabled   bool              `json:"enabled"`
+}
+
+// PublicDonationInput são os dados informados pelo visitante
+type PublicDonationInput struct {
+	CampaignID    string
+	Amount        float64
+	PaymentMethod string
+	Name          string
+	CPF           string
+	Email         string
+	Phone         string
+}
+
+// PublicDonation é a doação devolvida ao visitante, sem os dados pessoais
+type PublicDonation struct {
+	ID            string     `json:"id"`
+	CampaignID    string     `json:"campaign_id"`
+	Amount        float64    `json:"amount"`
+	PaymentMethod string     `json:"payment_method"`
+	Status        string     `json:"status"`
+	DueDate       time.Time  `json:"due_date"`
+	PaymentLink   string     `json:"payment_link,omitempty"`
+	Pix           *PixQrCode `json:"pix,omitempty"`
+}
+
+// Page monta a página pública de doações da comunidade identificada pelo slug
+func (s *GivingService) Page(ctx context.Context, slug string) (*GivingPage, error) {
+	community, campaigns, err := s.campaigns.PublicCampaigns(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*PublicCampaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Status == "active" {
+			active = append(active, campaign)
+		}
+	}
+
+	methods, enabled, err := s.methods(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GivingPage{
+		Community: community,
+		Campaigns: active,
+		Methods:   methods,
+		MinAmount: minPublicDonationAmount,
+		MaxAmount: maxPublicDonationAmount,
+		Enabled:   enabled,
+	}, nil
+}
+
+// Donate cria a doação e a cobrança no provedor da comunidade. Para Pix, devolve também
+// o QR Code e o código copia e cola da cobrança criada.
+func (s *GivingService) Donate(ctx context.Context, slug string, input *PublicDonationInput) (*PublicDonation, error) {
+	community, err := s.campaigns.publicCommunity(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, enabled, err := s.methods(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrGivingUnavailable
+	}
+	if !containsMethod(methods, input.PaymentMethod) {
+		return nil, ErrInvalidGivingMethod
+	}
+	amount := math.Round(input.Amount*100) / 100
+	if amount < minPublicDonationAmount || amount > maxPublicDonationAmount {
+		return nil, ErrInvalidGivingAmount
+	}
+	cpf := onlyDigits(input.CPF)
+	if err := validator.ValidateCPF(cpf); err != nil {
+		return nil, ErrInvalidDonorCPF
+	}
+
+	campaign, err := s.campaigns.findCampaign(ctx, community.ID, input.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	location := communityLocation(community)
+	today := startOfDay(time.Now(), location)
+	if campaign.Status != "active" || calendarDate(campaign.StartDate, location).After(today) {
+		return nil, ErrCampaignNotActive
+	}
+
+	// O boleto recebe alguns dias para pagamento; Pix e cartão vencem no mesmo dia
+	dueDate := today
+	if input.PaymentMethod == "boleto" {
+		dueDate = today.AddDate(0, 0, 3)
+	}
+
+	donation := &domain.Donation{
+		CommunityID:   community.ID,
+		UserID:        community.CreatedBy,
+		CampaignID:    campaign.ID,
+		Amount:        amount,
+		PaymentMethod: input.PaymentMethod,
+		DueDate:       dueDate,
+		Description:   "Doação - " + campaign.Name,
+		Status:        "pending",
+		CustomerName:  strings.TrimSpace(input.Name),
+		CustomerCPF:   cpf,
+		CustomerEmail: strings.ToLower(strings.TrimSpace(input.Email)),
+		CustomerPhone: onlyDigits(input.Phone),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	// Vincula a doação ao membro quando o CPF já está cadastrado na comunidade
+	member, err := s.repos.Member.FindByCPF(ctx, community.ID, cpf)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member != nil {
+		donation.MemberID = &member.ID
+	}
+
+	// O ID é gerado antes da cobrança para ser enviado como referência externa
+	donation.ID = uuid.New().String()
+	if err := s.payments.CreateDonationCharge(ctx, donation); err != nil {
+		s.logger.Error("erro ao criar cobrança da doação", zap.Error(err), zap.String("community_id", community.ID))
+		return nil, ErrGivingPaymentProcessing
+	}
+
+	if err := s.repos.Donation.Create(ctx, donation); err != nil {
+		// Sem a doação gravada a cobrança ficaria em aberto no provedor sem registro local
+		if cancelErr := s.payments.CancelDonationCharge(ctx, donation); cancelErr != nil {
+			s.logger.Error("erro ao cancelar cobrança de doação não gravada",
+				zap.Error(cancelErr),
+				zap.String("community_id", community.ID),
+				zap.String("charge_id", donation.AsaasID),
+			)
+		}
+		return nil, fmt.Errorf("erro ao criar doação: %v", err)
+	}
+
+	result := publicDonation(donation)
+	if donation.PaymentMethod == "pix" {
+		// Sem o QR Code o visitante ainda pode pagar pelo link da fatura ou consultar a doação depois
+		pix, err := s.payments.DonationPixQrCode(ctx, donation)
+		if err != nil {
+			s.logger.Error("erro ao buscar QR Code Pix", zap.Error(err), zap.String("donation_id", donation.ID))
+		}
+		result.Pix = pix
+	}
+
+	return result, nil
+}
+
+// Donation retorna a situação de uma doação feita pela página pública. Enquanto a
+// cobrança Pix estiver pendente, o QR Code é devolvido novamente.
+func (s *GivingService) Donation(ctx context.Context, slug, id string) (*PublicDonation, error) {
+	community, err := s.campaigns.publicCommunity(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	donation, err := s.repos.Donation.FindByID(ctx, community.ID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPublicDonationNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar doação: %v", err)
+	}
+
+	result := publicDonation(donation)
+	if donation.PaymentMethod == "pix" && donation.Status == "pending" && donation.AsaasID != "" {
+		pix, err := s.payments.DonationPixQrCode(ctx, donation)
+		if err != nil {
+			s.logger.Error("erro ao buscar QR Code Pix", zap.Error(err), zap.String("donation_id", donation.ID))
+		}
+		result.Pix = pix
+	}
+	return result, nil
+}
+
+// methods retorna as formas de pagamento aceitas pelo provedor da comunidade e se o
+// provedor está configurado para receber as doações
+func (s *GivingService) methods(ctx context.Context, slug string) ([]string, bool, error) {
+	community, err := s.campaigns.publicCommunity(ctx, slug)
+	if err != nil {
+		return nil, false, err
+	}
+	gateway, err := s.payments.Gateway(ctx, community.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	enabled, err := gateway.Configured(ctx, community.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	methods := make([]string, 0, len(givingMethods))
+	for _, method := range givingMethods {
+		if containsMethod(gateway.Methods(), method) {
+			methods = append(methods, method)
+		}
+	}
+	return methods, enabled, nil
+}
+
+func publicDonation(donation *domain.Donation) *PublicDonation {
+	return &PublicDonation{
+		ID:            donation.ID,
+		CampaignID:    donation.CampaignID,
+		Amount:        donation.Amount,
+		PaymentMethod: donation.PaymentMethod,
+		Status:        donation.Status,
+		DueDate:       donation.DueDate,
+		PaymentLink:   donation.PaymentLink,
+	}
+}
@@ -0,0 +1,857 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/pdf"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoStatements = errors.New("nenhuma contribuição encontrada no período")
+)
+
+// Origens dos lançamentos do comprovante
+const (
+	StatementSourceDonation     = "donation"
+	StatementSourceRecurring    = "recurring"
+	StatementSourceContribution = "contribution"
+)
+
+// GivingStatementEntry é um lançamento do comprovante anual
+type GivingStatementEntry struct {
+	Date        time.Time `json:"date"`
+	Source      string    `json:"source"`
+	Description string    `json:"description"`
+	Method      string    `json:"method"`
+	Amount      float64   `json:"amount"`
+}
+
+// GivingStatement é o comprovante anual de contribuições de um membro
+type GivingStatement struct {
+	Year               int                    `json:"year"`
+	Member             *domain.Member         `json:"member"`
+	Entries            []GivingStatementEntry `json:"entries"`
+	TotalDonations     float64                `json:"total_donations"`
+	TotalRecurring     float64                `json:"total_recurring"`
+	TotalContributions float64                `json:"total_contributions"`
+	Total              float64                `json:"total"`
+	GeneratedAt        time.Time              `json:"generated_at"`
+}
+
+// GivingStatementService gera os comprovantes anuais de contribuição dos membros
+// a partir das doações pagas, das cobranças recorrentes e das ofertas conferidas.
+type GivingStatementService struct {
+	repos         *repository.Repositories
+	logger        *zap.Logger
+	communication CommunicationService
+	uploadDir     string
+}
+
+func NewGivingStatementService(repos *repository.Repositories, logger *zap.Logger, communication CommunicationService, uploadDir string) *GivingStatementService {
+	return &GivingStatementService{
+		repos:         repos,
+		logger:        logger,
+		communication: communication,
+		uploadDir:     uploadDir,
+	}
+}
+
+// BuildStatements gera os comprovantes de todos os membros que contribuíram no ano
+func (s *GivingStatementService) BuildStatements(ctx context.Context, community *domain.Community, year int) ([]*GivingStatement, error) {
+	return s.build(ctx, community, "", year)
+}
+
+// BuildStatement gera o comprovante de um membro. O comprovante é retornado mesmo
+// que o membro não tenha contribuído no ano.
+func (s *GivingStatementService) BuildStatement(ctx context.Context, community *domain.Community, memberID string, year int) (*GivingStatement, error) {
+	member, err := s.repos.Member.FindByID(ctx, community.ID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	statements, err := s.build(ctx, community, memberID, year)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) > 0 {
+		return statements[0], nil
+	}
+
+	return &GivingStatement{
+		Year:        year,
+		Member:      member,
+		Entries:     []GivingStatementEntry{},
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+func (s *GivingStatementService) build(ctx context.Context, community *domain.Community, memberID string, year int) ([]*GivingStatement, error) {
+	start, end := statementPeriod(community, year)
+
+	donations, err := s.repos.Donation.FindPaidByPeriod(ctx, community.ID, memberID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar doações: %v", err)
+	}
+
+	contributions, err := s.repos.Contribution.FindCompletedByPeriod(ctx, community.ID, memberID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contribuições: %v", err)
+	}
+
+	campaigns := make(map[string]string)
+	statements := make(map[string]*GivingStatement)
+	statementFor := func(memberID string) *GivingStatement {
+		statement, ok := statements[memberID]
+		if !ok {
+			statement = &GivingStatement{Year: year, GeneratedAt: time.Now()}
+			statements[memberID] = statement
+		}
+		return statement
+	}
+
+	for _, donation := range donations {
+		statement := statementFor(*donation.MemberID)
+		entry := GivingStatementEntry{
+			Date:        *donation.PaidAt,
+			Source:      StatementSourceDonation,
+			Description: s.describeDonation(ctx, community.ID, donation, campaigns),
+			Method:      donation.PaymentMethod,
+			Amount:      donation.NetAmount(),
+		}
+		if donation.RecurringDonationID != nil {
+			entry.Source = StatementSourceRecurring
+			statement.TotalRecurring += entry.Amount
+		} else {
+			statement.TotalDonations += entry.Amount
+		}
+		statement.Entries = append(statement.Entries, entry)
+	}
+
+	for _, contribution := range contributions {
+		statement := statementFor(*contribution.MemberID)
+		statement.Entries = append(statement.Entries, GivingStatementEntry{
+			Date:        contribution.Date,
+			Source:      StatementSourceContribution,
+			Description: contributionTypeLabel(contribution.Type),
+			Method:      contribution.Method,
+			Amount:      contribution.Amount,
+		})
+		statement.TotalContributions += contribution.Amount
+	}
+
+	ids := make([]string, 0, len(statements))
+	for id := range statements {
+		ids = append(ids, id)
+	}
+	members, err := s.repos.Member.FindByIDs(ctx, community.ID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membros: %v", err)
+	}
+
+	result := make([]*GivingStatement, 0, len(members))
+	for _, member := range members {
+		statement := statements[member.ID]
+
+		statement.Member = member
+		statement.Total = statement.TotalDonations + statement.TotalRecurring + statement.TotalContributions
+		sort.SliceStable(statement.Entries, func(i, j int) bool {
+			return statement.Entries[i].Date.Before(statement.Entries[j].Date)
+		})
+		result = append(result, statement)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Member.Name) < strings.ToLower(result[j].Member.Name)
+	})
+
+	return result, nil
+}
+
+// SendStatements registra o envio por email do comprovante de cada membro, com o PDF
+// anexado. Os comprovantes são gerados e enviados em segundo plano por SendPending; o
+// andamento pode ser acompanhado pelos destinatários da comunicação.
+func (s *GivingStatementService) SendStatements(ctx context.Context, community *domain.Community, userID string, year int, memberIDs []string) (*domain.Communication, error) {
+	statements, err := s.BuildStatements(ctx, community, year)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(memberIDs) > 0 {
+		selected := make(map[string]bool, len(memberIDs))
+		for _, id := range memberIDs {
+			selected[id] = true
+		}
+		filtered := statements[:0]
+		for _, statement := range statements {
+			if selected[statement.Member.ID] {
+				filtered = append(filtered, statement)
+			}
+		}
+		statements = filtered
+	}
+
+	if len(statements) == 0 {
+		return nil, ErrNoStatements
+	}
+
+	now := time.Now()
+	communication := &domain.Communication{
+		ID:            uuid.New().String(),
+		CommunityID:   community.ID,
+		Type:          domain.CommunicationTypeEmail,
+		Subject:       statementSubject(community, year),
+		Content:       fmt.Sprintf("Envio dos comprovantes anuais de contribuição de %d para %d membros.", year, len(statements)),
+		RecipientType: domain.RecipientTypeCustom,
+		RecipientID:   fmt.Sprintf("giving-statements-%d", year),
+		Status:        domain.CommunicationStatusPending,
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err = s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Communication.Create(ctx, communication); err != nil {
+			return fmt.Errorf("erro ao criar comunicação: %v", err)
+		}
+
+		// Os destinatários ficam pendentes até o comprovante de cada um ser enviado
+		for _, statement := range statements {
+			email := statement.Member.Email
+			recipient := &domain.CommunicationRecipient{
+				ID:              uuid.New().String(),
+				CommunicationID: communication.ID,
+				RecipientType:   domain.RecipientTypeMember,
+				RecipientID:     statement.Member.ID,
+				Email:           &email,
+				Status:          domain.CommunicationStatusPending,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			}
+			if err := repos.Communication.CreateRecipient(ctx, recipient); err != nil {
+				return fmt.Errorf("erro ao registrar destinatário: %v", err)
+			}
+		}
+
+		batch := &domain.GivingStatementBatch{
+			CommunityID:     community.ID,
+			CommunicationID: communication.ID,
+			Year:            year,
+			Status:          domain.GivingStatementBatchPending,
+			CreatedBy:       userID,
+		}
+		if err := repos.GivingStatement.CreateBatch(ctx, batch); err != nil {
+			return fmt.Errorf("erro ao registrar envio dos comprovantes: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return communication, nil
+}
+
+// maxStatementAttempts é o número de rodadas em que os envios que falharam são repetidos
+const maxStatementAttempts = 3
+
+// Run gera e envia periodicamente os comprovantes dos envios pendentes até o contexto
+// ser cancelado.
+func (s *GivingStatementService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Com mais de uma instância do servidor, apenas uma executa a rodada
+		if _, err := s.repos.RunExclusive(ctx, "giving_statements", func(ctx context.Context) error {
+			if err := s.SendPending(ctx); err != nil {
+				s.logger.Error("erro ao enviar comprovantes anuais", zap.Error(err))
+			}
+			return nil
+		}); err != nil {
+			s.logger.Error("erro ao obter lock dos comprovantes anuais", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendPending gera e envia os comprovantes dos envios registrados e ainda não concluídos
+func (s *GivingStatementService) SendPending(ctx context.Context) error {
+	batches, err := s.repos.GivingStatement.ListPendingBatches(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar envios de comprovantes: %v", err)
+	}
+
+	for _, batch := range batches {
+		if err := s.sendBatch(ctx, batch); err != nil {
+			s.logger.Error("erro ao enviar comprovantes anuais",
+				zap.Error(err),
+				zap.String("batch_id", batch.ID),
+				zap.String("communication_id", batch.CommunicationID))
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// sendBatch envia os comprovantes pendentes de um envio. Na primeira rodada são
+// enviados os destinatários pendentes; nas seguintes, também os que falharam.
+func (s *GivingStatementService) sendBatch(ctx context.Context, batch *domain.GivingStatementBatch) error {
+	community, err := s.repos.Community.FindByID(ctx, batch.CommunityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	communication, err := s.repos.Communication.FindByID(ctx, batch.CommunityID, batch.CommunicationID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar comunicação: %v", err)
+	}
+	if community == nil || communication == nil {
+		// A comunidade ou a comunicação foi excluída; não há mais o que enviar
+		return s.completeBatch(ctx, batch, nil, 0, 0)
+	}
+
+	recipients, err := s.repos.Communication.ListRecipients(ctx, communication.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar destinatários: %v", err)
+	}
+
+	var statements map[string]*GivingStatement
+	failed := 0
+	for _, recipient := range recipients {
+		retry := recipient.Status == domain.CommunicationStatusFailed && batch.Attempts > 0
+		if recipient.Status != domain.CommunicationStatusPending && !retry {
+			continue
+		}
+		if ctx.Err() != nil {
+			// O servidor está sendo encerrado; a rodada é retomada na próxima execução
+			return ctx.Err()
+		}
+
+		if statements == nil {
+			all, err := s.BuildStatements(ctx, community, batch.Year)
+			if err != nil {
+				return err
+			}
+			statements = make(map[string]*GivingStatement, len(all))
+			for _, statement := range all {
+				statements[statement.Member.ID] = statement
+			}
+		}
+
+		message, err := s.statementMessage(ctx, community, batch.Year, recipient.RecipientID, statements)
+		if err == nil {
+			err = s.communication.SendRecipientMessage(ctx, recipient, *message)
+		} else {
+			s.failRecipient(ctx, recipient, err)
+		}
+		if err != nil {
+			failed++
+		}
+	}
+
+	batch.Attempts++
+	if failed > 0 && batch.Attempts < maxStatementAttempts {
+		return s.repos.GivingStatement.UpdateBatch(ctx, batch)
+	}
+
+	return s.completeBatch(ctx, batch, communication, failed, len(recipients))
+}
+
+// statementMessage gera a mensagem com o comprovante do membro
+func (s *GivingStatementService) statementMessage(ctx context.Context, community *domain.Community, year int, memberID string, statements map[string]*GivingStatement) (*PersonalizedMessage, error) {
+	statement, ok := statements[memberID]
+	if !ok {
+		var err error
+		if statement, err = s.BuildStatement(ctx, community, memberID, year); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := s.RenderHTML(community, statement)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.RenderPDF(community, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PersonalizedMessage{
+		RecipientType: domain.RecipientTypeMember,
+		RecipientID:   memberID,
+		Email:         statement.Member.Email,
+		Subject:       statementSubject(community, year),
+		Body:          string(body),
+		Attachments: []EmailAttachment{{
+			Filename:    StatementFilename(statement, "pdf"),
+			ContentType: "application/pdf",
+			Data:        document,
+		}},
+	}, nil
+}
+
+// failRecipient registra a falha ao gerar o comprovante de um destinatário
+func (s *GivingStatementService) failRecipient(ctx context.Context, recipient *domain.CommunicationRecipient, cause error) {
+	errorMessage := cause.Error()
+	recipient.Status = domain.CommunicationStatusFailed
+	recipient.ErrorMessage = &errorMessage
+	recipient.UpdatedAt = time.Now()
+	if err := s.repos.Communication.UpdateRecipient(ctx, recipient); err != nil {
+		s.logger.Error("erro ao atualizar destinatário", zap.Error(err), zap.String("id", recipient.ID))
+	}
+}
+
+// completeBatch conclui o envio e atualiza o status da comunicação. A comunicação só
+// é marcada como falha quando nenhum comprovante foi entregue.
+func (s *GivingStatementService) completeBatch(ctx context.Context, batch *domain.GivingStatementBatch, communication *domain.Communication, failed, total int) error {
+	now := time.Now()
+	if communication != nil {
+		communication.Status = domain.CommunicationStatusSent
+		if failed > 0 && failed == total {
+			communication.Status = domain.CommunicationStatusFailed
+		}
+		communication.SentAt = &now
+		communication.UpdatedAt = now
+		if err := s.repos.Communication.Update(ctx, communication); err != nil {
+			return fmt.Errorf("erro ao atualizar status da comunicação: %v", err)
+		}
+	}
+
+	batch.Status = domain.GivingStatementBatchCompleted
+	batch.CompletedAt = &now
+	return s.repos.GivingStatement.UpdateBatch(ctx, batch)
+}
+
+func statementSubject(community *domain.Community, year int) string {
+	return fmt.Sprintf("Comprovante anual de contribuições %d - %s", year, community.Name)
+}
+
+// RenderHTML gera o comprovante em HTML, com o logo incorporado à página
+func (s *GivingStatementService) RenderHTML(community *domain.Community, statement *GivingStatement) ([]byte, error) {
+	data := struct {
+		Community *domain.Community
+		Address   []string
+		Logo      template.URL
+		Statement *GivingStatement
+	}{
+		Community: community,
+		Address:   communityAddress(community),
+		Logo:      s.logoDataURL(community),
+		Statement: statement,
+	}
+
+	var buf bytes.Buffer
+	if err := givingStatementTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("erro ao gerar comprovante: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPDF gera o comprovante em PDF
+func (s *GivingStatementService) RenderPDF(community *domain.Community, statement *GivingStatement) ([]byte, error) {
+	doc := pdf.New()
+	page := doc.AddPage()
+	margin := 40.0
+	right := page.Width() - margin
+
+	// Cabeçalho com o logo e os dados da comunidade
+	textX := margin
+	if logo := loadCommunityLogo(s.uploadDir, community, s.logger); logo != nil {
+		if img, err := doc.AddImage(logo); err == nil {
+			width, height := fitImage(logo, 60, 60)
+			page.DrawImage(img, margin, margin, width, height)
+			textX = margin + 72
+		}
+	}
+	y := margin + 14
+	page.Text(textX, y, 16, true, community.Name)
+	for _, line := range communityAddress(community) {
+		y += 13
+		page.Text(textX, y, 9, false, line)
+	}
+	if y < margin+60 {
+		y = margin + 60
+	}
+
+	y += 20
+	page.Line(margin, y, right, y, 0.5)
+	y += 28
+	page.TextCenter(page.Width()/2, y, 14, true, fmt.Sprintf("Comprovante Anual de Contribuições - %d", statement.Year))
+
+	// Dados do membro
+	y += 30
+	page.Text(margin, y, 10, true, "Contribuinte:")
+	page.Text(margin+75, y, 10, false, statement.Member.Name)
+	if statement.Member.CPF != "" {
+		y += 15
+		page.Text(margin, y, 10, true, "CPF:")
+		page.Text(margin+75, y, 10, false, statement.Member.CPF)
+	}
+	if statement.Member.Email != "" {
+		y += 15
+		page.Text(margin, y, 10, true, "Email:")
+		page.Text(margin+75, y, 10, false, statement.Member.Email)
+	}
+
+	// Tabela de lançamentos
+	tableHeader := func(y float64) float64 {
+		page.Rect(margin, y-12, right-margin, 18, 0.9)
+		page.Text(margin+5, y, 9, true, "Data")
+		page.Text(margin+70, y, 9, true, "Descrição")
+		page.Text(margin+330, y, 9, true, "Forma")
+		page.TextRight(right-5, y, 9, true, "Valor")
+		return y + 20
+	}
+
+	y = tableHeader(y + 30)
+	for _, entry := range statement.Entries {
+		if y > page.Height()-90 {
+			page = doc.AddPage()
+			y = tableHeader(margin + 20)
+		}
+		description := entry.Description
+		for pdf.TextWidth(description, 9) > 250 && len([]rune(description)) > 4 {
+			runes := []rune(description)
+			description = string(runes[:len(runes)-4]) + "..."
+		}
+		page.Text(margin+5, y, 9, false, entry.Date.Format("02/01/2006"))
+		page.Text(margin+70, y, 9, false, description)
+		page.Text(margin+330, y, 9, false, paymentMethodLabel(entry.Method))
+		page.TextRight(right-5, y, 9, false, formatCurrency(entry.Amount))
+		y += 16
+	}
+	if len(statement.Entries) == 0 {
+		page.Text(margin+5, y, 9, false, "Nenhuma contribuição registrada no período.")
+		y += 16
+	}
+
+	// Resumo
+	if y > page.Height()-150 {
+		page = doc.AddPage()
+		y = margin
+	}
+	y += 4
+	page.Line(margin, y, right, y, 0.5)
+	totals := []struct {
+		label string
+		value float64
+	}{
+		{"Doações", statement.TotalDonations},
+		{"Doações recorrentes", statement.TotalRecurring},
+		{"Dízimos e ofertas", statement.TotalContributions},
+	}
+	for _, total := range totals {
+		y += 16
+		page.TextRight(right-110, y, 10, false, total.label)
+		page.TextRight(right-5, y, 10, false, formatCurrency(total.value))
+	}
+	y += 20
+	page.TextRight(right-110, y, 11, true, "Total")
+	page.TextRight(right-5, y, 11, true, formatCurrency(statement.Total))
+
+	y += 40
+	y = page.Paragraph(margin, y, right-margin, 9, false,
+		fmt.Sprintf("Declaramos, para os devidos fins, que %s contribuiu com a %s no ano de %d com os valores acima discriminados.",
+			statement.Member.Name, community.Name, statement.Year))
+	page.Text(margin, y+10, 8, false, "Emitido em "+statement.GeneratedAt.Format("02/01/2006 15:04"))
+
+	return doc.Bytes()
+}
+
+// StatementFilename retorna o nome do arquivo do comprovante
+func StatementFilename(statement *GivingStatement, extension string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-' || r == '_':
+			return '-'
+		}
+		return -1
+	}, statement.Member.Name)
+	if name == "" {
+		name = statement.Member.ID
+	}
+	return fmt.Sprintf("comprovante-%d-%s.%s", statement.Year, strings.ToLower(name), extension)
+}
+
+func (s *GivingStatementService) describeDonation(ctx context.Context, communityID string, donation *domain.Donation, campaigns map[string]string) string {
+	description := "Doação"
+	if donation.RecurringDonationID != nil {
+		description = "Doação recorrente"
+	}
+
+	if donation.CampaignID != "" {
+		name, ok := campaigns[donation.CampaignID]
+		if !ok {
+			if campaign, err := s.repos.Campaign.FindByID(ctx, communityID, donation.CampaignID); err == nil {
+				name = campaign.Name
+			}
+			campaigns[donation.CampaignID] = name
+		}
+		if name != "" {
+			return description + " - " + name
+		}
+	}
+
+	if donation.Description != "" {
+		return description + " - " + donation.Description
+	}
+	return description
+}
+
+// loadCommunityLogo carrega o logo da comunidade do diretório de uploads
+func loadCommunityLogo(uploadDir string, community *domain.Community, logger *zap.Logger) image.Image {
+	if community.Logo == "" {
+		return nil
+	}
+
+	file, err := os.Open(filepath.Join(uploadDir, community.Logo))
+	if err != nil {
+		logger.Warn("logo da comunidade não encontrado", zap.String("logo", community.Logo), zap.Error(err))
+		return nil
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		logger.Warn("formato de logo não suportado", zap.String("logo", community.Logo), zap.Error(err))
+		return nil
+	}
+	return img
+}
+
+// logoDataURL retorna o logo da comunidade como data URL para ser incorporado ao HTML
+func (s *GivingStatementService) logoDataURL(community *domain.Community) template.URL {
+	if community.Logo == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.uploadDir, community.Logo))
+	if err != nil {
+		return ""
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(community.Logo))
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	return template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
+
+// statementPeriod retorna o início e o fim do ano no fuso horário da comunidade
+func statementPeriod(community *domain.Community, year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, communityLocation(community))
+	return start, start.AddDate(1, 0, 0)
+}
+
+// communityLocation retorna o fuso horário da comunidade (UTC quando não configurado)
+func communityLocation(community *domain.Community) *time.Location {
+	if community.Timezone != "" {
+		if loc, err := time.LoadLocation(community.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+func communityAddress(community *domain.Community) []string {
+	var lines []string
+	if community.Address != "" {
+		lines = append(lines, community.Address)
+	}
+
+	var cityLine []string
+	if community.City != "" {
+		cityLine = append(cityLine, community.City)
+	}
+	if community.State != "" {
+		cityLine = append(cityLine, community.State)
+	}
+	line := strings.Join(cityLine, " - ")
+	if community.ZipCode != "" {
+		if line != "" {
+			line += ", "
+		}
+		line += "CEP " + community.ZipCode
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	var contact []string
+	if community.Phone != "" {
+		contact = append(contact, community.Phone)
+	}
+	if community.Email != "" {
+		contact = append(contact, community.Email)
+	}
+	if len(contact) > 0 {
+		lines = append(lines, strings.Join(contact, " | "))
+	}
+	return lines
+}
+
+// fitImage calcula as dimensões da imagem para caber na área informada mantendo a proporção
+func fitImage(img image.Image, maxWidth, maxHeight float64) (float64, float64) {
+	bounds := img.Bounds()
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	if width == 0 || height == 0 {
+		return maxWidth, maxHeight
+	}
+	scale := maxWidth / width
+	if height*scale > maxHeight {
+		scale = maxHeight / height
+	}
+	return width * scale, height * scale
+}
+
+// formatCurrency formata um valor em reais (ex.: R$ 1.234,56)
+func formatCurrency(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	formatted := fmt.Sprintf("%.2f", value)
+	integer, decimal := formatted[:len(formatted)-3], formatted[len(formatted)-2:]
+
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+
+	return sign + "R$ " + strings.Join(groups, ".") + "," + decimal
+}
+
+func paymentMethodLabel(method string) string {
+	switch method {
+	case "credit_card":
+		return "Cartão de crédito"
+	case "boleto":
+		return "Boleto"
+	case "pix":
+		return "Pix"
+	case "cash":
+		return "Dinheiro"
+	case "check":
+		return "Cheque"
+	case "bank_transfer":
+		return "Transferência"
+	default:
+		return method
+	}
+}
+
+func contributionTypeLabel(contributionType string) string {
+	switch contributionType {
+	case "tithe":
+		return "Dízimo"
+	case "offering":
+		return "Oferta"
+	case "donation":
+		return "Doação"
+	default:
+		return contributionType
+	}
+}
+
+var givingStatementTemplate = template.Must(template.New("giving_statement").Funcs(template.FuncMap{
+	"currency": formatCurrency,
+	"method":   paymentMethodLabel,
+	"date": func(t time.Time) string {
+		return t.Format("02/01/2006")
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Comprovante Anual de Contribuições {{.Statement.Year}}</title>
+</head>
+<body style="font-family: Arial, Helvetica, sans-serif; color: #333; max-width: 720px; margin: 0 auto; padding: 24px;">
+    <table style="width: 100%; border-bottom: 1px solid #ccc; padding-bottom: 12px;">
+        <tr>
+            {{if .Logo}}<td style="width: 80px;"><img src="{{.Logo}}" alt="{{.Community.Name}}" style="max-width: 70px; max-height: 70px;"></td>{{end}}
+            <td>
+                <h2 style="margin: 0;">{{.Community.Name}}</h2>
+                {{range .Address}}<div style="font-size: 12px; color: #666;">{{.}}</div>{{end}}
+            </td>
+        </tr>
+    </table>
+
+    <h3 style="text-align: center;">Comprovante Anual de Contribuições - {{.Statement.Year}}</h3>
+
+    <p>
+        <strong>Contribuinte:</strong> {{.Statement.Member.Name}}<br>
+        {{if .Statement.Member.CPF}}<strong>CPF:</strong> {{.Statement.Member.CPF}}<br>{{end}}
+        {{if .Statement.Member.Email}}<strong>Email:</strong> {{.Statement.Member.Email}}{{end}}
+    </p>
+
+    <table style="width: 100%; border-collapse: collapse; font-size: 13px;">
+        <thead>
+            <tr style="background: #eee;">
+                <th style="text-align: left; padding: 6px;">Data</th>
+                <th style="text-align: left; padding: 6px;">Descrição</th>
+                <th style="text-align: left; padding: 6px;">Forma</th>
+                <th style="text-align: right; padding: 6px;">Valor</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{range .Statement.Entries}}
+            <tr style="border-bottom: 1px solid #eee;">
+                <td style="padding: 6px;">{{date .Date}}</td>
+                <td style="padding: 6px;">{{.Description}}</td>
+                <td style="padding: 6px;">{{method .Method}}</td>
+                <td style="padding: 6px; text-align: right;">{{currency .Amount}}</td>
+            </tr>
+            {{else}}
+            <tr><td colspan="4" style="padding: 6px;">Nenhuma contribuição registrada no período.</td></tr>
+            {{end}}
+        </tbody>
+    </table>
+
+    <table style="width: 100%; margin-top: 16px; font-size: 13px;">
+        <tr><td style="text-align: right;">Doações</td><td style="text-align: right; width: 140px;">{{currency .Statement.TotalDonations}}</td></tr>
+        <tr><td style="text-align: right;">Doações recorrentes</td><td style="text-align: right;">{{currency .Statement.TotalRecurring}}</td></tr>
+        <tr><td style="text-align: right;">Dízimos e ofertas</td><td style="text-align: right;">{{currency .Statement.TotalContributions}}</td></tr>
+        <tr><td style="text-align: right;"><strong>Total</strong></td><td style="text-align: right;"><strong>{{currency .Statement.Total}}</strong></td></tr>
+    </table>
+
+    <p style="font-size: 12px; margin-top: 24px;">
+        Declaramos, para os devidos fins, que {{.Statement.Member.Name}} contribuiu com a {{.Community.Name}}
+        no ano de {{.Statement.Year}} com os valores acima discriminados.
+    </p>
+    <p style="font-size: 11px; color: #999;">Emitido em {{.Statement.GeneratedAt.Format "02/01/2006 15:04"}}</p>
+</body>
+</html>
+`))
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+type fakeCommunityRepository struct {
+	repository.CommunityRepository
+	community *domain.Community
+}
+
+func (r *fakeCommunityRepository) FindByID(ctx context.Context, id string) (*domain.Community, error) {
+	if r.community != nil && r.community.ID == id {
+		return r.community, nil
+	}
+	return nil, nil
+}
+
+type fakeCommunicationRepository struct {
+	repository.CommunicationRepository
+	communication *domain.Communication
+	recipients    []*domain.CommunicationRecipient
+}
+
+func (r *fakeCommunicationRepository) FindByID(ctx context.Context, communityID, communicationID string) (*domain.Communication, error) {
+	return r.communication, nil
+}
+
+func (r *fakeCommunicationRepository) Update(ctx context.Context, communication *domain.Communication) error {
+	return nil
+}
+
+func (r *fakeCommunicationRepository) ListRecipients(ctx context.Context, communicationID string) ([]*domain.CommunicationRecipient, error) {
+	return r.recipients, nil
+}
+
+func (r *fakeCommunicationRepository) UpdateRecipient(ctx context.Context, recipient *domain.CommunicationRecipient) error {
+	return nil
+}
+
+type fakeDonationRepository struct {
+	repository.DonationRepository
+	donations []*domain.Donation
+}
+
+func (r *fakeDonationRepository) FindPaidByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Donation, error) {
+	var donations []*domain.Donation
+	for _, donation := range r.donations {
+		if memberID == "" || *donation.MemberID == memberID {
+			donations = append(donations, donation)
+		}
+	}
+	return donations, nil
+}
+
+type fakeContributionRepository struct {
+	repository.ContributionRepository
+}
+
+func (r *fakeContributionRepository) FindCompletedByPeriod(ctx context.Context, communityID, memberID string, start, end time.Time) ([]*domain.Contribution, error) {
+	return nil, nil
+}
+
+type fakeGivingStatementRepository struct {
+	repository.GivingStatementRepository
+	updates int
+}
+
+func (r *fakeGivingStatementRepository) UpdateBatch(ctx context.Context, batch *domain.GivingStatementBatch) error {
+	r.updates++
+	return nil
+}
+
+// fakeCommunicationService registra os envios; mensagens sem email falham
+type fakeCommunicationService struct {
+	CommunicationService
+	sent map[string]int
+}
+
+func (s *fakeCommunicationService) SendRecipientMessage(ctx context.Context, recipient *domain.CommunicationRecipient, message PersonalizedMessage) error {
+	s.sent[message.RecipientID]++
+	if message.Email == "" || len(message.Attachments) != 1 {
+		recipient.Status = domain.CommunicationStatusFailed
+		return errors.New("destinatário sem email")
+	}
+	recipient.Status = domain.CommunicationStatusSent
+	return nil
+}
+
+func TestSendBatch(t *testing.T) {
+	community := &domain.Community{ID: "c1", Name: "Comunidade"}
+	paidAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	m1, m2 := "m1", "m2"
+
+	communications := &fakeCommunicationRepository{
+		communication: &domain.Communication{ID: "com1", CommunityID: "c1", Status: domain.CommunicationStatusPending},
+		recipients: []*domain.CommunicationRecipient{
+			{ID: "r1", CommunicationID: "com1", RecipientID: "m1", Status: domain.CommunicationStatusPending},
+			{ID: "r2", CommunicationID: "com1", RecipientID: "m2", Status: domain.CommunicationStatusPending},
+			{ID: "r3", CommunicationID: "com1", RecipientID: "m3", Status: domain.CommunicationStatusSent},
+		},
+	}
+	batches := &fakeGivingStatementRepository{}
+	sender := &fakeCommunicationService{sent: make(map[string]int)}
+	s := &GivingStatementService{
+		repos: &repository.Repositories{
+			Community: &fakeCommunityRepository{community: community},
+			Member: &fakeMemberRepository{members: []*domain.Member{
+				{ID: "m1", CommunityID: "c1", Name: "Ana Souza", Email: "ana@email.com"},
+				{ID: "m2", CommunityID: "c1", Name: "Bruno Lima"},
+				{ID: "m3", CommunityID: "c1", Name: "Carla Dias", Email: "carla@email.com"},
+			}},
+			Communication: communications,
+			Donation: &fakeDonationRepository{donations: []*domain.Donation{
+				{ID: "d1", MemberID: &m1, Amount: 100, Status: "paid", PaidAt: &paidAt, PaymentMethod: "pix"},
+				{ID: "d2", MemberID: &m2, Amount: 50, Status: "paid", PaidAt: &paidAt, PaymentMethod: "pix"},
+			}},
+			Contribution:    &fakeContributionRepository{},
+			GivingStatement: batches,
+		},
+		logger:        zap.NewNop(),
+		communication: sender,
+	}
+	batch := &domain.GivingStatementBatch{ID: "b1", CommunityID: "c1", CommunicationID: "com1", Year: 2025, Status: domain.GivingStatementBatchPending}
+
+	// Rodada interrompida pelo encerramento do servidor não conta como tentativa
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.sendBatch(canceled, batch); !errors.Is(err, context.Canceled) {
+		t.Fatalf("erro = %v; esperado %v", err, context.Canceled)
+	}
+	if batch.Attempts != 0 || len(sender.sent) != 0 {
+		t.Fatalf("tentativas = %d, envios = %v; esperado nenhum", batch.Attempts, sender.sent)
+	}
+
+	for attempt := 1; attempt <= maxStatementAttempts; attempt++ {
+		if err := s.sendBatch(context.Background(), batch); err != nil {
+			t.Fatalf("rodada %d: erro inesperado: %v", attempt, err)
+		}
+		if batch.Attempts != attempt {
+			t.Errorf("rodada %d: tentativas = %d", attempt, batch.Attempts)
+		}
+		wantStatus := domain.GivingStatementBatchPending
+		if attempt == maxStatementAttempts {
+			wantStatus = domain.GivingStatementBatchCompleted
+		}
+		if batch.Status != wantStatus {
+			t.Errorf("rodada %d: status = %q; esperado %q", attempt, batch.Status, wantStatus)
+		}
+	}
+
+	// O envio bem-sucedido não é repetido, o que falhou é repetido a cada rodada e o
+	// já enviado antes do início não é reenviado
+	if sender.sent["m1"] != 1 || sender.sent["m2"] != maxStatementAttempts || sender.sent["m3"] != 0 {
+		t.Errorf("envios = %v", sender.sent)
+	}
+	if batch.CompletedAt == nil {
+		t.Error("envio concluído sem data de conclusão")
+	}
+	if status := communications.communication.Status; status != domain.CommunicationStatusSent {
+		t.Errorf("status da comunicação = %q; esperado %q", status, domain.CommunicationStatusSent)
+	}
+}
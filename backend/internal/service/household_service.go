@@ -0,0 +1,1158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrFamilyNotFound      = errors.New("família não encontrada")
+	ErrMemberNotInFamily   = errors.New("o membro não pertence a esta família")
+	ErrInvalidFamilyRole   = errors.New("papel na família inválido")
+	ErrInvalidHousehold    = errors.New("dados da família inválidos")
+	ErrInvalidRelationship = errors.New("parentesco inválido")
+)
+
+// maxGenerations limita a busca de antepassados na validação de ciclos
+const maxGenerations = 30
+
+// householdRoles são os papéis aceitos na família, relativos ao chefe da família
+var householdRoles = []string{
+	domain.FamilyRoleHead, domain.FamilyRoleSpouse, domain.FamilyRoleChild, domain.FamilyRoleSibling,
+	domain.FamilyRoleParent, domain.FamilyRoleGrandparent, domain.FamilyRoleGrandchild,
+	domain.FamilyRoleUncleAunt, domain.FamilyRoleNephewNiece, domain.FamilyRoleCousin, domain.FamilyRoleOther,
+}
+
+// HouseholdNode é um membro no grafo da família
+type HouseholdNode struct {
+	MemberID    string  `json:"member_id"`
+	Name        string  `json:"name"`
+	Photo       string  `json:"photo"`
+	Gender      string  `json:"gender"`
+	Status      string  `json:"status"`
+	FamilyID    *string `json:"family_id"`
+	FamilyRole  string  `json:"family_role"`
+	InHousehold bool    `json:"in_household"`
+	IsHead      bool    `json:"is_head"`
+}
+
+// HouseholdEdge liga dois membros do grafo. Em parent, From é o pai ou a mãe de To.
+type HouseholdEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// HouseholdRelation é o parentesco de RelativeID em relação a MemberID, derivado dos
+// cônjuges e dos pais cadastrados (avós, irmãos, netos)
+type HouseholdRelation struct {
+	MemberID   string `json:"member_id"`
+	RelativeID string `json:"relative_id"`
+	Relation   string `json:"relation"`
+}
+
+// HouseholdGraph reúne os membros da família, os parentes de fora da casa e o
+// parentesco entre eles
+type HouseholdGraph struct {
+	Family    *domain.Family       `json:"family"`
+	Nodes     []*HouseholdNode     `json:"nodes"`
+	Edges     []*HouseholdEdge     `json:"edges"`
+	Relations []*HouseholdRelation `json:"relations"`
+}
+
+// Relative é um parente do membro
+type Relative struct {
+	Relation string         `json:"relation"`
+	Member   *HouseholdNode `json:"member"`
+}
+
+// HouseholdMailing é um destinatário da correspondência: uma família, com o endereço da
+// casa, ou um membro que não pertence a nenhuma família
+type HouseholdMailing struct {
+	FamilyID     *string  `json:"family_id"`
+	FamilyName   string   `json:"family_name"`
+	Addressee    string   `json:"addressee"`
+	MemberIDs    []string `json:"member_ids"`
+	Address      string   `json:"address"`
+	Number       string   `json:"number"`
+	Neighborhood string   `json:"neighborhood"`
+	City         string   `json:"city"`
+	State        string   `json:"state"`
+	Country      string   `json:"country"`
+	ZipCode      string   `json:"zip_code"`
+}
+
+// HouseholdService é o ponto único de manutenção das famílias. Mantém sincronizados os
+// vínculos em family_members e os campos FamilyID, FamilyRole, SpouseID e ParentID dos
+// membros, e valida o parentesco: cada membro tem no máximo um cônjuge e ninguém pode ser
+// antepassado de si mesmo. Os papéis na família são relativos ao chefe da família.
+type HouseholdService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewHouseholdService(repos *repository.Repositories, logger *zap.Logger) *HouseholdService {
+	return &HouseholdService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// householdEdit acumula os membros lidos e alterados em uma operação para gravá-los
+// juntos
+type householdEdit struct {
+	communityID string
+	members     map[string]*domain.Member
+	change      repository.HouseholdChange
+}
+
+func (s *HouseholdService) newEdit(communityID string) *householdEdit {
+	return &householdEdit{
+		communityID: communityID,
+		members:     make(map[string]*domain.Member),
+	}
+}
+
+// member busca o membro uma única vez por operação, para que as validações enxerguem as
+// alterações ainda não gravadas
+func (s *HouseholdService) member(ctx context.Context, edit *householdEdit, id string) (*domain.Member, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if member, ok := edit.members[id]; ok {
+		return member, nil
+	}
+	member, err := s.repos.Member.FindByID(ctx, edit.communityID, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	edit.members[id] = member
+	return member, nil
+}
+
+func (e *householdEdit) touch(member *domain.Member) {
+	for _, changed := range e.change.Members {
+		if changed == member {
+			return
+		}
+	}
+	e.change.Members = append(e.change.Members, member)
+}
+
+func (e *householdEdit) link(link *domain.FamilyMember) {
+	for _, changed := range e.change.Links {
+		if changed == link {
+			return
+		}
+	}
+	e.change.Links = append(e.change.Links, link)
+}
+
+func (e *householdEdit) family(family *domain.Family) {
+	for _, changed := range e.change.Families {
+		if changed == family {
+			return
+		}
+	}
+	e.change.Families = append(e.change.Families, family)
+}
+
+func (s *HouseholdService) save(ctx context.Context, edit *householdEdit) error {
+	if err := s.repos.Household.Save(ctx, &edit.change); err != nil {
+		return fmt.Errorf("erro ao gravar família: %v", err)
+	}
+	return nil
+}
+
+// normalizeFamilyRole aceita os papéis cadastrados e os nomes em português usados na
+// importação de membros (esposa, filho, avó...)
+func normalizeFamilyRole(role string) (string, error) {
+	key := normalizeHeader(role)
+	if value, ok := familyRoleValues[key]; ok {
+		return value, nil
+	}
+	if containsString(householdRoles, key) {
+		return key, nil
+	}
+	return "", fmt.Errorf("%w: %q; aceitos: %s", ErrInvalidFamilyRole, role, strings.Join(householdRoles, ", "))
+}
+
+// List lista as famílias da comunidade
+func (s *HouseholdService) List(ctx context.Context, communityID string) ([]*domain.Family, error) {
+	families, err := s.repos.Family.ListByCommunity(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar famílias: %v", err)
+	}
+	return families, nil
+}
+
+// Get retorna a família e os seus vínculos
+func (s *HouseholdService) Get(ctx context.Context, communityID, familyID string) (*domain.Family, []*domain.FamilyMember, error) {
+	family, err := s.repos.Family.FindByID(ctx, communityID, familyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar família: %v", err)
+	}
+	if family == nil {
+		return nil, nil, ErrFamilyNotFound
+	}
+	links, err := s.repos.Family.ListFamilyMembers(ctx, familyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar membros da família: %v", err)
+	}
+	return family, links, nil
+}
+
+// Create cria a família. O chefe da família informado deixa a família anterior e é
+// incluído na nova.
+func (s *HouseholdService) Create(ctx context.Context, family *domain.Family) (*domain.Family, error) {
+	family.Name = strings.TrimSpace(family.Name)
+	if family.Name == "" {
+		return nil, fmt.Errorf("%w: informe o nome da família", ErrInvalidHousehold)
+	}
+
+	edit := s.newEdit(family.CommunityID)
+	headID := family.HeadOfFamily
+	family.ID = uuid.New().String()
+	family.HeadOfFamily = ""
+	edit.family(family)
+
+	if headID != "" {
+		head, err := s.member(ctx, edit, headID)
+		if err != nil {
+			return nil, err
+		}
+		if head == nil {
+			return nil, ErrMemberNotFound
+		}
+		if _, err := s.join(ctx, edit, family, head, domain.FamilyRoleHead); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.save(ctx, edit); err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// Update altera o nome, o endereço e o chefe da família. Com applyAddress, o endereço da
+// família é copiado para o cadastro dos seus membros.
+func (s *HouseholdService) Update(ctx context.Context, update *domain.Family, applyAddress bool) (*domain.Family, error) {
+	family, links, err := s.Get(ctx, update.CommunityID, update.ID)
+	if err != nil {
+		return nil, err
+	}
+	update.Name = strings.TrimSpace(update.Name)
+	if update.Name == "" {
+		return nil, fmt.Errorf("%w: informe o nome da família", ErrInvalidHousehold)
+	}
+
+	family.Name = update.Name
+	family.Description = update.Description
+	family.Address = update.Address
+	family.Number = update.Number
+	family.Neighborhood = update.Neighborhood
+	family.City = update.City
+	family.State = update.State
+	family.Country = update.Country
+	family.ZipCode = update.ZipCode
+
+	edit := s.newEdit(family.CommunityID)
+	edit.family(family)
+	edit.change.ApplyAddress = applyAddress
+
+	// Troca do chefe: os papéis dos demais passam a ser relativos ao novo chefe
+	if update.HeadOfFamily != "" && update.HeadOfFamily != family.HeadOfFamily {
+		if findLink(links, update.HeadOfFamily) == nil {
+			return nil, fmt.Errorf("%w: o chefe da família deve ser um dos seus membros", ErrInvalidHousehold)
+		}
+		family.HeadOfFamily = update.HeadOfFamily
+		if err := s.rebaseRoles(ctx, edit, family, links, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.save(ctx, edit); err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// Delete remove a família e desvincula os seus membros. O parentesco é mantido.
+func (s *HouseholdService) Delete(ctx context.Context, communityID, familyID string) error {
+	_, links, err := s.Get(ctx, communityID, familyID)
+	if err != nil {
+		return err
+	}
+
+	edit := s.newEdit(communityID)
+	for _, link := range links {
+		member, err := s.member(ctx, edit, link.MemberID)
+		if err != nil {
+			return err
+		}
+		if member != nil && member.FamilyID != nil && *member.FamilyID == familyID {
+			member.FamilyID = nil
+			member.FamilyRole = ""
+			edit.touch(member)
+		}
+	}
+	edit.change.DeleteFamilyID = familyID
+	return s.save(ctx, edit)
+}
+
+// AddMember inclui o membro na família com o papel informado, retirando-o da família
+// anterior. O primeiro membro de uma família sem chefe passa a ser o chefe. Os papéis de
+// cônjuge, filho e pai/mãe também registram o parentesco com o chefe da família.
+func (s *HouseholdService) AddMember(ctx context.Context, communityID, familyID, memberID, role string) (*domain.FamilyMember, error) {
+	role, err := normalizeFamilyRole(role)
+	if err != nil {
+		return nil, err
+	}
+	family, links, err := s.Get(ctx, communityID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if findLink(links, memberID) != nil {
+		return s.UpdateRole(ctx, communityID, familyID, memberID, role)
+	}
+
+	edit := s.newEdit(communityID)
+	member, err := s.member(ctx, edit, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	if findLink(links, family.HeadOfFamily) == nil {
+		role = domain.FamilyRoleHead
+	} else if role == domain.FamilyRoleHead {
+		return nil, fmt.Errorf("%w: a família já tem um chefe; para trocá-lo, altere a família", ErrInvalidHousehold)
+	}
+	link, err := s.join(ctx, edit, family, member, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, edit); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UpdateRole altera o papel do membro na família e o parentesco com o chefe da família
+func (s *HouseholdService) UpdateRole(ctx context.Context, communityID, familyID, memberID, role string) (*domain.FamilyMember, error) {
+	role, err := normalizeFamilyRole(role)
+	if err != nil {
+		return nil, err
+	}
+	family, links, err := s.Get(ctx, communityID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	link := findLink(links, memberID)
+	if link == nil {
+		return nil, ErrMemberNotInFamily
+	}
+	if memberID == family.HeadOfFamily && role != domain.FamilyRoleHead {
+		return nil, fmt.Errorf("%w: para trocar o chefe da família, altere a família", ErrInvalidHousehold)
+	}
+	if memberID != family.HeadOfFamily && role == domain.FamilyRoleHead {
+		return nil, fmt.Errorf("%w: para trocar o chefe da família, altere a família", ErrInvalidHousehold)
+	}
+
+	edit := s.newEdit(communityID)
+	member, err := s.member(ctx, edit, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	if memberID != family.HeadOfFamily && role != link.Role {
+		head, err := s.member(ctx, edit, family.HeadOfFamily)
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			s.unlinkFromHead(edit, member, head, link.Role)
+			if err := s.linkToHead(ctx, edit, member, head, role); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	link.Role = role
+	edit.link(link)
+	member.FamilyRole = role
+	edit.touch(member)
+
+	if err := s.save(ctx, edit); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// RemoveMember retira o membro da família. Quando o membro era o chefe, o cônjuge ou o
+// membro mais antigo assume. O parentesco é mantido: quem deixa a casa continua cônjuge,
+// filho ou pai dos demais.
+func (s *HouseholdService) RemoveMember(ctx context.Context, communityID, familyID, memberID string) error {
+	family, links, err := s.Get(ctx, communityID, familyID)
+	if err != nil {
+		return err
+	}
+
+	edit := s.newEdit(communityID)
+	member, err := s.member(ctx, edit, memberID)
+	if err != nil {
+		return err
+	}
+	linked := findLink(links, memberID) != nil
+	if member == nil && !linked {
+		return ErrMemberNotFound
+	}
+	if !linked && (member.FamilyID == nil || *member.FamilyID != familyID) {
+		return ErrMemberNotInFamily
+	}
+
+	if member != nil {
+		member.FamilyID = nil
+		member.FamilyRole = ""
+		edit.touch(member)
+	}
+	edit.change.Unlink = append(edit.change.Unlink, memberID)
+	if err := s.leave(ctx, edit, family, links, memberID); err != nil {
+		return err
+	}
+	return s.save(ctx, edit)
+}
+
+// SetRelations define o cônjuge e o pai ou a mãe do membro (nil remove). O cônjuge
+// anterior também é desvinculado. Os papéis nas famílias envolvidas são atualizados de
+// acordo com o novo parentesco.
+func (s *HouseholdService) SetRelations(ctx context.Context, communityID, memberID string, spouseID, parentID *string) (*domain.Member, error) {
+	edit := s.newEdit(communityID)
+	member, err := s.member(ctx, edit, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	if !sameID(member.SpouseID, spouseID) {
+		if member.SpouseID != nil {
+			previous, err := s.member(ctx, edit, *member.SpouseID)
+			if err != nil {
+				return nil, err
+			}
+			if previous != nil && previous.SpouseID != nil && *previous.SpouseID == member.ID {
+				previous.SpouseID = nil
+				edit.touch(previous)
+			}
+			member.SpouseID = nil
+			edit.touch(member)
+		}
+		if spouseID != nil && *spouseID != "" {
+			spouse, err := s.member(ctx, edit, *spouseID)
+			if err != nil {
+				return nil, err
+			}
+			if spouse == nil {
+				return nil, fmt.Errorf("%w: cônjuge não encontrado", ErrInvalidRelationship)
+			}
+			if err := s.setSpouse(ctx, edit, member, spouse); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !sameID(member.ParentID, parentID) {
+		member.ParentID = nil
+		edit.touch(member)
+		if parentID != nil && *parentID != "" {
+			parent, err := s.member(ctx, edit, *parentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent == nil {
+				return nil, fmt.Errorf("%w: pai ou mãe não encontrado", ErrInvalidRelationship)
+			}
+			if err := s.setParent(ctx, edit, member, parent); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Atualiza os papéis nas famílias dos membros alterados
+	rebased := make(map[string]bool)
+	for _, changed := range append([]*domain.Member(nil), edit.change.Members...) {
+		if changed.FamilyID == nil || rebased[*changed.FamilyID] {
+			continue
+		}
+		rebased[*changed.FamilyID] = true
+		family, links, err := s.Get(ctx, communityID, *changed.FamilyID)
+		if errors.Is(err, ErrFamilyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.rebaseRoles(ctx, edit, family, links, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.save(ctx, edit); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// join inclui o membro na família, retirando-o da família anterior, e registra o
+// parentesco com o chefe
+func (s *HouseholdService) join(ctx context.Context, edit *householdEdit, family *domain.Family, member *domain.Member, role string) (*domain.FamilyMember, error) {
+	current, err := s.repos.Family.FindByMemberID(ctx, member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar família do membro: %v", err)
+	}
+	if current != nil && current.FamilyID != family.ID {
+		previous, previousLinks, err := s.Get(ctx, edit.communityID, current.FamilyID)
+		if err != nil && !errors.Is(err, ErrFamilyNotFound) {
+			return nil, err
+		}
+		if previous != nil {
+			if err := s.leave(ctx, edit, previous, previousLinks, member.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if role == domain.FamilyRoleHead {
+		family.HeadOfFamily = member.ID
+		edit.family(family)
+	} else {
+		head, err := s.member(ctx, edit, family.HeadOfFamily)
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			if err := s.linkToHead(ctx, edit, member, head, role); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	familyID := family.ID
+	member.FamilyID = &familyID
+	member.FamilyRole = role
+	edit.touch(member)
+	link := &domain.FamilyMember{FamilyID: family.ID, MemberID: member.ID, Role: role}
+	edit.link(link)
+	return link, nil
+}
+
+// leave ajusta a família que o membro está deixando: sem ele, o cônjuge ou o membro
+// mais antigo passa a ser o chefe
+func (s *HouseholdService) leave(ctx context.Context, edit *householdEdit, family *domain.Family, links []*domain.FamilyMember, memberID string) error {
+	if family.HeadOfFamily != memberID {
+		return nil
+	}
+
+	remaining := make([]*domain.FamilyMember, 0, len(links))
+	for _, link := range links {
+		if link.MemberID != memberID {
+			remaining = append(remaining, link)
+		}
+	}
+	sort.SliceStable(remaining, func(i, j int) bool { return remaining[i].CreatedAt.Before(remaining[j].CreatedAt) })
+
+	family.HeadOfFamily = ""
+	if len(remaining) > 0 {
+		family.HeadOfFamily = remaining[0].MemberID
+		head, err := s.member(ctx, edit, memberID)
+		if err != nil {
+			return err
+		}
+		if head != nil && head.SpouseID != nil && findLink(remaining, *head.SpouseID) != nil {
+			family.HeadOfFamily = *head.SpouseID
+		}
+	}
+	edit.family(family)
+	return s.rebaseRoles(ctx, edit, family, remaining, true)
+}
+
+// rebaseRoles recalcula os papéis relativos ao chefe da família: quem é cônjuge, filho
+// ou pai/mãe do chefe recebe esse papel e quem deixou de ser cônjuge ou chefe passa a
+// outro. Filhos e pais sem parentesco cadastrado (enteados, adotados) são mantidos, a não
+// ser que o chefe tenha mudado (headChanged).
+func (s *HouseholdService) rebaseRoles(ctx context.Context, edit *householdEdit, family *domain.Family, links []*domain.FamilyMember, headChanged bool) error {
+	head, err := s.member(ctx, edit, family.HeadOfFamily)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		member, err := s.member(ctx, edit, link.MemberID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			continue
+		}
+
+		role := link.Role
+		if link.MemberID == family.HeadOfFamily {
+			role = domain.FamilyRoleHead
+		} else if relation := headRelation(member, head); relation != "" {
+			role = relation
+		} else if role == domain.FamilyRoleHead || role == domain.FamilyRoleSpouse ||
+			(headChanged && (role == domain.FamilyRoleChild || role == domain.FamilyRoleParent)) {
+			role = domain.FamilyRoleOther
+		}
+
+		if link.Role != role {
+			link.Role = role
+			edit.link(link)
+		}
+		if member.FamilyRole != role {
+			member.FamilyRole = role
+			edit.touch(member)
+		}
+	}
+	return nil
+}
+
+// headRelation retorna o papel do membro derivado do parentesco com o chefe da família
+func headRelation(member, head *domain.Member) string {
+	if head == nil {
+		return ""
+	}
+	switch {
+	case sameID(member.SpouseID, &head.ID) || sameID(head.SpouseID, &member.ID):
+		return domain.FamilyRoleSpouse
+	case sameID(member.ParentID, &head.ID) || (head.SpouseID != nil && sameID(member.ParentID, head.SpouseID)):
+		return domain.FamilyRoleChild
+	case sameID(head.ParentID, &member.ID) || (head.ParentID != nil && sameID(member.SpouseID, head.ParentID)):
+		return domain.FamilyRoleParent
+	}
+	return ""
+}
+
+// linkToHead registra o parentesco que o papel indica: o cônjuge do chefe, o filho do
+// chefe (quando ainda não tem pai ou mãe cadastrado) ou o pai ou a mãe do chefe
+func (s *HouseholdService) linkToHead(ctx context.Context, edit *householdEdit, member, head *domain.Member, role string) error {
+	switch role {
+	case domain.FamilyRoleSpouse:
+		return s.setSpouse(ctx, edit, member, head)
+	case domain.FamilyRoleChild:
+		if member.ParentID == nil {
+			return s.setParent(ctx, edit, member, head)
+		}
+	case domain.FamilyRoleParent:
+		if head.ParentID == nil {
+			return s.setParent(ctx, edit, head, member)
+		}
+	}
+	return nil
+}
+
+// unlinkFromHead desfaz o parentesco com o chefe registrado pelo papel anterior
+func (s *HouseholdService) unlinkFromHead(edit *householdEdit, member, head *domain.Member, role string) {
+	switch role {
+	case domain.FamilyRoleSpouse:
+		if sameID(member.SpouseID, &head.ID) {
+			member.SpouseID = nil
+			edit.touch(member)
+		}
+		if sameID(head.SpouseID, &member.ID) {
+			head.SpouseID = nil
+			edit.touch(head)
+		}
+	case domain.FamilyRoleChild:
+		if sameID(member.ParentID, &head.ID) {
+			member.ParentID = nil
+			edit.touch(member)
+		}
+	case domain.FamilyRoleParent:
+		if sameID(head.ParentID, &member.ID) {
+			head.ParentID = nil
+			edit.touch(head)
+		}
+	}
+}
+
+// setSpouse casa os dois membros, que não podem ter outro cônjuge nem ser antepassado
+// um do outro
+func (s *HouseholdService) setSpouse(ctx context.Context, edit *householdEdit, member, spouse *domain.Member) error {
+	if member.ID == spouse.ID {
+		return fmt.Errorf("%w: o membro não pode ser cônjuge de si mesmo", ErrInvalidRelationship)
+	}
+	for _, m := range []*domain.Member{member, spouse} {
+		other := spouse
+		if m == spouse {
+			other = member
+		}
+		if m.SpouseID != nil && *m.SpouseID != other.ID {
+			return fmt.Errorf("%w: %s já tem cônjuge cadastrado", ErrInvalidRelationship, m.Name)
+		}
+	}
+	if err := s.checkNotAncestor(ctx, edit, member, spouse, "o cônjuge não pode ser antepassado do membro"); err != nil {
+		return err
+	}
+	if err := s.checkNotAncestor(ctx, edit, spouse, member, "o cônjuge não pode ser descendente do membro"); err != nil {
+		return err
+	}
+
+	spouseID, memberID := spouse.ID, member.ID
+	member.SpouseID = &spouseID
+	spouse.SpouseID = &memberID
+	edit.touch(member)
+	edit.touch(spouse)
+	return nil
+}
+
+// setParent registra o pai ou a mãe do membro, sem permitir ciclos na árvore
+func (s *HouseholdService) setParent(ctx context.Context, edit *householdEdit, member, parent *domain.Member) error {
+	if member.ID == parent.ID {
+		return fmt.Errorf("%w: o membro não pode ser pai ou mãe de si mesmo", ErrInvalidRelationship)
+	}
+	if sameID(member.SpouseID, &parent.ID) {
+		return fmt.Errorf("%w: o cônjuge não pode ser pai ou mãe do membro", ErrInvalidRelationship)
+	}
+	if err := s.checkNotAncestor(ctx, edit, parent, member, "o parentesco criaria um ciclo na árvore da família"); err != nil {
+		return err
+	}
+
+	parentID := parent.ID
+	member.ParentID = &parentID
+	edit.touch(member)
+	return nil
+}
+
+// checkNotAncestor verifica que ancestor não está entre os antepassados de member
+// (os pais, os seus cônjuges e assim por diante)
+func (s *HouseholdService) checkNotAncestor(ctx context.Context, edit *householdEdit, member, ancestor *domain.Member, message string) error {
+	visited := map[string]bool{member.ID: true}
+	frontier := []*domain.Member{member}
+	for generation := 0; generation < maxGenerations && len(frontier) > 0; generation++ {
+		var next []*domain.Member
+		for _, current := range frontier {
+			if current.ParentID == nil {
+				continue
+			}
+			parent, err := s.member(ctx, edit, *current.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				continue
+			}
+			parents := []*domain.Member{parent}
+			if parent.SpouseID != nil {
+				spouse, err := s.member(ctx, edit, *parent.SpouseID)
+				if err != nil {
+					return err
+				}
+				if spouse != nil {
+					parents = append(parents, spouse)
+				}
+			}
+			for _, p := range parents {
+				if p.ID == ancestor.ID {
+					return fmt.Errorf("%w: %s", ErrInvalidRelationship, message)
+				}
+				if !visited[p.ID] {
+					visited[p.ID] = true
+					next = append(next, p)
+				}
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
+
+// Graph monta o grafo da família: os membros da casa, os parentes de fora dela (cônjuges,
+// pais, avós, irmãos, filhos e netos) e o parentesco derivado entre eles
+func (s *HouseholdService) Graph(ctx context.Context, communityID, familyID string) (*HouseholdGraph, error) {
+	family, links, err := s.Get(ctx, communityID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(links))
+	household := make(map[string]bool, len(links))
+	for _, link := range links {
+		ids = append(ids, link.MemberID)
+		household[link.MemberID] = true
+	}
+	tree, err := s.loadTree(ctx, communityID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &HouseholdGraph{
+		Family:    family,
+		Nodes:     []*HouseholdNode{},
+		Edges:     []*HouseholdEdge{},
+		Relations: []*HouseholdRelation{},
+	}
+	included := make(map[string]bool)
+	for id := range household {
+		if _, ok := tree.members[id]; ok {
+			included[id] = true
+		}
+	}
+	for _, id := range ids {
+		if !included[id] {
+			continue
+		}
+		for _, relation := range tree.relations(id) {
+			graph.Relations = append(graph.Relations, &HouseholdRelation{MemberID: id, RelativeID: relation.id, Relation: relation.relation})
+			included[relation.id] = true
+		}
+	}
+
+	for id := range included {
+		member := tree.members[id]
+		graph.Nodes = append(graph.Nodes, householdNode(member, household[id], id == family.HeadOfFamily))
+		if spouse := tree.spouses[id]; included[spouse] && id < spouse {
+			graph.Edges = append(graph.Edges, &HouseholdEdge{From: id, To: spouse, Type: domain.FamilyRoleSpouse})
+		}
+		if member.ParentID != nil && included[*member.ParentID] {
+			graph.Edges = append(graph.Edges, &HouseholdEdge{From: *member.ParentID, To: id, Type: domain.FamilyRoleParent})
+		}
+	}
+
+	// Membros da casa primeiro, o chefe à frente
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		a, b := graph.Nodes[i], graph.Nodes[j]
+		if a.InHousehold != b.InHousehold {
+			return a.InHousehold
+		}
+		if a.IsHead != b.IsHead {
+			return a.IsHead
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	return graph, nil
+}
+
+// Relatives lista os parentes do membro derivados dos cônjuges e dos pais cadastrados
+func (s *HouseholdService) Relatives(ctx context.Context, communityID, memberID string) ([]*Relative, error) {
+	tree, err := s.loadTree(ctx, communityID, []string{memberID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tree.members[memberID]; !ok {
+		return nil, ErrMemberNotFound
+	}
+
+	member := tree.members[memberID]
+	relatives := []*Relative{}
+	for _, relation := range tree.relations(memberID) {
+		relative := tree.members[relation.id]
+		inHousehold := member.FamilyID != nil && sameID(relative.FamilyID, member.FamilyID)
+		relatives = append(relatives, &Relative{
+			Relation: relation.relation,
+			Member:   householdNode(relative, inHousehold, false),
+		})
+	}
+	return relatives, nil
+}
+
+func householdNode(member *domain.Member, inHousehold, isHead bool) *HouseholdNode {
+	return &HouseholdNode{
+		MemberID:    member.ID,
+		Name:        member.Name,
+		Photo:       member.Photo,
+		Gender:      member.Gender,
+		Status:      member.Status,
+		FamilyID:    member.FamilyID,
+		FamilyRole:  member.FamilyRole,
+		InHousehold: inHousehold,
+		IsHead:      isHead,
+	}
+}
+
+// loadTree carrega os membros informados e os parentes necessários para derivar avós,
+// irmãos e netos: a cada rodada entram os cônjuges, os pais e os filhos dos membros
+// carregados na rodada anterior
+func (s *HouseholdService) loadTree(ctx context.Context, communityID string, ids []string) (*familyTree, error) {
+	members := make(map[string]*domain.Member)
+	frontier := ids
+	for round := 0; round < 4 && len(frontier) > 0; round++ {
+		query := append([]string(nil), frontier...)
+		for _, id := range frontier {
+			member, ok := members[id]
+			if !ok {
+				continue
+			}
+			for _, related := range []*string{member.SpouseID, member.ParentID} {
+				if related != nil {
+					if _, loaded := members[*related]; !loaded {
+						query = append(query, *related)
+					}
+				}
+			}
+		}
+
+		found, err := s.repos.Household.FindRelatives(ctx, communityID, query)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar parentes: %v", err)
+		}
+		frontier = nil
+		for _, member := range found {
+			if _, ok := members[member.ID]; !ok {
+				members[member.ID] = member
+				frontier = append(frontier, member.ID)
+			}
+		}
+	}
+	return newFamilyTree(members), nil
+}
+
+// familyTree deriva o parentesco a partir dos cônjuges e dos pais cadastrados. O cônjuge
+// do pai ou da mãe também é considerado pai ou mãe.
+type familyTree struct {
+	members  map[string]*domain.Member
+	spouses  map[string]string
+	parents  map[string][]string
+	children map[string][]string
+}
+
+type treeRelation struct {
+	id       string
+	relation string
+}
+
+func newFamilyTree(members map[string]*domain.Member) *familyTree {
+	tree := &familyTree{
+		members:  members,
+		spouses:  make(map[string]string),
+		parents:  make(map[string][]string),
+		children: make(map[string][]string),
+	}
+
+	// O cônjuge pode estar cadastrado apenas de um dos lados
+	for id, member := range members {
+		if member.SpouseID != nil {
+			if _, ok := members[*member.SpouseID]; ok {
+				tree.spouses[id] = *member.SpouseID
+			}
+		}
+	}
+	for id, member := range members {
+		if member.SpouseID != nil {
+			if _, ok := tree.spouses[*member.SpouseID]; !ok {
+				if _, loaded := members[*member.SpouseID]; loaded {
+					tree.spouses[*member.SpouseID] = id
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return members[ids[i]].Name < members[ids[j]].Name })
+	for _, id := range ids {
+		member := members[id]
+		if member.ParentID == nil {
+			continue
+		}
+		if _, ok := members[*member.ParentID]; !ok {
+			continue
+		}
+		parents := []string{*member.ParentID}
+		if spouse, ok := tree.spouses[*member.ParentID]; ok && spouse != id {
+			parents = append(parents, spouse)
+		}
+		tree.parents[id] = parents
+		for _, parent := range parents {
+			tree.children[parent] = append(tree.children[parent], id)
+		}
+	}
+	return tree
+}
+
+// relations lista os parentes do membro. Cada parente aparece uma vez, com o parentesco
+// mais próximo.
+func (t *familyTree) relations(id string) []treeRelation {
+	var relations []treeRelation
+	seen := map[string]bool{id: true}
+	add := func(ids []string, relation string) {
+		for _, relative := range ids {
+			if !seen[relative] {
+				seen[relative] = true
+				relations = append(relations, treeRelation{id: relative, relation: relation})
+			}
+		}
+	}
+
+	if spouse, ok := t.spouses[id]; ok {
+		add([]string{spouse}, domain.FamilyRoleSpouse)
+	}
+	add(t.parents[id], domain.FamilyRoleParent)
+	add(t.children[id], domain.FamilyRoleChild)
+	for _, parent := range t.parents[id] {
+		add(t.children[parent], domain.FamilyRoleSibling)
+	}
+	for _, parent := range t.parents[id] {
+		add(t.parents[parent], domain.FamilyRoleGrandparent)
+	}
+	for _, child := range t.children[id] {
+		add(t.children[child], domain.FamilyRoleGrandchild)
+	}
+	return relations
+}
+
+// MailingList monta a lista de correspondência com um destinatário por família: o chefe
+// e o cônjuge, no endereço da família ou, sem ele, no endereço do chefe. Entram apenas os
+// membros ativos; quem não pertence a nenhuma família recebe a própria correspondência.
+func (s *HouseholdService) MailingList(ctx context.Context, communityID string) ([]*HouseholdMailing, error) {
+	families, err := s.repos.Family.ListByCommunity(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar famílias: %v", err)
+	}
+	links, err := s.repos.Household.ListLinks(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros das famílias: %v", err)
+	}
+	members, err := s.repos.Household.ListActiveMembers(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros: %v", err)
+	}
+
+	active := make(map[string]*domain.Member, len(members))
+	for _, member := range members {
+		active[member.ID] = member
+	}
+	households := make(map[string][]*domain.Member)
+	inHousehold := make(map[string]bool)
+	for _, link := range links {
+		if member, ok := active[link.MemberID]; ok && !inHousehold[member.ID] {
+			households[link.FamilyID] = append(households[link.FamilyID], member)
+			inHousehold[member.ID] = true
+		}
+	}
+
+	mailing := []*HouseholdMailing{}
+	for _, family := range families {
+		residents := households[family.ID]
+		if len(residents) == 0 {
+			continue
+		}
+		mailing = append(mailing, householdMailing(family, residents))
+	}
+	for _, member := range members {
+		if !inHousehold[member.ID] {
+			mailing = append(mailing, householdMailing(nil, []*domain.Member{member}))
+		}
+	}
+
+	sort.SliceStable(mailing, func(i, j int) bool {
+		return strings.ToLower(mailing[i].Addressee) < strings.ToLower(mailing[j].Addressee)
+	})
+	return mailing, nil
+}
+
+// householdMailing monta o destinatário da família. O chefe inativo é substituído pelo
+// cônjuge ou pelo primeiro membro ativo.
+func householdMailing(family *domain.Family, residents []*domain.Member) *HouseholdMailing {
+	head := residents[0]
+	if family != nil {
+		for _, resident := range residents {
+			if resident.ID == family.HeadOfFamily {
+				head = resident
+			}
+		}
+	}
+	var spouse *domain.Member
+	for _, resident := range residents {
+		if resident != head && (sameID(head.SpouseID, &resident.ID) || sameID(resident.SpouseID, &head.ID)) {
+			spouse = resident
+			break
+		}
+	}
+
+	names := []string{head.Name}
+	if spouse != nil {
+		names = append(names, spouse.Name)
+	}
+	if len(residents) > len(names) {
+		names = append(names, "família")
+	}
+	addressee := names[0]
+	if len(names) > 1 {
+		addressee = strings.Join(names[:len(names)-1], ", ") + " e " + names[len(names)-1]
+	}
+
+	row := &HouseholdMailing{Addressee: addressee}
+	for _, resident := range residents {
+		row.MemberIDs = append(row.MemberIDs, resident.ID)
+	}
+	if family != nil {
+		familyID := family.ID
+		row.FamilyID = &familyID
+		row.FamilyName = family.Name
+		if family.HasAddress() {
+			row.Address, row.Number, row.Neighborhood = family.Address, family.Number, family.Neighborhood
+			row.City, row.State, row.Country, row.ZipCode = family.City, family.State, family.Country, family.ZipCode
+			return row
+		}
+	}
+
+	// Sem endereço da família, usa o do chefe ou o do primeiro membro com endereço
+	source := head
+	if source.Address == "" && source.ZipCode == "" {
+		for _, resident := range residents {
+			if resident.Address != "" || resident.ZipCode != "" {
+				source = resident
+				break
+			}
+		}
+	}
+	row.Address, row.Number, row.Neighborhood = source.Address, source.Number, source.Neighborhood
+	row.City, row.State, row.Country, row.ZipCode = source.City, source.State, source.Country, source.ZipCode
+	return row
+}
+
+func findLink(links []*domain.FamilyMember, memberID string) *domain.FamilyMember {
+	for _, link := range links {
+		if link.MemberID == memberID {
+			return link
+		}
+	}
+	return nil
+}
+
+// sameID compara dois IDs opcionais; nil e vazio são equivalentes
+func sameID(a, b *string) bool {
+	value := func(id *string) string {
+		if id == nil {
+			return ""
+		}
+		return *id
+	}
+	return value(a) == value(b)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+// newFamilyEdit monta uma árvore de quatro gerações já carregada na edição:
+// avô e avó, pai e mãe, filho e neto, além de dois membros sem parentesco
+func newFamilyEdit() *householdEdit {
+	id := func(s string) *string { return &s }
+	members := []*domain.Member{
+		{ID: "avo", Name: "Avô", SpouseID: id("avoa")},
+		{ID: "avoa", Name: "Avó", SpouseID: id("avo")},
+		{ID: "pai", Name: "Pai", ParentID: id("avo"), SpouseID: id("mae")},
+		{ID: "mae", Name: "Mãe", SpouseID: id("pai")},
+		{ID: "filho", Name: "Filho", ParentID: id("pai")},
+		{ID: "neto", Name: "Neto", ParentID: id("filho")},
+		{ID: "ana", Name: "Ana"},
+		{ID: "bruno", Name: "Bruno"},
+	}
+
+	s := &HouseholdService{}
+	edit := s.newEdit("c1")
+	for _, member := range members {
+		edit.members[member.ID] = member
+	}
+	return edit
+}
+
+func TestHouseholdSetParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  string
+		parent  string
+		wantErr bool
+	}{
+		{name: "novo filho", member: "ana", parent: "pai"},
+		{name: "troca de pai", member: "neto", parent: "mae"},
+		{name: "pai de si mesmo", member: "ana", parent: "ana", wantErr: true},
+		{name: "cônjuge como pai", member: "pai", parent: "mae", wantErr: true},
+		{name: "neto como pai do avô", member: "avo", parent: "neto", wantErr: true},
+		{name: "filho como pai do pai", member: "pai", parent: "filho", wantErr: true},
+		{name: "neto como pai da avó, pelo cônjuge do avô", member: "avoa", parent: "neto", wantErr: true},
+	}
+
+	s := &HouseholdService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := newFamilyEdit()
+			member, parent := edit.members[tt.member], edit.members[tt.parent]
+			previous := member.ParentID
+
+			err := s.setParent(context.Background(), edit, member, parent)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRelationship) {
+					t.Fatalf("erro = %v; esperado %v", err, ErrInvalidRelationship)
+				}
+				if member.ParentID != previous || len(edit.change.Members) != 0 {
+					t.Errorf("parentesco alterado apesar do erro: pai %v, alterados %d", member.ParentID, len(edit.change.Members))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if member.ParentID == nil || *member.ParentID != tt.parent {
+				t.Errorf("pai = %v; esperado %s", member.ParentID, tt.parent)
+			}
+			if len(edit.change.Members) != 1 || edit.change.Members[0] != member {
+				t.Errorf("membros alterados = %v; esperado apenas %s", edit.change.Members, tt.member)
+			}
+		})
+	}
+}
+
+func TestHouseholdSetSpouse(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  string
+		spouse  string
+		wantErr bool
+	}{
+		{name: "casamento", member: "ana", spouse: "bruno"},
+		{name: "mesmo casamento", member: "pai", spouse: "mae"},
+		{name: "cônjuge de si mesmo", member: "ana", spouse: "ana", wantErr: true},
+		{name: "membro já casado", member: "pai", spouse: "ana", wantErr: true},
+		{name: "cônjuge já casado", member: "ana", spouse: "mae", wantErr: true},
+		{name: "cônjuge antepassado", member: "neto", spouse: "pai", wantErr: true},
+		{name: "cônjuge descendente", member: "filho", spouse: "neto", wantErr: true},
+	}
+
+	s := &HouseholdService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := newFamilyEdit()
+			member, spouse := edit.members[tt.member], edit.members[tt.spouse]
+
+			err := s.setSpouse(context.Background(), edit, member, spouse)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRelationship) {
+					t.Fatalf("erro = %v; esperado %v", err, ErrInvalidRelationship)
+				}
+				if len(edit.change.Members) != 0 {
+					t.Errorf("membros alterados apesar do erro: %d", len(edit.change.Members))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if member.SpouseID == nil || *member.SpouseID != spouse.ID || spouse.SpouseID == nil || *spouse.SpouseID != member.ID {
+				t.Errorf("cônjuges = %v e %v; esperado um do outro", member.SpouseID, spouse.SpouseID)
+			}
+		})
+	}
+}
+
+func TestHouseholdCheckNotAncestor(t *testing.T) {
+	tests := []struct {
+		member, ancestor string
+		want             bool
+	}{
+		{"neto", "filho", true},
+		{"neto", "avo", true},
+		{"neto", "avoa", true},
+		{"neto", "mae", true},
+		{"filho", "neto", false},
+		{"avo", "pai", false},
+		{"neto", "ana", false},
+	}
+
+	s := &HouseholdService{}
+	for _, tt := range tests {
+		edit := newFamilyEdit()
+		err := s.checkNotAncestor(context.Background(), edit, edit.members[tt.member], edit.members[tt.ancestor], "ciclo")
+		if got := errors.Is(err, ErrInvalidRelationship); got != tt.want {
+			t.Errorf("%s é antepassado de %s = %v (erro %v); esperado %v", tt.ancestor, tt.member, got, err, tt.want)
+		}
+	}
+}
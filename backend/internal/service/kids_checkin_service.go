@@ -0,0 +1,600 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/pdf"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidKidsCheckIn    = errors.New("dados do check-in infantil inválidos")
+	ErrChildAlreadyCheckedIn = errors.New("criança já está com check-in ativo neste evento")
+	ErrClassroomNotFound     = errors.New("sala do ministério infantil não encontrada")
+	ErrNoClassroomAvailable  = errors.New("nenhuma sala disponível para a criança")
+	ErrInvalidSecurityCode   = errors.New("código de segurança inválido")
+)
+
+// Código de segurança impresso nas etiquetas. O alfabeto não tem caracteres que se
+// confundem na leitura (0/O, 1/I, 5/S, 8/B, 2/Z).
+const (
+	securityCodeAlphabet = "ACDEFHJKLMNPRTUVWXY3479"
+	securityCodeLength   = 4
+)
+
+// Etiquetas de 4 x 2 polegadas, o tamanho padrão das impressoras térmicas de check-in
+const (
+	zplLabelWidth  = 812 // pontos a 203 dpi
+	zplLabelHeight = 406
+)
+
+// KidsCheckInChild é uma criança no check-in. Sem ClassroomID, a sala é escolhida pela
+// idade da criança na data do evento. Alergias e observações médicas informadas
+// atualizam o cadastro da criança.
+type KidsCheckInChild struct {
+	MemberID     string
+	ClassroomID  *string
+	Allergies    *string
+	MedicalNotes *string
+}
+
+// KidsCheckInInput é o check-in das crianças trazidas por um responsável. O responsável
+// pode ser um membro (GuardianID) ou apenas um nome e telefone.
+type KidsCheckInInput struct {
+	GuardianID    *string
+	GuardianName  string
+	GuardianPhone string
+	Children      []KidsCheckInChild
+}
+
+// KidsCheckInResult retorna o código de segurança compartilhado pelas crianças e pelo responsável
+type KidsCheckInResult struct {
+	SecurityCode string                 `json:"security_code"`
+	GuardianName string                 `json:"guardian_name"`
+	CheckIns     []*domain.ChildCheckIn `json:"check_ins"`
+}
+
+// KidsClassroom é uma sala do ministério infantil com as crianças presentes no evento
+type KidsClassroom struct {
+	Classroom *domain.Group `json:"classroom"`
+	CheckedIn int64         `json:"checked_in"`
+	// Vagas restantes; nulo quando a sala não tem limite
+	Available *int64 `json:"available"`
+}
+
+// KidsLabels reúne os dados impressos nas etiquetas das crianças e do responsável
+type KidsLabels struct {
+	Community    *domain.Community
+	Event        *domain.Event
+	SecurityCode string
+	CheckIns     []*domain.ChildCheckIn
+}
+
+// KidsCheckInService controla a entrada e a retirada das crianças no ministério infantil
+type KidsCheckInService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewKidsCheckInService(repos *repository.Repositories, logger *zap.Logger) *KidsCheckInService {
+	return &KidsCheckInService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+func (s *KidsCheckInService) findEvent(ctx context.Context, communityID, eventID string) (*domain.Event, error) {
+	event, err := s.repos.Event.FindByID(ctx, communityID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar evento: %v", err)
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// Classrooms lista as salas do ministério infantil com as crianças presentes no evento
+func (s *KidsCheckInService) Classrooms(ctx context.Context, communityID, eventID string) ([]*KidsClassroom, error) {
+	if _, err := s.findEvent(ctx, communityID, eventID); err != nil {
+		return nil, err
+	}
+	classrooms, err := s.repos.KidsCheckIn.ListClassrooms(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar salas: %v", err)
+	}
+	counts, err := s.repos.KidsCheckIn.CountActiveByClassroom(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar crianças por sala: %v", err)
+	}
+
+	result := make([]*KidsClassroom, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		item := &KidsClassroom{Classroom: classroom, CheckedIn: counts[classroom.ID]}
+		if classroom.MaxMembers > 0 {
+			available := int64(classroom.MaxMembers) - item.CheckedIn
+			if available < 0 {
+				available = 0
+			}
+			item.Available = &available
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// List lista as crianças do evento. status filtra as presentes (active) ou as já
+// retiradas (checked_out).
+func (s *KidsCheckInService) List(ctx context.Context, communityID, eventID, status, classroomID string) ([]*domain.ChildCheckIn, error) {
+	if status != "" && status != "active" && status != "checked_out" {
+		return nil, fmt.Errorf("%w: situação deve ser active ou checked_out", ErrInvalidKidsCheckIn)
+	}
+	if _, err := s.findEvent(ctx, communityID, eventID); err != nil {
+		return nil, err
+	}
+
+	checkIns, err := s.repos.KidsCheckIn.List(ctx, communityID, eventID, &repository.KidsCheckInFilter{
+		Status:      status,
+		ClassroomID: classroomID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar check-ins infantis: %v", err)
+	}
+	return checkIns, nil
+}
+
+// CheckIn registra a entrada das crianças, distribui cada uma em uma sala e gera o
+// código de segurança que o responsável apresenta na retirada. Cada criança também
+// entra na lista de check-ins do evento.
+func (s *KidsCheckInService) CheckIn(ctx context.Context, communityID, eventID string, input *KidsCheckInInput) (*KidsCheckInResult, error) {
+	event, err := s.findEvent(ctx, communityID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Children) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos uma criança", ErrInvalidKidsCheckIn)
+	}
+
+	guardianName := strings.TrimSpace(input.GuardianName)
+	guardianPhone := strings.TrimSpace(input.GuardianPhone)
+	if input.GuardianID != nil && *input.GuardianID != "" {
+		guardian, err := s.repos.Member.FindByID(ctx, communityID, *input.GuardianID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar responsável: %v", err)
+		}
+		if guardian == nil {
+			return nil, ErrMemberNotFound
+		}
+		if guardianName == "" {
+			guardianName = guardian.Name
+		}
+		if guardianPhone == "" {
+			guardianPhone = guardian.Phone
+		}
+	} else {
+		input.GuardianID = nil
+	}
+	if guardianName == "" {
+		return nil, fmt.Errorf("%w: informe o responsável pela criança", ErrInvalidKidsCheckIn)
+	}
+
+	classrooms, err := s.repos.KidsCheckIn.ListClassrooms(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar salas: %v", err)
+	}
+	counts, err := s.repos.KidsCheckIn.CountActiveByClassroom(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar crianças por sala: %v", err)
+	}
+
+	now := time.Now()
+	session := &repository.KidsCheckInSession{CommunityID: communityID, EventID: eventID}
+	seen := make(map[string]bool, len(input.Children))
+	for _, item := range input.Children {
+		if seen[item.MemberID] {
+			return nil, fmt.Errorf("%w: criança informada mais de uma vez", ErrInvalidKidsCheckIn)
+		}
+		seen[item.MemberID] = true
+		if input.GuardianID != nil && *input.GuardianID == item.MemberID {
+			return nil, fmt.Errorf("%w: o responsável não pode ser a própria criança", ErrInvalidKidsCheckIn)
+		}
+
+		child, err := s.repos.Member.FindByID(ctx, communityID, item.MemberID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar criança: %v", err)
+		}
+		if child == nil {
+			return nil, ErrMemberNotFound
+		}
+
+		classroom, err := s.classroomFor(classrooms, counts, child, event.StartDate, item.ClassroomID)
+		if err != nil {
+			return nil, err
+		}
+		counts[classroom.ID]++
+
+		// Alergias e observações informadas na entrada passam a valer no cadastro
+		changed := false
+		if item.Allergies != nil && strings.TrimSpace(*item.Allergies) != child.Allergies {
+			child.Allergies = strings.TrimSpace(*item.Allergies)
+			changed = true
+		}
+		if item.MedicalNotes != nil && strings.TrimSpace(*item.MedicalNotes) != child.MedicalNotes {
+			child.MedicalNotes = strings.TrimSpace(*item.MedicalNotes)
+			changed = true
+		}
+		if changed {
+			session.Members = append(session.Members, child)
+		}
+
+		memberID := child.ID
+		session.Attendances = append(session.Attendances, &domain.CheckIn{
+			EventID:   eventID,
+			MemberID:  &memberID,
+			Name:      child.Name,
+			Email:     child.Email,
+			Phone:     child.Phone,
+			City:      child.City,
+			Source:    "kids",
+			Consent:   true, // O consentimento do responsável vale para a criança
+			CheckInAt: now,
+		})
+		classroomID := classroom.ID
+		session.CheckIns = append(session.CheckIns, &domain.ChildCheckIn{
+			CommunityID:   communityID,
+			EventID:       eventID,
+			ChildID:       child.ID,
+			GuardianID:    input.GuardianID,
+			GuardianName:  guardianName,
+			GuardianPhone: guardianPhone,
+			ClassroomID:   &classroomID,
+			Allergies:     child.Allergies,
+			MedicalNotes:  child.MedicalNotes,
+			CheckedInAt:   now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Child:         child,
+			Classroom:     classroom,
+		})
+	}
+
+	if err := s.repos.KidsCheckIn.Create(ctx, session, newSecurityCode); err != nil {
+		if errors.Is(err, repository.ErrChildCheckedIn) {
+			return nil, ErrChildAlreadyCheckedIn
+		}
+		return nil, fmt.Errorf("erro ao registrar check-in infantil: %v", err)
+	}
+
+	return &KidsCheckInResult{
+		SecurityCode: session.CheckIns[0].SecurityCode,
+		GuardianName: guardianName,
+		CheckIns:     session.CheckIns,
+	}, nil
+}
+
+// classroomFor retorna a sala escolhida no check-in ou, sem escolha, a sala ativa de
+// faixa etária mais estreita que aceita a idade e o gênero da criança e ainda tem vagas
+func (s *KidsCheckInService) classroomFor(classrooms []*domain.Group, counts map[string]int64, child *domain.Member, date time.Time, classroomID *string) (*domain.Group, error) {
+	if classroomID != nil && *classroomID != "" {
+		for _, classroom := range classrooms {
+			if classroom.ID == *classroomID {
+				return classroom, nil
+			}
+		}
+		return nil, ErrClassroomNotFound
+	}
+
+	if child.BirthDate.IsZero() {
+		return nil, fmt.Errorf("%w: %s não tem data de nascimento cadastrada; escolha a sala", ErrNoClassroomAvailable, child.Name)
+	}
+
+	age := child.AgeAt(date)
+	var best *domain.Group
+	for _, classroom := range classrooms {
+		if (classroom.MinAge > 0 && age < classroom.MinAge) || (classroom.MaxAge > 0 && age > classroom.MaxAge) {
+			continue
+		}
+		if classroom.HasGenderRestriction() && classroom.Gender != child.Gender {
+			continue
+		}
+		if classroom.MaxMembers > 0 && counts[classroom.ID] >= int64(classroom.MaxMembers) {
+			continue
+		}
+		if best == nil || ageSpan(classroom) < ageSpan(best) {
+			best = classroom
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: nenhuma sala com vagas para %s (%d anos)", ErrNoClassroomAvailable, child.Name, age)
+	}
+	return best, nil
+}
+
+// ageSpan retorna a largura da faixa etária da sala; salas sem idade máxima ficam por último
+func ageSpan(classroom *domain.Group) int {
+	if classroom.MaxAge == 0 {
+		return 1000 - classroom.MinAge
+	}
+	return classroom.MaxAge - classroom.MinAge
+}
+
+// Checkout registra a retirada das crianças com o código de segurança do responsável.
+// Sem checkInIDs, todas as crianças presentes com o código são retiradas.
+func (s *KidsCheckInService) Checkout(ctx context.Context, communityID, eventID, code string, checkInIDs []string, pickedUpBy, userID string) ([]*domain.ChildCheckIn, error) {
+	if _, err := s.findEvent(ctx, communityID, eventID); err != nil {
+		return nil, err
+	}
+	code = normalizeSecurityCode(code)
+	if code == "" {
+		return nil, ErrInvalidSecurityCode
+	}
+
+	active, err := s.repos.KidsCheckIn.FindActiveByCode(ctx, communityID, eventID, code)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar check-ins infantis: %v", err)
+	}
+	if len(active) == 0 {
+		return nil, ErrInvalidSecurityCode
+	}
+
+	// Cada criança retirada precisa ter o mesmo código apresentado pelo responsável
+	selected := active
+	if len(checkInIDs) > 0 {
+		byID := make(map[string]*domain.ChildCheckIn, len(active))
+		for _, checkIn := range active {
+			byID[checkIn.ID] = checkIn
+		}
+		selected = nil
+		for _, id := range checkInIDs {
+			checkIn, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: o código não corresponde a todas as crianças informadas", ErrInvalidSecurityCode)
+			}
+			selected = append(selected, checkIn)
+			delete(byID, id)
+		}
+	}
+
+	now := time.Now()
+	ids := make([]string, 0, len(selected))
+	for _, checkIn := range selected {
+		ids = append(ids, checkIn.ID)
+	}
+	var checkedOutBy *string
+	if userID != "" {
+		checkedOutBy = &userID
+	}
+	pickedUpBy = strings.TrimSpace(pickedUpBy)
+	if pickedUpBy == "" {
+		pickedUpBy = selected[0].GuardianName
+	}
+
+	if err := s.repos.KidsCheckIn.Checkout(ctx, ids, now, checkedOutBy, pickedUpBy); err != nil {
+		if errors.Is(err, repository.ErrChildCheckedOut) {
+			return nil, fmt.Errorf("%w: criança já foi retirada", ErrInvalidSecurityCode)
+		}
+		return nil, fmt.Errorf("erro ao registrar retirada: %v", err)
+	}
+
+	for _, checkIn := range selected {
+		checkIn.CheckedOutAt = &now
+		checkIn.CheckedOutBy = checkedOutBy
+		checkIn.PickedUpBy = pickedUpBy
+		checkIn.UpdatedAt = now
+	}
+	return selected, nil
+}
+
+// Labels busca os dados das etiquetas das crianças presentes com o código de segurança
+func (s *KidsCheckInService) Labels(ctx context.Context, communityID, eventID, code string) (*KidsLabels, error) {
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, ErrCommunityNotFound
+	}
+	event, err := s.findEvent(ctx, communityID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	code = normalizeSecurityCode(code)
+	if code == "" {
+		return nil, ErrInvalidSecurityCode
+	}
+
+	checkIns, err := s.repos.KidsCheckIn.FindActiveByCode(ctx, communityID, eventID, code)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar check-ins infantis: %v", err)
+	}
+	if len(checkIns) == 0 {
+		return nil, ErrInvalidSecurityCode
+	}
+
+	return &KidsLabels{
+		Community:    community,
+		Event:        event,
+		SecurityCode: code,
+		CheckIns:     checkIns,
+	}, nil
+}
+
+// RenderLabelsZPL gera as etiquetas em ZPL para impressoras térmicas (203 dpi, 4 x 2
+// polegadas): uma por criança e, por último, a etiqueta de retirada do responsável
+func (s *KidsCheckInService) RenderLabelsZPL(labels *KidsLabels) []byte {
+	var b strings.Builder
+	footer := labelFooter(labels)
+
+	for _, checkIn := range labels.CheckIns {
+		b.WriteString(zplLabelStart())
+		zplField(&b, 30, 25, 56, 560, 1, "L", childName(checkIn))
+		fmt.Fprintf(&b, "^FO600,20^GB190,100,4^FS\n")
+		zplField(&b, 600, 42, 64, 190, 1, "C", labels.SecurityCode)
+		zplField(&b, 30, 95, 34, 560, 1, "L", classroomName(checkIn))
+		zplField(&b, 30, 145, 26, 750, 1, "L", "Responsável: "+guardianLine(checkIn))
+		y := 190
+		if checkIn.Allergies != "" {
+			// Faixa preta com o texto em negativo para chamar a atenção da equipe
+			fmt.Fprintf(&b, "^FO30,%d^GB752,56,56^FS\n", y)
+			fmt.Fprintf(&b, "^FO42,%d^A0N,34,34^FR^FB728,1,0,L^FD%s^FS\n", y+11, zplText("ALERGIA: "+checkIn.Allergies))
+			y += 68
+		}
+		if checkIn.MedicalNotes != "" {
+			zplField(&b, 30, y, 26, 752, 2, "L", "Obs.: "+checkIn.MedicalNotes)
+		}
+		zplField(&b, 30, 365, 22, 752, 1, "L", footer)
+		b.WriteString("^XZ\n")
+	}
+
+	b.WriteString(zplLabelStart())
+	zplField(&b, 30, 25, 30, 752, 1, "C", "RETIRADA DE CRIANÇAS")
+	zplField(&b, 30, 65, 28, 752, 1, "C", guardianLine(labels.CheckIns[0]))
+	fmt.Fprintf(&b, "^FO206,105^GB400,140,4^FS\n")
+	zplField(&b, 206, 130, 110, 400, 1, "C", labels.SecurityCode)
+	zplField(&b, 30, 265, 26, 752, 2, "C", strings.Join(childSummaries(labels.CheckIns), ", "))
+	zplField(&b, 30, 335, 22, 752, 1, "C", "Apresente esta etiqueta na retirada")
+	zplField(&b, 30, 365, 22, 752, 1, "C", footer)
+	b.WriteString("^XZ\n")
+
+	return []byte(b.String())
+}
+
+// RenderLabelsPDF gera as mesmas etiquetas em PDF, uma por página no tamanho da etiqueta
+func (s *KidsCheckInService) RenderLabelsPDF(labels *KidsLabels) ([]byte, error) {
+	doc := pdf.New()
+	width, height := pdf.MM(101.6), pdf.MM(50.8)
+	margin := 10.0
+	footer := labelFooter(labels)
+
+	for _, checkIn := range labels.CheckIns {
+		page := doc.AddPageSize(width, height)
+		page.StrokeRect(width-margin-72, 8, 72, 34, 1.5)
+		page.TextCenter(width-margin-36, 33, 20, true, labels.SecurityCode)
+		page.Text(margin, 24, 15, true, fitLabelText(childName(checkIn), 15, width-2*margin-80))
+		page.Text(margin, 40, 10, false, fitLabelText(classroomName(checkIn), 10, width-2*margin-80))
+		page.Text(margin, 56, 8, false, fitLabelText("Responsável: "+guardianLine(checkIn), 8, width-2*margin))
+		y := 64.0
+		if checkIn.Allergies != "" {
+			page.Rect(margin-2, y, width-2*margin+4, 16, 0.8)
+			page.Text(margin, y+11.5, 9, true, fitLabelText("ALERGIA: "+checkIn.Allergies, 9, width-2*margin))
+			y += 22
+		}
+		if checkIn.MedicalNotes != "" {
+			lines := pdf.WrapText("Obs.: "+checkIn.MedicalNotes, 8, width-2*margin)
+			for i, line := range lines {
+				if i == 2 {
+					break
+				}
+				y += 9
+				page.Text(margin, y, 8, false, line)
+			}
+		}
+		page.Text(margin, height-8, 7, false, fitLabelText(footer, 7, width-2*margin))
+	}
+
+	page := doc.AddPageSize(width, height)
+	center := width / 2
+	page.TextCenter(center, 18, 10, true, "RETIRADA DE CRIANÇAS")
+	page.TextCenter(center, 31, 9, false, fitLabelText(guardianLine(labels.CheckIns[0]), 9, width-2*margin))
+	page.StrokeRect(center-55, 38, 110, 44, 1.5)
+	page.TextCenter(center, 71, 30, true, labels.SecurityCode)
+	page.TextCenter(center, 96, 8, false, fitLabelText(strings.Join(childSummaries(labels.CheckIns), ", "), 8, width-2*margin))
+	page.TextCenter(center, 110, 7, false, "Apresente esta etiqueta na retirada")
+	page.TextCenter(center, height-8, 7, false, fitLabelText(footer, 7, width-2*margin))
+
+	return doc.Bytes()
+}
+
+// LabelsFilename retorna o nome do arquivo das etiquetas
+func LabelsFilename(labels *KidsLabels, extension string) string {
+	return fmt.Sprintf("etiquetas-%s.%s", strings.ToLower(labels.SecurityCode), extension)
+}
+
+// newSecurityCode sorteia um código de segurança com crypto/rand
+func newSecurityCode() (string, error) {
+	max := big.NewInt(int64(len(securityCodeAlphabet)))
+	code := make([]byte, securityCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("erro ao gerar código de segurança: %v", err)
+		}
+		code[i] = securityCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func normalizeSecurityCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+func childName(checkIn *domain.ChildCheckIn) string {
+	if checkIn.Child != nil {
+		return checkIn.Child.Name
+	}
+	return ""
+}
+
+func classroomName(checkIn *domain.ChildCheckIn) string {
+	if checkIn.Classroom != nil {
+		return checkIn.Classroom.Name
+	}
+	return "Sala não definida"
+}
+
+func guardianLine(checkIn *domain.ChildCheckIn) string {
+	if checkIn.GuardianPhone != "" {
+		return checkIn.GuardianName + " - " + checkIn.GuardianPhone
+	}
+	return checkIn.GuardianName
+}
+
+// childSummaries lista as crianças com as suas salas, para a etiqueta do responsável
+func childSummaries(checkIns []*domain.ChildCheckIn) []string {
+	summaries := make([]string, 0, len(checkIns))
+	for _, checkIn := range checkIns {
+		summaries = append(summaries, fmt.Sprintf("%s (%s)", childName(checkIn), classroomName(checkIn)))
+	}
+	return summaries
+}
+
+func labelFooter(labels *KidsLabels) string {
+	checkedInAt := labels.CheckIns[0].CheckedInAt.In(communityLocation(labels.Community))
+	return fmt.Sprintf("%s - %s", labels.Event.Title, checkedInAt.Format("02/01/2006 15:04"))
+}
+
+// fitLabelText corta o texto que não cabe na largura da etiqueta
+func fitLabelText(text string, size, width float64) string {
+	if pdf.TextWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+func zplLabelStart() string {
+	// ^CI28 habilita UTF-8 para os acentos
+	return fmt.Sprintf("^XA\n^CI28\n^PW%d\n^LL%d\n", zplLabelWidth, zplLabelHeight)
+}
+
+// zplField escreve um texto com a fonte escalável padrão, quebrando em até lines linhas
+// na largura informada. align é L (esquerda), C (centro) ou R (direita).
+func zplField(b *strings.Builder, x, y, size, width, lines int, align, text string) {
+	fmt.Fprintf(b, "^FO%d,%d^A0N,%d,%d^FB%d,%d,0,%s^FD%s^FS\n", x, y, size, size, width, lines, align, zplText(text))
+}
+
+// zplText remove os caracteres de comando do ZPL e as quebras de linha do texto
+func zplText(text string) string {
+	return strings.NewReplacer("^", " ", "~", " ", "\r", " ", "\n", " ").Replace(text)
+}
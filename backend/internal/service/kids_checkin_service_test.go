@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestClassroomFor(t *testing.T) {
+	eventDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	classrooms := []*domain.Group{
+		{ID: "bercario", Name: "Berçário", MaxAge: 2},
+		{ID: "kids", Name: "Kids", MinAge: 3, MaxAge: 10},
+		{ID: "primarios", Name: "Primários", MinAge: 6, MaxAge: 8, MaxMembers: 2},
+		{ID: "meninas", Name: "Meninas", MinAge: 9, MaxAge: 10, Gender: "female"},
+		{ID: "juniores", Name: "Juniores", MinAge: 9},
+	}
+	birth := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	chosen := "juniores"
+	missing := "inexistente"
+
+	tests := []struct {
+		name        string
+		child       domain.Member
+		counts      map[string]int64
+		classroomID *string
+		want        string
+		wantErr     error
+	}{
+		{"faixa mais estreita", domain.Member{BirthDate: birth(2019, 1, 10)}, nil, nil, "primarios", nil},
+		{"idade na data do evento", domain.Member{BirthDate: birth(2020, 3, 16)}, nil, nil, "kids", nil},
+		{"aniversário no dia do evento", domain.Member{BirthDate: birth(2020, 3, 15)}, nil, nil, "primarios", nil},
+		{"sala lotada", domain.Member{BirthDate: birth(2019, 1, 10)}, map[string]int64{"primarios": 2}, nil, "kids", nil},
+		{"sala restrita ao gênero", domain.Member{BirthDate: birth(2016, 5, 1), Gender: "female"}, nil, nil, "meninas", nil},
+		{"outro gênero", domain.Member{BirthDate: birth(2016, 5, 1), Gender: "male"}, nil, nil, "kids", nil},
+		{"sem idade máxima fica por último", domain.Member{BirthDate: birth(2015, 1, 1), Gender: "female"}, nil, nil, "juniores", nil},
+		{"sala escolhida no check-in", domain.Member{}, nil, &chosen, "juniores", nil},
+		{"sala escolhida inexistente", domain.Member{}, nil, &missing, "", ErrClassroomNotFound},
+		{"sem data de nascimento", domain.Member{Name: "Ana"}, nil, nil, "", ErrNoClassroomAvailable},
+		{"sala sem limite de vagas", domain.Member{BirthDate: birth(2024, 1, 1)}, map[string]int64{"bercario": 40}, nil, "bercario", nil},
+	}
+
+	s := &KidsCheckInService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classroom, err := s.classroomFor(classrooms, tt.counts, &tt.child, eventDate, tt.classroomID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v; esperado %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if classroom.ID != tt.want {
+				t.Errorf("sala = %s; esperado %s", classroom.ID, tt.want)
+			}
+		})
+	}
+
+	full := []*domain.Group{{ID: "bercario", MaxAge: 2, MaxMembers: 5}}
+	child := &domain.Member{Name: "Bia", BirthDate: birth(2025, 1, 1)}
+	if _, err := s.classroomFor(full, map[string]int64{"bercario": 5}, child, eventDate, nil); !errors.Is(err, ErrNoClassroomAvailable) {
+		t.Errorf("erro = %v; esperado %v", err, ErrNoClassroomAvailable)
+	}
+}
+
+func TestNewSecurityCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := newSecurityCode()
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if len(code) != securityCodeLength {
+			t.Fatalf("código %q com %d caracteres; esperado %d", code, len(code), securityCodeLength)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(securityCodeAlphabet, c) {
+				t.Fatalf("código %q com caractere fora do alfabeto: %q", code, c)
+			}
+		}
+		if normalizeSecurityCode(" "+strings.ToLower(code[:2])+" "+code[2:]) != code {
+			t.Fatalf("código %q digitado com espaços e minúsculas não confere", code)
+		}
+	}
+}
+
+func TestZplText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Ana Souza", "Ana Souza"},
+		{"Amendoim^XZ", "Amendoim XZ"},
+		{"~JA\r\nleite", " JA  leite"},
+	}
+
+	for _, tt := range tests {
+		if got := zplText(tt.text); got != tt.want {
+			t.Errorf("zplText(%q) = %q; esperado %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxImportRows limita o tamanho da planilha importada de uma só vez
+const maxImportRows = 5000
+
+var (
+	ErrUnsupportedImportFile   = errors.New("arquivo inválido: envie uma planilha CSV ou XLSX")
+	ErrEmptyImportFile         = errors.New("a planilha não possui linhas para importar")
+	ErrImportTooLarge          = fmt.Errorf("a planilha deve ter no máximo %d linhas", maxImportRows)
+	ErrInvalidImportMapping    = errors.New("mapeamento de colunas inválido")
+	ErrImportNameColumnMissing = errors.New("a planilha deve ter uma coluna com o nome do membro")
+	ErrInvalidDuplicatePolicy  = errors.New("on_duplicate deve ser skip ou update")
+	ErrMemberImportNotFound    = errors.New("importação não encontrada")
+	ErrImportErrorFileNotFound = errors.New("a importação não possui arquivo de erros")
+)
+
+// Tratamento dos membros que já existem na comunidade
+const (
+	ImportDuplicateSkip   = "skip"
+	ImportDuplicateUpdate = "update"
+)
+
+// Resultado de cada linha da importação
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionSkip   = "skip"
+	ImportActionError  = "error"
+)
+
+// MemberImportOptions são as opções escolhidas para a importação
+type MemberImportOptions struct {
+	Mapping     map[string]string
+	OnDuplicate string
+}
+
+// MemberImportRowResult é o resultado da importação de uma linha da planilha
+type MemberImportRowResult struct {
+	Row      int      `json:"row"`
+	Name     string   `json:"name"`
+	Action   string   `json:"action"`
+	MemberID string   `json:"member_id,omitempty"`
+	Family   string   `json:"family,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// MemberImportReport é o relatório da simulação da importação
+type MemberImportReport struct {
+	Format       string                  `json:"format"`
+	Columns      []string                `json:"columns"`
+	Mapping      map[string]string       `json:"mapping"`
+	Unmapped     []string                `json:"unmapped"`
+	Fields       []string                `json:"fields"`
+	TotalRows    int                     `json:"total_rows"`
+	CreatedCount int                     `json:"created_count"`
+	UpdatedCount int                     `json:"updated_count"`
+	SkippedCount int                     `json:"skipped_count"`
+	ErrorCount   int                     `json:"error_count"`
+	FamilyCount  int                     `json:"family_count"`
+	Rows         []MemberImportRowResult `json:"rows"`
+}
+
+// MemberImportService importa membros a partir de planilhas CSV ou XLSX, como as
+// exportadas por outros sistemas de gestão de igrejas. A simulação valida a planilha
+// sem gravar nada; a importação é processada em segundo plano.
+type MemberImportService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+	// Diretório dos arquivos de erros. Eles trazem CPF, telefone e email dos membros e
+	// por isso ficam fora da pasta pública de uploads, disponíveis apenas pelo download
+	// autenticado da importação.
+	dataDir string
+}
+
+func NewMemberImportService(repos *repository.Repositories, logger *zap.Logger, dataDir string) *MemberImportService {
+	return &MemberImportService{
+		repos:   repos,
+		logger:  logger,
+		dataDir: dataDir,
+	}
+}
+
+// DryRun simula a importação e informa o que aconteceria com cada linha
+func (s *MemberImportService) DryRun(ctx context.Context, communityID, filename string, data []byte, opts MemberImportOptions) (*MemberImportReport, error) {
+	job, err := s.prepare(communityID, filename, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MemberImportReport{
+		Format:   job.sheet.Format,
+		Columns:  job.sheet.Header,
+		Mapping:  job.mapping,
+		Unmapped: job.unmapped,
+		Fields:   MemberImportFields(),
+	}
+	if err := job.run(ctx, s.repos, true, func(result MemberImportRowResult) {
+		report.Rows = append(report.Rows, result)
+	}); err != nil {
+		return nil, err
+	}
+
+	report.TotalRows = len(job.sheet.Rows)
+	report.CreatedCount = job.created
+	report.UpdatedCount = job.updated
+	report.SkippedCount = job.skipped
+	report.ErrorCount = job.failed
+	report.FamilyCount = job.newFamilies
+	return report, nil
+}
+
+// Start valida a planilha, registra a importação e processa as linhas em segundo plano
+func (s *MemberImportService) Start(ctx context.Context, communityID, userID, filename string, data []byte, opts MemberImportOptions) (*domain.MemberImport, error) {
+	job, err := s.prepare(communityID, filename, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	memberImport := &domain.MemberImport{
+		CommunityID: communityID,
+		UserID:      userID,
+		Filename:    filepath.Base(filename),
+		Format:      job.sheet.Format,
+		Status:      "processing",
+		Mapping:     job.mapping,
+		OnDuplicate: job.onDuplicate,
+		TotalRows:   len(job.sheet.Rows),
+	}
+	if err := s.repos.MemberImport.Create(ctx, memberImport); err != nil {
+		return nil, fmt.Errorf("erro ao registrar importação: %v", err)
+	}
+
+	s.logger.Info("importação de membros iniciada",
+		zap.String("import_id", memberImport.ID),
+		zap.String("community_id", communityID),
+		zap.Int("rows", memberImport.TotalRows))
+
+	go s.process(context.Background(), memberImport, job)
+
+	return memberImport, nil
+}
+
+// Get busca uma importação da comunidade
+func (s *MemberImportService) Get(ctx context.Context, communityID, importID string) (*domain.MemberImport, error) {
+	memberImport, err := s.repos.MemberImport.FindByID(ctx, communityID, importID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar importação: %v", err)
+	}
+	if memberImport == nil {
+		return nil, ErrMemberImportNotFound
+	}
+	return memberImport, nil
+}
+
+// List lista as importações da comunidade, das mais recentes para as mais antigas
+func (s *MemberImportService) List(ctx context.Context, communityID string, filter *repository.Filter) ([]*domain.MemberImport, int64, error) {
+	imports, total, err := s.repos.MemberImport.List(ctx, communityID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar importações: %v", err)
+	}
+	return imports, total, nil
+}
+
+// ErrorFilePath retorna o caminho do arquivo CSV com as linhas rejeitadas
+func (s *MemberImportService) ErrorFilePath(ctx context.Context, communityID, importID string) (string, error) {
+	memberImport, err := s.Get(ctx, communityID, importID)
+	if err != nil {
+		return "", err
+	}
+	if !memberImport.HasErrorFile() {
+		return "", ErrImportErrorFileNotFound
+	}
+	path := filepath.Join(s.dataDir, memberImport.ErrorFile)
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrImportErrorFileNotFound
+	}
+	return path, nil
+}
+
+// prepare lê a planilha e resolve o mapeamento das colunas
+func (s *MemberImportService) prepare(communityID, filename string, data []byte, opts MemberImportOptions) (*memberImportJob, error) {
+	onDuplicate := opts.OnDuplicate
+	if onDuplicate == "" {
+		onDuplicate = ImportDuplicateSkip
+	}
+	if onDuplicate != ImportDuplicateSkip && onDuplicate != ImportDuplicateUpdate {
+		return nil, ErrInvalidDuplicatePolicy
+	}
+
+	sheet, err := parseMemberSheet(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	mapping, columns, unmapped, err := resolveMemberMapping(sheet.Header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memberImportJob{
+		communityID: communityID,
+		onDuplicate: onDuplicate,
+		sheet:       sheet,
+		mapping:     mapping,
+		columns:     columns,
+		unmapped:    unmapped,
+	}, nil
+}
+
+// process importa as linhas, atualizando o progresso, e grava o arquivo de erros
+func (s *MemberImportService) process(ctx context.Context, memberImport *domain.MemberImport, job *memberImportJob) {
+	logger := s.logger.With(zap.String("import_id", memberImport.ID), zap.String("community_id", memberImport.CommunityID))
+
+	// Um pânico no processamento não pode derrubar o servidor nem deixar a importação
+	// em andamento para sempre
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("pânico ao importar membros", zap.Any("panic", r), zap.Stack("stack"))
+			now := time.Now()
+			memberImport.Status = "failed"
+			memberImport.Error = "erro inesperado ao processar a planilha"
+			memberImport.FinishedAt = &now
+			if err := s.repos.MemberImport.Update(ctx, memberImport); err != nil {
+				logger.Error("erro ao finalizar importação", zap.Error(err))
+			}
+		}
+	}()
+
+	var rejected []MemberImportRowResult
+	processed := 0
+	err := job.run(ctx, s.repos, false, func(result MemberImportRowResult) {
+		processed++
+		if result.Action == ImportActionError {
+			rejected = append(rejected, result)
+		}
+		if processed%100 == 0 {
+			memberImport.ProcessedRows = processed
+			job.copyCounts(memberImport)
+			if err := s.repos.MemberImport.Update(ctx, memberImport); err != nil {
+				logger.Warn("erro ao atualizar progresso da importação", zap.Error(err))
+			}
+		}
+	})
+
+	now := time.Now()
+	memberImport.ProcessedRows = processed
+	memberImport.FinishedAt = &now
+	job.copyCounts(memberImport)
+	memberImport.Status = "completed"
+	if err != nil {
+		logger.Error("erro ao importar membros", zap.Error(err))
+		memberImport.Status = "failed"
+		memberImport.Error = err.Error()
+	}
+
+	if len(rejected) > 0 {
+		errorFile, err := s.writeErrorFile(memberImport, job.sheet, rejected)
+		if err != nil {
+			logger.Error("erro ao gravar arquivo de erros da importação", zap.Error(err))
+		} else {
+			memberImport.ErrorFile = errorFile
+		}
+	}
+
+	if err := s.repos.MemberImport.Update(ctx, memberImport); err != nil {
+		logger.Error("erro ao finalizar importação", zap.Error(err))
+		return
+	}
+
+	logger.Info("importação de membros concluída",
+		zap.String("status", memberImport.Status),
+		zap.Int("created", memberImport.CreatedCount),
+		zap.Int("updated", memberImport.UpdatedCount),
+		zap.Int("skipped", memberImport.SkippedCount),
+		zap.Int("errors", memberImport.ErrorCount))
+}
+
+// writeErrorFile grava as linhas rejeitadas como na planilha original, acrescidas do
+// número da linha e dos erros encontrados, para correção e nova importação
+func (s *MemberImportService) writeErrorFile(memberImport *domain.MemberImport, sheet *memberImportSheet, rejected []MemberImportRowResult) (string, error) {
+	rows := make(map[int][]string, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		rows[row.Number] = row.Values
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	writer := csv.NewWriter(&buf)
+	writer.Comma = ';'
+
+	header := append(append([]string{}, sheet.Header...), "linha", "erros")
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+	for _, result := range rejected {
+		record := make([]string, len(sheet.Header), len(sheet.Header)+2)
+		copy(record, rows[result.Row])
+		record = append(record, strconv.Itoa(result.Row), strings.Join(result.Errors, "; "))
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+
+	relative := filepath.Join("imports", memberImport.CommunityID, memberImport.ID+"-erros.csv")
+	path := filepath.Join(s.dataDir, relative)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return relative, nil
+}
+
+// memberImportJob guarda o estado do processamento de uma planilha
+type memberImportJob struct {
+	communityID string
+	onDuplicate string
+	sheet       *memberImportSheet
+	mapping     map[string]string
+	columns     map[int]string
+	unmapped    []string
+
+	// famílias da comunidade pelo nome normalizado
+	families map[string]*domain.Family
+	// linha em que cada email ou CPF apareceu pela primeira vez
+	seen map[string]int
+
+	created, updated, skipped, failed, newFamilies int
+}
+
+// run processa as linhas em ordem. Na simulação, nada é gravado e as famílias que
+// seriam criadas são apenas contadas.
+func (j *memberImportJob) run(ctx context.Context, repos *repository.Repositories, dryRun bool, onRow func(MemberImportRowResult)) error {
+	families, err := repos.Family.ListByCommunity(ctx, j.communityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar famílias: %v", err)
+	}
+	j.families = make(map[string]*domain.Family, len(families))
+	for _, family := range families {
+		j.families[normalizeHeader(family.Name)] = family
+	}
+	j.seen = make(map[string]int)
+
+	for _, row := range j.sheet.Rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := j.importRow(ctx, repos, row, dryRun)
+		if err != nil {
+			return err
+		}
+		switch result.Action {
+		case ImportActionCreate:
+			j.created++
+		case ImportActionUpdate:
+			j.updated++
+		case ImportActionSkip:
+			j.skipped++
+		case ImportActionError:
+			j.failed++
+		}
+		onRow(result)
+	}
+	return nil
+}
+
+// importRow valida e importa uma linha. Problemas nos dados da linha são devolvidos no
+// resultado; o erro interrompe a importação e indica falha no banco de dados.
+func (j *memberImportJob) importRow(ctx context.Context, repos *repository.Repositories, row memberImportRow, dryRun bool) (MemberImportRowResult, error) {
+	result := MemberImportRowResult{Row: row.Number}
+	values := rowValues(row, j.columns)
+	result.Name = values["name"]
+
+	// Valida os campos em um membro temporário antes de procurar duplicados
+	candidate := &domain.Member{}
+	result.Errors = j.apply(candidate, values)
+	if result.Name == "" {
+		result.Errors = append(result.Errors, "nome é obrigatório")
+	}
+	for _, key := range []string{"email:" + candidate.Email, "cpf:" + candidate.CPF} {
+		if strings.HasSuffix(key, ":") {
+			continue
+		}
+		if first, ok := j.seen[key]; ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s repetido na linha %d", strings.SplitN(key, ":", 2)[0], first))
+		} else {
+			j.seen[key] = row.Number
+		}
+	}
+	if len(result.Errors) > 0 {
+		result.Action = ImportActionError
+		return result, nil
+	}
+
+	var member *domain.Member
+	if candidate.Email != "" || candidate.CPF != "" {
+		existing, err := repos.Member.FindByEmailOrCPF(ctx, j.communityID, candidate.Email, candidate.CPF)
+		if err != nil {
+			return result, fmt.Errorf("erro ao buscar membro duplicado: %v", err)
+		}
+		member = existing
+	}
+
+	if member != nil {
+		result.MemberID = member.ID
+		if j.onDuplicate == ImportDuplicateSkip {
+			result.Action = ImportActionSkip
+			result.Errors = []string{"membro já cadastrado"}
+			return result, nil
+		}
+		result.Action = ImportActionUpdate
+		j.apply(member, values)
+	} else {
+		result.Action = ImportActionCreate
+		member = candidate
+		member.ID = uuid.New().String()
+		member.CommunityID = j.communityID
+		if member.Status == "" {
+			member.Status = "active"
+		}
+		if member.Type == "" {
+			member.Type = "regular"
+		}
+		member.Role = "member"
+		if member.JoinDate.IsZero() {
+			member.JoinDate = time.Now()
+		}
+		member.CreatedAt = time.Now()
+		member.UpdatedAt = time.Now()
+	}
+
+	familyMember, addToFamily, err := j.resolveFamily(ctx, repos, member, values, result.Action == ImportActionCreate, dryRun)
+	if err != nil {
+		return result, err
+	}
+	if familyMember != nil {
+		result.Family = values[importFieldFamily]
+	}
+
+	if dryRun {
+		if result.Action == ImportActionCreate {
+			result.MemberID = ""
+		}
+		return result, nil
+	}
+
+	if result.Action == ImportActionCreate {
+		if err := repos.Member.Create(ctx, member); err != nil {
+			return result, fmt.Errorf("erro ao criar membro: %v", err)
+		}
+		result.MemberID = member.ID
+	} else {
+		member.UpdatedAt = time.Now()
+		if err := repos.Member.Update(ctx, member); err != nil {
+			return result, fmt.Errorf("erro ao atualizar membro: %v", err)
+		}
+	}
+
+	if addToFamily {
+		if err := repos.Family.AddMember(ctx, familyMember); err != nil {
+			return result, fmt.Errorf("erro ao adicionar membro à família: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// apply atribui os valores da linha ao membro e devolve os erros de validação
+func (j *memberImportJob) apply(member *domain.Member, values map[string]string) []string {
+	var errs []string
+	for _, field := range MemberImportFields() {
+		value, ok := values[field]
+		setter := memberImportFields[field]
+		if !ok || setter == nil {
+			continue
+		}
+		if err := setter(member, value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", j.columnName(field), err))
+		}
+	}
+	return errs
+}
+
+// resolveFamily vincula o membro à família informada na planilha, criando-a quando não
+// existe. O primeiro membro importado em uma família nova é o chefe da família. Membros
+// que já pertencem a uma família permanecem nela. O vínculo devolvido só precisa ser
+// gravado quando é novo.
+func (j *memberImportJob) resolveFamily(ctx context.Context, repos *repository.Repositories, member *domain.Member, values map[string]string, isNew, dryRun bool) (*domain.FamilyMember, bool, error) {
+	name := values[importFieldFamily]
+	if name == "" {
+		return nil, false, nil
+	}
+
+	if !isNew {
+		current, err := repos.Family.FindByMemberID(ctx, member.ID)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao buscar família do membro: %v", err)
+		}
+		if current != nil {
+			member.FamilyID = &current.FamilyID
+			return current, false, nil
+		}
+	}
+
+	key := normalizeHeader(name)
+	family, ok := j.families[key]
+	if !ok {
+		family = &domain.Family{
+			CommunityID:  j.communityID,
+			Name:         name,
+			HeadOfFamily: member.ID,
+		}
+		if !dryRun {
+			if err := repos.Family.Create(ctx, family); err != nil {
+				return nil, false, fmt.Errorf("erro ao criar família: %v", err)
+			}
+		}
+		j.families[key] = family
+		j.newFamilies++
+	}
+
+	if member.FamilyRole == "" {
+		member.FamilyRole = domain.FamilyRoleOther
+	}
+	if family.ID != "" {
+		familyID := family.ID
+		member.FamilyID = &familyID
+	}
+	return &domain.FamilyMember{FamilyID: family.ID, MemberID: member.ID, Role: member.FamilyRole}, true, nil
+}
+
+// columnName retorna o nome da coluna da planilha mapeada para o campo
+func (j *memberImportJob) columnName(field string) string {
+	for column, mapped := range j.mapping {
+		if mapped == field {
+			return column
+		}
+	}
+	return field
+}
+
+// copyCounts copia os totais processados para o registro da importação
+func (j *memberImportJob) copyCounts(memberImport *domain.MemberImport) {
+	memberImport.CreatedCount = j.created
+	memberImport.UpdatedCount = j.updated
+	memberImport.SkippedCount = j.skipped
+	memberImport.ErrorCount = j.failed
+	memberImport.FamilyCount = j.newFamilies
+}
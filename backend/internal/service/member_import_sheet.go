@@ -0,0 +1,465 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/mail"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/pkg/validator"
+	"github.com/comunidade/backend/pkg/xlsx"
+)
+
+// Campos da importação que não são atribuídos diretamente ao membro
+const (
+	importFieldFamily     = "family"
+	importFieldFamilyRole = "family_role"
+)
+
+// memberImportSheet é a planilha lida: o cabeçalho e as linhas com conteúdo
+type memberImportSheet struct {
+	Format string
+	Header []string
+	Rows   []memberImportRow
+}
+
+// memberImportRow é uma linha da planilha com o número que ela tem no arquivo
+type memberImportRow struct {
+	Number int
+	Values []string
+}
+
+// memberFieldSetter valida o valor da célula e o atribui ao membro
+type memberFieldSetter func(member *domain.Member, value string) error
+
+// memberImportFields são os campos do membro aceitos no mapeamento de colunas
+var memberImportFields = map[string]memberFieldSetter{
+	"name":              setMemberText(func(m *domain.Member) *string { return &m.Name }, 255),
+	"email":             setMemberEmail,
+	"phone":             setMemberPhone(func(m *domain.Member) *string { return &m.Phone }),
+	"cpf":               setMemberCPF,
+	"birth_date":        setMemberBirthDate,
+	"gender":            setMemberGender,
+	"marital_status":    setMemberText(func(m *domain.Member) *string { return &m.MaritalStatus }, 20),
+	"occupation":        setMemberText(func(m *domain.Member) *string { return &m.Occupation }, 100),
+	"address":           setMemberText(func(m *domain.Member) *string { return &m.Address }, 0),
+	"number":            setMemberText(func(m *domain.Member) *string { return &m.Number }, 20),
+	"neighborhood":      setMemberText(func(m *domain.Member) *string { return &m.Neighborhood }, 100),
+	"city":              setMemberText(func(m *domain.Member) *string { return &m.City }, 100),
+	"state":             setMemberText(func(m *domain.Member) *string { return &m.State }, 100),
+	"country":           setMemberText(func(m *domain.Member) *string { return &m.Country }, 100),
+	"zip_code":          setMemberText(func(m *domain.Member) *string { return &m.ZipCode }, 20),
+	"notes":             setMemberText(func(m *domain.Member) *string { return &m.Notes }, 0),
+	"emergency_contact": setMemberText(func(m *domain.Member) *string { return &m.EmergencyContact }, 100),
+	"emergency_phone":   setMemberPhone(func(m *domain.Member) *string { return &m.EmergencyPhone }),
+	"type":              setMemberChoice(func(m *domain.Member) *string { return &m.Type }, memberTypeValues),
+	"status":            setMemberChoice(func(m *domain.Member) *string { return &m.Status }, memberStatusValues),
+	"join_date":         setMemberJoinDate,
+	"baptism_date":      setMemberDate(func(m *domain.Member) **time.Time { return &m.BaptismDate }),
+	"baptism_location":  setMemberText(func(m *domain.Member) *string { return &m.BaptismLocation }, 255),
+	"membership_date":   setMemberDate(func(m *domain.Member) **time.Time { return &m.MembershipDate }),
+	"membership_type":   setMemberText(func(m *domain.Member) *string { return &m.MembershipType }, 50),
+	"previous_church":   setMemberText(func(m *domain.Member) *string { return &m.PreviousChurch }, 255),
+	"wedding_date":      setMemberDate(func(m *domain.Member) **time.Time { return &m.WeddingDate }),
+	"ministry":          setMemberText(func(m *domain.Member) *string { return &m.Ministry }, 100),
+	"ministry_role":     setMemberText(func(m *domain.Member) *string { return &m.MinistryRole }, 100),
+	importFieldFamily:   nil,
+	importFieldFamilyRole: func(m *domain.Member, value string) error {
+		m.FamilyRole = familyRoleFromText(value)
+		return nil
+	},
+}
+
+// memberImportAliases relaciona os nomes de coluna mais comuns, sem acentos e em
+// minúsculas, aos campos do membro
+var memberImportAliases = map[string]string{
+	"nome": "name", "nome_completo": "name", "membro": "name",
+	"e_mail": "email", "email": "email",
+	"telefone": "phone", "celular": "phone", "fone": "phone", "whatsapp": "phone",
+	"cpf":        "cpf",
+	"nascimento": "birth_date", "data_nascimento": "birth_date", "data_de_nascimento": "birth_date", "aniversario": "birth_date",
+	"sexo": "gender", "genero": "gender",
+	"estado_civil": "marital_status",
+	"profissao":    "occupation", "ocupacao": "occupation",
+	"endereco": "address", "logradouro": "address", "rua": "address",
+	"numero": "number", "n": "number",
+	"bairro": "neighborhood",
+	"cidade": "city", "municipio": "city",
+	"estado": "state", "uf": "state",
+	"pais":        "country",
+	"cep":         "zip_code",
+	"observacoes": "notes", "observacao": "notes", "obs": "notes",
+	"contato_emergencia": "emergency_contact", "contato_de_emergencia": "emergency_contact",
+	"telefone_emergencia": "emergency_phone", "telefone_de_emergencia": "emergency_phone",
+	"tipo":         "type",
+	"situacao":     "status",
+	"data_entrada": "join_date", "data_de_entrada": "join_date", "data_ingresso": "join_date", "data_cadastro": "join_date",
+	"batismo": "baptism_date", "data_batismo": "baptism_date", "data_de_batismo": "baptism_date",
+	"local_batismo": "baptism_location", "local_de_batismo": "baptism_location",
+	"membresia": "membership_date", "data_membresia": "membership_date", "data_de_membresia": "membership_date", "data_recepcao": "membership_date",
+	"tipo_membresia": "membership_type", "forma_recepcao": "membership_type", "forma_de_recepcao": "membership_type",
+	"igreja_anterior": "previous_church", "igreja_de_origem": "previous_church",
+	"casamento": "wedding_date", "data_casamento": "wedding_date", "data_de_casamento": "wedding_date",
+	"ministerio": "ministry",
+	"funcao":     "ministry_role", "cargo": "ministry_role", "funcao_ministerio": "ministry_role",
+	"familia": "family", "nome_familia": "family", "nome_da_familia": "family",
+	"parentesco": "family_role", "papel_familia": "family_role", "papel_na_familia": "family_role", "vinculo_familiar": "family_role",
+}
+
+var memberTypeValues = map[string]string{
+	"regular": "regular", "membro": "regular", "member": "regular",
+	"visitor": "visitor", "visitante": "visitor", "congregado": "visitor",
+	"transferred": "transferred", "transferido": "transferred",
+}
+
+var memberStatusValues = map[string]string{
+	"active": "active", "ativo": "active",
+	"inactive": "inactive", "inativo": "inactive", "desligado": "inactive",
+	"pending": "pending", "pendente": "pending",
+	"blocked": "blocked", "bloqueado": "blocked",
+}
+
+var memberGenderValues = map[string]string{
+	"male": "male", "m": "male", "masculino": "male", "homem": "male",
+	"female": "female", "f": "female", "feminino": "female", "mulher": "female",
+	"other": "other", "outro": "other",
+}
+
+var familyRoleValues = map[string]string{
+	"conjuge": domain.FamilyRoleSpouse, "esposa": domain.FamilyRoleSpouse, "esposo": domain.FamilyRoleSpouse,
+	"marido": domain.FamilyRoleSpouse, "mulher": domain.FamilyRoleSpouse,
+	"filho": domain.FamilyRoleChild, "filha": domain.FamilyRoleChild, "filho_a": domain.FamilyRoleChild,
+	"irmao": domain.FamilyRoleSibling, "irma": domain.FamilyRoleSibling,
+	"pai": domain.FamilyRoleParent, "mae": domain.FamilyRoleParent,
+	"avo":  domain.FamilyRoleGrandparent,
+	"neto": domain.FamilyRoleGrandchild, "neta": domain.FamilyRoleGrandchild,
+	"tio": domain.FamilyRoleUncleAunt, "tia": domain.FamilyRoleUncleAunt,
+	"sobrinho": domain.FamilyRoleNephewNiece, "sobrinha": domain.FamilyRoleNephewNiece,
+	"primo": domain.FamilyRoleCousin, "prima": domain.FamilyRoleCousin,
+}
+
+var headerSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// MemberImportFields retorna os campos aceitos no mapeamento de colunas
+func MemberImportFields() []string {
+	fields := make([]string, 0, len(memberImportFields))
+	for field := range memberImportFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// parseMemberSheet lê a planilha CSV ou XLSX. O formato é detectado pelo conteúdo.
+func parseMemberSheet(filename string, data []byte) (*memberImportSheet, error) {
+	var records [][]string
+	format := "csv"
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) || strings.EqualFold(filepath.Ext(filename), ".xlsx") {
+		format = "xlsx"
+		rows, err := xlsx.ReadRows(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, ErrUnsupportedImportFile
+		}
+		records = rows
+	} else {
+		rows, err := readImportCSV(data)
+		if err != nil {
+			return nil, err
+		}
+		records = rows
+	}
+
+	sheet := &memberImportSheet{Format: format}
+	for i, record := range records {
+		if isBlankRecord(record) {
+			continue
+		}
+		if sheet.Header == nil {
+			sheet.Header = make([]string, len(record))
+			for j, name := range record {
+				sheet.Header[j] = strings.TrimSpace(name)
+			}
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, memberImportRow{Number: i + 1, Values: record})
+	}
+
+	if len(sheet.Rows) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+	if len(sheet.Rows) > maxImportRows {
+		return nil, ErrImportTooLarge
+	}
+	return sheet, nil
+}
+
+// readImportCSV lê o CSV exportado por planilhas ou sistemas antigos: aceita ponto e
+// vírgula, vírgula ou tabulação como separador e arquivos em UTF-8 ou Latin-1
+func readImportCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectSeparator(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedImportFile, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func detectSeparator(line []byte) rune {
+	separator, best := ',', bytes.Count(line, []byte(","))
+	for _, candidate := range []rune{';', '\t'} {
+		if count := bytes.Count(line, []byte(string(candidate))); count > best {
+			separator, best = candidate, count
+		}
+	}
+	return separator
+}
+
+func latin1ToUTF8(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveMemberMapping associa as colunas da planilha aos campos do membro. O
+// mapeamento informado (nome da coluna para campo; campo vazio ignora a coluna)
+// prevalece sobre a identificação automática pelo nome da coluna.
+func resolveMemberMapping(header []string, custom map[string]string) (map[string]string, map[int]string, []string, error) {
+	normalizedCustom := make(map[string]string, len(custom))
+	for column, field := range custom {
+		if field != "" {
+			if _, ok := memberImportFields[field]; !ok {
+				return nil, nil, nil, fmt.Errorf("%w: campo %q desconhecido para a coluna %q", ErrInvalidImportMapping, field, column)
+			}
+		}
+		normalizedCustom[normalizeHeader(column)] = field
+	}
+
+	mapping := make(map[string]string)
+	columns := make(map[int]string)
+	used := make(map[string]bool)
+	var unmapped []string
+	for i, name := range header {
+		key := normalizeHeader(name)
+		field, ok := normalizedCustom[key]
+		if !ok {
+			field = memberImportAliases[key]
+			if field == "" {
+				if _, known := memberImportFields[key]; known {
+					field = key
+				}
+			}
+		}
+		if field == "" || used[field] {
+			if name != "" {
+				unmapped = append(unmapped, name)
+			}
+			continue
+		}
+		used[field] = true
+		mapping[name] = field
+		columns[i] = field
+	}
+
+	if !used["name"] {
+		return nil, nil, nil, ErrImportNameColumnMissing
+	}
+	return mapping, columns, unmapped, nil
+}
+
+// normalizeHeader converte o nome da coluna para minúsculas sem acentos, com as
+// palavras separadas por sublinhado
+func normalizeHeader(name string) string {
+	name = strings.ToLower(pixAccents.Replace(strings.TrimSpace(name)))
+	return strings.Trim(headerSeparators.ReplaceAllString(name, "_"), "_")
+}
+
+// rowValues extrai os valores da linha por campo, sem espaços nas extremidades
+func rowValues(row memberImportRow, columns map[int]string) map[string]string {
+	values := make(map[string]string, len(columns))
+	for index, field := range columns {
+		if index < len(row.Values) {
+			if value := strings.TrimSpace(row.Values[index]); value != "" {
+				values[field] = value
+			}
+		}
+	}
+	return values
+}
+
+func setMemberText(field func(*domain.Member) *string, max int) memberFieldSetter {
+	return func(m *domain.Member, value string) error {
+		if max > 0 && utf8.RuneCountInString(value) > max {
+			return fmt.Errorf("deve ter no máximo %d caracteres", max)
+		}
+		*field(m) = value
+		return nil
+	}
+}
+
+func setMemberEmail(m *domain.Member, value string) error {
+	email := normalizeImportEmail(value)
+	if _, err := mail.ParseAddress(email); err != nil || strings.ContainsAny(email, " <>") {
+		return fmt.Errorf("email inválido")
+	}
+	m.Email = email
+	return nil
+}
+
+func setMemberPhone(field func(*domain.Member) *string) memberFieldSetter {
+	return func(m *domain.Member, value string) error {
+		phone := normalizeImportPhone(value)
+		if err := validator.ValidatePhone(phone); err != nil {
+			return err
+		}
+		*field(m) = phone
+		return nil
+	}
+}
+
+func setMemberCPF(m *domain.Member, value string) error {
+	cpf := onlyDigits(value)
+	if err := validator.ValidateCPF(cpf); err != nil {
+		return err
+	}
+	m.CPF = cpf
+	return nil
+}
+
+func setMemberBirthDate(m *domain.Member, value string) error {
+	date, err := parseImportDate(value)
+	if err != nil {
+		return err
+	}
+	if date.After(time.Now()) {
+		return fmt.Errorf("data de nascimento no futuro")
+	}
+	m.BirthDate = date
+	return nil
+}
+
+func setMemberJoinDate(m *domain.Member, value string) error {
+	date, err := parseImportDate(value)
+	if err != nil {
+		return err
+	}
+	m.JoinDate = date
+	return nil
+}
+
+func setMemberDate(field func(*domain.Member) **time.Time) memberFieldSetter {
+	return func(m *domain.Member, value string) error {
+		date, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		*field(m) = &date
+		return nil
+	}
+}
+
+func setMemberGender(m *domain.Member, value string) error {
+	gender, ok := memberGenderValues[normalizeHeader(value)]
+	if !ok {
+		return fmt.Errorf("valor %q não reconhecido", value)
+	}
+	m.Gender = gender
+	return nil
+}
+
+func setMemberChoice(field func(*domain.Member) *string, values map[string]string) memberFieldSetter {
+	return func(m *domain.Member, value string) error {
+		choice, ok := values[normalizeHeader(value)]
+		if !ok {
+			return fmt.Errorf("valor %q não reconhecido", value)
+		}
+		*field(m) = choice
+		return nil
+	}
+}
+
+// familyRoleFromText converte o parentesco informado no papel da família. Papéis não
+// reconhecidos são registrados como outro.
+func familyRoleFromText(value string) string {
+	key := normalizeHeader(value)
+	if role, ok := familyRoleValues[key]; ok {
+		return role
+	}
+	switch key {
+	case domain.FamilyRoleSpouse, domain.FamilyRoleChild, domain.FamilyRoleSibling, domain.FamilyRoleParent,
+		domain.FamilyRoleGrandparent, domain.FamilyRoleGrandchild, domain.FamilyRoleUncleAunt,
+		domain.FamilyRoleNephewNiece, domain.FamilyRoleCousin:
+		return key
+	}
+	return domain.FamilyRoleOther
+}
+
+// importDateLayouts são os formatos de data aceitos, além do número serial do Excel
+var importDateLayouts = []string{"02/01/2006", "2/1/2006", "02-01-2006", "02.01.2006", "2006-01-02", "02/01/06", "2006-01-02 15:04:05", time.RFC3339}
+
+// parseImportDate lê a data no formato brasileiro, ISO ou serial do Excel (XLSX)
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, fmt.Errorf("data %q inválida: use DD/MM/AAAA", value)
+}
+
+func normalizeImportEmail(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// normalizeImportPhone mantém apenas os dígitos e remove o código do Brasil
+func normalizeImportPhone(value string) string {
+	phone := onlyDigits(value)
+	if len(phone) > 11 && strings.HasPrefix(phone, "55") {
+		phone = phone[2:]
+	}
+	return phone
+}
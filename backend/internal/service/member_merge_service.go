@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMergeSameMember = errors.New("não é possível unificar o membro com ele mesmo")
+	ErrMergeConflict   = errors.New("os membros possuem CPFs diferentes e não podem ser unificados")
+)
+
+// DefaultDuplicateScore é a pontuação mínima para dois cadastros serem sugeridos como
+// duplicados
+const DefaultDuplicateScore = 40
+
+// maxDuplicateBucket ignora chaves compartilhadas por muitos membros (ex.: o telefone
+// da secretaria), que gerariam pares demais sem indicar duplicidade
+const maxDuplicateBucket = 50
+
+// MemberDuplicate é um par de cadastros que provavelmente são da mesma pessoa
+type MemberDuplicate struct {
+	Member    *domain.Member `json:"member"`
+	Duplicate *domain.Member `json:"duplicate"`
+	Score     int            `json:"score"`
+	Reasons   []string       `json:"reasons"`
+}
+
+// MemberMergeService encontra cadastros duplicados de membros e os unifica
+type MemberMergeService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewMemberMergeService(repos *repository.Repositories, logger *zap.Logger) *MemberMergeService {
+	return &MemberMergeService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// FindDuplicates compara os membros da comunidade que compartilham CPF, email, telefone
+// ou nome e devolve os pares com pontuação mínima, dos mais para os menos prováveis
+func (s *MemberMergeService) FindDuplicates(ctx context.Context, communityID string, minScore int) ([]*MemberDuplicate, error) {
+	members, err := s.repos.Member.ListByCommunity(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros: %v", err)
+	}
+	if minScore <= 0 {
+		minScore = DefaultDuplicateScore
+	}
+
+	keys := make([]memberMatchKey, len(members))
+	buckets := make(map[string][]int)
+	for i, member := range members {
+		keys[i] = newMemberMatchKey(member)
+		for _, bucket := range keys[i].buckets() {
+			buckets[bucket] = append(buckets[bucket], i)
+		}
+	}
+
+	compared := make(map[[2]int]bool)
+	var duplicates []*MemberDuplicate
+	for _, indexes := range buckets {
+		if len(indexes) < 2 || len(indexes) > maxDuplicateBucket {
+			continue
+		}
+		for a := 0; a < len(indexes); a++ {
+			for b := a + 1; b < len(indexes); b++ {
+				pair := [2]int{indexes[a], indexes[b]}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				score, reasons := scoreMemberPair(keys[pair[0]], keys[pair[1]])
+				if score < minScore {
+					continue
+				}
+				// O cadastro mais antigo é sugerido como o que deve ser mantido
+				member, duplicate := members[pair[0]], members[pair[1]]
+				if duplicate.CreatedAt.Before(member.CreatedAt) {
+					member, duplicate = duplicate, member
+				}
+				duplicates = append(duplicates, &MemberDuplicate{
+					Member:    member,
+					Duplicate: duplicate,
+					Score:     score,
+					Reasons:   reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].Member.Name < duplicates[j].Member.Name
+	})
+	return duplicates, nil
+}
+
+// Merge unifica o cadastro duplicado no membro mantido. Presenças, check-ins, grupos,
+// família, doações, postagens e pedidos de oração passam para o membro mantido, os
+// dados que faltam nele são completados com os do duplicado e o duplicado é excluído.
+func (s *MemberMergeService) Merge(ctx context.Context, communityID, survivorID, duplicateID, userID string) (*domain.MemberMerge, error) {
+	if survivorID == duplicateID {
+		return nil, ErrMergeSameMember
+	}
+
+	survivor, err := s.repos.Member.FindByID(ctx, communityID, survivorID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	duplicate, err := s.repos.Member.FindByID(ctx, communityID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if survivor == nil || duplicate == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	survivorKey, duplicateKey := newMemberMatchKey(survivor), newMemberMatchKey(duplicate)
+	if survivorKey.cpf != "" && duplicateKey.cpf != "" && survivorKey.cpf != duplicateKey.cpf {
+		return nil, ErrMergeConflict
+	}
+	score, reasons := scoreMemberPair(survivorKey, duplicateKey)
+
+	snapshot, err := memberSnapshot(duplicate)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar dados do membro: %v", err)
+	}
+
+	merge := &domain.MemberMerge{
+		CommunityID:    communityID,
+		SurvivorID:     survivor.ID,
+		MergedMemberID: duplicate.ID,
+		MergedName:     duplicate.Name,
+		MergedBy:       userID,
+		Score:          score,
+		Reasons:        reasons,
+		CopiedFields:   combineMembers(survivor, duplicate),
+		Snapshot:       snapshot,
+		CreatedAt:      time.Now(),
+	}
+	survivor.UpdatedAt = time.Now()
+
+	if err := s.repos.MemberMerge.Merge(ctx, merge, survivor); err != nil {
+		return nil, fmt.Errorf("erro ao unificar membros: %v", err)
+	}
+
+	s.logger.Info("membros unificados",
+		zap.String("community_id", communityID),
+		zap.String("survivor_id", survivor.ID),
+		zap.String("merged_member_id", duplicate.ID),
+		zap.String("user_id", userID),
+		zap.Any("moved_records", merge.MovedRecords))
+
+	return merge, nil
+}
+
+// ListMerges lista o histórico de unificações da comunidade
+func (s *MemberMergeService) ListMerges(ctx context.Context, communityID string, filter *repository.Filter) ([]*domain.MemberMerge, int64, error) {
+	merges, total, err := s.repos.MemberMerge.List(ctx, communityID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar unificações: %v", err)
+	}
+	return merges, total, nil
+}
+
+// memberMatchKey guarda os dados normalizados do membro usados na comparação
+type memberMatchKey struct {
+	name      string
+	tokens    []string
+	email     string
+	phone     string
+	cpf       string
+	birthDate string
+}
+
+// nameParticles são as preposições dos nomes brasileiros, ignoradas na comparação
+var nameParticles = map[string]bool{"da": true, "de": true, "do": true, "das": true, "dos": true, "e": true}
+
+func newMemberMatchKey(member *domain.Member) memberMatchKey {
+	key := memberMatchKey{
+		email: normalizeImportEmail(member.Email),
+		cpf:   onlyDigits(member.CPF),
+	}
+
+	name := strings.ToLower(pixAccents.Replace(member.Name))
+	for _, token := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if !nameParticles[token] {
+			key.tokens = append(key.tokens, token)
+		}
+	}
+	key.name = strings.Join(key.tokens, " ")
+
+	// Compara os 8 últimos dígitos, com ou sem DDD e o nono dígito
+	if phone := normalizeImportPhone(member.Phone); len(phone) >= 8 {
+		key.phone = phone[len(phone)-8:]
+	}
+	if !member.BirthDate.IsZero() && member.BirthDate.Year() > 1900 {
+		key.birthDate = member.BirthDate.Format("2006-01-02")
+	}
+	return key
+}
+
+// buckets são as chaves que aproximam os membros candidatos a duplicados, para não
+// comparar todos os pares da comunidade
+func (k memberMatchKey) buckets() []string {
+	var buckets []string
+	if k.cpf != "" {
+		buckets = append(buckets, "cpf:"+k.cpf)
+	}
+	if k.email != "" {
+		buckets = append(buckets, "email:"+k.email)
+	}
+	if k.phone != "" {
+		buckets = append(buckets, "phone:"+k.phone)
+	}
+	if len(k.tokens) > 0 {
+		first, last := k.tokens[0], k.tokens[len(k.tokens)-1]
+		buckets = append(buckets, "name:"+first+" "+last)
+		if k.birthDate != "" {
+			buckets = append(buckets, "birth:"+k.birthDate+" "+first)
+		}
+	}
+	return buckets
+}
+
+// scoreMemberPair pontua de 0 a 100 a chance de dois cadastros serem da mesma pessoa
+func scoreMemberPair(a, b memberMatchKey) (int, []string) {
+	// CPFs diferentes indicam pessoas diferentes, ainda que com o mesmo nome
+	if a.cpf != "" && b.cpf != "" && a.cpf != b.cpf {
+		return 0, nil
+	}
+
+	score := 0
+	var reasons []string
+	if a.cpf != "" && a.cpf == b.cpf {
+		score += 60
+		reasons = append(reasons, "mesmo CPF")
+	}
+	if a.email != "" && a.email == b.email {
+		score += 40
+		reasons = append(reasons, "mesmo email")
+	}
+	if a.phone != "" && a.phone == b.phone {
+		score += 25
+		reasons = append(reasons, "mesmo telefone")
+	}
+
+	switch {
+	case a.name != "" && a.name == b.name:
+		score += 35
+		reasons = append(reasons, "mesmo nome")
+	case len(a.tokens) > 1 && len(b.tokens) > 1 &&
+		a.tokens[0] == b.tokens[0] && a.tokens[len(a.tokens)-1] == b.tokens[len(b.tokens)-1]:
+		score += 25
+		reasons = append(reasons, "mesmo nome e sobrenome")
+	case a.name != "" && b.name != "" && levenshtein(a.name, b.name) <= 2:
+		score += 25
+		reasons = append(reasons, "nome parecido")
+	case tokenSimilarity(a.tokens, b.tokens) >= 0.6:
+		score += 15
+		reasons = append(reasons, "nome parecido")
+	}
+
+	if a.birthDate != "" && b.birthDate != "" {
+		if a.birthDate == b.birthDate {
+			score += 20
+			reasons = append(reasons, "mesma data de nascimento")
+		} else {
+			score -= 30
+		}
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, reasons
+}
+
+// tokenSimilarity é a proporção de palavras em comum entre os dois nomes
+func tokenSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, token := range a {
+		set[token] = true
+	}
+	common := 0
+	union := len(set)
+	for _, token := range b {
+		if set[token] {
+			common++
+			delete(set, token)
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// levenshtein é a quantidade de letras inseridas, removidas ou trocadas entre os textos
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// memberSnapshot converte o membro removido para o registro da unificação
+func memberSnapshot(member *domain.Member) (map[string]interface{}, error) {
+	data, err := json.Marshal(member)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// memberRoleRank ordena os papéis do membro para manter o de maior permissão
+var memberRoleRank = map[string]int{"member": 0, "leader": 1, "admin": 2}
+
+// combineMembers completa o membro mantido com os dados do duplicado e soma os
+// indicadores de participação. Retorna os campos copiados do duplicado.
+func combineMembers(survivor, duplicate *domain.Member) []string {
+	var copied []string
+
+	texts := []struct {
+		name        string
+		target, src *string
+	}{
+		{"user_id", &survivor.UserID, &duplicate.UserID},
+		{"email", &survivor.Email, &duplicate.Email},
+		{"phone", &survivor.Phone, &duplicate.Phone},
+		{"cpf", &survivor.CPF, &duplicate.CPF},
+		{"photo", &survivor.Photo, &duplicate.Photo},
+		{"gender", &survivor.Gender, &duplicate.Gender},
+		{"marital_status", &survivor.MaritalStatus, &duplicate.MaritalStatus},
+		{"occupation", &survivor.Occupation, &duplicate.Occupation},
+		{"address", &survivor.Address, &duplicate.Address},
+		{"number", &survivor.Number, &duplicate.Number},
+		{"neighborhood", &survivor.Neighborhood, &duplicate.Neighborhood},
+		{"city", &survivor.City, &duplicate.City},
+		{"state", &survivor.State, &duplicate.State},
+		{"country", &survivor.Country, &duplicate.Country},
+		{"zip_code", &survivor.ZipCode, &duplicate.ZipCode},
+		{"emergency_contact", &survivor.EmergencyContact, &duplicate.EmergencyContact},
+		{"emergency_phone", &survivor.EmergencyPhone, &duplicate.EmergencyPhone},
+		{"allergies", &survivor.Allergies, &duplicate.Allergies},
+		{"medical_notes", &survivor.MedicalNotes, &duplicate.MedicalNotes},
+		{"asaas_customer_id", &survivor.AsaasCustomerID, &duplicate.AsaasCustomerID},
+		{"ministry", &survivor.Ministry, &duplicate.Ministry},
+		{"ministry_role", &survivor.MinistryRole, &duplicate.MinistryRole},
+		{"family_role", &survivor.FamilyRole, &duplicate.FamilyRole},
+		{"baptism_location", &survivor.BaptismLocation, &duplicate.BaptismLocation},
+		{"membership_type", &survivor.MembershipType, &duplicate.MembershipType},
+		{"previous_church", &survivor.PreviousChurch, &duplicate.PreviousChurch},
+		{"transferred_from", &survivor.TransferredFrom, &duplicate.TransferredFrom},
+		{"transferred_to", &survivor.TransferredTo, &duplicate.TransferredTo},
+	}
+	for _, field := range texts {
+		if *field.target == "" && *field.src != "" {
+			*field.target = *field.src
+			copied = append(copied, field.name)
+		}
+	}
+
+	// O acesso ao portal do membro acompanha o email copiado
+	if survivor.Password == "" && duplicate.Password != "" {
+		survivor.Password = duplicate.Password
+		copied = append(copied, "password")
+	}
+
+	dates := []struct {
+		name        string
+		target, src **time.Time
+	}{
+		{"baptism_date", &survivor.BaptismDate, &duplicate.BaptismDate},
+		{"membership_date", &survivor.MembershipDate, &duplicate.MembershipDate},
+		{"ministry_start_date", &survivor.MinistryStartDate, &duplicate.MinistryStartDate},
+		{"transfer_date", &survivor.TransferDate, &duplicate.TransferDate},
+		{"wedding_date", &survivor.WeddingDate, &duplicate.WeddingDate},
+	}
+	for _, field := range dates {
+		if (*field.target == nil || (*field.target).IsZero()) && *field.src != nil && !(*field.src).IsZero() {
+			*field.target = *field.src
+			copied = append(copied, field.name)
+		}
+	}
+
+	if survivor.BirthDate.IsZero() && !duplicate.BirthDate.IsZero() {
+		survivor.BirthDate = duplicate.BirthDate
+		copied = append(copied, "birth_date")
+	}
+	if !duplicate.JoinDate.IsZero() && duplicate.JoinDate.Before(survivor.JoinDate) {
+		survivor.JoinDate = duplicate.JoinDate
+		copied = append(copied, "join_date")
+	}
+
+	ids := []struct {
+		name        string
+		target, src **string
+	}{
+		{"family_id", &survivor.FamilyID, &duplicate.FamilyID},
+		{"spouse_id", &survivor.SpouseID, &duplicate.SpouseID},
+		{"parent_id", &survivor.ParentID, &duplicate.ParentID},
+	}
+	for _, field := range ids {
+		if *field.target == nil && *field.src != nil && **field.src != survivor.ID {
+			*field.target = *field.src
+			copied = append(copied, field.name)
+		}
+		// Os vínculos entre os dois cadastros deixam de existir
+		if *field.target != nil && (**field.target == duplicate.ID || **field.target == survivor.ID) && field.name != "family_id" {
+			*field.target = nil
+		}
+	}
+
+	// Quem ainda não está no caminho de integração assume a etapa do outro cadastro
+	if survivor.PipelineStageID == nil && duplicate.PipelineStageID != nil {
+		survivor.PipelineStageID = duplicate.PipelineStageID
+		survivor.StageEnteredAt = duplicate.StageEnteredAt
+		copied = append(copied, "pipeline_stage_id")
+	}
+
+	if duplicate.Notes != "" && !strings.Contains(survivor.Notes, duplicate.Notes) {
+		if survivor.Notes != "" {
+			survivor.Notes += "\n\n"
+		}
+		survivor.Notes += duplicate.Notes
+		copied = append(copied, "notes")
+	}
+	if skills := mergeStrings(survivor.Skills, duplicate.Skills); len(skills) > len(survivor.Skills) {
+		survivor.Skills = skills
+		copied = append(copied, "skills")
+	}
+	if interests := mergeStrings(survivor.Interests, duplicate.Interests); len(interests) > len(survivor.Interests) {
+		survivor.Interests = interests
+		copied = append(copied, "interests")
+	}
+
+	// O visitante que se tornou membro mantém a situação do cadastro mais completo
+	if survivor.Type == "visitor" && duplicate.Type != "visitor" {
+		survivor.Type = duplicate.Type
+		copied = append(copied, "type")
+	}
+	if survivor.Status != "active" && duplicate.Status == "active" {
+		survivor.Status = duplicate.Status
+		copied = append(copied, "status")
+	}
+	if memberRoleRank[duplicate.Role] > memberRoleRank[survivor.Role] {
+		survivor.Role = duplicate.Role
+		copied = append(copied, "role")
+	}
+	survivor.IsVolunteer = survivor.IsVolunteer || duplicate.IsVolunteer
+
+	survivor.AttendanceCount += duplicate.AttendanceCount
+	survivor.ContributionCount += duplicate.ContributionCount
+	survivor.TotalContributions += duplicate.TotalContributions
+	survivor.LastAttendanceAt = latestTime(survivor.LastAttendanceAt, duplicate.LastAttendanceAt)
+	survivor.LastContributionAt = latestTime(survivor.LastContributionAt, duplicate.LastContributionAt)
+	survivor.LastLogin = latestTime(survivor.LastLogin, duplicate.LastLogin)
+
+	return copied
+}
+
+func mergeStrings(a, b []string) []string {
+	result := append([]string{}, a...)
+	seen := make(map[string]bool, len(a))
+	for _, value := range a {
+		seen[strings.ToLower(value)] = true
+	}
+	for _, value := range b {
+		if !seen[strings.ToLower(value)] {
+			seen[strings.ToLower(value)] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func latestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestScoreMemberPair(t *testing.T) {
+	birth := time.Date(1990, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		a, b        domain.Member
+		wantScore   int
+		wantReasons []string
+	}{
+		{
+			name:        "mesmo CPF com e sem formatação",
+			a:           domain.Member{Name: "Pedro Alves", CPF: "123.456.789-01"},
+			b:           domain.Member{Name: "Pedro Alves", CPF: "12345678901"},
+			wantScore:   95,
+			wantReasons: []string{"mesmo CPF", "mesmo nome"},
+		},
+		{
+			name:      "CPFs diferentes",
+			a:         domain.Member{Name: "Pedro Alves", CPF: "12345678901", Email: "pedro@email.com"},
+			b:         domain.Member{Name: "Pedro Alves", CPF: "10987654321", Email: "pedro@email.com"},
+			wantScore: 0,
+		},
+		{
+			name:        "acentos, preposições, email e telefone sem DDD",
+			a:           domain.Member{Name: "João da Silva", Email: "Joao@Email.com", Phone: "(11) 98765-4321"},
+			b:           domain.Member{Name: "Joao Silva", Email: "joao@email.com", Phone: "98765-4321"},
+			wantScore:   100,
+			wantReasons: []string{"mesmo email", "mesmo telefone", "mesmo nome"},
+		},
+		{
+			name:        "mesmo nome e sobrenome",
+			a:           domain.Member{Name: "Maria Aparecida Santos"},
+			b:           domain.Member{Name: "Maria Santos"},
+			wantScore:   25,
+			wantReasons: []string{"mesmo nome e sobrenome"},
+		},
+		{
+			name:        "nome com erro de digitação",
+			a:           domain.Member{Name: "Marcos Oliveira"},
+			b:           domain.Member{Name: "Marcos Olivera"},
+			wantScore:   25,
+			wantReasons: []string{"nome parecido"},
+		},
+		{
+			name:        "nomes com palavras em comum",
+			a:           domain.Member{Name: "Ana Clara Lima Rocha"},
+			b:           domain.Member{Name: "Clara Lima Rocha"},
+			wantScore:   15,
+			wantReasons: []string{"nome parecido"},
+		},
+		{
+			name:        "mesmo nome e mesma data de nascimento",
+			a:           domain.Member{Name: "Lucas Ferreira", BirthDate: birth},
+			b:           domain.Member{Name: "Lucas Ferreira", BirthDate: birth},
+			wantScore:   55,
+			wantReasons: []string{"mesmo nome", "mesma data de nascimento"},
+		},
+		{
+			name:        "mesmo nome e datas de nascimento diferentes",
+			a:           domain.Member{Name: "Lucas Ferreira", BirthDate: birth},
+			b:           domain.Member{Name: "Lucas Ferreira", BirthDate: birth.AddDate(30, 0, 0)},
+			wantScore:   5,
+			wantReasons: []string{"mesmo nome"},
+		},
+		{
+			name:      "pessoas diferentes",
+			a:         domain.Member{Name: "Carla Mendes", Email: "carla@email.com"},
+			b:         domain.Member{Name: "Roberto Nunes", Email: "roberto@email.com"},
+			wantScore: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := scoreMemberPair(newMemberMatchKey(&tt.a), newMemberMatchKey(&tt.b))
+			if score != tt.wantScore {
+				t.Errorf("pontuação = %d; esperado %d", score, tt.wantScore)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("motivos = %q; esperado %q", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestTokenSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want float64
+	}{
+		{nil, []string{"ana"}, 0},
+		{[]string{"ana", "lima"}, []string{"ana", "lima"}, 1},
+		{[]string{"ana", "clara", "lima", "rocha"}, []string{"clara", "lima", "rocha"}, 0.75},
+		{[]string{"ana", "lima"}, []string{"bruno", "costa"}, 0},
+		{[]string{"ana", "ana"}, []string{"ana"}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tokenSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("tokenSimilarity(%q, %q) = %v; esperado %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "ana", 3},
+		{"marcos", "marcos", 0},
+		{"oliveira", "olivera", 1},
+		{"souza", "sousa", 1},
+		{"joão", "joao", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d; esperado %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
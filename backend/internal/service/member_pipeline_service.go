@@ -0,0 +1,653 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrStageNotFound         = errors.New("etapa não encontrada")
+	ErrInvalidPipelineStage  = errors.New("etapa inválida")
+	ErrStageInUse            = errors.New("existem membros nesta etapa")
+	ErrFollowUpTaskNotFound  = errors.New("tarefa não encontrada")
+	ErrInvalidFollowUpTask   = errors.New("tarefa inválida")
+	ErrInvalidTaskAssignee   = errors.New("o responsável deve ser um líder ou administrador ativo da comunidade")
+	ErrFollowUpTaskCompleted = errors.New("a tarefa já foi concluída ou cancelada")
+
+	ErrVisitorCheckInNotFound = errors.New("check-in de visitante não encontrado")
+	ErrVisitorCheckInReviewed = errors.New("o check-in do visitante já foi confirmado ou descartado")
+)
+
+// stuckMembersLimit é a quantidade de membros parados listada por etapa no painel
+const stuckMembersLimit = 20
+
+// PipelineStageSummary resume os membros de uma etapa no painel do caminho de integração
+type PipelineStageSummary struct {
+	Stage        *domain.PipelineStage `json:"stage"`
+	Members      int64                 `json:"members"`
+	Stuck        int64                 `json:"stuck"`
+	StuckMembers []*domain.Member      `json:"stuck_members"`
+}
+
+// PipelineDashboard é o painel do caminho de integração da comunidade
+type PipelineDashboard struct {
+	Stages       []*PipelineStageSummary `json:"stages"`
+	PendingTasks int64                   `json:"pending_tasks"`
+	OverdueTasks int64                   `json:"overdue_tasks"`
+	GeneratedAt  time.Time               `json:"generated_at"`
+}
+
+// MemberPipelineService conduz os membros pelas etapas do caminho de integração
+// (visitante, frequentador, membro), registra o histórico de cada mudança de etapa,
+// tipo ou situação e mantém as tarefas de acompanhamento dos líderes.
+type MemberPipelineService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewMemberPipelineService(repos *repository.Repositories, logger *zap.Logger) *MemberPipelineService {
+	return &MemberPipelineService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// ListStages lista as etapas da comunidade na ordem do caminho
+func (s *MemberPipelineService) ListStages(ctx context.Context, communityID string) ([]*domain.PipelineStage, error) {
+	stages, err := s.repos.MemberPipeline.ListStages(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar etapas: %v", err)
+	}
+	return stages, nil
+}
+
+// CreateStage cria uma etapa no caminho de integração
+func (s *MemberPipelineService) CreateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	if err := s.validateStage(ctx, stage); err != nil {
+		return err
+	}
+	stage.CreatedAt = time.Now()
+	stage.UpdatedAt = time.Now()
+	if err := s.repos.MemberPipeline.CreateStage(ctx, stage); err != nil {
+		return fmt.Errorf("erro ao criar etapa: %v", err)
+	}
+	return s.keepSingleVisitorEntry(ctx, stage)
+}
+
+// UpdateStage altera a configuração da etapa. Os membros que já estão nela não são afetados.
+func (s *MemberPipelineService) UpdateStage(ctx context.Context, communityID, stageID string, changes *domain.PipelineStage) (*domain.PipelineStage, error) {
+	stage, err := s.findStage(ctx, communityID, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes.CommunityID = communityID
+	if err := s.validateStage(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	stage.Name = changes.Name
+	stage.Description = changes.Description
+	stage.Position = changes.Position
+	stage.MemberType = changes.MemberType
+	stage.MemberStatus = changes.MemberStatus
+	stage.MaxDays = changes.MaxDays
+	stage.IsVisitorEntry = changes.IsVisitorEntry
+	stage.FollowUpTitle = changes.FollowUpTitle
+	stage.FollowUpDays = changes.FollowUpDays
+	stage.FollowUpAssigneeID = changes.FollowUpAssigneeID
+	stage.UpdatedAt = time.Now()
+	if err := s.repos.MemberPipeline.UpdateStage(ctx, stage); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar etapa: %v", err)
+	}
+	if err := s.keepSingleVisitorEntry(ctx, stage); err != nil {
+		return nil, err
+	}
+	return stage, nil
+}
+
+// DeleteStage exclui a etapa, desde que não haja membros nela
+func (s *MemberPipelineService) DeleteStage(ctx context.Context, communityID, stageID string) error {
+	if _, err := s.findStage(ctx, communityID, stageID); err != nil {
+		return err
+	}
+	count, err := s.repos.MemberPipeline.CountMembersInStage(ctx, communityID, stageID)
+	if err != nil {
+		return fmt.Errorf("erro ao contar membros da etapa: %v", err)
+	}
+	if count > 0 {
+		return ErrStageInUse
+	}
+	if err := s.repos.MemberPipeline.DeleteStage(ctx, communityID, stageID); err != nil {
+		return fmt.Errorf("erro ao excluir etapa: %v", err)
+	}
+	return nil
+}
+
+// MoveMember coloca o membro na etapa, aplicando o tipo e a situação configurados nela,
+// registra a mudança no histórico e cria a tarefa de acompanhamento da etapa. As tarefas
+// pendentes das etapas anteriores são canceladas.
+func (s *MemberPipelineService) MoveMember(ctx context.Context, communityID, memberID, stageID string, changedBy *string, notes string) (*domain.Member, error) {
+	member, err := s.repos.Member.FindByID(ctx, communityID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	stage, err := s.findStage(ctx, communityID, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enterStage(ctx, member, stage, domain.StatusChangeSourcePipeline, changedBy, notes); err != nil {
+		return nil, err
+	}
+	if err := s.repos.MemberPipeline.CancelPendingTasks(ctx, communityID, member.ID, stage.ID); err != nil {
+		return nil, fmt.Errorf("erro ao cancelar tarefas anteriores: %v", err)
+	}
+	return member, nil
+}
+
+// ListVisitorCheckIns lista os check-ins de visitantes dos eventos da comunidade,
+// filtrando pela situação da revisão quando informada
+func (s *MemberPipelineService) ListVisitorCheckIns(ctx context.Context, communityID, status string) ([]domain.CheckIn, error) {
+	checkIns, err := s.repos.CheckIn.ListVisitors(ctx, communityID, status)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar check-ins de visitantes: %v", err)
+	}
+	return checkIns, nil
+}
+
+// ConfirmVisitor confirma o check-in pendente de um visitante, feito na rota pública,
+// associando-o ao membro escolhido pelo administrador. Sem membro escolhido, o visitante
+// é procurado pelo email e pelo telefone informados no check-in e, se não for
+// encontrado, é cadastrado. O visitante que ainda não está no caminho entra na etapa
+// de entrada dos visitantes.
+func (s *MemberPipelineService) ConfirmVisitor(ctx context.Context, communityID string, checkInID uint, memberID *string, reviewedBy string) (*domain.CheckIn, error) {
+	var checkIn *domain.CheckIn
+	err := s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		tx := &MemberPipelineService{repos: repos, logger: s.logger}
+
+		var err error
+		checkIn, err = tx.lockPendingVisitor(ctx, communityID, checkInID)
+		if err != nil {
+			return err
+		}
+
+		member, created, err := tx.findOrCreateVisitor(ctx, communityID, checkIn, memberID)
+		if err != nil {
+			return err
+		}
+		if err := tx.enterVisitorStage(ctx, member, created, "Check-in no evento "+checkIn.EventID, reviewedBy); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		checkIn.MemberID = &member.ID
+		checkIn.VisitorStatus = domain.CheckInVisitorConfirmed
+		checkIn.ReviewedBy = &reviewedBy
+		checkIn.ReviewedAt = &now
+		checkIn.UpdatedAt = now
+		if err := repos.CheckIn.Update(ctx, checkIn); err != nil {
+			return fmt.Errorf("erro ao atualizar check-in: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+// DismissVisitor descarta o check-in pendente de um visitante sem criar cadastro, como
+// nos check-ins de teste ou feitos por engano
+func (s *MemberPipelineService) DismissVisitor(ctx context.Context, communityID string, checkInID uint, reviewedBy string) (*domain.CheckIn, error) {
+	var checkIn *domain.CheckIn
+	err := s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		tx := &MemberPipelineService{repos: repos, logger: s.logger}
+
+		var err error
+		checkIn, err = tx.lockPendingVisitor(ctx, communityID, checkInID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		checkIn.VisitorStatus = domain.CheckInVisitorDismissed
+		checkIn.ReviewedBy = &reviewedBy
+		checkIn.ReviewedAt = &now
+		checkIn.UpdatedAt = now
+		if err := repos.CheckIn.Update(ctx, checkIn); err != nil {
+			return fmt.Errorf("erro ao atualizar check-in: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+func (s *MemberPipelineService) lockPendingVisitor(ctx context.Context, communityID string, checkInID uint) (*domain.CheckIn, error) {
+	checkIn, err := s.repos.CheckIn.LockVisitor(ctx, communityID, checkInID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVisitorCheckInNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar check-in: %v", err)
+	}
+	if checkIn.VisitorStatus != domain.CheckInVisitorPending {
+		return nil, ErrVisitorCheckInReviewed
+	}
+	return checkIn, nil
+}
+
+// findOrCreateVisitor retorna o membro do check-in do visitante e se ele foi cadastrado
+// agora. O membro escolhido pelo administrador tem prioridade sobre o email e o
+// telefone informados pelo visitante.
+func (s *MemberPipelineService) findOrCreateVisitor(ctx context.Context, communityID string, checkIn *domain.CheckIn, memberID *string) (*domain.Member, bool, error) {
+	if memberID != nil {
+		member, err := s.repos.Member.FindByID(ctx, communityID, *memberID)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao buscar membro: %v", err)
+		}
+		if member == nil {
+			return nil, false, ErrMemberNotFound
+		}
+		return member, false, nil
+	}
+
+	email := strings.TrimSpace(checkIn.Email)
+	phone := strings.TrimSpace(checkIn.Phone)
+	if email != "" {
+		member, err := s.repos.Member.FindByEmail(ctx, communityID, email)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao buscar membro: %v", err)
+		}
+		if member != nil {
+			return member, false, nil
+		}
+	}
+	if phone != "" {
+		member, err := s.repos.Member.FindByEmailOrPhone(ctx, communityID, phone)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao buscar membro: %v", err)
+		}
+		if member != nil {
+			return member, false, nil
+		}
+	}
+
+	now := time.Now()
+	member := &domain.Member{
+		CommunityID:  communityID,
+		Name:         strings.TrimSpace(checkIn.Name),
+		Email:        email,
+		Phone:        phone,
+		City:         checkIn.City,
+		Neighborhood: checkIn.District,
+		Type:         "visitor",
+		Status:       "active",
+		Role:         "member",
+		JoinDate:     now,
+		Notes:        "Cadastrado pelo check-in de visitante",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repos.Member.Create(ctx, member); err != nil {
+		return nil, false, fmt.Errorf("erro ao cadastrar visitante: %v", err)
+	}
+	return member, true, nil
+}
+
+// enterVisitorStage coloca o visitante na etapa de entrada do caminho. Quem já está no
+// caminho ou já é membro regular segue na etapa em que está; sem etapa de entrada, o
+// cadastro novo fica apenas registrado no histórico.
+func (s *MemberPipelineService) enterVisitorStage(ctx context.Context, member *domain.Member, created bool, notes, reviewedBy string) error {
+	if member.PipelineStageID != nil || member.Type != "visitor" {
+		return nil
+	}
+
+	stage, err := s.repos.MemberPipeline.FindVisitorStage(ctx, member.CommunityID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar etapa de entrada dos visitantes: %v", err)
+	}
+	switch {
+	case stage != nil:
+		return s.enterStage(ctx, member, stage, domain.StatusChangeSourceCheckIn, &reviewedBy, notes)
+	case created:
+		if err := s.repos.MemberPipeline.CreateHistory(ctx, &domain.MemberStatusHistory{
+			CommunityID: member.CommunityID,
+			MemberID:    member.ID,
+			ToType:      member.Type,
+			ToStatus:    member.Status,
+			Source:      domain.StatusChangeSourceCheckIn,
+			ChangedBy:   &reviewedBy,
+			Notes:       notes,
+			CreatedAt:   time.Now(),
+		}); err != nil {
+			return fmt.Errorf("erro ao registrar histórico do visitante: %v", err)
+		}
+	}
+	return nil
+}
+
+// enterStage grava o membro na etapa com o histórico e a tarefa de acompanhamento
+func (s *MemberPipelineService) enterStage(ctx context.Context, member *domain.Member, stage *domain.PipelineStage, source string, changedBy *string, notes string) error {
+	now := time.Now()
+	history := &domain.MemberStatusHistory{
+		CommunityID: member.CommunityID,
+		MemberID:    member.ID,
+		FromStageID: member.PipelineStageID,
+		ToStageID:   &stage.ID,
+		FromType:    member.Type,
+		ToType:      member.Type,
+		FromStatus:  member.Status,
+		ToStatus:    member.Status,
+		Source:      source,
+		ChangedBy:   changedBy,
+		Notes:       notes,
+		CreatedAt:   now,
+	}
+	if stage.MemberType != "" {
+		history.ToType = stage.MemberType
+	}
+	if stage.MemberStatus != "" {
+		history.ToStatus = stage.MemberStatus
+	}
+
+	var task *domain.FollowUpTask
+	if stage.HasFollowUp() {
+		location, err := s.location(ctx, member.CommunityID)
+		if err != nil {
+			return err
+		}
+		task = &domain.FollowUpTask{
+			CommunityID: member.CommunityID,
+			MemberID:    member.ID,
+			StageID:     &stage.ID,
+			AssigneeID:  stage.FollowUpAssigneeID,
+			Title:       stage.FollowUpTitle,
+			Description: stage.Description,
+			DueDate:     dueDate(now, location, stage.FollowUpDays),
+			Status:      domain.FollowUpTaskPending,
+			CreatedBy:   changedBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+
+	member.PipelineStageID = &stage.ID
+	member.StageEnteredAt = &now
+	member.Type = history.ToType
+	member.Status = history.ToStatus
+	member.UpdatedAt = now
+	if err := s.repos.MemberPipeline.MoveMember(ctx, member, history, task); err != nil {
+		return fmt.Errorf("erro ao mover membro de etapa: %v", err)
+	}
+	return nil
+}
+
+// RecordMemberChange registra no histórico a mudança de tipo ou situação feita
+// diretamente no cadastro do membro
+func (s *MemberPipelineService) RecordMemberChange(ctx context.Context, member *domain.Member, previousType, previousStatus, changedBy string) error {
+	if member.Type == previousType && member.Status == previousStatus {
+		return nil
+	}
+	history := &domain.MemberStatusHistory{
+		CommunityID: member.CommunityID,
+		MemberID:    member.ID,
+		FromStageID: member.PipelineStageID,
+		ToStageID:   member.PipelineStageID,
+		FromType:    previousType,
+		ToType:      member.Type,
+		FromStatus:  previousStatus,
+		ToStatus:    member.Status,
+		Source:      domain.StatusChangeSourceManual,
+		CreatedAt:   time.Now(),
+	}
+	if changedBy != "" {
+		history.ChangedBy = &changedBy
+	}
+	if err := s.repos.MemberPipeline.CreateHistory(ctx, history); err != nil {
+		return fmt.Errorf("erro ao registrar histórico do membro: %v", err)
+	}
+	return nil
+}
+
+// MemberHistory lista as mudanças de etapa, tipo e situação do membro, da mais recente
+// para a mais antiga
+func (s *MemberPipelineService) MemberHistory(ctx context.Context, communityID, memberID string) ([]*domain.MemberStatusHistory, error) {
+	member, err := s.repos.Member.FindByID(ctx, communityID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	history, err := s.repos.MemberPipeline.ListHistory(ctx, communityID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico do membro: %v", err)
+	}
+	return history, nil
+}
+
+// ListTasks lista as tarefas de acompanhamento da comunidade
+func (s *MemberPipelineService) ListTasks(ctx context.Context, communityID string, taskFilter *repository.FollowUpTaskFilter, filter *repository.Filter) ([]*domain.FollowUpTask, int64, error) {
+	if taskFilter != nil && taskFilter.Status != "" && !containsString([]string{domain.FollowUpTaskPending, domain.FollowUpTaskDone, domain.FollowUpTaskCancelled}, taskFilter.Status) {
+		return nil, 0, fmt.Errorf("%w: status aceita apenas pending, done, cancelled", ErrInvalidFollowUpTask)
+	}
+	tasks, total, err := s.repos.MemberPipeline.ListTasks(ctx, communityID, taskFilter, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar tarefas: %v", err)
+	}
+	return tasks, total, nil
+}
+
+// CreateTask cria uma tarefa de acompanhamento avulsa para o membro
+func (s *MemberPipelineService) CreateTask(ctx context.Context, task *domain.FollowUpTask) error {
+	member, err := s.repos.Member.FindByID(ctx, task.CommunityID, task.MemberID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return ErrMemberNotFound
+	}
+	if err := s.validateAssignee(ctx, task.CommunityID, task.AssigneeID); err != nil {
+		return err
+	}
+
+	task.StageID = member.PipelineStageID
+	task.Status = domain.FollowUpTaskPending
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	if err := s.repos.MemberPipeline.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("erro ao criar tarefa: %v", err)
+	}
+	return nil
+}
+
+// GetTask busca a tarefa de acompanhamento
+func (s *MemberPipelineService) GetTask(ctx context.Context, communityID, taskID string) (*domain.FollowUpTask, error) {
+	task, err := s.repos.MemberPipeline.FindTaskByID(ctx, communityID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tarefa: %v", err)
+	}
+	if task == nil {
+		return nil, ErrFollowUpTaskNotFound
+	}
+	return task, nil
+}
+
+// UpdateTask altera o título, a descrição, o prazo e o responsável de uma tarefa pendente
+func (s *MemberPipelineService) UpdateTask(ctx context.Context, communityID, taskID string, changes *domain.FollowUpTask) (*domain.FollowUpTask, error) {
+	task, err := s.GetTask(ctx, communityID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.FollowUpTaskPending {
+		return nil, ErrFollowUpTaskCompleted
+	}
+	if err := s.validateAssignee(ctx, communityID, changes.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	task.Title = changes.Title
+	task.Description = changes.Description
+	task.DueDate = changes.DueDate
+	task.AssigneeID = changes.AssigneeID
+	task.UpdatedAt = time.Now()
+	if err := s.repos.MemberPipeline.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar tarefa: %v", err)
+	}
+	return s.GetTask(ctx, communityID, taskID)
+}
+
+// CompleteTask conclui ou cancela a tarefa pendente, registrando o resultado do contato
+func (s *MemberPipelineService) CompleteTask(ctx context.Context, communityID, taskID, status, result string, completedBy string) (*domain.FollowUpTask, error) {
+	if status != domain.FollowUpTaskDone && status != domain.FollowUpTaskCancelled {
+		return nil, fmt.Errorf("%w: status aceita apenas done, cancelled", ErrInvalidFollowUpTask)
+	}
+	task, err := s.GetTask(ctx, communityID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.FollowUpTaskPending {
+		return nil, ErrFollowUpTaskCompleted
+	}
+
+	now := time.Now()
+	task.Status = status
+	task.Result = result
+	task.CompletedAt = &now
+	task.CompletedBy = &completedBy
+	task.UpdatedAt = now
+	if err := s.repos.MemberPipeline.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("erro ao concluir tarefa: %v", err)
+	}
+	return task, nil
+}
+
+// Dashboard monta o painel do caminho: membros por etapa, os que passaram do prazo da
+// etapa (os mais antigos primeiro) e as tarefas pendentes e atrasadas
+func (s *MemberPipelineService) Dashboard(ctx context.Context, communityID string) (*PipelineDashboard, error) {
+	location, err := s.location(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(location)
+
+	stages, err := s.ListStages(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repos.MemberPipeline.CountStageMembers(ctx, communityID, stages, now)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar membros por etapa: %v", err)
+	}
+
+	dashboard := &PipelineDashboard{
+		Stages:      make([]*PipelineStageSummary, 0, len(stages)),
+		GeneratedAt: now,
+	}
+	for i, stage := range stages {
+		summary := &PipelineStageSummary{
+			Stage:        stage,
+			Members:      counts[i].Total,
+			Stuck:        counts[i].Stuck,
+			StuckMembers: []*domain.Member{},
+		}
+		if summary.Stuck > 0 {
+			members, err := s.repos.MemberPipeline.ListStuckMembers(ctx, communityID, stage, now, stuckMembersLimit)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao listar membros parados na etapa: %v", err)
+			}
+			summary.StuckMembers = members
+		}
+		dashboard.Stages = append(dashboard.Stages, summary)
+	}
+
+	dashboard.PendingTasks, dashboard.OverdueTasks, err = s.repos.MemberPipeline.CountTasks(ctx, communityID, now)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar tarefas: %v", err)
+	}
+	return dashboard, nil
+}
+
+func (s *MemberPipelineService) findStage(ctx context.Context, communityID, stageID string) (*domain.PipelineStage, error) {
+	stage, err := s.repos.MemberPipeline.FindStageByID(ctx, communityID, stageID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar etapa: %v", err)
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+	return stage, nil
+}
+
+func (s *MemberPipelineService) validateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	if stage.MemberType != "" && !containsString(memberSearchTypes, stage.MemberType) {
+		return fmt.Errorf("%w: member_type aceita apenas %s", ErrInvalidPipelineStage, strings.Join(memberSearchTypes, ", "))
+	}
+	if stage.MemberStatus != "" && !containsString(memberSearchStatuses, stage.MemberStatus) {
+		return fmt.Errorf("%w: member_status aceita apenas %s", ErrInvalidPipelineStage, strings.Join(memberSearchStatuses, ", "))
+	}
+	if stage.MaxDays < 0 || stage.FollowUpDays < 0 {
+		return fmt.Errorf("%w: max_days e follow_up_days não podem ser negativos", ErrInvalidPipelineStage)
+	}
+	if !stage.HasFollowUp() && stage.FollowUpAssigneeID != nil {
+		return fmt.Errorf("%w: informe follow_up_title para atribuir a tarefa", ErrInvalidPipelineStage)
+	}
+	return s.validateAssignee(ctx, stage.CommunityID, stage.FollowUpAssigneeID)
+}
+
+// validateAssignee verifica se o responsável é um líder ou administrador ativo
+func (s *MemberPipelineService) validateAssignee(ctx context.Context, communityID string, assigneeID *string) error {
+	if assigneeID == nil {
+		return nil
+	}
+	assignee, err := s.repos.Member.FindByID(ctx, communityID, *assigneeID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar responsável: %v", err)
+	}
+	if assignee == nil || assignee.Status != "active" || (assignee.Role != "leader" && assignee.Role != "admin") {
+		return ErrInvalidTaskAssignee
+	}
+	return nil
+}
+
+// keepSingleVisitorEntry garante uma só etapa de entrada de visitantes por comunidade
+func (s *MemberPipelineService) keepSingleVisitorEntry(ctx context.Context, stage *domain.PipelineStage) error {
+	if !stage.IsVisitorEntry {
+		return nil
+	}
+	if err := s.repos.MemberPipeline.ClearVisitorEntry(ctx, stage.CommunityID, stage.ID); err != nil {
+		return fmt.Errorf("erro ao atualizar etapa de entrada dos visitantes: %v", err)
+	}
+	return nil
+}
+
+func (s *MemberPipelineService) location(ctx context.Context, communityID string) (*time.Location, error) {
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return time.UTC, nil
+	}
+	return communityLocation(community), nil
+}
+
+// dueDate é a data, no calendário da comunidade, daqui a days dias
+func dueDate(now time.Time, location *time.Location, days int) time.Time {
+	year, month, day := now.In(location).Date()
+	return time.Date(year, month, day+days, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+)
+
+// fakeMemberRepository guarda os membros em memória. Os métodos não usados nos testes
+// ficam com a interface embutida e falham se chamados.
+type fakeMemberRepository struct {
+	repository.MemberRepository
+	members []*domain.Member
+	created []*domain.Member
+}
+
+func (r *fakeMemberRepository) find(communityID string, match func(*domain.Member) bool) *domain.Member {
+	for _, member := range r.members {
+		if member.CommunityID == communityID && match(member) {
+			return member
+		}
+	}
+	return nil
+}
+
+func (r *fakeMemberRepository) FindByID(ctx context.Context, communityID, memberID string) (*domain.Member, error) {
+	return r.find(communityID, func(m *domain.Member) bool { return m.ID == memberID }), nil
+}
+
+func (r *fakeMemberRepository) FindByIDs(ctx context.Context, communityID string, memberIDs []string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	for _, id := range memberIDs {
+		if member, _ := r.FindByID(ctx, communityID, id); member != nil {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeMemberRepository) FindByEmail(ctx context.Context, communityID, email string) (*domain.Member, error) {
+	return r.find(communityID, func(m *domain.Member) bool { return m.Email == email }), nil
+}
+
+func (r *fakeMemberRepository) FindByEmailOrPhone(ctx context.Context, communityID, search string) (*domain.Member, error) {
+	return r.find(communityID, func(m *domain.Member) bool { return m.Email == search || m.Phone == search }), nil
+}
+
+func (r *fakeMemberRepository) Create(ctx context.Context, member *domain.Member) error {
+	member.ID = "novo"
+	r.members = append(r.members, member)
+	r.created = append(r.created, member)
+	return nil
+}
+
+func TestFindOrCreateVisitor(t *testing.T) {
+	members := []*domain.Member{
+		{ID: "m1", CommunityID: "c1", Name: "Ana Souza", Email: "ana@email.com", Phone: "11911112222", Type: "regular"},
+		{ID: "m2", CommunityID: "c1", Name: "Bruno Lima", Email: "bruno@email.com", Phone: "11933334444", Type: "visitor"},
+		{ID: "m3", CommunityID: "c2", Name: "Carla Dias", Email: "carla@email.com", Phone: "11955556666", Type: "regular"},
+	}
+	id := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		checkIn     domain.CheckIn
+		memberID    *string
+		wantID      string
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:     "membro escolhido pelo administrador",
+			checkIn:  domain.CheckIn{Name: "Bruno", Email: "ana@email.com"},
+			memberID: id("m2"),
+			wantID:   "m2",
+		},
+		{
+			name:     "membro escolhido de outra comunidade",
+			checkIn:  domain.CheckIn{Name: "Carla"},
+			memberID: id("m3"),
+			wantErr:  ErrMemberNotFound,
+		},
+		{
+			name:    "mesmo email",
+			checkIn: domain.CheckIn{Name: "Ana", Email: " ana@email.com ", Phone: "11900000000"},
+			wantID:  "m1",
+		},
+		{
+			name:    "mesmo telefone",
+			checkIn: domain.CheckIn{Name: "Bruno", Email: "outro@email.com", Phone: "11933334444"},
+			wantID:  "m2",
+		},
+		{
+			name:        "contato de membro de outra comunidade",
+			checkIn:     domain.CheckIn{Name: "Carla Dias", Email: "carla@email.com", Phone: "11955556666", City: "Campinas", District: "Centro"},
+			wantID:      "novo",
+			wantCreated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMemberRepository{members: append([]*domain.Member(nil), members...)}
+			s := &MemberPipelineService{repos: &repository.Repositories{Member: repo}}
+
+			member, created, err := s.findOrCreateVisitor(context.Background(), "c1", &tt.checkIn, tt.memberID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro %v; esperado %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if member.ID != tt.wantID || created != tt.wantCreated {
+				t.Errorf("membro %q, criado %v; esperado %q, %v", member.ID, created, tt.wantID, tt.wantCreated)
+			}
+			if !tt.wantCreated {
+				if len(repo.created) > 0 {
+					t.Errorf("%d cadastros criados; esperado nenhum", len(repo.created))
+				}
+				return
+			}
+			if member.CommunityID != "c1" || member.Type != "visitor" || member.Email != tt.checkIn.Email ||
+				member.City != tt.checkIn.City || member.Neighborhood != tt.checkIn.District {
+				t.Errorf("cadastro criado = %+v", member)
+			}
+		})
+	}
+}
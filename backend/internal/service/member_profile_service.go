@@ -0,0 +1,666 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEmptyProfileUpdate      = errors.New("nenhum dado informado para alteração")
+	ErrProfileEmailInUse       = errors.New("este email já está em uso por outro membro da comunidade")
+	ErrInvalidProfileSettings  = errors.New("configuração de aprovação inválida")
+	ErrProfileChangeNotFound   = errors.New("solicitação de alteração não encontrada")
+	ErrProfileChangeReviewed   = errors.New("a solicitação de alteração já foi revisada")
+	ErrInvalidFamilyChange     = errors.New("alteração de familiar inválida")
+	ErrFamilyMemberNotEditable = errors.New("este familiar tem acesso próprio ao portal e deve alterar o próprio cadastro")
+)
+
+// defaultApprovalFields são os campos que exigem aprovação enquanto a comunidade não
+// configurar os seus: o email identifica o membro no portal e os familiares criam cadastros
+var defaultApprovalFields = []string{"email", domain.ProfileFieldFamily}
+
+// profileFieldIndex relaciona o nome JSON de cada campo de MemberProfileUpdate ao campo
+// de mesmo nome JSON em Member
+var profileFieldIndex = func() map[string][]int {
+	members := make(map[string][]int)
+	memberType := reflect.TypeOf(domain.Member{})
+	for i := 0; i < memberType.NumField(); i++ {
+		members[jsonFieldName(memberType.Field(i))] = memberType.Field(i).Index
+	}
+
+	index := make(map[string][]int)
+	updateType := reflect.TypeOf(domain.MemberProfileUpdate{})
+	for i := 0; i < updateType.NumField(); i++ {
+		name := jsonFieldName(updateType.Field(i))
+		if _, ok := members[name]; !ok {
+			panic("campo sem correspondente em Member: " + name)
+		}
+		index[name] = members[name]
+	}
+	return index
+}()
+
+// MemberProfileService aplica as alterações de cadastro feitas pelo próprio membro no
+// portal. Os campos configurados pela comunidade ficam pendentes até a aprovação de um
+// administrador; os demais são aplicados na hora.
+type MemberProfileService struct {
+	repos     *repository.Repositories
+	logger    *zap.Logger
+	uploadDir string
+	household *HouseholdService
+}
+
+func NewMemberProfileService(repos *repository.Repositories, logger *zap.Logger, uploadDir string, household *HouseholdService) *MemberProfileService {
+	return &MemberProfileService{
+		repos:     repos,
+		logger:    logger,
+		uploadDir: uploadDir,
+		household: household,
+	}
+}
+
+// ProfileFields retorna os campos que podem exigir aprovação
+func ProfileFields() []string {
+	fields := make([]string, 0, len(profileFieldIndex)+1)
+	for name := range profileFieldIndex {
+		fields = append(fields, name)
+	}
+	fields = append(fields, domain.ProfileFieldFamily)
+	sort.Strings(fields)
+	return fields
+}
+
+// GetSettings retorna os campos que exigem aprovação na comunidade
+func (s *MemberProfileService) GetSettings(ctx context.Context, communityID string) (*domain.MemberProfileSettings, error) {
+	settings, err := s.repos.MemberProfile.FindSettings(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar configuração do portal: %v", err)
+	}
+	if settings == nil {
+		settings = &domain.MemberProfileSettings{
+			CommunityID:    communityID,
+			ApprovalFields: append([]string(nil), defaultApprovalFields...),
+		}
+	}
+	return settings, nil
+}
+
+// UpdateSettings define os campos que exigem aprovação na comunidade
+func (s *MemberProfileService) UpdateSettings(ctx context.Context, communityID string, fields []string, updatedBy string) (*domain.MemberProfileSettings, error) {
+	allowed := ProfileFields()
+	approval := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !containsString(allowed, field) {
+			return nil, fmt.Errorf("%w: campo %q desconhecido; aceitos: %s", ErrInvalidProfileSettings, field, strings.Join(allowed, ", "))
+		}
+		if !containsString(approval, field) {
+			approval = append(approval, field)
+		}
+	}
+
+	settings, err := s.GetSettings(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = time.Now()
+	}
+	settings.ApprovalFields = approval
+	settings.UpdatedBy = &updatedBy
+	settings.UpdatedAt = time.Now()
+	if err := s.repos.MemberProfile.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("erro ao salvar configuração do portal: %v", err)
+	}
+	return settings, nil
+}
+
+// UpdateProfile aplica os campos alterados pelo membro que não exigem aprovação e
+// registra os demais em uma solicitação pendente. Uma nova alteração do mesmo campo
+// substitui a que ainda aguarda aprovação. Retorna a solicitação pendente, se houver.
+func (s *MemberProfileService) UpdateProfile(ctx context.Context, member *domain.Member, update *domain.MemberProfileUpdate) (*domain.MemberProfileChange, error) {
+	normalizeProfileUpdate(update)
+	if len(profileUpdateFields(update)) == 0 {
+		return nil, ErrEmptyProfileUpdate
+	}
+	if err := s.checkEmail(ctx, member, update.Email); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetSettings(ctx, member.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+	immediate, pending := splitProfileUpdate(update, settings.ApprovalFields)
+
+	if len(profileUpdateFields(immediate)) > 0 {
+		if err := s.applyProfile(ctx, member, immediate); err != nil {
+			return nil, err
+		}
+	}
+	if len(profileUpdateFields(pending)) == 0 {
+		return nil, nil
+	}
+
+	change, err := s.repos.MemberProfile.FindPendingProfileChange(ctx, member.CommunityID, member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alteração pendente: %v", err)
+	}
+	if change == nil {
+		change = &domain.MemberProfileChange{
+			CommunityID: member.CommunityID,
+			MemberID:    member.ID,
+			Kind:        domain.ProfileChangeKindProfile,
+			Profile:     &domain.MemberProfileUpdate{},
+			Previous:    &domain.MemberProfileUpdate{},
+			Status:      domain.ProfileChangePending,
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	// A foto substituída antes da aprovação não será mais usada
+	if pending.Photo != nil && change.Profile.Photo != nil && *change.Profile.Photo != *pending.Photo {
+		s.removePhoto(*change.Profile.Photo)
+	}
+	mergeProfileUpdate(change.Profile, pending)
+	mergeProfileUpdate(change.Previous, currentProfile(member, pending))
+	change.UpdatedAt = time.Now()
+
+	if change.ID == "" {
+		err = s.repos.MemberProfile.CreateChange(ctx, change)
+	} else {
+		err = s.repos.MemberProfile.UpdateChange(ctx, change)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar alteração pendente: %v", err)
+	}
+	return change, nil
+}
+
+// UpdatePhoto troca a foto do membro pela foto já gravada em path, relativo ao
+// diretório de uploads
+func (s *MemberProfileService) UpdatePhoto(ctx context.Context, member *domain.Member, path string) (*domain.MemberProfileChange, error) {
+	change, err := s.UpdateProfile(ctx, member, &domain.MemberProfileUpdate{Photo: &path})
+	if err != nil {
+		s.removePhoto(path)
+	}
+	return change, err
+}
+
+// FamilyMembers lista os familiares do membro
+func (s *MemberProfileService) FamilyMembers(ctx context.Context, member *domain.Member) ([]*domain.Member, error) {
+	members, err := s.repos.Family.ListFamilyMembersWithDetails(ctx, member.CommunityID, member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar familiares: %v", err)
+	}
+	if members == nil {
+		members = []*domain.Member{}
+	}
+	return members, nil
+}
+
+// RequestFamilyChange inclui, altera ou remove um familiar do membro. Quando a comunidade
+// exige aprovação para familiares, registra a solicitação pendente e a retorna.
+func (s *MemberProfileService) RequestFamilyChange(ctx context.Context, member *domain.Member, update *domain.MemberFamilyUpdate) (*domain.MemberProfileChange, error) {
+	if err := s.validateFamilyChange(ctx, member, update); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetSettings(ctx, member.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(settings.ApprovalFields, domain.ProfileFieldFamily) {
+		return nil, s.applyFamilyChange(ctx, member, update)
+	}
+
+	change := &domain.MemberProfileChange{
+		CommunityID: member.CommunityID,
+		MemberID:    member.ID,
+		Kind:        domain.ProfileChangeKindFamily,
+		Family:      update,
+		Status:      domain.ProfileChangePending,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.repos.MemberProfile.CreateChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("erro ao registrar alteração de familiar: %v", err)
+	}
+	return change, nil
+}
+
+// ListChanges lista as solicitações de alteração da comunidade ou de um membro
+func (s *MemberProfileService) ListChanges(ctx context.Context, communityID, memberID, status string, filter *repository.Filter) ([]*domain.MemberProfileChange, int64, error) {
+	changes, total, err := s.repos.MemberProfile.ListChanges(ctx, communityID, memberID, status, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar alterações de cadastro: %v", err)
+	}
+	return changes, total, nil
+}
+
+// GetChange busca a solicitação de alteração
+func (s *MemberProfileService) GetChange(ctx context.Context, communityID, changeID string) (*domain.MemberProfileChange, error) {
+	change, err := s.repos.MemberProfile.FindChangeByID(ctx, communityID, changeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alteração de cadastro: %v", err)
+	}
+	if change == nil {
+		return nil, ErrProfileChangeNotFound
+	}
+	return change, nil
+}
+
+// CancelChange cancela uma solicitação pendente do próprio membro
+func (s *MemberProfileService) CancelChange(ctx context.Context, member *domain.Member, changeID string) (*domain.MemberProfileChange, error) {
+	change, err := s.pendingChange(ctx, member.CommunityID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change.MemberID != member.ID {
+		return nil, ErrProfileChangeNotFound
+	}
+	return change, s.close(ctx, change, domain.ProfileChangeCancelled, nil, "")
+}
+
+// ApproveChange aplica a solicitação pendente ao cadastro do membro
+func (s *MemberProfileService) ApproveChange(ctx context.Context, communityID, changeID, reviewerID, notes string) (*domain.MemberProfileChange, error) {
+	change, err := s.pendingChange(ctx, communityID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.repos.Member.FindByID(ctx, communityID, change.MemberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	switch change.Kind {
+	case domain.ProfileChangeKindProfile:
+		if err := s.checkEmail(ctx, member, change.Profile.Email); err != nil {
+			return nil, err
+		}
+		err = s.applyProfile(ctx, member, change.Profile)
+	case domain.ProfileChangeKindFamily:
+		// O familiar pode ter mudado desde a solicitação
+		if err = s.validateFamilyChange(ctx, member, change.Family); err == nil {
+			err = s.applyFamilyChange(ctx, member, change.Family)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change.Member = member
+	return change, s.close(ctx, change, domain.ProfileChangeApproved, &reviewerID, notes)
+}
+
+// RejectChange recusa a solicitação pendente sem alterar o cadastro
+func (s *MemberProfileService) RejectChange(ctx context.Context, communityID, changeID, reviewerID, notes string) (*domain.MemberProfileChange, error) {
+	change, err := s.pendingChange(ctx, communityID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	return change, s.close(ctx, change, domain.ProfileChangeRejected, &reviewerID, notes)
+}
+
+func (s *MemberProfileService) pendingChange(ctx context.Context, communityID, changeID string) (*domain.MemberProfileChange, error) {
+	change, err := s.GetChange(ctx, communityID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != domain.ProfileChangePending {
+		return nil, ErrProfileChangeReviewed
+	}
+	return change, nil
+}
+
+// close encerra a solicitação. A foto enviada em uma solicitação não aprovada é removida.
+func (s *MemberProfileService) close(ctx context.Context, change *domain.MemberProfileChange, status string, reviewerID *string, notes string) error {
+	now := time.Now()
+	change.Status = status
+	change.ReviewedBy = reviewerID
+	change.ReviewNotes = notes
+	if reviewerID != nil {
+		change.ReviewedAt = &now
+	}
+	change.UpdatedAt = now
+	if err := s.repos.MemberProfile.UpdateChange(ctx, change); err != nil {
+		return fmt.Errorf("erro ao atualizar alteração de cadastro: %v", err)
+	}
+
+	if status != domain.ProfileChangeApproved && change.Profile != nil && change.Profile.Photo != nil {
+		s.removePhoto(*change.Profile.Photo)
+	}
+	return nil
+}
+
+// applyProfile grava os campos no cadastro do membro, removendo a foto substituída
+func (s *MemberProfileService) applyProfile(ctx context.Context, member *domain.Member, update *domain.MemberProfileUpdate) error {
+	oldPhoto := member.Photo
+	applyProfileUpdate(member, update)
+	member.UpdatedAt = time.Now()
+	if err := s.repos.Member.Update(ctx, member); err != nil {
+		return fmt.Errorf("erro ao atualizar cadastro do membro: %v", err)
+	}
+	if update.Photo != nil && oldPhoto != "" && oldPhoto != member.Photo {
+		s.removePhoto(oldPhoto)
+	}
+	return nil
+}
+
+func (s *MemberProfileService) checkEmail(ctx context.Context, member *domain.Member, email *string) error {
+	if email == nil || *email == "" || strings.EqualFold(*email, member.Email) {
+		return nil
+	}
+	existing, err := s.repos.Member.FindByEmail(ctx, member.CommunityID, *email)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar email: %v", err)
+	}
+	if existing != nil && existing.ID != member.ID {
+		return ErrProfileEmailInUse
+	}
+	return nil
+}
+
+// validateFamilyChange confere a ação pedida. Só os familiares da mesma família podem
+// ser alterados ou removidos, e só os que não têm acesso próprio ao portal são alterados.
+func (s *MemberProfileService) validateFamilyChange(ctx context.Context, member *domain.Member, update *domain.MemberFamilyUpdate) error {
+	update.Name = strings.TrimSpace(update.Name)
+	update.Email = strings.TrimSpace(update.Email)
+	update.Phone = strings.TrimSpace(update.Phone)
+	if update.Role != "" && familyRoleFromText(update.Role) != update.Role {
+		return fmt.Errorf("%w: parentesco %q desconhecido", ErrInvalidFamilyChange, update.Role)
+	}
+	if update.Gender != "" && !containsString(memberSearchGenders, update.Gender) {
+		return fmt.Errorf("%w: gênero aceita apenas %s", ErrInvalidFamilyChange, strings.Join(memberSearchGenders, ", "))
+	}
+
+	switch update.Action {
+	case domain.FamilyChangeAdd:
+		if update.Name == "" {
+			return fmt.Errorf("%w: informe o nome do familiar", ErrInvalidFamilyChange)
+		}
+		update.MemberID = nil
+		return nil
+	case domain.FamilyChangeUpdate, domain.FamilyChangeRemove:
+	default:
+		return fmt.Errorf("%w: ação aceita apenas add, update, remove", ErrInvalidFamilyChange)
+	}
+
+	if update.MemberID == nil || *update.MemberID == member.ID {
+		return fmt.Errorf("%w: informe o familiar", ErrInvalidFamilyChange)
+	}
+	own, err := s.repos.Family.FindByMemberID(ctx, member.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar família do membro: %v", err)
+	}
+	relative, err := s.repos.Family.FindByMemberID(ctx, *update.MemberID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar família do familiar: %v", err)
+	}
+	if own == nil || relative == nil || own.FamilyID != relative.FamilyID {
+		return fmt.Errorf("%w: o membro informado não pertence à sua família", ErrInvalidFamilyChange)
+	}
+
+	if update.Action == domain.FamilyChangeUpdate {
+		target, err := s.repos.Member.FindByID(ctx, member.CommunityID, *update.MemberID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar familiar: %v", err)
+		}
+		if target == nil {
+			return fmt.Errorf("%w: o membro informado não pertence à sua família", ErrInvalidFamilyChange)
+		}
+		if target.Password != "" {
+			return ErrFamilyMemberNotEditable
+		}
+	}
+	return nil
+}
+
+// applyFamilyChange executa a inclusão, alteração ou remoção do familiar
+func (s *MemberProfileService) applyFamilyChange(ctx context.Context, member *domain.Member, update *domain.MemberFamilyUpdate) error {
+	switch update.Action {
+	case domain.FamilyChangeAdd:
+		return s.addRelative(ctx, member, update)
+
+	case domain.FamilyChangeUpdate:
+		// O parentesco é gravado primeiro pelo serviço de famílias e o cadastro é lido
+		// em seguida, já com o novo papel
+		if update.Role != "" {
+			relative, err := s.repos.Family.FindByMemberID(ctx, *update.MemberID)
+			if err != nil {
+				return fmt.Errorf("erro ao buscar família do familiar: %v", err)
+			}
+			if relative != nil {
+				if _, err := s.household.UpdateRole(ctx, member.CommunityID, relative.FamilyID, relative.MemberID, update.Role); err != nil {
+					return err
+				}
+			}
+		}
+		target, err := s.repos.Member.FindByID(ctx, member.CommunityID, *update.MemberID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar familiar: %v", err)
+		}
+		if update.Name != "" {
+			target.Name = update.Name
+		}
+		if update.BirthDate != nil {
+			target.BirthDate = *update.BirthDate
+		}
+		if update.Gender != "" {
+			target.Gender = update.Gender
+		}
+		if update.Email != "" {
+			target.Email = update.Email
+		}
+		if update.Phone != "" {
+			target.Phone = update.Phone
+		}
+		target.UpdatedAt = time.Now()
+		if err := s.repos.Member.Update(ctx, target); err != nil {
+			return fmt.Errorf("erro ao atualizar familiar: %v", err)
+		}
+		return nil
+
+	case domain.FamilyChangeRemove:
+		relative, err := s.repos.Family.FindByMemberID(ctx, *update.MemberID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar família do familiar: %v", err)
+		}
+		if relative == nil {
+			return nil
+		}
+		return s.household.RemoveMember(ctx, member.CommunityID, relative.FamilyID, relative.MemberID)
+	}
+	return nil
+}
+
+// addRelative cadastra o familiar e o inclui na família do membro, criando a família
+// quando o membro ainda não tem uma
+func (s *MemberProfileService) addRelative(ctx context.Context, member *domain.Member, update *domain.MemberFamilyUpdate) error {
+	own, err := s.repos.Family.FindByMemberID(ctx, member.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar família do membro: %v", err)
+	}
+	var familyID string
+	if own != nil {
+		familyID = own.FamilyID
+	} else {
+		family, err := s.household.Create(ctx, &domain.Family{
+			CommunityID:  member.CommunityID,
+			Name:         "Família " + lastName(member.Name),
+			HeadOfFamily: member.ID,
+		})
+		if err != nil {
+			return err
+		}
+		familyID = family.ID
+		member.FamilyID = &familyID
+		member.FamilyRole = domain.FamilyRoleHead
+	}
+
+	role := update.Role
+	if role == "" {
+		role = domain.FamilyRoleOther
+	}
+	now := time.Now()
+	relative := &domain.Member{
+		CommunityID: member.CommunityID,
+		Name:        update.Name,
+		Email:       update.Email,
+		Phone:       update.Phone,
+		Gender:      update.Gender,
+		Role:        "member",
+		Status:      "active",
+		Type:        member.Type,
+		JoinDate:    now,
+		Notes:       "Cadastrado por " + member.Name + " no portal do membro",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if update.BirthDate != nil {
+		relative.BirthDate = *update.BirthDate
+	}
+	if err := s.repos.Member.Create(ctx, relative); err != nil {
+		return fmt.Errorf("erro ao cadastrar familiar: %v", err)
+	}
+
+	// Sem vínculo com a família (parentesco inválido, por exemplo), o cadastro é desfeito
+	if _, err := s.household.AddMember(ctx, member.CommunityID, familyID, relative.ID, role); err != nil {
+		if deleteErr := s.repos.Member.Delete(ctx, member.CommunityID, relative.ID); deleteErr != nil {
+			s.logger.Error("erro ao remover familiar sem família", zap.String("member_id", relative.ID), zap.Error(deleteErr))
+		}
+		return err
+	}
+	update.MemberID = &relative.ID
+	return nil
+}
+
+// removePhoto apaga do disco uma foto enviada pelo portal
+func (s *MemberProfileService) removePhoto(path string) {
+	if path == "" || strings.Contains(path, "..") {
+		return
+	}
+	if err := os.Remove(filepath.Join(s.uploadDir, path)); err != nil && !os.IsNotExist(err) {
+		s.logger.Error("erro ao remover foto", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// normalizeProfileUpdate remove espaços das informações e repetições das listas
+func normalizeProfileUpdate(update *domain.MemberProfileUpdate) {
+	value := reflect.ValueOf(update).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsNil() || field.Elem().Kind() != reflect.String {
+			continue
+		}
+		field.Elem().SetString(strings.TrimSpace(field.Elem().String()))
+	}
+	for _, list := range []*[]string{update.Skills, update.Interests} {
+		if list == nil {
+			continue
+		}
+		values := make([]string, 0, len(*list))
+		for _, value := range *list {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		*list = mergeStrings(nil, values)
+	}
+}
+
+// profileUpdateFields retorna os nomes JSON dos campos preenchidos
+func profileUpdateFields(update *domain.MemberProfileUpdate) []string {
+	var fields []string
+	value := reflect.ValueOf(update).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			fields = append(fields, jsonFieldName(value.Type().Field(i)))
+		}
+	}
+	return fields
+}
+
+// splitProfileUpdate separa os campos aplicados na hora dos que exigem aprovação
+func splitProfileUpdate(update *domain.MemberProfileUpdate, approval []string) (immediate, pending *domain.MemberProfileUpdate) {
+	immediate, pending = &domain.MemberProfileUpdate{}, &domain.MemberProfileUpdate{}
+	source := reflect.ValueOf(update).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if source.Field(i).IsNil() {
+			continue
+		}
+		target := immediate
+		if containsString(approval, jsonFieldName(source.Type().Field(i))) {
+			target = pending
+		}
+		reflect.ValueOf(target).Elem().Field(i).Set(source.Field(i))
+	}
+	return immediate, pending
+}
+
+// mergeProfileUpdate copia para dst os campos preenchidos em src
+func mergeProfileUpdate(dst, src *domain.MemberProfileUpdate) {
+	source := reflect.ValueOf(src).Elem()
+	target := reflect.ValueOf(dst).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if !source.Field(i).IsNil() {
+			target.Field(i).Set(source.Field(i))
+		}
+	}
+}
+
+// applyProfileUpdate copia os campos preenchidos para o cadastro do membro
+func applyProfileUpdate(member *domain.Member, update *domain.MemberProfileUpdate) {
+	source := reflect.ValueOf(update).Elem()
+	target := reflect.ValueOf(member).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if source.Field(i).IsNil() {
+			continue
+		}
+		name := jsonFieldName(source.Type().Field(i))
+		target.FieldByIndex(profileFieldIndex[name]).Set(source.Field(i).Elem())
+	}
+}
+
+// currentProfile retorna os valores atuais do cadastro para os campos preenchidos em update
+func currentProfile(member *domain.Member, update *domain.MemberProfileUpdate) *domain.MemberProfileUpdate {
+	current := &domain.MemberProfileUpdate{}
+	source := reflect.ValueOf(update).Elem()
+	target := reflect.ValueOf(current).Elem()
+	memberValue := reflect.ValueOf(member).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if source.Field(i).IsNil() {
+			continue
+		}
+		name := jsonFieldName(source.Type().Field(i))
+		value := reflect.New(source.Field(i).Type().Elem())
+		value.Elem().Set(memberValue.FieldByIndex(profileFieldIndex[name]))
+		target.Field(i).Set(value)
+	}
+	return current
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+func lastName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
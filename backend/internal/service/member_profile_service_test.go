@@ -0,0 +1,107 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestNormalizeProfileUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	skills := []string{" Música ", "", "música", "Teatro"}
+	update := &domain.MemberProfileUpdate{
+		Email:  str("  ana@email.com "),
+		City:   str("\tSão Paulo\n"),
+		Skills: &skills,
+	}
+
+	normalizeProfileUpdate(update)
+
+	if *update.Email != "ana@email.com" || *update.City != "São Paulo" {
+		t.Errorf("email %q e cidade %q; esperado sem espaços", *update.Email, *update.City)
+	}
+	if want := []string{"Música", "Teatro"}; !reflect.DeepEqual(*update.Skills, want) {
+		t.Errorf("habilidades = %q; esperado %q", *update.Skills, want)
+	}
+	if update.Phone != nil || update.Interests != nil {
+		t.Error("campos não informados foram preenchidos")
+	}
+}
+
+func TestSplitProfileUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	yes := true
+	update := &domain.MemberProfileUpdate{
+		Email:         str("ana@email.com"),
+		Phone:         str("11911112222"),
+		Address:       str("Rua das Flores"),
+		NotifyByEmail: &yes,
+	}
+
+	immediate, pending := splitProfileUpdate(update, []string{"email", "address", domain.ProfileFieldFamily})
+
+	if got, want := profileUpdateFields(immediate), []string{"phone", "notify_by_email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("campos aplicados na hora = %q; esperado %q", got, want)
+	}
+	if got, want := profileUpdateFields(pending), []string{"email", "address"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("campos pendentes = %q; esperado %q", got, want)
+	}
+	if pending.Email != update.Email || immediate.Phone != update.Phone {
+		t.Error("os valores informados não foram mantidos")
+	}
+
+	mergeProfileUpdate(immediate, pending)
+	if got, want := profileUpdateFields(immediate), profileUpdateFields(update); !reflect.DeepEqual(got, want) {
+		t.Errorf("campos após juntar = %q; esperado %q", got, want)
+	}
+}
+
+func TestApplyProfileUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	no := false
+	skills := []string{"Música"}
+	member := &domain.Member{
+		Email:         "antigo@email.com",
+		Phone:         "11900000000",
+		City:          "Campinas",
+		NotifyByEmail: true,
+		Skills:        []string{"Teatro"},
+	}
+	update := &domain.MemberProfileUpdate{
+		Email:         str("ana@email.com"),
+		City:          str("São Paulo"),
+		NotifyByEmail: &no,
+		Skills:        &skills,
+	}
+
+	// Os valores anteriores são guardados apenas para os campos alterados
+	previous := currentProfile(member, update)
+	if got, want := profileUpdateFields(previous), profileUpdateFields(update); !reflect.DeepEqual(got, want) {
+		t.Fatalf("campos anteriores = %q; esperado %q", got, want)
+	}
+	if *previous.Email != "antigo@email.com" || *previous.City != "Campinas" || !*previous.NotifyByEmail || !reflect.DeepEqual(*previous.Skills, []string{"Teatro"}) {
+		t.Errorf("valores anteriores = %+v", previous)
+	}
+
+	applyProfileUpdate(member, update)
+	if member.Email != "ana@email.com" || member.City != "São Paulo" || member.NotifyByEmail || !reflect.DeepEqual(member.Skills, skills) {
+		t.Errorf("cadastro = %+v; esperado com os valores informados", member)
+	}
+	if member.Phone != "11900000000" {
+		t.Errorf("telefone = %q; esperado sem alteração", member.Phone)
+	}
+}
+
+func TestProfileFields(t *testing.T) {
+	fields := ProfileFields()
+	// Todos os campos de MemberProfileUpdate podem exigir aprovação, além dos familiares
+	if want := reflect.TypeOf(domain.MemberProfileUpdate{}).NumField() + 1; len(fields) != want {
+		t.Errorf("campos = %d; esperado %d", len(fields), want)
+	}
+	for _, field := range []string{"email", "photo", domain.ProfileFieldFamily} {
+		if !containsString(fields, field) {
+			t.Errorf("campo %q ausente de %q", field, fields)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidMemberSearch = errors.New("critério de busca inválido")
+	ErrSegmentNotFound     = errors.New("segmento não encontrado")
+	ErrSegmentInUse        = errors.New("o segmento é destinatário de comunicações ainda não enviadas")
+)
+
+// Valores aceitos nos critérios da busca, iguais às restrições da tabela de membros
+var (
+	memberSearchStatuses = []string{"pending", "active", "inactive", "blocked"}
+	memberSearchTypes    = []string{"regular", "visitor", "transferred"}
+	memberSearchRoles    = []string{"member", "leader", "admin"}
+	memberSearchGenders  = []string{"male", "female", "other", "not_specified"}
+)
+
+// MemberSegmentService executa a busca avançada de membros e mantém os segmentos salvos
+type MemberSegmentService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewMemberSegmentService(repos *repository.Repositories, logger *zap.Logger) *MemberSegmentService {
+	return &MemberSegmentService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Search busca os membros da comunidade pelos critérios informados
+func (s *MemberSegmentService) Search(ctx context.Context, communityID string, search *domain.MemberSearch, filter *repository.Filter) ([]*domain.Member, int64, error) {
+	if err := ValidateMemberSearch(search); err != nil {
+		return nil, 0, err
+	}
+	members, total, err := s.repos.Member.Search(ctx, communityID, search, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar membros: %v", err)
+	}
+	return members, total, nil
+}
+
+// CreateSegment salva a busca como um segmento da comunidade
+func (s *MemberSegmentService) CreateSegment(ctx context.Context, segment *domain.MemberSegment) error {
+	if err := ValidateMemberSearch(&segment.Criteria); err != nil {
+		return err
+	}
+	segment.ID = uuid.New().String()
+	segment.CreatedAt = time.Now()
+	segment.UpdatedAt = time.Now()
+	if err := s.repos.MemberSegment.Create(ctx, segment); err != nil {
+		return fmt.Errorf("erro ao criar segmento: %v", err)
+	}
+	return s.countMembers(ctx, segment)
+}
+
+// GetSegment busca o segmento com a quantidade atual de membros
+func (s *MemberSegmentService) GetSegment(ctx context.Context, communityID, segmentID string) (*domain.MemberSegment, error) {
+	segment, err := s.findSegment(ctx, communityID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.countMembers(ctx, segment); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+// ListSegments lista os segmentos da comunidade
+func (s *MemberSegmentService) ListSegments(ctx context.Context, communityID string, filter *repository.Filter) ([]*domain.MemberSegment, int64, error) {
+	segments, total, err := s.repos.MemberSegment.List(ctx, communityID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar segmentos: %v", err)
+	}
+	return segments, total, nil
+}
+
+// UpdateSegment altera o nome, a descrição e os critérios do segmento
+func (s *MemberSegmentService) UpdateSegment(ctx context.Context, communityID, segmentID string, changes *domain.MemberSegment) (*domain.MemberSegment, error) {
+	if err := ValidateMemberSearch(&changes.Criteria); err != nil {
+		return nil, err
+	}
+	segment, err := s.findSegment(ctx, communityID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	segment.Name = changes.Name
+	segment.Description = changes.Description
+	segment.Criteria = changes.Criteria
+	segment.UpdatedAt = time.Now()
+	if err := s.repos.MemberSegment.Update(ctx, segment); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar segmento: %v", err)
+	}
+	if err := s.countMembers(ctx, segment); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+// DeleteSegment exclui o segmento, desde que não haja comunicações pendentes para ele
+func (s *MemberSegmentService) DeleteSegment(ctx context.Context, communityID, segmentID string) error {
+	if _, err := s.findSegment(ctx, communityID, segmentID); err != nil {
+		return err
+	}
+	inUse, err := s.repos.MemberSegment.HasPendingCommunications(ctx, communityID, segmentID)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar comunicações do segmento: %v", err)
+	}
+	if inUse {
+		return ErrSegmentInUse
+	}
+	if err := s.repos.MemberSegment.Delete(ctx, communityID, segmentID); err != nil {
+		return fmt.Errorf("erro ao excluir segmento: %v", err)
+	}
+	return nil
+}
+
+// SegmentMembers lista, com paginação, os membros que atendem hoje aos critérios do segmento
+func (s *MemberSegmentService) SegmentMembers(ctx context.Context, communityID, segmentID string, filter *repository.Filter) ([]*domain.Member, int64, error) {
+	segment, err := s.findSegment(ctx, communityID, segmentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.Search(ctx, communityID, &segment.Criteria, filter)
+}
+
+func (s *MemberSegmentService) findSegment(ctx context.Context, communityID, segmentID string) (*domain.MemberSegment, error) {
+	segment, err := s.repos.MemberSegment.FindByID(ctx, communityID, segmentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar segmento: %v", err)
+	}
+	if segment == nil {
+		return nil, ErrSegmentNotFound
+	}
+	return segment, nil
+}
+
+func (s *MemberSegmentService) countMembers(ctx context.Context, segment *domain.MemberSegment) error {
+	_, total, err := s.repos.Member.Search(ctx, segment.CommunityID, &segment.Criteria, &repository.Filter{Page: 1, PerPage: 1})
+	if err != nil {
+		return fmt.Errorf("erro ao contar membros do segmento: %v", err)
+	}
+	segment.MemberCount = &total
+	return nil
+}
+
+// ValidateMemberSearch verifica os valores dos critérios da busca avançada
+func ValidateMemberSearch(search *domain.MemberSearch) error {
+	if search == nil {
+		return nil
+	}
+
+	choices := []struct {
+		name    string
+		values  []string
+		allowed []string
+	}{
+		{"statuses", search.Statuses, memberSearchStatuses},
+		{"types", search.Types, memberSearchTypes},
+		{"roles", search.Roles, memberSearchRoles},
+		{"genders", search.Genders, memberSearchGenders},
+	}
+	for _, choice := range choices {
+		for _, value := range choice.values {
+			if !containsString(choice.allowed, value) {
+				return fmt.Errorf("%w: %s aceita apenas %s", ErrInvalidMemberSearch, choice.name, strings.Join(choice.allowed, ", "))
+			}
+		}
+	}
+
+	for name, age := range map[string]*int{"min_age": search.MinAge, "max_age": search.MaxAge} {
+		if age != nil && (*age < 0 || *age > 130) {
+			return fmt.Errorf("%w: %s deve estar entre 0 e 130", ErrInvalidMemberSearch, name)
+		}
+	}
+	if search.MinAge != nil && search.MaxAge != nil && *search.MinAge > *search.MaxAge {
+		return fmt.Errorf("%w: min_age maior que max_age", ErrInvalidMemberSearch)
+	}
+
+	days := map[string]*int{
+		"attended_within_days":    search.AttendedWithinDays,
+		"absent_for_days":         search.AbsentForDays,
+		"contributed_within_days": search.ContributedWithinDays,
+	}
+	for name, value := range days {
+		if value != nil && (*value < 1 || *value > 3650) {
+			return fmt.Errorf("%w: %s deve estar entre 1 e 3650", ErrInvalidMemberSearch, name)
+		}
+	}
+
+	if search.LastAttendanceFrom != nil && search.LastAttendanceTo != nil && search.LastAttendanceTo.Before(*search.LastAttendanceFrom) {
+		return fmt.Errorf("%w: last_attendance_to anterior a last_attendance_from", ErrInvalidMemberSearch)
+	}
+	if search.LastContributionFrom != nil && search.LastContributionTo != nil && search.LastContributionTo.Before(*search.LastContributionFrom) {
+		return fmt.Errorf("%w: last_contribution_to anterior a last_contribution_from", ErrInvalidMemberSearch)
+	}
+
+	for _, groupID := range search.GroupIDs {
+		if _, err := uuid.Parse(groupID); err != nil {
+			return fmt.Errorf("%w: group_ids deve conter IDs de grupos", ErrInvalidMemberSearch)
+		}
+	}
+	if search.WithoutGroup && len(search.GroupIDs) > 0 {
+		return fmt.Errorf("%w: without_group não pode ser combinado com group_ids", ErrInvalidMemberSearch)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
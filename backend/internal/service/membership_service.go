@@ -0,0 +1,680 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/pdf"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMembershipEventNotFound = errors.New("registro de membresia não encontrado")
+	ErrInvalidMembershipEvent  = errors.New("registro de membresia inválido")
+	ErrMembershipEventVoided   = errors.New("o registro já foi anulado")
+	ErrInvalidLetterType       = errors.New("tipo de carta inválido")
+	ErrInvalidLetterTemplate   = errors.New("modelo de carta inválido")
+	ErrInvalidLetter           = errors.New("carta inválida")
+	ErrMemberNotInCommunion    = errors.New("o membro não está em plena comunhão")
+	ErrMemberLetterNotFound    = errors.New("carta não encontrada")
+)
+
+var membershipEventTypes = []string{
+	domain.MembershipEventJoined,
+	domain.MembershipEventBaptized,
+	domain.MembershipEventTransferredIn,
+	domain.MembershipEventTransferredOut,
+	domain.MembershipEventDisciplined,
+	domain.MembershipEventDeceased,
+	domain.MembershipEventReinstated,
+}
+
+var letterTypes = []string{domain.MemberLetterTransfer, domain.MemberLetterRecommendation}
+
+// Modelos usados enquanto a comunidade não personaliza os seus
+var defaultLetterTemplates = map[string]domain.LetterTemplate{
+	domain.MemberLetterTransfer: {
+		Title: "Carta de Transferência",
+		Body: `Prezados irmãos da {{.DestinationChurch}},
+
+Graça e paz da parte de nosso Senhor Jesus Cristo.
+
+{{.Community.Name}} apresenta, por meio desta, {{.Article}} {{.Brother}} {{.Member.Name}}, membro desta igreja{{if .MembershipDate}} desde {{.MembershipDate}}{{end}}{{if .BaptismDate}}, com batismo em {{.BaptismDate}}{{end}}, que se encontra em plena comunhão conosco e solicitou transferência para essa igreja.
+
+Concedemos a presente carta de transferência e rogamos que {{.Article}} recebam no amor de Cristo.
+
+Fraternalmente,`,
+		SignerTitle: "Pastor",
+	},
+	domain.MemberLetterRecommendation: {
+		Title: "Carta de Recomendação",
+		Body: `{{if .DestinationChurch}}Prezados irmãos da {{.DestinationChurch}},{{else}}A quem possa interessar,{{end}}
+
+Graça e paz da parte de nosso Senhor Jesus Cristo.
+
+{{.Community.Name}} recomenda à comunhão e ao cuidado dos irmãos em Cristo {{.Article}} {{.Brother}} {{.Member.Name}}, membro desta igreja{{if .MembershipDate}} desde {{.MembershipDate}}{{end}}, que se encontra em plena comunhão conosco.
+
+Esta carta é válida por 90 dias a partir da data de emissão.
+
+Fraternalmente,`,
+		SignerTitle: "Pastor",
+	},
+}
+
+// LetterData são os dados disponíveis nos modelos de carta
+type LetterData struct {
+	Member            *domain.Member
+	Community         *domain.Community
+	DestinationChurch string
+	// Data de emissão por extenso
+	Date string
+	// Datas por extenso; vazias quando não informadas
+	MembershipDate string
+	BaptismDate    string
+	// "o", "a" ou "o(a)" e "irmão", "irmã" ou "irmão(ã)" conforme o gênero do membro
+	Article string
+	Brother string
+}
+
+// LetterRequest são os dados de emissão de uma carta
+type LetterRequest struct {
+	Type              string
+	DestinationChurch string
+	SignerName        string
+	SignerTitle       string
+	// Registra a saída do membro no histórico ao emitir a carta de transferência
+	RegisterTransfer bool
+}
+
+// MembershipService mantém o histórico de membresia dos membros (recepção, batismo,
+// transferências, disciplina, falecimento) e emite as cartas de transferência e de
+// recomendação com o timbre da comunidade.
+type MembershipService struct {
+	repos     *repository.Repositories
+	logger    *zap.Logger
+	uploadDir string
+}
+
+func NewMembershipService(repos *repository.Repositories, logger *zap.Logger, uploadDir string) *MembershipService {
+	return &MembershipService{
+		repos:     repos,
+		logger:    logger,
+		uploadDir: uploadDir,
+	}
+}
+
+// ListEvents lista o histórico de membresia do membro, incluindo os registros anulados
+func (s *MembershipService) ListEvents(ctx context.Context, communityID, memberID string) ([]*domain.MembershipEvent, error) {
+	if _, err := s.findMember(ctx, communityID, memberID); err != nil {
+		return nil, err
+	}
+	events, err := s.repos.Membership.ListEvents(ctx, communityID, memberID, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico de membresia: %v", err)
+	}
+	return events, nil
+}
+
+// RecordEvent registra um evento no histórico e recalcula os dados de membresia do membro
+func (s *MembershipService) RecordEvent(ctx context.Context, event *domain.MembershipEvent, recordedBy string) (*domain.Member, error) {
+	member, err := s.findMember(ctx, event.CommunityID, event.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.location(ctx, event.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Church = strings.TrimSpace(event.Church)
+	event.Location = strings.TrimSpace(event.Location)
+	event.Notes = strings.TrimSpace(event.Notes)
+	if !containsString(membershipEventTypes, event.Type) {
+		return nil, fmt.Errorf("%w: tipo aceita apenas %s", ErrInvalidMembershipEvent, strings.Join(membershipEventTypes, ", "))
+	}
+	today := dueDate(time.Now(), location, 0)
+	if event.Date.IsZero() {
+		event.Date = today
+	}
+	event.Date = time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+	if event.Date.After(today) {
+		return nil, fmt.Errorf("%w: a data não pode ser futura", ErrInvalidMembershipEvent)
+	}
+	if (event.Type == domain.MembershipEventTransferredIn || event.Type == domain.MembershipEventTransferredOut) && event.Church == "" {
+		return nil, fmt.Errorf("%w: informe a igreja da transferência", ErrInvalidMembershipEvent)
+	}
+
+	events, err := s.repos.Membership.ListEvents(ctx, event.CommunityID, event.MemberID, false)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico de membresia: %v", err)
+	}
+	if latest := latestStatusEvent(events); latest != nil {
+		if latest.Type == domain.MembershipEventDeceased {
+			return nil, fmt.Errorf("%w: o falecimento do membro já foi registrado", ErrInvalidMembershipEvent)
+		}
+		if event.Type == domain.MembershipEventReinstated && latest.Type != domain.MembershipEventDisciplined {
+			return nil, fmt.Errorf("%w: apenas membros em disciplina podem ser restaurados", ErrInvalidMembershipEvent)
+		}
+	} else if event.Type == domain.MembershipEventReinstated {
+		return nil, fmt.Errorf("%w: apenas membros em disciplina podem ser restaurados", ErrInvalidMembershipEvent)
+	}
+
+	event.ID = ""
+	event.CreatedAt = time.Now()
+	event.VoidedAt = nil
+	event.VoidedBy = nil
+	event.VoidReason = ""
+	if recordedBy != "" {
+		event.RecordedBy = &recordedBy
+	}
+
+	history := s.applyEvents(member, insertEvent(events, event), nil, recordedBy)
+	if err := s.repos.Membership.SaveEvent(ctx, event, member, history); err != nil {
+		return nil, fmt.Errorf("erro ao registrar evento de membresia: %v", err)
+	}
+	return member, nil
+}
+
+// VoidEvent anula um registro lançado por engano e recalcula os dados do membro
+func (s *MembershipService) VoidEvent(ctx context.Context, communityID, memberID, eventID, voidedBy, reason string) (*domain.MembershipEvent, *domain.Member, error) {
+	member, err := s.findMember(ctx, communityID, memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+	event, err := s.repos.Membership.FindEventByID(ctx, communityID, memberID, eventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar registro de membresia: %v", err)
+	}
+	if event == nil {
+		return nil, nil, ErrMembershipEventNotFound
+	}
+	if event.VoidedAt != nil {
+		return nil, nil, ErrMembershipEventVoided
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil, fmt.Errorf("%w: informe o motivo da anulação", ErrInvalidMembershipEvent)
+	}
+
+	events, err := s.repos.Membership.ListEvents(ctx, communityID, memberID, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao listar histórico de membresia: %v", err)
+	}
+	remaining := make([]*domain.MembershipEvent, 0, len(events))
+	for _, e := range events {
+		if e.ID != event.ID {
+			remaining = append(remaining, e)
+		}
+	}
+
+	now := time.Now()
+	event.VoidedAt = &now
+	event.VoidReason = reason
+	if voidedBy != "" {
+		event.VoidedBy = &voidedBy
+	}
+
+	history := s.applyEvents(member, remaining, event, voidedBy)
+	if err := s.repos.Membership.SaveEvent(ctx, event, member, history); err != nil {
+		return nil, nil, fmt.Errorf("erro ao anular registro de membresia: %v", err)
+	}
+	return event, member, nil
+}
+
+// applyEvents recalcula os dados de membresia do membro a partir dos eventos válidos,
+// em ordem cronológica, e retorna o registro do histórico quando o tipo ou a situação
+// mudam. Os dados sem nenhum evento correspondente são mantidos como estavam no
+// cadastro, exceto quando o evento anulado (voided) era o único que os definia.
+func (s *MembershipService) applyEvents(member *domain.Member, events []*domain.MembershipEvent, voided *domain.MembershipEvent, changedBy string) *domain.MemberStatusHistory {
+	previousType, previousStatus := member.Type, member.Status
+
+	var entry, baptism, transferIn, transferOut, transfer, status *domain.MembershipEvent
+	for _, event := range events {
+		switch event.Type {
+		case domain.MembershipEventJoined:
+			entry, status = event, event
+		case domain.MembershipEventBaptized:
+			baptism = event
+		case domain.MembershipEventTransferredIn:
+			entry, status, transferIn, transfer = event, event, event, event
+		case domain.MembershipEventTransferredOut:
+			status, transferOut, transfer = event, event, event
+		default:
+			status = event
+		}
+	}
+
+	if entry != nil {
+		date := entry.Date
+		member.MembershipDate = &date
+		if entry.Type == domain.MembershipEventTransferredIn {
+			member.Type = "transferred"
+		} else if member.Type == "visitor" {
+			member.Type = "regular"
+		}
+	} else if voided != nil && (voided.Type == domain.MembershipEventJoined || voided.Type == domain.MembershipEventTransferredIn) {
+		member.MembershipDate = nil
+	}
+
+	if baptism != nil {
+		date := baptism.Date
+		member.BaptismDate = &date
+		member.BaptismLocation = baptism.Location
+	} else if voided != nil && voided.Type == domain.MembershipEventBaptized {
+		member.BaptismDate = nil
+		member.BaptismLocation = ""
+	}
+
+	if transferIn != nil {
+		member.TransferredFrom = transferIn.Church
+	} else if voided != nil && voided.Type == domain.MembershipEventTransferredIn {
+		member.TransferredFrom = ""
+	}
+	if transferOut != nil {
+		member.TransferredTo = transferOut.Church
+	} else if voided != nil && voided.Type == domain.MembershipEventTransferredOut {
+		member.TransferredTo = ""
+	}
+	if transfer != nil {
+		date := transfer.Date
+		member.TransferDate = &date
+	} else if voided != nil && (voided.Type == domain.MembershipEventTransferredIn || voided.Type == domain.MembershipEventTransferredOut) {
+		member.TransferDate = nil
+	}
+
+	if status != nil {
+		switch status.Type {
+		case domain.MembershipEventJoined, domain.MembershipEventTransferredIn, domain.MembershipEventReinstated:
+			member.Status = "active"
+		default:
+			member.Status = "inactive"
+		}
+	}
+	member.UpdatedAt = time.Now()
+
+	if member.Type == previousType && member.Status == previousStatus {
+		return nil
+	}
+	history := &domain.MemberStatusHistory{
+		CommunityID: member.CommunityID,
+		MemberID:    member.ID,
+		FromStageID: member.PipelineStageID,
+		ToStageID:   member.PipelineStageID,
+		FromType:    previousType,
+		ToType:      member.Type,
+		FromStatus:  previousStatus,
+		ToStatus:    member.Status,
+		Source:      domain.StatusChangeSourceMembership,
+		CreatedAt:   time.Now(),
+	}
+	if changedBy != "" {
+		history.ChangedBy = &changedBy
+	}
+	if voided != nil {
+		history.Notes = "Anulação: " + voided.VoidReason
+	} else if status != nil {
+		history.Notes = status.Notes
+	}
+	return history
+}
+
+// GetTemplate retorna o modelo de carta da comunidade ou o modelo padrão
+func (s *MembershipService) GetTemplate(ctx context.Context, communityID, letterType string) (*domain.LetterTemplate, error) {
+	if !containsString(letterTypes, letterType) {
+		return nil, ErrInvalidLetterType
+	}
+	letterTemplate, err := s.repos.Membership.FindTemplate(ctx, communityID, letterType)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar modelo de carta: %v", err)
+	}
+	if letterTemplate == nil {
+		defaults := defaultLetterTemplates[letterType]
+		letterTemplate = &defaults
+		letterTemplate.CommunityID = communityID
+		letterTemplate.Type = letterType
+	}
+	return letterTemplate, nil
+}
+
+// UpdateTemplate personaliza o modelo de carta da comunidade
+func (s *MembershipService) UpdateTemplate(ctx context.Context, input *domain.LetterTemplate, updatedBy string) (*domain.LetterTemplate, error) {
+	letterTemplate, err := s.GetTemplate(ctx, input.CommunityID, input.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	letterTemplate.Title = strings.TrimSpace(input.Title)
+	letterTemplate.Body = strings.TrimSpace(input.Body)
+	letterTemplate.SignerName = strings.TrimSpace(input.SignerName)
+	letterTemplate.SignerTitle = strings.TrimSpace(input.SignerTitle)
+	if letterTemplate.Title == "" || letterTemplate.Body == "" {
+		return nil, fmt.Errorf("%w: título e texto são obrigatórios", ErrInvalidLetterTemplate)
+	}
+
+	// Valida o modelo com dados de exemplo para detectar campos inexistentes
+	sample := &LetterData{
+		Member:            &domain.Member{Name: "Nome do Membro"},
+		Community:         &domain.Community{Name: "Nome da Comunidade"},
+		DestinationChurch: "Igreja de Destino",
+		Date:              longDate(time.Now()),
+		MembershipDate:    longDate(time.Now()),
+		BaptismDate:       longDate(time.Now()),
+		Article:           "o(a)",
+		Brother:           "irmão(ã)",
+	}
+	if _, err := renderLetter(letterTemplate.Body, sample); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLetterTemplate, err)
+	}
+
+	letterTemplate.UpdatedBy = &updatedBy
+	letterTemplate.UpdatedAt = time.Now()
+	if letterTemplate.ID == "" {
+		letterTemplate.CreatedAt = time.Now()
+	}
+	if err := s.repos.Membership.SaveTemplate(ctx, letterTemplate); err != nil {
+		return nil, fmt.Errorf("erro ao salvar modelo de carta: %v", err)
+	}
+	return letterTemplate, nil
+}
+
+// IssueLetter emite uma carta para o membro a partir do modelo da comunidade. A carta de
+// transferência pode registrar a saída do membro no histórico na mesma operação.
+func (s *MembershipService) IssueLetter(ctx context.Context, communityID, memberID string, request *LetterRequest, issuedBy string) (*domain.MemberLetter, *domain.Member, error) {
+	if !containsString(letterTypes, request.Type) {
+		return nil, nil, ErrInvalidLetterType
+	}
+	request.DestinationChurch = strings.TrimSpace(request.DestinationChurch)
+	if request.Type == domain.MemberLetterTransfer && request.DestinationChurch == "" {
+		return nil, nil, fmt.Errorf("%w: informe a igreja de destino", ErrInvalidLetter)
+	}
+
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, nil, ErrCommunityNotFound
+	}
+	member, err := s.findMember(ctx, communityID, memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member.Status != "active" {
+		return nil, nil, ErrMemberNotInCommunion
+	}
+
+	letterTemplate, err := s.GetTemplate(ctx, communityID, request.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	location := communityLocation(community)
+	data := &LetterData{
+		Member:            member,
+		Community:         community,
+		DestinationChurch: request.DestinationChurch,
+		Date:              longDate(now.In(location)),
+	}
+	if member.MembershipDate != nil {
+		data.MembershipDate = longDate(*member.MembershipDate)
+	}
+	if member.BaptismDate != nil {
+		data.BaptismDate = longDate(*member.BaptismDate)
+	}
+	switch member.Gender {
+	case "male":
+		data.Article, data.Brother = "o", "irmão"
+	case "female":
+		data.Article, data.Brother = "a", "irmã"
+	default:
+		data.Article, data.Brother = "o(a)", "irmão(ã)"
+	}
+
+	body, err := renderLetter(letterTemplate.Body, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidLetterTemplate, err)
+	}
+
+	letter := &domain.MemberLetter{
+		CommunityID:       communityID,
+		MemberID:          memberID,
+		Type:              request.Type,
+		Year:              now.In(location).Year(),
+		DestinationChurch: request.DestinationChurch,
+		Title:             letterTemplate.Title,
+		Body:              body,
+		SignerName:        letterTemplate.SignerName,
+		SignerTitle:       letterTemplate.SignerTitle,
+		IssuedBy:          issuedBy,
+		IssuedAt:          now,
+	}
+	if signer := strings.TrimSpace(request.SignerName); signer != "" {
+		letter.SignerName = signer
+	}
+	if title := strings.TrimSpace(request.SignerTitle); title != "" {
+		letter.SignerTitle = title
+	}
+
+	var event *domain.MembershipEvent
+	var history *domain.MemberStatusHistory
+	if request.Type == domain.MemberLetterTransfer && request.RegisterTransfer {
+		events, err := s.repos.Membership.ListEvents(ctx, communityID, memberID, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao listar histórico de membresia: %v", err)
+		}
+		event = &domain.MembershipEvent{
+			CommunityID: communityID,
+			MemberID:    memberID,
+			Type:        domain.MembershipEventTransferredOut,
+			Date:        dueDate(now, location, 0),
+			Church:      request.DestinationChurch,
+			Notes:       "Carta de transferência emitida",
+			RecordedBy:  &issuedBy,
+			CreatedAt:   now,
+		}
+		history = s.applyEvents(member, insertEvent(events, event), nil, issuedBy)
+	}
+
+	if err := s.repos.Membership.CreateLetter(ctx, letter, event, member, history); err != nil {
+		return nil, nil, fmt.Errorf("erro ao emitir carta: %v", err)
+	}
+	return letter, member, nil
+}
+
+// ListLetters lista as cartas emitidas para o membro
+func (s *MembershipService) ListLetters(ctx context.Context, communityID, memberID string) ([]*domain.MemberLetter, error) {
+	if _, err := s.findMember(ctx, communityID, memberID); err != nil {
+		return nil, err
+	}
+	letters, err := s.repos.Membership.ListLetters(ctx, communityID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar cartas: %v", err)
+	}
+	return letters, nil
+}
+
+// LetterPDF gera a segunda via em PDF de uma carta emitida
+func (s *MembershipService) LetterPDF(ctx context.Context, communityID, memberID, letterID string) (*domain.MemberLetter, []byte, error) {
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, nil, ErrCommunityNotFound
+	}
+	letter, err := s.repos.Membership.FindLetterByID(ctx, communityID, memberID, letterID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar carta: %v", err)
+	}
+	if letter == nil {
+		return nil, nil, ErrMemberLetterNotFound
+	}
+
+	content, err := s.RenderLetterPDF(community, letter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao gerar PDF da carta: %v", err)
+	}
+	return letter, content, nil
+}
+
+// RenderLetterPDF gera a carta em papel timbrado da comunidade
+func (s *MembershipService) RenderLetterPDF(community *domain.Community, letter *domain.MemberLetter) ([]byte, error) {
+	doc := pdf.New()
+	page := doc.AddPage()
+	margin := 56.0
+	right := page.Width() - margin
+
+	// Timbre com o logo e os dados da comunidade
+	textX := margin
+	if logo := loadCommunityLogo(s.uploadDir, community, s.logger); logo != nil {
+		if img, err := doc.AddImage(logo); err == nil {
+			width, height := fitImage(logo, 60, 60)
+			page.DrawImage(img, margin, 40, width, height)
+			textX = margin + 72
+		}
+	}
+	y := 40 + 14.0
+	page.Text(textX, y, 16, true, community.Name)
+	for _, line := range communityAddress(community) {
+		y += 13
+		page.Text(textX, y, 9, false, line)
+	}
+	if y < 100 {
+		y = 100
+	}
+	y += 16
+	page.Line(margin, y, right, y, 0.5)
+
+	y += 24
+	page.TextRight(right, y, 10, false, LetterNumber(letter))
+	y += 40
+	page.TextCenter(page.Width()/2, y, 14, true, letter.Title)
+	y += 36
+
+	for _, line := range pdf.WrapText(letter.Body, 11, right-margin) {
+		if y > page.Height()-margin {
+			page = doc.AddPage()
+			y = margin
+		}
+		page.Text(margin, y, 11, false, line)
+		y += 11 * 1.5
+	}
+
+	// Local, data e assinatura
+	if y > page.Height()-160 {
+		page = doc.AddPage()
+		y = margin
+	}
+	place := longDate(letter.IssuedAt.In(communityLocation(community)))
+	if community.City != "" {
+		place = community.City + ", " + place
+	}
+	y += 20
+	page.TextRight(right, y, 11, false, place+".")
+
+	y += 70
+	center := page.Width() / 2
+	page.Line(center-120, y, center+120, y, 0.5)
+	if letter.SignerName != "" {
+		y += 15
+		page.TextCenter(center, y, 11, true, letter.SignerName)
+	}
+	if letter.SignerTitle != "" {
+		y += 14
+		page.TextCenter(center, y, 10, false, letter.SignerTitle)
+	}
+
+	return doc.Bytes()
+}
+
+// LetterNumber retorna o número da carta no formato "Carta nº 0001/2026"
+func LetterNumber(letter *domain.MemberLetter) string {
+	return fmt.Sprintf("Carta nº %04d/%d", letter.Number, letter.Year)
+}
+
+// LetterFilename retorna o nome do arquivo da carta
+func LetterFilename(letter *domain.MemberLetter) string {
+	prefix := "carta-recomendacao"
+	if letter.Type == domain.MemberLetterTransfer {
+		prefix = "carta-transferencia"
+	}
+	return fmt.Sprintf("%s-%04d-%d.pdf", prefix, letter.Number, letter.Year)
+}
+
+func (s *MembershipService) findMember(ctx context.Context, communityID, memberID string) (*domain.Member, error) {
+	member, err := s.repos.Member.FindByID(ctx, communityID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+func (s *MembershipService) location(ctx context.Context, communityID string) (*time.Location, error) {
+	community, err := s.repos.Community.FindByID(ctx, communityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar comunidade: %v", err)
+	}
+	if community == nil {
+		return nil, ErrCommunityNotFound
+	}
+	return communityLocation(community), nil
+}
+
+// insertEvent inclui o evento na lista mantendo a ordem cronológica; eventos do mesmo
+// dia ficam na ordem em que foram registrados
+func insertEvent(events []*domain.MembershipEvent, event *domain.MembershipEvent) []*domain.MembershipEvent {
+	result := make([]*domain.MembershipEvent, 0, len(events)+1)
+	inserted := false
+	for _, e := range events {
+		if !inserted && e.Date.After(event.Date) {
+			result = append(result, event)
+			inserted = true
+		}
+		result = append(result, e)
+	}
+	if !inserted {
+		result = append(result, event)
+	}
+	return result
+}
+
+// latestStatusEvent retorna o último evento que altera a situação do membro
+func latestStatusEvent(events []*domain.MembershipEvent) *domain.MembershipEvent {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type != domain.MembershipEventBaptized {
+			return events[i]
+		}
+	}
+	return nil
+}
+
+func renderLetter(body string, data *LetterData) (string, error) {
+	tmpl, err := template.New("letter").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+var monthNames = []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+	"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+
+// longDate formata a data por extenso ("5 de março de 2026")
+func longDate(t time.Time) string {
+	return fmt.Sprintf("%d de %s de %d", t.Day(), monthNames[t.Month()-1], t.Year())
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestLongDate(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("fuso horário indisponível: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"dia sem zero à esquerda", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), "5 de março de 2026"},
+		{"dezembro", time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "31 de dezembro de 2025"},
+		// A emissão usa o fuso da comunidade: 02h em UTC ainda é o dia anterior em São Paulo
+		{"emissão no fuso da comunidade", time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC).In(saoPaulo), "31 de dezembro de 2025"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := longDate(tt.date); got != tt.want {
+				t.Errorf("longDate(%s) = %q; esperado %q", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDefaultLetters(t *testing.T) {
+	member := &domain.Member{Name: "Ana Souza"}
+	community := &domain.Community{Name: "Igreja Central"}
+
+	tests := []struct {
+		name       string
+		letterType string
+		data       LetterData
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "transferência com as datas de membresia",
+			letterType: domain.MemberLetterTransfer,
+			data: LetterData{
+				DestinationChurch: "Igreja do Bairro",
+				Date:              "10 de abril de 2026",
+				MembershipDate:    "5 de março de 2020",
+				BaptismDate:       "12 de julho de 2015",
+				Article:           "a",
+				Brother:           "irmã",
+			},
+			want: []string{"Igreja do Bairro", "a irmã Ana Souza", "desde 5 de março de 2020", "com batismo em 12 de julho de 2015"},
+		},
+		{
+			name:       "recomendação sem data de membresia",
+			letterType: domain.MemberLetterRecommendation,
+			data:       LetterData{Date: "10 de abril de 2026", Article: "o(a)", Brother: "irmão(ã)"},
+			want:       []string{"o(a) irmão(ã) Ana Souza, membro desta igreja, que"},
+			notWant:    []string{"desde"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.data.Member, tt.data.Community = member, community
+			body, err := renderLetter(defaultLetterTemplates[tt.letterType].Body, &tt.data)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("carta sem %q:\n%s", want, body)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("carta com %q:\n%s", notWant, body)
+				}
+			}
+		})
+	}
+
+	if _, err := renderLetter("{{.Inexistente}}", &LetterData{}); err == nil {
+		t.Error("modelo com campo inexistente renderizado sem erro")
+	}
+}
+
+func TestInsertEvent(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+	events := []*domain.MembershipEvent{
+		{ID: "a", Date: date(1)},
+		{ID: "b", Date: date(10)},
+		{ID: "c", Date: date(10)},
+		{ID: "d", Date: date(20)},
+	}
+
+	tests := []struct {
+		name  string
+		event *domain.MembershipEvent
+		want  string
+	}{
+		{"antes de todos", &domain.MembershipEvent{ID: "x", Date: date(1).AddDate(0, 0, -1)}, "xabcd"},
+		{"mesmo dia fica depois dos registrados", &domain.MembershipEvent{ID: "x", Date: date(10)}, "abcxd"},
+		{"depois de todos", &domain.MembershipEvent{ID: "x", Date: date(25)}, "abcdx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			for _, event := range insertEvent(events, tt.event) {
+				got.WriteString(event.ID)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ordem = %s; esperado %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMembershipEvents(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	s := &MembershipService{}
+
+	t.Run("transferência recebida, batismo e saída", func(t *testing.T) {
+		member := &domain.Member{Type: "visitor", Status: "inactive"}
+		events := []*domain.MembershipEvent{
+			{Type: domain.MembershipEventBaptized, Date: date(2015, 7, 12), Location: "Rio Jordão"},
+			{Type: domain.MembershipEventTransferredIn, Date: date(2020, 3, 5), Church: "Igreja Antiga"},
+			{Type: domain.MembershipEventTransferredOut, Date: date(2026, 4, 10), Church: "Igreja Nova"},
+		}
+
+		history := s.applyEvents(member, events, nil, "u1")
+
+		if member.MembershipDate == nil || !member.MembershipDate.Equal(date(2020, 3, 5)) {
+			t.Errorf("membro desde %v; esperado 05/03/2020", member.MembershipDate)
+		}
+		if member.BaptismDate == nil || !member.BaptismDate.Equal(date(2015, 7, 12)) || member.BaptismLocation != "Rio Jordão" {
+			t.Errorf("batismo em %v em %q", member.BaptismDate, member.BaptismLocation)
+		}
+		if member.TransferDate == nil || !member.TransferDate.Equal(date(2026, 4, 10)) {
+			t.Errorf("transferência em %v; esperado a última, 10/04/2026", member.TransferDate)
+		}
+		if member.TransferredFrom != "Igreja Antiga" || member.TransferredTo != "Igreja Nova" {
+			t.Errorf("transferido de %q para %q", member.TransferredFrom, member.TransferredTo)
+		}
+		if member.Type != "transferred" || member.Status != "inactive" {
+			t.Errorf("tipo %q e situação %q; esperado transferred e inactive", member.Type, member.Status)
+		}
+		if history == nil || history.FromType != "visitor" || history.ToType != "transferred" {
+			t.Errorf("histórico = %+v; esperado a mudança de tipo", history)
+		}
+	})
+
+	t.Run("anulação do único batismo limpa a data", func(t *testing.T) {
+		baptism := date(2015, 7, 12)
+		member := &domain.Member{Type: "regular", Status: "active", BaptismDate: &baptism, BaptismLocation: "Rio Jordão"}
+		voided := &domain.MembershipEvent{Type: domain.MembershipEventBaptized, Date: baptism, VoidReason: "registro duplicado"}
+		events := []*domain.MembershipEvent{
+			{Type: domain.MembershipEventJoined, Date: date(2020, 3, 5)},
+		}
+
+		if history := s.applyEvents(member, events, voided, "u1"); history != nil {
+			t.Errorf("histórico = %+v; esperado nenhum sem mudança de situação", history)
+		}
+		if member.BaptismDate != nil || member.BaptismLocation != "" {
+			t.Errorf("batismo em %v em %q; esperado vazio", member.BaptismDate, member.BaptismLocation)
+		}
+		if member.MembershipDate == nil || !member.MembershipDate.Equal(date(2020, 3, 5)) {
+			t.Errorf("membro desde %v; esperado 05/03/2020", member.MembershipDate)
+		}
+	})
+
+	t.Run("datas sem evento são mantidas", func(t *testing.T) {
+		joined := date(2010, 1, 1)
+		member := &domain.Member{Type: "regular", Status: "active", MembershipDate: &joined}
+
+		s.applyEvents(member, []*domain.MembershipEvent{{Type: domain.MembershipEventDisciplined, Date: date(2026, 2, 1)}}, nil, "")
+
+		if member.MembershipDate == nil || !member.MembershipDate.Equal(joined) {
+			t.Errorf("membro desde %v; esperado a data do cadastro", member.MembershipDate)
+		}
+		if member.Status != "inactive" {
+			t.Errorf("situação = %q; esperado inactive", member.Status)
+		}
+	})
+}
+
+func TestLetterNumberAndFilename(t *testing.T) {
+	letter := &domain.MemberLetter{Type: domain.MemberLetterTransfer, Number: 7, Year: 2026}
+	if got := LetterNumber(letter); got != "Carta nº 0007/2026" {
+		t.Errorf("LetterNumber = %q", got)
+	}
+	if got := LetterFilename(letter); got != "carta-transferencia-0007-2026.pdf" {
+		t.Errorf("LetterFilename = %q", got)
+	}
+	letter.Type = domain.MemberLetterRecommendation
+	if got := LetterFilename(letter); got != "carta-recomendacao-0007-2026.pdf" {
+		t.Errorf("LetterFilename = %q", got)
+	}
+}
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownPaymentGateway    = errors.New("provedor de pagamentos desconhecido")
+	ErrGatewayUnsupported       = errors.New("operação não suportada pelo provedor de pagamentos")
+	ErrGatewayNotConfigured     = errors.New("o provedor de pagamentos da comunidade não está configurado")
+	ErrPaymentMethodUnsupported = errors.New("forma de pagamento não aceita pelo provedor de pagamentos da comunidade")
+	ErrInvalidPaymentEvent      = errors.New("evento de pagamento inválido")
+	ErrInvalidPixSettings       = errors.New("informe a chave Pix, o nome e a cidade do recebedor")
+)
+
+// Provedores de pagamento disponíveis
+const (
+	GatewayAsaas = "asaas"
+	GatewayPix   = "pix"
+	GatewayFake  = "fake"
+)
+
+// Eventos de cobrança normalizados, independentes do provedor
+const (
+	PaymentEventPaid     = "paid"
+	PaymentEventOverdue  = "overdue"
+	PaymentEventRefunded = "refunded"
+	PaymentEventDeleted  = "deleted"
+
+	// PaymentEventChargeback indica a contestação do pagamento pelo titular do cartão e
+	// PaymentEventChargebackReversed, a contestação decidida a favor da comunidade
+	PaymentEventChargeback         = "chargeback"
+	PaymentEventChargebackReversed = "chargeback_reversed"
+)
+
+// PaymentGateway é um provedor de pagamentos. Cada comunidade escolhe o seu provedor e
+// as cobranças guardam o provedor em que foram criadas, para que as operações
+// seguintes sejam feitas no mesmo lugar.
+type PaymentGateway interface {
+	// Name identifica o provedor nas rotas de webhook e nos registros
+	Name() string
+	// Methods são as formas de pagamento aceitas pelo provedor
+	Methods() []string
+	// Configured indica se a comunidade possui credenciais para receber pelo provedor
+	Configured(ctx context.Context, communityID string) (bool, error)
+
+	CreateCustomer(ctx context.Context, communityID string, customer *PaymentCustomer) (string, error)
+
+	CreateCharge(ctx context.Context, donation *domain.Donation, customerID string) (*PaymentCharge, error)
+	UpdateCharge(ctx context.Context, donation *domain.Donation) error
+	CancelCharge(ctx context.Context, donation *domain.Donation) error
+	SendChargeLink(ctx context.Context, donation *domain.Donation) error
+	PixQrCode(ctx context.Context, donation *domain.Donation) (*PixQrCode, error)
+	// RefundCharge devolve o valor informado de uma cobrança paga
+	RefundCharge(ctx context.Context, donation *domain.Donation, amount float64) error
+
+	CreateSubscription(ctx context.Context, recurring *domain.RecurringDonation, customerID string) (string, error)
+	UpdateSubscription(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error
+	UpdateSubscriptionCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) (*CardInfo, error)
+	CancelSubscription(ctx context.Context, recurring *domain.RecurringDonation) error
+
+	// ParseWebhook autentica o webhook do provedor e o converte em um evento normalizado.
+	// Eventos sem efeito nas doações são devolvidos com Type vazio.
+	ParseWebhook(ctx context.Context, header http.Header, body []byte) (*PaymentEvent, error)
+}
+
+// PaymentCustomer são os dados do pagador enviados ao provedor
+type PaymentCustomer struct {
+	Name     string
+	CPF      string
+	Email    string
+	Phone    string
+	Street   string
+	Number   string
+	District string
+	City     string
+	State    string
+	ZipCode  string
+}
+
+// PaymentCard é o cartão de crédito informado pelo doador
+type PaymentCard struct {
+	HolderName  string
+	Number      string
+	ExpiryMonth string
+	ExpiryYear  string
+	CVV         string
+}
+
+// PaymentCardHolder são os dados do titular do cartão enviados ao provedor
+type PaymentCardHolder struct {
+	Name     string
+	CPF      string
+	Email    string
+	Phone    string
+	Street   string
+	Number   string
+	District string
+	City     string
+	State    string
+	ZipCode  string
+}
+
+// PaymentCharge é a cobrança criada no provedor
+type PaymentCharge struct {
+	ID          string
+	PaymentLink string
+}
+
+// SubscriptionUpdate são os campos alterados em uma assinatura. Campos vazios não são
+// enviados ao provedor. Status aceita "active" e "paused".
+type SubscriptionUpdate struct {
+	Amount                *float64
+	NextDueDate           *time.Time
+	Status                string
+	UpdatePendingPayments bool
+}
+
+// CardInfo identifica o cartão salvo na assinatura
+type CardInfo struct {
+	Brand      string
+	LastDigits string
+}
+
+// PaymentEvent é um evento de cobrança recebido por webhook, já associado à comunidade
+type PaymentEvent struct {
+	CommunityID    string
+	Event          string
+	Type           string
+	ChargeID       string
+	SubscriptionID string
+	Amount         float64
+	Method         string
+	DueDate        time.Time
+	PaidAt         time.Time
+	PaymentLink    string
+}
+
+// PaymentGatewaySettingsInput são os dados informados na configuração do provedor
+type PaymentGatewaySettingsInput struct {
+	Provider          string
+	PixKey            string
+	MerchantName      string
+	MerchantCity      string
+	RegenerateWebhook bool
+}
+
+// PaymentService encaminha as cobranças e assinaturas ao provedor de pagamentos de
+// cada comunidade e processa os webhooks de todos os provedores.
+type PaymentService struct {
+	repos    *repository.Repositories
+	logger   *zap.Logger
+	posting  *DonationPostingService
+	gateways map[string]PaymentGateway
+}
+
+func NewPaymentService(repos *repository.Repositories, logger *zap.Logger, gateways ...PaymentGateway) *PaymentService {
+	registry := make(map[string]PaymentGateway, len(gateways))
+	for _, gateway := range gateways {
+		registry[gateway.Name()] = gateway
+	}
+	return &PaymentService{
+		repos:    repos,
+		logger:   logger,
+		posting:  NewDonationPostingService(repos, logger),
+		gateways: registry,
+	}
+}
+
+// Providers lista os provedores registrados
+func (s *PaymentService) Providers() []string {
+	providers := make([]string, 0, len(s.gateways))
+	for name := range s.gateways {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// Gateway retorna o provedor escolhido pela comunidade
+func (s *PaymentService) Gateway(ctx context.Context, communityID string) (PaymentGateway, error) {
+	settings, err := s.Settings(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.gateway(settings.Provider)
+}
+
+// gateway retorna o provedor pelo nome. Registros anteriores à escolha de provedor
+// não possuem nome e pertencem ao ASAAS.
+func (s *PaymentService) gateway(name string) (PaymentGateway, error) {
+	if name == "" {
+		name = GatewayAsaas
+	}
+	gateway, ok := s.gateways[name]
+	if !ok {
+		return nil, ErrUnknownPaymentGateway
+	}
+	return gateway, nil
+}
+
+// Settings retorna a configuração do provedor da comunidade. Sem configuração, a
+// comunidade usa o ASAAS.
+func (s *PaymentService) Settings(ctx context.Context, communityID string) (*domain.PaymentGatewaySettings, error) {
+	settings, err := s.repos.PaymentGateway.FindByCommunityID(ctx, communityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &domain.PaymentGatewaySettings{CommunityID: communityID, Provider: GatewayAsaas}, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar provedor de pagamentos: %v", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings troca o provedor da comunidade. As cobranças e assinaturas já
+// criadas continuam no provedor de origem. Quando um novo token de webhook é gerado, ele
+// é devolvido em webhookToken; depois disso só é exibido mascarado.
+func (s *PaymentService) UpdateSettings(ctx context.Context, communityID string, input *PaymentGatewaySettingsInput) (*domain.PaymentGatewaySettings, string, error) {
+	if _, err := s.gateway(input.Provider); err != nil {
+		return nil, "", err
+	}
+
+	settings, err := s.Settings(ctx, communityID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	settings.Provider = input.Provider
+	settings.PixKey = strings.TrimSpace(input.PixKey)
+	settings.MerchantName = strings.TrimSpace(input.MerchantName)
+	settings.MerchantCity = strings.TrimSpace(input.MerchantCity)
+	if settings.Provider == GatewayPix && (settings.PixKey == "" || settings.MerchantName == "" || settings.MerchantCity == "") {
+		return nil, "", ErrInvalidPixSettings
+	}
+
+	// O token autentica os webhooks do provedor Pix; o ASAAS usa o token da sua configuração
+	var webhookToken string
+	if settings.WebhookToken == "" || input.RegenerateWebhook {
+		webhookToken, err = newWebhookToken()
+		if err != nil {
+			return nil, "", err
+		}
+		settings.WebhookToken = webhookToken
+	}
+
+	now := time.Now()
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	settings.UpdatedAt = now
+	if err := s.repos.PaymentGateway.Save(ctx, settings); err != nil {
+		return nil, "", fmt.Errorf("erro ao salvar provedor de pagamentos: %v", err)
+	}
+	return settings, webhookToken, nil
+}
+
+// CreateDonationCharge cria o pagador e a cobrança da doação no provedor da comunidade
+func (s *PaymentService) CreateDonationCharge(ctx context.Context, donation *domain.Donation) error {
+	gateway, err := s.Gateway(ctx, donation.CommunityID)
+	if err != nil {
+		return err
+	}
+	if !containsMethod(gateway.Methods(), donation.PaymentMethod) {
+		return ErrPaymentMethodUnsupported
+	}
+
+	// O ID da doação identifica a cobrança no provedor
+	if donation.ID == "" {
+		donation.ID = uuid.New().String()
+	}
+
+	customerID, err := gateway.CreateCustomer(ctx, donation.CommunityID, donationCustomer(donation))
+	if err != nil {
+		return fmt.Errorf("erro ao criar cliente no provedor %s: %v", gateway.Name(), err)
+	}
+
+	charge, err := gateway.CreateCharge(ctx, donation, customerID)
+	if err != nil {
+		return fmt.Errorf("erro ao criar cobrança no provedor %s: %v", gateway.Name(), err)
+	}
+
+	donation.Gateway = gateway.Name()
+	donation.AsaasID = charge.ID
+	donation.AsaasPaymentID = charge.ID
+	donation.PaymentLink = charge.PaymentLink
+	return nil
+}
+
+// UpdateDonationCharge envia o novo valor, vencimento e descrição da doação ao provedor
+func (s *PaymentService) UpdateDonationCharge(ctx context.Context, donation *domain.Donation) error {
+	if donation.AsaasID == "" {
+		return nil
+	}
+	gateway, err := s.gateway(donation.Gateway)
+	if err != nil {
+		return err
+	}
+	return gateway.UpdateCharge(ctx, donation)
+}
+
+// CancelDonationCharge exclui a cobrança da doação no provedor
+func (s *PaymentService) CancelDonationCharge(ctx context.Context, donation *domain.Donation) error {
+	if donation.AsaasID == "" {
+		return nil
+	}
+	gateway, err := s.gateway(donation.Gateway)
+	if err != nil {
+		return err
+	}
+	return gateway.CancelCharge(ctx, donation)
+}
+
+// SendDonationLink pede ao provedor que envie o link de pagamento ao doador
+func (s *PaymentService) SendDonationLink(ctx context.Context, donation *domain.Donation) error {
+	gateway, err := s.gateway(donation.Gateway)
+	if err != nil {
+		return err
+	}
+	return gateway.SendChargeLink(ctx, donation)
+}
+
+// DonationPixQrCode retorna o QR Code Pix da cobrança da doação
+func (s *PaymentService) DonationPixQrCode(ctx context.Context, donation *domain.Donation) (*PixQrCode, error) {
+	gateway, err := s.gateway(donation.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	return gateway.PixQrCode(ctx, donation)
+}
+
+// CreateSubscription cria o pagador e a assinatura no provedor da comunidade
+func (s *PaymentService) CreateSubscription(ctx context.Context, recurring *domain.RecurringDonation) error {
+	gateway, err := s.Gateway(ctx, recurring.CommunityID)
+	if err != nil {
+		return err
+	}
+	if !containsMethod(gateway.Methods(), recurring.PaymentMethod) {
+		return ErrPaymentMethodUnsupported
+	}
+
+	customerID, err := gateway.CreateCustomer(ctx, recurring.CommunityID, recurringCustomer(recurring))
+	if err != nil {
+		return fmt.Errorf("erro ao criar cliente no provedor %s: %v", gateway.Name(), err)
+	}
+
+	subscriptionID, err := gateway.CreateSubscription(ctx, recurring, customerID)
+	if err != nil {
+		return fmt.Errorf("erro ao criar assinatura no provedor %s: %v", gateway.Name(), err)
+	}
+
+	recurring.Gateway = gateway.Name()
+	recurring.AsaasID = subscriptionID
+	return nil
+}
+
+// UpdateSubscription altera a assinatura no provedor em que ela foi criada
+func (s *PaymentService) UpdateSubscription(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error {
+	if recurring.AsaasID == "" {
+		return nil
+	}
+	gateway, err := s.gateway(recurring.Gateway)
+	if err != nil {
+		return err
+	}
+	return gateway.UpdateSubscription(ctx, recurring, update)
+}
+
+// UpdateSubscriptionCard substitui o cartão da assinatura no provedor
+func (s *PaymentService) UpdateSubscriptionCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) (*CardInfo, error) {
+	gateway, err := s.gateway(recurring.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	return gateway.UpdateSubscriptionCard(ctx, recurring, card, holder, remoteIP)
+}
+
+// CancelSubscription encerra a assinatura no provedor
+func (s *PaymentService) CancelSubscription(ctx context.Context, recurring *domain.RecurringDonation) error {
+	if recurring.AsaasID == "" {
+		return nil
+	}
+	gateway, err := s.gateway(recurring.Gateway)
+	if err != nil {
+		return err
+	}
+	return gateway.CancelSubscription(ctx, recurring)
+}
+
+// containsMethod indica se a forma de pagamento está na lista
+func containsMethod(methods []string, method string) bool {
+	for _, accepted := range methods {
+		if accepted == method {
+			return true
+		}
+	}
+	return false
+}
+
+func donationCustomer(donation *domain.Donation) *PaymentCustomer {
+	return &PaymentCustomer{
+		Name:     donation.CustomerName,
+		CPF:      donation.CustomerCPF,
+		Email:    donation.CustomerEmail,
+		Phone:    donation.CustomerPhone,
+		Street:   donation.BillingAddress.Street,
+		Number:   donation.BillingAddress.Number,
+		District: donation.BillingAddress.District,
+		City:     donation.BillingAddress.City,
+		State:    donation.BillingAddress.State,
+		ZipCode:  donation.BillingAddress.ZipCode,
+	}
+}
+
+func recurringCustomer(recurring *domain.RecurringDonation) *PaymentCustomer {
+	return &PaymentCustomer{
+		Name:     recurring.CustomerName,
+		CPF:      recurring.CustomerCPF,
+		Email:    recurring.CustomerEmail,
+		Phone:    recurring.CustomerPhone,
+		Street:   recurring.BillingAddress.Street,
+		Number:   recurring.BillingAddress.Number,
+		District: recurring.BillingAddress.District,
+		City:     recurring.BillingAddress.City,
+		State:    recurring.BillingAddress.State,
+		ZipCode:  recurring.BillingAddress.ZipCode,
+	}
+}
+
+// newWebhookToken gera um token aleatório para autenticar os webhooks recebidos
+func newWebhookToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("erro ao gerar token do webhook: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
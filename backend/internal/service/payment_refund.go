@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDonationNotFound      = errors.New("doação não encontrada")
+	ErrDonationNotRefundable = errors.New("somente doações pagas podem ser reembolsadas")
+	ErrInvalidRefundAmount   = errors.New("o valor do reembolso deve ser maior que zero e não pode ultrapassar o valor pago")
+	ErrRefundReasonRequired  = errors.New("informe o motivo do reembolso")
+	ErrRefundGateway         = errors.New("não foi possível estornar o pagamento no provedor de pagamentos. Tente novamente")
+)
+
+// DonationRefund é o reembolso solicitado. Sem valor, reembolsa todo o valor ainda não
+// devolvido.
+type DonationRefund struct {
+	Amount *float64
+	Reason string
+}
+
+// RefundDonation estorna total ou parcialmente uma doação paga no provedor em que foi
+// cobrada. O reembolso parcial mantém a doação paga com o valor líquido; o total a
+// move para refunded. A receita lançada e as estatísticas do membro acompanham o
+// valor líquido.
+func (s *PaymentService) RefundDonation(ctx context.Context, communityID, donationID string, refund *DonationRefund) (*domain.Donation, error) {
+	reason := strings.TrimSpace(refund.Reason)
+	if reason == "" {
+		return nil, ErrRefundReasonRequired
+	}
+
+	// A doação fica bloqueada do estorno no provedor até a gravação do reembolso, para que
+	// dois reembolsos simultâneos não ultrapassem o valor pago e o webhook do reembolso
+	// só seja aplicado depois dele
+	var refunded *domain.Donation
+	err := s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		donation, err := s.withRepos(repos).refundDonation(ctx, communityID, donationID, refund.Amount, reason)
+		if err != nil {
+			return err
+		}
+		refunded = donation
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refunded, nil
+}
+
+// refundDonation estorna a doação bloqueada e grava o reembolso na transação de s.repos
+func (s *PaymentService) refundDonation(ctx context.Context, communityID, donationID string, requested *float64, reason string) (*domain.Donation, error) {
+	donation, err := s.repos.Donation.LockByID(ctx, communityID, donationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDonationNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar doação: %v", err)
+	}
+	if donation.Status != "paid" {
+		return nil, ErrDonationNotRefundable
+	}
+
+	remaining := math.Round(donation.NetAmount()*100) / 100
+	amount := remaining
+	if requested != nil {
+		amount = math.Round(*requested*100) / 100
+	}
+	if amount <= 0 || amount > remaining {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	if donation.AsaasID != "" {
+		gateway, err := s.gateway(donation.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		if err := gateway.RefundCharge(ctx, donation, amount); err != nil {
+			if err == ErrGatewayUnsupported {
+				return nil, err
+			}
+			s.logger.Error("erro ao estornar cobrança no provedor de pagamentos",
+				zap.Error(err),
+				zap.String("donation_id", donation.ID))
+			return nil, ErrRefundGateway
+		}
+	}
+	previous := *donation
+
+	now := time.Now()
+	donation.RefundedAmount = math.Round((donation.RefundedAmount+amount)*100) / 100
+	if donation.RefundedAmount >= donation.Amount {
+		donation.RefundedAmount = donation.Amount
+		donation.Status = "refunded"
+	}
+	donation.RefundedAt = &now
+	donation.RefundReason = reason
+	donation.UpdatedAt = now
+	if err := s.repos.Donation.Update(ctx, donation); err != nil {
+		s.logger.Error("reembolso estornado no provedor mas não gravado",
+			zap.Error(err),
+			zap.String("donation_id", donation.ID),
+			zap.Float64("amount", amount))
+		return nil, fmt.Errorf("erro ao atualizar doação: %v", err)
+	}
+
+	// Ajusta a receita lançada para o valor líquido ou a estorna no reembolso total
+	if err := s.posting.SyncDonation(ctx, donation); err != nil {
+		return nil, err
+	}
+	if err := s.syncMemberContribution(ctx, &previous, donation); err != nil {
+		return nil, err
+	}
+
+	return donation, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+func (r *fakeDonationRepository) LockByID(ctx context.Context, communityID, id string) (*domain.Donation, error) {
+	for _, donation := range r.donations {
+		if donation.CommunityID == communityID && donation.ID == id {
+			return donation, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *fakeDonationRepository) Update(ctx context.Context, donation *domain.Donation) error {
+	return nil
+}
+
+type fakeRevenueRepository struct {
+	repository.RevenueRepository
+	revenue *domain.Revenue
+}
+
+func (r *fakeRevenueRepository) FindByDonationID(ctx context.Context, communityID, donationID string) (*domain.Revenue, error) {
+	return r.revenue, nil
+}
+
+func (r *fakeRevenueRepository) Update(ctx context.Context, revenue *domain.Revenue) error {
+	return nil
+}
+
+func TestRefundDonation(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name          string
+		status        string
+		refunded      float64
+		donationID    string
+		amount        *float64
+		wantErr       error
+		wantStatus    string
+		wantRefunded  float64
+		wantRevenue   float64
+		wantCancelled bool
+	}{
+		{name: "reembolso parcial", status: "paid", amount: amount(30), wantStatus: "paid", wantRefunded: 30, wantRevenue: 70},
+		{name: "valor arredondado em centavos", status: "paid", amount: amount(33.333), wantStatus: "paid", wantRefunded: 33.33, wantRevenue: 66.67},
+		{name: "reembolso total sem valor", status: "paid", wantStatus: "refunded", wantRefunded: 100, wantCancelled: true},
+		{name: "restante após reembolso parcial", status: "paid", refunded: 80, amount: amount(20), wantStatus: "refunded", wantRefunded: 100, wantCancelled: true},
+		{name: "valor acima do restante", status: "paid", refunded: 80, amount: amount(20.01), wantErr: ErrInvalidRefundAmount},
+		{name: "valor zero", status: "paid", amount: amount(0), wantErr: ErrInvalidRefundAmount},
+		{name: "doação pendente", status: "pending", wantErr: ErrDonationNotRefundable},
+		{name: "doação já reembolsada", status: "refunded", refunded: 100, wantErr: ErrDonationNotRefundable},
+		{name: "doação de outra comunidade", status: "paid", donationID: "outra", wantErr: ErrDonationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			donation := &domain.Donation{ID: "d1", CommunityID: "c1", Amount: 100, RefundedAmount: tt.refunded, Status: tt.status}
+			revenue := &domain.Revenue{ID: "r1", Amount: donation.NetAmount(), Status: "received"}
+			repos := &repository.Repositories{
+				Donation: &fakeDonationRepository{donations: []*domain.Donation{donation}},
+				Revenue:  &fakeRevenueRepository{revenue: revenue},
+			}
+			s := &PaymentService{repos: repos, logger: zap.NewNop(), posting: NewDonationPostingService(repos, zap.NewNop())}
+
+			donationID := "d1"
+			if tt.donationID != "" {
+				donationID = tt.donationID
+			}
+			got, err := s.refundDonation(context.Background(), "c1", donationID, tt.amount, "pedido do doador")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("erro = %v; esperado %v", err, tt.wantErr)
+				}
+				if donation.RefundedAmount != tt.refunded || donation.Status != tt.status {
+					t.Errorf("doação alterada apesar do erro: %v %s", donation.RefundedAmount, donation.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+
+			if got.Status != tt.wantStatus || got.RefundedAmount != tt.wantRefunded {
+				t.Errorf("doação = %s com %v reembolsado; esperado %s com %v", got.Status, got.RefundedAmount, tt.wantStatus, tt.wantRefunded)
+			}
+			if got.RefundReason != "pedido do doador" || got.RefundedAt == nil {
+				t.Errorf("motivo %q em %v; esperado o motivo e a data do reembolso", got.RefundReason, got.RefundedAt)
+			}
+			if tt.wantCancelled {
+				if revenue.Status != "cancelled" {
+					t.Errorf("receita %s; esperado estornada", revenue.Status)
+				}
+			} else if revenue.Status != "received" || revenue.Amount != tt.wantRevenue {
+				t.Errorf("receita %s de %v; esperado recebida de %v", revenue.Status, revenue.Amount, tt.wantRevenue)
+			}
+		})
+	}
+}
+
+func TestRefundDonationRequiresReason(t *testing.T) {
+	s := &PaymentService{}
+	if _, err := s.RefundDonation(context.Background(), "c1", "d1", &DonationRefund{Reason: "  "}); err != ErrRefundReasonRequired {
+		t.Errorf("erro = %v; esperado %v", err, ErrRefundReasonRequired)
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidWebhookToken = errors.New("token do webhook inválido")
+)
+
+// HandleWebhook processa o webhook de cobranças do provedor informado.
+// O processamento é idempotente: eventos repetidos ou fora de ordem não
+// fazem o status da doação retroceder nem contabilizam o pagamento duas vezes.
+func (s *PaymentService) HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) error {
+	gateway, err := s.gateway(provider)
+	if err != nil {
+		return err
+	}
+
+	// O provedor autentica o webhook e identifica a comunidade
+	event, err := gateway.ParseWebhook(ctx, header, body)
+	if err != nil {
+		return err
+	}
+	if event.Type == "" {
+		s.logger.Info("evento de cobrança ignorado",
+			zap.String("gateway", gateway.Name()),
+			zap.String("event", event.Event))
+		return nil
+	}
+
+	// A doação fica bloqueada até o fim da transação: entregas simultâneas do mesmo
+	// evento esperam a anterior e encontram a doação já atualizada. Se qualquer etapa
+	// falhar, nada é gravado e a nova tentativa do provedor refaz todo o processamento.
+	return s.repos.Transaction(ctx, func(repos *repository.Repositories) error {
+		return s.withRepos(repos).applyWebhookEvent(ctx, gateway.Name(), event)
+	})
+}
+
+// withRepos retorna uma cópia do serviço que usa os repositórios informados (ex.: os
+// repositórios de uma transação)
+func (s *PaymentService) withRepos(repos *repository.Repositories) *PaymentService {
+	return &PaymentService{
+		repos:    repos,
+		logger:   s.logger,
+		posting:  NewDonationPostingService(repos, s.logger),
+		gateways: s.gateways,
+	}
+}
+
+// applyWebhookEvent aplica o evento à doação da cobrança, ao lançamento no financeiro,
+// às estatísticas do membro e à doação recorrente
+func (s *PaymentService) applyWebhookEvent(ctx context.Context, gateway string, event *PaymentEvent) error {
+	donation, err := s.findOrCreateWebhookDonation(ctx, gateway, event)
+	if err != nil {
+		return err
+	}
+	if donation == nil {
+		s.logger.Warn("cobrança do webhook não encontrada",
+			zap.String("gateway", gateway),
+			zap.String("community_id", event.CommunityID),
+			zap.String("payment_id", event.ChargeID),
+		)
+		return nil
+	}
+
+	previous := *donation
+	now := time.Now()
+
+	if !applyPaymentEvent(donation, event, now) {
+		return nil
+	}
+
+	donation.UpdatedAt = now
+	if err := s.repos.Donation.Update(ctx, donation); err != nil {
+		return fmt.Errorf("erro ao atualizar doação: %v", err)
+	}
+
+	// Lança ou estorna a receita correspondente no financeiro
+	if err := s.posting.SyncDonation(ctx, donation); err != nil {
+		return err
+	}
+
+	// Atualiza as estatísticas de contribuição do membro
+	if err := s.syncMemberContribution(ctx, &previous, donation); err != nil {
+		return err
+	}
+
+	if donation.Status == "paid" && previous.Status != "disputed" {
+		if err := s.advanceRecurringDonation(ctx, donation); err != nil {
+			return err
+		}
+	}
+
+	if donation.Status == "failed" {
+		if err := s.failRecurringDonation(ctx, donation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPaymentEvent aplica a transição de status do evento à doação. Retorna false
+// quando o evento não altera a doação: repetido, fora de ordem ou sem efeito no
+// status atual.
+func applyPaymentEvent(donation *domain.Donation, event *PaymentEvent, now time.Time) bool {
+	switch event.Type {
+	case PaymentEventPaid:
+		if donation.Status != "pending" && donation.Status != "failed" {
+			return false
+		}
+		paidAt := event.PaidAt
+		if paidAt.IsZero() {
+			paidAt = now
+		}
+		donation.Status = "paid"
+		donation.PaidAt = &paidAt
+	case PaymentEventOverdue:
+		if donation.Status != "pending" {
+			return false
+		}
+		donation.Status = "failed"
+	case PaymentEventRefunded:
+		switch donation.Status {
+		case "paid", "disputed":
+			// O reembolso total pode ter sido feito no painel do provedor
+			donation.Status = "refunded"
+			donation.RefundedAmount = donation.Amount
+			donation.RefundedAt = &now
+		case "pending", "failed":
+			donation.Status = "cancelled"
+		default:
+			return false
+		}
+	case PaymentEventDeleted:
+		if donation.Status != "pending" && donation.Status != "failed" {
+			return false
+		}
+		donation.Status = "cancelled"
+	case PaymentEventChargeback:
+		if donation.Status != "paid" {
+			return false
+		}
+		donation.Status = "disputed"
+		donation.DisputedAt = &now
+	case PaymentEventChargebackReversed:
+		// A contestação foi vencida pela comunidade e o valor volta a ser da doação
+		if donation.Status != "disputed" {
+			return false
+		}
+		donation.Status = "paid"
+	default:
+		return false
+	}
+
+	return true
+}
+
+// findOrCreateWebhookDonation busca a doação da cobrança. Cobranças geradas por
+// assinaturas ainda não possuem doação e são criadas vinculadas à doação recorrente.
+func (s *PaymentService) findOrCreateWebhookDonation(ctx context.Context, gateway string, event *PaymentEvent) (*domain.Donation, error) {
+	communityID := event.CommunityID
+	donation, err := s.repos.Donation.LockByAsaasID(ctx, communityID, event.ChargeID)
+	if err == nil {
+		return donation, nil
+	}
+	if err != repository.ErrNotFound {
+		return nil, fmt.Errorf("erro ao buscar doação: %v", err)
+	}
+
+	if event.SubscriptionID == "" {
+		return nil, nil
+	}
+
+	recurring, err := s.repos.RecurringDonation.FindByAsaasID(ctx, communityID, event.SubscriptionID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar doação recorrente: %v", err)
+	}
+
+	// Uma cobrança excluída que nunca foi registrada não precisa gerar doação
+	if event.Type == PaymentEventDeleted {
+		return nil, nil
+	}
+
+	dueDate := event.DueDate
+	if dueDate.IsZero() {
+		dueDate = time.Now()
+	}
+
+	amount := event.Amount
+	if amount == 0 {
+		amount = recurring.Amount
+	}
+
+	paymentMethod := event.Method
+	if paymentMethod == "" {
+		paymentMethod = recurring.PaymentMethod
+	}
+
+	recurringID := recurring.ID
+	donation = &domain.Donation{
+		CommunityID:         communityID,
+		UserID:              recurring.UserID,
+		MemberID:            recurring.MemberID,
+		CampaignID:          recurring.CampaignID,
+		RecurringDonationID: &recurringID,
+		Amount:              amount,
+		PaymentMethod:       paymentMethod,
+		DueDate:             dueDate,
+		Description:         recurring.Description,
+		Status:              "pending",
+		AsaasID:             event.ChargeID,
+		AsaasPaymentID:      event.ChargeID,
+		Gateway:             gateway,
+		PaymentLink:         event.PaymentLink,
+		CustomerName:        recurring.CustomerName,
+		CustomerCPF:         recurring.CustomerCPF,
+		CustomerEmail:       recurring.CustomerEmail,
+		CustomerPhone:       recurring.CustomerPhone,
+	}
+	donation.BillingAddress.Street = recurring.BillingAddress.Street
+	donation.BillingAddress.Number = recurring.BillingAddress.Number
+	donation.BillingAddress.Complement = recurring.BillingAddress.Complement
+	donation.BillingAddress.District = recurring.BillingAddress.District
+	donation.BillingAddress.City = recurring.BillingAddress.City
+	donation.BillingAddress.State = recurring.BillingAddress.State
+	donation.BillingAddress.ZipCode = recurring.BillingAddress.ZipCode
+
+	created, err := s.repos.Donation.CreateCharge(ctx, donation)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar doação da assinatura: %v", err)
+	}
+	if created {
+		return donation, nil
+	}
+
+	// Outra entrega do webhook gravou a doação da cobrança primeiro
+	donation, err = s.repos.Donation.LockByAsaasID(ctx, communityID, event.ChargeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar doação: %v", err)
+	}
+	return donation, nil
+}
+
+// contributedAmount é o valor que a doação soma nas estatísticas do membro: o valor
+// líquido enquanto está paga e zero nas demais situações
+func contributedAmount(donation *domain.Donation) (float64, int) {
+	if donation.Status != "paid" {
+		return 0, 0
+	}
+	return donation.NetAmount(), 1
+}
+
+// syncMemberContribution aplica às estatísticas do membro a diferença entre a situação
+// anterior e a atual da doação
+func (s *PaymentService) syncMemberContribution(ctx context.Context, previous, donation *domain.Donation) error {
+	beforeAmount, beforeCount := contributedAmount(previous)
+	afterAmount, afterCount := contributedAmount(donation)
+	if beforeAmount == afterAmount && beforeCount == afterCount {
+		return nil
+	}
+	return s.applyMemberContribution(ctx, donation, afterAmount-beforeAmount, afterCount-beforeCount)
+}
+
+// applyMemberContribution soma (ou estorna, com valores negativos) a doação nas estatísticas do membro
+func (s *PaymentService) applyMemberContribution(ctx context.Context, donation *domain.Donation, amount float64, count int) error {
+	if donation.MemberID == nil || *donation.MemberID == "" {
+		return nil
+	}
+
+	member, err := s.repos.Member.FindByID(ctx, donation.CommunityID, *donation.MemberID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar membro: %v", err)
+	}
+	if member == nil {
+		return nil
+	}
+
+	member.ContributionCount += count
+	member.TotalContributions += amount
+	if member.ContributionCount < 0 {
+		member.ContributionCount = 0
+	}
+	if member.TotalContributions < 0 {
+		member.TotalContributions = 0
+	}
+	if count > 0 && donation.PaidAt != nil {
+		if member.LastContributionAt == nil || donation.PaidAt.After(*member.LastContributionAt) {
+			paidAt := *donation.PaidAt
+			member.LastContributionAt = &paidAt
+		}
+	}
+
+	if err := s.repos.Member.Update(ctx, member); err != nil {
+		return fmt.Errorf("erro ao atualizar contribuições do membro: %v", err)
+	}
+
+	return nil
+}
+
+// advanceRecurringDonation atualiza o próximo vencimento da assinatura após uma cobrança paga
+func (s *PaymentService) advanceRecurringDonation(ctx context.Context, donation *domain.Donation) error {
+	if donation.RecurringDonationID == nil {
+		return nil
+	}
+
+	recurring, err := s.repos.RecurringDonation.FindByID(ctx, donation.CommunityID, *donation.RecurringDonationID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("erro ao buscar doação recorrente: %v", err)
+	}
+
+	// Assinaturas pausadas ou canceladas não têm próximo vencimento
+	if recurring.Status != "active" && recurring.Status != "failed" {
+		return nil
+	}
+
+	nextDueDate := NextRecurringDueDate(donation.DueDate, recurring.DueDay)
+	if recurring.Status == "active" && recurring.NextDueDate != nil && !nextDueDate.After(*recurring.NextDueDate) {
+		return nil
+	}
+
+	recurring.Status = "active"
+	recurring.NextDueDate = &nextDueDate
+	recurring.UpdatedAt = time.Now()
+	if err := s.repos.RecurringDonation.Update(ctx, recurring); err != nil {
+		return fmt.Errorf("erro ao atualizar doação recorrente: %v", err)
+	}
+
+	return nil
+}
+
+// failRecurringDonation marca a assinatura como falha quando uma cobrança vence sem
+// pagamento, indicando que o cartão precisa ser atualizado
+func (s *PaymentService) failRecurringDonation(ctx context.Context, donation *domain.Donation) error {
+	if donation.RecurringDonationID == nil {
+		return nil
+	}
+
+	recurring, err := s.repos.RecurringDonation.FindByID(ctx, donation.CommunityID, *donation.RecurringDonationID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("erro ao buscar doação recorrente: %v", err)
+	}
+	if recurring.Status != "active" {
+		return nil
+	}
+
+	recurring.Status = "failed"
+	recurring.UpdatedAt = time.Now()
+	if err := s.repos.RecurringDonation.Update(ctx, recurring); err != nil {
+		return fmt.Errorf("erro ao atualizar doação recorrente: %v", err)
+	}
+
+	return nil
+}
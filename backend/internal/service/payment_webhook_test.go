@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestApplyPaymentEvent(t *testing.T) {
+	now := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2026, 3, 19, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     string
+		event      PaymentEvent
+		wantStatus string
+		changed    bool
+		wantPaidAt *time.Time
+	}{
+		{"pagamento de pendente", "pending", PaymentEvent{Type: PaymentEventPaid, PaidAt: paidAt}, "paid", true, &paidAt},
+		{"pagamento sem data usa o horário atual", "pending", PaymentEvent{Type: PaymentEventPaid}, "paid", true, &now},
+		{"pagamento após vencimento", "failed", PaymentEvent{Type: PaymentEventPaid, PaidAt: paidAt}, "paid", true, &paidAt},
+		{"pagamento repetido", "paid", PaymentEvent{Type: PaymentEventPaid, PaidAt: paidAt}, "paid", false, nil},
+		{"pagamento após reembolso", "refunded", PaymentEvent{Type: PaymentEventPaid}, "refunded", false, nil},
+		{"vencimento de pendente", "pending", PaymentEvent{Type: PaymentEventOverdue}, "failed", true, nil},
+		{"vencimento fora de ordem", "paid", PaymentEvent{Type: PaymentEventOverdue}, "paid", false, nil},
+		{"reembolso de paga", "paid", PaymentEvent{Type: PaymentEventRefunded}, "refunded", true, nil},
+		{"reembolso de contestada", "disputed", PaymentEvent{Type: PaymentEventRefunded}, "refunded", true, nil},
+		{"reembolso de pendente cancela", "pending", PaymentEvent{Type: PaymentEventRefunded}, "cancelled", true, nil},
+		{"reembolso repetido", "refunded", PaymentEvent{Type: PaymentEventRefunded}, "refunded", false, nil},
+		{"exclusão de pendente", "pending", PaymentEvent{Type: PaymentEventDeleted}, "cancelled", true, nil},
+		{"exclusão de vencida", "failed", PaymentEvent{Type: PaymentEventDeleted}, "cancelled", true, nil},
+		{"exclusão de paga", "paid", PaymentEvent{Type: PaymentEventDeleted}, "paid", false, nil},
+		{"contestação de paga", "paid", PaymentEvent{Type: PaymentEventChargeback}, "disputed", true, nil},
+		{"contestação de pendente", "pending", PaymentEvent{Type: PaymentEventChargeback}, "pending", false, nil},
+		{"contestação revertida", "disputed", PaymentEvent{Type: PaymentEventChargebackReversed}, "paid", true, nil},
+		{"reversão sem contestação", "paid", PaymentEvent{Type: PaymentEventChargebackReversed}, "paid", false, nil},
+		{"evento desconhecido", "pending", PaymentEvent{Type: "outro"}, "pending", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			donation := &domain.Donation{Amount: 100, Status: tt.status}
+			changed := applyPaymentEvent(donation, &tt.event, now)
+			if changed != tt.changed {
+				t.Errorf("alterada = %v; esperado %v", changed, tt.changed)
+			}
+			if donation.Status != tt.wantStatus {
+				t.Errorf("status = %q; esperado %q", donation.Status, tt.wantStatus)
+			}
+			if tt.wantPaidAt != nil && (donation.PaidAt == nil || !donation.PaidAt.Equal(*tt.wantPaidAt)) {
+				t.Errorf("pago em %v; esperado %v", donation.PaidAt, *tt.wantPaidAt)
+			}
+
+			switch {
+			case tt.wantStatus == "refunded" && tt.changed:
+				if donation.RefundedAmount != donation.Amount || donation.RefundedAt == nil {
+					t.Errorf("reembolso = %v em %v; esperado o valor total", donation.RefundedAmount, donation.RefundedAt)
+				}
+			case tt.wantStatus == "disputed" && tt.changed:
+				if donation.DisputedAt == nil || !donation.DisputedAt.Equal(now) {
+					t.Errorf("contestada em %v; esperado %v", donation.DisputedAt, now)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Eventos aceitos no webhook do provedor Pix
+const (
+	PixEventReceived = "pix.received"
+	PixEventRefunded = "pix.refunded"
+)
+
+// PixGateway recebe doações por Pix diretamente na chave da comunidade, sem
+// intermediário. O código copia e cola é gerado localmente no padrão BR Code do Banco
+// Central, com o valor e o identificador da doação; a confirmação do pagamento chega
+// pelo webhook do banco ou do PSP que recebe na chave, autenticado pelo token da
+// configuração do provedor.
+type PixGateway struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewPixGateway(repos *repository.Repositories, logger *zap.Logger) *PixGateway {
+	return &PixGateway{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// PixWebhookEvent é o corpo do webhook do provedor Pix
+type PixWebhookEvent struct {
+	Event  string  `json:"event"`
+	TxID   string  `json:"txid"`
+	Amount float64 `json:"amount"`
+	PaidAt string  `json:"paid_at"`
+}
+
+// Name identifica o provedor Pix entre os provedores de pagamento
+func (g *PixGateway) Name() string {
+	return GatewayPix
+}
+
+// Methods retorna as formas de pagamento aceitas pelo provedor: apenas Pix
+func (g *PixGateway) Methods() []string {
+	return []string{"pix"}
+}
+
+// Configured indica se a comunidade escolheu o provedor Pix e informou a chave
+func (g *PixGateway) Configured(ctx context.Context, communityID string) (bool, error) {
+	if _, err := g.settings(ctx, communityID); err != nil {
+		if err == ErrGatewayNotConfigured {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateCustomer não faz nada: o Pix estático não possui cadastro de pagador
+func (g *PixGateway) CreateCustomer(ctx context.Context, communityID string, customer *PaymentCustomer) (string, error) {
+	return "", nil
+}
+
+// CreateCharge registra a cobrança com o identificador usado no código copia e cola
+func (g *PixGateway) CreateCharge(ctx context.Context, donation *domain.Donation, customerID string) (*PaymentCharge, error) {
+	if donation.PaymentMethod != "pix" {
+		return nil, ErrPaymentMethodUnsupported
+	}
+	if _, err := g.settings(ctx, donation.CommunityID); err != nil {
+		return nil, err
+	}
+	return &PaymentCharge{ID: pixTxID(donation.ID)}, nil
+}
+
+// UpdateCharge não faz nada: o código copia e cola é gerado novamente com o novo valor
+func (g *PixGateway) UpdateCharge(ctx context.Context, donation *domain.Donation) error {
+	return nil
+}
+
+// CancelCharge não faz nada: cobranças Pix estáticas não são registradas no banco
+func (g *PixGateway) CancelCharge(ctx context.Context, donation *domain.Donation) error {
+	return nil
+}
+
+func (g *PixGateway) SendChargeLink(ctx context.Context, donation *domain.Donation) error {
+	return ErrGatewayUnsupported
+}
+
+// PixQrCode gera o código copia e cola da doação. A imagem do QR Code é montada pela
+// página a partir do código.
+func (g *PixGateway) PixQrCode(ctx context.Context, donation *domain.Donation) (*PixQrCode, error) {
+	settings, err := g.settings(ctx, donation.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+	return &PixQrCode{
+		Payload: PixPayload(settings.PixKey, settings.MerchantName, settings.MerchantCity, donation.AsaasID, donation.Amount),
+	}, nil
+}
+
+// RefundCharge não é suportado: a devolução é feita pelo banco da comunidade
+func (g *PixGateway) RefundCharge(ctx context.Context, donation *domain.Donation, amount float64) error {
+	return ErrGatewayUnsupported
+}
+
+func (g *PixGateway) CreateSubscription(ctx context.Context, recurring *domain.RecurringDonation, customerID string) (string, error) {
+	return "", ErrGatewayUnsupported
+}
+
+func (g *PixGateway) UpdateSubscription(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error {
+	return ErrGatewayUnsupported
+}
+
+func (g *PixGateway) UpdateSubscriptionCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) (*CardInfo, error) {
+	return nil, ErrGatewayUnsupported
+}
+
+func (g *PixGateway) CancelSubscription(ctx context.Context, recurring *domain.RecurringDonation) error {
+	return ErrGatewayUnsupported
+}
+
+// ParseWebhook valida o token do cabeçalho X-Webhook-Token, que identifica a
+// comunidade, e converte a notificação de Pix recebido ou devolvido
+func (g *PixGateway) ParseWebhook(ctx context.Context, header http.Header, body []byte) (*PaymentEvent, error) {
+	var event PixWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.TxID == "" {
+		return nil, ErrInvalidPaymentEvent
+	}
+
+	token := header.Get("X-Webhook-Token")
+	if token == "" {
+		return nil, ErrInvalidWebhookToken
+	}
+	settings, err := g.repos.PaymentGateway.FindByWebhookToken(ctx, GatewayPix, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidWebhookToken
+		}
+		return nil, fmt.Errorf("erro ao buscar provedor de pagamentos: %v", err)
+	}
+
+	result := &PaymentEvent{
+		CommunityID: settings.CommunityID,
+		Event:       event.Event,
+		ChargeID:    event.TxID,
+		Amount:      event.Amount,
+		Method:      "pix",
+	}
+	switch event.Event {
+	case PixEventReceived:
+		result.Type = PaymentEventPaid
+		result.PaidAt = time.Now()
+		if paidAt, err := time.Parse(time.RFC3339, event.PaidAt); err == nil {
+			result.PaidAt = paidAt
+		}
+	case PixEventRefunded:
+		result.Type = PaymentEventRefunded
+	}
+	return result, nil
+}
+
+// settings retorna a configuração Pix da comunidade
+func (g *PixGateway) settings(ctx context.Context, communityID string) (*domain.PaymentGatewaySettings, error) {
+	settings, err := g.repos.PaymentGateway.FindByCommunityID(ctx, communityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrGatewayNotConfigured
+		}
+		return nil, fmt.Errorf("erro ao buscar provedor de pagamentos: %v", err)
+	}
+	if settings.Provider != GatewayPix || settings.PixKey == "" {
+		return nil, ErrGatewayNotConfigured
+	}
+	return settings, nil
+}
+
+// PixPayload monta o código copia e cola (BR Code) de um Pix estático com valor e
+// identificador da transação, conforme o manual do BR Code do Banco Central
+func PixPayload(key, merchantName, merchantCity, txID string, amount float64) string {
+	account := emvField("00", "br.gov.bcb.pix") + emvField("01", key)
+
+	txID = pixTxID(txID)
+	if txID == "" {
+		txID = "***"
+	}
+
+	var payload strings.Builder
+	payload.WriteString(emvField("00", "01"))
+	payload.WriteString(emvField("26", account))
+	payload.WriteString(emvField("52", "0000"))
+	payload.WriteString(emvField("53", "986"))
+	if amount > 0 {
+		payload.WriteString(emvField("54", strconv.FormatFloat(amount, 'f', 2, 64)))
+	}
+	payload.WriteString(emvField("58", "BR"))
+	payload.WriteString(emvField("59", pixText(merchantName, 25)))
+	payload.WriteString(emvField("60", pixText(merchantCity, 15)))
+	payload.WriteString(emvField("62", emvField("05", txID)))
+	payload.WriteString("6304")
+
+	return payload.String() + fmt.Sprintf("%04X", crc16CCITT(payload.String()))
+}
+
+// emvField codifica um campo EMV: identificador, tamanho com dois dígitos e valor
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// pixTxID mantém apenas letras e números e limita o identificador a 25 caracteres
+func pixTxID(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	txID := b.String()
+	if len(txID) > 25 {
+		txID = txID[:25]
+	}
+	return txID
+}
+
+// pixAccents converte as letras acentuadas para ASCII, exigido nos campos do BR Code
+var pixAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// pixText converte o texto para ASCII em maiúsculas e limita o tamanho
+func pixText(value string, max int) string {
+	var b strings.Builder
+	for _, r := range pixAccents.Replace(strings.TrimSpace(value)) {
+		if r >= 0x20 && r < 0x7f {
+			b.WriteRune(r)
+		}
+	}
+	text := strings.ToUpper(b.String())
+	if len(text) > max {
+		text = strings.TrimSpace(text[:max])
+	}
+	return text
+}
+
+// crc16CCITT calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) do BR Code
+func crc16CCITT(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,617 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"github.com/comunidade/backend/pkg/cnab"
+	"github.com/comunidade/backend/pkg/ofx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnsupportedStatement    = errors.New("formato de arquivo não suportado: envie um extrato OFX ou um retorno CNAB 240")
+	ErrEmptyStatement          = errors.New("o arquivo não possui lançamentos")
+	ErrBankTransactionNotFound = errors.New("lançamento bancário não encontrado")
+	ErrStatementImportNotFound = errors.New("importação de extrato não encontrada")
+	ErrTransactionResolved     = errors.New("o lançamento já foi conciliado")
+	ErrTransactionNotResolved  = errors.New("o lançamento ainda está na fila de conciliação")
+	ErrEntryNotFound           = errors.New("despesa ou receita não encontrada")
+	ErrEntryNotPending         = errors.New("a despesa ou receita não está pendente")
+	ErrEntryMismatch           = errors.New("débitos devem ser conciliados com despesas e créditos com receitas de mesmo valor")
+	ErrInvalidExpenseCategory  = errors.New("a categoria deve ser uma categoria de despesa da comunidade")
+	ErrSupplierNotFound        = errors.New("fornecedor não encontrado")
+)
+
+// reconciliationWindow é a tolerância entre a data do lançamento bancário e a data
+// (ou vencimento) da despesa/receita na conciliação automática
+const reconciliationWindow = 5 * 24 * time.Hour
+
+// candidateWindow é a tolerância usada ao sugerir correspondências na fila de conciliação
+const candidateWindow = 30 * 24 * time.Hour
+
+var cnpjPattern = regexp.MustCompile(`\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}`)
+
+// ReconciliationService importa extratos bancários (OFX) e retornos CNAB 240,
+// concilia os lançamentos com as despesas e receitas pendentes e mantém a fila
+// de lançamentos sem correspondência para resolução manual.
+type ReconciliationService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+func NewReconciliationService(repos *repository.Repositories, logger *zap.Logger) *ReconciliationService {
+	return &ReconciliationService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// ReconciliationCandidates são as despesas ou receitas sugeridas para um lançamento
+type ReconciliationCandidates struct {
+	Transaction *domain.BankTransaction `json:"transaction"`
+	Expenses    []*domain.Expense       `json:"expenses,omitempty"`
+	Revenues    []*domain.Revenue       `json:"revenues,omitempty"`
+}
+
+// ReconciliationEntry são os dados da despesa ou receita criada a partir de um lançamento
+type ReconciliationEntry struct {
+	CategoryID  string
+	SupplierID  *string
+	EventID     *string
+	Description string
+	PaymentType string
+}
+
+// Import lê o arquivo, registra os lançamentos ainda não importados e executa a
+// conciliação automática. O formato é detectado pelo conteúdo do arquivo.
+func (s *ReconciliationService) Import(ctx context.Context, communityID, userID, filename string, data []byte) (*domain.BankStatementImport, error) {
+	statement, transactions, err := parseStatement(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, ErrEmptyStatement
+	}
+
+	statement.CommunityID = communityID
+	statement.UserID = userID
+	statement.Filename = filepath.Base(filename)
+	statement.CreatedAt = time.Now()
+	statement.UpdatedAt = time.Now()
+	for _, transaction := range transactions {
+		transaction.CommunityID = communityID
+		transaction.Status = "pending"
+		transaction.CreatedAt = time.Now()
+		transaction.UpdatedAt = time.Now()
+	}
+
+	created, err := s.repos.BankReconciliation.CreateImport(ctx, statement, transactions)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar importação: %v", err)
+	}
+
+	// Uma despesa ou receita só pode ser usada por um lançamento
+	used := make(map[string]bool)
+	for _, transaction := range created {
+		if err := s.autoMatch(ctx, transaction, used); err != nil {
+			s.logger.Error("erro na conciliação automática",
+				zap.Error(err),
+				zap.String("transaction_id", transaction.ID))
+		}
+	}
+
+	updated, err := s.repos.BankReconciliation.FindImportByID(ctx, communityID, statement.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar importação: %v", err)
+	}
+	if updated != nil {
+		updated.DuplicateCount = statement.DuplicateCount
+		return updated, nil
+	}
+	return statement, nil
+}
+
+// GetImport retorna uma importação
+func (s *ReconciliationService) GetImport(ctx context.Context, communityID, importID string) (*domain.BankStatementImport, error) {
+	statement, err := s.repos.BankReconciliation.FindImportByID(ctx, communityID, importID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar importação: %v", err)
+	}
+	if statement == nil {
+		return nil, ErrStatementImportNotFound
+	}
+	return statement, nil
+}
+
+// GetCandidates retorna o lançamento com as despesas (débitos) ou receitas (créditos)
+// pendentes de mesmo valor em uma janela mais ampla que a da conciliação automática
+func (s *ReconciliationService) GetCandidates(ctx context.Context, communityID, transactionID string) (*ReconciliationCandidates, error) {
+	transaction, err := s.findTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReconciliationCandidates{Transaction: transaction}
+	if transaction.Status != "pending" {
+		return result, nil
+	}
+
+	start := transaction.Date.Add(-candidateWindow)
+	end := transaction.Date.Add(candidateWindow)
+	amount := math.Abs(transaction.Amount)
+
+	if transaction.IsDebit() {
+		result.Expenses, err = s.repos.Expense.FindReconciliationCandidates(ctx, communityID, amount, start, end)
+	} else {
+		result.Revenues, err = s.repos.Revenue.FindReconciliationCandidates(ctx, communityID, amount, start, end)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sugestões de conciliação: %v", err)
+	}
+
+	return result, nil
+}
+
+// Match concilia manualmente o lançamento com uma despesa (débito) ou receita (crédito)
+// pendente de mesmo valor
+func (s *ReconciliationService) Match(ctx context.Context, communityID, transactionID, userID, expenseID, revenueID string) (*domain.BankTransaction, error) {
+	transaction, err := s.findPendingTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	resolveTransaction(transaction, userID, false)
+
+	switch {
+	case transaction.IsDebit() && expenseID != "":
+		expense, err := s.repos.Expense.FindByID(ctx, communityID, expenseID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar despesa: %v", err)
+		}
+		if expense == nil {
+			return nil, ErrEntryNotFound
+		}
+		if !sameAmount(expense.Amount, math.Abs(transaction.Amount)) {
+			return nil, ErrEntryMismatch
+		}
+		if expense.Status == "requested" || expense.Status == "rejected" {
+			return nil, ErrExpenseNotApproved
+		}
+		err = s.repos.BankReconciliation.MatchExpense(ctx, transaction, expense)
+		if err != nil {
+			return nil, reconciliationError(err)
+		}
+
+	case !transaction.IsDebit() && revenueID != "":
+		revenue, err := s.repos.Revenue.FindByID(ctx, communityID, revenueID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar receita: %v", err)
+		}
+		if revenue == nil {
+			return nil, ErrEntryNotFound
+		}
+		if !sameAmount(revenue.Amount, transaction.Amount) {
+			return nil, ErrEntryMismatch
+		}
+		err = s.repos.BankReconciliation.MatchRevenue(ctx, transaction, revenue)
+		if err != nil {
+			return nil, reconciliationError(err)
+		}
+
+	default:
+		return nil, ErrEntryMismatch
+	}
+
+	return s.findTransaction(ctx, communityID, transaction.ID)
+}
+
+// CreateEntry cria uma despesa paga (débito) ou uma receita recebida (crédito) a
+// partir do lançamento e o concilia com ela
+func (s *ReconciliationService) CreateEntry(ctx context.Context, communityID, transactionID, userID string, entry ReconciliationEntry) (*domain.BankTransaction, error) {
+	transaction, err := s.findPendingTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.repos.FinancialCategory.FindByID(ctx, communityID, entry.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar categoria: %v", err)
+	}
+
+	description := entry.Description
+	if description == "" {
+		description = strings.TrimSpace(strings.Join([]string{transaction.Name, transaction.Description}, " "))
+	}
+	resolveTransaction(transaction, userID, false)
+
+	if transaction.IsDebit() {
+		if category == nil || category.Type != "expense" {
+			return nil, ErrInvalidExpenseCategory
+		}
+		supplierID, err := s.resolveSupplier(ctx, communityID, transaction, entry.SupplierID)
+		if err != nil {
+			return nil, err
+		}
+
+		expense := &domain.Expense{
+			CommunityID: communityID,
+			UserID:      userID,
+			CategoryID:  category.ID,
+			SupplierID:  supplierID,
+			EventID:     entry.EventID,
+			Amount:      math.Abs(transaction.Amount),
+			Date:        transaction.Date,
+			DueDate:     transaction.Date,
+			Description: description,
+			PaymentType: entry.PaymentType,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := s.repos.BankReconciliation.MatchExpense(ctx, transaction, expense); err != nil {
+			return nil, reconciliationError(err)
+		}
+	} else {
+		if category == nil || category.Type != "revenue" {
+			return nil, ErrInvalidCategory
+		}
+
+		revenue := &domain.Revenue{
+			CommunityID: communityID,
+			UserID:      userID,
+			CategoryID:  category.ID,
+			EventID:     entry.EventID,
+			Amount:      transaction.Amount,
+			Date:        transaction.Date,
+			Description: description,
+			PaymentType: entry.PaymentType,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		if err := s.repos.BankReconciliation.MatchRevenue(ctx, transaction, revenue); err != nil {
+			return nil, reconciliationError(err)
+		}
+	}
+
+	return s.findTransaction(ctx, communityID, transaction.ID)
+}
+
+// Ignore retira o lançamento da fila sem vinculá-lo a uma despesa ou receita
+func (s *ReconciliationService) Ignore(ctx context.Context, communityID, transactionID, userID, notes string) (*domain.BankTransaction, error) {
+	transaction, err := s.findPendingTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	resolveTransaction(transaction, userID, false)
+	transaction.Notes = notes
+
+	if err := s.repos.BankReconciliation.Ignore(ctx, transaction); err != nil {
+		return nil, reconciliationError(err)
+	}
+	return transaction, nil
+}
+
+// Reopen desfaz a conciliação do lançamento, devolvendo-o à fila. A despesa ou
+// receita vinculada volta a ficar pendente.
+func (s *ReconciliationService) Reopen(ctx context.Context, communityID, transactionID string) (*domain.BankTransaction, error) {
+	transaction, err := s.findTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status == "pending" {
+		return nil, ErrTransactionNotResolved
+	}
+
+	if err := s.repos.BankReconciliation.Reopen(ctx, transaction); err != nil {
+		return nil, reconciliationError(err)
+	}
+	return s.findTransaction(ctx, communityID, transaction.ID)
+}
+
+// autoMatch concilia o lançamento quando há uma única correspondência inequívoca:
+// mesmo valor, data dentro da janela e, para despesas, o CNPJ do fornecedor quando
+// informado no extrato
+func (s *ReconciliationService) autoMatch(ctx context.Context, transaction *domain.BankTransaction, used map[string]bool) error {
+	start := transaction.Date.Add(-reconciliationWindow)
+	end := transaction.Date.Add(reconciliationWindow)
+	amount := math.Abs(transaction.Amount)
+
+	if transaction.IsDebit() {
+		expenses, err := s.repos.Expense.FindReconciliationCandidates(ctx, transaction.CommunityID, amount, start, end)
+		if err != nil {
+			return err
+		}
+		expense := pickExpense(transaction, expenses, used)
+		if expense == nil {
+			return nil
+		}
+		resolveTransaction(transaction, "", true)
+		if err := s.repos.BankReconciliation.MatchExpense(ctx, transaction, expense); err != nil {
+			return err
+		}
+		used[expense.ID] = true
+		return nil
+	}
+
+	revenues, err := s.repos.Revenue.FindReconciliationCandidates(ctx, transaction.CommunityID, amount, start, end)
+	if err != nil {
+		return err
+	}
+	revenue := pickRevenue(transaction, revenues, used)
+	if revenue == nil {
+		return nil
+	}
+	resolveTransaction(transaction, "", true)
+	if err := s.repos.BankReconciliation.MatchRevenue(ctx, transaction, revenue); err != nil {
+		return err
+	}
+	used[revenue.ID] = true
+	return nil
+}
+
+// pickExpense escolhe a despesa correspondente ao débito. Com o CNPJ no extrato, só
+// despesas do fornecedor com esse CNPJ (ou sem fornecedor identificado) são aceitas.
+// Havendo mais de uma opção, vence a de data mais próxima, se não houver empate.
+func pickExpense(transaction *domain.BankTransaction, expenses []*domain.Expense, used map[string]bool) *domain.Expense {
+	var bySupplier, others []*domain.Expense
+	for _, expense := range expenses {
+		if used[expense.ID] {
+			continue
+		}
+		cnpj := ""
+		if expense.Supplier != nil {
+			cnpj = onlyDigits(expense.Supplier.CNPJ)
+		}
+		switch {
+		case transaction.Document != "" && cnpj == transaction.Document:
+			bySupplier = append(bySupplier, expense)
+		case transaction.Document == "" || cnpj == "":
+			others = append(others, expense)
+		}
+	}
+
+	if len(bySupplier) > 0 {
+		return closest(transaction.Date, bySupplier, expenseDate)
+	}
+	return closest(transaction.Date, others, expenseDate)
+}
+
+// pickRevenue escolhe a receita correspondente ao crédito
+func pickRevenue(transaction *domain.BankTransaction, revenues []*domain.Revenue, used map[string]bool) *domain.Revenue {
+	var available []*domain.Revenue
+	for _, revenue := range revenues {
+		if !used[revenue.ID] {
+			available = append(available, revenue)
+		}
+	}
+	return closest(transaction.Date, available, func(r *domain.Revenue) time.Time { return r.Date })
+}
+
+// closest retorna o item de data mais próxima, ou nil quando a escolha é ambígua
+func closest[T any](date time.Time, items []T, dateOf func(T) time.Time) T {
+	var best T
+	bestDistance := time.Duration(-1)
+	tie := false
+	for _, item := range items {
+		distance := dateOf(item).Sub(date)
+		if distance < 0 {
+			distance = -distance
+		}
+		switch {
+		case bestDistance < 0 || distance < bestDistance:
+			best, bestDistance, tie = item, distance, false
+		case distance == bestDistance:
+			tie = true
+		}
+	}
+	if tie {
+		var zero T
+		return zero
+	}
+	return best
+}
+
+// expenseDate usa o vencimento da despesa, quando informado
+func expenseDate(expense *domain.Expense) time.Time {
+	if !expense.DueDate.IsZero() {
+		return expense.DueDate
+	}
+	return expense.Date
+}
+
+// resolveSupplier valida o fornecedor informado ou, na falta dele, procura o
+// fornecedor pelo CNPJ do lançamento
+func (s *ReconciliationService) resolveSupplier(ctx context.Context, communityID string, transaction *domain.BankTransaction, supplierID *string) (*string, error) {
+	if supplierID != nil && *supplierID != "" {
+		supplier, err := s.repos.Supplier.FindByID(ctx, communityID, *supplierID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar fornecedor: %v", err)
+		}
+		if supplier == nil {
+			return nil, ErrSupplierNotFound
+		}
+		return &supplier.ID, nil
+	}
+
+	if transaction.Document == "" {
+		return nil, nil
+	}
+	supplier, err := s.repos.Supplier.FindByCNPJ(ctx, communityID, transaction.Document)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar fornecedor: %v", err)
+	}
+	if supplier == nil {
+		return nil, nil
+	}
+	return &supplier.ID, nil
+}
+
+func (s *ReconciliationService) findTransaction(ctx context.Context, communityID, transactionID string) (*domain.BankTransaction, error) {
+	transaction, err := s.repos.BankReconciliation.FindTransactionByID(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar lançamento: %v", err)
+	}
+	if transaction == nil {
+		return nil, ErrBankTransactionNotFound
+	}
+	return transaction, nil
+}
+
+func (s *ReconciliationService) findPendingTransaction(ctx context.Context, communityID, transactionID string) (*domain.BankTransaction, error) {
+	transaction, err := s.findTransaction(ctx, communityID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != "pending" {
+		return nil, ErrTransactionResolved
+	}
+	return transaction, nil
+}
+
+// resolveTransaction registra quem resolveu o lançamento; conciliações automáticas não têm autor
+func resolveTransaction(transaction *domain.BankTransaction, userID string, auto bool) {
+	now := time.Now()
+	transaction.AutoMatched = auto
+	transaction.ResolvedAt = &now
+	transaction.ResolvedBy = nil
+	if userID != "" {
+		transaction.ResolvedBy = &userID
+	}
+	// As associações carregadas não devem ser gravadas junto com o lançamento
+	transaction.Expense = nil
+	transaction.Revenue = nil
+}
+
+// reconciliationError traduz os erros do repositório para os erros do serviço
+func reconciliationError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTransactionResolved):
+		return ErrTransactionResolved
+	case errors.Is(err, repository.ErrTransactionNotResolved):
+		return ErrTransactionNotResolved
+	case errors.Is(err, repository.ErrEntryNotPending):
+		return ErrEntryNotPending
+	}
+	return fmt.Errorf("erro ao conciliar lançamento: %v", err)
+}
+
+// parseStatement identifica o formato do arquivo e converte seus lançamentos
+func parseStatement(filename string, data []byte) (*domain.BankStatementImport, []*domain.BankTransaction, error) {
+	statement := &domain.BankStatementImport{}
+	var transactions []*domain.BankTransaction
+
+	switch {
+	case bytes.Contains(bytes.ToUpper(data), []byte("<OFX>")):
+		parsed, err := ofx.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		statement.Format = "ofx"
+		statement.BankCode = parsed.BankID
+		statement.AccountNumber = parsed.AccountID
+		if !parsed.Start.IsZero() {
+			statement.StartDate = &parsed.Start
+		}
+		if !parsed.End.IsZero() {
+			statement.EndDate = &parsed.End
+		}
+
+		for i, t := range parsed.Transactions {
+			// Sem FITID, o lançamento é identificado pelo conteúdo para evitar duplicidade
+			externalID := t.ID
+			if externalID == "" {
+				externalID = fmt.Sprintf("%s-%.2f-%s-%d", t.Date.Format("20060102"), t.Amount, t.Name, i)
+			}
+			transactions = append(transactions, &domain.BankTransaction{
+				ExternalID:  "ofx:" + statement.AccountNumber + ":" + externalID,
+				Date:        t.Date,
+				Amount:      math.Round(t.Amount*100) / 100,
+				Name:        t.Name,
+				Description: t.Memo,
+				Document:    findCNPJ(t.Name + " " + t.Memo),
+			})
+		}
+
+	case looksLikeCNAB240(data) || isCNABFilename(filename):
+		parsed, err := cnab.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		statement.Format = "cnab240"
+		statement.BankCode = parsed.BankCode
+		statement.AccountNumber = parsed.Account
+
+		for _, t := range parsed.Transactions {
+			if statement.StartDate == nil || t.Date.Before(*statement.StartDate) {
+				date := t.Date
+				statement.StartDate = &date
+			}
+			if statement.EndDate == nil || t.Date.After(*statement.EndDate) {
+				date := t.Date
+				statement.EndDate = &date
+			}
+			transactions = append(transactions, &domain.BankTransaction{
+				ExternalID:  "cnab:" + parsed.BankCode + ":" + parsed.Account + ":" + t.ID,
+				Date:        t.Date,
+				Amount:      t.Amount,
+				Name:        t.Name,
+				Description: t.Description,
+				Document:    t.Document,
+			})
+		}
+
+	default:
+		return nil, nil, ErrUnsupportedStatement
+	}
+
+	return statement, transactions, nil
+}
+
+// looksLikeCNAB240 verifica se a primeira linha é um header de arquivo CNAB 240
+func looksLikeCNAB240(data []byte) bool {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+	line = bytes.TrimRight(line, "\r")
+	return len(line) == 240 && line[7] == '0'
+}
+
+func isCNABFilename(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ret", ".cnab", ".rem":
+		return true
+	}
+	return false
+}
+
+// findCNPJ procura um CNPJ no texto do lançamento (comum em TEDs e Pix)
+func findCNPJ(text string) string {
+	match := cnpjPattern.FindString(text)
+	if match == "" {
+		return ""
+	}
+	digits := onlyDigits(match)
+	if len(digits) != 14 {
+		return ""
+	}
+	return digits
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
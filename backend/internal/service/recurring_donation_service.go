@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRecurringDonationNotFound  = errors.New("doação recorrente não encontrada")
+	ErrRecurringDonationCancelled = errors.New("a doação recorrente está cancelada")
+	ErrRecurringDonationNotActive = errors.New("a doação recorrente não está ativa")
+	ErrRecurringDonationNotPaused = errors.New("a doação recorrente não está pausada")
+	ErrInvalidRecurringAmount     = errors.New("o valor da doação recorrente deve ser maior que zero")
+	ErrInvalidDueDay              = errors.New("o dia de vencimento deve estar entre 1 e 31")
+	ErrRecurringDonationNoChanges = errors.New("informe o novo valor ou o novo dia de vencimento")
+	ErrSubscriptionGateway        = errors.New("não foi possível atualizar a assinatura no provedor de pagamentos. Tente novamente")
+)
+
+// RecurringDonationService gerencia o ciclo de vida das doações recorrentes. Toda
+// alteração é enviada primeiro ao provedor de pagamentos e só então gravada, mantendo
+// a situação e o próximo vencimento iguais nos dois lados.
+type RecurringDonationService struct {
+	repos    *repository.Repositories
+	logger   *zap.Logger
+	payments *PaymentService
+}
+
+func NewRecurringDonationService(repos *repository.Repositories, logger *zap.Logger, payments *PaymentService) *RecurringDonationService {
+	return &RecurringDonationService{
+		repos:    repos,
+		logger:   logger,
+		payments: payments,
+	}
+}
+
+// RecurringDonationChanges são as alterações de valor e vencimento de uma assinatura
+type RecurringDonationChanges struct {
+	Amount *float64
+	DueDay *int
+}
+
+// GetRecurringDonation busca uma doação recorrente da comunidade
+func (s *RecurringDonationService) GetRecurringDonation(ctx context.Context, communityID, id string) (*domain.RecurringDonation, error) {
+	recurring, err := s.repos.RecurringDonation.FindByID(ctx, communityID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRecurringDonationNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar doação recorrente: %v", err)
+	}
+	return recurring, nil
+}
+
+// Pause suspende as próximas cobranças da assinatura
+func (s *RecurringDonationService) Pause(ctx context.Context, recurring *domain.RecurringDonation) error {
+	if recurring.Status != "active" && recurring.Status != "failed" {
+		return ErrRecurringDonationNotActive
+	}
+
+	if err := s.mirror(ctx, recurring, &SubscriptionUpdate{Status: "paused"}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	recurring.Status = "paused"
+	recurring.PausedAt = &now
+	recurring.NextDueDate = nil
+	return s.save(ctx, recurring, now)
+}
+
+// Resume reativa uma assinatura pausada a partir do próximo dia de vencimento
+func (s *RecurringDonationService) Resume(ctx context.Context, community *domain.Community, recurring *domain.RecurringDonation) error {
+	if recurring.Status != "paused" {
+		return ErrRecurringDonationNotPaused
+	}
+
+	nextDueDate := NextRecurringDueDate(time.Now().In(communityLocation(community)), recurring.DueDay)
+	if err := s.mirror(ctx, recurring, &SubscriptionUpdate{
+		Status:      "active",
+		NextDueDate: &nextDueDate,
+	}); err != nil {
+		return err
+	}
+
+	recurring.Status = "active"
+	recurring.PausedAt = nil
+	recurring.NextDueDate = &nextDueDate
+	return s.save(ctx, recurring, time.Now())
+}
+
+// Update altera o valor e/ou o dia de vencimento. As cobranças pendentes da assinatura
+// também são atualizadas no provedor.
+func (s *RecurringDonationService) Update(ctx context.Context, community *domain.Community, recurring *domain.RecurringDonation, changes *RecurringDonationChanges) error {
+	if recurring.Status == "cancelled" {
+		return ErrRecurringDonationCancelled
+	}
+	if changes.Amount == nil && changes.DueDay == nil {
+		return ErrRecurringDonationNoChanges
+	}
+
+	update := &SubscriptionUpdate{UpdatePendingPayments: true}
+	if changes.Amount != nil {
+		amount := math.Round(*changes.Amount*100) / 100
+		if amount <= 0 {
+			return ErrInvalidRecurringAmount
+		}
+		changes.Amount = &amount
+		update.Amount = &amount
+	}
+
+	var nextDueDate *time.Time
+	if changes.DueDay != nil {
+		if *changes.DueDay < 1 || *changes.DueDay > 31 {
+			return ErrInvalidDueDay
+		}
+		// Assinaturas pausadas recebem o novo vencimento ao serem retomadas
+		if *changes.DueDay != recurring.DueDay && recurring.Status != "paused" {
+			next := NextRecurringDueDate(time.Now().In(communityLocation(community)), *changes.DueDay)
+			nextDueDate = &next
+			update.NextDueDate = &next
+		}
+	}
+
+	if err := s.mirror(ctx, recurring, update); err != nil {
+		return err
+	}
+
+	if changes.Amount != nil {
+		recurring.Amount = *changes.Amount
+	}
+	if changes.DueDay != nil {
+		recurring.DueDay = *changes.DueDay
+	}
+	if nextDueDate != nil {
+		recurring.NextDueDate = nextDueDate
+	}
+	return s.save(ctx, recurring, time.Now())
+}
+
+// UpdateCard substitui o cartão de crédito da assinatura. Uma assinatura com cobrança
+// recusada volta a ficar ativa.
+func (s *RecurringDonationService) UpdateCard(ctx context.Context, recurring *domain.RecurringDonation, card *PaymentCard, holder *PaymentCardHolder, remoteIP string) error {
+	if recurring.Status == "cancelled" {
+		return ErrRecurringDonationCancelled
+	}
+
+	info, err := s.payments.UpdateSubscriptionCard(ctx, recurring, card, holder, remoteIP)
+	if err != nil {
+		s.logger.Error("erro ao atualizar cartão da assinatura no provedor de pagamentos",
+			zap.Error(err),
+			zap.String("recurring_donation_id", recurring.ID))
+		return ErrSubscriptionGateway
+	}
+
+	recurring.CardBrand = info.Brand
+	recurring.CardLastDigits = info.LastDigits
+	if recurring.Status == "failed" {
+		recurring.Status = "active"
+	}
+	return s.save(ctx, recurring, time.Now())
+}
+
+// Cancel encerra a assinatura. As cobranças pendentes são removidas pelo provedor e
+// canceladas pelo webhook.
+func (s *RecurringDonationService) Cancel(ctx context.Context, recurring *domain.RecurringDonation) error {
+	if recurring.Status == "cancelled" {
+		return ErrRecurringDonationCancelled
+	}
+
+	if err := s.payments.CancelSubscription(ctx, recurring); err != nil {
+		s.logger.Error("erro ao cancelar assinatura no provedor de pagamentos",
+			zap.Error(err),
+			zap.String("recurring_donation_id", recurring.ID))
+		return ErrSubscriptionGateway
+	}
+
+	now := time.Now()
+	recurring.Status = "cancelled"
+	recurring.CancelledAt = &now
+	recurring.PausedAt = nil
+	recurring.NextDueDate = nil
+	return s.save(ctx, recurring, now)
+}
+
+// mirror envia a alteração da assinatura ao provedor de pagamentos
+func (s *RecurringDonationService) mirror(ctx context.Context, recurring *domain.RecurringDonation, update *SubscriptionUpdate) error {
+	if err := s.payments.UpdateSubscription(ctx, recurring, update); err != nil {
+		s.logger.Error("erro ao atualizar assinatura no provedor de pagamentos",
+			zap.Error(err),
+			zap.String("recurring_donation_id", recurring.ID))
+		return ErrSubscriptionGateway
+	}
+	return nil
+}
+
+func (s *RecurringDonationService) save(ctx context.Context, recurring *domain.RecurringDonation, now time.Time) error {
+	recurring.UpdatedAt = now
+	if err := s.repos.RecurringDonation.Update(ctx, recurring); err != nil {
+		return fmt.Errorf("erro ao atualizar doação recorrente: %v", err)
+	}
+	return nil
+}
+
+// NextRecurringDueDate retorna o próximo vencimento após from no dia informado. Em
+// meses mais curtos, o vencimento passa para o último dia do mês.
+func NextRecurringDueDate(from time.Time, dueDay int) time.Time {
+	year, month, _ := from.Date()
+	next := addMonthsClamped(year, month, dueDay, 0, from.Location())
+	if !next.After(from) {
+		next = addMonthsClamped(year, month, dueDay, 1, from.Location())
+	}
+	return next
+}
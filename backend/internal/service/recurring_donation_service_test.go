@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRecurringDueDate(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*3600)
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		dueDay int
+		want   time.Time
+	}{
+		{"dia ainda não passou", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), 10, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"dia já passou", time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC), 10, time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{"no próprio dia vai para o mês seguinte", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), 10, time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{"dia 31 em abril", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 31, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{"último dia de fevereiro já passou", time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC), 30, time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{"virada do ano", time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC), 5, time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"fuso da data informada", time.Date(2026, 5, 31, 22, 0, 0, 0, saoPaulo), 31, time.Date(2026, 6, 30, 0, 0, 0, 0, saoPaulo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextRecurringDueDate(tt.from, tt.dueDay); !got.Equal(tt.want) {
+				t.Errorf("NextRecurringDueDate(%s, %d) = %s; esperado %s", tt.from, tt.dueDay, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,627 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+	"github.com/comunidade/backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRecurringExpenseNotFound  = errors.New("despesa recorrente não encontrada")
+	ErrInvalidFrequency          = errors.New("a frequência deve ser weekly, monthly ou yearly")
+	ErrInvalidRecurrenceInterval = errors.New("o intervalo da recorrência deve estar entre 1 e 12")
+	ErrInvalidRecurrenceEnd      = errors.New("a data final deve ser igual ou posterior à data inicial")
+	ErrInvalidLeadDays           = errors.New("a antecedência de geração deve estar entre 0 e 366 dias")
+	ErrInvalidReminderDays       = errors.New("a antecedência do lembrete deve estar entre 0 e 30 dias")
+	ErrInvalidRecurringEmails    = errors.New("e-mail inválido na lista de notificação da despesa recorrente")
+	ErrRecurringExpenseGroup     = errors.New("ministério não encontrado")
+	ErrInvalidPayablesDays       = errors.New("o período de contas a pagar deve estar entre 1 e 366 dias")
+)
+
+const (
+	// defaultReminderDays é a antecedência do lembrete das despesas avulsas
+	defaultReminderDays = 3
+	// maxReminderDays limita a antecedência do lembrete de vencimento
+	maxReminderDays = 30
+	// maxOccurrencesPerRun limita as ocorrências geradas de uma vez por modelo
+	maxOccurrencesPerRun = 120
+)
+
+// RecurringExpenseService gera as despesas a partir dos modelos de despesas
+// recorrentes, monta a visão de contas a pagar e envia os lembretes de vencimento
+// ao responsável financeiro da comunidade.
+type RecurringExpenseService struct {
+	repos         *repository.Repositories
+	logger        *zap.Logger
+	communication CommunicationService
+}
+
+func NewRecurringExpenseService(repos *repository.Repositories, logger *zap.Logger, communication CommunicationService) *RecurringExpenseService {
+	return &RecurringExpenseService{
+		repos:         repos,
+		logger:        logger,
+		communication: communication,
+	}
+}
+
+// Payables é a visão de contas a pagar: despesas vencidas e a vencer no período
+type Payables struct {
+	Today         time.Time         `json:"today"`
+	Until         time.Time         `json:"until"`
+	Overdue       []*domain.Expense `json:"overdue"`
+	Upcoming      []*domain.Expense `json:"upcoming"`
+	OverdueTotal  float64           `json:"overdue_total"`
+	UpcomingTotal float64           `json:"upcoming_total"`
+	Calendar      []*PayablesDay    `json:"calendar"`
+}
+
+// PayablesDay totaliza as despesas a vencer em um dia
+type PayablesDay struct {
+	Date  string  `json:"date"`
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
+
+// CreateRecurringExpense valida o modelo, registra e gera as primeiras ocorrências
+func (s *RecurringExpenseService) CreateRecurringExpense(ctx context.Context, community *domain.Community, recurring *domain.RecurringExpense) error {
+	if recurring.Interval == 0 {
+		recurring.Interval = 1
+	}
+
+	// As datas informadas são dias do calendário da comunidade
+	location := communityLocation(community)
+	recurring.StartDate = calendarDate(recurring.StartDate, location)
+	if recurring.EndDate != nil {
+		endDate := calendarDate(*recurring.EndDate, location)
+		recurring.EndDate = &endDate
+	}
+
+	if err := s.validate(ctx, recurring); err != nil {
+		return err
+	}
+
+	recurring.Active = true
+	recurring.CreatedAt = time.Now()
+	recurring.UpdatedAt = time.Now()
+	if err := s.repos.RecurringExpense.Create(ctx, recurring); err != nil {
+		return fmt.Errorf("erro ao criar despesa recorrente: %v", err)
+	}
+
+	if _, err := s.Materialize(ctx, community, recurring, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateRecurringExpense atualiza o modelo. As despesas pendentes ainda não vencidas
+// recebem os novos dados e as posteriores à nova data final são excluídas. A
+// frequência, o intervalo e a data inicial não são alterados.
+func (s *RecurringExpenseService) UpdateRecurringExpense(ctx context.Context, community *domain.Community, recurring *domain.RecurringExpense) error {
+	location := communityLocation(community)
+	if recurring.EndDate != nil {
+		endDate := calendarDate(*recurring.EndDate, location)
+		recurring.EndDate = &endDate
+	}
+
+	if err := s.validate(ctx, recurring); err != nil {
+		return err
+	}
+
+	// Com a data final antecipada, as ocorrências posteriores deixam de contar como
+	// geradas para que voltem a ser geradas se a data final for estendida
+	if recurring.EndDate != nil {
+		end := startOfDay(*recurring.EndDate, location)
+		for recurring.GeneratedCount > 0 && occurrence(recurring, recurring.GeneratedCount-1, location).After(end) {
+			recurring.GeneratedCount--
+		}
+		recurring.LastDueDate = nil
+		if recurring.GeneratedCount > 0 {
+			last := occurrence(recurring, recurring.GeneratedCount-1, location)
+			recurring.LastDueDate = &last
+		}
+	}
+
+	recurring.UpdatedAt = time.Now()
+	recurring.Category = nil
+	recurring.Supplier = nil
+	recurring.Group = nil
+	if err := s.repos.RecurringExpense.Update(ctx, recurring); err != nil {
+		return fmt.Errorf("erro ao atualizar despesa recorrente: %v", err)
+	}
+
+	today := startOfDay(time.Now(), location)
+	if _, err := s.repos.RecurringExpense.UpdatePendingExpenses(ctx, recurring, today); err != nil {
+		return fmt.Errorf("erro ao atualizar despesas geradas: %v", err)
+	}
+	if recurring.EndDate != nil {
+		end := startOfDay(*recurring.EndDate, location).AddDate(0, 0, 1).Add(-time.Microsecond)
+		if _, err := s.repos.RecurringExpense.DeletePendingExpenses(ctx, recurring.ID, end); err != nil {
+			return fmt.Errorf("erro ao excluir despesas geradas: %v", err)
+		}
+	}
+
+	if recurring.Active {
+		if _, err := s.Materialize(ctx, community, recurring, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRecurringExpense retorna o modelo de despesa recorrente
+func (s *RecurringExpenseService) GetRecurringExpense(ctx context.Context, communityID, recurringID string) (*domain.RecurringExpense, error) {
+	recurring, err := s.repos.RecurringExpense.FindByID(ctx, communityID, recurringID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar despesa recorrente: %v", err)
+	}
+	if recurring == nil {
+		return nil, ErrRecurringExpenseNotFound
+	}
+	return recurring, nil
+}
+
+// DeleteRecurringExpense exclui o modelo e as despesas pendentes que ainda não venceram
+func (s *RecurringExpenseService) DeleteRecurringExpense(ctx context.Context, community *domain.Community, recurring *domain.RecurringExpense) error {
+	today := startOfDay(time.Now(), communityLocation(community))
+	if err := s.repos.RecurringExpense.Delete(ctx, community.ID, recurring.ID, today); err != nil {
+		return fmt.Errorf("erro ao excluir despesa recorrente: %v", err)
+	}
+	return nil
+}
+
+// Materialize gera as despesas pendentes do modelo com vencimento até LeadDays dias
+// após a data informada, respeitando a data final. Retorna a quantidade criada.
+// As despesas geradas são autorizadas pelo próprio modelo e não passam pelo fluxo
+// de aprovação.
+func (s *RecurringExpenseService) Materialize(ctx context.Context, community *domain.Community, recurring *domain.RecurringExpense, now time.Time) (int, error) {
+	if !recurring.Active {
+		return 0, nil
+	}
+
+	location := communityLocation(community)
+	horizon := startOfDay(now, location).AddDate(0, 0, recurring.LeadDays)
+
+	var end time.Time
+	if recurring.EndDate != nil {
+		end = startOfDay(*recurring.EndDate, location)
+	}
+
+	var expenses []*domain.Expense
+	count := recurring.GeneratedCount
+	for len(expenses) < maxOccurrencesPerRun {
+		dueDate := occurrence(recurring, count, location)
+		if dueDate.After(horizon) || (!end.IsZero() && dueDate.After(end)) {
+			break
+		}
+
+		expenses = append(expenses, &domain.Expense{
+			CommunityID:        recurring.CommunityID,
+			UserID:             recurring.UserID,
+			CategoryID:         recurring.CategoryID,
+			SupplierID:         recurring.SupplierID,
+			GroupID:            recurring.GroupID,
+			Amount:             recurring.Amount,
+			Date:               dueDate,
+			DueDate:            dueDate,
+			Description:        recurring.Description,
+			Status:             "pending",
+			PaymentType:        recurring.PaymentType,
+			RecurringExpenseID: &recurring.ID,
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+		recurring.LastDueDate = &dueDate
+		count++
+	}
+	if len(expenses) == 0 {
+		return 0, nil
+	}
+
+	recurring.GeneratedCount = count
+	created, err := s.repos.RecurringExpense.Materialize(ctx, recurring, expenses)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar despesas recorrentes: %v", err)
+	}
+
+	s.logger.Info("despesas recorrentes geradas",
+		zap.String("recurring_expense_id", recurring.ID),
+		zap.Int("created", created))
+	return created, nil
+}
+
+// MaterializeAll gera as ocorrências pendentes de todos os modelos ativos
+func (s *RecurringExpenseService) MaterializeAll(ctx context.Context, now time.Time) error {
+	recurring, err := s.repos.RecurringExpense.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar despesas recorrentes: %v", err)
+	}
+
+	for _, item := range recurring {
+		if item.Community == nil {
+			continue
+		}
+		if _, err := s.Materialize(ctx, item.Community, item, now); err != nil {
+			// Uma falha em um modelo não impede a geração dos demais
+			s.logger.Error("erro ao gerar despesa recorrente",
+				zap.Error(err),
+				zap.String("recurring_expense_id", item.ID))
+		}
+	}
+	return nil
+}
+
+// Payables monta as contas a pagar da comunidade: despesas pendentes vencidas e as
+// que vencem nos próximos dias, com o total por dia de vencimento
+func (s *RecurringExpenseService) Payables(ctx context.Context, community *domain.Community, days int) (*Payables, error) {
+	if days < 1 || days > 366 {
+		return nil, ErrInvalidPayablesDays
+	}
+
+	location := communityLocation(community)
+	today := startOfDay(time.Now(), location)
+	until := today.AddDate(0, 0, days+1).Add(-time.Microsecond)
+
+	expenses, err := s.repos.Expense.FindPayables(ctx, community.ID, until)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contas a pagar: %v", err)
+	}
+
+	payables := &Payables{
+		Today:    today,
+		Until:    until,
+		Overdue:  []*domain.Expense{},
+		Upcoming: []*domain.Expense{},
+		Calendar: []*PayablesDay{},
+	}
+	calendar := make(map[string]*PayablesDay)
+	for _, expense := range expenses {
+		if expense.DueDate.Before(today) {
+			payables.Overdue = append(payables.Overdue, expense)
+			payables.OverdueTotal += expense.Amount
+			continue
+		}
+
+		payables.Upcoming = append(payables.Upcoming, expense)
+		payables.UpcomingTotal += expense.Amount
+
+		date := expense.DueDate.In(location).Format("2006-01-02")
+		day, ok := calendar[date]
+		if !ok {
+			day = &PayablesDay{Date: date}
+			calendar[date] = day
+			payables.Calendar = append(payables.Calendar, day)
+		}
+		day.Count++
+		day.Total = math.Round((day.Total+expense.Amount)*100) / 100
+	}
+
+	payables.OverdueTotal = math.Round(payables.OverdueTotal*100) / 100
+	payables.UpcomingTotal = math.Round(payables.UpcomingTotal*100) / 100
+	return payables, nil
+}
+
+// SendReminders envia, por comunidade, um resumo das despesas pendentes que vencem
+// dentro da antecedência de lembrete. Cada despesa é lembrada uma única vez.
+func (s *RecurringExpenseService) SendReminders(ctx context.Context, now time.Time) error {
+	// A janela é ampliada em um dia para cobrir os fusos horários das comunidades
+	expenses, err := s.repos.Expense.FindDueForReminder(ctx, now.AddDate(0, 0, -1), now.AddDate(0, 0, maxReminderDays+1))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar despesas para lembrete: %v", err)
+	}
+
+	byCommunity := make(map[string][]*domain.Expense)
+	var order []string
+	for _, expense := range expenses {
+		if _, ok := byCommunity[expense.CommunityID]; !ok {
+			order = append(order, expense.CommunityID)
+		}
+		byCommunity[expense.CommunityID] = append(byCommunity[expense.CommunityID], expense)
+	}
+
+	for _, communityID := range order {
+		community, err := s.repos.Community.FindByID(ctx, communityID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar comunidade: %v", err)
+		}
+		if community == nil {
+			continue
+		}
+
+		location := communityLocation(community)
+		today := startOfDay(now, location)
+
+		var due []*domain.Expense
+		for _, expense := range byCommunity[communityID] {
+			reminderDays := defaultReminderDays
+			if expense.RecurringExpense != nil {
+				reminderDays = expense.RecurringExpense.ReminderDays
+			}
+			if reminderDays == 0 {
+				continue
+			}
+			dueDate := startOfDay(expense.DueDate, location)
+			if dueDate.Before(today) || dueDate.After(today.AddDate(0, 0, reminderDays)) {
+				continue
+			}
+			due = append(due, expense)
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		// O lembrete é registrado antes do envio e inclui apenas as despesas que nenhuma
+		// outra execução registrou
+		ids := make([]string, 0, len(due))
+		for _, expense := range due {
+			ids = append(ids, expense.ID)
+		}
+		claimed, err := s.repos.Expense.ClaimReminders(ctx, ids, time.Now())
+		if err != nil {
+			return fmt.Errorf("erro ao registrar envio de lembrete: %v", err)
+		}
+		due = claimedExpenses(due, claimed)
+		if len(due) == 0 {
+			continue
+		}
+
+		if err := s.sendReminder(ctx, community, due, today); err != nil {
+			s.logger.Error("erro ao enviar lembrete de vencimento",
+				zap.Error(err),
+				zap.String("community_id", community.ID))
+			if err := s.repos.Expense.ReleaseReminders(ctx, claimed); err != nil {
+				s.logger.Error("erro ao liberar lembretes não enviados", zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// claimedExpenses mantém as despesas cujos IDs foram registrados
+func claimedExpenses(expenses []*domain.Expense, ids []string) []*domain.Expense {
+	claimed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		claimed[id] = true
+	}
+	var kept []*domain.Expense
+	for _, expense := range expenses {
+		if claimed[expense.ID] {
+			kept = append(kept, expense)
+		}
+	}
+	return kept
+}
+
+// Run gera as despesas recorrentes e envia os lembretes de vencimento periodicamente,
+// até o contexto ser cancelado
+func (s *RecurringExpenseService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Com mais de uma instância do servidor, apenas uma executa a rodada
+		if _, err := s.repos.RunExclusive(ctx, "recurring_expenses", func(ctx context.Context) error {
+			now := time.Now()
+			if err := s.MaterializeAll(ctx, now); err != nil {
+				s.logger.Error("erro ao gerar despesas recorrentes", zap.Error(err))
+			}
+			if err := s.SendReminders(ctx, now); err != nil {
+				s.logger.Error("erro ao enviar lembretes de vencimento", zap.Error(err))
+			}
+			return nil
+		}); err != nil {
+			s.logger.Error("erro ao obter lock das despesas recorrentes", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendReminder envia o resumo de vencimentos ao responsável pela comunidade e aos
+// e-mails configurados nos modelos das despesas
+func (s *RecurringExpenseService) sendReminder(ctx context.Context, community *domain.Community, expenses []*domain.Expense, today time.Time) error {
+	var recipients []string
+	owner, err := s.repos.User.FindByID(ctx, community.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar responsável pela comunidade: %v", err)
+	}
+	if owner != nil && owner.Email != "" {
+		recipients = append(recipients, owner.Email)
+	}
+	for _, expense := range expenses {
+		if expense.RecurringExpense != nil {
+			recipients = append(recipients, splitEmails(expense.RecurringExpense.NotifyEmails)...)
+		}
+	}
+	recipients = uniqueEmails(recipients)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	location := communityLocation(community)
+	type reminderItem struct {
+		DueDate     string
+		Description string
+		Supplier    string
+		Category    string
+		Amount      string
+		Today       bool
+	}
+	items := make([]reminderItem, 0, len(expenses))
+	var total float64
+	for _, expense := range expenses {
+		item := reminderItem{
+			DueDate:     expense.DueDate.In(location).Format("02/01/2006"),
+			Description: expense.Description,
+			Amount:      formatCurrency(expense.Amount),
+			Today:       startOfDay(expense.DueDate, location).Equal(today),
+		}
+		if expense.Supplier != nil {
+			item.Supplier = expense.Supplier.Name
+		}
+		if expense.Category != nil {
+			item.Category = expense.Category.Name
+		}
+		items = append(items, item)
+		total += expense.Amount
+	}
+
+	subject := fmt.Sprintf("%d conta(s) a pagar vencendo em breve", len(expenses))
+	if len(expenses) == 1 {
+		subject = fmt.Sprintf("Conta a pagar vence em %s: %s", items[0].DueDate, expenses[0].Description)
+	}
+
+	var body bytes.Buffer
+	if err := payablesReminderTemplate.Execute(&body, map[string]interface{}{
+		"Community": community.Name,
+		"Items":     items,
+		"Total":     formatCurrency(total),
+	}); err != nil {
+		return fmt.Errorf("erro ao gerar lembrete de vencimento: %v", err)
+	}
+
+	recipientID := "payables-" + today.Format("2006-01-02")
+	messages := make([]PersonalizedMessage, 0, len(recipients))
+	for _, email := range recipients {
+		messages = append(messages, PersonalizedMessage{
+			RecipientType: domain.RecipientTypeCustom,
+			RecipientID:   recipientID,
+			Email:         email,
+			Subject:       subject,
+			Body:          body.String(),
+		})
+	}
+
+	communication := &domain.Communication{
+		Type:          domain.CommunicationTypeEmail,
+		Subject:       subject,
+		Content:       body.String(),
+		RecipientType: domain.RecipientTypeCustom,
+		RecipientID:   recipientID,
+		CreatedBy:     community.CreatedBy,
+	}
+	if err := s.communication.SendPersonalizedCommunication(ctx, community.ID, communication, messages); err != nil {
+		return fmt.Errorf("erro ao enviar lembrete de vencimento: %v", err)
+	}
+	return nil
+}
+
+func (s *RecurringExpenseService) validate(ctx context.Context, recurring *domain.RecurringExpense) error {
+	switch recurring.Frequency {
+	case "weekly", "monthly", "yearly":
+	default:
+		return ErrInvalidFrequency
+	}
+	if recurring.Interval < 1 || recurring.Interval > 12 {
+		return ErrInvalidRecurrenceInterval
+	}
+	if recurring.EndDate != nil && recurring.EndDate.Before(recurring.StartDate) {
+		return ErrInvalidRecurrenceEnd
+	}
+	if recurring.LeadDays < 0 || recurring.LeadDays > 366 {
+		return ErrInvalidLeadDays
+	}
+	if recurring.ReminderDays < 0 || recurring.ReminderDays > maxReminderDays {
+		return ErrInvalidReminderDays
+	}
+
+	for _, email := range splitEmails(recurring.NotifyEmails) {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return ErrInvalidRecurringEmails
+		}
+	}
+	recurring.NotifyEmails = strings.Join(splitEmails(recurring.NotifyEmails), ", ")
+
+	category, err := s.repos.FinancialCategory.FindByID(ctx, recurring.CommunityID, recurring.CategoryID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar categoria: %v", err)
+	}
+	if category == nil || category.Type != "expense" {
+		return ErrInvalidExpenseCategory
+	}
+
+	if recurring.SupplierID != nil {
+		supplier, err := s.repos.Supplier.FindByID(ctx, recurring.CommunityID, *recurring.SupplierID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar fornecedor: %v", err)
+		}
+		if supplier == nil {
+			return ErrSupplierNotFound
+		}
+	}
+
+	if recurring.GroupID != nil {
+		group, err := s.repos.Group.FindByID(ctx, recurring.CommunityID, *recurring.GroupID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar ministério: %v", err)
+		}
+		if group == nil {
+			return ErrRecurringExpenseGroup
+		}
+	}
+	return nil
+}
+
+// occurrence calcula o vencimento da n-ésima ocorrência (a partir de 0) do modelo.
+// Nas recorrências mensais e anuais, o dia é ajustado ao último dia dos meses mais
+// curtos, sem deslocar as ocorrências seguintes.
+func occurrence(recurring *domain.RecurringExpense, n int, location *time.Location) time.Time {
+	year, month, day := recurring.StartDate.In(location).Date()
+	switch recurring.Frequency {
+	case "weekly":
+		return time.Date(year, month, day+7*recurring.Interval*n, 0, 0, 0, 0, location)
+	case "yearly":
+		return addMonthsClamped(year, month, day, 12*recurring.Interval*n, location)
+	}
+	return addMonthsClamped(year, month, day, recurring.Interval*n, location)
+}
+
+func addMonthsClamped(year int, month time.Month, day, months int, location *time.Location) time.Time {
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, location)
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, location)
+}
+
+// calendarDate interpreta o dia informado (ano, mês e dia da própria data) como a
+// meia-noite desse dia no fuso da comunidade
+func calendarDate(t time.Time, location *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
+
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	year, month, day := t.In(location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
+
+var payablesReminderTemplate = template.Must(template.New("payables-reminder").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="font-family: Arial, Helvetica, sans-serif; color: #222;">
+<h2>{{.Community}}</h2>
+<p>As seguintes contas a pagar vencem nos próximos dias:</p>
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr style="background: #f0f0f0;"><th align="left">Vencimento</th><th align="left">Descrição</th><th align="left">Fornecedor</th><th align="left">Categoria</th><th align="right">Valor</th></tr>
+{{range .Items}}
+<tr><td>{{.DueDate}}{{if .Today}} (hoje){{end}}</td><td>{{.Description}}</td><td>{{.Supplier}}</td><td>{{.Category}}</td><td style="text-align: right;">{{.Amount}}</td></tr>
+{{end}}
+<tr><td colspan="4"><strong>Total</strong></td><td style="text-align: right;"><strong>{{.Total}}</strong></td></tr>
+</table>
+</body>
+</html>
+`))
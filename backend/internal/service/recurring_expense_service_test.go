@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/comunidade/backend/internal/domain"
+)
+
+func TestAddMonthsClamped(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*3600)
+
+	tests := []struct {
+		name     string
+		year     int
+		month    time.Month
+		day      int
+		months   int
+		location *time.Location
+		want     time.Time
+	}{
+		{"mesmo mês", 2026, time.March, 15, 0, time.UTC, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"próximo mês", 2026, time.March, 15, 1, time.UTC, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)},
+		{"dia 31 em abril", 2026, time.March, 31, 1, time.UTC, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{"dia 31 em fevereiro", 2026, time.January, 31, 1, time.UTC, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"fevereiro de ano bissexto", 2028, time.January, 30, 1, time.UTC, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"virada do ano", 2026, time.November, 31, 3, time.UTC, time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"meses negativos", 2026, time.March, 31, -1, time.UTC, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"fuso da comunidade", 2026, time.May, 31, 1, saoPaulo, time.Date(2026, 6, 30, 0, 0, 0, 0, saoPaulo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := addMonthsClamped(tt.year, tt.month, tt.day, tt.months, tt.location)
+			if !got.Equal(tt.want) || got.Location() != tt.want.Location() {
+				t.Errorf("addMonthsClamped = %s; esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrence(t *testing.T) {
+	start := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		frequency string
+		interval  int
+		n         int
+		want      time.Time
+	}{
+		{"primeira ocorrência", "monthly", 1, 0, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"mensal ajustada ao fim de fevereiro", "monthly", 1, 1, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"mensal volta ao dia 31", "monthly", 1, 2, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"bimestral", "monthly", 2, 2, time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)},
+		{"semanal", "weekly", 1, 1, time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)},
+		{"quinzenal", "weekly", 2, 3, time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"anual", "yearly", 1, 2, time.Date(2028, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurring := &domain.RecurringExpense{StartDate: start, Frequency: tt.frequency, Interval: tt.interval}
+			if got := occurrence(recurring, tt.n, time.UTC); !got.Equal(tt.want) {
+				t.Errorf("occurrence(%d) = %s; esperado %s", tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Package cnab lê arquivos de retorno no padrão FEBRABAN CNAB 240.
+//
+// São reconhecidos os segmentos E (extrato para conciliação bancária), T/U
+// (liquidação de cobrança), A/B (pagamentos a fornecedores) e J (pagamento de
+// boletos). Os demais segmentos são ignorados.
+package cnab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFile = errors.New("arquivo CNAB 240 inválido")
+
+// File é o conteúdo lido de um arquivo de retorno
+type File struct {
+	BankCode     string
+	Agency       string
+	Account      string
+	Transactions []Transaction
+}
+
+// Transaction é um lançamento do retorno. Valores negativos são débitos.
+type Transaction struct {
+	ID          string
+	Segment     string
+	Date        time.Time
+	Amount      float64
+	Name        string
+	Document    string
+	Description string
+}
+
+// Parse lê um arquivo de retorno CNAB 240
+func Parse(r io.Reader) (*File, error) {
+	file := &File{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024), 1024*1024)
+
+	var pending *Transaction
+	flush := func() {
+		if pending != nil {
+			file.Transactions = append(file.Transactions, *pending)
+			pending = nil
+		}
+	}
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) < 240 {
+			return nil, fmt.Errorf("%w: linha %d com %d posições", ErrInvalidFile, lineNumber, len(line))
+		}
+		record := record(line)
+
+		switch record.field(8, 8) {
+		case "0":
+			// Header de arquivo
+			file.BankCode = record.field(1, 3)
+			file.Agency = strings.TrimLeft(record.field(53, 57), "0")
+			file.Account = strings.TrimLeft(record.field(59, 70), "0")
+		case "3":
+			transaction, complement, err := parseDetail(record)
+			if err != nil {
+				return nil, fmt.Errorf("%w: linha %d: %v", ErrInvalidFile, lineNumber, err)
+			}
+			if complement != nil {
+				if pending != nil {
+					complement(pending)
+				}
+				continue
+			}
+			flush()
+			pending = transaction
+		case "5", "9":
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if file.BankCode == "" {
+		return nil, ErrInvalidFile
+	}
+	return file, nil
+}
+
+// parseDetail lê um registro de detalhe. Segmentos complementares (U e B) retornam
+// uma função que completa o lançamento do segmento anterior.
+func parseDetail(r record) (*Transaction, func(*Transaction), error) {
+	segment := r.field(14, 14)
+	sequence := r.field(9, 13)
+
+	switch segment {
+	case "E":
+		date, err := r.date(143, 150)
+		if err != nil {
+			return nil, nil, err
+		}
+		amount := r.amount(151, 168)
+		if r.field(169, 169) == "D" {
+			amount = -amount
+		}
+		document := r.field(202, 240)
+		return &Transaction{
+			ID:          strings.Join([]string{r.field(143, 150), r.field(173, 176), document, sequence}, "-"),
+			Segment:     segment,
+			Date:        date,
+			Amount:      amount,
+			Description: r.field(177, 201),
+			Document:    onlyDigits(document),
+		}, nil, nil
+
+	case "T":
+		// Cobrança: crédito do título liquidado. Valor e data efetivos vêm no segmento U.
+		date, _ := r.date(74, 81)
+		return &Transaction{
+			ID:          "T-" + r.field(38, 57),
+			Segment:     segment,
+			Date:        date,
+			Amount:      r.amount(82, 96),
+			Name:        r.field(149, 188),
+			Document:    onlyDigits(r.field(134, 148)),
+			Description: "Liquidação de título " + r.field(59, 73),
+		}, nil, nil
+
+	case "U":
+		paid := r.amount(78, 92)
+		date, err := r.date(146, 153)
+		if err != nil {
+			date, err = r.date(138, 145)
+		}
+		return nil, func(t *Transaction) {
+			if paid > 0 {
+				t.Amount = paid
+			}
+			if err == nil {
+				t.Date = date
+			}
+		}, nil
+
+	case "A":
+		// Pagamento a fornecedor: débito. O CNPJ do favorecido vem no segmento B.
+		date, err := r.date(155, 162)
+		if err != nil {
+			if date, err = r.date(94, 101); err != nil {
+				return nil, nil, err
+			}
+		}
+		amount := r.amount(163, 177)
+		if amount == 0 {
+			amount = r.amount(120, 134)
+		}
+		return &Transaction{
+			ID:          "A-" + r.field(74, 93) + "-" + sequence,
+			Segment:     segment,
+			Date:        date,
+			Amount:      -amount,
+			Name:        r.field(44, 73),
+			Description: "Pagamento " + r.field(74, 93),
+		}, nil, nil
+
+	case "B":
+		document := onlyDigits(r.field(19, 32))
+		return nil, func(t *Transaction) {
+			if t.Segment == "A" {
+				t.Document = document
+			}
+		}, nil
+
+	case "J":
+		// O segmento J-52 (complementar) não traz valores
+		if r.field(18, 19) == "52" {
+			return nil, func(*Transaction) {}, nil
+		}
+		date, err := r.date(145, 152)
+		if err != nil {
+			return nil, nil, err
+		}
+		amount := r.amount(153, 167)
+		if amount == 0 {
+			amount = r.amount(100, 114)
+		}
+		return &Transaction{
+			ID:          "J-" + r.field(183, 202) + "-" + sequence,
+			Segment:     segment,
+			Date:        date,
+			Amount:      -amount,
+			Name:        r.field(62, 91),
+			Description: "Pagamento de boleto " + r.field(183, 202),
+		}, nil, nil
+	}
+
+	// Segmento não suportado: ignorado sem interromper a leitura
+	return nil, func(*Transaction) {}, nil
+}
+
+// record é uma linha do arquivo; as posições seguem a numeração do layout (a partir de 1)
+type record string
+
+func (r record) field(start, end int) string {
+	return strings.TrimSpace(string(r[start-1 : end]))
+}
+
+func (r record) amount(start, end int) float64 {
+	value, err := strconv.ParseInt(r.field(start, end), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return float64(value) / 100
+}
+
+func (r record) date(start, end int) (time.Time, error) {
+	value := r.field(start, end)
+	if value == "" || strings.Trim(value, "0") == "" {
+		return time.Time{}, errors.New("data não informada")
+	}
+	return time.Parse("02012006", value)
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := strings.TrimLeft(b.String(), "0")
+	// CPF e CNPJ podem começar com zero: mantém o tamanho padrão
+	switch {
+	case len(digits) == 0:
+		return ""
+	case len(digits) <= 11:
+		return fmt.Sprintf("%011s", digits)
+	case len(digits) <= 14:
+		return fmt.Sprintf("%014s", digits)
+	}
+	return digits
+}
@@ -0,0 +1,141 @@
+package cnab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// line monta um registro de 240 posições com os campos informados pela posição
+// inicial do layout (a partir de 1)
+func line(fields map[int]string) string {
+	b := []byte(strings.Repeat(" ", 240))
+	for start, value := range fields {
+		copy(b[start-1:], value)
+	}
+	return string(b)
+}
+
+func TestParse(t *testing.T) {
+	header := line(map[int]string{1: "341", 8: "0", 53: "01234", 59: "000000012345"})
+	trailer := line(map[int]string{1: "341", 8: "9"})
+	date := func(day int, month time.Month) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		details []string
+		want    []Transaction
+	}{
+		{
+			name: "extrato (segmento E)",
+			details: []string{
+				line(map[int]string{8: "3", 9: "00001", 14: "E", 143: "05032026", 151: "000000000000015075", 169: "D", 173: "0101", 177: "TARIFA", 202: "123"}),
+				line(map[int]string{8: "3", 9: "00002", 14: "E", 143: "06032026", 151: "000000000000100000", 169: "C", 173: "0202", 177: "DEPOSITO"}),
+			},
+			want: []Transaction{
+				{ID: "05032026-0101-123-00001", Segment: "E", Date: date(5, 3), Amount: -150.75, Description: "TARIFA", Document: "00000000123"},
+				{ID: "06032026-0202--00002", Segment: "E", Date: date(6, 3), Amount: 1000, Description: "DEPOSITO"},
+			},
+		},
+		{
+			name: "cobrança com valor e data efetivos no segmento U",
+			details: []string{
+				line(map[int]string{8: "3", 9: "00001", 14: "T", 38: "NOSSO123", 59: "DOC9", 74: "10032026", 82: "000000000010000", 134: "012345678901", 149: "JOAO DA SILVA"}),
+				line(map[int]string{8: "3", 9: "00002", 14: "U", 78: "000000000009850", 138: "11032026", 146: "12032026"}),
+			},
+			want: []Transaction{
+				{ID: "T-NOSSO123", Segment: "T", Date: date(12, 3), Amount: 98.5, Name: "JOAO DA SILVA", Document: "12345678901", Description: "Liquidação de título DOC9"},
+			},
+		},
+		{
+			name: "pagamento a fornecedor com CNPJ no segmento B",
+			details: []string{
+				line(map[int]string{8: "3", 9: "00001", 14: "A", 44: "FORNECEDOR LTDA", 74: "PAG1", 94: "14032026", 120: "000000000050000", 155: "15032026", 163: "000000000049900"}),
+				line(map[int]string{8: "3", 9: "00002", 14: "B", 19: "12345678000190"}),
+			},
+			want: []Transaction{
+				{ID: "A-PAG1-00001", Segment: "A", Date: date(15, 3), Amount: -499, Name: "FORNECEDOR LTDA", Document: "12345678000190", Description: "Pagamento PAG1"},
+			},
+		},
+		{
+			name: "boleto com segmento J-52 e segmento desconhecido ignorados",
+			details: []string{
+				line(map[int]string{8: "3", 9: "00001", 14: "J", 62: "CONCESSIONARIA", 100: "000000000020000", 145: "20032026", 153: "000000000000000", 183: "BOLETO1"}),
+				line(map[int]string{8: "3", 9: "00002", 14: "J", 18: "52"}),
+				line(map[int]string{8: "3", 9: "00003", 14: "Z"}),
+			},
+			want: []Transaction{
+				{ID: "J-BOLETO1-00001", Segment: "J", Date: date(20, 3), Amount: -200, Name: "CONCESSIONARIA", Description: "Pagamento de boleto BOLETO1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.Join(append(append([]string{header}, tt.details...), trailer), "\r\n")
+			file, err := Parse(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if file.BankCode != "341" || file.Agency != "1234" || file.Account != "12345" {
+				t.Errorf("conta = %q %q %q; esperado 341 1234 12345", file.BankCode, file.Agency, file.Account)
+			}
+			if len(file.Transactions) != len(tt.want) {
+				t.Fatalf("%d lançamentos; esperado %d: %+v", len(file.Transactions), len(tt.want), file.Transactions)
+			}
+			for i, want := range tt.want {
+				got := file.Transactions[i]
+				if !got.Date.Equal(want.Date) {
+					t.Errorf("lançamento %d: data %s; esperado %s", i, got.Date, want.Date)
+				}
+				got.Date, want.Date = time.Time{}, time.Time{}
+				if got != want {
+					t.Errorf("lançamento %d = %+v; esperado %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"linha curta", "341000000"},
+		{"sem header de arquivo", line(map[int]string{8: "9"})},
+		{"data inválida no extrato", line(map[int]string{8: "0"}) + "\n" + line(map[int]string{8: "3", 14: "E", 143: "00000000"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Parse: erro %v; esperado %v", err, ErrInvalidFile)
+			}
+		})
+	}
+}
+
+func TestOnlyDigits(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"000", ""},
+		{"123.456.789-01", "12345678901"},
+		{"0001234", "00000001234"},
+		{"12.345.678/0001-90", "12345678000190"},
+		{"000012345678000190", "12345678000190"},
+		{"123456789012345", "123456789012345"},
+	}
+
+	for _, tt := range tests {
+		if got := onlyDigits(tt.input); got != tt.want {
+			t.Errorf("onlyDigits(%q) = %q; esperado %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -28,6 +28,7 @@ func Init(cfg *config.Config) error {
 		zap.AddCaller(),
 		zap.AddCallerSkip(1),
 		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.WrapCore(RedactCore),
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted substitui o valor dos campos sensíveis nos logs
+const redacted = "[REDACTED]"
+
+// sensitiveKeys são os trechos de nome de campo que indicam um segredo
+var sensitiveKeys = []string{"password", "senha", "secret", "token", "api_key", "apikey", "authorization"}
+
+// redactingCore mascara os segredos dos campos antes de repassá-los ao core original
+type redactingCore struct {
+	zapcore.Core
+}
+
+// RedactCore envolve o core do zap para mascarar senhas, chaves de API e tokens, seja
+// no nome do próprio campo ou dentro dos objetos registrados com zap.Any. Use com
+// zap.WrapCore.
+func RedactCore(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	result := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		result[i] = redactField(field)
+	}
+	return result
+}
+
+func redactField(field zapcore.Field) zapcore.Field {
+	if isSensitive(field.Key) {
+		return zap.String(field.Key, redacted)
+	}
+	if field.Type != zapcore.ReflectType || field.Interface == nil {
+		return field
+	}
+
+	// Objetos são convertidos pelo JSON para mascarar os campos sensíveis aninhados
+	data, err := json.Marshal(field.Interface)
+	if err != nil {
+		return field
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return field
+	}
+	return zap.Any(field.Key, redactValue(value))
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitive(key) {
+				if s, ok := item.(string); ok && s == "" {
+					continue
+				}
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package ofx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrInvalidFile = errors.New("arquivo OFX inválido")
+
+// Statement é o extrato lido de um arquivo OFX
+type Statement struct {
+	BankID       string
+	AccountID    string
+	Currency     string
+	Start        time.Time
+	End          time.Time
+	Transactions []ParsedTransaction
+}
+
+// ParsedTransaction é um lançamento lido do extrato
+type ParsedTransaction struct {
+	ID       string
+	Type     string
+	Date     time.Time
+	Amount   float64
+	Name     string
+	Memo     string
+	CheckNum string
+}
+
+// Parse lê um extrato OFX nas versões 1.x (SGML) e 2.x (XML)
+func Parse(r io.Reader) (*Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, ErrInvalidFile
+	}
+
+	// Arquivos SGML costumam vir em Windows-1252; arquivos XML em UTF-8
+	body := data[start:]
+	if !utf8.Valid(body) {
+		body = decodeWindows1252(body)
+	}
+
+	statement := &Statement{}
+	var current *ParsedTransaction
+
+	for _, token := range tokenize(string(body)) {
+		if token.closing {
+			if token.name == "STMTTRN" && current != nil {
+				statement.Transactions = append(statement.Transactions, *current)
+				current = nil
+			}
+			continue
+		}
+
+		switch token.name {
+		case "STMTTRN":
+			// Em SGML o fechamento de STMTTRN pode ser omitido antes do próximo lançamento
+			if current != nil {
+				statement.Transactions = append(statement.Transactions, *current)
+			}
+			current = &ParsedTransaction{}
+			continue
+		case "BANKID":
+			statement.BankID = token.value
+		case "ACCTID":
+			statement.AccountID = token.value
+		case "CURDEF":
+			statement.Currency = token.value
+		case "DTSTART":
+			statement.Start, _ = parseDate(token.value)
+		case "DTEND":
+			statement.End, _ = parseDate(token.value)
+		}
+
+		if current == nil {
+			continue
+		}
+		switch token.name {
+		case "TRNTYPE":
+			current.Type = token.value
+		case "DTPOSTED":
+			date, err := parseDate(token.value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: data inválida no lançamento: %s", ErrInvalidFile, token.value)
+			}
+			current.Date = date
+		case "TRNAMT":
+			amount, err := parseAmount(token.value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: valor inválido no lançamento: %s", ErrInvalidFile, token.value)
+			}
+			current.Amount = amount
+		case "FITID":
+			current.ID = token.value
+		case "NAME":
+			current.Name = token.value
+		case "MEMO":
+			current.Memo = token.value
+		case "CHECKNUM":
+			current.CheckNum = token.value
+		}
+	}
+
+	if current != nil {
+		statement.Transactions = append(statement.Transactions, *current)
+	}
+
+	return statement, nil
+}
+
+type token struct {
+	name    string
+	value   string
+	closing bool
+}
+
+// tokenize separa as tags e os valores do arquivo, aceitando tags sem fechamento (SGML)
+func tokenize(body string) []token {
+	var tokens []token
+	for {
+		open := strings.IndexByte(body, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(body[open:], '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.TrimSpace(body[open+1 : open+end])
+		body = body[open+end+1:]
+
+		if tag == "" || strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+
+		if strings.HasPrefix(tag, "/") {
+			tokens = append(tokens, token{name: strings.ToUpper(strings.TrimSpace(tag[1:])), closing: true})
+			continue
+		}
+
+		next := strings.IndexByte(body, '<')
+		value := body
+		if next >= 0 {
+			value = body[:next]
+		}
+		tokens = append(tokens, token{name: strings.ToUpper(tag), value: unescape(strings.TrimSpace(value))})
+	}
+	return tokens
+}
+
+// parseDate lê datas no formato AAAAMMDD[HHMMSS[.XXX]][[-3:BRT]]
+func parseDate(value string) (time.Time, error) {
+	offset := 0
+	if i := strings.IndexByte(value, '['); i >= 0 {
+		zone := strings.TrimSuffix(value[i+1:], "]")
+		if j := strings.IndexByte(zone, ':'); j >= 0 {
+			zone = zone[:j]
+		}
+		if hours, err := strconv.ParseFloat(zone, 64); err == nil {
+			offset = int(hours * 3600)
+		}
+		value = value[:i]
+	}
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	location := time.FixedZone("", offset)
+	switch {
+	case len(value) >= 14:
+		return time.ParseInLocation("20060102150405", value[:14], location)
+	case len(value) >= 8:
+		return time.ParseInLocation("20060102", value[:8], location)
+	}
+	return time.Time{}, ErrInvalidFile
+}
+
+// parseAmount aceita valores com ponto ou vírgula decimal
+func parseAmount(value string) (float64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func unescape(value string) string {
+	replacer := strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", "\"", "&apos;", "'")
+	return replacer.Replace(value)
+}
+
+// decodeWindows1252 converte o conteúdo para UTF-8. Os caracteres acentuados do
+// português coincidem com o Latin-1 nessa codificação.
+func decodeWindows1252(data []byte) []byte {
+	var buf bytes.Buffer
+	for _, b := range data {
+		if b < 0x80 {
+			buf.WriteByte(b)
+			continue
+		}
+		buf.WriteRune(rune(b))
+	}
+	return buf.Bytes()
+}
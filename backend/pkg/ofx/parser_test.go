@@ -0,0 +1,170 @@
+package ofx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM>
+<BANKID>0341
+<ACCTID>12345-6
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20260301
+<DTEND>20260331
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260305120000[-3:BRT]
+<TRNAMT>-150,75
+<FITID>A1
+<NAME>CONTA DE LUZ
+<MEMO>Energia &amp; agua
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260310
+<TRNAMT>1.234,50
+<FITID>A2
+<CHECKNUM>000123
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <BANKMSGSRSV1><STMTTRNRS><STMTRS>
+    <CURDEF>BRL</CURDEF>
+    <BANKACCTFROM><BANKID>001</BANKID><ACCTID>999</ACCTID></BANKACCTFROM>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20260401000000.000</DTPOSTED>
+        <TRNAMT>-10.5</TRNAMT>
+        <FITID>X1</FITID>
+        <NAME>Tarifa</NAME>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParse(t *testing.T) {
+	brt := time.FixedZone("", -3*3600)
+
+	tests := []struct {
+		name     string
+		input    string
+		bankID   string
+		account  string
+		currency string
+		want     []ParsedTransaction
+	}{
+		{
+			name:     "SGML sem fechamento das tags",
+			input:    sgmlStatement,
+			bankID:   "0341",
+			account:  "12345-6",
+			currency: "BRL",
+			want: []ParsedTransaction{
+				{ID: "A1", Type: "DEBIT", Date: time.Date(2026, 3, 5, 12, 0, 0, 0, brt), Amount: -150.75, Name: "CONTA DE LUZ", Memo: "Energia & agua"},
+				{ID: "A2", Type: "CREDIT", Date: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Amount: 1234.50, CheckNum: "000123"},
+			},
+		},
+		{
+			name:     "XML",
+			input:    xmlStatement,
+			bankID:   "001",
+			account:  "999",
+			currency: "BRL",
+			want: []ParsedTransaction{
+				{ID: "X1", Type: "DEBIT", Date: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Amount: -10.5, Name: "Tarifa"},
+			},
+		},
+		{
+			name:  "Windows-1252",
+			input: "<OFX><STMTTRN><TRNAMT>1<NAME>Pagamento \xe0 vista</OFX>",
+			want: []ParsedTransaction{
+				{Amount: 1, Name: "Pagamento à vista"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if statement.BankID != tt.bankID || statement.AccountID != tt.account || statement.Currency != tt.currency {
+				t.Errorf("conta = %q %q %q; esperado %q %q %q",
+					statement.BankID, statement.AccountID, statement.Currency, tt.bankID, tt.account, tt.currency)
+			}
+			if len(statement.Transactions) != len(tt.want) {
+				t.Fatalf("%d lançamentos; esperado %d", len(statement.Transactions), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := statement.Transactions[i]
+				if !got.Date.Equal(want.Date) {
+					t.Errorf("lançamento %d: data %s; esperado %s", i, got.Date, want.Date)
+				}
+				got.Date, want.Date = time.Time{}, time.Time{}
+				if got != want {
+					t.Errorf("lançamento %d = %+v; esperado %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"sem OFX", "DATA:OFXSGML\n<FOO>bar"},
+		{"data inválida", "<OFX><STMTTRN><DTPOSTED>2026"},
+		{"valor inválido", "<OFX><STMTTRN><TRNAMT>abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Parse: erro %v; esperado %v", err, ErrInvalidFile)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"10.50", 10.5},
+		{"-10,50", -10.5},
+		{"1.234,56", 1234.56},
+		{" 2 000,00 ", 2000},
+		{"-0.01", -0.01},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.input)
+		if err != nil {
+			t.Errorf("parseAmount(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAmount(%q) = %v; esperado %v", tt.input, got, tt.want)
+		}
+	}
+}
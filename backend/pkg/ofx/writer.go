@@ -0,0 +1,132 @@
+// Package ofx escreve extratos no formato OFX 1.02 (SGML), aceito pelos bancos
+// brasileiros e pelos sistemas contábeis.
+package ofx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Tipos de transação
+const (
+	TypeCredit = "CREDIT"
+	TypeDebit  = "DEBIT"
+)
+
+// Account identifica a conta do extrato
+type Account struct {
+	BankID    string
+	AccountID string
+	Currency  string
+}
+
+// Transaction é um lançamento do extrato. Valores negativos são débitos.
+type Transaction struct {
+	ID     string
+	Date   time.Time
+	Amount float64
+	Name   string
+	Memo   string
+}
+
+// Writer escreve um extrato transação a transação
+type Writer struct {
+	w       *bufio.Writer
+	balance float64
+	end     time.Time
+}
+
+// NewWriter inicia o extrato da conta no período informado
+func NewWriter(w io.Writer, account Account, start, end time.Time) (*Writer, error) {
+	if account.Currency == "" {
+		account.Currency = "BRL"
+	}
+	if account.BankID == "" {
+		account.BankID = "0000"
+	}
+
+	writer := &Writer{w: bufio.NewWriter(w), end: end}
+	now := formatDateTime(time.Now())
+
+	fmt.Fprint(writer.w, "OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\nCHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+	fmt.Fprint(writer.w, "<OFX>\r\n")
+	fmt.Fprintf(writer.w, "<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>%s<LANGUAGE>POR</SONRS></SIGNONMSGSRSV1>\r\n", now)
+	fmt.Fprint(writer.w, "<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STATUS><CODE>0<SEVERITY>INFO</STATUS>\r\n")
+	fmt.Fprintf(writer.w, "<STMTRS><CURDEF>%s\r\n", account.Currency)
+	fmt.Fprintf(writer.w, "<BANKACCTFROM><BANKID>%s<ACCTID>%s<ACCTTYPE>CHECKING</BANKACCTFROM>\r\n",
+		encode(account.BankID, 9), encode(account.AccountID, 22))
+	fmt.Fprintf(writer.w, "<BANKTRANLIST><DTSTART>%s<DTEND>%s\r\n", formatDateTime(start), formatDateTime(end))
+
+	return writer, writer.w.Flush()
+}
+
+// WriteTransaction escreve um lançamento
+func (w *Writer) WriteTransaction(t Transaction) error {
+	trnType := TypeCredit
+	if t.Amount < 0 {
+		trnType = TypeDebit
+	}
+	w.balance += t.Amount
+
+	fmt.Fprintf(w.w, "<STMTTRN><TRNTYPE>%s<DTPOSTED>%s<TRNAMT>%s<FITID>%s",
+		trnType, formatDateTime(t.Date), formatAmount(t.Amount), encode(t.ID, 255))
+	if t.Name != "" {
+		fmt.Fprintf(w.w, "<NAME>%s", encode(t.Name, 32))
+	}
+	if t.Memo != "" {
+		fmt.Fprintf(w.w, "<MEMO>%s", encode(t.Memo, 255))
+	}
+	fmt.Fprint(w.w, "</STMTTRN>\r\n")
+
+	return w.w.Flush()
+}
+
+// Close finaliza o extrato com o saldo dos lançamentos escritos
+func (w *Writer) Close() error {
+	fmt.Fprint(w.w, "</BANKTRANLIST>\r\n")
+	fmt.Fprintf(w.w, "<LEDGERBAL><BALAMT>%s<DTASOF>%s</LEDGERBAL>\r\n", formatAmount(w.balance), formatDateTime(w.end))
+	fmt.Fprint(w.w, "</STMTRS></STMTTRNRS></BANKMSGSRSV1>\r\n</OFX>\r\n")
+	return w.w.Flush()
+}
+
+func formatDateTime(t time.Time) string {
+	return t.Format("20060102150405")
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", math.Round(amount*100)/100)
+}
+
+// encode converte o texto para Windows-1252, escapa os caracteres especiais do SGML
+// e limita o tamanho do campo
+func encode(text string, limit int) string {
+	var b strings.Builder
+	count := 0
+	for _, r := range text {
+		if count >= limit {
+			break
+		}
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case r == '\r' || r == '\n' || r == '\t':
+			b.WriteByte(' ')
+		case r < 0x20:
+			continue
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+		count++
+	}
+	return b.String()
+}
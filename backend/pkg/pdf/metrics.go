@@ -0,0 +1,86 @@
+package pdf
+
+import "strings"
+
+// helveticaWidths contém a largura dos caracteres ASCII imprimíveis (32 a 126)
+// da fonte Helvetica, em milésimos do tamanho da fonte
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// TextWidth retorna a largura aproximada do texto em pontos. Letras acentuadas
+// usam a largura da letra base; a variação da fonte em negrito é desprezada.
+func TextWidth(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += charWidth(r)
+	}
+	return float64(total) * size / 1000
+}
+
+func charWidth(r rune) int {
+	if r >= 32 && r <= 126 {
+		return helveticaWidths[r-32]
+	}
+	switch {
+	case strings.ContainsRune("ÀÁÂÃÄÅ", r):
+		return helveticaWidths['A'-32]
+	case strings.ContainsRune("àáâãäå", r):
+		return helveticaWidths['a'-32]
+	case strings.ContainsRune("ÈÉÊË", r):
+		return helveticaWidths['E'-32]
+	case strings.ContainsRune("èéêë", r):
+		return helveticaWidths['e'-32]
+	case strings.ContainsRune("ÌÍÎÏ", r):
+		return helveticaWidths['I'-32]
+	case strings.ContainsRune("ìíîï", r):
+		return helveticaWidths['i'-32]
+	case strings.ContainsRune("ÒÓÔÕÖ", r):
+		return helveticaWidths['O'-32]
+	case strings.ContainsRune("òóôõö", r):
+		return helveticaWidths['o'-32]
+	case strings.ContainsRune("ÙÚÛÜ", r):
+		return helveticaWidths['U'-32]
+	case strings.ContainsRune("ùúûü", r):
+		return helveticaWidths['u'-32]
+	case r == 'Ç':
+		return helveticaWidths['C'-32]
+	case r == 'ç':
+		return helveticaWidths['c'-32]
+	case r == 'Ñ':
+		return helveticaWidths['N'-32]
+	case r == 'ñ':
+		return helveticaWidths['n'-32]
+	}
+	return 556
+}
+
+// WrapText quebra o texto em linhas que caibam na largura informada
+func WrapText(text string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			candidate := line + " " + word
+			if TextWidth(candidate, size) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,305 @@
+// Package pdf gera documentos PDF simples (texto, linhas, retângulos e imagens)
+// usando apenas as fontes padrão Helvetica, sem dependências externas.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Tamanhos de página em pontos (1/72 polegada)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// MM converte milímetros em pontos
+func MM(v float64) float64 {
+	return v * 72 / 25.4
+}
+
+// Document representa um documento PDF em construção
+type Document struct {
+	pages  []*Page
+	images []*Image
+}
+
+// Page representa uma página do documento. As coordenadas usam a origem no
+// canto superior esquerdo, em pontos.
+type Page struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+	images  []*Image
+}
+
+// Image representa uma imagem incorporada ao documento
+type Image struct {
+	index  int
+	width  int
+	height int
+	data   []byte
+}
+
+// New cria um documento vazio
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage adiciona uma página A4
+func (d *Document) AddPage() *Page {
+	return d.AddPageSize(A4Width, A4Height)
+}
+
+// AddPageSize adiciona uma página com o tamanho informado em pontos
+func (d *Document) AddPageSize(width, height float64) *Page {
+	page := &Page{width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// AddImage incorpora uma imagem ao documento para ser desenhada nas páginas
+func (d *Document) AddImage(img image.Image) (*Image, error) {
+	bounds := img.Bounds()
+	raw := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			// Compõe a transparência sobre fundo branco
+			r = (r*a + 0xffff*(0xffff-a)) / 0xffff
+			g = (g*a + 0xffff*(0xffff-a)) / 0xffff
+			b = (b*a + 0xffff*(0xffff-a)) / 0xffff
+			raw = append(raw, byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw); err != nil {
+		return nil, fmt.Errorf("erro ao comprimir imagem: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao comprimir imagem: %v", err)
+	}
+
+	image := &Image{
+		index:  len(d.images) + 1,
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   compressed.Bytes(),
+	}
+	d.images = append(d.images, image)
+	return image, nil
+}
+
+// Width retorna a largura da página
+func (p *Page) Width() float64 {
+	return p.width
+}
+
+// Height retorna a altura da página
+func (p *Page) Height() float64 {
+	return p.height
+}
+
+// Text escreve um texto com a linha de base na posição informada
+func (p *Page) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font, size, x, p.height-y, escape(encode(text)))
+}
+
+// TextRight escreve um texto alinhado à direita da posição informada
+func (p *Page) TextRight(x, y, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size), y, size, bold, text)
+}
+
+// TextCenter escreve um texto centralizado na posição informada
+func (p *Page) TextCenter(x, y, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size)/2, y, size, bold, text)
+}
+
+// Paragraph escreve um texto quebrando as linhas na largura informada e
+// retorna a posição vertical após a última linha
+func (p *Page) Paragraph(x, y, width, size float64, bold bool, text string) float64 {
+	lineHeight := size * 1.4
+	for _, line := range WrapText(text, size, width) {
+		p.Text(x, y, size, bold, line)
+		y += lineHeight
+	}
+	return y
+}
+
+// Line desenha uma linha
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, p.height-y1, x2, p.height-y2)
+}
+
+// Rect desenha um retângulo preenchido com o tom de cinza informado (0 = preto, 1 = branco)
+func (p *Page) Rect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n",
+		gray, x, p.height-y-height, width, height)
+}
+
+// StrokeRect desenha o contorno de um retângulo
+func (p *Page) StrokeRect(x, y, width, height, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n",
+		lineWidth, x, p.height-y-height, width, height)
+}
+
+// DrawImage desenha uma imagem do documento com o canto superior esquerdo na posição informada
+func (p *Page) DrawImage(img *Image, x, y, width, height float64) {
+	p.images = append(p.images, img)
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n",
+		width, height, x, p.height-y-height, img.index)
+}
+
+// Bytes gera o conteúdo do documento
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo escreve o documento no writer
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Reserva os números dos objetos: 1 catálogo, 2 páginas, 3 e 4 fontes,
+	// em seguida as imagens e depois cada página com seu conteúdo
+	imageBase := 5
+	pageBase := imageBase + len(d.images)
+	totalObjects := pageBase + len(d.pages)*2 - 1
+
+	newObject := func(n int) {
+		for len(offsets) < n {
+			offsets = append(offsets, 0)
+		}
+		offsets[n-1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	newObject(1)
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageBase+i*2))
+	}
+	newObject(2)
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+
+	newObject(3)
+	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\nendobj\n")
+	newObject(4)
+	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+	for i, img := range d.images {
+		newObject(imageBase + i)
+		fmt.Fprintf(&buf, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n",
+			img.width, img.height, len(img.data))
+		buf.Write(img.data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	for i, page := range d.pages {
+		pageObject := pageBase + i*2
+
+		var xobjects strings.Builder
+		seen := make(map[int]bool)
+		for _, img := range page.images {
+			if seen[img.index] {
+				continue
+			}
+			seen[img.index] = true
+			fmt.Fprintf(&xobjects, " /Im%d %d 0 R", img.index, imageBase+img.index-1)
+		}
+		resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+		if xobjects.Len() > 0 {
+			resources += " /XObject <<" + xobjects.String() + " >>"
+		}
+
+		newObject(pageObject)
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>\nendobj\n",
+			page.width, page.height, resources, pageObject+1)
+
+		newObject(pageObject + 1)
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n", page.content.Len())
+		buf.Write(page.content.Bytes())
+		buf.WriteString("endstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", totalObjects+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", totalObjects+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// encode converte o texto para WinAnsiEncoding. Caracteres sem representação
+// são substituídos por "?".
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			out = append(out, byte(r))
+		case r == '€':
+			out = append(out, 0x80)
+		case r == '–':
+			out = append(out, 0x96)
+		case r == '—':
+			out = append(out, 0x97)
+		case r == '‘':
+			out = append(out, 0x91)
+		case r == '’':
+			out = append(out, 0x92)
+		case r == '“':
+			out = append(out, 0x93)
+		case r == '”':
+			out = append(out, 0x94)
+		case r == '•':
+			out = append(out, 0x95)
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
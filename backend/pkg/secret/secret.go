@@ -0,0 +1,278 @@
+// Package secret cifra segredos (chaves de API, senhas e tokens) para armazenamento
+// com criptografia de envelope: cada valor recebe uma chave de dados aleatória, que
+// cifra o valor e é cifrada pela chave mestra. Trocar a chave mestra exige apenas
+// cifrar novamente os valores com a nova chave, mantendo as anteriores para leitura.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// prefix identifica os valores cifrados. Valores sem o prefixo são texto puro, gravados
+// antes da criptografia ou sem chave mestra configurada.
+const prefix = "enc:v1:"
+
+// maskPrefix inicia os valores mascarados nas respostas da API e nos logs
+const maskPrefix = "****"
+
+var (
+	ErrInvalidMasterKey = errors.New("a chave mestra deve ter 32 bytes codificados em base64")
+	ErrUnknownKey       = errors.New("valor cifrado com uma chave mestra desconhecida")
+	ErrMalformed        = errors.New("valor cifrado inválido")
+)
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Keyring guarda a chave mestra atual, usada para cifrar, e as anteriores, aceitas
+// apenas para decifrar os valores ainda não migrados.
+type Keyring struct {
+	primary *masterKey
+	keys    map[string]*masterKey
+}
+
+// NewKeyring monta o chaveiro a partir da chave mestra atual e das anteriores, todas
+// em base64. Sem chave mestra, os valores são gravados em texto puro.
+func NewKeyring(master string, previous ...string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]*masterKey)}
+	if strings.TrimSpace(master) == "" {
+		return k, nil
+	}
+
+	primary, err := parseMasterKey(master)
+	if err != nil {
+		return nil, err
+	}
+	k.primary = primary
+	k.keys[primary.id] = primary
+
+	for _, encoded := range previous {
+		if strings.TrimSpace(encoded) == "" {
+			continue
+		}
+		key, err := parseMasterKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[key.id] = key
+	}
+	return k, nil
+}
+
+// Enabled indica se há chave mestra para cifrar os valores
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.primary != nil
+}
+
+// KeyID identifica a chave mestra atual
+func (k *Keyring) KeyID() string {
+	if !k.Enabled() {
+		return ""
+	}
+	return k.primary.id
+}
+
+// Encrypt cifra o valor com uma nova chave de dados. Valores vazios continuam vazios.
+// Sem chave mestra, o valor é devolvido sem alteração.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" || !k.Enabled() {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("erro ao gerar chave de dados: %v", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.primary.aead, dataKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := seal(dataAEAD, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.primary.id + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decifra um valor gravado por Encrypt. Valores em texto puro são devolvidos
+// sem alteração.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	if k == nil {
+		return "", ErrUnknownKey
+	}
+	key, ok := k.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	data, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(key.aead, wrapped)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation indica se o valor está em texto puro ou cifrado com uma chave mestra
+// anterior
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" || !k.Enabled() {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	return !strings.HasPrefix(value, prefix+k.primary.id+":")
+}
+
+// IsEncrypted indica se o valor foi gravado cifrado
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Mask oculta o segredo para exibição, mantendo os quatro últimos caracteres dos
+// valores longos o bastante para que isso não o revele
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) < 12 || IsEncrypted(value) {
+		return maskPrefix
+	}
+	return maskPrefix + value[len(value)-4:]
+}
+
+// IsMasked indica se o valor é um segredo mascarado, como devolvido pela API
+func IsMasked(value string) bool {
+	return strings.HasPrefix(value, maskPrefix)
+}
+
+// Keep devolve o segredo atual quando o novo valor está vazio ou é o próprio segredo
+// mascarado, reenviado pelo formulário de edição
+func Keep(current, value string) string {
+	if value == "" || IsMasked(value) {
+		return current
+	}
+	return value
+}
+
+// Digest retorna o SHA-256 em hexadecimal do segredo, usado para encontrar o registro
+// pelo segredo recebido sem gravá-lo em texto puro. Valores vazios continuam vazios.
+func Digest(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateMasterKey gera uma nova chave mestra em base64
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("erro ao gerar chave mestra: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault define o chaveiro usado pelos serializadores do banco de dados
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default retorna o chaveiro definido na inicialização. Sem chaveiro, os valores são
+// gravados em texto puro.
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+func parseMasterKey(encoded string) (*masterKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidMasterKey
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return &masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cifra: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cifra: %v", err)
+	}
+	return aead, nil
+}
+
+// seal cifra com AES-GCM e prefixa o nonce ao resultado
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("erro ao gerar nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
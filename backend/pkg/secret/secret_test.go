@@ -0,0 +1,218 @@
+package secret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestKey(t *testing.T) string {
+	t.Helper()
+	key, err := GenerateMasterKey()
+	if err != nil {
+		t.Fatalf("GenerateMasterKey: %v", err)
+	}
+	return key
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	keyring, err := NewKeyring(newTestKey(t))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"vazio", ""},
+		{"chave de API", "$aact_chave-de-teste-0123456789"},
+		{"senha com acentos", "senhaçãoé:com:dois-pontos"},
+		{"texto longo", strings.Repeat("x", 4096)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := keyring.Encrypt(tt.value)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if tt.value != "" {
+				if !IsEncrypted(encrypted) || strings.Contains(encrypted, tt.value) {
+					t.Fatalf("Encrypt(%q) = %q; esperado valor cifrado", tt.value, encrypted)
+				}
+			} else if encrypted != "" {
+				t.Fatalf("Encrypt(\"\") = %q; esperado vazio", encrypted)
+			}
+
+			decrypted, err := keyring.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if decrypted != tt.value {
+				t.Errorf("Decrypt(Encrypt(%q)) = %q", tt.value, decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptUsesNewDataKey(t *testing.T) {
+	keyring, err := NewKeyring(newTestKey(t))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	first, _ := keyring.Encrypt("segredo")
+	second, _ := keyring.Encrypt("segredo")
+	if first == second {
+		t.Error("o mesmo valor cifrado duas vezes gerou o mesmo resultado")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	old, err := NewKeyring(oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	encrypted, err := old.Encrypt("segredo")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotated, err := NewKeyring(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if !rotated.NeedsRotation(encrypted) {
+		t.Error("valor cifrado com a chave anterior deveria precisar de rotação")
+	}
+	decrypted, err := rotated.Decrypt(encrypted)
+	if err != nil || decrypted != "segredo" {
+		t.Fatalf("Decrypt com chave anterior = %q, %v", decrypted, err)
+	}
+
+	reencrypted, err := rotated.Encrypt(decrypted)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if rotated.NeedsRotation(reencrypted) {
+		t.Error("valor cifrado com a chave atual não deveria precisar de rotação")
+	}
+
+	withoutOld, err := NewKeyring(newKey)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if _, err := withoutOld.Decrypt(encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt sem a chave anterior: erro %v; esperado %v", err, ErrUnknownKey)
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	keyring, err := NewKeyring(newTestKey(t))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	encrypted, err := keyring.Encrypt("segredo")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	parts := strings.Split(encrypted, ":")
+	tampered := strings.Join(append(parts[:len(parts)-1], "A"+parts[len(parts)-1][1:]), ":")
+	if tampered == encrypted {
+		tampered = strings.Join(append(parts[:len(parts)-1], "B"+parts[len(parts)-1][1:]), ":")
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  error
+	}{
+		{"partes faltando", prefix + keyring.KeyID() + ":abc", ErrMalformed},
+		{"base64 inválido", prefix + keyring.KeyID() + ":***:***", ErrMalformed},
+		{"conteúdo alterado", tampered, ErrMalformed},
+		{"chave desconhecida", prefix + "00000000:abc:def", ErrUnknownKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := keyring.Decrypt(tt.value); !errors.Is(err, tt.want) {
+				t.Errorf("Decrypt(%q): erro %v; esperado %v", tt.value, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyringWithoutMasterKey(t *testing.T) {
+	keyring, err := NewKeyring("")
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if keyring.Enabled() {
+		t.Fatal("chaveiro sem chave mestra não deveria estar habilitado")
+	}
+	encrypted, err := keyring.Encrypt("segredo")
+	if err != nil || encrypted != "segredo" {
+		t.Errorf("Encrypt sem chave mestra = %q, %v; esperado texto puro", encrypted, err)
+	}
+	if keyring.NeedsRotation("segredo") {
+		t.Error("sem chave mestra nenhum valor precisa de rotação")
+	}
+}
+
+func TestNewKeyringInvalidKey(t *testing.T) {
+	for _, key := range []string{"curta", "c2VtIDMyIGJ5dGVz"} {
+		if _, err := NewKeyring(key); !errors.Is(err, ErrInvalidMasterKey) {
+			t.Errorf("NewKeyring(%q): erro %v; esperado %v", key, err, ErrInvalidMasterKey)
+		}
+	}
+	if _, err := NewKeyring(newTestKey(t), "invalida"); !errors.Is(err, ErrInvalidMasterKey) {
+		t.Errorf("NewKeyring com chave anterior inválida: erro %v; esperado %v", err, ErrInvalidMasterKey)
+	}
+}
+
+func TestMaskAndKeep(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"curta", "****"},
+		{"abcdefghijkl", "****ijkl"},
+		{prefix + "abcd:efgh:ijkl", "****"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.value); got != tt.want {
+			t.Errorf("Mask(%q) = %q; esperado %q", tt.value, got, tt.want)
+		}
+	}
+
+	keepTests := []struct {
+		current, value, want string
+	}{
+		{"atual", "", "atual"},
+		{"atual", "****ijkl", "atual"},
+		{"atual", "novo", "novo"},
+	}
+	for _, tt := range keepTests {
+		if got := Keep(tt.current, tt.value); got != tt.want {
+			t.Errorf("Keep(%q, %q) = %q; esperado %q", tt.current, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	if got := Digest(""); got != "" {
+		t.Errorf("Digest(\"\") = %q; esperado vazio", got)
+	}
+
+	// Mesmo valor calculado pelo PostgreSQL ao preencher os registros existentes:
+	// encode(sha256(convert_to('token', 'UTF8')), 'hex')
+	want := "3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0"
+	if got := Digest("token"); got != want {
+		t.Errorf("Digest(\"token\") = %q; esperado %q", got, want)
+	}
+	if Digest("token") == Digest("token2") {
+		t.Error("valores diferentes com o mesmo resumo")
+	}
+}
@@ -0,0 +1,157 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Membros & Visitantes")
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.WriteHeader("Nome", "Valor", "Data", "Ativo", "Filhos"); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+
+	birth := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	rows := [][]interface{}{
+		{"João <Silva> & Cia", 1234.5, birth, true, 2},
+		{"Maria", nil, (*time.Time)(nil), false, int64(0)},
+		{"", "", nil, nil, "último"},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := ReadRows(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadRows: %v", err)
+	}
+	want := [][]string{
+		{"Nome", "Valor", "Data", "Ativo", "Filhos"},
+		{"João <Silva> & Cia", "1234.5", "46096", "TRUE", "2"},
+		{"Maria", "", "", "FALSE", "0"},
+		{"", "", "", "", "último"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRows = %q; esperado %q", got, want)
+	}
+}
+
+// writeZip monta uma planilha com os arquivos informados
+func writeZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadRows(t *testing.T) {
+	const ns = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  [][]string
+	}{
+		{
+			name: "textos compartilhados, linha omitida e células puladas",
+			files: map[string]string{
+				"xl/sharedStrings.xml": `<sst ` + ns + `><si><t>Nome</t></si><si><r><t>Ana </t></r><r><t>Souza</t></r></si></sst>`,
+				"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData>` +
+					`<row r="1"><c r="A1" t="s"><v>0</v></c></row>` +
+					`<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>42</v></c><c r="D3" t="s"><v>9</v></c></row>` +
+					`</sheetData></worksheet>`,
+			},
+			want: [][]string{{"Nome"}, nil, {"Ana Souza", "", "42", ""}},
+		},
+		{
+			name: "primeira aba pelo workbook",
+			files: map[string]string{
+				"xl/workbook.xml":            `<workbook ` + ns + `><sheets><sheet name="Dados" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+				"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId7" Target="worksheets/dados.xml"/></Relationships>`,
+				"xl/worksheets/dados.xml":    `<worksheet ` + ns + `><sheetData><row r="1"><c r="B1" t="inlineStr"><is><t>certo</t></is></c></row></sheetData></worksheet>`,
+				"xl/worksheets/sheet1.xml":   `<worksheet ` + ns + `><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>errado</t></is></c></row></sheetData></worksheet>`,
+			},
+			want: [][]string{{"", "certo"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := writeZip(t, tt.files)
+			got, err := ReadRows(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatalf("ReadRows: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadRows = %q; esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRowsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"não é zip", []byte("nome;email\n")},
+		{"sem aba", writeZip(t, map[string]string{"xl/styles.xml": "<styleSheet/>"})},
+		{"XML inválido", writeZip(t, map[string]string{"xl/worksheets/sheet1.xml": "<worksheet><sheetData><row><c>"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadRows(bytes.NewReader(tt.data), int64(len(tt.data))); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("ReadRows: erro %v; esperado %v", err, ErrInvalidFile)
+			}
+		})
+	}
+}
+
+func TestColumnNameAndIndex(t *testing.T) {
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.name {
+			t.Errorf("columnName(%d) = %q; esperado %q", tt.index, got, tt.name)
+		}
+		if got := columnIndex(tt.name + "12"); got != tt.index {
+			t.Errorf("columnIndex(%q) = %d; esperado %d", tt.name+"12", got, tt.index)
+		}
+	}
+}